@@ -20,6 +20,7 @@ import (
 	"os"
 
 	"github.com/tombee/conductor/internal/cli"
+	authcmd "github.com/tombee/conductor/internal/commands/auth"
 	"github.com/tombee/conductor/internal/commands/completion"
 	"github.com/tombee/conductor/internal/commands/config"
 	"github.com/tombee/conductor/internal/commands/controller"
@@ -124,6 +125,9 @@ func main() {
 
 	// Configuration and security
 	rootCmd.AddCommand(config.NewConfigCommand())
+	rootCmd.AddCommand(authcmd.NewCommand())
+	rootCmd.AddCommand(authcmd.NewLoginCommand())
+	rootCmd.AddCommand(authcmd.NewLogoutCommand())
 	rootCmd.AddCommand(integrations.NewCommand())
 	rootCmd.AddCommand(workspacecmd.NewCommand())
 	rootCmd.AddCommand(secrets.NewCommand())
@@ -0,0 +1,128 @@
+// Package email provides a builtin action for sending email over SMTP.
+//
+// The email action sends messages with plain text and HTML bodies, given
+// inline or rendered from templates, and attachments read from workflow
+// paths ($out/, $temp/, ./). Connections use STARTTLS or implicit TLS, with
+// PLAIN authentication when credentials are set.
+package email
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tombee/conductor/pkg/security"
+)
+
+// Result represents the output of an email operation.
+type Result struct {
+	Response interface{}
+	Metadata map[string]interface{}
+}
+
+// How the connection to the SMTP server is secured.
+const (
+	// SecurityAuto uses STARTTLS when the server offers it (default).
+	SecurityAuto = ""
+
+	// SecuritySTARTTLS requires STARTTLS.
+	SecuritySTARTTLS = "starttls"
+
+	// SecurityTLS connects with implicit TLS, usually on port 465.
+	SecurityTLS = "tls"
+
+	// SecurityNone never encrypts the connection.
+	SecurityNone = "none"
+)
+
+// SMTPConfig identifies an SMTP server and the sender to use with it.
+type SMTPConfig struct {
+	// Host is the SMTP server host name.
+	Host string
+
+	// Port is the SMTP server port. Default: 465 with implicit TLS,
+	// otherwise 587
+	Port int
+
+	// Security is how the connection is secured: starttls, tls or none.
+	// Default: STARTTLS when the server offers it
+	Security string
+
+	// Username and Password authenticate with PLAIN auth when set. In the
+	// controller's configuration either can be an environment variable
+	// reference like ${SMTP_PASSWORD}; values given by a step are used as is
+	Username string
+	Password string
+
+	// From is the default sender address.
+	From string
+}
+
+// Config holds configuration for the email action.
+type Config struct {
+	// SMTP is the server used by steps that do not name their own.
+	SMTP SMTPConfig
+
+	// WorkflowDir is the base directory for ./ paths
+	WorkflowDir string
+
+	// OutputDir is the directory for $out/ paths
+	OutputDir string
+
+	// TempDir is the directory for $temp/ paths
+	TempDir string
+
+	// Timeout bounds connecting to and talking with the server (default 30s).
+	Timeout time.Duration
+
+	// MaxAttachmentSize is the maximum total size of a message's
+	// attachments in bytes (default 25MB).
+	MaxAttachmentSize int64
+
+	// DNSMonitor provides DNS query monitoring for exfiltration prevention
+	DNSMonitor *security.DNSQueryMonitor
+
+	// SecurityConfig restricts which SMTP hosts can be contacted
+	SecurityConfig *security.HTTPSecurityConfig
+}
+
+// EmailAction implements the action interface for sending email.
+type EmailAction struct {
+	config *Config
+}
+
+// New creates a new email action instance.
+func New(config *Config) (*EmailAction, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	// Apply defaults to unset fields
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.MaxAttachmentSize == 0 {
+		config.MaxAttachmentSize = 25 * 1024 * 1024 // 25MB
+	}
+
+	// Only the controller's own credentials may reference the environment
+	config.SMTP.Username = expandEnv(config.SMTP.Username)
+	config.SMTP.Password = expandEnv(config.SMTP.Password)
+
+	return &EmailAction{config: config}, nil
+}
+
+// Name returns the action identifier.
+func (c *EmailAction) Name() string {
+	return "email"
+}
+
+// Execute runs an email operation.
+func (c *EmailAction) Execute(ctx context.Context, operation string, inputs map[string]interface{}) (*Result, error) {
+	switch operation {
+	case "send":
+		return c.send(ctx, inputs)
+	default:
+		return nil, fmt.Errorf("unknown email operation: %s", operation)
+	}
+}
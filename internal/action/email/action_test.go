@@ -0,0 +1,421 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/emersion/go-message/mail"
+	"github.com/tombee/conductor/pkg/security"
+)
+
+// smtpServer is a minimal SMTP server that records the messages it
+// receives. It offers PLAIN auth but not STARTTLS.
+type smtpServer struct {
+	addr     string
+	password string // Required when set
+
+	mu       sync.Mutex
+	from     string
+	rcpts    []string
+	data     string
+	username string
+}
+
+func startSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	s := &smtpServer{addr: l.Addr().String()}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+		switch cmd {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			// AUTH PLAIN base64(\0user\0pass)
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			parts := strings.Split(string(decoded), "\x00")
+			if len(parts) == 3 && parts[2] == s.password {
+				s.username = parts[1]
+				reply("235 Authenticated")
+			} else {
+				reply("535 Authentication failed")
+			}
+		case "MAIL":
+			if s.password != "" && s.username == "" {
+				reply("530 Authentication required")
+				break
+			}
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			if strings.Contains(line, "blocked@") {
+				reply("550 No such user")
+				break
+			}
+			s.rcpts = append(s.rcpts, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					s.mu.Unlock()
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = data.String()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("250 OK")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *smtpServer) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.addr)
+	p, _ := strconv.Atoi(port)
+	return SMTPConfig{Host: host, Port: p, From: "Conductor <conductor@example.com>"}
+}
+
+// received parses the last message the server received.
+func (s *smtpServer) received(t *testing.T) *mail.Reader {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := mail.CreateReader(strings.NewReader(s.data))
+	if err != nil {
+		t.Fatalf("failed to parse received message: %v", err)
+	}
+	return r
+}
+
+func TestNew(t *testing.T) {
+	action, err := New(nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if action.config.Timeout == 0 || action.config.MaxAttachmentSize == 0 {
+		t.Errorf("defaults not applied: %+v", action.config)
+	}
+	if action.Name() != "email" {
+		t.Errorf("Name() = %q", action.Name())
+	}
+}
+
+func TestSend_Text(t *testing.T) {
+	server := startSMTPServer(t)
+	action, _ := New(&Config{SMTP: server.config()})
+
+	result, err := action.Execute(context.Background(), "send", map[string]interface{}{
+		"to":      []interface{}{"ops@example.com", "Team <team@example.com>"},
+		"bcc":     "audit@example.com",
+		"subject": "Deploy finished\r\nBcc: attacker@example.com",
+		"text":    "All green.",
+		"headers": map[string]interface{}{"X-Run-Id": "run-1"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	response := result.Response.(map[string]interface{})
+	if response["recipients"] != 3 || response["message_id"] == "" {
+		t.Errorf("response = %v", response)
+	}
+	if server.from != "MAIL FROM:<conductor@example.com>" {
+		t.Errorf("MAIL = %q", server.from)
+	}
+	if len(server.rcpts) != 3 {
+		t.Errorf("RCPT = %v", server.rcpts)
+	}
+
+	r := server.received(t)
+	if subject, _ := r.Header.Subject(); subject != "Deploy finished  Bcc: attacker@example.com" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if r.Header.Get("Bcc") != "" {
+		t.Error("Bcc recipients leaked into the headers")
+	}
+	if r.Header.Get("X-Run-Id") != "run-1" {
+		t.Errorf("X-Run-Id = %q", r.Header.Get("X-Run-Id"))
+	}
+	part, err := r.NextPart()
+	if err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+	body, _ := io.ReadAll(part.Body)
+	if strings.TrimSpace(string(body)) != "All green." {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSend_TemplatesAndAttachments(t *testing.T) {
+	server := startSMTPServer(t)
+	server.password = "secret"
+	workflowDir := t.TempDir()
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		filepath.Join(workflowDir, "report.txt.tmpl"):  "Hello {{ .name | upper }}",
+		filepath.Join(workflowDir, "report.html.tmpl"): "<p>Hello {{ .name }}</p>",
+		filepath.Join(tempDir, "report.csv"):           "sku,qty\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("TEST_SMTP_PASSWORD", "secret")
+	cfg := server.config()
+	cfg.Username = "conductor"
+	cfg.Password = "${TEST_SMTP_PASSWORD}"
+	action, _ := New(&Config{SMTP: cfg, WorkflowDir: workflowDir, TempDir: tempDir})
+
+	result, err := action.Execute(context.Background(), "send", map[string]interface{}{
+		"from":          "reports@example.com",
+		"to":            "ops@example.com",
+		"subject":       "Weekly report",
+		"text_template": "./report.txt.tmpl",
+		"html_template": "./report.html.tmpl",
+		"data":          map[string]interface{}{"name": "<ops>"},
+		"attachments": []interface{}{
+			"$temp/report.csv",
+			map[string]interface{}{"path": "./report.txt.tmpl", "filename": "template.txt"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata["attachments"] != 2 {
+		t.Errorf("metadata = %v", result.Metadata)
+	}
+	if server.username != "conductor" {
+		t.Errorf("authenticated as %q", server.username)
+	}
+
+	r := server.received(t)
+	var text, html string
+	var attachments []string
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		body, _ := io.ReadAll(part.Body)
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			if ct, _, _ := h.ContentType(); ct == "text/html" {
+				html = strings.TrimSpace(string(body))
+			} else {
+				text = strings.TrimSpace(string(body))
+			}
+		case *mail.AttachmentHeader:
+			name, _ := h.Filename()
+			ct, _, _ := h.ContentType()
+			attachments = append(attachments, name+" "+ct+" "+strings.TrimSpace(string(body)))
+		}
+	}
+
+	if text != "Hello <OPS>" {
+		t.Errorf("text = %q", text)
+	}
+	if html != "<p>Hello &lt;ops&gt;</p>" {
+		t.Errorf("html = %q", html)
+	}
+	want := []string{"report.csv text/csv sku,qty", "template.txt text/plain Hello {{ .name | upper }}"}
+	if strings.Join(attachments, "|") != strings.Join(want, "|") {
+		t.Errorf("attachments = %q, want %q", attachments, want)
+	}
+}
+
+func TestSend_Errors(t *testing.T) {
+	server := startSMTPServer(t)
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		config   Config
+		inputs   map[string]interface{}
+		wantType ErrorType
+	}{
+		{
+			name:     "no server",
+			inputs:   map[string]interface{}{"to": "a@example.com", "subject": "s", "text": "t"},
+			wantType: ErrorTypeConfiguration,
+		},
+		{
+			name:     "missing recipient",
+			config:   Config{SMTP: server.config()},
+			inputs:   map[string]interface{}{"subject": "s", "text": "t"},
+			wantType: ErrorTypeValidation,
+		},
+		{
+			name:     "invalid address",
+			config:   Config{SMTP: server.config()},
+			inputs:   map[string]interface{}{"to": "not an address", "subject": "s", "text": "t"},
+			wantType: ErrorTypeValidation,
+		},
+		{
+			name:     "missing body",
+			config:   Config{SMTP: server.config()},
+			inputs:   map[string]interface{}{"to": "a@example.com", "subject": "s"},
+			wantType: ErrorTypeValidation,
+		},
+		{
+			name:     "protected header",
+			config:   Config{SMTP: server.config()},
+			inputs:   map[string]interface{}{"to": "a@example.com", "subject": "s", "text": "t", "headers": map[string]interface{}{"Bcc": "x@example.com"}},
+			wantType: ErrorTypeValidation,
+		},
+		{
+			name:     "missing attachment",
+			config:   Config{SMTP: server.config(), TempDir: tempDir},
+			inputs:   map[string]interface{}{"to": "a@example.com", "subject": "s", "text": "t", "attachments": []interface{}{"$temp/missing.pdf"}},
+			wantType: ErrorTypeAttachment,
+		},
+		{
+			name:     "starttls required",
+			config:   Config{SMTP: SMTPConfig{Host: server.config().Host, Port: server.config().Port, Security: SecuritySTARTTLS, From: "a@example.com"}},
+			inputs:   map[string]interface{}{"to": "a@example.com", "subject": "s", "text": "t"},
+			wantType: ErrorTypeConfiguration,
+		},
+		{
+			name:     "host not allowed",
+			config:   Config{SMTP: server.config(), SecurityConfig: &security.HTTPSecurityConfig{AllowedHosts: []string{"smtp.example.com"}}},
+			inputs:   map[string]interface{}{"to": "a@example.com", "subject": "s", "text": "t"},
+			wantType: ErrorTypeSecurity,
+		},
+		{
+			name:     "recipient rejected",
+			config:   Config{SMTP: server.config()},
+			inputs:   map[string]interface{}{"to": "blocked@example.com", "subject": "s", "text": "t"},
+			wantType: ErrorTypeRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, _ := New(&tt.config)
+			_, err := action.Execute(context.Background(), "send", tt.inputs)
+			var opErr *OperationError
+			if !errors.As(err, &opErr) {
+				t.Fatalf("Execute() error = %v, want an OperationError", err)
+			}
+			if opErr.ErrorType != tt.wantType {
+				t.Errorf("ErrorType = %s, want %s (%v)", opErr.ErrorType, tt.wantType, err)
+			}
+		})
+	}
+}
+
+func TestSend_StepServerCredentials(t *testing.T) {
+	configured := startSMTPServer(t)
+	configured.password = "secret"
+	other := startSMTPServer(t)
+
+	t.Setenv("TEST_SMTP_PASSWORD", "secret")
+	cfg := configured.config()
+	cfg.Username = "conductor"
+	cfg.Password = "${TEST_SMTP_PASSWORD}"
+	action, _ := New(&Config{SMTP: cfg})
+	host, port, _ := net.SplitHostPort(other.addr)
+
+	// The configured credentials are not sent to a server named by the step
+	_, err := action.Execute(context.Background(), "send", map[string]interface{}{
+		"to": "a@example.com", "subject": "s", "text": "t",
+		"smtp": map[string]interface{}{"host": host, "port": port},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if other.username != "" {
+		t.Errorf("step server received credentials for %q", other.username)
+	}
+
+	// Step credentials are not expanded from the environment
+	other.password = "secret"
+	_, err = action.Execute(context.Background(), "send", map[string]interface{}{
+		"to": "a@example.com", "subject": "s", "text": "t",
+		"smtp": map[string]interface{}{"host": host, "port": port, "username": "step", "password": "${TEST_SMTP_PASSWORD}"},
+	})
+	if err == nil {
+		t.Error("Execute() succeeded with an environment reference as the step password")
+	}
+}
+
+func TestSend_ConnectionRefused(t *testing.T) {
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := l.Addr().(*net.TCPAddr)
+	l.Close()
+
+	action, _ := New(&Config{SMTP: SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "a@example.com"}})
+	_, err := action.Execute(context.Background(), "send", map[string]interface{}{
+		"to": "b@example.com", "subject": "s", "text": "t",
+	})
+	var opErr *OperationError
+	if !errors.As(err, &opErr) || !opErr.IsRetryable() {
+		t.Errorf("Execute() error = %v, want a retryable connection error", err)
+	}
+}
+
+func TestExecute_UnknownOperation(t *testing.T) {
+	action, _ := New(nil)
+	if _, err := action.Execute(context.Background(), "receive", nil); err == nil {
+		t.Error("Execute() succeeded for an unknown operation")
+	}
+}
@@ -0,0 +1,57 @@
+package email
+
+import "fmt"
+
+// ErrorType represents the type of email action error.
+type ErrorType string
+
+const (
+	// ErrorTypeValidation indicates invalid input parameters.
+	ErrorTypeValidation ErrorType = "validation"
+
+	// ErrorTypeConfiguration indicates a missing or invalid SMTP server.
+	ErrorTypeConfiguration ErrorType = "configuration"
+
+	// ErrorTypeTemplate indicates a body template could not be rendered.
+	ErrorTypeTemplate ErrorType = "template"
+
+	// ErrorTypeAttachment indicates an attachment could not be read.
+	ErrorTypeAttachment ErrorType = "attachment"
+
+	// ErrorTypeConnection indicates the SMTP server could not be reached.
+	ErrorTypeConnection ErrorType = "connection"
+
+	// ErrorTypeRejected indicates the SMTP server refused the message.
+	ErrorTypeRejected ErrorType = "rejected"
+
+	// ErrorTypeSecurity indicates the SMTP server is blocked by the
+	// security profile.
+	ErrorTypeSecurity ErrorType = "security"
+)
+
+// OperationError represents an error from an email action operation.
+type OperationError struct {
+	Operation  string
+	Message    string
+	ErrorType  ErrorType
+	Cause      error
+	Suggestion string
+}
+
+// Error implements the error interface.
+func (e *OperationError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Operation, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Operation, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *OperationError) Unwrap() error {
+	return e.Cause
+}
+
+// IsRetryable returns true if the error may succeed on retry.
+func (e *OperationError) IsRetryable() bool {
+	return e.ErrorType == ErrorTypeConnection
+}
@@ -0,0 +1,680 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/emersion/go-message/mail"
+
+	"github.com/tombee/conductor/internal/action/file"
+)
+
+// attachment is a file to attach to a message.
+type attachment struct {
+	filename    string
+	contentType string
+	data        []byte
+}
+
+// message is a validated email.send request.
+type message struct {
+	from        *netmail.Address
+	to          []*netmail.Address
+	cc          []*netmail.Address
+	bcc         []*netmail.Address
+	replyTo     []*netmail.Address
+	subject     string
+	text        string
+	html        string
+	headers     map[string]string
+	attachments []attachment
+}
+
+// send implements the email.send operation.
+func (c *EmailAction) send(ctx context.Context, inputs map[string]interface{}) (*Result, error) {
+	server, err := c.server(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.message(inputs, server.From)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, messageID, err := msg.encode()
+	if err != nil {
+		return nil, &OperationError{
+			Operation: "send",
+			Message:   "failed to encode message",
+			ErrorType: ErrorTypeValidation,
+			Cause:     err,
+		}
+	}
+
+	var recipients []string
+	for _, list := range [][]*netmail.Address{msg.to, msg.cc, msg.bcc} {
+		for _, addr := range list {
+			recipients = append(recipients, addr.Address)
+		}
+	}
+
+	start := time.Now()
+	if err := c.deliver(ctx, server, msg.from.Address, recipients, raw); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Response: map[string]interface{}{
+			"message_id": messageID,
+			"recipients": len(recipients),
+		},
+		Metadata: map[string]interface{}{
+			"host":        server.Host,
+			"bytes":       len(raw),
+			"attachments": len(msg.attachments),
+			"duration_ms": time.Since(start).Milliseconds(),
+		},
+	}, nil
+}
+
+// server returns the SMTP server to send with: the step's smtp input over
+// the configured server. The configured credentials are only sent to the
+// configured server, so a step that names another host or port must give
+// its own.
+func (c *EmailAction) server(inputs map[string]interface{}) (SMTPConfig, error) {
+	server := c.config.SMTP
+	if raw, ok := inputs["smtp"]; ok {
+		overrides, ok := raw.(map[string]interface{})
+		if !ok {
+			return server, validationError("parameter smtp must be an object")
+		}
+		_, host := overrides["host"]
+		_, port := overrides["port"]
+		if host || port {
+			server.Username = ""
+			server.Password = ""
+		}
+		for key, value := range overrides {
+			switch key {
+			case "host":
+				server.Host = fmt.Sprint(value)
+			case "port":
+				port, err := strconv.Atoi(fmt.Sprint(value))
+				if err != nil || port <= 0 || port > 65535 {
+					return server, validationError(fmt.Sprintf("invalid smtp port: %v", value))
+				}
+				server.Port = port
+			case "security":
+				server.Security = fmt.Sprint(value)
+			case "username":
+				server.Username = fmt.Sprint(value)
+			case "password":
+				server.Password = fmt.Sprint(value)
+			case "from":
+				server.From = fmt.Sprint(value)
+			default:
+				return server, validationError(fmt.Sprintf("unknown smtp setting: %s", key))
+			}
+		}
+	}
+
+	if server.Host == "" {
+		return server, &OperationError{
+			Operation:  "send",
+			Message:    "no SMTP server configured",
+			ErrorType:  ErrorTypeConfiguration,
+			Suggestion: "set controller.notifications.smtp, or pass smtp.host to the step",
+		}
+	}
+	switch server.Security {
+	case SecurityAuto, SecuritySTARTTLS, SecurityTLS, SecurityNone:
+	default:
+		return server, validationError(fmt.Sprintf("invalid smtp security: %s (use starttls, tls or none)", server.Security))
+	}
+
+	return server, nil
+}
+
+// checkHost applies the DNS monitor and the host allowlist to the SMTP
+// server before connecting.
+func (c *EmailAction) checkHost(host string) error {
+	if c.config.DNSMonitor != nil {
+		if err := c.config.DNSMonitor.ValidateQuery(host); err != nil {
+			return securityError(host, fmt.Errorf("DNS validation failed: %w", err))
+		}
+	}
+	if c.config.SecurityConfig != nil {
+		if err := c.config.SecurityConfig.ValidateHost(host); err != nil {
+			return securityError(host, err)
+		}
+	}
+	return nil
+}
+
+// message validates the inputs of a send and reads its bodies and
+// attachments.
+func (c *EmailAction) message(inputs map[string]interface{}, defaultFrom string) (*message, error) {
+	msg := &message{headers: make(map[string]string)}
+	var err error
+
+	from := defaultFrom
+	if v, ok := inputs["from"]; ok {
+		from = fmt.Sprint(v)
+	}
+	if from == "" {
+		return nil, validationError("missing sender: set from, or a default sender for the SMTP server")
+	}
+	if msg.from, err = netmail.ParseAddress(from); err != nil {
+		return nil, validationError(fmt.Sprintf("invalid from address %q: %v", from, err))
+	}
+
+	for key, list := range map[string]*[]*netmail.Address{"to": &msg.to, "cc": &msg.cc, "bcc": &msg.bcc, "reply_to": &msg.replyTo} {
+		if *list, err = addressList(inputs, key); err != nil {
+			return nil, err
+		}
+	}
+	if len(msg.to)+len(msg.cc)+len(msg.bcc) == 0 {
+		return nil, validationError("missing required parameter: to")
+	}
+
+	subject, ok := inputs["subject"].(string)
+	if !ok || subject == "" {
+		return nil, validationError("missing required parameter: subject")
+	}
+	msg.subject = headerSafe(subject)
+
+	if msg.text, err = c.body(inputs, "text", "text_template", false); err != nil {
+		return nil, err
+	}
+	if msg.html, err = c.body(inputs, "html", "html_template", true); err != nil {
+		return nil, err
+	}
+	if msg.text == "" && msg.html == "" {
+		return nil, validationError("missing body: set text, html, text_template or html_template")
+	}
+
+	if raw, ok := inputs["headers"]; ok {
+		headers, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, validationError("parameter headers must be an object")
+		}
+		for name, value := range headers {
+			if !validHeaderName(name) {
+				return nil, validationError(fmt.Sprintf("invalid header name: %q", name))
+			}
+			msg.headers[name] = headerSafe(fmt.Sprint(value))
+		}
+	}
+
+	if msg.attachments, err = c.readAttachments(inputs); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// body returns a message body, given inline or rendered from a template
+// file with the step's data input. HTML templates escape their data.
+func (c *EmailAction) body(inputs map[string]interface{}, key, templateKey string, html bool) (string, error) {
+	if v, ok := inputs[key]; ok {
+		if _, ok := inputs[templateKey]; ok {
+			return "", validationError(fmt.Sprintf("set %s or %s, not both", key, templateKey))
+		}
+		s, ok := v.(string)
+		if !ok {
+			return "", validationError(fmt.Sprintf("parameter %s must be a string", key))
+		}
+		return s, nil
+	}
+
+	v, ok := inputs[templateKey]
+	if !ok {
+		return "", nil
+	}
+	path, ok := v.(string)
+	if !ok {
+		return "", validationError(fmt.Sprintf("parameter %s must be a string", templateKey))
+	}
+	resolved, err := c.resolver().Resolve(path)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		return "", &OperationError{
+			Operation: "send",
+			Message:   fmt.Sprintf("failed to read template %s", path),
+			ErrorType: ErrorTypeTemplate,
+			Cause:     err,
+		}
+	}
+
+	var buf bytes.Buffer
+	if html {
+		var tmpl *htmltemplate.Template
+		tmpl, err = htmltemplate.New(filepath.Base(path)).Funcs(htmltemplate.FuncMap(file.TemplateFuncs())).Parse(string(content))
+		if err == nil {
+			err = tmpl.Execute(&buf, inputs["data"])
+		}
+	} else {
+		var tmpl *template.Template
+		tmpl, err = template.New(filepath.Base(path)).Funcs(file.TemplateFuncs()).Parse(string(content))
+		if err == nil {
+			err = tmpl.Execute(&buf, inputs["data"])
+		}
+	}
+	if err != nil {
+		return "", &OperationError{
+			Operation: "send",
+			Message:   fmt.Sprintf("failed to render template %s", path),
+			ErrorType: ErrorTypeTemplate,
+			Cause:     err,
+		}
+	}
+	return buf.String(), nil
+}
+
+// readAttachments reads the files of the attachments input. Each is a
+// path, or an object with a path and optional filename and content_type.
+func (c *EmailAction) readAttachments(inputs map[string]interface{}) ([]attachment, error) {
+	raw, ok := inputs["attachments"]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, validationError("parameter attachments must be a list")
+	}
+
+	var attachments []attachment
+	var total int64
+	for i, item := range list {
+		var path, filename, contentType string
+		switch v := item.(type) {
+		case string:
+			path = v
+		case map[string]interface{}:
+			path, _ = v["path"].(string)
+			filename, _ = v["filename"].(string)
+			contentType, _ = v["content_type"].(string)
+		}
+		if path == "" {
+			return nil, validationError(fmt.Sprintf("attachment %d must be a path, or an object with a path", i))
+		}
+
+		resolved, err := c.resolver().Resolve(path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(resolved)
+		if err == nil {
+			total += info.Size()
+			if total > c.config.MaxAttachmentSize {
+				return nil, &OperationError{
+					Operation: "send",
+					Message:   fmt.Sprintf("attachments exceed the maximum size of %d bytes", c.config.MaxAttachmentSize),
+					ErrorType: ErrorTypeAttachment,
+				}
+			}
+		}
+		data, err := os.ReadFile(resolved)
+		if err != nil {
+			return nil, &OperationError{
+				Operation: "send",
+				Message:   fmt.Sprintf("failed to read attachment %s", path),
+				ErrorType: ErrorTypeAttachment,
+				Cause:     err,
+			}
+		}
+
+		if filename == "" {
+			filename = filepath.Base(resolved)
+		}
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(filename))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		attachments = append(attachments, attachment{
+			filename:    headerSafe(filename),
+			contentType: contentType,
+			data:        data,
+		})
+	}
+	return attachments, nil
+}
+
+func (c *EmailAction) resolver() *file.PathResolver {
+	return file.NewPathResolver(&file.PathResolverConfig{
+		WorkflowDir: c.config.WorkflowDir,
+		OutputDir:   c.config.OutputDir,
+		TempDir:     c.config.TempDir,
+	})
+}
+
+// encode returns the message in RFC 5322 form and its Message-ID. Bcc
+// recipients are left out of the headers.
+func (m *message) encode() ([]byte, string, error) {
+	var h mail.Header
+	h.SetDate(time.Now())
+	h.SetAddressList("From", []*mail.Address{(*mail.Address)(m.from)})
+	h.SetAddressList("To", mailAddresses(m.to))
+	if len(m.cc) > 0 {
+		h.SetAddressList("Cc", mailAddresses(m.cc))
+	}
+	if len(m.replyTo) > 0 {
+		h.SetAddressList("Reply-To", mailAddresses(m.replyTo))
+	}
+	h.SetSubject(m.subject)
+	for name, value := range m.headers {
+		h.SetText(name, value)
+	}
+	if err := h.GenerateMessageID(); err != nil {
+		return nil, "", err
+	}
+	messageID, _ := h.MessageID()
+
+	var buf bytes.Buffer
+	if len(m.attachments) == 0 && (m.text == "" || m.html == "") {
+		if m.html != "" {
+			h.SetContentType("text/html", map[string]string{"charset": "utf-8"})
+		} else {
+			h.SetContentType("text/plain", map[string]string{"charset": "utf-8"})
+		}
+		w, err := mail.CreateSingleInlineWriter(&buf, h)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.WriteString(w, m.text+m.html); err != nil {
+			return nil, "", err
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), messageID, nil
+	}
+
+	w, err := mail.CreateWriter(&buf, h)
+	if err != nil {
+		return nil, "", err
+	}
+	inline, err := w.CreateInline()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, part := range []struct{ contentType, body string }{{"text/plain", m.text}, {"text/html", m.html}} {
+		if part.body == "" {
+			continue
+		}
+		var ph mail.InlineHeader
+		ph.SetContentType(part.contentType, map[string]string{"charset": "utf-8"})
+		pw, err := inline.CreatePart(ph)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.WriteString(pw, part.body); err != nil {
+			return nil, "", err
+		}
+		if err := pw.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := inline.Close(); err != nil {
+		return nil, "", err
+	}
+
+	for _, a := range m.attachments {
+		mediaType, params, err := mime.ParseMediaType(a.contentType)
+		if err != nil {
+			mediaType, params = "application/octet-stream", nil
+		}
+		var ah mail.AttachmentHeader
+		ah.SetContentType(mediaType, params)
+		ah.SetFilename(a.filename)
+		aw, err := w.CreateAttachment(ah)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := aw.Write(a.data); err != nil {
+			return nil, "", err
+		}
+		if err := aw.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), messageID, nil
+}
+
+// deliver sends a message through the SMTP server.
+func (c *EmailAction) deliver(ctx context.Context, server SMTPConfig, from string, recipients []string, raw []byte) error {
+	port := server.Port
+	if port == 0 {
+		port = 587
+		if server.Security == SecurityTLS {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(server.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: server.Host}
+
+	if err := c.checkHost(server.Host); err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: c.config.Timeout}
+	dial := dialer.DialContext
+	if c.config.SecurityConfig != nil {
+		// Re-validates the resolved address to prevent DNS rebinding
+		dial = c.config.SecurityConfig.SecureDialContext(nil)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	conn, err := dial(dialCtx, "tcp", addr)
+	if err == nil && server.Security == SecurityTLS {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err = tlsConn.HandshakeContext(dialCtx); err != nil {
+			conn.Close()
+		}
+		conn = tlsConn
+	}
+	if err != nil {
+		return connectionError(fmt.Sprintf("failed to connect to %s", addr), err)
+	}
+
+	// net/smtp has no context support, so the deadline bounds the session
+	deadline := time.Now().Add(c.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return connectionError("failed to set deadline", err)
+	}
+
+	client, err := smtp.NewClient(conn, server.Host)
+	if err != nil {
+		conn.Close()
+		return connectionError(fmt.Sprintf("failed to start SMTP session with %s", addr), err)
+	}
+	defer client.Close()
+
+	if server.Security == SecurityAuto || server.Security == SecuritySTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return connectionError("failed to start TLS", err)
+			}
+		} else if server.Security == SecuritySTARTTLS {
+			return &OperationError{
+				Operation:  "send",
+				Message:    fmt.Sprintf("%s does not offer STARTTLS", addr),
+				ErrorType:  ErrorTypeConfiguration,
+				Suggestion: "use security: tls for implicit TLS, or none for servers without encryption",
+			}
+		}
+	}
+
+	if server.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", server.Username, server.Password, server.Host)); err != nil {
+			return &OperationError{
+				Operation: "send",
+				Message:   "authentication failed",
+				ErrorType: ErrorTypeConfiguration,
+				Cause:     err,
+			}
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return rejectedError(fmt.Sprintf("sender %s rejected", from), err)
+	}
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return rejectedError(fmt.Sprintf("recipient %s rejected", rcpt), err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return rejectedError("message rejected", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return connectionError("failed to send message", err)
+	}
+	if err := w.Close(); err != nil {
+		return rejectedError("message rejected", err)
+	}
+
+	// The message is accepted once its data is; a failed QUIT changes nothing
+	client.Quit()
+	return nil
+}
+
+// addressList parses an address input: a string of comma-separated
+// addresses, or a list of them.
+func addressList(inputs map[string]interface{}, key string) ([]*netmail.Address, error) {
+	raw, ok := inputs[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	var values []string
+	switch v := raw.(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, validationError(fmt.Sprintf("parameter %s must be a list of addresses", key))
+			}
+			values = append(values, s)
+		}
+	default:
+		return nil, validationError(fmt.Sprintf("parameter %s must be an address or a list of addresses", key))
+	}
+
+	var addrs []*netmail.Address
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		list, err := netmail.ParseAddressList(value)
+		if err != nil {
+			return nil, validationError(fmt.Sprintf("invalid %s address %q: %v", key, value, err))
+		}
+		addrs = append(addrs, list...)
+	}
+	return addrs, nil
+}
+
+func mailAddresses(addrs []*netmail.Address) []*mail.Address {
+	result := make([]*mail.Address, len(addrs))
+	for i, a := range addrs {
+		result[i] = (*mail.Address)(a)
+	}
+	return result
+}
+
+// headerSafe strips line breaks so values cannot add mail headers.
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// validHeaderName reports whether name is a header field name that does not
+// replace one the action sets.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r > '~' || r == ':' {
+			return false
+		}
+	}
+	switch strings.ToLower(name) {
+	case "from", "to", "cc", "bcc", "reply-to", "subject", "date", "message-id",
+		"mime-version", "content-type", "content-transfer-encoding":
+		return false
+	}
+	return true
+}
+
+func expandEnv(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return os.ExpandEnv(s)
+}
+
+func validationError(message string) error {
+	return &OperationError{
+		Operation: "send",
+		Message:   message,
+		ErrorType: ErrorTypeValidation,
+	}
+}
+
+func connectionError(message string, cause error) error {
+	return &OperationError{
+		Operation: "send",
+		Message:   message,
+		ErrorType: ErrorTypeConnection,
+		Cause:     cause,
+	}
+}
+
+func securityError(host string, cause error) error {
+	return &OperationError{
+		Operation:  "send",
+		Message:    fmt.Sprintf("SMTP server %s is blocked by the security profile", host),
+		ErrorType:  ErrorTypeSecurity,
+		Cause:      cause,
+		Suggestion: "add the host to the security profile's allowed hosts",
+	}
+}
+
+func rejectedError(message string, cause error) error {
+	return &OperationError{
+		Operation: "send",
+		Message:   message,
+		ErrorType: ErrorTypeRejected,
+		Cause:     cause,
+	}
+}
@@ -261,6 +261,12 @@ func renderTemplate(tmplStr string, data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+// TemplateFuncs returns the functions available to templates rendered by
+// file.render, for other actions that render templates the same way.
+func TemplateFuncs() template.FuncMap {
+	return restrictedFuncMap()
+}
+
 // restrictedFuncMap returns a whitelist of safe template functions.
 func restrictedFuncMap() template.FuncMap {
 	return template.FuncMap{
@@ -134,10 +134,49 @@ type ResolvedMCPServerBinding struct {
 	// Timeout is the default timeout for tool calls in seconds
 	Timeout int
 
+	// URL is the endpoint of a remote server
+	URL string
+
+	// Headers are HTTP headers for a remote server (secrets resolved to values)
+	Headers map[string]string
+
+	// Transport selects how to connect: "stdio", "http" or "sse"
+	Transport string
+
+	// Auth contains authentication for a remote server (secrets resolved to values)
+	Auth *ResolvedMCPAuthBinding
+
 	// Source indicates where this binding came from (for debugging)
 	Source BindingSource
 }
 
+// ResolvedMCPAuthBinding contains resolved authentication for a remote MCP server.
+type ResolvedMCPAuthBinding struct {
+	// Type is the auth scheme: "bearer" or "oauth2"
+	Type string
+
+	// Token is the bearer token (resolved from secret reference)
+	Token string
+
+	// Flow is the OAuth2 flow
+	Flow string
+
+	// ClientID is the OAuth2 client ID
+	ClientID string
+
+	// ClientSecret is the OAuth2 client secret (resolved from secret reference)
+	ClientSecret string
+
+	// TokenURL is the OAuth2 token endpoint
+	TokenURL string
+
+	// RefreshToken is the OAuth2 refresh token (resolved from secret reference)
+	RefreshToken string
+
+	// Scopes are the OAuth2 scopes to request
+	Scopes []string
+}
+
 // BindingSource indicates where a binding value originated.
 type BindingSource string
 
@@ -382,10 +421,14 @@ func (r *Resolver) resolveMCPServerBinding(ctx context.Context, resCtx *Resoluti
 				}
 
 				binding := &profile.MCPServerBinding{
-					Command: mcpCfg.Command,
-					Args:    mcpCfg.Args,
-					Env:     envMap,
-					Timeout: mcpCfg.Timeout,
+					Command:   mcpCfg.Command,
+					Args:      mcpCfg.Args,
+					Env:       envMap,
+					Timeout:   mcpCfg.Timeout,
+					URL:       mcpCfg.URL,
+					Headers:   mcpCfg.Headers,
+					Transport: mcpCfg.Transport,
+					Auth:      mcpAuthBinding(mcpCfg.Auth),
 				}
 				return binding, SourceInline, nil
 			}
@@ -404,10 +447,12 @@ func (r *Resolver) resolveMCPServerBinding(ctx context.Context, resCtx *Resoluti
 // resolveMCPServerSecrets resolves all secret references in an MCP server binding.
 func (r *Resolver) resolveMCPServerSecrets(ctx context.Context, resCtx *ResolutionContext, binding *profile.MCPServerBinding) (*ResolvedMCPServerBinding, error) {
 	resolved := &ResolvedMCPServerBinding{
-		Command: binding.Command,
-		Args:    binding.Args,
-		Env:     make(map[string]string),
-		Timeout: binding.Timeout,
+		Command:   binding.Command,
+		Args:      binding.Args,
+		Env:       make(map[string]string),
+		Timeout:   binding.Timeout,
+		URL:       binding.URL,
+		Transport: binding.Transport,
 	}
 
 	// Resolve environment variable secrets
@@ -420,9 +465,62 @@ func (r *Resolver) resolveMCPServerSecrets(ctx context.Context, resCtx *Resoluti
 		resolved.Env[key] = resolvedValue
 	}
 
+	// Resolve header secrets for remote servers
+	if len(binding.Headers) > 0 {
+		resolved.Headers = make(map[string]string, len(binding.Headers))
+		for key, value := range binding.Headers {
+			resolvedValue, err := r.resolveSecret(ctx, value)
+			if err != nil {
+				resolvedValue = value
+			}
+			resolved.Headers[key] = resolvedValue
+		}
+	}
+
+	// Resolve auth secrets for remote servers
+	if binding.Auth != nil {
+		resolved.Auth = &ResolvedMCPAuthBinding{
+			Type:         binding.Auth.Type,
+			Token:        r.resolveSecretOrLiteral(ctx, binding.Auth.Token),
+			Flow:         binding.Auth.Flow,
+			ClientID:     binding.Auth.ClientID,
+			ClientSecret: r.resolveSecretOrLiteral(ctx, binding.Auth.ClientSecret),
+			TokenURL:     binding.Auth.TokenURL,
+			RefreshToken: r.resolveSecretOrLiteral(ctx, binding.Auth.RefreshToken),
+			Scopes:       binding.Auth.Scopes,
+		}
+	}
+
 	return resolved, nil
 }
 
+// resolveSecretOrLiteral resolves a secret reference, falling back to the
+// literal value when it is not one.
+func (r *Resolver) resolveSecretOrLiteral(ctx context.Context, value string) string {
+	resolvedValue, err := r.resolveSecret(ctx, value)
+	if err != nil {
+		return value
+	}
+	return resolvedValue
+}
+
+// mcpAuthBinding converts inline MCP server auth into its profile binding form.
+func mcpAuthBinding(auth *workflow.MCPAuthConfig) *profile.MCPAuthBinding {
+	if auth == nil {
+		return nil
+	}
+	return &profile.MCPAuthBinding{
+		Type:         auth.Type,
+		Token:        auth.Token,
+		Flow:         auth.Flow,
+		ClientID:     auth.ClientID,
+		ClientSecret: auth.ClientSecret,
+		TokenURL:     auth.TokenURL,
+		RefreshToken: auth.RefreshToken,
+		Scopes:       auth.Scopes,
+	}
+}
+
 // resolveInlineBindings handles self-contained workflows without requires section.
 func (r *Resolver) resolveInlineBindings(ctx context.Context, resCtx *ResolutionContext, resolved *ResolvedBinding) (*ResolvedBinding, error) {
 	// Process inline integrations
@@ -463,10 +561,14 @@ func (r *Resolver) resolveInlineBindings(ctx context.Context, resCtx *Resolution
 		}
 
 		binding := &profile.MCPServerBinding{
-			Command: mcpCfg.Command,
-			Args:    mcpCfg.Args,
-			Env:     envMap,
-			Timeout: mcpCfg.Timeout,
+			Command:   mcpCfg.Command,
+			Args:      mcpCfg.Args,
+			Env:       envMap,
+			Timeout:   mcpCfg.Timeout,
+			URL:       mcpCfg.URL,
+			Headers:   mcpCfg.Headers,
+			Transport: mcpCfg.Transport,
+			Auth:      mcpAuthBinding(mcpCfg.Auth),
 		}
 
 		resolvedBinding, err := r.resolveMCPServerSecrets(ctx, resCtx, binding)
@@ -333,6 +333,111 @@ func TestResolver_ResolveMCPServerRequirements(t *testing.T) {
 	}
 }
 
+func TestResolver_MCPServerTransportAndAuth(t *testing.T) {
+	registry := secrets.NewRegistry()
+	envProvider := secrets.NewTestEnvProvider(map[string]string{
+		"MCP_CLIENT_SECRET": "client-secret-value",
+		"MCP_TOKEN":         "token-value",
+	})
+	if err := registry.Register(envProvider); err != nil {
+		t.Fatalf("failed to register env provider: %v", err)
+	}
+	resolver := NewResolver(registry, profile.InheritEnvConfig{Enabled: true})
+
+	t.Run("inline server", func(t *testing.T) {
+		resCtx := &ResolutionContext{
+			Profile: &profile.Profile{Name: "test"},
+			Workflow: &workflow.Definition{
+				Name: "test-workflow",
+				MCPServers: []workflow.MCPServerConfig{
+					{
+						Name:      "remote",
+						Transport: "sse",
+						URL:       "https://mcp.example.com/sse",
+						Auth: &workflow.MCPAuthConfig{
+							Type:         "oauth2",
+							ClientID:     "conductor",
+							ClientSecret: "env:MCP_CLIENT_SECRET",
+							TokenURL:     "https://auth.example.com/token",
+							Scopes:       []string{"tools"},
+						},
+					},
+				},
+			},
+			RunID:     "test-run",
+			Workspace: "default",
+		}
+
+		resolved, err := resolver.Resolve(context.Background(), resCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		binding, exists := resolved.MCPServerBindings["remote"]
+		if !exists {
+			t.Fatal("expected remote MCP server binding but got none")
+		}
+		if binding.Transport != "sse" {
+			t.Errorf("expected transport %q but got %q", "sse", binding.Transport)
+		}
+		if binding.Auth == nil {
+			t.Fatal("expected auth to be resolved but got nil")
+		}
+		if binding.Auth.Type != "oauth2" || binding.Auth.ClientID != "conductor" || binding.Auth.TokenURL != "https://auth.example.com/token" {
+			t.Errorf("unexpected auth settings: %+v", binding.Auth)
+		}
+		if binding.Auth.ClientSecret != "client-secret-value" {
+			t.Errorf("expected resolved client secret but got %q", binding.Auth.ClientSecret)
+		}
+		if len(binding.Auth.Scopes) != 1 || binding.Auth.Scopes[0] != "tools" {
+			t.Errorf("expected scopes [tools] but got %v", binding.Auth.Scopes)
+		}
+	})
+
+	t.Run("profile binding", func(t *testing.T) {
+		resCtx := &ResolutionContext{
+			Profile: &profile.Profile{
+				Name: "test",
+				Bindings: profile.Bindings{
+					MCPServers: map[string]profile.MCPServerBinding{
+						"remote": {
+							Transport: "http",
+							URL:       "https://mcp.example.com/mcp",
+							Auth: &profile.MCPAuthBinding{
+								Type:  "bearer",
+								Token: "env:MCP_TOKEN",
+							},
+						},
+					},
+				},
+			},
+			Workflow: &workflow.Definition{
+				Name: "test-workflow",
+				Requires: &workflow.RequirementsDefinition{
+					MCPServers: []workflow.MCPServerRequirement{
+						{Name: "remote"},
+					},
+				},
+			},
+			RunID:     "test-run",
+			Workspace: "default",
+		}
+
+		resolved, err := resolver.Resolve(context.Background(), resCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		binding := resolved.MCPServerBindings["remote"]
+		if binding.Transport != "http" {
+			t.Errorf("expected transport %q but got %q", "http", binding.Transport)
+		}
+		if binding.Auth == nil || binding.Auth.Type != "bearer" || binding.Auth.Token != "token-value" {
+			t.Errorf("expected resolved bearer auth but got %+v", binding.Auth)
+		}
+	})
+}
+
 func TestResolver_BackwardCompatibility(t *testing.T) {
 	// Workflow with no requires section should use inline definitions
 	workflow := &workflow.Definition{
@@ -203,10 +203,21 @@ func (c *Client) Post(ctx context.Context, path string, body any) (map[string]an
 
 // PostYAML performs a POST request with YAML body.
 func (c *Client) PostYAML(ctx context.Context, path string, yamlData []byte) (map[string]any, error) {
+	return c.PostYAMLWithHeaders(ctx, path, yamlData, nil)
+}
+
+// PostYAMLWithHeaders performs a POST request with YAML body and extra
+// request headers, such as Idempotency-Key.
+func (c *Client) PostYAMLWithHeaders(ctx context.Context, path string, yamlData []byte, header http.Header) (map[string]any, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(yamlData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
 	req.Header.Set("Content-Type", "application/x-yaml")
 	c.addAuth(req)
 
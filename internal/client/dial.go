@@ -15,6 +15,7 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"os"
@@ -85,9 +86,11 @@ func FromEnvironment() (*Client, error) {
 
 	opts := []Option{WithTransport(transport)}
 
-	// Add API key if configured
+	// Add API key if configured, or else the token from `conductor login`
 	if apiKey := os.Getenv(ConductorAPIKeyEnv); apiKey != "" {
 		opts = append(opts, WithAPIKey(apiKey))
+	} else if token := loginBearerToken(context.Background()); token != "" {
+		opts = append(opts, WithAPIKey(token))
 	}
 
 	return New(opts...)
@@ -0,0 +1,134 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/tombee/conductor/internal/secrets"
+)
+
+// loginTokenKey is the keychain entry holding the token from `conductor login`.
+const loginTokenKey = "controller-login-token"
+
+// tokenRefreshMargin refreshes tokens this long before they expire.
+const tokenRefreshMargin = 30 * time.Second
+
+// LoginToken is an OIDC token obtained by `conductor login`.
+type LoginToken struct {
+	Issuer       string    `json:"issuer"`
+	ClientID     string    `json:"client_id"`
+	TokenURL     string    `json:"token_url"`
+	AccessToken  string    `json:"access_token"`
+	IDToken      string    `json:"id_token,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// BearerToken returns the token sent to the controller: the ID token, whose
+// audience is the Conductor client, or the access token if there is none.
+func (t *LoginToken) BearerToken() string {
+	if t.IDToken != "" {
+		return t.IDToken
+	}
+	return t.AccessToken
+}
+
+// SaveLoginToken stores a login token in the system keychain.
+func SaveLoginToken(ctx context.Context, token *LoginToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode login token: %w", err)
+	}
+	return secrets.NewKeychainBackend().Set(ctx, loginTokenKey, string(data))
+}
+
+// LoadLoginToken reads the stored login token.
+func LoadLoginToken(ctx context.Context) (*LoginToken, error) {
+	data, err := secrets.NewKeychainBackend().Get(ctx, loginTokenKey)
+	if err != nil {
+		return nil, err
+	}
+	var token LoginToken
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, fmt.Errorf("failed to decode login token: %w", err)
+	}
+	return &token, nil
+}
+
+// DeleteLoginToken removes the stored login token.
+func DeleteLoginToken(ctx context.Context) error {
+	return secrets.NewKeychainBackend().Delete(ctx, loginTokenKey)
+}
+
+// loginBearerToken returns the stored login token for requests, refreshing
+// and storing it again if it is about to expire. It returns "" if there is
+// no usable token.
+func loginBearerToken(ctx context.Context) string {
+	token, err := LoadLoginToken(ctx)
+	if err != nil {
+		return ""
+	}
+	if token.Expiry.IsZero() || time.Until(token.Expiry) > tokenRefreshMargin {
+		return token.BearerToken()
+	}
+	if token.RefreshToken == "" || token.TokenURL == "" {
+		return ""
+	}
+
+	if err := RefreshLoginToken(ctx, token); err != nil {
+		return ""
+	}
+	if err := SaveLoginToken(ctx, token); err != nil {
+		// The refreshed token still works for this command
+		return token.BearerToken()
+	}
+	return token.BearerToken()
+}
+
+// RefreshLoginToken exchanges a login token's refresh token for new tokens.
+func RefreshLoginToken(ctx context.Context, token *LoginToken) error {
+	cfg := oauth2.Config{
+		ClientID: token.ClientID,
+		Endpoint: oauth2.Endpoint{TokenURL: token.TokenURL, AuthStyle: oauth2.AuthStyleInParams},
+	}
+	refreshed, err := cfg.TokenSource(ctx, &oauth2.Token{
+		RefreshToken: token.RefreshToken,
+		Expiry:       time.Now().Add(-time.Minute),
+	}).Token()
+	if err != nil {
+		return fmt.Errorf("failed to refresh login token: %w", err)
+	}
+	token.UpdateFrom(refreshed)
+	return nil
+}
+
+// UpdateFrom copies the tokens from an OAuth2 token response. Providers may
+// omit the refresh or ID token on refresh, so missing ones are kept.
+func (t *LoginToken) UpdateFrom(tok *oauth2.Token) {
+	t.AccessToken = tok.AccessToken
+	t.Expiry = tok.Expiry
+	if tok.RefreshToken != "" {
+		t.RefreshToken = tok.RefreshToken
+	}
+	if idToken, ok := tok.Extra("id_token").(string); ok && idToken != "" {
+		t.IDToken = idToken
+	}
+}
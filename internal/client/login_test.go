@@ -0,0 +1,97 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestLoginBearerToken(t *testing.T) {
+	keyring.MockInit()
+	ctx := context.Background()
+
+	if got := loginBearerToken(ctx); got != "" {
+		t.Errorf("loginBearerToken() without a token = %q, want empty", got)
+	}
+
+	if err := SaveLoginToken(ctx, &LoginToken{
+		AccessToken: "access",
+		IDToken:     "id-token",
+		Expiry:      time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("SaveLoginToken() error = %v", err)
+	}
+	if got := loginBearerToken(ctx); got != "id-token" {
+		t.Errorf("loginBearerToken() = %q, want id-token", got)
+	}
+
+	if err := DeleteLoginToken(ctx); err != nil {
+		t.Fatalf("DeleteLoginToken() error = %v", err)
+	}
+	if got := loginBearerToken(ctx); got != "" {
+		t.Errorf("loginBearerToken() after delete = %q, want empty", got)
+	}
+}
+
+func TestLoginBearerToken_Refresh(t *testing.T) {
+	keyring.MockInit()
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-2",
+			"id_token":     "id-token-2",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer server.Close()
+
+	if err := SaveLoginToken(ctx, &LoginToken{
+		ClientID:     "conductor",
+		TokenURL:     server.URL,
+		AccessToken:  "access-1",
+		IDToken:      "id-token-1",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Minute),
+	}); err != nil {
+		t.Fatalf("SaveLoginToken() error = %v", err)
+	}
+
+	if got := loginBearerToken(ctx); got != "id-token-2" {
+		t.Fatalf("loginBearerToken() = %q, want id-token-2", got)
+	}
+
+	stored, err := LoadLoginToken(ctx)
+	if err != nil {
+		t.Fatalf("LoadLoginToken() error = %v", err)
+	}
+	if stored.AccessToken != "access-2" || stored.RefreshToken != "refresh-1" || time.Until(stored.Expiry) < time.Minute {
+		t.Errorf("stored token = %+v", stored)
+	}
+}
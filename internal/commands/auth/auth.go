@@ -0,0 +1,210 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides commands for managing controller access control.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/client"
+	"github.com/tombee/conductor/internal/commands/shared"
+)
+
+// NewCommand creates the auth command group.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "auth",
+		Annotations: map[string]string{
+			"group": "configuration",
+		},
+		Short: "Manage controller roles and permissions",
+		Long: `Commands for managing role-based access control on the controller.
+
+Roles grant actions (read, read-outputs, run, cancel, approve, manage-triggers,
+manage-mcp, admin) on workflows and workspaces. The viewer, operator and admin
+roles are built in; custom roles and role bindings are stored in the
+controller's backend. Managing roles requires the admin action.`,
+	}
+
+	cmd.AddCommand(newWhoamiCommand())
+	cmd.AddCommand(newRolesCommand())
+	cmd.AddCommand(newBindCommand())
+	cmd.AddCommand(newUnbindCommand())
+	cmd.AddCommand(newBindingsCommand())
+
+	return cmd
+}
+
+func newWhoamiCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "whoami",
+		Short: "Show your identity and roles on the controller",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiGet("/v1/auth/whoami")
+			if err != nil {
+				return err
+			}
+			if shared.GetJSON() {
+				return json.NewEncoder(os.Stdout).Encode(resp)
+			}
+
+			user, _ := resp["user"].(string)
+			if user == "" {
+				user = "(unauthenticated)"
+			}
+			fmt.Printf("%s %s\n", shared.Muted.Render("User:"), shared.Bold.Render(user))
+			if unrestricted, _ := resp["unrestricted"].(bool); unrestricted {
+				fmt.Printf("%s %s\n", shared.Muted.Render("Access:"), "unrestricted (RBAC not enforced)")
+				return nil
+			}
+			fmt.Printf("%s %s\n", shared.Muted.Render("Roles:"), joinStrings(resp["roles"]))
+			fmt.Printf("%s %s\n", shared.Muted.Render("Actions:"), joinStrings(resp["actions"]))
+			return nil
+		},
+	}
+}
+
+func newBindCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bind <subject> <role>...",
+		Short: "Bind roles to a user or API key",
+		Long: `Replace the roles bound to a subject.
+
+The subject is a user ID, or an API key name such as "key-1" for the first
+key in the controller's api_keys.`,
+		Example: `  # Let alice run and cancel workflows
+  conductor auth bind alice operator
+
+  # Give the first API key a custom role
+  conductor auth bind key-1 deployer`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiPost("/v1/auth/bindings", map[string]any{
+				"subject": args[0],
+				"roles":   args[1:],
+			})
+			if err != nil {
+				return err
+			}
+			if shared.GetJSON() {
+				return json.NewEncoder(os.Stdout).Encode(resp)
+			}
+			fmt.Printf("%s Bound %s to %s\n", shared.StatusOK.Render(shared.SymbolOK),
+				args[0], joinStrings(resp["roles"]))
+			return nil
+		},
+	}
+}
+
+func newUnbindCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unbind <subject>",
+		Short: "Remove the stored roles of a user or API key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiDelete("/v1/auth/bindings/" + url.PathEscape(args[0])); err != nil {
+				return err
+			}
+			fmt.Printf("%s Removed role binding for %s\n", shared.StatusOK.Render(shared.SymbolOK), args[0])
+			return nil
+		},
+	}
+}
+
+func newBindingsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bindings",
+		Short: "List stored role bindings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiGet("/v1/auth/bindings")
+			if err != nil {
+				return err
+			}
+			if shared.GetJSON() {
+				return json.NewEncoder(os.Stdout).Encode(resp)
+			}
+
+			bindings, _ := resp["bindings"].([]any)
+			if len(bindings) == 0 {
+				fmt.Println(shared.Muted.Render("No role bindings found"))
+				return nil
+			}
+			fmt.Printf("%s %s\n",
+				shared.Bold.Render(fmt.Sprintf("%-24s", "SUBJECT")),
+				shared.Bold.Render("ROLES"))
+			for _, b := range bindings {
+				binding, _ := b.(map[string]any)
+				subject, _ := binding["subject"].(string)
+				fmt.Printf("%-24s %s\n", subject, joinStrings(binding["roles"]))
+			}
+			return nil
+		},
+	}
+}
+
+func apiGet(path string) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return c.Get(ctx, path)
+}
+
+func apiPost(path string, body any) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return c.Post(ctx, path, body)
+}
+
+func apiDelete(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	return c.Delete(ctx, path)
+}
+
+// joinStrings joins a decoded JSON string array for display.
+func joinStrings(v any) string {
+	items, _ := v.([]any)
+	if len(items) == 0 {
+		return shared.Muted.Render("(none)")
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, ", ")
+}
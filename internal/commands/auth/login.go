@@ -0,0 +1,214 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+
+	"github.com/tombee/conductor/internal/client"
+	"github.com/tombee/conductor/internal/commands/shared"
+	controllerauth "github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/secrets"
+)
+
+// defaultLoginScopes are requested when neither the controller nor --scope
+// names any.
+var defaultLoginScopes = []string{"openid", "profile", "email", "offline_access"}
+
+// loginTimeout bounds how long `conductor login` waits for the user to
+// approve the device code.
+const loginTimeout = 15 * time.Minute
+
+// NewLoginCommand creates the login command.
+func NewLoginCommand() *cobra.Command {
+	var (
+		issuer   string
+		clientID string
+		scopes   []string
+	)
+
+	cmd := &cobra.Command{
+		Use: "login",
+		Annotations: map[string]string{
+			"group": "configuration",
+		},
+		Short: "Log in to the controller with your identity provider",
+		Long: `Log in to the controller with OpenID Connect using the device authorization flow.
+
+The issuer and client ID are read from the controller unless given as flags.
+Open the URL shown, enter the code, and approve the login. The token is
+stored in the system keychain, refreshed when it expires, and sent with
+every request to the controller. CONDUCTOR_API_KEY takes precedence over it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), loginTimeout)
+			defer cancel()
+
+			if issuer == "" || clientID == "" {
+				info, err := controllerOIDCInfo(ctx)
+				if err != nil {
+					return err
+				}
+				if issuer == "" {
+					issuer = info.issuer
+				}
+				if clientID == "" {
+					clientID = info.clientID
+				}
+				if len(scopes) == 0 {
+					scopes = info.scopes
+				}
+			}
+			if len(scopes) == 0 {
+				scopes = defaultLoginScopes
+			}
+
+			token, err := deviceLogin(ctx, http.DefaultClient, issuer, clientID, scopes, os.Stdout)
+			if err != nil {
+				return err
+			}
+			if err := client.SaveLoginToken(ctx, token); err != nil {
+				if errors.Is(err, secrets.ErrBackendUnavailable) {
+					return fmt.Errorf("cannot store the login token: the system keychain is unavailable")
+				}
+				return fmt.Errorf("failed to store login token: %w", err)
+			}
+
+			fmt.Printf("%s Logged in to %s\n", shared.StatusOK.Render(shared.SymbolOK), issuer)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&issuer, "issuer", "", "OIDC issuer URL (default: from the controller)")
+	cmd.Flags().StringVar(&clientID, "client-id", "", "OIDC client ID (default: from the controller)")
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "Scopes to request (default: from the controller)")
+
+	return cmd
+}
+
+// NewLogoutCommand creates the logout command.
+func NewLogoutCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "logout",
+		Annotations: map[string]string{
+			"group": "configuration",
+		},
+		Short: "Remove the token stored by conductor login",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := client.DeleteLoginToken(cmd.Context()); err != nil {
+				if errors.Is(err, secrets.ErrSecretNotFound) {
+					fmt.Println(shared.Muted.Render("Not logged in"))
+					return nil
+				}
+				return fmt.Errorf("failed to remove login token: %w", err)
+			}
+			fmt.Printf("%s Logged out\n", shared.StatusOK.Render(shared.SymbolOK))
+			return nil
+		},
+	}
+}
+
+type oidcInfo struct {
+	issuer   string
+	clientID string
+	scopes   []string
+}
+
+// controllerOIDCInfo asks the controller which issuer to log in with.
+func controllerOIDCInfo(ctx context.Context) (*oidcInfo, error) {
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	resp, err := c.Get(ctx, controllerauth.OIDCInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read login settings from the controller (is oidc configured? use --issuer and --client-id): %w", err)
+	}
+
+	info := &oidcInfo{}
+	info.issuer, _ = resp["issuer"].(string)
+	info.clientID, _ = resp["client_id"].(string)
+	if scopes, ok := resp["scopes"].([]any); ok {
+		for _, s := range scopes {
+			if scope, ok := s.(string); ok {
+				info.scopes = append(info.scopes, scope)
+			}
+		}
+	}
+	return info, nil
+}
+
+// deviceLogin runs the OAuth 2.0 device authorization flow against an OIDC
+// issuer, printing the verification URL and code to out.
+func deviceLogin(ctx context.Context, httpClient *http.Client, issuer, clientID string, scopes []string, out io.Writer) (*client.LoginToken, error) {
+	if issuer == "" || clientID == "" {
+		return nil, fmt.Errorf("an issuer and client ID are required")
+	}
+
+	doc, err := controllerauth.DiscoverOIDC(ctx, httpClient, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if doc.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("issuer %s does not support the device authorization flow", issuer)
+	}
+
+	cfg := oauth2.Config{
+		ClientID: clientID,
+		Scopes:   scopes,
+		Endpoint: oauth2.Endpoint{
+			DeviceAuthURL: doc.DeviceAuthorizationEndpoint,
+			TokenURL:      doc.TokenEndpoint,
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+
+	device, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device login: %w", err)
+	}
+
+	if device.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "Open %s to log in.\n", device.VerificationURIComplete)
+		fmt.Fprintf(out, "Confirm the code %s when asked.\n", device.UserCode)
+	} else {
+		fmt.Fprintf(out, "Open %s and enter the code %s to log in.\n", device.VerificationURI, device.UserCode)
+	}
+	fmt.Fprintln(out, "Waiting for approval...")
+
+	tok, err := cfg.DeviceAccessToken(ctx, device)
+	if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+
+	token := &client.LoginToken{
+		Issuer:   issuer,
+		ClientID: clientID,
+		TokenURL: doc.TokenEndpoint,
+	}
+	token.UpdateFrom(tok)
+	return token, nil
+}
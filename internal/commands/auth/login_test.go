@@ -0,0 +1,111 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeviceLogin(t *testing.T) {
+	var polls atomic.Int32
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                        server.URL,
+			"jwks_uri":                      server.URL + "/keys",
+			"token_endpoint":                server.URL + "/token",
+			"device_authorization_endpoint": server.URL + "/device",
+		})
+	})
+	mux.HandleFunc("POST /device", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "conductor" || !strings.Contains(r.Form.Get("scope"), "openid") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"device_code":      "device-123",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": server.URL + "/activate",
+			"expires_in":       60,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("device_code") != "device-123" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		// The user approves after the first poll
+		if polls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access",
+			"id_token":      "id-token",
+			"refresh_token": "refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	var out strings.Builder
+	token, err := deviceLogin(context.Background(), server.Client(), server.URL, "conductor", defaultLoginScopes, &out)
+	if err != nil {
+		t.Fatalf("deviceLogin() error = %v", err)
+	}
+
+	if !strings.Contains(out.String(), "ABCD-EFGH") || !strings.Contains(out.String(), "/activate") {
+		t.Errorf("output does not show the code and URL: %q", out.String())
+	}
+	if token.BearerToken() != "id-token" || token.RefreshToken != "refresh" {
+		t.Errorf("token = %+v", token)
+	}
+	if token.Issuer != server.URL || token.ClientID != "conductor" || token.TokenURL != server.URL+"/token" {
+		t.Errorf("token metadata = %+v", token)
+	}
+}
+
+func TestDeviceLogin_NoDeviceEndpoint(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":         server.URL,
+			"jwks_uri":       server.URL + "/keys",
+			"token_endpoint": server.URL + "/token",
+		})
+	}))
+	defer server.Close()
+
+	_, err := deviceLogin(context.Background(), server.Client(), server.URL, "conductor", nil, &strings.Builder{})
+	if err == nil || !strings.Contains(err.Error(), "device authorization") {
+		t.Errorf("deviceLogin() error = %v, want unsupported device flow", err)
+	}
+}
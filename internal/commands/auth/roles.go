@@ -0,0 +1,171 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/commands/shared"
+)
+
+func newRolesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "roles",
+		Short: "Manage roles",
+	}
+
+	cmd.AddCommand(newRolesListCommand())
+	cmd.AddCommand(newRolesShowCommand())
+	cmd.AddCommand(newRolesCreateCommand())
+	cmd.AddCommand(newRolesDeleteCommand())
+
+	return cmd
+}
+
+func newRolesListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List built-in and custom roles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiGet("/v1/auth/roles")
+			if err != nil {
+				return err
+			}
+			if shared.GetJSON() {
+				return json.NewEncoder(os.Stdout).Encode(resp)
+			}
+
+			roles, _ := resp["roles"].([]any)
+			fmt.Printf("%s %s %s\n",
+				shared.Bold.Render(fmt.Sprintf("%-20s", "NAME")),
+				shared.Bold.Render(fmt.Sprintf("%-9s", "TYPE")),
+				shared.Bold.Render("DESCRIPTION"))
+			for _, r := range roles {
+				role, _ := r.(map[string]any)
+				name, _ := role["name"].(string)
+				description, _ := role["description"].(string)
+				kind := "custom"
+				if builtin, _ := role["builtin"].(bool); builtin {
+					kind = "built-in"
+				}
+				fmt.Printf("%-20s %-9s %s\n", name, kind, description)
+			}
+			return nil
+		},
+	}
+}
+
+func newRolesShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a role's permissions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiGet("/v1/auth/roles/" + url.PathEscape(args[0]))
+			if err != nil {
+				return err
+			}
+			if shared.GetJSON() {
+				return json.NewEncoder(os.Stdout).Encode(resp)
+			}
+
+			fmt.Printf("%s %s\n", shared.Muted.Render("Role:"), shared.Bold.Render(args[0]))
+			if description, _ := resp["description"].(string); description != "" {
+				fmt.Printf("%s %s\n", shared.Muted.Render("Description:"), description)
+			}
+			permissions, _ := resp["permissions"].([]any)
+			for _, p := range permissions {
+				perm, _ := p.(map[string]any)
+				fmt.Printf("  %s %s\n", shared.Muted.Render("Actions:"), joinStrings(perm["actions"]))
+				if _, ok := perm["workflows"]; ok {
+					fmt.Printf("    %s %s\n", shared.Muted.Render("Workflows:"), joinStrings(perm["workflows"]))
+				}
+				if _, ok := perm["workspaces"]; ok {
+					fmt.Printf("    %s %s\n", shared.Muted.Render("Workspaces:"), joinStrings(perm["workspaces"]))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func newRolesCreateCommand() *cobra.Command {
+	var (
+		actions     []string
+		workflows   []string
+		workspaces  []string
+		description string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create or replace a custom role",
+		Long: `Create a custom role, or replace an existing one, with a single permission.
+
+Workflow and workspace patterns match exactly or, with a trailing "*", by
+prefix. Without patterns the role applies to all workflows and workspaces.`,
+		Example: `  # Let deployers run and cancel deploy workflows in production
+  conductor auth roles create deployer --allow run,cancel,read \
+    --workflow "deploy-*" --workspace production`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiPost("/v1/auth/roles", map[string]any{
+				"name":        args[0],
+				"description": description,
+				"permissions": []map[string]any{{
+					"actions":    actions,
+					"workflows":  workflows,
+					"workspaces": workspaces,
+				}},
+			})
+			if err != nil {
+				return err
+			}
+			if shared.GetJSON() {
+				return json.NewEncoder(os.Stdout).Encode(resp)
+			}
+			fmt.Printf("%s Role %s saved\n", shared.StatusOK.Render(shared.SymbolOK), args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&actions, "allow", nil, "Actions the role grants (comma-separated)")
+	cmd.Flags().StringSliceVar(&workflows, "workflow", nil, "Workflow name patterns the role applies to")
+	cmd.Flags().StringSliceVar(&workspaces, "workspace", nil, "Workspace patterns the role applies to")
+	cmd.Flags().StringVar(&description, "description", "", "Role description")
+	_ = cmd.MarkFlagRequired("allow")
+
+	return cmd
+}
+
+func newRolesDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a custom role",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiDelete("/v1/auth/roles/" + url.PathEscape(args[0])); err != nil {
+				return err
+			}
+			fmt.Printf("%s Role %s deleted\n", shared.StatusOK.Render(shared.SymbolOK), args[0])
+			return nil
+		},
+	}
+}
@@ -74,6 +74,11 @@ Examples:
     --base-url "https://github.mycompany.com/api/v3" \
     --token '${WORK_GITHUB_TOKEN}'
 
+  # Add self-hosted GitLab
+  conductor integrations add gitlab \
+    --base-url "https://gitlab.mycompany.com/api/v4" \
+    --token '${GITLAB_TOKEN}'
+
   # Add Jira with basic auth
   conductor integrations add jira \
     --base-url "https://mycompany.atlassian.net" \
@@ -48,6 +48,9 @@ Use 'conductor run' to execute a workflow. Use 'conductor history' to view past
 	cmd.AddCommand(newHistoryOutputCommand())
 	cmd.AddCommand(newHistoryLogsCommand())
 	cmd.AddCommand(newHistoryCancelCommand())
+	cmd.AddCommand(newHistoryPauseCommand())
+	cmd.AddCommand(newHistoryResumeCommand())
+	cmd.AddCommand(newHistoryRetryCommand())
 
 	return cmd
 }
@@ -173,6 +176,80 @@ func newHistoryCancelCommand() *cobra.Command {
 	}
 }
 
+func newHistoryPauseCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause <run-id>",
+		Short: "Pause a running workflow",
+		Long: `Pause a pending or running workflow execution.
+
+A running workflow pauses once its current step finishes and gives up its
+execution slot. Completed steps are kept, and 'conductor history resume'
+continues the run from the next step.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.CompleteActiveRunIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return historyRunAction(args[0], "/pause", nil, "Pause requested for execution %s")
+		},
+	}
+}
+
+func newHistoryResumeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume <run-id>",
+		Short: "Resume a paused workflow",
+		Long: `Resume a paused workflow execution from the step after the last completed one.
+
+The run is queued again and waits for a free execution slot.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.CompleteRunIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return historyRunAction(args[0], "/resume", nil, "Execution %s resumed")
+		},
+	}
+}
+
+func newHistoryRetryCommand() *cobra.Command {
+	var inputs []string
+
+	cmd := &cobra.Command{
+		Use:   "retry <run-id> <step-id>",
+		Short: "Retry the failed step of a workflow",
+		Long: `Execute the failed step of a workflow execution again and continue the same run.
+
+Steps completed before the failure are not executed again. Use --input to edit
+the step's inputs for the retry; for LLM steps, "prompt" and "system" replace
+the step's prompts.
+
+See also: conductor history show --failed, conductor run replay`,
+		Example: `  # Example 1: Retry the failed step
+  conductor history retry abc123 summarize
+
+  # Example 2: Retry an LLM step with an edited prompt
+  conductor history retry abc123 summarize --input prompt="Summarize in one line"`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completion.CompleteRunIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var body map[string]any
+			if len(inputs) > 0 {
+				edited := make(map[string]any, len(inputs))
+				for _, input := range inputs {
+					key, value, ok := strings.Cut(input, "=")
+					if !ok || key == "" {
+						return fmt.Errorf("invalid input format %q (expected key=value)", input)
+					}
+					edited[key] = value
+				}
+				body = map[string]any{"inputs": edited}
+			}
+			return historyRunAction(args[0], "/steps/"+args[1]+"/retry", body, "Retrying step "+args[1]+" of execution %s")
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&inputs, "input", nil, "Edited step input in key=value format (can be specified multiple times)")
+
+	return cmd
+}
+
 func historyList(status, workflow string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -322,14 +399,23 @@ func historyShow(id string, showFailureDetails bool) error {
 				fmt.Printf("  %s %s\n", shared.Muted.Render("Error Message:"), shared.StatusError.Render(errorMsg))
 			}
 
-			// Show the step that failed (from progress.current_step)
-			var failedStep string
-			if progress, ok := resp["progress"].(map[string]any); ok {
-				if current, ok := progress["current_step"].(string); ok && current != "" {
-					failedStep = current
-					fmt.Printf("  %s %s\n", shared.Muted.Render("Failed At:"), failedStep)
+			// Show the step that failed (from failed_step, or progress.current_step)
+			failedStep, _ := resp["failed_step"].(string)
+			if failedStep == "" {
+				if progress, ok := resp["progress"].(map[string]any); ok {
+					failedStep, _ = progress["current_step"].(string)
 				}
 			}
+			if failedStep != "" {
+				fmt.Printf("  %s %s\n", shared.Muted.Render("Failed At:"), failedStep)
+			}
+
+			// Suggest retrying the step in place
+			if _, ok := resp["failed_step"].(string); ok && failedStep != "" {
+				fmt.Println()
+				fmt.Println(shared.Bold.Render("Retry the Failed Step"))
+				fmt.Printf("  %s\n", shared.StatusInfo.Render(fmt.Sprintf("conductor history retry %s %s", id, failedStep)))
+			}
 
 			// Suggest replay command
 			fmt.Println()
@@ -521,6 +607,33 @@ func historyCancel(id string) error {
 	return nil
 }
 
+// historyRunAction posts an action on a run and reports the run's new status.
+// message is formatted with the run ID.
+func historyRunAction(id, action string, body any, message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	resp, err := c.Post(ctx, "/v1/runs/"+id+action, body)
+	if err != nil {
+		return fmt.Errorf("failed to update execution: %w", err)
+	}
+
+	if shared.GetJSON() {
+		return json.NewEncoder(os.Stdout).Encode(resp)
+	}
+
+	fmt.Printf("%s %s\n", shared.StatusOK.Render(shared.SymbolOK), fmt.Sprintf(message, id))
+	if status, ok := resp["status"].(string); ok {
+		fmt.Printf("%s %s\n", shared.Muted.Render("Status:"), status)
+	}
+	return nil
+}
+
 func truncate(s string, max int) string {
 	if len(s) <= max {
 		return s
@@ -28,13 +28,16 @@ func NewMCPCommand() *cobra.Command {
 		Short: "Manage MCP (Model Context Protocol) servers",
 		Long: `Manage MCP servers for use in Conductor workflows.
 
-MCP servers provide tools that can be used in workflow steps.
+MCP servers provide tools, resources, and prompts that can be used in
+workflow steps.
 
 Commands:
   init      Create a new MCP server project from a template
   list      List all registered MCP servers
   status    Show detailed status of an MCP server
   tools     List tools available from an MCP server
+  resources List or read resources from an MCP server
+  prompts   List or render prompts from an MCP server
   start     Start a global MCP server
   stop      Stop a running MCP server
   restart   Restart an MCP server
@@ -42,13 +45,17 @@ Commands:
   remove    Remove a global MCP server
   validate  Validate an MCP server configuration
   test      Test an MCP server by starting it and checking connectivity
-  logs      View logs from an MCP server`,
+  logs      View logs from an MCP server
+  lock      Pin the tools of a workflow's MCP servers
+  verify    Check a workflow's MCP servers against the lockfile`,
 	}
 
 	cmd.AddCommand(newMCPInitCommand())
 	cmd.AddCommand(newMCPListCommand())
 	cmd.AddCommand(newMCPStatusCommand())
 	cmd.AddCommand(newMCPToolsCommand())
+	cmd.AddCommand(newMCPResourcesCommand())
+	cmd.AddCommand(newMCPPromptsCommand())
 	cmd.AddCommand(newMCPStartCommand())
 	cmd.AddCommand(newMCPStopCommand())
 	cmd.AddCommand(newMCPRestartCommand())
@@ -57,6 +64,8 @@ Commands:
 	cmd.AddCommand(newMCPValidateCommand())
 	cmd.AddCommand(newMCPTestCommand())
 	cmd.AddCommand(newMCPLogsCommand())
+	cmd.AddCommand(newMCPLockCommand())
+	cmd.AddCommand(newMCPVerifyCommand())
 
 	return cmd
 }
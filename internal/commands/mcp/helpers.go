@@ -15,6 +15,7 @@
 package mcp
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -84,3 +85,20 @@ func containsShellMeta(s string) bool {
 	}
 	return false
 }
+
+// parseKeyValueFlags converts repeated KEY=VALUE flags into a map.
+// kind names the flag in error messages (e.g. "header").
+func parseKeyValueFlags(kind string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(values))
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid %s %q: must be in KEY=VALUE format", kind, v)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/commands/shared"
+	"github.com/tombee/conductor/internal/mcp"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// Lock and verify commands
+
+func newMCPLockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock <workflow>",
+		Short: "Pin the tools of a workflow's MCP servers",
+		Long: `Start each MCP server defined in a workflow and record its tools in
+the mcp.lock file next to the workflow.
+
+The lockfile holds each tool's name, description and a hash of its input
+schema. Runs compare servers against it and warn or refuse to run when
+tools change, depending on the security profile. Review tool changes
+before re-running this command.
+
+Examples:
+  conductor mcp lock workflows/review.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPLock(context.Background(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func newMCPVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <workflow>",
+		Short: "Check a workflow's MCP servers against the lockfile",
+		Long: `Start each MCP server defined in a workflow and compare its tools with
+the mcp.lock file next to the workflow.
+
+Exits with an error if a tool was added, removed or changed, or if a
+server is missing from the lockfile. Suspicious tool descriptions are
+reported as warnings.
+
+Examples:
+  conductor mcp verify workflows/review.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPVerify(context.Background(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runMCPLock(ctx context.Context, workflowArg string) error {
+	def, workflowDir, err := loadLockWorkflow(workflowArg)
+	if err != nil {
+		return err
+	}
+
+	path := mcp.LockfilePath(workflowDir)
+	lock, err := mcp.LoadLockfile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		lock = mcp.NewLockfile()
+	} else if err != nil {
+		return err
+	}
+
+	for _, serverDef := range def.MCPServers {
+		config := mcp.WorkflowServerConfig(serverDef)
+		tools, err := listServerTools(ctx, config)
+		if err != nil {
+			return err
+		}
+		printSuspiciousTools(mcp.ScanTools(serverDef.Name, tools))
+
+		serverLock, err := mcp.NewServerLock(mcp.ServerSource(config), tools)
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint MCP server %s: %w", serverDef.Name, err)
+		}
+		lock.Servers[serverDef.Name] = serverLock
+		fmt.Printf("%s (%d tools)\n", shared.RenderOK("Pinned "+serverDef.Name), len(serverLock.Tools))
+	}
+
+	if err := lock.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("\nWrote %s\n", path)
+	return nil
+}
+
+func runMCPVerify(ctx context.Context, workflowArg string) error {
+	def, workflowDir, err := loadLockWorkflow(workflowArg)
+	if err != nil {
+		return err
+	}
+
+	path := mcp.LockfilePath(workflowDir)
+	lock, err := mcp.LoadLockfile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no lockfile at %s (run 'conductor mcp lock %s' to create it)", path, workflowArg)
+	} else if err != nil {
+		return err
+	}
+
+	var drifted int
+	for _, serverDef := range def.MCPServers {
+		config := mcp.WorkflowServerConfig(serverDef)
+		tools, err := listServerTools(ctx, config)
+		if err != nil {
+			return err
+		}
+		printSuspiciousTools(mcp.ScanTools(serverDef.Name, tools))
+
+		current, err := mcp.NewServerLock(mcp.ServerSource(config), tools)
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint MCP server %s: %w", serverDef.Name, err)
+		}
+
+		drift := lock.Compare(serverDef.Name, current)
+		if len(drift) == 0 {
+			fmt.Println(shared.RenderOK(serverDef.Name + " matches lockfile"))
+			continue
+		}
+		drifted++
+		fmt.Println(shared.RenderError(serverDef.Name + " differs from lockfile"))
+		for _, d := range drift {
+			fmt.Printf("  - %s\n", d)
+		}
+	}
+
+	if drifted > 0 {
+		return fmt.Errorf("%d MCP server(s) differ from %s", drifted, path)
+	}
+	return nil
+}
+
+// loadLockWorkflow reads a workflow and returns it with its directory.
+func loadLockWorkflow(workflowArg string) (*workflow.Definition, string, error) {
+	path, err := shared.ResolveWorkflowPath(workflowArg)
+	if err != nil {
+		return nil, "", shared.NewInvalidWorkflowError("failed to resolve workflow path", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", shared.NewInvalidWorkflowError("failed to read workflow file", err)
+	}
+
+	def, err := workflow.ParseDefinition(data)
+	if err != nil {
+		return nil, "", shared.NewInvalidWorkflowError("failed to parse workflow", err)
+	}
+	if len(def.MCPServers) == 0 {
+		return nil, "", fmt.Errorf("workflow %s does not define any MCP servers", def.Name)
+	}
+
+	return def, filepath.Dir(path), nil
+}
+
+// listServerTools connects to an MCP server and lists its tools.
+func listServerTools(ctx context.Context, config mcp.ServerConfig) ([]mcp.ToolDefinition, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	client, err := mcp.NewClient(ctx, mcp.ClientConfig{
+		ServerName: config.Name,
+		Transport:  config.Transport,
+		Command:    config.Command,
+		Args:       config.Args,
+		Env:        config.Env,
+		URL:        config.URL,
+		Headers:    config.Headers,
+		Auth:       config.Auth,
+		Timeout:    config.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server %s: %w", config.Name, err)
+	}
+	defer client.Close()
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools from MCP server %s: %w", config.Name, err)
+	}
+	return tools, nil
+}
+
+func printSuspiciousTools(findings []mcp.SuspiciousTool) {
+	for _, finding := range findings {
+		fmt.Println(shared.RenderWarn("Suspicious tool description: " + finding.String()))
+	}
+}
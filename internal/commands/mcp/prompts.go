@@ -0,0 +1,175 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/commands/completion"
+	"github.com/tombee/conductor/internal/commands/shared"
+)
+
+// newMCPPromptsCommand creates the 'mcp prompts' command.
+func newMCPPromptsCommand() *cobra.Command {
+	var promptArgs []string
+
+	cmd := &cobra.Command{
+		Use:   "prompts <name> [prompt]",
+		Short: "List or render prompts from an MCP server",
+		Long: `List the prompts exposed by an MCP server, or render one prompt.
+
+The server must be running. With a prompt argument, the prompt is rendered
+with the values given by --arg.
+
+Workflows use MCP prompts through prompt_from and system_from on llm and
+agent steps.
+
+Examples:
+  conductor mcp prompts prompts-server
+  conductor mcp prompts prompts-server code-review --arg language=go
+  conductor mcp prompts prompts-server --json`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completion.CompleteMCPServerNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				values, err := parseKeyValueFlags("argument", promptArgs)
+				if err != nil {
+					return err
+				}
+				return runMCPGetPrompt(args[0], args[1], values)
+			}
+			return runMCPPrompts(args[0])
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&promptArgs, "arg", nil, "Prompt argument in KEY=VALUE format (repeatable)")
+
+	return cmd
+}
+
+func runMCPPrompts(name string) error {
+	client := newMCPAPIClient()
+	ctx := context.Background()
+
+	data, err := client.get(ctx, "/v1/mcp/servers/"+name+"/prompts")
+	if err != nil {
+		return err
+	}
+
+	if shared.GetJSON() {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var resp struct {
+		Prompts []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Arguments   []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Required    bool   `json:"required"`
+			} `json:"arguments"`
+		} `json:"prompts"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(resp.Prompts) == 0 {
+		fmt.Println("No prompts available from this server.")
+		return nil
+	}
+
+	fmt.Printf("Prompts from %s:\n\n", name)
+	for _, p := range resp.Prompts {
+		fmt.Printf("  %s\n", p.Name)
+		if p.Description != "" {
+			desc := wrapText(p.Description, 60)
+			for _, line := range strings.Split(desc, "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+		for _, arg := range p.Arguments {
+			label := arg.Name
+			if arg.Required {
+				label += " " + shared.Muted.Render("(required)")
+			}
+			if arg.Description != "" {
+				label += ": " + arg.Description
+			}
+			fmt.Printf("    - %s\n", label)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runMCPGetPrompt(name, prompt string, args map[string]string) error {
+	client := newMCPAPIClient()
+	ctx := context.Background()
+
+	query := url.Values{}
+	for k, v := range args {
+		query.Set(k, v)
+	}
+	path := "/v1/mcp/servers/" + name + "/prompts/" + url.PathEscape(prompt)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := client.get(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	if shared.GetJSON() {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var resp struct {
+		Messages []struct {
+			Role string `json:"role"`
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"messages"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	for i, m := range resp.Messages {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(shared.Header.Render(m.Role + ":"))
+		if m.Text == "" {
+			fmt.Println(shared.Muted.Render("[" + m.Type + " content]"))
+			continue
+		}
+		fmt.Println(m.Text)
+	}
+
+	return nil
+}
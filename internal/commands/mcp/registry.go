@@ -25,50 +25,73 @@ import (
 	"github.com/tombee/conductor/internal/config"
 )
 
+// mcpAddOptions holds the flags for 'mcp add'.
+type mcpAddOptions struct {
+	command   string
+	args      []string
+	env       []string
+	url       string
+	transport string
+	headers   []string
+	timeout   int
+	autoStart bool
+	dryRun    bool
+}
+
 // newMCPAddCommand creates the 'mcp add' command.
 func newMCPAddCommand() *cobra.Command {
-	var (
-		command   string
-		args      []string
-		env       []string
-		timeout   int
-		autoStart bool
-		dryRun    bool
-	)
+	var opts mcpAddOptions
 
 	cmd := &cobra.Command{
 		Use:   "add <name>",
 		Short: "Register a new global MCP server",
 		Long: `Register a new global MCP server.
 
+Local servers are started from --command and spoken to over stdio.
+Remote servers are reached at --url over Streamable HTTP (default) or SSE.
+
 The server configuration is saved to ~/.config/conductor/mcp.yaml.
 
 Examples:
   conductor mcp add github --command npx --args "-y" --args "@modelcontextprotocol/server-github"
   conductor mcp add my-server --command python --args "server.py" --env "DEBUG=true"
-  conductor mcp add db --command ./db-server --timeout 60 --auto-start`,
+  conductor mcp add db --command ./db-server --timeout 60 --auto-start
+  conductor mcp add docs --url https://mcp.example.com/mcp --header 'Authorization=Bearer ${DOCS_TOKEN}'
+  conductor mcp add legacy --url https://mcp.example.com/sse --transport sse`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
-			return runMCPAdd(cmdArgs[0], command, args, env, timeout, autoStart, dryRun)
+			if opts.command == "" && opts.url == "" {
+				return fmt.Errorf("either --command or --url is required")
+			}
+			if opts.command != "" && opts.url != "" {
+				return fmt.Errorf("--command and --url are mutually exclusive")
+			}
+			return runMCPAdd(cmdArgs[0], opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&command, "command", "", "Command to run (required)")
-	cmd.Flags().StringArrayVar(&args, "args", nil, "Command arguments (can be repeated)")
-	cmd.Flags().StringArrayVar(&env, "env", nil, "Environment variables in KEY=VALUE format (can be repeated)")
-	cmd.Flags().IntVar(&timeout, "timeout", 30, "Timeout for tool calls in seconds")
-	cmd.Flags().BoolVar(&autoStart, "auto-start", false, "Start automatically when controller starts")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be added without executing")
-
-	cmd.MarkFlagRequired("command")
+	cmd.Flags().StringVar(&opts.command, "command", "", "Command to run (local servers)")
+	cmd.Flags().StringArrayVar(&opts.args, "args", nil, "Command arguments (can be repeated)")
+	cmd.Flags().StringArrayVar(&opts.env, "env", nil, "Environment variables in KEY=VALUE format (can be repeated)")
+	cmd.Flags().StringVar(&opts.url, "url", "", "Endpoint of a remote server")
+	cmd.Flags().StringVar(&opts.transport, "transport", "", "Transport: stdio, http, or sse (default: http for --url, stdio for --command)")
+	cmd.Flags().StringArrayVar(&opts.headers, "header", nil, "HTTP headers for remote servers in KEY=VALUE format (can be repeated)")
+	cmd.Flags().IntVar(&opts.timeout, "timeout", 30, "Timeout for tool calls in seconds")
+	cmd.Flags().BoolVar(&opts.autoStart, "auto-start", false, "Start automatically when controller starts")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show what would be added without executing")
 
 	return cmd
 }
 
-func runMCPAdd(name, command string, args, env []string, timeout int, autoStart bool, dryRun bool) error {
+func runMCPAdd(name string, opts mcpAddOptions) error {
+	headers, err := parseKeyValueFlags("header", opts.headers)
+	if err != nil {
+		return err
+	}
+
 	// Handle dry-run mode
-	if dryRun {
-		return mcpAddDryRun(name, command, args, env, timeout, autoStart)
+	if opts.dryRun {
+		return mcpAddDryRun(name, opts)
 	}
 
 	client := newMCPAPIClient()
@@ -76,11 +99,14 @@ func runMCPAdd(name, command string, args, env []string, timeout int, autoStart
 
 	reqBody := map[string]any{
 		"name":       name,
-		"command":    command,
-		"args":       args,
-		"env":        env,
-		"timeout":    timeout,
-		"auto_start": autoStart,
+		"transport":  opts.transport,
+		"command":    opts.command,
+		"args":       opts.args,
+		"env":        opts.env,
+		"url":        opts.url,
+		"headers":    headers,
+		"timeout":    opts.timeout,
+		"auto_start": opts.autoStart,
 	}
 
 	body, err := json.Marshal(reqBody)
@@ -133,7 +159,9 @@ func runMCPRemove(name string) error {
 }
 
 // mcpAddDryRun shows what would be added when registering an MCP server
-func mcpAddDryRun(name, command string, args, env []string, timeout int, autoStart bool) error {
+func mcpAddDryRun(name string, opts mcpAddOptions) error {
+	command, args, env, timeout, autoStart := opts.command, opts.args, opts.env, opts.timeout, opts.autoStart
+
 	// Get config directory for placeholder
 	configDir, err := config.ConfigDir()
 	if err != nil {
@@ -146,7 +174,27 @@ func mcpAddDryRun(name, command string, args, env []string, timeout int, autoSta
 	mcpConfigPath := "<config-dir>/mcp.yaml"
 
 	// Build description of what would be added
-	description := fmt.Sprintf("register MCP server '%s' (command: %s", name, command)
+	var description string
+	if opts.url != "" {
+		description = fmt.Sprintf("register MCP server '%s' (url: %s", name, opts.url)
+		if opts.transport != "" {
+			description += fmt.Sprintf(", transport: %s", opts.transport)
+		}
+		if len(opts.headers) > 0 {
+			maskedHeaders := make([]string, len(opts.headers))
+			for i, h := range opts.headers {
+				parts := strings.SplitN(h, "=", 2)
+				if len(parts) == 2 {
+					maskedHeaders[i] = parts[0] + "=" + shared.MaskSensitiveData(parts[0], parts[1])
+				} else {
+					maskedHeaders[i] = h
+				}
+			}
+			description += fmt.Sprintf(", headers: %v", maskedHeaders)
+		}
+	} else {
+		description = fmt.Sprintf("register MCP server '%s' (command: %s", name, command)
+	}
 	if len(args) > 0 {
 		description += fmt.Sprintf(", args: %v", args)
 	}
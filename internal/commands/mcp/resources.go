@@ -0,0 +1,146 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/commands/completion"
+	"github.com/tombee/conductor/internal/commands/shared"
+)
+
+// newMCPResourcesCommand creates the 'mcp resources' command.
+func newMCPResourcesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources <name> [uri]",
+		Short: "List or read resources from an MCP server",
+		Long: `List the resources exposed by an MCP server, or read one resource.
+
+The server must be running. With a URI argument, the resource content is
+printed instead of the list.
+
+Workflows reference resources as mcp://<server>/<uri>.
+
+Examples:
+  conductor mcp resources docs-server
+  conductor mcp resources docs-server file:///docs/setup.md
+  conductor mcp resources docs-server --json`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completion.CompleteMCPServerNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				return runMCPReadResource(args[0], args[1])
+			}
+			return runMCPResources(args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runMCPResources(name string) error {
+	client := newMCPAPIClient()
+	ctx := context.Background()
+
+	data, err := client.get(ctx, "/v1/mcp/servers/"+name+"/resources")
+	if err != nil {
+		return err
+	}
+
+	if shared.GetJSON() {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var resp struct {
+		Resources []struct {
+			URI         string `json:"uri"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			MimeType    string `json:"mime_type"`
+		} `json:"resources"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(resp.Resources) == 0 {
+		fmt.Println("No resources available from this server.")
+		return nil
+	}
+
+	fmt.Printf("Resources from %s:\n\n", name)
+	for _, r := range resp.Resources {
+		fmt.Printf("  %s\n", r.URI)
+		details := r.Name
+		if r.MimeType != "" {
+			details += " " + shared.Muted.Render("("+r.MimeType+")")
+		}
+		fmt.Printf("    %s\n", details)
+		if r.Description != "" {
+			desc := wrapText(r.Description, 60)
+			for _, line := range strings.Split(desc, "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runMCPReadResource(name, uri string) error {
+	client := newMCPAPIClient()
+	ctx := context.Background()
+
+	data, err := client.get(ctx, "/v1/mcp/servers/"+name+"/resources/read?uri="+url.QueryEscape(uri))
+	if err != nil {
+		return err
+	}
+
+	if shared.GetJSON() {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var resp struct {
+		Contents []struct {
+			URI      string `json:"uri"`
+			MimeType string `json:"mime_type"`
+			Text     string `json:"text"`
+			Blob     string `json:"blob"`
+		} `json:"contents"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	for _, c := range resp.Contents {
+		if c.Blob != "" {
+			fmt.Printf("%s\n", shared.Muted.Render(fmt.Sprintf("[binary content: %s, %d bytes base64]", c.MimeType, len(c.Blob))))
+			continue
+		}
+		fmt.Println(c.Text)
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -69,10 +70,14 @@ func runMCPStatus(name string) error {
 		FailureCount  int    `json:"failure_count"`
 		LastError     string `json:"last_error,omitempty"`
 		Config        *struct {
-			Command string   `json:"command"`
-			Args    []string `json:"args,omitempty"`
-			Env     []string `json:"env,omitempty"`
-			Timeout int      `json:"timeout"`
+			Transport string            `json:"transport"`
+			Command   string            `json:"command"`
+			Args      []string          `json:"args,omitempty"`
+			Env       []string          `json:"env,omitempty"`
+			URL       string            `json:"url,omitempty"`
+			Headers   map[string]string `json:"headers,omitempty"`
+			AuthType  string            `json:"auth_type,omitempty"`
+			Timeout   int               `json:"timeout"`
 		} `json:"config,omitempty"`
 		Capabilities *struct {
 			Tools     bool `json:"tools"`
@@ -108,7 +113,26 @@ func runMCPStatus(name string) error {
 	if resp.Config != nil {
 		fmt.Println()
 		fmt.Println(shared.Bold.Render("Configuration:"))
-		fmt.Printf("  %s %s\n", shared.Muted.Render("Command:"), resp.Config.Command)
+		if resp.Config.Transport != "" {
+			fmt.Printf("  %s %s\n", shared.Muted.Render("Transport:"), resp.Config.Transport)
+		}
+		if resp.Config.URL != "" {
+			fmt.Printf("  %s %s\n", shared.Muted.Render("URL:"), resp.Config.URL)
+		}
+		if len(resp.Config.Headers) > 0 {
+			headers := make([]string, 0, len(resp.Config.Headers))
+			for k, v := range resp.Config.Headers {
+				headers = append(headers, k+"="+v)
+			}
+			sort.Strings(headers)
+			fmt.Printf("  %s %s\n", shared.Muted.Render("Headers:"), strings.Join(headers, ", "))
+		}
+		if resp.Config.AuthType != "" {
+			fmt.Printf("  %s %s\n", shared.Muted.Render("Auth:"), resp.Config.AuthType)
+		}
+		if resp.Config.Command != "" {
+			fmt.Printf("  %s %s\n", shared.Muted.Render("Command:"), resp.Config.Command)
+		}
 		if len(resp.Config.Args) > 0 {
 			fmt.Printf("  %s %s\n", shared.Muted.Render("Args:"), strings.Join(resp.Config.Args, " "))
 		}
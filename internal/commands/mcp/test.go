@@ -35,7 +35,7 @@ func newMCPTestCommand() *cobra.Command {
 		Long: `Test an MCP server by starting it and verifying it responds correctly.
 
 The test will:
-1. Start the server (if not already running)
+1. Start the server, or connect to it for remote servers (if not already running)
 2. Send initialize request
 3. Verify MCP protocol handshake
 4. List available tools
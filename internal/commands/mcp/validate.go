@@ -64,10 +64,12 @@ func runMCPValidate(name string) error {
 		Name   string `json:"name"`
 		Status string `json:"status"`
 		Config *struct {
-			Command string   `json:"command"`
-			Args    []string `json:"args"`
-			Env     []string `json:"env"`
-			Timeout int      `json:"timeout"`
+			Transport string   `json:"transport"`
+			Command   string   `json:"command"`
+			Args      []string `json:"args"`
+			Env       []string `json:"env"`
+			URL       string   `json:"url"`
+			Timeout   int      `json:"timeout"`
 		} `json:"config"`
 	}
 
@@ -91,16 +93,31 @@ func runMCPValidate(name string) error {
 	}
 
 	if server.Config != nil {
-		// Check command
-		fmt.Print("  Command.................. ")
-		if server.Config.Command != "" {
-			fmt.Printf("'%s' ", server.Config.Command)
-			// Note: We can't check if command exists from CLI since controller runs it
-			fmt.Printf("%s\n", shared.Muted.Render("(check on controller)"))
+		// Check command (or URL for remote servers)
+		if server.Config.URL != "" {
+			fmt.Print("  URL...................... ")
+			if strings.HasPrefix(server.Config.URL, "https://") {
+				fmt.Printf("'%s' %s\n", server.Config.URL, shared.StatusOK.Render("OK"))
+			} else if strings.HasPrefix(server.Config.URL, "http://") {
+				fmt.Printf("'%s' %s\n", server.Config.URL, shared.StatusWarn.Render("WARNING"))
+				fmt.Printf("    %s Remote server is not using TLS\n", shared.StatusWarn.Render("Warning:"))
+				warnings++
+			} else {
+				fmt.Println(shared.StatusError.Render("INVALID"))
+				fmt.Printf("    %s URL must start with http:// or https://\n", shared.StatusError.Render("Error:"))
+				issues++
+			}
 		} else {
-			fmt.Println(shared.StatusError.Render("MISSING"))
-			fmt.Printf("    %s Command is required\n", shared.StatusError.Render("Error:"))
-			issues++
+			fmt.Print("  Command.................. ")
+			if server.Config.Command != "" {
+				fmt.Printf("'%s' ", server.Config.Command)
+				// Note: We can't check if command exists from CLI since controller runs it
+				fmt.Printf("%s\n", shared.Muted.Render("(check on controller)"))
+			} else {
+				fmt.Println(shared.StatusError.Render("MISSING"))
+				fmt.Printf("    %s Command is required\n", shared.StatusError.Render("Error:"))
+				issues++
+			}
 		}
 
 		// Check for shell injection in args
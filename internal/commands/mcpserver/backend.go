@@ -0,0 +1,88 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/tombee/conductor/internal/client"
+	"github.com/tombee/conductor/internal/mcp/server"
+)
+
+// controllerRunBackend runs workflow tools on the controller through its API.
+type controllerRunBackend struct {
+	client *client.Client
+}
+
+// StartRun submits a run of the tool's endpoint or workflow file.
+func (b *controllerRunBackend) StartRun(ctx context.Context, tool server.WorkflowTool, inputs map[string]any) (*server.RunInfo, error) {
+	var resp map[string]any
+	var err error
+
+	if tool.Endpoint != "" {
+		resp, err = b.client.Post(ctx, "/v1/endpoints/"+url.PathEscape(tool.Endpoint)+"/runs", map[string]any{
+			"inputs": inputs,
+		})
+	} else {
+		var data []byte
+		data, err = os.ReadFile(tool.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read workflow file: %w", err)
+		}
+
+		params := url.Values{}
+		params.Set("workflow_dir", filepath.Dir(tool.Path))
+		resp, err = b.client.Post(ctx, "/v1/runs?"+params.Encode(), map[string]any{
+			"workflow_yaml": string(data),
+			"inputs":        inputs,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRunInfo(resp)
+}
+
+// GetRun fetches the current state of a run.
+func (b *controllerRunBackend) GetRun(ctx context.Context, id string) (*server.RunInfo, error) {
+	resp, err := b.client.Get(ctx, "/v1/runs/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	return decodeRunInfo(resp)
+}
+
+// decodeRunInfo converts a run returned by the controller API.
+func decodeRunInfo(resp map[string]any) (*server.RunInfo, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	var run server.RunInfo
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to decode run: %w", err)
+	}
+	if run.ID == "" {
+		return nil, fmt.Errorf("controller did not return run ID")
+	}
+	return &run, nil
+}
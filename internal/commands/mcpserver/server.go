@@ -23,14 +23,19 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/client"
 	"github.com/tombee/conductor/internal/commands/shared"
+	"github.com/tombee/conductor/internal/config"
+	"github.com/tombee/conductor/internal/controller/endpoint"
 	"github.com/tombee/conductor/internal/mcp/server"
 )
 
 // NewCommand creates the mcp-server command
 func NewCommand() *cobra.Command {
 	var (
-		logLevel string
+		logLevel     string
+		workflowsDir string
+		endpoints    bool
 	)
 
 	cmd := &cobra.Command{
@@ -67,18 +72,31 @@ The server exposes these tools:
   - conductor_doctor: Check installation health
 
 For safety, the conductor_run tool defaults to dry_run=true. AI assistants must
-explicitly set dry_run=false to execute workflows.`,
+explicitly set dry_run=false to execute workflows.
+
+With --workflows-dir, every workflow in the directory is also exposed as its own
+tool, with an input schema built from the workflow inputs. With --endpoints,
+every endpoint configured on the controller is exposed instead. Runs execute on
+the controller and are published as resources (conductor://runs/{id}, /logs and
+/outputs) with change notifications:
+  conductor mcp-server --workflows-dir ./workflows
+  conductor mcp-server --endpoints`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runMCPServer(cmd, logLevel)
+			if workflowsDir != "" && endpoints {
+				return fmt.Errorf("--workflows-dir and --endpoints cannot be combined")
+			}
+			return runMCPServer(cmd, logLevel, workflowsDir, endpoints)
 		},
 	}
 
 	cmd.Flags().StringVar(&logLevel, "log-level", "info", "Logging verbosity (debug, info, warn, error)")
+	cmd.Flags().StringVar(&workflowsDir, "workflows-dir", "", "Expose every workflow in this directory as a tool")
+	cmd.Flags().BoolVar(&endpoints, "endpoints", false, "Expose every controller endpoint as a tool")
 
 	return cmd
 }
 
-func runMCPServer(cmd *cobra.Command, logLevel string) error {
+func runMCPServer(cmd *cobra.Command, logLevel, workflowsDir string, endpoints bool) error {
 	// Get version info
 	versionStr, _, _ := shared.GetVersion()
 
@@ -89,6 +107,13 @@ func runMCPServer(cmd *cobra.Command, logLevel string) error {
 		LogLevel: logLevel,
 	}
 
+	// Expose workflows or endpoints as dedicated tools, run by the controller
+	if workflowsDir != "" || endpoints {
+		if err := configureWorkflowTools(&config, workflowsDir, endpoints); err != nil {
+			return err
+		}
+	}
+
 	// Create the MCP server
 	srv, err := server.NewServer(config)
 	if err != nil {
@@ -126,3 +151,46 @@ func runMCPServer(cmd *cobra.Command, logLevel string) error {
 
 	return nil
 }
+
+// configureWorkflowTools loads the workflow tools and connects to the controller that runs them.
+func configureWorkflowTools(serverCfg *server.ServerConfig, workflowsDir string, endpoints bool) error {
+	cfg, err := config.Load("")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var tools []server.WorkflowTool
+	if endpoints {
+		if !cfg.Controller.Endpoints.Enabled {
+			return fmt.Errorf("endpoints are not enabled in the controller configuration")
+		}
+		registry, err := endpoint.LoadConfig(cfg.Controller.Endpoints, cfg.Controller.WorkflowsDir, nil)
+		if err != nil {
+			return fmt.Errorf("failed to load endpoints: %w", err)
+		}
+		tools, err = server.EndpointWorkflowTools(registry, cfg.Controller.WorkflowsDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		tools, err = server.LoadWorkflowTools(workflowsDir)
+		if err != nil {
+			return err
+		}
+	}
+	if len(tools) == 0 {
+		return fmt.Errorf("no workflows found to expose as tools")
+	}
+
+	c, err := client.EnsureController(client.AutoStartConfig{
+		Enabled:    true,
+		SocketPath: cfg.Controller.SocketPath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to controller: %w", err)
+	}
+
+	serverCfg.Workflows = tools
+	serverCfg.Runs = &controllerRunBackend{client: c}
+	return nil
+}
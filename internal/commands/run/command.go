@@ -52,6 +52,7 @@ func NewCommand() *cobra.Command {
 		step                           string
 		breakpoints                    []string
 		noProgress                     bool
+		idempotencyKey                 string
 	)
 
 	cmd := &cobra.Command{
@@ -79,6 +80,9 @@ Tier Overrides:
 
 Execution Modes:
   --background   Run asynchronously, return run ID immediately
+  --idempotency-key <key>
+                 Safe retries: a repeat with the same key within the retention
+                 window follows the run it started instead of starting another
 
 Profile Selection:
   --workspace, -w <name>   Workspace for profile resolution (env: CONDUCTOR_WORKSPACE)
@@ -122,7 +126,7 @@ Progress Display:
 			}
 
 			// All execution goes through controller
-			return runWorkflowViaController(args[0], inputs, inputFile, outputFile, noStats, background, mcpDev, noCache, quiet, verbose, noInteractive, helpInputs, dryRun, noProgress, provider, model, timeout, tierFast, tierBalanced, tierStrategic, workspace, profile, bindIntegrations, securityMode, allowHosts, allowPaths, logLevel, step, breakpoints)
+			return runWorkflowViaController(args[0], inputs, inputFile, outputFile, noStats, background, mcpDev, noCache, quiet, verbose, noInteractive, helpInputs, dryRun, noProgress, provider, model, timeout, tierFast, tierBalanced, tierStrategic, workspace, profile, bindIntegrations, securityMode, allowHosts, allowPaths, logLevel, step, breakpoints, idempotencyKey)
 		},
 	}
 
@@ -155,6 +159,7 @@ Progress Display:
 	cmd.Flags().StringVar(&step, "step", "", "Pause execution at the specified step ID (debug mode)")
 	cmd.Flags().StringSliceVar(&breakpoints, "breakpoint", nil, "Pause execution at these step IDs (debug mode)")
 	cmd.Flags().BoolVar(&noProgress, "no-progress", false, "Disable interactive progress display")
+	cmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Return the run started earlier with this key instead of starting another")
 
 	// Register flag completions
 	cmd.RegisterFlagCompletionFunc("provider", completion.CompleteProviderNames)
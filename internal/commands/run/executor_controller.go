@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -36,7 +37,7 @@ import (
 )
 
 // runWorkflowViaController submits a workflow to the controller for execution
-func runWorkflowViaController(workflowPath string, inputArgs []string, inputFile, outputFile string, noStats, background, mcpDev, noCache, quiet, verbose, noInteractive, helpInputs, dryRun, noProgress bool, provider, model, timeout, tierFast, tierBalanced, tierStrategic, workspace, profile string, bindIntegrations []string, security string, allowHosts, allowPaths []string, logLevel, step string, breakpoints []string) error {
+func runWorkflowViaController(workflowPath string, inputArgs []string, inputFile, outputFile string, noStats, background, mcpDev, noCache, quiet, verbose, noInteractive, helpInputs, dryRun, noProgress bool, provider, model, timeout, tierFast, tierBalanced, tierStrategic, workspace, profile string, bindIntegrations []string, security string, allowHosts, allowPaths []string, logLevel, step string, breakpoints []string, idempotencyKey string) error {
 	ctx := context.Background()
 
 	// Apply environment variable defaults for workspace and profile
@@ -282,7 +283,11 @@ func runWorkflowViaController(workflowPath string, inputArgs []string, inputFile
 		submitPath = fmt.Sprintf("%s?%s", submitPath, params.Encode())
 	}
 
-	resp, err := c.PostYAML(ctx, submitPath, data)
+	var header http.Header
+	if idempotencyKey != "" {
+		header = http.Header{"Idempotency-Key": []string{idempotencyKey}}
+	}
+	resp, err := c.PostYAMLWithHeaders(ctx, submitPath, data, header)
 	if err != nil {
 		return shared.NewProviderError("failed to submit workflow", err)
 	}
@@ -17,6 +17,8 @@ package triggers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tombee/conductor/internal/triggers"
@@ -28,6 +30,13 @@ var (
 	scheduleEvery    string
 	scheduleAt       string
 	scheduleTimezone string
+
+	scheduleCatchup       string
+	scheduleCatchupWindow time.Duration
+	scheduleOverlap       string
+	scheduleJitter        time.Duration
+	scheduleExclude       []string
+	schedulePreview       int
 )
 
 func newAddScheduleCommand() *cobra.Command {
@@ -36,12 +45,28 @@ func newAddScheduleCommand() *cobra.Command {
 		Short: "Add a schedule trigger",
 		Long: `Add a schedule trigger that invokes a workflow on a recurring schedule.
 
-You can specify the schedule in two ways:
+You can specify the schedule in three ways:
 1. Using cron syntax: --cron="0 9 * * *"
 2. Using human-friendly syntax: --every=day --at=09:00
+3. As a one-off run: --at=2026-11-01T09:00
+
+Cron expressions take an optional seconds field first ("*/30 * * * * *"),
+month and day names, steps (5/15), and the day modifiers L (last day),
+LW (last weekday), 15W (weekday nearest the 15th), 5L (last Friday) and
+1#2 (second Monday). "@every 90m" runs at a fixed interval.
+
+The --every flag accepts: hour, day, week, month, or an interval (90m)
+The --at flag uses 24-hour format: HH:MM, or a date and time for a one-off
+
+The next fire times are shown when the schedule is added (see --preview).
+--exclude names calendars from controller.schedules.calendars, such as
+holidays, that the schedule does not run in.
 
-The --every flag accepts: hour, day, week, month
-The --at flag uses 24-hour format: HH:MM`,
+Policies:
+  --catchup none|last|all       Runs missed while the controller was down
+  --catchup-window <duration>   How far back to catch up (default 24h)
+  --overlap allow|skip|queue    When the previous run is still going
+  --jitter <duration>           Delay each run by a random amount up to this`,
 		Example: `  # Run daily at 9 AM
   conductor triggers add schedule daily-report.yaml \
     --name=daily-report \
@@ -66,6 +91,31 @@ The --at flag uses 24-hour format: HH:MM`,
     --every=week \
     --at=10:00
 
+  # Catch up a missed nightly run and never run two at once
+  conductor triggers add schedule nightly-sync.yaml \
+    --name=nightly-sync \
+    --cron="0 2 * * *" \
+    --catchup=last \
+    --overlap=skip \
+    --jitter=5m
+
+  # Run at 5 PM on the last weekday of each month, except on holidays
+  conductor triggers add schedule month-end.yaml \
+    --name=month-end \
+    --cron="0 17 LW * *" \
+    --exclude=holidays
+
+  # Run every 90 minutes
+  conductor triggers add schedule sync.yaml \
+    --name=sync \
+    --every=90m
+
+  # Run once
+  conductor triggers add schedule migrate.yaml \
+    --name=migrate \
+    --at=2026-11-01T09:00 \
+    --timezone="Europe/London"
+
   # Dry-run to preview
   conductor triggers add schedule test.yaml \
     --name=test \
@@ -78,9 +128,15 @@ The --at flag uses 24-hour format: HH:MM`,
 
 	cmd.Flags().StringVar(&scheduleName, "name", "", "Unique schedule name (required)")
 	cmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression (e.g., '0 9 * * *')")
-	cmd.Flags().StringVar(&scheduleEvery, "every", "", "Human-friendly schedule: hour, day, week, month")
-	cmd.Flags().StringVar(&scheduleAt, "at", "", "Time for daily/weekly/monthly schedules (HH:MM)")
+	cmd.Flags().StringVar(&scheduleEvery, "every", "", "Human-friendly schedule: hour, day, week, month, or an interval (90m)")
+	cmd.Flags().StringVar(&scheduleAt, "at", "", "Time for daily/weekly/monthly schedules (HH:MM), or a one-off time (2026-11-01T09:00)")
 	cmd.Flags().StringVar(&scheduleTimezone, "timezone", "UTC", "IANA timezone (default: UTC)")
+	cmd.Flags().StringVar(&scheduleCatchup, "catchup", "", "Missed run policy: none, last, all (default: none)")
+	cmd.Flags().DurationVar(&scheduleCatchupWindow, "catchup-window", 0, "How far back missed runs are caught up (default: 24h)")
+	cmd.Flags().StringVar(&scheduleOverlap, "overlap", "", "Policy when the previous run is still going: allow, skip, queue (default: allow)")
+	cmd.Flags().DurationVar(&scheduleJitter, "jitter", 0, "Delay each run by a random duration up to this")
+	cmd.Flags().StringSliceVar(&scheduleExclude, "exclude", nil, "Exclusion calendars the schedule does not run in")
+	cmd.Flags().IntVar(&schedulePreview, "preview", 5, "Number of next fire times to show")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without writing")
 
 	cmd.MarkFlagRequired("name")
@@ -91,8 +147,8 @@ The --at flag uses 24-hour format: HH:MM`,
 func runAddSchedule(cmd *cobra.Command, args []string) error {
 	workflow := args[0]
 
-	if scheduleCron == "" && scheduleEvery == "" {
-		return fmt.Errorf("either --cron or --every is required")
+	if scheduleCron == "" && scheduleEvery == "" && scheduleAt == "" {
+		return fmt.Errorf("one of --cron, --every or --at is required")
 	}
 
 	req := triggers.CreateScheduleRequest{
@@ -102,14 +158,24 @@ func runAddSchedule(cmd *cobra.Command, args []string) error {
 		Every:    scheduleEvery,
 		At:       scheduleAt,
 		Timezone: scheduleTimezone,
+
+		Catchup:       scheduleCatchup,
+		CatchupWindow: scheduleCatchupWindow,
+		Overlap:       scheduleOverlap,
+		Jitter:        scheduleJitter,
+		Exclude:       scheduleExclude,
 	}
 
+	ctx := context.Background()
+
 	if dryRun {
 		fmt.Fprintf(cmd.OutOrStdout(), "Dry-run: Would add schedule trigger:\n")
 		fmt.Fprintf(cmd.OutOrStdout(), "  Name: %s\n", req.Name)
 		fmt.Fprintf(cmd.OutOrStdout(), "  Workflow: %s\n", req.Workflow)
 		if req.Cron != "" {
 			fmt.Fprintf(cmd.OutOrStdout(), "  Cron: %s\n", req.Cron)
+		} else if req.Every == "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  At: %s\n", req.At)
 		} else {
 			fmt.Fprintf(cmd.OutOrStdout(), "  Every: %s\n", req.Every)
 			if req.At != "" {
@@ -117,7 +183,26 @@ func runAddSchedule(cmd *cobra.Command, args []string) error {
 			}
 		}
 		fmt.Fprintf(cmd.OutOrStdout(), "  Timezone: %s\n", req.Timezone)
-		return nil
+		if req.Catchup != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Catchup: %s\n", req.Catchup)
+		}
+		if req.Overlap != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Overlap: %s\n", req.Overlap)
+		}
+		if req.Jitter > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Jitter: %s\n", req.Jitter)
+		}
+		if len(req.Exclude) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Exclude: %s\n", strings.Join(req.Exclude, ", "))
+		}
+
+		// Without a config file there are no calendars, but a schedule
+		// that excludes none can still be previewed
+		mgr, err := getManager()
+		if err != nil {
+			mgr = triggers.NewManager("", "")
+		}
+		return printSchedulePreview(ctx, cmd, mgr, req)
 	}
 
 	mgr, err := getManager()
@@ -125,15 +210,39 @@ func runAddSchedule(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ctx := context.Background()
 	if err := mgr.AddSchedule(ctx, req); err != nil {
 		return fmt.Errorf("failed to add schedule: %w", err)
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Schedule trigger created!\n")
 	fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\n", scheduleName)
+	if err := printSchedulePreview(ctx, cmd, mgr, req); err != nil {
+		return err
+	}
 	fmt.Fprintf(cmd.OutOrStdout(), "\nRestart the controller for changes to take effect:\n")
 	fmt.Fprintf(cmd.OutOrStdout(), "  conductor controller restart\n")
 
 	return nil
 }
+
+// printSchedulePreview prints the schedule's next fire times.
+func printSchedulePreview(ctx context.Context, cmd *cobra.Command, mgr *triggers.Manager, req triggers.CreateScheduleRequest) error {
+	if schedulePreview <= 0 {
+		return nil
+	}
+
+	times, err := mgr.PreviewSchedule(ctx, req, schedulePreview)
+	if err != nil {
+		return err
+	}
+
+	if len(times) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "\nThe schedule has no future runs.\n")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\nNext runs:\n")
+	for _, t := range times {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", t.Format("Mon 2006-01-02 15:04:05 MST"))
+	}
+	return nil
+}
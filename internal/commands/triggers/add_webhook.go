@@ -40,6 +40,7 @@ func newAddWebhookCommand() *cobra.Command {
 
 The webhook path must be unique. Common sources include:
   - github: GitHub webhook events
+  - gitlab: GitLab webhook events (X-Gitlab-Token secret)
   - slack: Slack event subscriptions
   - generic: Generic HTTP POST with JSON payload
 
@@ -69,7 +70,7 @@ Input mapping uses JSONPath to extract values from the webhook payload.`,
 	}
 
 	cmd.Flags().StringVar(&webhookPath, "path", "", "Webhook URL path (required)")
-	cmd.Flags().StringVar(&webhookSource, "source", "", "Webhook source type: github, slack, generic (required)")
+	cmd.Flags().StringVar(&webhookSource, "source", "", "Webhook source type: github, gitlab, slack, stripe, linear, pagerduty, jira, sentry, twilio, generic (required)")
 	cmd.Flags().StringVar(&webhookSecret, "secret", "", "Secret for signature verification (e.g., ${VAR_NAME})")
 	cmd.Flags().StringSliceVar(&webhookEvents, "events", nil, "Event types to handle (comma-separated)")
 	cmd.Flags().StringSliceVar(&webhookMap, "map", nil, "Input mapping: key=jsonpath (repeatable)")
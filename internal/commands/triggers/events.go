@@ -0,0 +1,211 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/client"
+	"github.com/tombee/conductor/internal/commands/shared"
+)
+
+// newEventsCommand creates the command to inspect and redeliver trigger events.
+func newEventsCommand() *cobra.Command {
+	var status, workflow, source string
+	var failed bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "List recorded webhook and poll trigger events",
+		Long: `List the webhook and poll trigger events recorded by the controller, newest first.
+
+Each event is stored with its payload, headers, whether it matched its trigger,
+and the outcome of its delivery. Events whose delivery failed are kept as
+failed (the dead-letter list) and can be redelivered.`,
+		Example: `  # List recent trigger events
+  conductor triggers events
+
+  # List failed deliveries
+  conductor triggers events --failed
+
+  # Show an event with its payload
+  conductor triggers events show 5f0c...
+
+  # Redeliver a failed event
+  conductor triggers events redeliver 5f0c...`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if failed {
+				status = "failed"
+			}
+			return runListEvents(cmd, status, workflow, source, limit)
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", "", "Filter by status (received, delivered, ignored, rejected, failed)")
+	cmd.Flags().StringVar(&workflow, "workflow", "", "Filter by workflow name")
+	cmd.Flags().StringVar(&source, "source", "", "Filter by source (webhook, poll)")
+	cmd.Flags().BoolVar(&failed, "failed", false, "Show only failed deliveries (shorthand for --status failed)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of events to list")
+
+	cmd.AddCommand(newEventsShowCommand())
+	cmd.AddCommand(newEventsRedeliverCommand())
+
+	return cmd
+}
+
+func newEventsShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <event-id>",
+		Short: "Show a trigger event with its payload",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShowEvent(cmd, args[0])
+		},
+	}
+}
+
+func newEventsRedeliverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "redeliver <event-id>",
+		Short: "Deliver a trigger event again",
+		Long: `Deliver a recorded trigger event again, starting a new run of its workflow
+with the event's inputs. Only events that matched their trigger can be redelivered.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRedeliverEvent(cmd, args[0])
+		},
+	}
+}
+
+func runListEvents(cmd *cobra.Command, status, workflow, source string, limit int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	params := url.Values{}
+	if status != "" {
+		params.Set("status", status)
+	}
+	if workflow != "" {
+		params.Set("workflow", workflow)
+	}
+	if source != "" {
+		params.Set("source", source)
+	}
+	params.Set("limit", strconv.Itoa(limit))
+
+	resp, err := c.Get(ctx, "/v1/triggers/events?"+params.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to list trigger events: %w", err)
+	}
+
+	if shared.GetJSON() {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp)
+	}
+
+	events, _ := resp["events"].([]any)
+	if len(events) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No trigger events found.")
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%-36s %-8s %-20s %-10s %-8s %s\n",
+		"ID", "SOURCE", "WORKFLOW", "STATUS", "ATTEMPTS", "RECEIVED")
+	for _, e := range events {
+		event, _ := e.(map[string]any)
+		attempts, _ := event["attempts"].(float64)
+		received := "-"
+		if s, ok := event["created_at"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				received = formatRelativeTime(t)
+			}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%-36s %-8s %-20s %-10s %-8d %s\n",
+			stringField(event, "id"),
+			stringField(event, "source"),
+			truncate(stringField(event, "workflow"), 20),
+			stringField(event, "status"),
+			int(attempts),
+			received)
+	}
+
+	return nil
+}
+
+func runShowEvent(cmd *cobra.Command, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	event, err := c.Get(ctx, "/v1/triggers/events/"+url.PathEscape(id))
+	if err != nil {
+		return fmt.Errorf("failed to get trigger event: %w", err)
+	}
+
+	// Events are shown as JSON so the payload and headers stay readable
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(event)
+}
+
+func runRedeliverEvent(cmd *cobra.Command, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	event, err := c.Post(ctx, "/v1/triggers/events/"+url.PathEscape(id)+"/redeliver", nil)
+	if err != nil {
+		return fmt.Errorf("failed to redeliver trigger event: %w", err)
+	}
+
+	if shared.GetJSON() {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(event)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Redelivered event %s to workflow %s\n", id, stringField(event, "workflow"))
+	fmt.Fprintf(cmd.OutOrStdout(), "Run ID: %s\n", stringField(event, "run_id"))
+	return nil
+}
+
+// stringField returns a string field of a decoded JSON object, or "-".
+func stringField(m map[string]any, key string) string {
+	if s, ok := m[key].(string); ok && s != "" {
+		return s
+	}
+	return "-"
+}
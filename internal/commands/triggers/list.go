@@ -145,6 +145,10 @@ func runList(cmd *cobra.Command, args []string) error {
 				if tz == "" {
 					tz = "UTC"
 				}
+				when := sch.Cron
+				if sch.At != "" {
+					when = "at " + sch.At
+				}
 				var statusStyled string
 				if sch.Enabled {
 					statusStyled = shared.StatusOK.Render("enabled")
@@ -154,7 +158,7 @@ func runList(cmd *cobra.Command, args []string) error {
 				fmt.Fprintf(out, "  %s %s: %s %s -> %s [%s]\n",
 					shared.StatusOK.Render(shared.SymbolOK),
 					shared.Bold.Render(sch.Name),
-					sch.Cron,
+					when,
 					shared.Muted.Render("("+tz+")"),
 					sch.Workflow,
 					statusStyled)
@@ -197,17 +197,13 @@ func truncate(s string, maxLen int) string {
 }
 
 // listPollTriggers finds all poll triggers from workflow files.
-// This scans the workflows directory for poll trigger configurations.
 func listPollTriggers() ([]*polltrigger.PollTriggerRegistration, error) {
 	mgr, err := getManager()
 	if err != nil {
 		return nil, err
 	}
 
-	// This will be implemented when we add poll triggers to the manager
-	// For now, return empty list
-	_ = mgr
-	return nil, fmt.Errorf("poll trigger listing not yet implemented in workflow manager")
+	return mgr.ListPollTriggers(context.Background())
 }
 
 // getPollStateManager creates a state manager for reading poll state.
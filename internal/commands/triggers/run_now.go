@@ -0,0 +1,69 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tombee/conductor/internal/client"
+	"github.com/tombee/conductor/internal/commands/shared"
+)
+
+// newRunNowCommand creates the command to start a schedule's run immediately.
+func newRunNowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run-now <schedule>",
+		Short: "Run a scheduled workflow now",
+		Long: `Start a run of a schedule's workflow on the running controller immediately.
+
+The run is recorded in the schedule's history (last run and run count) but
+does not change when the schedule is next due. Disabled schedules can be run.`,
+		Example: `  conductor triggers run-now nightly-report`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScheduleNow(cmd, args[0])
+		},
+	}
+}
+
+func runScheduleNow(cmd *cobra.Command, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c, err := client.FromEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	run, err := c.Post(ctx, "/v1/schedules/"+url.PathEscape(name)+"/run", nil)
+	if err != nil {
+		return fmt.Errorf("failed to run schedule: %w", err)
+	}
+
+	if shared.GetJSON() {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(run)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Started schedule %s (workflow %s)\n", name, stringField(run, "workflow"))
+	fmt.Fprintf(cmd.OutOrStdout(), "Run ID: %s\n", stringField(run, "id"))
+	return nil
+}
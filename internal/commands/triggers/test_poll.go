@@ -91,7 +91,7 @@ func runTestPoll(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get the poller for this integration
-	poller, err := getPoller(pollTrigger.Integration)
+	poller, err := getPoller(pollTrigger)
 	if err != nil {
 		return fmt.Errorf("failed to get poller: %w", err)
 	}
@@ -142,7 +142,7 @@ func runTestPoll(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(cmd.OutOrStdout(), "  Total events returned: %d\n", len(events))
 	fmt.Fprintf(cmd.OutOrStdout(), "  New events (not seen): %d\n", len(newEvents))
 	if cursor != "" {
-		fmt.Fprintf(cmd.OutOrStdout(), "  Pagination cursor: %s\n", cursor)
+		fmt.Fprintf(cmd.OutOrStdout(), "  Cursor: %s\n", cursor)
 	}
 	fmt.Fprintln(cmd.OutOrStdout())
 
@@ -198,9 +198,12 @@ func findPollTrigger(name string) (*polltrigger.PollTriggerRegistration, error)
 	return nil, nil
 }
 
-// getPoller gets the appropriate poller for an integration.
-func getPoller(integration string) (polltrigger.IntegrationPoller, error) {
-	// This will be implemented when we integrate with the actual pollers
-	// For now, return an error
-	return nil, fmt.Errorf("poller for integration %q not yet available in CLI context", integration)
+// getPoller gets the appropriate poller for a trigger. Workflow-declared
+// operations carry their own poller; builtin integrations are configured
+// from the same environment variables the controller uses.
+func getPoller(reg *polltrigger.PollTriggerRegistration) (polltrigger.IntegrationPoller, error) {
+	if reg.Poller != nil {
+		return reg.Poller, nil
+	}
+	return polltrigger.NewPollerFromEnv(reg.Integration)
 }
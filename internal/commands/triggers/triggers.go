@@ -40,7 +40,9 @@ Subcommands:
   list-poll  - List poll triggers and their status
   test       - Test a poll trigger (dry-run)
   reset      - Reset poll trigger state
-  show       - Show detailed poll trigger information`,
+  show       - Show detailed poll trigger information
+  events     - List, show and redeliver recorded trigger events
+  run-now    - Run a scheduled workflow now`,
 		Annotations: map[string]string{
 			"group": "controller",
 		},
@@ -53,6 +55,8 @@ Subcommands:
 	cmd.AddCommand(newTestPollCommand())
 	cmd.AddCommand(newResetPollCommand())
 	cmd.AddCommand(newShowPollCommand())
+	cmd.AddCommand(newEventsCommand())
+	cmd.AddCommand(newRunNowCommand())
 
 	return cmd
 }
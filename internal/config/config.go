@@ -28,6 +28,7 @@ import (
 	conductorerrors "github.com/tombee/conductor/pkg/errors"
 	"github.com/tombee/conductor/pkg/profile"
 	"github.com/tombee/conductor/pkg/security"
+	"github.com/tombee/conductor/pkg/workflow"
 	"gopkg.in/yaml.v3"
 )
 
@@ -107,6 +108,9 @@ type ControllerConfig struct {
 	// MaxConcurrentRuns limits concurrent workflow executions.
 	MaxConcurrentRuns int `yaml:"max_concurrent_runs,omitempty"`
 
+	// Queue configures how runs wait for an execution slot.
+	Queue QueueConfig `yaml:"queue,omitempty"`
+
 	// DefaultTimeout is the default timeout for workflow execution.
 	DefaultTimeout time.Duration `yaml:"default_timeout,omitempty"`
 
@@ -126,6 +130,11 @@ type ControllerConfig struct {
 	// Default: 24h
 	RunRetention time.Duration `yaml:"run_retention,omitempty"`
 
+	// IdempotencyKeyRetention is how long an Idempotency-Key sent when creating
+	// a run returns that run instead of starting another.
+	// Default: 24h
+	IdempotencyKeyRetention time.Duration `yaml:"idempotency_key_retention,omitempty"`
+
 	// CheckpointsEnabled enables checkpoint saving for crash recovery.
 	CheckpointsEnabled bool `yaml:"checkpoints_enabled"`
 
@@ -152,6 +161,84 @@ type ControllerConfig struct {
 
 	// Observability configures tracing and metrics.
 	Observability ObservabilityConfig `yaml:"observability,omitempty"`
+
+	// MCPServer serves workflows as MCP tools over HTTP (controller-specific).
+	MCPServer MCPServerConfig `yaml:"mcp_server,omitempty"`
+
+	// Dashboard serves the built-in web UI (controller-specific).
+	Dashboard DashboardConfig `yaml:"dashboard,omitempty"`
+
+	// Notifications sends run lifecycle events to webhooks, chat and email.
+	Notifications NotificationsConfig `yaml:"notifications,omitempty"`
+
+	// TriggerEvents configures the record of inbound webhook and poll events.
+	TriggerEvents TriggerEventsConfig `yaml:"trigger_events,omitempty"`
+}
+
+// TriggerEventsConfig configures how inbound trigger events are recorded.
+type TriggerEventsConfig struct {
+	// Retention is how long events, including failed deliveries, are kept.
+	// Default: 168h (7 days)
+	Retention time.Duration `yaml:"retention,omitempty"`
+}
+
+// NotificationsConfig configures run notifications. A workflow's notify
+// block takes precedence; fields it leaves unset come from Defaults.
+type NotificationsConfig struct {
+	// Defaults apply to every workflow, including those without a notify block.
+	Defaults workflow.NotifyDefinition `yaml:"defaults,omitempty"`
+
+	// SMTP configures the mail server used by email channels.
+	SMTP SMTPConfig `yaml:"smtp,omitempty"`
+
+	// MaxAttempts is the number of delivery attempts before a notification
+	// is recorded as a dead letter. Default: 5
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// RetryBackoff is the delay before the first retry. It doubles after
+	// each failed attempt. Default: 5s
+	RetryBackoff time.Duration `yaml:"retry_backoff,omitempty"`
+}
+
+// SMTPConfig configures the mail server used to send email notifications.
+// STARTTLS is used when the server offers it.
+type SMTPConfig struct {
+	// Host is the SMTP server host name.
+	Host string `yaml:"host,omitempty"`
+
+	// Port is the SMTP server port. Default: 587
+	Port int `yaml:"port,omitempty"`
+
+	// Username and Password authenticate with PLAIN auth when set.
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+
+	// From is the sender address.
+	From string `yaml:"from,omitempty"`
+}
+
+// DashboardConfig configures the built-in web dashboard served at /ui/.
+// The dashboard's static files are served without authentication; all data
+// it displays is read through the authenticated /v1 API.
+type DashboardConfig struct {
+	// Enabled serves the dashboard.
+	Enabled bool `yaml:"enabled"`
+}
+
+// QueueConfig configures scheduling of runs waiting for an execution slot.
+// Runs start in priority class order (interactive, trigger, batch).
+type QueueConfig struct {
+	// MaxDepth limits the number of waiting runs. New runs are rejected with
+	// HTTP 429 once it is reached. Zero means unlimited.
+	MaxDepth int `yaml:"max_depth,omitempty"`
+
+	// FairShare shares slots between groups of runs within a priority class:
+	// "workspace", "workflow", or empty for first come, first served.
+	FairShare string `yaml:"fair_share,omitempty"`
+
+	// RetryAfter is the delay suggested to clients rejected by a full queue.
+	// Default: 30s
+	RetryAfter time.Duration `yaml:"retry_after,omitempty"`
 }
 
 // ControllerListenConfig configures how the controller listens for connections.
@@ -210,6 +297,61 @@ type ControllerAuthConfig struct {
 
 	// AllowUnixSocket allows unauthenticated access via Unix socket.
 	AllowUnixSocket bool `yaml:"allow_unix_socket"`
+
+	// RBAC configures role-based access control for authenticated users.
+	RBAC RBACConfig `yaml:"rbac,omitempty"`
+
+	// OIDC accepts tokens from an OpenID Connect provider, such as those
+	// stored by `conductor login`.
+	OIDC OIDCConfig `yaml:"oidc,omitempty"`
+}
+
+// OIDCConfig configures login with an OpenID Connect provider.
+type OIDCConfig struct {
+	// Issuer is the provider's issuer URL. OIDC is enabled when it is set.
+	Issuer string `yaml:"issuer,omitempty"`
+
+	// ClientID is the client registered for Conductor. It must be allowed
+	// to use the device authorization grant for `conductor login`.
+	ClientID string `yaml:"client_id,omitempty"`
+
+	// Audiences are further accepted token audiences, for access tokens.
+	Audiences []string `yaml:"audiences,omitempty"`
+
+	// Scopes are requested by `conductor login`.
+	// Default: openid, profile, email, offline_access
+	Scopes []string `yaml:"scopes,omitempty"`
+
+	// UsernameClaim is the claim used as the user ID. Default: sub
+	UsernameClaim string `yaml:"username_claim,omitempty"`
+
+	// GroupsClaim is the claim holding the user's groups. Default: groups
+	GroupsClaim string `yaml:"groups_claim,omitempty"`
+
+	// GroupRoles maps groups to RBAC roles.
+	GroupRoles map[string][]string `yaml:"group_roles,omitempty"`
+
+	// GroupScopes maps groups to endpoint scopes.
+	GroupScopes map[string][]string `yaml:"group_scopes,omitempty"`
+
+	// CacheTTL is how long the discovery document and signing keys are
+	// cached. Default: 1h
+	CacheTTL time.Duration `yaml:"cache_ttl,omitempty"`
+}
+
+// RBACConfig configures role-based access control.
+type RBACConfig struct {
+	// Enabled enforces roles on every API request. When disabled, any
+	// authenticated user has full access.
+	Enabled bool `yaml:"enabled"`
+
+	// DefaultRole is granted to users without any role binding.
+	// Empty means such users are denied.
+	DefaultRole string `yaml:"default_role,omitempty"`
+
+	// Bindings maps subjects (user IDs, or API key names such as "key-1")
+	// to role names, in addition to bindings stored in the backend.
+	Bindings map[string][]string `yaml:"bindings,omitempty"`
 }
 
 // BackendConfig configures the storage backend.
@@ -261,7 +403,7 @@ type DistributedConfig struct {
 	// If empty, a random ID is generated.
 	InstanceID string `yaml:"instance_id,omitempty"`
 
-	// LeaderElection enables leader election for scheduler.
+	// LeaderElection enables leader election for the scheduler and queue triggers.
 	LeaderElection bool `yaml:"leader_election"`
 
 	// StalledJobTimeoutSeconds is how long before a locked job is considered stalled.
@@ -272,6 +414,46 @@ type DistributedConfig struct {
 type WebhooksConfig struct {
 	// Routes defines webhook routes.
 	Routes []WebhookRoute `yaml:"routes,omitempty"`
+
+	// Sources defines webhook sources signed with an HMAC, by name. Routes
+	// and workflow webhook triggers use them as their source.
+	Sources map[string]WebhookSourceConfig `yaml:"sources,omitempty"`
+}
+
+// WebhookSourceConfig defines how a webhook source signs its requests.
+type WebhookSourceConfig struct {
+	// SignatureHeader is the header holding the signature.
+	SignatureHeader string `yaml:"signature_header"`
+
+	// Algorithm is the HMAC hash: sha1, sha256 or sha512. Default: sha256
+	Algorithm string `yaml:"algorithm,omitempty"`
+
+	// Encoding is how the signature is written: hex or base64. Default: hex
+	Encoding string `yaml:"encoding,omitempty"`
+
+	// Prefix is removed from the signature header, e.g. "sha256=".
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// SignedContent is a template for the signed bytes, using .Body,
+	// .Timestamp, .Method, .URL, .Path and {{header "Name"}}.
+	// Default: {{.Body}}
+	SignedContent string `yaml:"signed_content,omitempty"`
+
+	// TimestampHeader is the header holding the signing time. When set,
+	// old requests and replays are rejected.
+	TimestampHeader string `yaml:"timestamp_header,omitempty"`
+
+	// TimestampFormat is unix, unix_ms or rfc3339. Default: unix
+	TimestampFormat string `yaml:"timestamp_format,omitempty"`
+
+	// Tolerance is how old the timestamp may be. Default: 5m
+	Tolerance time.Duration `yaml:"tolerance,omitempty"`
+
+	// EventHeader is the header holding the event type.
+	EventHeader string `yaml:"event_header,omitempty"`
+
+	// EventField is a dotted payload path holding the event type.
+	EventField string `yaml:"event_field,omitempty"`
 }
 
 // WebhookRoute defines a webhook route mapping.
@@ -279,7 +461,9 @@ type WebhookRoute struct {
 	// Path is the URL path (e.g., "/webhooks/github").
 	Path string `yaml:"path"`
 
-	// Source is the webhook source type (github, slack, generic).
+	// Source is the webhook source type (github, gitlab, slack, stripe,
+	// linear, pagerduty, jira, sentry, twilio, generic, or a configured
+	// source).
 	Source string `yaml:"source"`
 
 	// Workflow is the workflow to trigger.
@@ -291,6 +475,10 @@ type WebhookRoute struct {
 	// Secret is used for signature verification.
 	Secret string `yaml:"secret,omitempty"`
 
+	// Filter is a condition over the request that must hold to trigger
+	// the workflow: an expr-lang expression, or an object with jq.
+	Filter *workflow.WebhookFilter `yaml:"filter,omitempty"`
+
 	// InputMapping defines how to map payload to inputs.
 	InputMapping map[string]string `yaml:"input_mapping,omitempty"`
 }
@@ -302,6 +490,26 @@ type SchedulesConfig struct {
 
 	// Schedules defines the scheduled workflows.
 	Schedules []ScheduleEntry `yaml:"schedules,omitempty"`
+
+	// Calendars are named exclusion calendars, such as holidays or change
+	// freezes. Schedules that exclude a calendar do not run in it.
+	Calendars map[string]ScheduleCalendar `yaml:"calendars,omitempty"`
+}
+
+// ScheduleCalendar is a named set of days and periods schedules can exclude.
+type ScheduleCalendar struct {
+	// Dates are whole days (2006-01-02) in each schedule's timezone.
+	Dates []string `yaml:"dates,omitempty"`
+
+	// Ranges are periods from From up to (not including) To.
+	Ranges []ScheduleCalendarRange `yaml:"ranges,omitempty"`
+}
+
+// ScheduleCalendarRange is a period of a calendar. Times without an offset
+// are in each schedule's timezone.
+type ScheduleCalendarRange struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
 }
 
 // ScheduleEntry defines a scheduled workflow.
@@ -309,8 +517,13 @@ type ScheduleEntry struct {
 	// Name is the unique schedule identifier.
 	Name string `yaml:"name"`
 
-	// Cron is the cron expression.
-	Cron string `yaml:"cron"`
+	// Cron is the cron expression: 5 fields, 6 with seconds, or an
+	// @every interval.
+	Cron string `yaml:"cron,omitempty"`
+
+	// At is the time of a one-off schedule (2026-11-01T09:00), used
+	// instead of Cron.
+	At string `yaml:"at,omitempty"`
 
 	// Workflow is the workflow to run.
 	Workflow string `yaml:"workflow"`
@@ -323,6 +536,37 @@ type ScheduleEntry struct {
 
 	// Timezone for cron evaluation.
 	Timezone string `yaml:"timezone,omitempty"`
+
+	// Catchup is what happens to runs missed while the controller was down:
+	// none (default), last or all.
+	Catchup string `yaml:"catchup,omitempty"`
+
+	// CatchupWindow bounds how far back missed runs are caught up.
+	// Default: 24h
+	CatchupWindow time.Duration `yaml:"catchup_window,omitempty"`
+
+	// Overlap is what happens when the schedule is due while its previous
+	// run is still going: allow (default), skip or queue.
+	Overlap string `yaml:"overlap,omitempty"`
+
+	// Jitter delays each run by a random duration up to this long.
+	Jitter time.Duration `yaml:"jitter,omitempty"`
+
+	// Exclude names the calendars the schedule does not run in.
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// MCPServerConfig configures the MCP server mounted on the controller API.
+type MCPServerConfig struct {
+	// Enabled mounts the MCP server.
+	Enabled bool `yaml:"enabled"`
+
+	// Path is the HTTP path of the MCP endpoint (default: /mcp).
+	Path string `yaml:"path,omitempty"`
+
+	// Expose selects what becomes a tool: "workflows" for every workflow in
+	// workflows_dir (default), or "endpoints" for every named endpoint.
+	Expose string `yaml:"expose,omitempty"`
 }
 
 // EndpointsConfig configures named API endpoints.
@@ -1032,6 +1276,106 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate OIDC configuration
+	if oidc := c.Controller.ControllerAuth.OIDC; oidc.Issuer != "" {
+		if !strings.HasPrefix(oidc.Issuer, "https://") && !strings.HasPrefix(oidc.Issuer, "http://") {
+			errs = append(errs, fmt.Sprintf("controller.controller_auth.oidc.issuer must be an http(s) URL, got %q", oidc.Issuer))
+		}
+		if oidc.ClientID == "" {
+			errs = append(errs, "controller.controller_auth.oidc.client_id is required when oidc.issuer is set")
+		}
+	}
+
+	// Validate queue configuration
+	switch c.Controller.Queue.FairShare {
+	case "", "workspace", "workflow":
+	default:
+		errs = append(errs, fmt.Sprintf("controller.queue.fair_share must be workspace or workflow, got %q", c.Controller.Queue.FairShare))
+	}
+	if c.Controller.Queue.MaxDepth < 0 {
+		errs = append(errs, fmt.Sprintf("controller.queue.max_depth must be non-negative, got %d", c.Controller.Queue.MaxDepth))
+	}
+
+	// Validate schedule policies
+	for i, sched := range c.Controller.Schedules.Schedules {
+		switch sched.Catchup {
+		case "", "none", "last", "all":
+		default:
+			errs = append(errs, fmt.Sprintf("controller.schedules.schedules[%d].catchup must be none, last or all, got %q", i, sched.Catchup))
+		}
+		switch sched.Overlap {
+		case "", "allow", "skip", "queue":
+		default:
+			errs = append(errs, fmt.Sprintf("controller.schedules.schedules[%d].overlap must be allow, skip or queue, got %q", i, sched.Overlap))
+		}
+		if sched.CatchupWindow < 0 || sched.Jitter < 0 {
+			errs = append(errs, fmt.Sprintf("controller.schedules.schedules[%d]: catchup_window and jitter must be non-negative", i))
+		}
+		if sched.Cron != "" && sched.At != "" {
+			errs = append(errs, fmt.Sprintf("controller.schedules.schedules[%d]: cron and at cannot both be set", i))
+		}
+		for _, name := range sched.Exclude {
+			if _, ok := c.Controller.Schedules.Calendars[name]; !ok {
+				errs = append(errs, fmt.Sprintf("controller.schedules.schedules[%d].exclude: unknown calendar %q", i, name))
+			}
+		}
+	}
+
+	// Validate webhook sources
+	for name, source := range c.Controller.Webhooks.Sources {
+		field := "controller.webhooks.sources." + name
+		if source.SignatureHeader == "" {
+			errs = append(errs, field+".signature_header is required")
+		}
+		switch source.Algorithm {
+		case "", "sha1", "sha256", "sha512":
+		default:
+			errs = append(errs, fmt.Sprintf("%s.algorithm must be sha1, sha256 or sha512, got %q", field, source.Algorithm))
+		}
+		switch source.Encoding {
+		case "", "hex", "base64":
+		default:
+			errs = append(errs, fmt.Sprintf("%s.encoding must be hex or base64, got %q", field, source.Encoding))
+		}
+		switch source.TimestampFormat {
+		case "", "unix", "unix_ms", "rfc3339":
+		default:
+			errs = append(errs, fmt.Sprintf("%s.timestamp_format must be unix, unix_ms or rfc3339, got %q", field, source.TimestampFormat))
+		}
+		if source.Tolerance < 0 {
+			errs = append(errs, fmt.Sprintf("%s.tolerance must be non-negative, got %s", field, source.Tolerance))
+		}
+	}
+
+	// Validate webhook route filters and input mappings
+	for i, route := range c.Controller.Webhooks.Routes {
+		hook := workflow.WebhookTrigger{Filter: route.Filter, InputMapping: route.InputMapping}
+		if err := hook.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("controller.webhooks.routes[%d]: %v", i, err))
+		}
+	}
+
+	// Validate notification configuration
+	notifications := c.Controller.Notifications
+	if err := notifications.Defaults.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("controller.notifications.defaults: %v", err))
+	}
+	if notifications.MaxAttempts < 0 {
+		errs = append(errs, fmt.Sprintf("controller.notifications.max_attempts must be non-negative, got %d", notifications.MaxAttempts))
+	}
+	for _, ch := range notifications.Defaults.Channels {
+		if ch.Type == workflow.NotifyChannelEmail && (notifications.SMTP.Host == "" || notifications.SMTP.From == "") {
+			errs = append(errs, "controller.notifications.smtp: host and from are required for email channels")
+			break
+		}
+	}
+	if c.Controller.IdempotencyKeyRetention < 0 {
+		errs = append(errs, fmt.Sprintf("controller.idempotency_key_retention must be non-negative, got %s", c.Controller.IdempotencyKeyRetention))
+	}
+	if c.Controller.TriggerEvents.Retention < 0 {
+		errs = append(errs, fmt.Sprintf("controller.trigger_events.retention must be non-negative, got %s", c.Controller.TriggerEvents.Retention))
+	}
+
 	// Validate endpoints configuration
 	if c.Controller.Endpoints.Enabled {
 		endpointNames := make(map[string]bool)
@@ -1065,6 +1409,25 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate MCP server configuration
+	if c.Controller.MCPServer.Enabled {
+		switch c.Controller.MCPServer.Expose {
+		case "", "workflows":
+			if c.Controller.WorkflowsDir == "" {
+				errs = append(errs, "controller.mcp_server: workflows_dir is required to expose workflows")
+			}
+		case "endpoints":
+			if !c.Controller.Endpoints.Enabled {
+				errs = append(errs, "controller.mcp_server: endpoints must be enabled to expose endpoints")
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("controller.mcp_server.expose must be workflows or endpoints, got %q", c.Controller.MCPServer.Expose))
+		}
+		if p := c.Controller.MCPServer.Path; p != "" && !strings.HasPrefix(p, "/") {
+			errs = append(errs, fmt.Sprintf("controller.mcp_server.path must start with /, got %q", p))
+		}
+	}
+
 	// Validate retention days (must be positive when observability is enabled)
 	if c.Controller.Observability.Enabled {
 		ret := c.Controller.Observability.Storage.Retention
@@ -107,6 +107,24 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "mcp server exposing endpoints requires endpoints",
+			modify: func(c *Config) {
+				c.Controller.MCPServer.Enabled = true
+				c.Controller.MCPServer.Expose = "endpoints"
+			},
+			wantErr: true,
+			errText: "endpoints must be enabled to expose endpoints",
+		},
+		{
+			name: "invalid mcp server expose",
+			modify: func(c *Config) {
+				c.Controller.MCPServer.Enabled = true
+				c.Controller.MCPServer.Expose = "everything"
+			},
+			wantErr: true,
+			errText: "controller.mcp_server.expose must be workflows or endpoints",
+		},
 	}
 
 	for _, tt := range tests {
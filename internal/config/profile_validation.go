@@ -264,10 +264,13 @@ func validateIntegrationBinding(path string, binding profile.IntegrationBinding)
 
 // validateMCPServerBinding validates an MCP server binding.
 func validateMCPServerBinding(path string, binding profile.MCPServerBinding) error {
-	// Command is required
-	if binding.Command == "" {
+	// Command is required for local servers, URL for remote servers
+	if binding.Command == "" && binding.URL == "" {
 		return fmt.Errorf("%s: command is required", path)
 	}
+	if binding.Command != "" && binding.URL != "" {
+		return fmt.Errorf("%s: command and url are mutually exclusive", path)
+	}
 
 	// Timeout should be positive if specified
 	if binding.Timeout < 0 {
@@ -71,15 +71,13 @@ func ValidatePublicAPIRequirements(cfg *Config) error {
 		}
 
 		// Check if workflow has public API listeners
-		if def.Trigger != nil {
-			if def.Trigger.Webhook != nil {
-				workflowsRequiringPublicAPI = append(workflowsRequiringPublicAPI,
-					fmt.Sprintf("%s (has listen.webhook)", def.Name))
-			}
-			if def.Trigger.API != nil {
-				workflowsRequiringPublicAPI = append(workflowsRequiringPublicAPI,
-					fmt.Sprintf("%s (has listen.api)", def.Name))
-			}
+		if len(def.TriggersOfType(workflow.TriggerTypeWebhook)) > 0 {
+			workflowsRequiringPublicAPI = append(workflowsRequiringPublicAPI,
+				fmt.Sprintf("%s (has listen.webhook)", def.Name))
+		}
+		if len(def.TriggersOfType(workflow.TriggerTypeAPI)) > 0 {
+			workflowsRequiringPublicAPI = append(workflowsRequiringPublicAPI,
+				fmt.Sprintf("%s (has listen.api)", def.Name))
 		}
 
 		return nil
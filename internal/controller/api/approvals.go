@@ -0,0 +1,109 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/pkg/tools/approval"
+)
+
+// ApprovalsHandler handles approval requests waiting for a decision, such as
+// MCP sampling requests from servers that require approval.
+type ApprovalsHandler struct {
+	queue *approval.Queue
+	runs  RunLookup
+}
+
+// NewApprovalsHandler creates a new approvals handler.
+func NewApprovalsHandler(q *approval.Queue) *ApprovalsHandler {
+	return &ApprovalsHandler{queue: q}
+}
+
+// SetRuns sets the run lookup used to authorize requests against the
+// workflow of the run that made them.
+func (h *ApprovalsHandler) SetRuns(runs RunLookup) {
+	h.runs = runs
+}
+
+// RegisterRoutes registers approval API routes on the router.
+func (h *ApprovalsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/approvals", h.handleList)
+	mux.HandleFunc("POST /v1/approvals/{id}/approve", h.handleApprove)
+	mux.HandleFunc("POST /v1/approvals/{id}/deny", h.handleDeny)
+}
+
+// handleList returns the pending approval requests the caller may read.
+func (h *ApprovalsHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	pending := make([]approval.Request, 0)
+	for _, req := range h.queue.Pending() {
+		if runAllowed(r, h.runs, auth.ActionRead, requestRunID(req)) {
+			pending = append(pending, req)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"approvals": pending,
+		"count":     len(pending),
+	})
+}
+
+// handleApprove approves a pending request.
+func (h *ApprovalsHandler) handleApprove(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, r.PathValue("id"), true)
+}
+
+// handleDeny denies a pending request.
+func (h *ApprovalsHandler) handleDeny(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, r.PathValue("id"), false)
+}
+
+func (h *ApprovalsHandler) decide(w http.ResponseWriter, r *http.Request, id string, approved bool) {
+	for _, req := range h.queue.Pending() {
+		if req.ID != id {
+			continue
+		}
+		if !runAllowed(r, h.runs, auth.ActionRead, requestRunID(req)) {
+			// Requests the caller cannot see are reported as missing
+			writeError(w, http.StatusNotFound, approval.ErrRequestNotFound.Error())
+			return
+		}
+		if !authorizeRunLookup(w, r, h.runs, auth.ActionApprove, requestRunID(req)) {
+			return
+		}
+	}
+
+	if err := h.queue.Decide(id, approved); err != nil {
+		if errors.Is(err, approval.ErrRequestNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status := "denied"
+	if approved {
+		status = "approved"
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": status})
+}
+
+// requestRunID returns the run that made an approval request, or "".
+func requestRunID(req approval.Request) string {
+	runID, _ := req.Inputs["run_id"].(string)
+	return runID
+}
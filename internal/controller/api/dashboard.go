@@ -0,0 +1,57 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// DashboardPath is the URL path prefix of the web dashboard.
+const DashboardPath = "/ui/"
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// DashboardHandler serves the embedded web dashboard. The dashboard is
+// static HTML and JavaScript; all data is read through the /v1 API with the
+// caller's credentials.
+type DashboardHandler struct {
+	files http.Handler
+}
+
+// NewDashboardHandler creates a new dashboard handler.
+func NewDashboardHandler() *DashboardHandler {
+	// Sub cannot fail for a directory embedded at build time
+	files, _ := fs.Sub(dashboardFiles, "dashboard")
+	return &DashboardHandler{
+		files: http.StripPrefix(DashboardPath, http.FileServer(http.FS(files))),
+	}
+}
+
+// RegisterRoutes registers dashboard routes on the router.
+func (h *DashboardHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /ui", http.RedirectHandler(DashboardPath, http.StatusMovedPermanently))
+	mux.HandleFunc("GET "+DashboardPath, h.handleFiles)
+}
+
+// handleFiles serves dashboard assets.
+func (h *DashboardHandler) handleFiles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	h.files.ServeHTTP(w, r)
+}
@@ -0,0 +1,544 @@
+// Conductor dashboard. Reads everything through the /v1 API; when the
+// controller requires authentication, the API key entered by the user is
+// kept in sessionStorage and sent in the X-API-Key header.
+'use strict';
+
+const state = {
+  tab: 'runs',
+  runs: [],
+  selectedRun: null,
+  run: null,
+  steps: {},
+  selectedTrace: null,
+};
+
+// --- API -------------------------------------------------------------------
+
+class AuthError extends Error {}
+
+function authHeaders() {
+  const key = sessionStorage.getItem('conductor.apiKey');
+  return key ? { 'X-API-Key': key } : {};
+}
+
+async function api(method, path, body) {
+  const opts = { method, headers: authHeaders() };
+  if (body !== undefined) {
+    opts.headers['Content-Type'] = 'application/json';
+    opts.body = JSON.stringify(body);
+  }
+  const resp = await fetch(path, opts);
+  if (resp.status === 401) {
+    showLogin();
+    throw new AuthError('authentication required');
+  }
+  const text = await resp.text();
+  let data = null;
+  try {
+    data = text ? JSON.parse(text) : null;
+  } catch {
+    data = { error: text.trim() };
+  }
+  if (!resp.ok) {
+    throw new Error((data && data.error) || `${resp.status} ${resp.statusText}`);
+  }
+  return data;
+}
+
+function showLogin() {
+  document.getElementById('login').hidden = false;
+  document.querySelector('main').hidden = true;
+}
+
+document.getElementById('login').addEventListener('submit', (ev) => {
+  ev.preventDefault();
+  sessionStorage.setItem('conductor.apiKey', document.getElementById('api-key').value);
+  document.getElementById('login').hidden = true;
+  document.querySelector('main').hidden = false;
+  refresh();
+  streamEvents();
+});
+
+// --- Helpers ---------------------------------------------------------------
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k === 'class') node.className = v;
+    else if (k.startsWith('on')) node.addEventListener(k.slice(2), v);
+    else node.setAttribute(k, v);
+  }
+  for (const child of children) {
+    if (child === null || child === undefined) continue;
+    node.append(child instanceof Node ? child : String(child));
+  }
+  return node;
+}
+
+function fill(tbody, rows, empty, columns) {
+  tbody.replaceChildren(...(rows.length ? rows : [el('tr', {}, el('td', { colspan: columns, class: 'muted' }, empty))]));
+}
+
+function fmtTime(ts) {
+  if (!ts || ts.startsWith('0001-')) return '';
+  return new Date(ts).toLocaleString();
+}
+
+function fmtDuration(ms) {
+  if (ms === undefined || ms === null) return '';
+  if (ms < 1000) return `${ms}ms`;
+  if (ms < 60000) return `${(ms / 1000).toFixed(1)}s`;
+  return `${Math.floor(ms / 60000)}m${Math.round((ms % 60000) / 1000)}s`;
+}
+
+function fmtCost(usd) {
+  return usd ? `$${usd.toFixed(4)}` : '';
+}
+
+function json(value) {
+  return el('pre', {}, JSON.stringify(value, null, 2));
+}
+
+function showError(err) {
+  if (err instanceof AuthError) return;
+  const node = document.getElementById('error');
+  node.textContent = err.message;
+  node.hidden = false;
+  setTimeout(() => { node.hidden = true; }, 5000);
+}
+
+function debounce(fn, ms) {
+  let timer;
+  return () => {
+    clearTimeout(timer);
+    timer = setTimeout(fn, ms);
+  };
+}
+
+// --- Tabs ------------------------------------------------------------------
+
+for (const button of document.querySelectorAll('nav button')) {
+  button.addEventListener('click', () => {
+    state.tab = button.dataset.tab;
+    for (const b of document.querySelectorAll('nav button')) b.classList.toggle('active', b === button);
+    for (const section of document.querySelectorAll('main section')) {
+      section.hidden = section.id !== `tab-${state.tab}`;
+    }
+    refresh();
+  });
+}
+
+function refresh() {
+  const loaders = {
+    runs: loadRuns,
+    schedules: loadSchedules,
+    approvals: loadApprovals,
+    mcp: loadMCP,
+    traces: loadTraces,
+  };
+  loaders[state.tab]().catch(showError);
+  if (state.tab !== 'approvals') loadApprovals().catch(showError);
+}
+
+// --- Runs ------------------------------------------------------------------
+
+async function loadRuns() {
+  const params = new URLSearchParams();
+  const status = document.getElementById('filter-status').value;
+  const workflow = document.getElementById('filter-workflow').value.trim();
+  if (status) params.set('status', status);
+  if (workflow) params.set('workflow', workflow);
+  const data = await api('GET', `/v1/runs?${params}`);
+  state.runs = (data.runs || []).sort((a, b) => b.created_at.localeCompare(a.created_at));
+  renderRuns();
+}
+
+function renderRuns() {
+  const rows = state.runs.map((run) => {
+    const progress = run.progress ? `${run.progress.completed}/${run.progress.total}` : '';
+    const row = el('tr', { class: 'selectable', onclick: () => selectRun(run.id) },
+      el('td', {}, el('code', {}, run.id.slice(0, 8))),
+      el('td', {}, run.workflow),
+      el('td', { class: `status-${run.status}` }, run.status,
+        run.queue_position ? el('span', { class: 'muted' }, ` #${run.queue_position}`) : null),
+      el('td', {}, progress),
+      el('td', {}, fmtTime(run.created_at)));
+    if (run.id === state.selectedRun) row.classList.add('selected');
+    return row;
+  });
+  fill(document.querySelector('#runs tbody'), rows, 'No runs', 5);
+}
+
+async function selectRun(id) {
+  state.selectedRun = id;
+  state.steps = {};
+  renderRuns();
+  await loadRun();
+  loadRunTrace().catch(showError);
+}
+
+async function loadRun() {
+  if (!state.selectedRun) return;
+  const id = state.selectedRun;
+  state.run = await api('GET', `/v1/runs/${id}`);
+  try {
+    const data = await api('GET', `/v1/runs/${id}/steps`);
+    for (const step of data.steps || []) state.steps[step.step_id] = step;
+  } catch (err) {
+    // Step results are optional for backends without step storage
+  }
+  renderRun();
+}
+
+function runAction(label, method, path, body, cls) {
+  return el('button', {
+    class: cls || '',
+    onclick: async () => {
+      try {
+        await api(method, path, body);
+        await loadRun();
+        await loadRuns();
+      } catch (err) {
+        showError(err);
+      }
+    },
+  }, label);
+}
+
+function renderRun() {
+  const run = state.run;
+  document.getElementById('run-detail').hidden = false;
+  document.getElementById('run-title').replaceChildren(run.workflow, ' ', el('code', {}, run.id));
+
+  const meta = [
+    el('span', { class: `status-${run.status}` }, run.status),
+    run.priority ? ` · ${run.priority}` : '',
+    run.started_at ? ` · started ${fmtTime(run.started_at)}` : '',
+    run.completed_at ? ` · finished ${fmtTime(run.completed_at)}` : '',
+  ];
+  const metaNode = document.getElementById('run-meta');
+  metaNode.replaceChildren(...meta);
+  if (run.error) metaNode.append(el('div', { class: 'error' }, run.error));
+
+  const actions = [];
+  const base = `/v1/runs/${run.id}`;
+  if (run.status === 'running' || run.status === 'pending') actions.push(runAction('Pause', 'POST', `${base}/pause`));
+  if (run.status === 'paused') actions.push(runAction('Resume', 'POST', `${base}/resume`));
+  if (['running', 'pending', 'paused'].includes(run.status)) actions.push(runAction('Cancel', 'DELETE', base, undefined, 'danger'));
+  document.getElementById('run-actions').replaceChildren(...actions);
+
+  renderTimeline();
+}
+
+// timelineSteps folds the run's step events into one entry per step.
+function timelineSteps(run) {
+  const steps = new Map();
+  for (const entry of run.logs || []) {
+    if (entry.type === 'step_start') {
+      steps.set(entry.step_id, {
+        id: entry.step_id,
+        name: entry.step_name || entry.step_id,
+        status: 'running',
+        started: entry.timestamp,
+      });
+    } else if (entry.type === 'step_complete') {
+      const step = steps.get(entry.step_id) || { id: entry.step_id, name: entry.step_name || entry.step_id };
+      Object.assign(step, {
+        status: entry.status,
+        duration: entry.duration_ms,
+        cost: entry.cost_usd,
+        tokensIn: entry.tokens_in,
+        tokensOut: entry.tokens_out,
+        output: entry.output,
+        error: entry.error,
+      });
+      steps.set(entry.step_id, step);
+    }
+  }
+  return [...steps.values()];
+}
+
+function renderTimeline() {
+  const run = state.run;
+  const items = timelineSteps(run).map((step) => {
+    const stored = state.steps[step.id] || {};
+    const stats = [
+      fmtDuration(step.duration),
+      fmtCost(step.cost),
+      step.tokensIn || step.tokensOut ? `${step.tokensIn || 0} in / ${step.tokensOut || 0} out tokens` : '',
+    ].filter(Boolean).join(' · ');
+
+    const item = el('li', { class: `status-${step.status}` },
+      el('strong', {}, step.name), ' ',
+      el('span', { class: `status-${step.status}` }, step.status), ' ',
+      el('span', { class: 'muted' }, stats));
+    if (step.error) item.append(el('div', { class: 'error' }, step.error));
+    if (stored.inputs) item.append(el('details', {}, el('summary', {}, 'Inputs'), json(stored.inputs)));
+    const output = step.output || stored.outputs;
+    if (output) item.append(el('details', {}, el('summary', {}, 'Output'), json(output)));
+
+    // The failed step can be retried in place with edited inputs
+    if (run.status === 'failed' && run.failed_step === step.id) {
+      const editor = el('textarea', {}, JSON.stringify(stored.inputs || {}, null, 2));
+      const retry = el('button', {
+        onclick: async () => {
+          let inputs;
+          try {
+            inputs = JSON.parse(editor.value || '{}');
+          } catch (err) {
+            showError(new Error(`Inputs must be JSON: ${err.message}`));
+            return;
+          }
+          try {
+            await api('POST', `/v1/runs/${run.id}/steps/${encodeURIComponent(step.id)}/retry`, { inputs });
+            await loadRun();
+            await loadRuns();
+          } catch (err) {
+            showError(err);
+          }
+        },
+      }, 'Retry step');
+      item.append(el('details', {}, el('summary', {}, 'Retry with edited inputs'), editor, retry));
+    }
+    return item;
+  });
+  const timeline = document.getElementById('timeline');
+  if (items.length) timeline.replaceChildren(...items);
+  else timeline.replaceChildren(el('li', { class: 'muted' }, 'No steps have started'));
+}
+
+async function loadRunTrace() {
+  const node = document.getElementById('run-trace');
+  let data;
+  try {
+    data = await api('GET', `/v1/runs/${state.selectedRun}/trace`);
+  } catch (err) {
+    if (err instanceof AuthError) throw err;
+    node.replaceChildren(el('p', { class: 'muted' }, 'No trace recorded for this run.'));
+    return;
+  }
+  renderWaterfall(node, (data && data.spans) || []);
+}
+
+document.getElementById('filter-status').addEventListener('change', () => loadRuns().catch(showError));
+document.getElementById('filter-workflow').addEventListener('input', debounce(() => loadRuns().catch(showError), 300));
+document.getElementById('refresh-runs').addEventListener('click', () => loadRuns().catch(showError));
+
+// --- Traces ----------------------------------------------------------------
+
+function renderWaterfall(node, spans) {
+  if (!spans.length) {
+    node.replaceChildren(el('p', { class: 'muted' }, 'No spans recorded.'));
+    return;
+  }
+  const time = (ts) => (ts && !ts.startsWith('0001-') ? Date.parse(ts) : Date.now());
+  const start = Math.min(...spans.map((s) => time(s.StartTime)));
+  const end = Math.max(...spans.map((s) => time(s.EndTime)));
+  const total = Math.max(end - start, 1);
+
+  const depth = new Map();
+  const byID = new Map(spans.map((s) => [s.SpanID, s]));
+  const depthOf = (span) => {
+    if (depth.has(span.SpanID)) return depth.get(span.SpanID);
+    const parent = byID.get(span.ParentID);
+    const d = parent && parent !== span ? depthOf(parent) + 1 : 0;
+    depth.set(span.SpanID, d);
+    return d;
+  };
+
+  const rows = [...spans]
+    .sort((a, b) => time(a.StartTime) - time(b.StartTime))
+    .map((span) => {
+      const s = time(span.StartTime);
+      const e = time(span.EndTime);
+      const bar = el('div', { class: span.Status && span.Status.Code === 2 ? 'bar err' : 'bar', title: span.Status && span.Status.Message || '' });
+      bar.style.left = `${((s - start) / total) * 100}%`;
+      bar.style.width = `${((e - s) / total) * 100}%`;
+      const name = el('span', { class: 'name', title: span.Name }, span.Name);
+      name.style.paddingLeft = `${depthOf(span)}rem`;
+      return el('div', { class: 'span' }, name, el('div', { class: 'track' }, bar), el('span', { class: 'dur' }, fmtDuration(e - s)));
+    });
+  node.replaceChildren(...rows);
+}
+
+async function loadTraces() {
+  const list = document.getElementById('traces');
+  const data = await api('GET', '/v1/traces?limit=50');
+  if (!data || !Array.isArray(data.traces)) {
+    list.replaceChildren(el('li', { class: 'muted' }, 'Tracing is not enabled on this controller.'));
+    return;
+  }
+  const items = data.traces.map((id) => {
+    const item = el('li', { onclick: () => selectTrace(id) }, id);
+    if (id === state.selectedTrace) item.classList.add('selected');
+    return item;
+  });
+  list.replaceChildren(...(items.length ? items : [el('li', { class: 'muted' }, 'No traces')]));
+}
+
+async function selectTrace(id) {
+  state.selectedTrace = id;
+  await loadTraces();
+  const data = await api('GET', `/v1/traces/${id}/spans`);
+  renderWaterfall(document.getElementById('trace-detail'), (data && data.spans) || []);
+}
+
+// --- Schedules -------------------------------------------------------------
+
+async function loadSchedules() {
+  const data = await api('GET', '/v1/schedules');
+  const rows = (data.schedules || []).map((s) => {
+    const toggle = el('input', { type: 'checkbox' });
+    toggle.checked = s.enabled;
+    toggle.addEventListener('change', async () => {
+      try {
+        await api('POST', `/v1/schedules/${encodeURIComponent(s.name)}/${toggle.checked ? 'enable' : 'disable'}`);
+      } catch (err) {
+        toggle.checked = !toggle.checked;
+        showError(err);
+      }
+    });
+    return el('tr', {},
+      el('td', {}, s.name),
+      el('td', {}, s.workflow),
+      el('td', {}, el('code', {}, s.cron)),
+      el('td', {}, fmtTime(s.next_run)),
+      el('td', {}, s.run_count),
+      el('td', {}, s.error_count),
+      el('td', {}, toggle));
+  });
+  fill(document.querySelector('#schedules tbody'), rows, 'No schedules', 7);
+}
+
+// --- Approvals -------------------------------------------------------------
+
+async function loadApprovals() {
+  let data;
+  try {
+    data = await api('GET', '/v1/approvals');
+  } catch (err) {
+    if (err instanceof AuthError) throw err;
+    data = null;
+  }
+  const approvals = (data && data.approvals) || [];
+
+  const badge = document.getElementById('approval-count');
+  badge.textContent = approvals.length;
+  badge.hidden = approvals.length === 0;
+
+  const decide = (id, action) => async () => {
+    try {
+      await api('POST', `/v1/approvals/${id}/${action}`);
+    } catch (err) {
+      showError(err);
+    }
+    loadApprovals().catch(showError);
+  };
+  const rows = approvals.map((a) => el('tr', {},
+    el('td', {}, fmtTime(a.created_at)),
+    el('td', {}, a.tool_name),
+    el('td', {}, a.description),
+    el('td', {}, a.inputs ? json(a.inputs) : ''),
+    el('td', { class: 'actions' },
+      el('button', { onclick: decide(a.id, 'approve') }, 'Approve'),
+      el('button', { class: 'danger', onclick: decide(a.id, 'deny') }, 'Deny'))));
+  fill(document.querySelector('#approvals tbody'), rows, 'No pending approvals', 5);
+}
+
+// --- MCP servers -----------------------------------------------------------
+
+async function loadMCP() {
+  const data = await api('GET', '/v1/mcp/servers');
+  if (!data || !Array.isArray(data.servers)) {
+    fill(document.querySelector('#mcp tbody'), [], 'MCP servers are not available on this controller', 6);
+    return;
+  }
+  const rows = data.servers.map((s) => el('tr', {},
+    el('td', {}, s.name),
+    el('td', { class: s.status === 'running' ? 'status-completed' : s.status === 'error' ? 'status-failed' : 'muted' }, s.status),
+    el('td', {}, s.tool_count === null || s.tool_count === undefined ? '' : s.tool_count),
+    el('td', {}, s.uptime_seconds ? fmtDuration(s.uptime_seconds * 1000) : ''),
+    el('td', {}, s.failure_count),
+    el('td', { class: 'error' }, s.last_error || '')));
+  fill(document.querySelector('#mcp tbody'), rows, 'No MCP servers registered', 6);
+}
+
+// --- Live events -----------------------------------------------------------
+
+const refreshRuns = debounce(() => loadRuns().catch(showError), 500);
+
+function handleEvent(event) {
+  if (!event.run_id) return;
+  if (state.tab === 'runs') refreshRuns();
+  if (event.run_id !== state.selectedRun || !state.run) return;
+
+  // Apply step events to the open run without refetching it
+  state.run.logs = (state.run.logs || []).concat(event);
+  if (event.type === 'status') {
+    loadRun().catch(showError);
+    return;
+  }
+  if (event.type === 'step_complete') {
+    loadRun().catch(showError);
+    return;
+  }
+  renderTimeline();
+}
+
+function setLive(on) {
+  const node = document.getElementById('live');
+  node.textContent = on ? 'live' : 'offline';
+  node.classList.toggle('on', on);
+}
+
+// streamEvents follows /v1/events/stream. fetch is used instead of
+// EventSource so the API key can be sent in a header.
+let streaming = false;
+async function streamEvents() {
+  if (streaming) return;
+  streaming = true;
+  for (;;) {
+    try {
+      const resp = await fetch('/v1/events/stream', { headers: authHeaders() });
+      if (resp.status === 401) {
+        showLogin();
+        break;
+      }
+      if (!resp.ok || !resp.body) throw new Error(`event stream returned ${resp.status}`);
+      setLive(true);
+
+      const reader = resp.body.getReader();
+      const decoder = new TextDecoder();
+      let buffer = '';
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) break;
+        buffer += decoder.decode(value, { stream: true });
+        let end;
+        while ((end = buffer.indexOf('\n\n')) >= 0) {
+          const message = buffer.slice(0, end);
+          buffer = buffer.slice(end + 2);
+          for (const line of message.split('\n')) {
+            if (line.startsWith('data: ')) handleEvent(JSON.parse(line.slice(6)));
+          }
+        }
+      }
+    } catch (err) {
+      // Reconnect below
+    }
+    setLive(false);
+    await new Promise((resolve) => setTimeout(resolve, 3000));
+  }
+  streaming = false;
+  setLive(false);
+}
+
+// Approvals and MCP status have no event feed; poll them
+setInterval(() => {
+  if (document.getElementById('login').hidden === false) return;
+  if (state.tab === 'mcp') loadMCP().catch(showError);
+  loadApprovals().catch(showError);
+}, 5000);
+
+refresh();
+streamEvents();
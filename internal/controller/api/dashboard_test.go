@@ -0,0 +1,168 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/tools/approval"
+)
+
+func TestDashboardHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	NewDashboardHandler().RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/ui", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != DashboardPath {
+		t.Errorf("GET /ui = %d %q, want redirect to %s", rec.Code, rec.Header().Get("Location"), DashboardPath)
+	}
+
+	for _, path := range []string{"/ui/", "/ui/app.js", "/ui/style.css"} {
+		req := httptest.NewRequest("GET", path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", path, rec.Code)
+			continue
+		}
+		if csp := rec.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "default-src 'self'") {
+			t.Errorf("GET %s: unexpected Content-Security-Policy %q", path, csp)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/ui/missing.js", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /ui/missing.js = %d, want 404", rec.Code)
+	}
+}
+
+func TestApprovalsHandler(t *testing.T) {
+	queue := approval.NewQueue(time.Minute)
+	mux := http.NewServeMux()
+	NewApprovalsHandler(queue).RegisterRoutes(mux)
+
+	result := make(chan bool, 1)
+	go func() {
+		approved, _ := queue.Approve(context.Background(), "github:sampling", "completion", map[string]any{"prompt": "hi"})
+		result <- approved
+	}()
+
+	var list struct {
+		Approvals []approval.Request `json:"approvals"`
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(list.Approvals) == 0 && time.Now().Before(deadline) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/approvals", nil))
+		if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+			t.Fatalf("failed to decode approvals: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(list.Approvals) != 1 || list.Approvals[0].ToolName != "github:sampling" {
+		t.Fatalf("unexpected approvals %+v", list.Approvals)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/approvals/"+list.Approvals[0].ID+"/approve", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body.String())
+	}
+	if !<-result {
+		t.Error("expected request to be approved")
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/approvals/"+list.Approvals[0].ID+"/deny", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("deny after decision = %d, want 404", rec.Code)
+	}
+}
+
+type fakeRunEvents struct {
+	ch chan runner.LogEntry
+}
+
+func (f *fakeRunEvents) SubscribeAll() (<-chan runner.LogEntry, func()) {
+	return f.ch, func() {}
+}
+
+func (f *fakeRunEvents) Get(id string) (*runner.RunSnapshot, error) {
+	return &runner.RunSnapshot{ID: id, Workflow: "test"}, nil
+}
+
+func TestEventsHandler_StreamRunEvents(t *testing.T) {
+	source := &fakeRunEvents{ch: make(chan runner.LogEntry, 1)}
+	handler := NewEventsHandler(nil)
+	handler.SetRunEvents(source)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/v1/events/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	source.ch <- runner.LogEntry{Type: "step_start", RunID: "run-1", StepID: "fetch"}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var entry runner.LogEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		if entry.Type == "step_start" {
+			if entry.RunID != "run-1" || entry.StepID != "fetch" {
+				t.Errorf("unexpected event %+v", entry)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without the run event: %v", scanner.Err())
+}
+
+func TestEventsHandler_ListWithoutStore(t *testing.T) {
+	mux := http.NewServeMux()
+	NewEventsHandler(nil).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/events?trace_id=abc", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /v1/events without store = %d, want 503", rec.Code)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/tombee/conductor/internal/controller/auth"
 	"github.com/tombee/conductor/internal/controller/debug"
 )
 
@@ -40,6 +41,7 @@ const (
 // DebugHandler handles debug-related API requests.
 type DebugHandler struct {
 	sessionManager       *debug.SessionManager
+	runs                 RunLookup
 	activeSSEConnections int
 	mu                   sync.Mutex
 }
@@ -51,6 +53,12 @@ func NewDebugHandler(sessionManager *debug.SessionManager) *DebugHandler {
 	}
 }
 
+// SetRuns sets the run lookup used to authorize debug requests against the
+// run's workflow.
+func (h *DebugHandler) SetRuns(runs RunLookup) {
+	h.runs = runs
+}
+
 // RegisterRoutes registers debug API routes on the router.
 func (h *DebugHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/runs/{id}/debug/events", h.handleDebugEvents)
@@ -62,6 +70,10 @@ func (h *DebugHandler) handleDebugEvents(w http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 	runID := r.PathValue("id")
 
+	if !authorizeRunLookup(w, r, h.runs, auth.ActionReadOutputs, runID) {
+		return
+	}
+
 	// Enforce TLS for non-localhost connections
 	if !isLocalhost(r) && !isTLS(r) {
 		writeError(w, http.StatusForbidden, "TLS required for non-localhost debug connections")
@@ -236,6 +248,10 @@ func (h *DebugHandler) handleDebugCommand(w http.ResponseWriter, r *http.Request
 	ctx := r.Context()
 	runID := r.PathValue("id")
 
+	if !authorizeRunLookup(w, r, h.runs, auth.ActionRun, runID) {
+		return
+	}
+
 	// Check Bearer token authentication
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
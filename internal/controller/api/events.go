@@ -20,21 +20,38 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/runner"
 	"github.com/tombee/conductor/internal/tracing/storage"
 )
 
+// RunEventSource provides log entries for every run on the controller, and
+// looks up runs so that entries are only streamed to callers who may read
+// the run's workflow.
+type RunEventSource interface {
+	RunLookup
+	SubscribeAll() (<-chan runner.LogEntry, func())
+}
+
 // EventsHandler provides HTTP handlers for event access.
 type EventsHandler struct {
-	store *storage.SQLiteStore
+	store     *storage.SQLiteStore
+	runEvents RunEventSource
 }
 
 // NewEventsHandler creates a new events API handler.
+// store may be nil when trace storage is disabled.
 func NewEventsHandler(store *storage.SQLiteStore) *EventsHandler {
 	return &EventsHandler{
 		store: store,
 	}
 }
 
+// SetRunEvents sets the source of run events for the event stream.
+func (h *EventsHandler) SetRunEvents(source RunEventSource) {
+	h.runEvents = source
+}
+
 // RegisterRoutes registers event API routes on the provided router.
 func (h *EventsHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/events", h.ListEvents)
@@ -71,6 +88,11 @@ func (h *EventsHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
 	// In a full implementation, this would query a dedicated events table
 	var events []EventResponse
 
+	if traceID != nil && h.store == nil {
+		writeError(w, http.StatusServiceUnavailable, "trace storage not enabled")
+		return
+	}
+
 	if traceID != nil {
 		// Get spans for this trace
 		spans, err := h.store.GetTraceSpans(ctx, *traceID)
@@ -114,7 +136,9 @@ func (h *EventsHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
 }
 
 // StreamEvents handles GET /v1/events/stream for Server-Sent Events (SSE).
-// This provides real-time event streaming.
+// Each run log entry (log, step_start, step_complete, status) is sent as a
+// JSON event with its run_id; a heartbeat is sent every 10 seconds. Entries
+// for runs the caller may not read are skipped.
 func (h *EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
@@ -130,24 +154,48 @@ func (h *EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
+	var events <-chan runner.LogEntry
+	var runs RunLookup
+	if h.runEvents != nil {
+		runs = h.runEvents
+		ch, unsub := h.runEvents.SubscribeAll()
+		defer unsub()
+		events = ch
+	}
+
 	// Send initial connection message
 	fmt.Fprintf(w, "data: {\"type\":\"connected\"}\n\n")
 	flusher.Flush()
 
-	// In a full implementation, this would:
-	// 1. Subscribe to a pub/sub channel for new events
-	// 2. Stream events as they arrive
-	// 3. Handle client disconnection via ctx.Done()
-
-	// For now, send a simple heartbeat
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
+	// Whether the caller may read each run, checked on its first entry
+	readable := make(map[string]bool)
+
 	for {
 		select {
 		case <-ctx.Done():
 			// Client disconnected
 			return
+		case entry, ok := <-events:
+			if !ok {
+				return
+			}
+			allowed, checked := readable[entry.RunID]
+			if !checked {
+				allowed = runAllowed(r, runs, auth.ActionRead, entry.RunID)
+				readable[entry.RunID] = allowed
+			}
+			if !allowed {
+				continue
+			}
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
 		case <-ticker.C:
 			// Send heartbeat
 			fmt.Fprintf(w, "data: {\"type\":\"heartbeat\"}\n\n")
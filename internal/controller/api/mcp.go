@@ -47,6 +47,10 @@ func (h *MCPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/mcp/servers", h.handleListServers)
 	mux.HandleFunc("GET /v1/mcp/servers/{name}", h.handleGetServer)
 	mux.HandleFunc("GET /v1/mcp/servers/{name}/tools", h.handleGetServerTools)
+	mux.HandleFunc("GET /v1/mcp/servers/{name}/resources", h.handleGetServerResources)
+	mux.HandleFunc("GET /v1/mcp/servers/{name}/resources/read", h.handleReadServerResource)
+	mux.HandleFunc("GET /v1/mcp/servers/{name}/prompts", h.handleGetServerPrompts)
+	mux.HandleFunc("GET /v1/mcp/servers/{name}/prompts/{prompt}", h.handleGetServerPrompt)
 	mux.HandleFunc("GET /v1/mcp/servers/{name}/health", h.handleGetServerHealth)
 	mux.HandleFunc("GET /v1/mcp/servers/{name}/logs", h.handleGetServerLogs)
 	mux.HandleFunc("POST /v1/mcp/servers/{name}/start", h.handleStartServer)
@@ -72,10 +76,14 @@ type MCPServerResponse struct {
 
 // MCPServerConfigResponse represents server configuration in API responses.
 type MCPServerConfigResponse struct {
-	Command string   `json:"command"`
-	Args    []string `json:"args,omitempty"`
-	Env     []string `json:"env,omitempty"`
-	Timeout int      `json:"timeout"`
+	Transport string            `json:"transport"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       []string          `json:"env,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	AuthType  string            `json:"auth_type,omitempty"`
+	Timeout   int               `json:"timeout"`
 }
 
 // MCPCapabilitiesResponse represents server capabilities.
@@ -92,6 +100,43 @@ type MCPToolResponse struct {
 	InputSchema json.RawMessage `json:"input_schema,omitempty"`
 }
 
+// MCPResourceResponse represents a resource in API responses.
+type MCPResourceResponse struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mime_type,omitempty"`
+}
+
+// MCPResourceContentResponse represents resource content in API responses.
+type MCPResourceContentResponse struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mime_type,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// MCPPromptResponse represents a prompt in API responses.
+type MCPPromptResponse struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	Arguments   []MCPPromptArgumentResponse `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgumentResponse represents a prompt argument in API responses.
+type MCPPromptArgumentResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// MCPPromptMessageResponse represents a rendered prompt message in API responses.
+type MCPPromptMessageResponse struct {
+	Role string `json:"role"`
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
 // MCPListResponse represents the list servers response.
 type MCPListResponse struct {
 	Servers []MCPServerResponse `json:"servers"`
@@ -105,12 +150,16 @@ type MCPHealthResponse struct {
 
 // MCPRegisterRequest represents a server registration request.
 type MCPRegisterRequest struct {
-	Name      string   `json:"name"`
-	Command   string   `json:"command"`
-	Args      []string `json:"args,omitempty"`
-	Env       []string `json:"env,omitempty"`
-	Timeout   int      `json:"timeout,omitempty"`
-	AutoStart bool     `json:"auto_start,omitempty"`
+	Name      string            `json:"name"`
+	Transport string            `json:"transport,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       []string          `json:"env,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Auth      *mcp.AuthConfig   `json:"auth,omitempty"`
+	Timeout   int               `json:"timeout,omitempty"`
+	AutoStart bool              `json:"auto_start,omitempty"`
 }
 
 // handleListServers handles GET /v1/mcp/servers
@@ -176,10 +225,16 @@ func (h *MCPHandler) handleGetServer(w http.ResponseWriter, r *http.Request) {
 	// Add config with redacted env
 	if status.Config != nil {
 		resp.Config = &MCPServerConfigResponse{
-			Command: status.Config.Command,
-			Args:    status.Config.Args,
-			Env:     mcp.RedactEnv(status.Config.Env),
-			Timeout: int(status.Config.Timeout.Seconds()),
+			Transport: string(mcp.ResolveTransport(status.Config.Transport, status.Config.URL)),
+			Command:   status.Config.Command,
+			Args:      status.Config.Args,
+			Env:       mcp.RedactEnv(status.Config.Env),
+			URL:       status.Config.URL,
+			Headers:   mcp.RedactHeaders(status.Config.Headers),
+			Timeout:   int(status.Config.Timeout.Seconds()),
+		}
+		if status.Config.Auth != nil {
+			resp.Config.AuthType = string(status.Config.Auth.Type)
 		}
 	}
 
@@ -242,6 +297,182 @@ func (h *MCPHandler) handleGetServerTools(w http.ResponseWriter, r *http.Request
 	writeJSON(w, http.StatusOK, map[string]any{"tools": resp})
 }
 
+// getRunningClient returns the client for a running server, writing an error
+// response and returning false if it is not available.
+func (h *MCPHandler) getRunningClient(w http.ResponseWriter, r *http.Request) (mcp.ClientProvider, bool) {
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "server name is required")
+		return nil, false
+	}
+
+	client, err := h.registry.GetClient(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, http.StatusNotFound, err.Error())
+		} else if strings.Contains(err.Error(), "not ready") || strings.Contains(err.Error(), "not running") {
+			writeError(w, http.StatusServiceUnavailable, "server is not running")
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return nil, false
+	}
+	return client, true
+}
+
+// writeMCPCallError writes an error from a resource or prompt request.
+func writeMCPCallError(w http.ResponseWriter, action string, err error) {
+	if strings.Contains(err.Error(), "does not support") {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "failed to "+action+": "+err.Error())
+}
+
+// handleGetServerResources handles GET /v1/mcp/servers/{name}/resources
+func (h *MCPHandler) handleGetServerResources(w http.ResponseWriter, r *http.Request) {
+	client, ok := h.getRunningClient(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	resources, err := client.ListResources(ctx)
+	if err != nil {
+		writeMCPCallError(w, "list resources", err)
+		return
+	}
+
+	resp := make([]MCPResourceResponse, len(resources))
+	for i, res := range resources {
+		resp[i] = MCPResourceResponse{
+			URI:         res.URI,
+			Name:        res.Name,
+			Description: res.Description,
+			MimeType:    res.MimeType,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"resources": resp})
+}
+
+// handleReadServerResource handles GET /v1/mcp/servers/{name}/resources/read?uri=...
+func (h *MCPHandler) handleReadServerResource(w http.ResponseWriter, r *http.Request) {
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		writeError(w, http.StatusBadRequest, "uri query parameter is required")
+		return
+	}
+
+	client, ok := h.getRunningClient(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := client.ReadResource(ctx, mcp.ResourceReadRequest{URI: uri})
+	if err != nil {
+		writeMCPCallError(w, "read resource", err)
+		return
+	}
+
+	resp := make([]MCPResourceContentResponse, len(result.Contents))
+	for i, c := range result.Contents {
+		resp[i] = MCPResourceContentResponse{
+			URI:      c.URI,
+			MimeType: c.MimeType,
+			Text:     c.Text,
+			Blob:     c.Blob,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"contents": resp})
+}
+
+// handleGetServerPrompts handles GET /v1/mcp/servers/{name}/prompts
+func (h *MCPHandler) handleGetServerPrompts(w http.ResponseWriter, r *http.Request) {
+	client, ok := h.getRunningClient(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	prompts, err := client.ListPrompts(ctx)
+	if err != nil {
+		writeMCPCallError(w, "list prompts", err)
+		return
+	}
+
+	resp := make([]MCPPromptResponse, len(prompts))
+	for i, p := range prompts {
+		resp[i] = MCPPromptResponse{
+			Name:        p.Name,
+			Description: p.Description,
+		}
+		for _, arg := range p.Arguments {
+			resp[i].Arguments = append(resp[i].Arguments, MCPPromptArgumentResponse{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"prompts": resp})
+}
+
+// handleGetServerPrompt handles GET /v1/mcp/servers/{name}/prompts/{prompt}
+// Query parameters are passed to the prompt as arguments.
+func (h *MCPHandler) handleGetServerPrompt(w http.ResponseWriter, r *http.Request) {
+	promptName := r.PathValue("prompt")
+	if promptName == "" {
+		writeError(w, http.StatusBadRequest, "prompt name is required")
+		return
+	}
+
+	client, ok := h.getRunningClient(w, r)
+	if !ok {
+		return
+	}
+
+	args := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			args[key] = values[0]
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := client.GetPrompt(ctx, mcp.PromptGetRequest{Name: promptName, Arguments: args})
+	if err != nil {
+		writeMCPCallError(w, "get prompt", err)
+		return
+	}
+
+	messages := make([]MCPPromptMessageResponse, len(result.Messages))
+	for i, m := range result.Messages {
+		messages[i] = MCPPromptMessageResponse{
+			Role: m.Role,
+			Type: m.Content.Type,
+			Text: m.Content.Text,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"name":        promptName,
+		"description": result.Description,
+		"messages":    messages,
+	})
+}
+
 // handleGetServerHealth handles GET /v1/mcp/servers/{name}/health
 func (h *MCPHandler) handleGetServerHealth(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
@@ -370,8 +601,8 @@ func (h *MCPHandler) handleRegisterServer(w http.ResponseWriter, r *http.Request
 		writeError(w, http.StatusBadRequest, "name is required")
 		return
 	}
-	if req.Command == "" {
-		writeError(w, http.StatusBadRequest, "command is required")
+	if req.Command == "" && req.URL == "" {
+		writeError(w, http.StatusBadRequest, "command or url is required")
 		return
 	}
 
@@ -381,9 +612,13 @@ func (h *MCPHandler) handleRegisterServer(w http.ResponseWriter, r *http.Request
 	}
 
 	entry := &mcp.MCPServerEntry{
+		Transport: mcp.TransportType(req.Transport),
 		Command:   req.Command,
 		Args:      req.Args,
 		Env:       req.Env,
+		URL:       req.URL,
+		Headers:   req.Headers,
+		Auth:      req.Auth,
 		Timeout:   req.Timeout,
 		AutoStart: req.AutoStart,
 	}
@@ -0,0 +1,79 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/backend"
+)
+
+// defaultDeadLetterLimit caps dead letters returned without a limit parameter.
+const defaultDeadLetterLimit = 100
+
+// NotificationsHandler serves run notifications that could not be delivered.
+type NotificationsHandler struct {
+	store backend.NotificationStore
+}
+
+// NewNotificationsHandler creates a new notifications handler. store is nil
+// if the backend does not record dead letters.
+func NewNotificationsHandler(store backend.NotificationStore) *NotificationsHandler {
+	return &NotificationsHandler{store: store}
+}
+
+// RegisterRoutes registers notification routes on the router.
+func (h *NotificationsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/notifications/dead-letters", h.handleListDeadLetters)
+}
+
+// handleListDeadLetters handles GET /v1/notifications/dead-letters.
+func (h *NotificationsHandler) handleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		writeError(w, http.StatusNotImplemented, "backend does not support notification dead letters")
+		return
+	}
+
+	limit := defaultDeadLetterLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		limit = n
+	}
+
+	letters, err := h.store.ListNotificationDeadLetters(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Only list notifications about workflows the caller may read
+	visible := make([]*backend.NotificationDeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		if auth.Allowed(r.Context(), auth.ActionRead, auth.Resource{Workflow: letter.Workflow}) {
+			visible = append(visible, letter)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"dead_letters": visible,
+		"count":        len(visible),
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+)
+
+// OIDCInfo tells clients how to log in to the controller.
+type OIDCInfo struct {
+	Issuer   string   `json:"issuer"`
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
+}
+
+// OIDCHandler serves the controller's OIDC login settings. It is public so
+// that `conductor login` can find the issuer before it has a token.
+type OIDCHandler struct {
+	info OIDCInfo
+}
+
+// NewOIDCHandler creates a new OIDC handler.
+func NewOIDCHandler(info OIDCInfo) *OIDCHandler {
+	return &OIDCHandler{info: info}
+}
+
+// RegisterRoutes registers OIDC routes on the router.
+func (h *OIDCHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+auth.OIDCInfoPath, h.handleInfo)
+}
+
+// handleInfo handles GET /v1/auth/oidc.
+func (h *OIDCHandler) handleInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.info)
+}
@@ -18,6 +18,8 @@ import (
 	"net/http"
 
 	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/internal/controller/webhook"
 )
 
 // PublicRouter handles routing for the public-facing API.
@@ -31,6 +33,13 @@ type PublicRouter struct {
 type PublicRouterConfig struct {
 	Runner       *runner.Runner
 	WorkflowsDir string
+
+	// Events records webhook events. If nil, they are not recorded.
+	Events *trigger.EventLog
+
+	// WebhookSources are the webhook source handlers. If nil, the built-in
+	// sources are used.
+	WebhookSources *webhook.Registry
 }
 
 // NewPublicRouter creates a new public API router.
@@ -55,6 +64,12 @@ func NewPublicRouter(cfg PublicRouterConfig) *PublicRouter {
 
 		// Register webhook handler
 		webhookHandler := NewWebhookHandler(cfg.Runner, cfg.WorkflowsDir)
+		if cfg.Events != nil {
+			webhookHandler.SetEventLog(cfg.Events)
+		}
+		if cfg.WebhookSources != nil {
+			webhookHandler.SetSources(cfg.WebhookSources)
+		}
 		webhookHandler.RegisterRoutes(mux)
 	}
 
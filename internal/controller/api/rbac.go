@@ -0,0 +1,252 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+)
+
+// RBACHandler manages roles and role bindings. Access to it is limited to
+// the admin action by the auth middleware, except for whoami.
+type RBACHandler struct {
+	authorizer *auth.Authorizer
+	enabled    bool
+}
+
+// NewRBACHandler creates a new RBAC handler. enabled reports whether the
+// authorizer is enforced; roles can be managed either way.
+func NewRBACHandler(authorizer *auth.Authorizer, enabled bool) *RBACHandler {
+	return &RBACHandler{authorizer: authorizer, enabled: enabled}
+}
+
+// RegisterRoutes registers RBAC routes on the router.
+func (h *RBACHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/auth/whoami", h.handleWhoami)
+	mux.HandleFunc("GET /v1/auth/roles", h.handleListRoles)
+	mux.HandleFunc("POST /v1/auth/roles", h.handleSaveRole)
+	mux.HandleFunc("GET /v1/auth/roles/{name}", h.handleGetRole)
+	mux.HandleFunc("DELETE /v1/auth/roles/{name}", h.handleDeleteRole)
+	mux.HandleFunc("GET /v1/auth/bindings", h.handleListBindings)
+	mux.HandleFunc("POST /v1/auth/bindings", h.handleSaveBinding)
+	mux.HandleFunc("DELETE /v1/auth/bindings/{subject}", h.handleDeleteBinding)
+}
+
+// WhoamiResponse describes the caller's identity and roles.
+type WhoamiResponse struct {
+	// User is empty when the request was not authenticated by user,
+	// such as requests over the Unix socket.
+	User         string   `json:"user,omitempty"`
+	Roles        []string `json:"roles"`
+	Actions      []string `json:"actions"`
+	RBACEnabled  bool     `json:"rbac_enabled"`
+	Unrestricted bool     `json:"unrestricted"`
+}
+
+// handleWhoami handles GET /v1/auth/whoami.
+func (h *RBACHandler) handleWhoami(w http.ResponseWriter, r *http.Request) {
+	resp := WhoamiResponse{Roles: []string{}, Actions: []string{}, RBACEnabled: h.enabled}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok || !h.enabled {
+		if ok {
+			resp.User = user.ID
+		}
+		resp.Unrestricted = true
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	resp.User = user.ID
+	roles, err := h.authorizer.RoleNames(r.Context(), user)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp.Roles = roles
+	for _, action := range auth.Actions {
+		if h.authorizer.Allowed(r.Context(), user, action, auth.Resource{Precheck: true}) {
+			resp.Actions = append(resp.Actions, string(action))
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleListRoles handles GET /v1/auth/roles.
+func (h *RBACHandler) handleListRoles(w http.ResponseWriter, r *http.Request) {
+	roles := auth.BuiltinRoles()
+	if store := h.authorizer.Store(); store != nil {
+		stored, err := store.ListRoles(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		roles = append(roles, stored...)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"roles": roles,
+		"count": len(roles),
+	})
+}
+
+// handleGetRole handles GET /v1/auth/roles/{name}.
+func (h *RBACHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	role, err := h.authorizer.Role(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeRoleStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, role)
+}
+
+// handleSaveRole handles POST /v1/auth/roles, creating or replacing a role.
+func (h *RBACHandler) handleSaveRole(w http.ResponseWriter, r *http.Request) {
+	store := h.storeOrError(w)
+	if store == nil {
+		return
+	}
+
+	var role auth.Role
+	if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	role.Builtin = false
+	if err := role.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := store.SaveRole(r.Context(), &role); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, role)
+}
+
+// handleDeleteRole handles DELETE /v1/auth/roles/{name}.
+func (h *RBACHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	store := h.storeOrError(w)
+	if store == nil {
+		return
+	}
+
+	name := r.PathValue("name")
+	for _, role := range auth.BuiltinRoles() {
+		if role.Name == name {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("role %q is built in and cannot be deleted", name))
+			return
+		}
+	}
+	if err := store.DeleteRole(r.Context(), name); err != nil {
+		writeRoleStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// handleListBindings handles GET /v1/auth/bindings.
+func (h *RBACHandler) handleListBindings(w http.ResponseWriter, r *http.Request) {
+	bindings := []*auth.RoleBinding{}
+	if store := h.authorizer.Store(); store != nil {
+		stored, err := store.ListRoleBindings(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		bindings = append(bindings, stored...)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"bindings": bindings,
+		"count":    len(bindings),
+	})
+}
+
+// handleSaveBinding handles POST /v1/auth/bindings, replacing the roles
+// bound to a subject.
+func (h *RBACHandler) handleSaveBinding(w http.ResponseWriter, r *http.Request) {
+	store := h.storeOrError(w)
+	if store == nil {
+		return
+	}
+
+	var binding auth.RoleBinding
+	if err := json.NewDecoder(r.Body).Decode(&binding); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if binding.Subject == "" {
+		writeError(w, http.StatusBadRequest, "subject is required")
+		return
+	}
+	if len(binding.Roles) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one role is required")
+		return
+	}
+	for _, name := range binding.Roles {
+		if _, err := h.authorizer.Role(r.Context(), name); err != nil {
+			if errors.Is(err, auth.ErrRoleNotFound) {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown role %q", name))
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	slices.Sort(binding.Roles)
+	binding.Roles = slices.Compact(binding.Roles)
+
+	if err := store.SaveRoleBinding(r.Context(), &binding); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, binding)
+}
+
+// handleDeleteBinding handles DELETE /v1/auth/bindings/{subject}.
+func (h *RBACHandler) handleDeleteBinding(w http.ResponseWriter, r *http.Request) {
+	store := h.storeOrError(w)
+	if store == nil {
+		return
+	}
+	if err := store.DeleteRoleBinding(r.Context(), r.PathValue("subject")); err != nil {
+		writeRoleStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// storeOrError returns the role store, or writes 501 if the backend cannot
+// store roles.
+func (h *RBACHandler) storeOrError(w http.ResponseWriter) auth.RoleStore {
+	store := h.authorizer.Store()
+	if store == nil {
+		writeError(w, http.StatusNotImplemented, "backend does not support role storage")
+	}
+	return store
+}
+
+func writeRoleStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, auth.ErrRoleNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,408 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/internal/controller/scheduler"
+	"github.com/tombee/conductor/internal/triggers"
+	"github.com/tombee/conductor/pkg/tools/approval"
+)
+
+// rbacTestStore holds custom roles limited to deploy workflows.
+type rbacTestStore struct {
+	auth.RoleStore
+}
+
+func (rbacTestStore) GetRole(ctx context.Context, name string) (*auth.Role, error) {
+	switch name {
+	case "deployer":
+		return &auth.Role{
+			Name: "deployer",
+			Permissions: []auth.Permission{{
+				Actions:   []auth.Action{auth.ActionRead, auth.ActionReadOutputs, auth.ActionRun, auth.ActionCancel, auth.ActionApprove},
+				Workflows: []string{"deploy-*"},
+			}},
+		}, nil
+	case "deploy-triggers":
+		return &auth.Role{
+			Name: "deploy-triggers",
+			Permissions: []auth.Permission{{
+				Actions:   []auth.Action{auth.ActionRead, auth.ActionManageTriggers},
+				Workflows: []string{"deploy-*"},
+			}},
+		}, nil
+	default:
+		return nil, auth.ErrRoleNotFound
+	}
+}
+
+func (rbacTestStore) GetRoleBinding(ctx context.Context, subject string) (*auth.RoleBinding, error) {
+	return nil, auth.ErrRoleNotFound
+}
+
+func setupRBACTestServer(t *testing.T) (http.Handler, *runner.Runner) {
+	t.Helper()
+
+	mux, r := setupTestServer(t)
+	authorizer := auth.NewAuthorizer(auth.AuthorizerConfig{Store: rbacTestStore{}})
+	NewRBACHandler(authorizer, true).RegisterRoutes(mux)
+
+	m := auth.NewMiddleware(auth.Config{
+		Enabled: true,
+		APIKeys: []auth.APIKey{
+			{Key: "deployer-key", Name: "deployer", Roles: []string{"deployer"}},
+			{Key: "viewer-key", Name: "viewer", Roles: []string{auth.RoleViewer}},
+			{Key: "admin-key", Name: "admin", Roles: []string{auth.RoleAdmin}},
+		},
+		Authorizer: authorizer,
+	})
+	return m.Wrap(mux), r
+}
+
+func rbacRequest(handler http.Handler, key, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-API-Key", key)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/x-yaml")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRunsHandler_RBAC(t *testing.T) {
+	handler, r := setupRBACTestServer(t)
+
+	workflowYAML := func(name string) string {
+		return "name: " + name + "\nsteps:\n  - id: step1\n    type: llm\n    prompt: test\n"
+	}
+
+	// The deployer may only run deploy workflows
+	rec := rbacRequest(handler, "deployer-key", "POST", "/v1/runs", workflowYAML("cleanup"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("submit cleanup: status = %d, want 403. Body: %s", rec.Code, rec.Body.String())
+	}
+	rec = rbacRequest(handler, "deployer-key", "POST", "/v1/runs", workflowYAML("deploy-api"))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit deploy-api: status = %d, want 202. Body: %s", rec.Code, rec.Body.String())
+	}
+
+	// An admin runs a workflow the deployer cannot see
+	rec = rbacRequest(handler, "admin-key", "POST", "/v1/runs", workflowYAML("cleanup"))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("admin submit: status = %d, want 202. Body: %s", rec.Code, rec.Body.String())
+	}
+	var created map[string]any
+	json.NewDecoder(rec.Body).Decode(&created)
+	cleanupID, _ := created["id"].(string)
+
+	rec = rbacRequest(handler, "deployer-key", "GET", "/v1/runs", "")
+	var list struct {
+		Runs []*runner.RunSnapshot `json:"runs"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	if len(list.Runs) != 1 || list.Runs[0].Workflow != "deploy-api" {
+		t.Errorf("deployer list = %d runs, want only deploy-api", len(list.Runs))
+	}
+	if got := len(r.List(runner.ListFilter{})); got != 2 {
+		t.Errorf("runner has %d runs, want 2", got)
+	}
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/v1/runs/" + cleanupID},
+		{"GET", "/v1/runs/" + cleanupID + "/logs"},
+		{"DELETE", "/v1/runs/" + cleanupID},
+	} {
+		rec = rbacRequest(handler, "deployer-key", req.method, req.path, "")
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status = %d, want 403", req.method, req.path, rec.Code)
+		}
+	}
+
+	// Viewers are stopped by the middleware before the handler
+	rec = rbacRequest(handler, "viewer-key", "GET", "/v1/runs/"+cleanupID+"/logs", "")
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("viewer logs: status = %d, want 403", rec.Code)
+	}
+}
+
+// scopedRunEvents streams entries for runs of two workflows.
+type scopedRunEvents struct {
+	ch chan runner.LogEntry
+}
+
+func (f *scopedRunEvents) SubscribeAll() (<-chan runner.LogEntry, func()) {
+	return f.ch, func() {}
+}
+
+func (f *scopedRunEvents) Get(id string) (*runner.RunSnapshot, error) {
+	workflows := map[string]string{"run-deploy": "deploy-api", "run-cleanup": "cleanup"}
+	if workflow, ok := workflows[id]; ok {
+		return &runner.RunSnapshot{ID: id, Workflow: workflow}, nil
+	}
+	return nil, errors.New("run not found")
+}
+
+func TestRunScopedRoutes_RBAC(t *testing.T) {
+	mux := http.NewServeMux()
+	source := &scopedRunEvents{ch: make(chan runner.LogEntry, 3)}
+
+	debugHandler := NewDebugHandler(nil)
+	debugHandler.SetRuns(source)
+	debugHandler.RegisterRoutes(mux)
+	tracesHandler := NewTracesHandler(nil)
+	tracesHandler.SetRuns(source)
+	tracesHandler.RegisterRoutes(mux)
+	eventsHandler := NewEventsHandler(nil)
+	eventsHandler.SetRunEvents(source)
+	eventsHandler.RegisterRoutes(mux)
+	queue := approval.NewQueue(0)
+	approvalsHandler := NewApprovalsHandler(queue)
+	approvalsHandler.SetRuns(source)
+	approvalsHandler.RegisterRoutes(mux)
+
+	authorizer := auth.NewAuthorizer(auth.AuthorizerConfig{Store: rbacTestStore{}})
+	handler := auth.NewMiddleware(auth.Config{
+		Enabled:    true,
+		APIKeys:    []auth.APIKey{{Key: "deployer-key", Name: "deployer", Roles: []string{"deployer"}}},
+		Authorizer: authorizer,
+	}).Wrap(mux)
+
+	// Run-scoped routes check the run's workflow
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/v1/runs/run-cleanup/debug/events?session_id=s"},
+		{"POST", "/v1/runs/run-cleanup/debug/command?session_id=s"},
+		{"GET", "/v1/runs/run-cleanup/trace"},
+	} {
+		rec := rbacRequest(handler, "deployer-key", req.method, req.path, "")
+		if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "cleanup") {
+			t.Errorf("%s %s: status = %d (%s), want 403 for workflow cleanup", req.method, req.path, rec.Code, rec.Body.String())
+		}
+	}
+	// Allowed through to the handler, which wants a bearer token
+	rec := rbacRequest(handler, "deployer-key", "POST", "/v1/runs/run-deploy/debug/command?session_id=s", "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("debug command on deploy run: status = %d, want 401", rec.Code)
+	}
+
+	// Approval requests are limited to the runs the caller may read
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Approve(ctx, "sampling", "", map[string]interface{}{"run_id": "run-cleanup"})
+	go queue.Approve(ctx, "sampling", "", map[string]interface{}{"run_id": "run-deploy"})
+	for deadline := time.Now().Add(5 * time.Second); len(queue.Pending()) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatal("approval requests were not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	var cleanupRequest string
+	for _, req := range queue.Pending() {
+		if req.Inputs["run_id"] == "run-cleanup" {
+			cleanupRequest = req.ID
+		}
+	}
+
+	rec = rbacRequest(handler, "deployer-key", "GET", "/v1/approvals", "")
+	var list struct {
+		Approvals []approval.Request `json:"approvals"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode approvals: %v", err)
+	}
+	if len(list.Approvals) != 1 || list.Approvals[0].Inputs["run_id"] != "run-deploy" {
+		t.Errorf("approvals = %+v, want only the deploy run's request", list.Approvals)
+	}
+	rec = rbacRequest(handler, "deployer-key", "POST", "/v1/approvals/"+cleanupRequest+"/approve", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("approve cleanup request: status = %d, want 404", rec.Code)
+	}
+
+	// The event stream skips entries for other workflows
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	source.ch <- runner.LogEntry{RunID: "run-cleanup", Type: "log", Message: "hidden"}
+	source.ch <- runner.LogEntry{RunID: "run-deploy", Type: "log", Message: "visible"}
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/v1/events/stream", nil)
+	req.Header.Set("X-API-Key", "deployer-key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, "hidden") {
+			t.Fatal("stream included an entry for a run the caller may not read")
+		}
+		if strings.Contains(line, "visible") {
+			break
+		}
+	}
+}
+
+func TestSchedulesHandler_RBAC(t *testing.T) {
+	sched, err := scheduler.New(scheduler.Config{Schedules: []scheduler.Schedule{
+		{Name: "deploy-nightly", Cron: "0 2 * * *", Workflow: "deploy-api", Enabled: true},
+		{Name: "cleanup-nightly", Cron: "0 3 * * *", Workflow: "cleanup", Enabled: true},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+	mux := http.NewServeMux()
+	NewSchedulesHandler(sched).RegisterRoutes(mux)
+	handler := auth.NewMiddleware(auth.Config{
+		Enabled:    true,
+		APIKeys:    []auth.APIKey{{Key: "triggers-key", Name: "triggers", Roles: []string{"deploy-triggers"}}},
+		Authorizer: auth.NewAuthorizer(auth.AuthorizerConfig{Store: rbacTestStore{}}),
+	}).Wrap(mux)
+
+	rec := rbacRequest(handler, "triggers-key", "GET", "/v1/schedules", "")
+	var list struct {
+		Schedules []scheduler.ScheduleStatus `json:"schedules"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode schedules: %v", err)
+	}
+	if len(list.Schedules) != 1 || list.Schedules[0].Name != "deploy-nightly" {
+		t.Errorf("schedules = %+v, want only deploy-nightly", list.Schedules)
+	}
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/v1/schedules/cleanup-nightly"},
+		{"POST", "/v1/schedules/cleanup-nightly/disable"},
+		{"POST", "/v1/schedules/cleanup-nightly/enable"},
+	} {
+		rec = rbacRequest(handler, "triggers-key", req.method, req.path, "")
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status = %d, want 403", req.method, req.path, rec.Code)
+		}
+	}
+	if s, _ := sched.GetSchedule("cleanup-nightly"); !s.Enabled {
+		t.Error("a forbidden request disabled the cleanup schedule")
+	}
+
+	rec = rbacRequest(handler, "triggers-key", "POST", "/v1/schedules/deploy-nightly/disable", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("disable deploy-nightly: status = %d, want 200. Body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTriggerManagementHandler_RBAC(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"deploy-api.yaml", "cleanup.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("name: test\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("controller:\n  webhooks:\n    routes:\n      - path: /webhooks/cleanup\n        source: github\n        workflow: cleanup.yaml\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	router := &Router{mux: http.NewServeMux()}
+	router.SetTriggerManagementHandler(NewTriggerManagementHandler(triggers.NewManager(configPath, dir)))
+	handler := auth.NewMiddleware(auth.Config{
+		Enabled:    true,
+		APIKeys:    []auth.APIKey{{Key: "triggers-key", Name: "triggers", Roles: []string{"deploy-triggers"}}},
+		Authorizer: auth.NewAuthorizer(auth.AuthorizerConfig{Store: rbacTestStore{}}),
+	}).Wrap(router.mux)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", "triggers-key")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send("POST", "/v1/triggers/webhooks", `{"workflow": "cleanup.yaml", "path": "/webhooks/other", "source": "github"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("create cleanup webhook: status = %d, want 403. Body: %s", rec.Code, rec.Body.String())
+	}
+	rec = send("POST", "/v1/triggers/endpoints", `{"workflow": "cleanup.yaml", "name": "cleanup"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("create cleanup endpoint: status = %d, want 403. Body: %s", rec.Code, rec.Body.String())
+	}
+	rec = send("DELETE", "/v1/triggers/webhooks/webhooks%2Fcleanup", "")
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("delete cleanup webhook: status = %d, want 403. Body: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = send("POST", "/v1/triggers/webhooks", `{"workflow": "deploy-api.yaml", "path": "/webhooks/deploy", "source": "github"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create deploy webhook: status = %d, want 201. Body: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = send("GET", "/v1/triggers/webhooks", "")
+	var webhooks []triggers.WebhookTrigger
+	if err := json.NewDecoder(rec.Body).Decode(&webhooks); err != nil {
+		t.Fatalf("failed to decode webhooks: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].Workflow != "deploy-api.yaml" {
+		t.Errorf("webhooks = %+v, want only the deploy webhook", webhooks)
+	}
+}
+
+func TestRBACHandler(t *testing.T) {
+	handler, _ := setupRBACTestServer(t)
+
+	rec := rbacRequest(handler, "deployer-key", "GET", "/v1/auth/whoami", "")
+	var whoami WhoamiResponse
+	if err := json.NewDecoder(rec.Body).Decode(&whoami); err != nil {
+		t.Fatalf("failed to decode whoami: %v", err)
+	}
+	if whoami.User != "deployer" || len(whoami.Roles) != 1 || whoami.Roles[0] != "deployer" || whoami.Unrestricted {
+		t.Errorf("whoami = %+v", whoami)
+	}
+
+	// Managing roles needs admin
+	rec = rbacRequest(handler, "deployer-key", "GET", "/v1/auth/roles", "")
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("deployer list roles: status = %d, want 403", rec.Code)
+	}
+	rec = rbacRequest(handler, "admin-key", "GET", "/v1/auth/roles/operator", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("admin get role: status = %d, want 200", rec.Code)
+	}
+	rec = rbacRequest(handler, "admin-key", "GET", "/v1/auth/roles/missing", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("get missing role: status = %d, want 404", rec.Code)
+	}
+	rec = rbacRequest(handler, "admin-key", "DELETE", "/v1/auth/roles/admin", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("delete built-in role: status = %d, want 400", rec.Code)
+	}
+}
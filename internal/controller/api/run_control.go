@@ -0,0 +1,114 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+)
+
+// RetryStepRequest is the request body for retrying a failed step.
+type RetryStepRequest struct {
+	// Inputs replace values in the step's inputs for the retry.
+	// For LLM steps, "prompt" and "system" replace the step's prompts.
+	Inputs map[string]any `json:"inputs,omitempty"`
+}
+
+// handlePause handles POST /v1/runs/{id}/pause.
+func (h *RunsHandler) handlePause(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "run ID required")
+		return
+	}
+
+	if !h.authorizeRunID(w, r, auth.ActionCancel, id) {
+		return
+	}
+
+	run, err := h.runner.Pause(id)
+	if err != nil {
+		writeRunStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.createRunResponse(run))
+}
+
+// handleResume handles POST /v1/runs/{id}/resume.
+func (h *RunsHandler) handleResume(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "run ID required")
+		return
+	}
+
+	if !h.authorizeRunID(w, r, auth.ActionRun, id) {
+		return
+	}
+
+	run, err := h.runner.Resume(r.Context(), id)
+	if err != nil {
+		writeRunStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, h.createRunResponse(run))
+}
+
+// handleRetryStep handles POST /v1/runs/{id}/steps/{step_id}/retry.
+func (h *RunsHandler) handleRetryStep(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	stepID := r.PathValue("step_id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "run ID required")
+		return
+	}
+	if stepID == "" {
+		writeError(w, http.StatusBadRequest, "step ID required")
+		return
+	}
+
+	if !h.authorizeRunID(w, r, auth.ActionRun, id) {
+		return
+	}
+
+	// The body is optional; an empty body retries with the original inputs
+	var req RetryStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	run, err := h.runner.RetryStep(r.Context(), id, stepID, req.Inputs)
+	if err != nil {
+		writeRunStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, h.createRunResponse(run))
+}
+
+// writeRunStateError writes a runner error for a request that acts on an
+// existing run: 404 if the run does not exist, otherwise 409.
+func writeRunStateError(w http.ResponseWriter, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusConflict, err.Error())
+}
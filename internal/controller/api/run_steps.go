@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/tombee/conductor/internal/controller/auth"
 	"github.com/tombee/conductor/internal/controller/backend"
 )
 
@@ -36,6 +37,10 @@ func (h *RunsHandler) handleGetStep(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeRunID(w, r, auth.ActionReadOutputs, runID) {
+		return
+	}
+
 	// Get the step result from backend storage
 	store, ok := h.runner.Backend().(backend.StepResultStore)
 	if !ok {
@@ -61,6 +66,10 @@ func (h *RunsHandler) handleListSteps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeRunID(w, r, auth.ActionReadOutputs, runID) {
+		return
+	}
+
 	// Get all step results for the run from backend storage
 	store, ok := h.runner.Backend().(backend.StepResultStore)
 	if !ok {
@@ -16,12 +16,16 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/tombee/conductor/internal/controller/auth"
 	"github.com/tombee/conductor/internal/controller/runner"
 )
 
@@ -44,17 +48,32 @@ func (h *RunsHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/runs/{id}/logs", h.handleGetLogs)
 	mux.HandleFunc("GET /v1/runs/{id}/steps", h.handleListSteps)
 	mux.HandleFunc("GET /v1/runs/{id}/steps/{step_id}", h.handleGetStep)
+	mux.HandleFunc("POST /v1/runs/{id}/steps/{step_id}/retry", h.handleRetryStep)
+	mux.HandleFunc("POST /v1/runs/{id}/pause", h.handlePause)
+	mux.HandleFunc("POST /v1/runs/{id}/resume", h.handleResume)
 	mux.HandleFunc("DELETE /v1/runs/{id}", h.handleCancel)
 }
 
 // CreateRunRequest is the request body for creating a run.
 type CreateRunRequest struct {
-	Workflow  string         `json:"workflow"`
-	Inputs    map[string]any `json:"inputs,omitempty"`
-	Workspace string         `json:"workspace,omitempty"` // Workspace for profile resolution
-	Profile   string         `json:"profile,omitempty"`   // Profile for binding resolution
+	Workflow     string         `json:"workflow"`
+	WorkflowYAML string         `json:"workflow_yaml,omitempty"` // Inline workflow definition
+	Inputs       map[string]any `json:"inputs,omitempty"`
+	Workspace    string         `json:"workspace,omitempty"` // Workspace for profile resolution
+	Profile      string         `json:"profile,omitempty"`   // Profile for binding resolution
+	MCPLock      string         `json:"mcp_lock,omitempty"`  // mcp.lock contents pinning MCP server tools
 }
 
+// Headers for idempotent run creation.
+const (
+	// IdempotencyKeyHeader makes a run creation request idempotent: a repeat
+	// with the same key returns the original run.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on responses that return an earlier run.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 // handleCreate handles POST /v1/runs.
 func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	// Check if runner is draining (graceful shutdown in progress)
@@ -83,6 +102,7 @@ func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	logLevel := r.URL.Query().Get("log_level")
 	debugStep := r.URL.Query().Get("debug_step")
 	debugBreakpoints := r.URL.Query()["debug_breakpoint"]
+	priority := runner.PriorityClass(r.URL.Query().Get("priority"))
 
 	// Parse and validate timeout if provided
 	var timeout time.Duration
@@ -120,6 +140,7 @@ func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 			"log_level":        true,
 			"debug_step":       true,
 			"debug_breakpoint": true,
+			"priority":         true,
 		}
 		inputs := make(map[string]any)
 		for key, values := range r.URL.Query() {
@@ -153,14 +174,17 @@ func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 			MCPDev:           mcpDev,
 			LogLevel:         logLevel,
 			DebugBreakpoints: breakpoints,
+			Priority:         priority,
+			IdempotencyKey:   r.Header.Get(IdempotencyKeyHeader),
+			Authorize:        authorizeSubmit(r),
 		})
 		if err != nil {
-			writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to submit run: %v", err))
+			writeSubmitError(w, err)
 			return
 		}
 
 		response := h.createRunResponse(run)
-		writeJSON(w, http.StatusAccepted, response)
+		writeJSON(w, submitStatus(w, run), response)
 		return
 	}
 
@@ -180,8 +204,11 @@ func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 
 		// For now, workflow must be provided inline in a workflow_yaml field
 		// In the future, we'll support referencing workflows by name
-		writeError(w, http.StatusBadRequest, "workflow_yaml field required (workflow reference not yet supported)")
-		return
+		if req.WorkflowYAML == "" {
+			writeError(w, http.StatusBadRequest, "workflow_yaml field required (workflow reference not yet supported)")
+			return
+		}
+		workflowYAML = []byte(req.WorkflowYAML)
 	} else if strings.HasPrefix(contentType, "application/x-yaml") || strings.HasPrefix(contentType, "text/yaml") {
 		// YAML workflow directly in body
 		var err error
@@ -218,6 +245,17 @@ func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// An mcp.lock may be uploaded alongside the workflow
+		if lockFile, _, err := r.FormFile("mcp_lock"); err == nil {
+			defer lockFile.Close()
+			lock, err := io.ReadAll(lockFile)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read mcp_lock file: %v", err))
+				return
+			}
+			req.MCPLock = string(lock)
+		}
+
 		// Parse inputs from form
 		req.Inputs = make(map[string]any)
 		for key, values := range r.Form {
@@ -264,14 +302,18 @@ func (h *RunsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 		MCPDev:           mcpDev,
 		LogLevel:         logLevel,
 		DebugBreakpoints: breakpoints,
+		Priority:         priority,
+		IdempotencyKey:   r.Header.Get(IdempotencyKeyHeader),
+		MCPLock:          []byte(req.MCPLock),
+		Authorize:        authorizeSubmit(r),
 	})
 	if err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to submit run: %v", err))
+		writeSubmitError(w, err)
 		return
 	}
 
 	response := h.createRunResponse(run)
-	writeJSON(w, http.StatusAccepted, response)
+	writeJSON(w, submitStatus(w, run), response)
 }
 
 // handleList handles GET /v1/runs.
@@ -287,6 +329,16 @@ func (h *RunsHandler) handleList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	runs := h.runner.List(filter)
+
+	// Only list runs of workflows the caller may read
+	visible := runs[:0]
+	for _, run := range runs {
+		if auth.Allowed(r.Context(), auth.ActionRead, runResource(run)) {
+			visible = append(visible, run)
+		}
+	}
+	runs = visible
+
 	writeJSON(w, http.StatusOK, map[string]any{
 		"runs":  runs,
 		"count": len(runs),
@@ -306,6 +358,9 @@ func (h *RunsHandler) handleGet(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
+	if !authorizeRun(w, r, auth.ActionRead, run) {
+		return
+	}
 
 	writeJSON(w, http.StatusOK, run)
 }
@@ -323,6 +378,9 @@ func (h *RunsHandler) handleGetOutput(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
+	if !authorizeRun(w, r, auth.ActionReadOutputs, run) {
+		return
+	}
 
 	if run.Status != runner.RunStatusCompleted {
 		writeError(w, http.StatusConflict, fmt.Sprintf("run not completed (status: %s)", run.Status))
@@ -346,6 +404,9 @@ func (h *RunsHandler) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
+	if !authorizeRun(w, r, auth.ActionReadOutputs, run) {
+		return
+	}
 
 	// Check if client wants SSE streaming
 	accept := r.Header.Get("Accept")
@@ -431,13 +492,12 @@ func (h *RunsHandler) handleCancel(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "run ID required")
 		return
 	}
+	if !h.authorizeRunID(w, r, auth.ActionCancel, id) {
+		return
+	}
 
 	if err := h.runner.Cancel(id); err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			writeError(w, http.StatusNotFound, err.Error())
-		} else {
-			writeError(w, http.StatusConflict, err.Error())
-		}
+		writeRunStateError(w, err)
 		return
 	}
 
@@ -461,5 +521,104 @@ func (h *RunsHandler) createRunResponse(run *runner.RunSnapshot) map[string]inte
 		"progress":       run.Progress,
 		"started_at":     run.StartedAt,
 		"completed_at":   run.CompletedAt,
+		"priority":       run.Priority,
+		"queue_position": run.QueuePosition,
+		"failed_step":    run.FailedStep,
+	}
+}
+
+// runResource returns the RBAC resource a run belongs to.
+func runResource(run *runner.RunSnapshot) auth.Resource {
+	return auth.Resource{Workflow: run.Workflow, Workspace: run.Workspace}
+}
+
+// authorizeRun checks the caller may perform an action on a run, writing
+// 403 if not.
+func authorizeRun(w http.ResponseWriter, r *http.Request, action auth.Action, run *runner.RunSnapshot) bool {
+	if err := auth.Authorize(r.Context(), action, runResource(run)); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return false
+	}
+	return true
+}
+
+// authorizeRunID is authorizeRun for handlers that have only the run ID.
+// Unknown runs are allowed through so the handler reports them as usual.
+func (h *RunsHandler) authorizeRunID(w http.ResponseWriter, r *http.Request, action auth.Action, id string) bool {
+	return authorizeRunLookup(w, r, h.runner, action, id)
+}
+
+// RunLookup finds runs by ID so that handlers outside the runs API can
+// authorize requests against the run's workflow.
+type RunLookup interface {
+	Get(id string) (*runner.RunSnapshot, error)
+}
+
+// authorizeRunLookup is authorizeRunID for handlers with a RunLookup. A nil
+// lookup or an unknown run is allowed through.
+func authorizeRunLookup(w http.ResponseWriter, r *http.Request, runs RunLookup, action auth.Action, id string) bool {
+	if runs == nil {
+		return true
+	}
+	run, err := runs.Get(id)
+	if err != nil {
+		return true
+	}
+	return authorizeRun(w, r, action, run)
+}
+
+// runAllowed reports whether the caller may perform an action on a run,
+// for filtering lists and streams. Runs that cannot be found are only
+// visible to callers allowed the action on every workflow.
+func runAllowed(r *http.Request, runs RunLookup, action auth.Action, id string) bool {
+	if runs == nil {
+		return true
+	}
+	run, err := runs.Get(id)
+	if err != nil {
+		return auth.Allowed(r.Context(), action, auth.Resource{Workflow: "*"})
+	}
+	return auth.Allowed(r.Context(), action, runResource(run))
+}
+
+// authorizeSubmit returns a submit hook that checks the caller may run the
+// submitted workflow.
+func authorizeSubmit(r *http.Request) func(workflow, workspace string) error {
+	return func(workflow, workspace string) error {
+		return auth.Authorize(r.Context(), auth.ActionRun, auth.Resource{Workflow: workflow, Workspace: workspace})
+	}
+}
+
+// submitStatus returns the status for a submitted run: 202, or 200 with an
+// Idempotent-Replayed header if its Idempotency-Key returned an earlier run.
+func submitStatus(w http.ResponseWriter, run *runner.RunSnapshot) int {
+	if run.Replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		return http.StatusOK
+	}
+	return http.StatusAccepted
+}
+
+// writeSubmitError writes the response for a run that could not be submitted.
+// A full queue is reported as 429 with a Retry-After header, a refused
+// authorization as 403 and an Idempotency-Key reused for a different
+// request as 409.
+func writeSubmitError(w http.ResponseWriter, err error) {
+	var forbidden *auth.ForbiddenError
+	if errors.As(err, &forbidden) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	var conflict *runner.IdempotencyConflictError
+	if errors.As(err, &conflict) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	var queueFull *runner.QueueFullError
+	if errors.As(err, &queueFull) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(queueFull.RetryAfter.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
 	}
+	writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to submit run: %v", err))
 }
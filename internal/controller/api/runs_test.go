@@ -29,6 +29,7 @@ import (
 	"github.com/tombee/conductor/internal/controller/backend/memory"
 	"github.com/tombee/conductor/internal/controller/checkpoint"
 	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
 func setupTestServer(t *testing.T) (*http.ServeMux, *runner.Runner) {
@@ -121,6 +122,12 @@ outputs: []
 			wantStatus:     http.StatusBadRequest,
 			wantErrContain: "workflow_yaml field required",
 		},
+		{
+			name:        "JSON with inline workflow",
+			contentType: "application/json",
+			body:        `{"workflow_yaml": "name: json-workflow\ninputs:\n  - name: count\n    type: number\nsteps:\n  - id: step1\n    type: llm\n    prompt: test\n", "inputs": {"count": 3}}`,
+			wantStatus:  http.StatusAccepted,
+		},
 		{
 			name:           "unsupported content type",
 			contentType:    "text/plain",
@@ -465,6 +472,52 @@ outputs: []
 	}
 }
 
+func TestRunsHandler_IdempotencyKey(t *testing.T) {
+	mux, _ := setupTestServer(t)
+
+	workflow := `name: idempotent-test
+inputs:
+  - name: message
+    type: string
+steps:
+  - id: step1
+    type: llm
+    prompt: "test"
+`
+	create := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/runs"+query, strings.NewReader(workflow))
+		req.Header.Set("Content-Type", "application/x-yaml")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	runID := func(rec *httptest.ResponseRecorder) string {
+		var result map[string]any
+		json.NewDecoder(rec.Body).Decode(&result)
+		id, _ := result["id"].(string)
+		return id
+	}
+
+	first := create("?message=hello")
+	if first.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d. Body: %s", first.Code, http.StatusAccepted, first.Body.String())
+	}
+	firstID := runID(first)
+
+	repeat := create("?message=hello")
+	if repeat.Code != http.StatusOK || repeat.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("repeat got status %d, replayed %q, want 200 and true", repeat.Code, repeat.Header().Get(IdempotentReplayedHeader))
+	}
+	if id := runID(repeat); id != firstID {
+		t.Errorf("repeat returned run %s, want %s", id, firstID)
+	}
+
+	if conflict := create("?message=bye"); conflict.Code != http.StatusConflict {
+		t.Errorf("different body got status %d, want %d. Body: %s", conflict.Code, http.StatusConflict, conflict.Body.String())
+	}
+}
+
 // TestConcurrentAPIAccess tests concurrent API access to verify no race conditions.
 func TestConcurrentAPIAccess(t *testing.T) {
 	mux, _ := setupTestServer(t)
@@ -558,3 +611,111 @@ outputs: []
 
 	wg.Wait()
 }
+
+// blockingAdapter holds every run until ctx is cancelled.
+type blockingAdapter struct{}
+
+func (blockingAdapter) ExecuteWorkflow(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts runner.ExecutionOptions) (*runner.ExecutionResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRunsHandler_QueueFull(t *testing.T) {
+	r := runner.New(runner.Config{
+		MaxParallel:     1,
+		MaxQueueDepth:   1,
+		QueueRetryAfter: 90 * time.Second,
+	}, memory.New(), nil)
+	r.SetAdapter(blockingAdapter{})
+	defer r.Stop(context.Background())
+
+	mux := http.NewServeMux()
+	NewRunsHandler(r).RegisterRoutes(mux)
+
+	body := "name: queued\nsteps:\n  - id: step1\n    type: llm\n    prompt: test\n"
+	submit := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/runs?priority=batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-yaml")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The first run takes the only slot, the second waits in the queue
+	if rec := submit(); rec.Code != http.StatusAccepted {
+		t.Fatalf("first submit: got status %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := submit()
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("second submit: got status %d: %s", rec.Code, rec.Body.String())
+	}
+	var queued map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &queued); err != nil {
+		t.Fatal(err)
+	}
+	if queued["queue_position"] != float64(1) || queued["priority"] != "batch" {
+		t.Errorf("expected queued batch run at position 1, got %v", queued)
+	}
+
+	rec = submit()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want 429: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("Retry-After = %q, want 90", got)
+	}
+
+	req := httptest.NewRequest("POST", "/v1/runs?priority=urgent", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid priority: got status %d, want 400", rec.Code)
+	}
+}
+
+func TestRunsHandler_PauseResumeRetry(t *testing.T) {
+	r := runner.New(runner.Config{MaxParallel: 1}, memory.New(), nil)
+	r.SetAdapter(blockingAdapter{})
+	defer r.Stop(context.Background())
+
+	mux := http.NewServeMux()
+	NewRunsHandler(r).RegisterRoutes(mux)
+
+	do := func(method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-yaml")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		var resp map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	// The second run waits in the queue behind the first
+	body := "name: paused\nsteps:\n  - id: step1\n    type: llm\n    prompt: test\n"
+	do("POST", "/v1/runs", body)
+	_, queued := do("POST", "/v1/runs", body)
+	id, _ := queued["id"].(string)
+
+	rec, resp := do("POST", "/v1/runs/"+id+"/pause", "")
+	if rec.Code != http.StatusOK || resp["status"] != "paused" {
+		t.Fatalf("pause: got %d %v", rec.Code, resp)
+	}
+
+	rec, resp = do("POST", "/v1/runs/"+id+"/resume", "")
+	if rec.Code != http.StatusAccepted || resp["status"] != "pending" {
+		t.Fatalf("resume: got %d %v", rec.Code, resp)
+	}
+
+	// Only failed runs can have a step retried
+	rec, _ = do("POST", "/v1/runs/"+id+"/steps/step1/retry", `{"inputs": {"prompt": "again"}}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("retry of pending run: got status %d, want 409", rec.Code)
+	}
+
+	rec, _ = do("POST", "/v1/runs/missing/pause", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("pause of missing run: got status %d, want 404", rec.Code)
+	}
+}
@@ -15,8 +15,10 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/tombee/conductor/internal/controller/auth"
 	"github.com/tombee/conductor/internal/controller/scheduler"
 )
 
@@ -36,6 +38,7 @@ func (h *SchedulesHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/schedules/{name}", h.handleGet)
 	mux.HandleFunc("POST /v1/schedules/{name}/enable", h.handleEnable)
 	mux.HandleFunc("POST /v1/schedules/{name}/disable", h.handleDisable)
+	mux.HandleFunc("POST /v1/schedules/{name}/run", h.handleRunNow)
 }
 
 // handleList returns all schedules.
@@ -47,34 +50,53 @@ func (h *SchedulesHandler) handleList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	statuses := h.scheduler.GetStatus()
+	statuses := []scheduler.ScheduleStatus{}
+	for _, status := range h.scheduler.GetStatus() {
+		if auth.Allowed(r.Context(), auth.ActionRead, auth.Resource{Workflow: status.Workflow}) {
+			statuses = append(statuses, status)
+		}
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
 		"schedules": statuses,
 	})
 }
 
-// handleGet returns a specific schedule.
-func (h *SchedulesHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+// authorizedSchedule returns the named schedule if the caller may perform
+// an action on its workflow, and otherwise writes the error response.
+func (h *SchedulesHandler) authorizedSchedule(w http.ResponseWriter, r *http.Request, action auth.Action) (*scheduler.Schedule, bool) {
 	name := r.PathValue("name")
 	if name == "" {
 		writeError(w, http.StatusBadRequest, "schedule name required")
-		return
+		return nil, false
 	}
 
 	if h.scheduler == nil {
 		writeError(w, http.StatusNotFound, "schedule not found")
-		return
+		return nil, false
 	}
 
 	sched, ok := h.scheduler.GetSchedule(name)
 	if !ok {
 		writeError(w, http.StatusNotFound, "schedule not found")
+		return nil, false
+	}
+	if err := auth.Authorize(r.Context(), action, auth.Resource{Workflow: sched.Workflow}); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return nil, false
+	}
+	return sched, true
+}
+
+// handleGet returns a specific schedule.
+func (h *SchedulesHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	sched, ok := h.authorizedSchedule(w, r, auth.ActionRead)
+	if !ok {
 		return
 	}
 
 	statuses := h.scheduler.GetStatus()
 	for _, status := range statuses {
-		if status.Name == name {
+		if status.Name == sched.Name {
 			writeJSON(w, http.StatusOK, status)
 			return
 		}
@@ -84,6 +106,7 @@ func (h *SchedulesHandler) handleGet(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{
 		"name":     sched.Name,
 		"cron":     sched.Cron,
+		"at":       sched.At,
 		"workflow": sched.Workflow,
 		"enabled":  sched.Enabled,
 	})
@@ -91,18 +114,12 @@ func (h *SchedulesHandler) handleGet(w http.ResponseWriter, r *http.Request) {
 
 // handleEnable enables a schedule.
 func (h *SchedulesHandler) handleEnable(w http.ResponseWriter, r *http.Request) {
-	name := r.PathValue("name")
-	if name == "" {
-		writeError(w, http.StatusBadRequest, "schedule name required")
-		return
-	}
-
-	if h.scheduler == nil {
-		writeError(w, http.StatusNotFound, "schedule not found")
+	sched, ok := h.authorizedSchedule(w, r, auth.ActionManageTriggers)
+	if !ok {
 		return
 	}
 
-	if err := h.scheduler.SetEnabled(name, true); err != nil {
+	if err := h.scheduler.SetEnabled(sched.Name, true); err != nil {
 		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -115,18 +132,12 @@ func (h *SchedulesHandler) handleEnable(w http.ResponseWriter, r *http.Request)
 
 // handleDisable disables a schedule.
 func (h *SchedulesHandler) handleDisable(w http.ResponseWriter, r *http.Request) {
-	name := r.PathValue("name")
-	if name == "" {
-		writeError(w, http.StatusBadRequest, "schedule name required")
-		return
-	}
-
-	if h.scheduler == nil {
-		writeError(w, http.StatusNotFound, "schedule not found")
+	sched, ok := h.authorizedSchedule(w, r, auth.ActionManageTriggers)
+	if !ok {
 		return
 	}
 
-	if err := h.scheduler.SetEnabled(name, false); err != nil {
+	if err := h.scheduler.SetEnabled(sched.Name, false); err != nil {
 		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -136,3 +147,28 @@ func (h *SchedulesHandler) handleDisable(w http.ResponseWriter, r *http.Request)
 		"message": "Schedule disabled",
 	})
 }
+
+// handleRunNow starts a run of a schedule's workflow immediately. The run
+// counts towards the schedule's history but does not move its next run.
+func (h *SchedulesHandler) handleRunNow(w http.ResponseWriter, r *http.Request) {
+	sched, ok := h.authorizedSchedule(w, r, auth.ActionRun)
+	if !ok {
+		return
+	}
+
+	run, err := h.scheduler.RunNow(r.Context(), sched.Name)
+	switch {
+	case errors.Is(err, scheduler.ErrScheduleNotFound):
+		writeError(w, http.StatusNotFound, "schedule not found")
+		return
+	case errors.Is(err, scheduler.ErrDraining):
+		w.Header().Set("Retry-After", "10")
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, run)
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/tombee/conductor/internal/controller/auth"
@@ -95,21 +96,26 @@ func (h *StartHandler) handleStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify workflow has listen.api configured
-	if def.Trigger == nil || def.Trigger.API == nil {
+	// Verify workflow has an API trigger configured
+	apiTriggers := def.TriggersOfType(workflow.TriggerTypeAPI)
+	if len(apiTriggers) == 0 {
 		// Return 404 to prevent enumeration of workflows without API access
 		writeError(w, http.StatusNotFound, "workflow not found or not available via API")
 		return
 	}
 
-	// Extract secret (expand environment variables if needed)
-	secret := def.Trigger.API.Secret
-	if strings.HasPrefix(secret, "${") && strings.HasSuffix(secret, "}") {
-		envVar := strings.TrimSuffix(strings.TrimPrefix(secret, "${"), "}")
-		secret = os.Getenv(envVar)
+	// Extract secrets (expand environment variables if needed)
+	secrets := make([]string, 0, len(apiTriggers))
+	for _, t := range apiTriggers {
+		secret := t.API.Secret
+		if strings.HasPrefix(secret, "${") && strings.HasSuffix(secret, "}") {
+			envVar := strings.TrimSuffix(strings.TrimPrefix(secret, "${"), "}")
+			secret = os.Getenv(envVar)
+		}
+		secrets = append(secrets, secret)
 	}
 
-	if secret == "" {
+	if !slices.ContainsFunc(secrets, func(secret string) bool { return secret != "" }) {
 		writeError(w, http.StatusInternalServerError, "workflow API secret not configured")
 		return
 	}
@@ -138,9 +144,17 @@ func (h *StartHandler) handleStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify the Bearer token against the workflow's secret
+	// Verify the Bearer token against the secrets of the workflow's API
+	// triggers. The trigger whose secret matches started the run.
 	authenticator := auth.NewBearerAuthenticator()
-	if !authenticator.VerifyToken(token, secret) {
+	var apiTrigger *workflow.TriggerConfig
+	for i, secret := range secrets {
+		if secret != "" && authenticator.VerifyToken(token, secret) {
+			apiTrigger = apiTriggers[i]
+			break
+		}
+	}
+	if apiTrigger == nil {
 		writeError(w, http.StatusUnauthorized, "invalid Bearer token")
 		return
 	}
@@ -167,17 +181,27 @@ func (h *StartHandler) handleStart(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if inputs == nil {
+		inputs = make(map[string]any)
+	}
+	inputs["trigger"] = map[string]any{
+		"type": string(workflow.TriggerTypeAPI),
+		"id":   apiTrigger.TriggerID(),
+	}
+
 	// Submit the workflow
 	run, err := h.runner.Submit(r.Context(), runner.SubmitRequest{
-		WorkflowYAML: workflowYAML,
-		Inputs:       inputs,
+		WorkflowYAML:   workflowYAML,
+		Inputs:         inputs,
+		IdempotencyKey: r.Header.Get(IdempotencyKeyHeader),
+		Authorize:      authorizeSubmit(r),
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to submit workflow: %v", err))
+		writeSubmitError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusAccepted, map[string]any{
+	writeJSON(w, submitStatus(w, run), map[string]any{
 		"id":       run.ID,
 		"workflow": run.Workflow,
 		"status":   run.Status,
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/tombee/conductor/internal/controller/auth"
 	"github.com/tombee/conductor/internal/tracing/storage"
 	"github.com/tombee/conductor/pkg/observability"
 )
@@ -26,6 +27,7 @@ import (
 // TracesHandler provides HTTP handlers for trace access.
 type TracesHandler struct {
 	store *storage.SQLiteStore
+	runs  RunLookup
 }
 
 // NewTracesHandler creates a new traces API handler.
@@ -35,6 +37,12 @@ func NewTracesHandler(store *storage.SQLiteStore) *TracesHandler {
 	}
 }
 
+// SetRuns sets the run lookup used to limit traces to the workflows the
+// caller may read.
+func (h *TracesHandler) SetRuns(runs RunLookup) {
+	h.runs = runs
+}
+
 // RegisterRoutes registers trace API routes on the provided router.
 func (h *TracesHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/traces", h.ListTraces)
@@ -89,12 +97,19 @@ func (h *TracesHandler) ListTraces(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute query
-	traces, err := h.store.ListTraces(ctx, filter)
+	traceIDs, err := h.store.ListTraces(ctx, filter)
 	if err != nil {
 		http.Error(w, "failed to list traces", http.StatusInternalServerError)
 		return
 	}
 
+	traces := make([]string, 0, len(traceIDs))
+	for _, traceID := range traceIDs {
+		if h.traceAllowed(r, traceID) {
+			traces = append(traces, traceID)
+		}
+	}
+
 	// Return results
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -120,7 +135,7 @@ func (h *TracesHandler) GetTrace(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(spans) == 0 {
+	if len(spans) == 0 || !spansAllowed(r, h.runs, spans) {
 		http.Error(w, "trace not found", http.StatusNotFound)
 		return
 	}
@@ -150,6 +165,10 @@ func (h *TracesHandler) GetTraceSpans(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to get spans", http.StatusInternalServerError)
 		return
 	}
+	if !spansAllowed(r, h.runs, spans) {
+		http.Error(w, "trace not found", http.StatusNotFound)
+		return
+	}
 
 	// Return spans
 	w.Header().Set("Content-Type", "application/json")
@@ -170,6 +189,9 @@ func (h *TracesHandler) GetRunTrace(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "run ID is required", http.StatusBadRequest)
 		return
 	}
+	if !authorizeRunLookup(w, r, h.runs, auth.ActionRead, runID) {
+		return
+	}
 
 	// Look up trace ID by run ID
 	traceID, err := h.store.GetTraceByRunID(ctx, runID)
@@ -200,6 +222,35 @@ func (h *TracesHandler) GetRunTrace(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// traceAllowed reports whether the caller may read a trace.
+func (h *TracesHandler) traceAllowed(r *http.Request, traceID string) bool {
+	if _, ok := auth.UserFromContext(r.Context()); !ok {
+		return true
+	}
+	spans, err := h.store.GetTraceSpans(r.Context(), traceID)
+	if err != nil {
+		return false
+	}
+	return spansAllowed(r, h.runs, spans)
+}
+
+// spansAllowed reports whether the caller may read the trace made of spans,
+// by the run or workflow recorded in their attributes. Traces that name
+// neither are only visible to callers who may read every workflow.
+func spansAllowed(r *http.Request, runs RunLookup, spans []*observability.Span) bool {
+	for _, span := range spans {
+		if runID, ok := span.Attributes["run_id"].(string); ok && runID != "" {
+			return runAllowed(r, runs, auth.ActionRead, runID)
+		}
+	}
+	for _, span := range spans {
+		if name, ok := span.Attributes["workflow.name"].(string); ok && name != "" {
+			return auth.Allowed(r.Context(), auth.ActionRead, auth.Resource{Workflow: name})
+		}
+	}
+	return auth.Allowed(r.Context(), auth.ActionRead, auth.Resource{Workflow: "*"})
+}
+
 // TraceResponse represents a trace with metadata.
 type TraceResponse struct {
 	TraceID   string                `json:"trace_id"`
@@ -114,15 +114,18 @@ func (h *TriggerHandler) handleTrigger(w http.ResponseWriter, r *http.Request) {
 
 	// Submit the workflow
 	run, err := h.runner.Submit(r.Context(), runner.SubmitRequest{
-		WorkflowYAML: workflowYAML,
-		Inputs:       inputs,
+		WorkflowYAML:   workflowYAML,
+		Inputs:         inputs,
+		Priority:       runner.PriorityTrigger,
+		IdempotencyKey: r.Header.Get(IdempotencyKeyHeader),
+		Authorize:      authorizeSubmit(r),
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to submit workflow: %v", err))
+		writeSubmitError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusAccepted, map[string]any{
+	writeJSON(w, submitStatus(w, run), map[string]any{
 		"id":       run.ID,
 		"workflow": run.Workflow,
 		"status":   run.Status,
@@ -0,0 +1,158 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/trigger"
+)
+
+// defaultTriggerEventLimit caps events returned without a limit parameter.
+const defaultTriggerEventLimit = 100
+
+// TriggerEventsHandler serves recorded webhook and poll trigger events and
+// redelivers failed ones.
+type TriggerEventsHandler struct {
+	store  backend.TriggerEventStore
+	events *trigger.EventLog
+}
+
+// NewTriggerEventsHandler creates a new trigger events handler. store is nil
+// if the backend does not record trigger events.
+func NewTriggerEventsHandler(store backend.TriggerEventStore, events *trigger.EventLog) *TriggerEventsHandler {
+	return &TriggerEventsHandler{store: store, events: events}
+}
+
+// RegisterRoutes registers trigger event routes on the router.
+func (h *TriggerEventsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/triggers/events", h.handleList)
+	mux.HandleFunc("GET /v1/triggers/events/{id}", h.handleGet)
+	mux.HandleFunc("POST /v1/triggers/events/{id}/redeliver", h.handleRedeliver)
+}
+
+// handleList handles GET /v1/triggers/events.
+// Query parameters: status, workflow, source and limit.
+func (h *TriggerEventsHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		writeError(w, http.StatusNotImplemented, "backend does not record trigger events")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := backend.TriggerEventFilter{
+		Status:   query.Get("status"),
+		Workflow: query.Get("workflow"),
+		Source:   query.Get("source"),
+		Limit:    defaultTriggerEventLimit,
+	}
+	if s := query.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		filter.Limit = n
+	}
+
+	events, err := h.store.ListTriggerEvents(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Only list events for workflows the caller may read
+	visible := make([]*backend.TriggerEvent, 0, len(events))
+	for _, event := range events {
+		if auth.Allowed(r.Context(), auth.ActionRead, auth.Resource{Workflow: event.Workflow}) {
+			visible = append(visible, event)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"events": visible,
+		"count":  len(visible),
+	})
+}
+
+// handleGet handles GET /v1/triggers/events/{id}.
+func (h *TriggerEventsHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.getEvent(w, r, auth.ActionRead)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, event)
+}
+
+// handleRedeliver handles POST /v1/triggers/events/{id}/redeliver.
+// The event's workflow is loaded again and a new run started with the
+// event's inputs.
+func (h *TriggerEventsHandler) handleRedeliver(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.getEvent(w, r, auth.ActionRun); !ok {
+		return
+	}
+
+	event, err := h.events.Redeliver(r.Context(), r.PathValue("id"))
+	switch {
+	case errors.Is(err, trigger.ErrNotRedeliverable):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, trigger.ErrDraining):
+		w.Header().Set("Retry-After", "10")
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil && event == nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	case err != nil:
+		// The event stays failed and can be redelivered again
+		writeJSON(w, http.StatusBadGateway, map[string]string{
+			"error":    err.Error(),
+			"event_id": event.ID,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, event)
+}
+
+// getEvent loads the event named in the path and checks the caller may act
+// on its workflow. It writes an error response and returns false otherwise.
+func (h *TriggerEventsHandler) getEvent(w http.ResponseWriter, r *http.Request, action auth.Action) (*backend.TriggerEvent, bool) {
+	if h.store == nil {
+		writeError(w, http.StatusNotImplemented, "backend does not record trigger events")
+		return nil, false
+	}
+
+	event, err := h.store.GetTriggerEvent(r.Context(), r.PathValue("id"))
+	if errors.Is(err, backend.ErrTriggerEventNotFound) {
+		writeError(w, http.StatusNotFound, "trigger event not found")
+		return nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if err := auth.Authorize(r.Context(), action, auth.Resource{Workflow: event.Workflow}); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return nil, false
+	}
+	return event, true
+}
@@ -20,6 +20,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/tombee/conductor/internal/controller/auth"
 	"github.com/tombee/conductor/internal/triggers"
 )
 
@@ -42,6 +43,9 @@ func (h *TriggerManagementHandler) HandleCreateWebhook(w http.ResponseWriter, r
 		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if !authorizeTrigger(w, r, req.Workflow) {
+		return
+	}
 
 	ctx := r.Context()
 	if err := h.manager.AddWebhook(ctx, req); err != nil {
@@ -68,7 +72,7 @@ func (h *TriggerManagementHandler) HandleListWebhooks(w http.ResponseWriter, r *
 		return
 	}
 
-	writeJSON(w, http.StatusOK, webhooks)
+	writeJSON(w, http.StatusOK, visibleTriggers(r, webhooks, func(t triggers.WebhookTrigger) string { return t.Workflow }))
 }
 
 // HandleDeleteWebhook handles DELETE /v1/triggers/webhooks/{path...}.
@@ -84,6 +88,17 @@ func (h *TriggerManagementHandler) HandleDeleteWebhook(w http.ResponseWriter, r
 	path = "/" + path
 
 	ctx := r.Context()
+	webhooks, err := h.manager.ListWebhooks(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to remove webhook")
+		return
+	}
+	for _, webhook := range webhooks {
+		if webhook.Path == path && !authorizeTrigger(w, r, webhook.Workflow) {
+			return
+		}
+	}
+
 	if err := h.manager.RemoveWebhook(ctx, path); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			writeError(w, http.StatusNotFound, err.Error())
@@ -103,6 +118,9 @@ func (h *TriggerManagementHandler) HandleCreateSchedule(w http.ResponseWriter, r
 		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if !authorizeTrigger(w, r, req.Workflow) {
+		return
+	}
 
 	ctx := r.Context()
 	if err := h.manager.AddSchedule(ctx, req); err != nil {
@@ -128,7 +146,7 @@ func (h *TriggerManagementHandler) HandleListSchedules(w http.ResponseWriter, r
 		return
 	}
 
-	writeJSON(w, http.StatusOK, schedules)
+	writeJSON(w, http.StatusOK, visibleTriggers(r, schedules, func(t triggers.ScheduleTrigger) string { return t.Workflow }))
 }
 
 // HandleDeleteSchedule handles DELETE /v1/triggers/schedules/{name}.
@@ -136,6 +154,17 @@ func (h *TriggerManagementHandler) HandleDeleteSchedule(w http.ResponseWriter, r
 	name := r.PathValue("name")
 
 	ctx := r.Context()
+	schedules, err := h.manager.ListSchedules(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to remove schedule")
+		return
+	}
+	for _, schedule := range schedules {
+		if schedule.Name == name && !authorizeTrigger(w, r, schedule.Workflow) {
+			return
+		}
+	}
+
 	if err := h.manager.RemoveSchedule(ctx, name); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			writeError(w, http.StatusNotFound, err.Error())
@@ -155,6 +184,9 @@ func (h *TriggerManagementHandler) HandleCreateEndpoint(w http.ResponseWriter, r
 		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if !authorizeTrigger(w, r, req.Workflow) {
+		return
+	}
 
 	ctx := r.Context()
 	if err := h.manager.AddEndpoint(ctx, req); err != nil {
@@ -181,7 +213,7 @@ func (h *TriggerManagementHandler) HandleListEndpoints(w http.ResponseWriter, r
 		return
 	}
 
-	writeJSON(w, http.StatusOK, endpoints)
+	writeJSON(w, http.StatusOK, visibleTriggers(r, endpoints, func(t triggers.EndpointTrigger) string { return t.Workflow }))
 }
 
 // HandleDeleteEndpoint handles DELETE /v1/triggers/endpoints/{name}.
@@ -189,6 +221,17 @@ func (h *TriggerManagementHandler) HandleDeleteEndpoint(w http.ResponseWriter, r
 	name := r.PathValue("name")
 
 	ctx := r.Context()
+	endpoints, err := h.manager.ListEndpoints(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to remove endpoint")
+		return
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.Name == name && !authorizeTrigger(w, r, endpoint.Workflow) {
+			return
+		}
+	}
+
 	if err := h.manager.RemoveEndpoint(ctx, name); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			writeError(w, http.StatusNotFound, err.Error())
@@ -224,14 +267,35 @@ func (h *TriggerManagementHandler) HandleListAll(w http.ResponseWriter, r *http.
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
-		"webhooks":  webhooks,
-		"schedules": schedules,
-		"endpoints": endpoints,
+		"webhooks":  visibleTriggers(r, webhooks, func(t triggers.WebhookTrigger) string { return t.Workflow }),
+		"schedules": visibleTriggers(r, schedules, func(t triggers.ScheduleTrigger) string { return t.Workflow }),
+		"endpoints": visibleTriggers(r, endpoints, func(t triggers.EndpointTrigger) string { return t.Workflow }),
 	})
 }
 
 // Helper functions
 
+// authorizeTrigger checks the caller may manage the triggers of a workflow,
+// and writes a 403 response if not.
+func authorizeTrigger(w http.ResponseWriter, r *http.Request, workflow string) bool {
+	if err := auth.Authorize(r.Context(), auth.ActionManageTriggers, auth.Resource{Workflow: workflow}); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return false
+	}
+	return true
+}
+
+// visibleTriggers returns the triggers whose workflow the caller may read.
+func visibleTriggers[T any](r *http.Request, all []T, workflow func(T) string) []T {
+	visible := make([]T, 0, len(all))
+	for _, t := range all {
+		if auth.Allowed(r.Context(), auth.ActionRead, auth.Resource{Workflow: workflow(t)}) {
+			visible = append(visible, t)
+		}
+	}
+	return visible
+}
+
 func getWebhookURLFromRequest(r *http.Request, path string) string {
 	scheme := "https"
 	if r.TLS == nil {
@@ -17,12 +17,15 @@ package api
 import (
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/tombee/conductor/internal/controller/backend"
 	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/internal/controller/trigger"
 	"github.com/tombee/conductor/internal/controller/webhook"
 	"github.com/tombee/conductor/pkg/workflow"
 )
@@ -33,24 +36,40 @@ const maxWebhookBodySize = 10 * 1024 * 1024 // 10MB
 // Unlike the control plane webhook handler, this enforces per-workflow
 // signature verification based on listen.webhook.secret configuration.
 type WebhookHandler struct {
-	runner       *runner.Runner
 	workflowsDir string
-	handlers     map[string]webhook.Handler
+	sources      *webhook.Registry
+	events       *trigger.EventLog
+	failures     *webhook.SignatureFailures
+	logger       *slog.Logger
 }
 
 // NewWebhookHandler creates a new webhook handler for the public API.
 func NewWebhookHandler(r *runner.Runner, workflowsDir string) *WebhookHandler {
+	logger := slog.Default().With(slog.String("component", "webhook"))
 	return &WebhookHandler{
-		runner:       r,
 		workflowsDir: workflowsDir,
-		handlers: map[string]webhook.Handler{
-			"github":  &webhook.GitHubHandler{},
-			"slack":   &webhook.SlackHandler{},
-			"generic": &webhook.GenericHandler{},
-		},
+		sources:      webhook.NewRegistry(),
+		failures:     webhook.NewSignatureFailures(logger),
+		logger:       logger,
+		events: trigger.NewEventLog(trigger.EventLogConfig{
+			Submitter:    r,
+			WorkflowsDir: workflowsDir,
+			Logger:       logger,
+		}),
 	}
 }
 
+// SetEventLog sets the event log that records and delivers webhook events.
+func (h *WebhookHandler) SetEventLog(events *trigger.EventLog) {
+	h.events = events
+}
+
+// SetSources sets the webhook source handlers used to verify and parse
+// requests.
+func (h *WebhookHandler) SetSources(sources *webhook.Registry) {
+	h.sources = sources
+}
+
 // RegisterRoutes registers webhook routes on the public API mux.
 func (h *WebhookHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /webhooks/{source}/{workflow}", h.handleWebhook)
@@ -60,13 +79,6 @@ func (h *WebhookHandler) RegisterRoutes(mux *http.ServeMux) {
 // Loads the workflow, verifies it has listen.webhook configured,
 // verifies the signature, and triggers the workflow.
 func (h *WebhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
-	// Check if runner is draining
-	if h.runner.IsDraining() {
-		w.Header().Set("Retry-After", "10")
-		writeError(w, http.StatusServiceUnavailable, "controller is shutting down gracefully")
-		return
-	}
-
 	source := r.PathValue("source")
 	workflowName := r.PathValue("workflow")
 
@@ -83,10 +95,7 @@ func (h *WebhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get handler for source type
-	handler, ok := h.handlers[source]
-	if !ok {
-		handler = h.handlers["generic"]
-	}
+	handler := h.sources.Handler(source)
 
 	// Read body (limit to 10MB for webhooks)
 	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
@@ -116,14 +125,15 @@ func (h *WebhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify workflow has listen.webhook configured
-	if def.Trigger == nil || def.Trigger.Webhook == nil {
+	// Verify workflow has a webhook trigger configured
+	webhookTrigger := findWebhookTrigger(def, source, r.URL.Query().Get("trigger"))
+	if webhookTrigger == nil {
 		// Return 404 to prevent enumeration of workflows without webhook listeners
 		writeError(w, http.StatusNotFound, "webhook not found or not available")
 		return
 	}
 
-	webhookConfig := def.Trigger.Webhook
+	webhookConfig := webhookTrigger.Webhook
 
 	// Expand secret from environment if needed
 	secret := webhookConfig.Secret
@@ -132,45 +142,100 @@ func (h *WebhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		secret = os.Getenv(envVar)
 	}
 
-	// Verify signature if secret is configured
+	event := &backend.TriggerEvent{
+		Source:         trigger.SourceWebhook,
+		Trigger:        r.URL.Path,
+		Workflow:       workflowName,
+		IdempotencyKey: webhook.IdempotencyKey(r),
+		Headers:        trigger.RecordedHeaders(r.Header),
+	}
+
+	// Verify signature if secret is configured. Verify reserves the request
+	// against replays; the reservation is released if its event cannot be
+	// accepted, so the sender can retry
 	if secret != "" {
 		if err := handler.Verify(r, body, secret); err != nil {
+			h.failures.Record(r.URL.Path, source, err)
 			writeError(w, http.StatusUnauthorized, "webhook signature verification failed")
 			return
 		}
 	}
 
 	// Parse event type
-	event := handler.ParseEvent(r)
+	event.Event = handler.ParseEvent(r)
+
+	// Extract payload
+	payload, err := handler.ExtractPayload(body)
+	if err != nil {
+		event.Payload = webhook.RawPayloadJSON(body)
+		event.Status = backend.TriggerEventRejected
+		event.Error = fmt.Sprintf("failed to parse payload: %v", err)
+		h.events.Record(r.Context(), event)
+		webhook.Release(handler, r, body, secret)
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse payload: %v", err))
+		return
+	}
+	event.Payload = webhook.PayloadJSON(payload)
+	event.Event = webhook.EventType(handler, event.Event, payload)
 
 	// Check if event is allowed (if events filter is configured)
-	if len(webhookConfig.Events) > 0 && !contains(webhookConfig.Events, event) {
+	if len(webhookConfig.Events) > 0 && !contains(webhookConfig.Events, event.Event) {
+		message := fmt.Sprintf("event %s not configured for this webhook", event.Event)
+		event.Status = backend.TriggerEventIgnored
+		event.Error = message
+		h.events.Record(r.Context(), event)
 		writeJSON(w, http.StatusOK, map[string]string{
 			"status":  "ignored",
-			"message": fmt.Sprintf("event %s not configured for this webhook", event),
+			"message": message,
 		})
 		return
 	}
 
-	// Extract payload
-	payload, err := handler.ExtractPayload(body)
+	// Skip requests the trigger's filter does not match
+	matched, err := webhook.Match(r.Context(), webhookConfig.Filter, payload, r.Header, event.Event, source)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse payload: %v", err))
+		h.logger.Warn("Webhook filter failed",
+			slog.String("path", r.URL.Path),
+			slog.String("workflow", workflowName),
+			slog.Any("error", err),
+		)
+	}
+	if !matched {
+		h.events.Record(r.Context(), webhook.SkippedEvent(event, err))
+		webhook.WriteSkipped(w)
 		return
 	}
 
 	// Create inputs from payload
 	inputs := map[string]any{
-		"_event":   event,
+		"_event":   event.Event,
 		"_source":  source,
 		"_payload": payload,
 	}
 
-	// Apply input mapping if configured
+	// Apply input mapping if configured. Values that are not jq: queries or
+	// $. paths name a top-level payload field
 	if webhookConfig.InputMapping != nil {
 		for key, mapping := range webhookConfig.InputMapping {
-			// Simple mapping support - could be extended with JSONPath
-			if val, ok := payload[mapping]; ok {
+			val, ok, err := webhook.MapInput(r.Context(), mapping, payload)
+			if err != nil {
+				h.logger.Warn("Webhook input mapping failed",
+					slog.String("path", r.URL.Path),
+					slog.String("workflow", workflowName),
+					slog.String("input", key),
+					slog.Any("error", err),
+				)
+				event.Status = backend.TriggerEventRejected
+				event.Error = err.Error()
+				h.events.Record(r.Context(), event)
+				webhook.Release(handler, r, body, secret)
+				writeError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+			if !ok {
+				val, ok = payload[mapping]
+			}
+			if ok && val != nil {
 				inputs[key] = val
 			}
 		}
@@ -180,23 +245,50 @@ func (h *WebhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 			inputs[k] = v
 		}
 	}
+	inputs["trigger"] = map[string]any{
+		"type":   string(workflow.TriggerTypeWebhook),
+		"id":     webhookTrigger.TriggerID(),
+		"source": source,
+		"event":  event.Event,
+	}
+	event.Inputs = inputs
 
 	// Submit workflow
-	run, err := h.runner.Submit(r.Context(), runner.SubmitRequest{
-		WorkflowYAML: workflowYAML,
-		Inputs:       inputs,
-	})
+	stored, duplicate, err := h.events.Deliver(r.Context(), event)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to trigger workflow: %v", err))
-		return
+		webhook.Release(handler, r, body, secret)
 	}
+	webhook.WriteDelivery(w, stored, duplicate, err)
+}
 
-	writeJSON(w, http.StatusAccepted, map[string]any{
-		"status":   "triggered",
-		"run_id":   run.ID,
-		"workflow": run.Workflow,
-		"event":    event,
-	})
+// findWebhookTrigger returns the workflow's webhook trigger for a request:
+// the one with the given ID if set, else the first for the source, else the
+// first without a source, else the first.
+func findWebhookTrigger(def *workflow.Definition, source, id string) *workflow.TriggerConfig {
+	triggers := def.TriggersOfType(workflow.TriggerTypeWebhook)
+	if id != "" {
+		for _, t := range triggers {
+			if t.TriggerID() == id {
+				return t
+			}
+		}
+		return nil
+	}
+
+	for _, t := range triggers {
+		if t.Webhook.Source == source {
+			return t
+		}
+	}
+	for _, t := range triggers {
+		if t.Webhook.Source == "" {
+			return t
+		}
+	}
+	if len(triggers) > 0 {
+		return triggers[0]
+	}
+	return nil
 }
 
 // findWorkflow looks for a workflow file by name.
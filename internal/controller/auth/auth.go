@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"path"
 	"strings"
 	"sync"
 	"time"
@@ -43,6 +44,7 @@ type User struct {
 	ID     string
 	Name   string
 	Scopes []string
+	Roles  []string // RBAC roles carried by the credentials
 }
 
 // UserFromContext extracts the authenticated user from the request context.
@@ -68,9 +70,21 @@ type Config struct {
 	// AllowUnixSocket allows unauthenticated access via Unix socket.
 	AllowUnixSocket bool
 
+	// PublicPathPrefixes lists URL paths served without authentication,
+	// along with everything beneath them. Only use it for static assets
+	// that contain no data, such as the dashboard.
+	PublicPathPrefixes []string
+
+	// Authorizer enforces role-based access control on authenticated
+	// requests. Nil disables RBAC.
+	Authorizer *Authorizer
+
 	// JWT contains JWT authentication configuration.
 	JWT *JWTConfig
 
+	// OIDC validates bearer tokens issued by an OpenID Connect provider.
+	OIDC *OIDCVerifier
+
 	// RateLimit contains rate limiting configuration.
 	RateLimit RateLimitConfig
 
@@ -97,6 +111,9 @@ type APIKey struct {
 
 	// Scopes limits what the key can access (empty means all).
 	Scopes []string `json:"scopes,omitempty"`
+
+	// Roles are RBAC roles granted to the key.
+	Roles []string `json:"roles,omitempty"`
 }
 
 // Middleware provides authentication middleware.
@@ -128,6 +145,20 @@ func NewMiddleware(cfg Config) *Middleware {
 	return m
 }
 
+// isPublicPath reports whether a path is one of the public path prefixes or
+// lies beneath one. The path is cleaned first so that "/ui/../v1/runs" is
+// not public.
+func (m *Middleware) isPublicPath(p string) bool {
+	cleaned := path.Clean("/" + p)
+	for _, prefix := range m.config.PublicPathPrefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if cleaned == prefix || strings.HasPrefix(cleaned, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // Wrap wraps an http.Handler with authentication.
 func (m *Middleware) Wrap(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -156,8 +187,13 @@ func (m *Middleware) Wrap(next http.Handler) http.Handler {
 			return
 		}
 
-		// Skip auth for health endpoint
-		if r.URL.Path == "/v1/health" {
+		// Skip auth for health endpoint and the login discovery endpoint
+		if r.URL.Path == "/v1/health" || r.URL.Path == OIDCInfoPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if m.isPublicPath(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -177,8 +213,18 @@ func (m *Middleware) Wrap(next http.Handler) http.Handler {
 
 		var user *User
 
-		// Try JWT validation first (if configured)
-		if m.config.JWT != nil && strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		// Try OIDC validation first (if configured)
+		if m.config.OIDC != nil && strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			oidcUser, err := m.config.OIDC.Verify(r.Context(), token)
+			if err == nil {
+				user = oidcUser
+			} else if m.logger != nil {
+				m.logger.Debug("OIDC token rejected", "error", err)
+			}
+		}
+
+		// Then JWT validation (if configured)
+		if user == nil && m.config.JWT != nil && strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
 			claims, err := ValidateJWT(token, *m.config.JWT)
 			if err == nil {
 				// Valid JWT token
@@ -186,6 +232,7 @@ func (m *Middleware) Wrap(next http.Handler) http.Handler {
 					ID:     claims.UserID,
 					Name:   claims.Subject,
 					Scopes: claims.Scopes,
+					Roles:  claims.Roles,
 				}
 			}
 		}
@@ -208,6 +255,7 @@ func (m *Middleware) Wrap(next http.Handler) http.Handler {
 				ID:     key.Name,
 				Name:   key.Name,
 				Scopes: key.Scopes,
+				Roles:  key.Roles,
 			}
 		}
 
@@ -224,6 +272,20 @@ func (m *Middleware) Wrap(next http.Handler) http.Handler {
 
 		// Add user info to request context
 		ctx := context.WithValue(r.Context(), userContextKey, user)
+
+		// Check the user's roles allow the request; handlers check the
+		// specific workflow once they have loaded it
+		if a := m.config.Authorizer; a != nil {
+			ctx = context.WithValue(ctx, authorizerContextKey, a)
+			ctx = context.WithValue(ctx, requestInfoContextKey, requestInfo{path: r.URL.Path, ipAddress: r.RemoteAddr})
+			if action, res := RequestAction(r.Method, r.URL.Path); action != "" {
+				if err := a.Authorize(ctx, user, action, res); err != nil {
+					m.forbidden(w, err.Error())
+					return
+				}
+			}
+		}
+
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -274,6 +336,15 @@ func (m *Middleware) unauthorized(w http.ResponseWriter, message string) {
 	})
 }
 
+// forbidden sends a forbidden response.
+func (m *Middleware) forbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+	})
+}
+
 // AddKey adds a new API key.
 func (m *Middleware) AddKey(key APIKey) {
 	m.mu.Lock()
@@ -312,6 +383,7 @@ func (m *Middleware) ListKeys() []APIKey {
 			CreatedAt: key.CreatedAt,
 			ExpiresAt: key.ExpiresAt,
 			Scopes:    key.Scopes,
+			Roles:     key.Roles,
 		}
 	}
 	return result
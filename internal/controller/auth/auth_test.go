@@ -166,6 +166,39 @@ func TestMiddleware_HealthEndpointBypass(t *testing.T) {
 	}
 }
 
+func TestMiddleware_PublicPathPrefixes(t *testing.T) {
+	m := NewMiddleware(Config{
+		Enabled:            true,
+		APIKeys:            []APIKey{{Key: "secret"}},
+		PublicPathPrefixes: []string{"/ui/"},
+	})
+
+	handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/ui", http.StatusOK},
+		{"/ui/", http.StatusOK},
+		{"/ui/app.js", http.StatusOK},
+		{"/ui/../v1/runs", http.StatusUnauthorized},
+		{"/uix", http.StatusUnauthorized},
+		{"/v1/runs", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.URL.Path = tt.path
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.want, rec.Code)
+		}
+	}
+}
+
 func TestMiddleware_QueryParameterRejected(t *testing.T) {
 	m := NewMiddleware(Config{
 		Enabled: true,
@@ -51,6 +51,8 @@ type Claims struct {
 	UserID string `json:"user_id,omitempty"`
 	// Scopes defines what the token can access.
 	Scopes []string `json:"scopes,omitempty"`
+	// Roles are RBAC roles granted to the token's user.
+	Roles []string `json:"roles,omitempty"`
 }
 
 // ValidateJWT validates a JWT token and returns the claims.
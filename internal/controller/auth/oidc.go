@@ -0,0 +1,416 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// OIDCInfoPath serves the issuer and client ID that clients log in
+	// with. It needs no authentication.
+	OIDCInfoPath = "/v1/auth/oidc"
+
+	// DefaultOIDCCacheTTL is how long discovery documents and signing keys
+	// are cached.
+	DefaultOIDCCacheTTL = time.Hour
+
+	defaultUsernameClaim = "sub"
+	defaultGroupsClaim   = "groups"
+)
+
+// minKeyRefreshInterval limits how often an unknown key ID triggers a JWKS
+// fetch, so tokens with made-up key IDs cannot flood the issuer.
+var minKeyRefreshInterval = 10 * time.Second
+
+// OIDCConfig configures validation of tokens issued by an OpenID Connect
+// provider.
+type OIDCConfig struct {
+	// Issuer is the issuer URL. Its discovery document is fetched from
+	// {Issuer}/.well-known/openid-configuration.
+	Issuer string
+
+	// ClientID is the client registered for Conductor. ID tokens must name
+	// it as their audience.
+	ClientID string
+
+	// Audiences are further accepted audiences, for access tokens issued
+	// for an API rather than the client.
+	Audiences []string
+
+	// UsernameClaim is the claim used as the user ID. Defaults to "sub".
+	UsernameClaim string
+
+	// GroupsClaim is the claim holding the user's groups. Defaults to "groups".
+	GroupsClaim string
+
+	// GroupRoles maps groups to RBAC roles.
+	GroupRoles map[string][]string
+
+	// GroupScopes maps groups to endpoint scopes. Users whose groups map to
+	// no scopes are not limited by scope.
+	GroupScopes map[string][]string
+
+	// CacheTTL is how long the discovery document and keys are cached.
+	// Defaults to DefaultOIDCCacheTTL.
+	CacheTTL time.Duration
+
+	// ClockSkew allows for clock skew when validating exp/nbf claims.
+	ClockSkew time.Duration
+
+	// HTTPClient fetches the discovery document and keys.
+	HTTPClient *http.Client
+}
+
+// OIDCDiscovery is the subset of an OpenID Connect discovery document that
+// Conductor uses.
+type OIDCDiscovery struct {
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+}
+
+// DiscoverOIDC fetches an issuer's discovery document.
+func DiscoverOIDC(ctx context.Context, client *http.Client, issuer string) (*OIDCDiscovery, error) {
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	var doc OIDCDiscovery
+	if err := getJSON(ctx, client, url, &doc); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+	if doc.Issuer != issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", doc.Issuer, issuer)
+	}
+	if doc.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document has no jwks_uri")
+	}
+	return &doc, nil
+}
+
+// OIDCVerifier validates OIDC ID and access tokens against an issuer's
+// signing keys. The discovery document and keys are fetched on first use
+// and cached; a token signed with an unknown key refreshes the keys, so key
+// rotation at the issuer needs no restart.
+type OIDCVerifier struct {
+	config OIDCConfig
+	client *http.Client
+
+	refresh        singleflight.Group
+	mu             sync.Mutex
+	discovery      *OIDCDiscovery
+	keys           map[string]crypto.PublicKey
+	keysFetchedAt  time.Time
+	keysRefreshing time.Time
+}
+
+// NewOIDCVerifier creates a new verifier. It does not contact the issuer
+// until the first token is verified.
+func NewOIDCVerifier(cfg OIDCConfig) *OIDCVerifier {
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = defaultUsernameClaim
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = defaultGroupsClaim
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultOIDCCacheTTL
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCVerifier{config: cfg, client: client}
+}
+
+// Issuer returns the configured issuer URL.
+func (v *OIDCVerifier) Issuer() string {
+	return v.config.Issuer
+}
+
+// ClientID returns the configured client ID.
+func (v *OIDCVerifier) ClientID() string {
+	return v.config.ClientID
+}
+
+// Verify validates a token and returns the user it identifies, with roles
+// and scopes mapped from the user's groups.
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (*User, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(v.config.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.config.ClockSkew),
+	)
+
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC token: %w", err)
+	}
+
+	if !v.audienceAccepted(claims) {
+		return nil, fmt.Errorf("invalid OIDC token: audience not accepted")
+	}
+
+	userID, _ := claims[v.config.UsernameClaim].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("invalid OIDC token: missing %s claim", v.config.UsernameClaim)
+	}
+
+	user := &User{ID: userID, Name: userID}
+	if name, ok := claims["name"].(string); ok && name != "" {
+		user.Name = name
+	}
+	for _, group := range stringsClaim(claims[v.config.GroupsClaim]) {
+		user.Roles = append(user.Roles, v.config.GroupRoles[group]...)
+		user.Scopes = append(user.Scopes, v.config.GroupScopes[group]...)
+	}
+	slices.Sort(user.Roles)
+	user.Roles = slices.Compact(user.Roles)
+	slices.Sort(user.Scopes)
+	user.Scopes = slices.Compact(user.Scopes)
+	return user, nil
+}
+
+// audienceAccepted checks that the aud claim names the client or one of the
+// configured audiences.
+func (v *OIDCVerifier) audienceAccepted(claims jwt.MapClaims) bool {
+	accepted := append([]string{v.config.ClientID}, v.config.Audiences...)
+	audiences, _ := claims.GetAudience()
+	for _, aud := range audiences {
+		if aud != "" && slices.Contains(accepted, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the signing key with the given ID, fetching the issuer's keys
+// when the cache is empty, stale, or does not hold the key. Concurrent
+// callers share a single fetch, and the cache stays readable while it runs.
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	stale := time.Since(v.keysFetchedAt) > v.config.CacheTTL
+	key, known := v.lookupKey(kid)
+	v.mu.Unlock()
+	if known && !stale {
+		return key, nil
+	}
+
+	_, err, _ := v.refresh.Do("keys", func() (any, error) {
+		return nil, v.refreshKeys(ctx)
+	})
+
+	v.mu.Lock()
+	fresh, found := v.lookupKey(kid)
+	v.mu.Unlock()
+	if found {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds a cached key. An empty kid matches the only key, if
+// there is exactly one. Callers must hold v.mu.
+func (v *OIDCVerifier) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refreshKeys fetches the discovery document, if it is missing or stale,
+// and the issuer's keys, then swaps them into the cache. Once keys are
+// cached it fetches at most once per minKeyRefreshInterval. If the fetch
+// fails the cached keys are kept, so they stay usable while the issuer is
+// unreachable.
+func (v *OIDCVerifier) refreshKeys(ctx context.Context) error {
+	v.mu.Lock()
+	if v.keys != nil && time.Since(v.keysRefreshing) < minKeyRefreshInterval {
+		v.mu.Unlock()
+		return nil
+	}
+	v.keysRefreshing = time.Now()
+	discovery := v.discovery
+	if time.Since(v.keysFetchedAt) > v.config.CacheTTL {
+		discovery = nil
+	}
+	v.mu.Unlock()
+
+	if discovery == nil {
+		doc, err := DiscoverOIDC(ctx, v.client, v.config.Issuer)
+		if err != nil {
+			return err
+		}
+		discovery = doc
+	}
+
+	var set jsonWebKeySet
+	if err := getJSON(ctx, v.client, discovery.JWKSURI, &set); err != nil {
+		return fmt.Errorf("failed to fetch OIDC signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip keys we cannot use rather than failing the whole set
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("issuer published no usable signing keys")
+	}
+
+	v.mu.Lock()
+	v.discovery = discovery
+	v.keys = keys
+	v.keysFetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+// jsonWebKeySet is a JWKS document.
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jsonWebKey is a public key from a JWKS document.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key parameter: %w", err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// stringsClaim reads a claim holding a string or a list of strings.
+func stringsClaim(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
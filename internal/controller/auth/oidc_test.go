@@ -0,0 +1,319 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIssuer is a minimal OIDC provider serving discovery and JWKS.
+type mockIssuer struct {
+	server      *httptest.Server
+	mu          sync.Mutex
+	key         *rsa.PrivateKey
+	kid         string
+	jwksFetches atomic.Int32
+	jwksHold    chan struct{} // If set, JWKS requests wait until it is closed
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	m := &mockIssuer{}
+	m.rotate(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OIDCDiscovery{
+			Issuer:        m.server.URL,
+			JWKSURI:       m.server.URL + "/keys",
+			TokenEndpoint: m.server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		m.jwksFetches.Add(1)
+		m.mu.Lock()
+		hold := m.jwksHold
+		m.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		pub := m.key.PublicKey
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": m.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			}},
+		})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// rotate replaces the issuer's signing key.
+func (m *mockIssuer) rotate(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.key = key
+	m.kid = kid
+}
+
+// sign issues a token with the given claims over the defaults.
+func (m *mockIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	all := jwt.MapClaims{
+		"iss": m.server.URL,
+		"sub": "alice",
+		"aud": "conductor",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, all)
+	token.Header["kid"] = m.kid
+	signed, err := token.SignedString(m.key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func newTestVerifier(issuer *mockIssuer) *OIDCVerifier {
+	return NewOIDCVerifier(OIDCConfig{
+		Issuer:      issuer.server.URL,
+		ClientID:    "conductor",
+		Audiences:   []string{"conductor-api"},
+		GroupRoles:  map[string][]string{"platform": {RoleAdmin}, "dev": {RoleOperator}},
+		GroupScopes: map[string][]string{"dev": {"review-*"}},
+	})
+}
+
+func TestOIDCVerifier_Verify(t *testing.T) {
+	issuer := newMockIssuer(t)
+	v := newTestVerifier(issuer)
+	ctx := context.Background()
+
+	user, err := v.Verify(ctx, issuer.sign(t, jwt.MapClaims{
+		"name":   "Alice",
+		"groups": []string{"dev", "unmapped"},
+	}))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if user.ID != "alice" || user.Name != "Alice" {
+		t.Errorf("user = %+v", user)
+	}
+	if len(user.Roles) != 1 || user.Roles[0] != RoleOperator {
+		t.Errorf("Roles = %v, want [operator]", user.Roles)
+	}
+	if len(user.Scopes) != 1 || user.Scopes[0] != "review-*" {
+		t.Errorf("Scopes = %v, want [review-*]", user.Scopes)
+	}
+
+	// Keys are cached between tokens
+	if _, err := v.Verify(ctx, issuer.sign(t, nil)); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got := issuer.jwksFetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", got)
+	}
+}
+
+func TestOIDCVerifier_Rejects(t *testing.T) {
+	issuer := newMockIssuer(t)
+	v := newTestVerifier(issuer)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"wrong audience", jwt.MapClaims{"aud": "other"}},
+		{"wrong issuer", jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{"expired", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{"missing subject", jwt.MapClaims{"sub": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(ctx, issuer.sign(t, tt.claims)); err == nil {
+				t.Error("Verify() succeeded, want error")
+			}
+		})
+	}
+
+	// Tokens signed with a key the issuer never published
+	other := newMockIssuer(t)
+	forged := other.sign(t, jwt.MapClaims{"iss": issuer.server.URL})
+	if _, err := v.Verify(ctx, forged); err == nil {
+		t.Error("Verify() accepted a token signed by another key")
+	}
+}
+
+func TestOIDCVerifier_AccessTokenAudience(t *testing.T) {
+	issuer := newMockIssuer(t)
+	v := newTestVerifier(issuer)
+	ctx := context.Background()
+
+	if _, err := v.Verify(ctx, issuer.sign(t, jwt.MapClaims{"aud": "conductor-api"})); err != nil {
+		t.Errorf("configured audience rejected: %v", err)
+	}
+	if _, err := v.Verify(ctx, issuer.sign(t, jwt.MapClaims{"aud": "other", "azp": "conductor"})); err == nil {
+		t.Error("Verify() accepted a token for another audience because of its azp claim")
+	}
+}
+
+func TestOIDCVerifier_KeyRotation(t *testing.T) {
+	old := minKeyRefreshInterval
+	minKeyRefreshInterval = 0
+	t.Cleanup(func() { minKeyRefreshInterval = old })
+
+	issuer := newMockIssuer(t)
+	v := newTestVerifier(issuer)
+	ctx := context.Background()
+
+	if _, err := v.Verify(ctx, issuer.sign(t, nil)); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	issuer.rotate(t, "key-2")
+	if _, err := v.Verify(ctx, issuer.sign(t, nil)); err != nil {
+		t.Fatalf("Verify() after rotation error = %v", err)
+	}
+	if got := issuer.jwksFetches.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", got)
+	}
+}
+
+func TestOIDCVerifier_KeyRefreshDoesNotBlockCachedKeys(t *testing.T) {
+	old := minKeyRefreshInterval
+	minKeyRefreshInterval = 0
+	t.Cleanup(func() { minKeyRefreshInterval = old })
+
+	issuer := newMockIssuer(t)
+	v := newTestVerifier(issuer)
+	ctx := context.Background()
+
+	cached := issuer.sign(t, nil)
+	if _, err := v.Verify(ctx, cached); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	// Tokens with the rotated key wait on the JWKS fetch
+	hold := make(chan struct{})
+	issuer.mu.Lock()
+	issuer.jwksHold = hold
+	issuer.mu.Unlock()
+	issuer.rotate(t, "key-2")
+	rotated := issuer.sign(t, nil)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Verify(ctx, rotated)
+			errs <- err
+		}()
+	}
+	for issuer.jwksFetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The cached key stays usable while the fetch is in flight
+	if _, err := v.Verify(ctx, cached); err != nil {
+		t.Errorf("Verify() with cached key during refresh error = %v", err)
+	}
+
+	close(hold)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Verify() after rotation error = %v", err)
+		}
+	}
+}
+
+func TestMiddleware_OIDC(t *testing.T) {
+	issuer := newMockIssuer(t)
+	m := NewMiddleware(Config{
+		Enabled: true,
+		APIKeys: []APIKey{{Key: "static-key", Name: "key-1"}},
+		OIDC:    newTestVerifier(issuer),
+	})
+
+	var gotUser *User
+	handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/runs", nil)
+	req.Header.Set("Authorization", "Bearer "+issuer.sign(t, jwt.MapClaims{"groups": []string{"platform"}}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if gotUser == nil || gotUser.ID != "alice" || len(gotUser.Roles) != 1 || gotUser.Roles[0] != RoleAdmin {
+		t.Errorf("user = %+v", gotUser)
+	}
+
+	// API keys still work alongside OIDC
+	req = httptest.NewRequest("GET", "/v1/runs", nil)
+	req.Header.Set("Authorization", "Bearer static-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("API key status = %d, want 200", rec.Code)
+	}
+
+	// The login settings are public
+	req = httptest.NewRequest("GET", OIDCInfoPath, nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("%s status = %d, want 200", OIDCInfoPath, rec.Code)
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// Action is an operation that a role can grant.
+type Action string
+
+const (
+	// ActionRead allows listing and viewing runs, schedules, triggers,
+	// traces and MCP servers.
+	ActionRead Action = "read"
+	// ActionReadOutputs allows reading run outputs, step results and logs.
+	ActionReadOutputs Action = "read-outputs"
+	// ActionRun allows starting, resuming and retrying runs.
+	ActionRun Action = "run"
+	// ActionCancel allows cancelling and pausing runs.
+	ActionCancel Action = "cancel"
+	// ActionApprove allows deciding approval requests.
+	ActionApprove Action = "approve"
+	// ActionManageTriggers allows changing schedules, webhooks and endpoints.
+	ActionManageTriggers Action = "manage-triggers"
+	// ActionManageMCP allows registering, starting and stopping MCP servers.
+	ActionManageMCP Action = "manage-mcp"
+	// ActionAdmin allows managing roles, role bindings and security overrides.
+	ActionAdmin Action = "admin"
+
+	// ActionAll grants every action.
+	ActionAll Action = "*"
+)
+
+// Actions lists every action that can be granted, in display order.
+var Actions = []Action{
+	ActionRead,
+	ActionReadOutputs,
+	ActionRun,
+	ActionCancel,
+	ActionApprove,
+	ActionManageTriggers,
+	ActionManageMCP,
+	ActionAdmin,
+}
+
+// Built-in role names.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// ErrRoleNotFound is returned by a RoleStore when a role or role binding
+// does not exist.
+var ErrRoleNotFound = errors.New("role not found")
+
+// Permission grants actions on the workflows and workspaces matching its
+// patterns. Patterns match exactly or, with a trailing "*", by prefix.
+// An empty pattern list matches everything.
+type Permission struct {
+	Actions    []Action `json:"actions"`
+	Workflows  []string `json:"workflows,omitempty"`
+	Workspaces []string `json:"workspaces,omitempty"`
+}
+
+// Role is a named set of permissions.
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	Builtin     bool         `json:"builtin,omitempty"`
+	UpdatedAt   time.Time    `json:"updated_at,omitempty"`
+}
+
+// RoleBinding assigns roles to a subject, the ID of an authenticated user.
+type RoleBinding struct {
+	Subject   string    `json:"subject"`
+	Roles     []string  `json:"roles"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Resource identifies what an action applies to. An empty field matches
+// only permissions that do not restrict it, so a role scoped to one
+// workspace is not granted a run whose workspace is unknown.
+type Resource struct {
+	Workflow  string
+	Workspace string
+
+	// Precheck marks a route-level check made before the resource has been
+	// loaded. Its empty fields match any pattern; the handler checks the
+	// loaded resource again.
+	Precheck bool
+}
+
+// String returns a description of the resource for logs.
+func (r Resource) String() string {
+	switch {
+	case r.Workflow != "" && r.Workspace != "":
+		return "workflow " + r.Workflow + " in workspace " + r.Workspace
+	case r.Workflow != "":
+		return "workflow " + r.Workflow
+	case r.Workspace != "":
+		return "workspace " + r.Workspace
+	default:
+		return "any resource"
+	}
+}
+
+// BuiltinRoles returns the roles that exist without being stored:
+// viewer, operator and admin.
+func BuiltinRoles() []*Role {
+	return []*Role{
+		{
+			Name:        RoleViewer,
+			Description: "View runs, schedules, traces and MCP servers",
+			Permissions: []Permission{{Actions: []Action{ActionRead}}},
+			Builtin:     true,
+		},
+		{
+			Name:        RoleOperator,
+			Description: "Run, cancel and approve workflows and read their outputs",
+			Permissions: []Permission{{Actions: []Action{ActionRead, ActionReadOutputs, ActionRun, ActionCancel, ActionApprove}}},
+			Builtin:     true,
+		},
+		{
+			Name:        RoleAdmin,
+			Description: "Full access, including managing triggers, MCP servers and roles",
+			Permissions: []Permission{{Actions: []Action{ActionAll}}},
+			Builtin:     true,
+		},
+	}
+}
+
+// builtinRole returns the built-in role with the given name, or nil.
+func builtinRole(name string) *Role {
+	for _, role := range BuiltinRoles() {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Validate checks a role's name and actions.
+func (r *Role) Validate() error {
+	if !roleNamePattern.MatchString(r.Name) {
+		return fmt.Errorf("invalid role name %q: use lowercase letters, digits, '-' and '_'", r.Name)
+	}
+	if builtinRole(r.Name) != nil {
+		return fmt.Errorf("role %q is built in and cannot be changed", r.Name)
+	}
+	if len(r.Permissions) == 0 {
+		return fmt.Errorf("role %q has no permissions", r.Name)
+	}
+	for _, perm := range r.Permissions {
+		if len(perm.Actions) == 0 {
+			return fmt.Errorf("role %q has a permission without actions", r.Name)
+		}
+		for _, action := range perm.Actions {
+			if action != ActionAll && !slices.Contains(Actions, action) {
+				return fmt.Errorf("unknown action %q", action)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the role grants an action on a resource.
+func (r *Role) Allows(action Action, res Resource) bool {
+	for _, perm := range r.Permissions {
+		if perm.allows(action, res) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Permission) allows(action Action, res Resource) bool {
+	if !slices.Contains(p.Actions, action) && !slices.Contains(p.Actions, ActionAll) {
+		return false
+	}
+	return matchesPatterns(p.Workflows, res.Workflow, res.Precheck) &&
+		matchesPatterns(p.Workspaces, res.Workspace, res.Precheck)
+}
+
+// matchesPatterns reports whether a name matches any of the patterns.
+// No patterns match everything. An empty name matches only in a precheck.
+func matchesPatterns(patterns []string, name string, precheck bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if name == "" {
+		return precheck
+	}
+	for _, pattern := range patterns {
+		if matchesScopePattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleStore persists custom roles and role bindings.
+// Get methods return ErrRoleNotFound if the role or binding does not exist.
+type RoleStore interface {
+	GetRole(ctx context.Context, name string) (*Role, error)
+	ListRoles(ctx context.Context) ([]*Role, error)
+	SaveRole(ctx context.Context, role *Role) error
+	DeleteRole(ctx context.Context, name string) error
+
+	GetRoleBinding(ctx context.Context, subject string) (*RoleBinding, error)
+	ListRoleBindings(ctx context.Context) ([]*RoleBinding, error)
+	SaveRoleBinding(ctx context.Context, binding *RoleBinding) error
+	DeleteRoleBinding(ctx context.Context, subject string) error
+}
+
+// ForbiddenError is returned when a user is not allowed to perform an action.
+type ForbiddenError struct {
+	UserID   string
+	Action   Action
+	Resource Resource
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("%s is not allowed to %s on %s", e.UserID, e.Action, e.Resource)
+}
+
+// Denial describes a refused authorization, for audit logging.
+type Denial struct {
+	UserID    string
+	Action    Action
+	Resource  Resource
+	Path      string
+	IPAddress string
+}
+
+// AuthorizerConfig configures an Authorizer.
+type AuthorizerConfig struct {
+	// Store holds custom roles and role bindings. Without a store only the
+	// built-in roles, static bindings and roles carried by credentials apply.
+	Store RoleStore
+
+	// Bindings are role bindings from configuration, by subject.
+	Bindings map[string][]string
+
+	// DefaultRoles apply to users without any role. Empty means no access.
+	DefaultRoles []string
+
+	// OnDeny is called for every refused authorization.
+	OnDeny func(Denial)
+
+	// Logger for authorization errors.
+	Logger *slog.Logger
+}
+
+// Authorizer decides whether users may perform actions, using role-based
+// access control.
+type Authorizer struct {
+	config AuthorizerConfig
+	logger *slog.Logger
+}
+
+// NewAuthorizer creates a new authorizer.
+func NewAuthorizer(cfg AuthorizerConfig) *Authorizer {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Authorizer{config: cfg, logger: logger}
+}
+
+// Store returns the authorizer's role store, or nil.
+func (a *Authorizer) Store() RoleStore {
+	return a.config.Store
+}
+
+// RoleNames returns the names of the roles a user holds: roles carried by
+// the user's credentials, static bindings, stored bindings, or the default
+// roles if there are none.
+func (a *Authorizer) RoleNames(ctx context.Context, user *User) ([]string, error) {
+	names := slices.Clone(user.Roles)
+	names = append(names, a.config.Bindings[user.ID]...)
+	if a.config.Store != nil {
+		binding, err := a.config.Store.GetRoleBinding(ctx, user.ID)
+		if err != nil && !errors.Is(err, ErrRoleNotFound) {
+			return nil, fmt.Errorf("failed to load role binding: %w", err)
+		}
+		if binding != nil {
+			names = append(names, binding.Roles...)
+		}
+	}
+	if len(names) == 0 {
+		names = slices.Clone(a.config.DefaultRoles)
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+// Role returns a built-in or stored role.
+func (a *Authorizer) Role(ctx context.Context, name string) (*Role, error) {
+	if role := builtinRole(name); role != nil {
+		return role, nil
+	}
+	if a.config.Store == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRoleNotFound, name)
+	}
+	return a.config.Store.GetRole(ctx, name)
+}
+
+// Allowed reports whether a user may perform an action on a resource,
+// without recording a denial. Use it to filter lists.
+func (a *Authorizer) Allowed(ctx context.Context, user *User, action Action, res Resource) bool {
+	names, err := a.RoleNames(ctx, user)
+	if err != nil {
+		a.logger.Error("authorization failed", "user", user.ID, "error", err)
+		return false
+	}
+	for _, name := range names {
+		role, err := a.Role(ctx, name)
+		if err != nil {
+			// A binding to a deleted role grants nothing
+			if !errors.Is(err, ErrRoleNotFound) {
+				a.logger.Error("failed to load role", "role", name, "error", err)
+			}
+			continue
+		}
+		if role.Allows(action, res) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns a ForbiddenError if a user may not perform an action on
+// a resource, and reports the denial to OnDeny.
+func (a *Authorizer) Authorize(ctx context.Context, user *User, action Action, res Resource) error {
+	if a.Allowed(ctx, user, action, res) {
+		return nil
+	}
+	if a.config.OnDeny != nil {
+		denial := Denial{UserID: user.ID, Action: action, Resource: res}
+		if info, ok := ctx.Value(requestInfoContextKey).(requestInfo); ok {
+			denial.Path = info.path
+			denial.IPAddress = info.ipAddress
+		}
+		a.config.OnDeny(denial)
+	}
+	return &ForbiddenError{UserID: user.ID, Action: action, Resource: res}
+}
+
+const (
+	authorizerContextKey  contextKey = "authorizer"
+	requestInfoContextKey contextKey = "request_info"
+)
+
+// requestInfo describes the request being authorized, for denial records.
+type requestInfo struct {
+	path      string
+	ipAddress string
+}
+
+// Authorize checks that the user in ctx may perform an action on a resource.
+// It returns nil when RBAC is disabled or the request was not authenticated
+// by user, such as requests over the Unix socket.
+func Authorize(ctx context.Context, action Action, res Resource) error {
+	a, user, ok := authorizerFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return a.Authorize(ctx, user, action, res)
+}
+
+// Allowed reports whether the user in ctx may perform an action on a
+// resource without recording a denial. It returns true when Authorize would
+// not check.
+func Allowed(ctx context.Context, action Action, res Resource) bool {
+	a, user, ok := authorizerFromContext(ctx)
+	if !ok {
+		return true
+	}
+	return a.Allowed(ctx, user, action, res)
+}
+
+func authorizerFromContext(ctx context.Context) (*Authorizer, *User, bool) {
+	a, _ := ctx.Value(authorizerContextKey).(*Authorizer)
+	user, _ := UserFromContext(ctx)
+	if a == nil || user == nil {
+		return nil, nil, false
+	}
+	return a, user, true
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeRoleStore is an in-memory RoleStore for tests.
+type fakeRoleStore struct {
+	roles    map[string]*Role
+	bindings map[string]*RoleBinding
+}
+
+func (s *fakeRoleStore) GetRole(ctx context.Context, name string) (*Role, error) {
+	if role, ok := s.roles[name]; ok {
+		return role, nil
+	}
+	return nil, ErrRoleNotFound
+}
+
+func (s *fakeRoleStore) ListRoles(ctx context.Context) ([]*Role, error) {
+	var roles []*Role
+	for _, role := range s.roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (s *fakeRoleStore) SaveRole(ctx context.Context, role *Role) error {
+	s.roles[role.Name] = role
+	return nil
+}
+
+func (s *fakeRoleStore) DeleteRole(ctx context.Context, name string) error {
+	delete(s.roles, name)
+	return nil
+}
+
+func (s *fakeRoleStore) GetRoleBinding(ctx context.Context, subject string) (*RoleBinding, error) {
+	if binding, ok := s.bindings[subject]; ok {
+		return binding, nil
+	}
+	return nil, ErrRoleNotFound
+}
+
+func (s *fakeRoleStore) ListRoleBindings(ctx context.Context) ([]*RoleBinding, error) {
+	var bindings []*RoleBinding
+	for _, binding := range s.bindings {
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+func (s *fakeRoleStore) SaveRoleBinding(ctx context.Context, binding *RoleBinding) error {
+	s.bindings[binding.Subject] = binding
+	return nil
+}
+
+func (s *fakeRoleStore) DeleteRoleBinding(ctx context.Context, subject string) error {
+	delete(s.bindings, subject)
+	return nil
+}
+
+func newTestAuthorizer(onDeny func(Denial)) *Authorizer {
+	store := &fakeRoleStore{
+		roles: map[string]*Role{
+			"deployer": {
+				Name: "deployer",
+				Permissions: []Permission{{
+					Actions:    []Action{ActionRead, ActionRun},
+					Workflows:  []string{"deploy-*"},
+					Workspaces: []string{"production"},
+				}},
+			},
+		},
+		bindings: map[string]*RoleBinding{
+			"dana": {Subject: "dana", Roles: []string{"deployer"}},
+		},
+	}
+	return NewAuthorizer(AuthorizerConfig{
+		Store:    store,
+		Bindings: map[string][]string{"olivia": {RoleOperator}, "ada": {RoleAdmin}},
+		OnDeny:   onDeny,
+	})
+}
+
+func TestAuthorizer_Allowed(t *testing.T) {
+	a := newTestAuthorizer(nil)
+	ctx := context.Background()
+	prodDeploy := Resource{Workflow: "deploy-api", Workspace: "production"}
+
+	tests := []struct {
+		name   string
+		user   *User
+		action Action
+		res    Resource
+		want   bool
+	}{
+		{"viewer reads", &User{ID: "vic", Roles: []string{RoleViewer}}, ActionRead, prodDeploy, true},
+		{"viewer cannot read outputs", &User{ID: "vic", Roles: []string{RoleViewer}}, ActionReadOutputs, prodDeploy, false},
+		{"viewer cannot run", &User{ID: "vic", Roles: []string{RoleViewer}}, ActionRun, prodDeploy, false},
+		{"operator runs", &User{ID: "olivia"}, ActionRun, prodDeploy, true},
+		{"operator cannot manage triggers", &User{ID: "olivia"}, ActionManageTriggers, Resource{}, false},
+		{"admin manages MCP", &User{ID: "ada"}, ActionManageMCP, Resource{}, true},
+		{"custom role matches pattern", &User{ID: "dana"}, ActionRun, prodDeploy, true},
+		{"custom role other workflow", &User{ID: "dana"}, ActionRun, Resource{Workflow: "cleanup", Workspace: "production"}, false},
+		{"custom role other workspace", &User{ID: "dana"}, ActionRun, Resource{Workflow: "deploy-api", Workspace: "staging"}, false},
+		{"custom role precheck", &User{ID: "dana"}, ActionRun, Resource{Precheck: true}, true},
+		{"custom role unknown resource", &User{ID: "dana"}, ActionRun, Resource{}, false},
+		{"custom role unknown workspace", &User{ID: "dana"}, ActionRun, Resource{Workflow: "deploy-api"}, false},
+		{"custom role precheck other workflow", &User{ID: "dana"}, ActionRun, Resource{Workflow: "cleanup", Precheck: true}, false},
+		{"custom role other action", &User{ID: "dana"}, ActionCancel, prodDeploy, false},
+		{"no roles", &User{ID: "nobody"}, ActionRead, Resource{}, false},
+		{"deleted role grants nothing", &User{ID: "gone", Roles: []string{"removed"}}, ActionRead, Resource{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Allowed(ctx, tt.user, tt.action, tt.res); got != tt.want {
+				t.Errorf("Allowed(%s, %s, %v) = %v, want %v", tt.user.ID, tt.action, tt.res, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_DefaultRoles(t *testing.T) {
+	a := NewAuthorizer(AuthorizerConfig{DefaultRoles: []string{RoleViewer}})
+	ctx := context.Background()
+
+	if !a.Allowed(ctx, &User{ID: "anyone"}, ActionRead, Resource{}) {
+		t.Error("default role should allow read")
+	}
+	if a.Allowed(ctx, &User{ID: "anyone"}, ActionRun, Resource{}) {
+		t.Error("default role should not allow run")
+	}
+	// Explicit roles replace the default
+	names, err := a.RoleNames(ctx, &User{ID: "bound", Roles: []string{RoleAdmin}})
+	if err != nil {
+		t.Fatalf("RoleNames() error = %v", err)
+	}
+	if len(names) != 1 || names[0] != RoleAdmin {
+		t.Errorf("RoleNames() = %v, want [admin]", names)
+	}
+}
+
+func TestAuthorizer_AuthorizeReportsDenial(t *testing.T) {
+	var denials []Denial
+	a := newTestAuthorizer(func(d Denial) { denials = append(denials, d) })
+	ctx := context.Background()
+
+	err := a.Authorize(ctx, &User{ID: "dana"}, ActionRun, Resource{Workflow: "cleanup"})
+	var forbidden *ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("Authorize() error = %v, want ForbiddenError", err)
+	}
+	if len(denials) != 1 || denials[0].UserID != "dana" || denials[0].Action != ActionRun {
+		t.Errorf("denials = %+v", denials)
+	}
+
+	if err := a.Authorize(ctx, &User{ID: "dana"}, ActionRun, Resource{Workflow: "deploy-web", Workspace: "production"}); err != nil {
+		t.Errorf("Authorize() error = %v, want nil", err)
+	}
+	if len(denials) != 1 {
+		t.Errorf("allowed request recorded a denial")
+	}
+}
+
+func TestAuthorize_WithoutAuthorizer(t *testing.T) {
+	if err := Authorize(context.Background(), ActionAdmin, Resource{}); err != nil {
+		t.Errorf("Authorize() without authorizer = %v, want nil", err)
+	}
+	if !Allowed(context.Background(), ActionAdmin, Resource{}) {
+		t.Error("Allowed() without authorizer = false, want true")
+	}
+}
+
+func TestRoleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    Role
+		wantErr bool
+	}{
+		{"valid", Role{Name: "deployer", Permissions: []Permission{{Actions: []Action{ActionRun}}}}, false},
+		{"wildcard action", Role{Name: "ops", Permissions: []Permission{{Actions: []Action{ActionAll}}}}, false},
+		{"invalid name", Role{Name: "Deploy Team", Permissions: []Permission{{Actions: []Action{ActionRun}}}}, true},
+		{"builtin name", Role{Name: RoleAdmin, Permissions: []Permission{{Actions: []Action{ActionRun}}}}, true},
+		{"no permissions", Role{Name: "empty"}, true},
+		{"unknown action", Role{Name: "odd", Permissions: []Permission{{Actions: []Action{"deploy"}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.role.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequestAction(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		action   Action
+		workflow string
+	}{
+		{"GET", "/v1/health", "", ""},
+		{"GET", "/v1/auth/whoami", "", ""},
+		{"POST", "/webhooks/github/deploy", "", ""},
+		{"GET", "/v1/runs", ActionRead, ""},
+		{"GET", "/v1/runs/abc", ActionRead, ""},
+		{"GET", "/v1/runs/abc/output", ActionReadOutputs, ""},
+		{"GET", "/v1/runs/abc/logs", ActionReadOutputs, ""},
+		{"GET", "/v1/runs/abc/steps/build", ActionReadOutputs, ""},
+		{"POST", "/v1/runs", ActionRun, ""},
+		{"POST", "/v1/runs/abc/resume", ActionRun, ""},
+		{"POST", "/v1/runs/abc/pause", ActionCancel, ""},
+		{"DELETE", "/v1/runs/abc", ActionCancel, ""},
+		{"POST", "/v1/approvals/1/approve", ActionApprove, ""},
+		{"GET", "/v1/schedules", ActionRead, ""},
+		{"POST", "/v1/schedules/nightly/enable", ActionManageTriggers, ""},
+		{"POST", "/v1/triggers", ActionManageTriggers, ""},
+		{"POST", "/v1/mcp/servers", ActionManageMCP, ""},
+		{"GET", "/v1/auth/roles", ActionAdmin, ""},
+		{"POST", "/run/deploy-api", ActionRun, "deploy-api"},
+		{"POST", "/v1/start/deploy-api", ActionRun, "deploy-api"},
+		{"POST", "/v1/unknown", ActionAdmin, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			action, res := RequestAction(tt.method, tt.path)
+			if action != tt.action || res.Workflow != tt.workflow {
+				t.Errorf("RequestAction() = %q, %q; want %q, %q", action, res.Workflow, tt.action, tt.workflow)
+			}
+		})
+	}
+}
+
+func TestMiddleware_RBAC(t *testing.T) {
+	var denials []Denial
+	m := NewMiddleware(Config{
+		Enabled: true,
+		APIKeys: []APIKey{
+			{Key: "viewer-key", Name: "key-1", Roles: []string{RoleViewer}},
+			{Key: "operator-key", Name: "key-2", Roles: []string{RoleOperator}},
+		},
+		Authorizer: NewAuthorizer(AuthorizerConfig{
+			OnDeny: func(d Denial) { denials = append(denials, d) },
+		}),
+	})
+
+	handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		key    string
+		method string
+		path   string
+		want   int
+	}{
+		{"viewer-key", "GET", "/v1/runs", http.StatusOK},
+		{"viewer-key", "POST", "/v1/runs", http.StatusForbidden},
+		{"viewer-key", "GET", "/v1/auth/whoami", http.StatusOK},
+		{"operator-key", "POST", "/v1/runs", http.StatusOK},
+		{"operator-key", "POST", "/v1/mcp/servers", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("X-API-Key", tt.key)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s %s with %s: status = %d, want %d", tt.method, tt.path, tt.key, rec.Code, tt.want)
+		}
+	}
+
+	if len(denials) != 2 {
+		t.Fatalf("denials = %d, want 2", len(denials))
+	}
+	if denials[0].UserID != "key-1" || denials[0].Path != "/v1/runs" {
+		t.Errorf("denial = %+v", denials[0])
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// RequestAction returns the action an API request needs and, when the path
+// names it, the workflow it acts on, as a precheck resource. It returns an
+// empty action for requests that need no permission.
+//
+// Every /v1 route needs an action: GET requests need ActionRead unless they
+// read outputs, and unknown write routes need ActionAdmin. Outside /v1 only
+// the trigger API (/run/{workflow}) is checked; webhooks and the MCP server
+// have their own authentication.
+func RequestAction(method, urlPath string) (Action, Resource) {
+	action, res := requestAction(method, urlPath)
+	res.Precheck = true
+	return action, res
+}
+
+func requestAction(method, urlPath string) (Action, Resource) {
+	p := path.Clean("/" + urlPath)
+	read := method == http.MethodGet || method == http.MethodHead
+
+	switch {
+	case p == "/v1/health" || p == "/v1/version" || p == "/v1/auth/whoami" || p == OIDCInfoPath:
+		return "", Resource{}
+	case strings.HasPrefix(p, "/run/"):
+		return ActionRun, Resource{Workflow: pathSegment(p, 1)}
+	case strings.HasPrefix(p, "/v1/start/"):
+		return ActionRun, Resource{Workflow: pathSegment(p, 2)}
+	case !strings.HasPrefix(p, "/v1/"):
+		return "", Resource{}
+	case strings.HasPrefix(p, "/v1/runs"):
+		return runAction(method, p), Resource{}
+	case strings.HasPrefix(p, "/v1/approvals"):
+		return readOr(read, ActionApprove), Resource{}
+	case strings.HasPrefix(p, "/v1/schedules"),
+		strings.HasPrefix(p, "/v1/triggers"),
+		strings.HasPrefix(p, "/v1/admin/endpoints"):
+		return readOr(read, ActionManageTriggers), Resource{}
+	case strings.HasPrefix(p, "/v1/mcp"):
+		return readOr(read, ActionManageMCP), Resource{}
+	case strings.HasPrefix(p, "/v1/endpoints"):
+		return readOr(read, ActionRun), Resource{}
+	case strings.HasPrefix(p, "/v1/auth"), strings.HasPrefix(p, "/v1/override"):
+		return ActionAdmin, Resource{}
+	default:
+		return readOr(read, ActionAdmin), Resource{}
+	}
+}
+
+// runAction returns the action needed for a /v1/runs request.
+func runAction(method, p string) Action {
+	if method == http.MethodGet || method == http.MethodHead {
+		switch {
+		case strings.HasSuffix(p, "/output"),
+			strings.HasSuffix(p, "/logs"),
+			strings.Contains(p, "/steps"),
+			strings.Contains(p, "/debug/"):
+			return ActionReadOutputs
+		default:
+			return ActionRead
+		}
+	}
+	switch {
+	case method == http.MethodDelete, strings.HasSuffix(p, "/pause"):
+		return ActionCancel
+	default:
+		// Creating, resuming, retrying and debugging runs
+		return ActionRun
+	}
+}
+
+func readOr(read bool, write Action) Action {
+	if read {
+		return ActionRead
+	}
+	return write
+}
+
+// pathSegment returns the i-th segment of a cleaned path, or "".
+func pathSegment(p string, i int) string {
+	segments := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	if i < len(segments) {
+		return segments[i]
+	}
+	return ""
+}
@@ -28,6 +28,7 @@ import (
 // WebhookAuthenticator provides signature verification for webhooks.
 type WebhookAuthenticator struct {
 	githubHandler *webhook.GitHubHandler
+	gitlabHandler *webhook.GitLabHandler
 	slackHandler  *webhook.SlackHandler
 }
 
@@ -35,6 +36,7 @@ type WebhookAuthenticator struct {
 func NewWebhookAuthenticator() *WebhookAuthenticator {
 	return &WebhookAuthenticator{
 		githubHandler: &webhook.GitHubHandler{},
+		gitlabHandler: &webhook.GitLabHandler{},
 		slackHandler:  &webhook.SlackHandler{},
 	}
 }
@@ -44,6 +46,11 @@ func (a *WebhookAuthenticator) VerifyGitHub(r *http.Request, body []byte, secret
 	return a.githubHandler.Verify(r, body, secret)
 }
 
+// VerifyGitLab verifies a GitLab webhook secret token.
+func (a *WebhookAuthenticator) VerifyGitLab(r *http.Request, body []byte, secret string) error {
+	return a.gitlabHandler.Verify(r, body, secret)
+}
+
 // VerifySlack verifies a Slack webhook signature.
 func (a *WebhookAuthenticator) VerifySlack(r *http.Request, body []byte, secret string) error {
 	return a.slackHandler.Verify(r, body, secret)
@@ -78,6 +85,8 @@ func (a *WebhookAuthenticator) VerifyWebhook(source string, r *http.Request, bod
 	switch source {
 	case "github":
 		return a.VerifyGitHub(r, body, secret)
+	case "gitlab":
+		return a.VerifyGitLab(r, body, secret)
 	case "slack":
 		return a.VerifySlack(r, body, secret)
 	case "generic", "":
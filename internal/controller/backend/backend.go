@@ -20,7 +20,13 @@
 //
 //   - RunStore (core, required): CreateRun, GetRun, UpdateRun
 //   - RunLister (optional): ListRuns, DeleteRun
+//   - RunIdempotencyStore (optional): find runs by Idempotency-Key
 //   - CheckpointStore (optional): SaveCheckpoint, GetCheckpoint
+//   - JobQueue (optional): job queue for distributed execution
+//   - RunLogStore (optional): run logs shared between controllers
+//   - RoleStore (optional): RBAC roles and role bindings
+//   - NotificationStore (optional): undelivered run notifications
+//   - TriggerEventStore (optional): inbound webhook and poll trigger events
 //   - io.Closer (optional): Close
 //
 // The Backend interface composes all of these for full-featured implementations.
@@ -30,6 +36,8 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"time"
 )
@@ -63,6 +71,35 @@ type RunLister interface {
 	DeleteRun(ctx context.Context, id string) error
 }
 
+// ErrRunNotFound is returned by FindRunByIdempotencyKey when no run was
+// created with the key.
+var ErrRunNotFound = errors.New("run not found")
+
+// RunIdempotencyStore is an optional interface for finding runs by the
+// idempotency key they were created with, so a retried request can be
+// answered with the original run.
+// Use type assertion to detect if a backend supports this capability:
+//
+//	if store, ok := be.(RunIdempotencyStore); ok {
+//	    run, err := store.FindRunByIdempotencyKey(ctx, workflow, key, since)
+//	}
+type RunIdempotencyStore interface {
+	// FindRunByIdempotencyKey returns the newest run of a workflow created
+	// with key at or after since. Returns ErrRunNotFound if there is none.
+	FindRunByIdempotencyKey(ctx context.Context, workflow, key string, since time.Time) (*Run, error)
+
+	// ReleaseIdempotencyKey clears key from the runs of a workflow created
+	// before a time, so that it can be used for a new run once it has
+	// expired.
+	ReleaseIdempotencyKey(ctx context.Context, workflow, key string, before time.Time) error
+}
+
+// ErrIdempotencyKeyExists is returned by CreateRun when the workflow already
+// has a run with the new run's idempotency key. Backends that store runs in
+// a shared database enforce this so that controllers racing on a retried
+// request create one run.
+var ErrIdempotencyKeyExists = errors.New("idempotency key already used for this workflow")
+
 // CheckpointStore is an optional interface for checkpoint storage.
 // Backends can implement this to support checkpoint persistence.
 // Use type assertion to detect if a backend supports this capability:
@@ -96,6 +133,172 @@ type StepResultStore interface {
 	ListStepResults(ctx context.Context, runID string) ([]*StepResult, error)
 }
 
+// ErrJobNotClaimed is returned by HeartbeatJob when the worker no longer
+// holds the claim on a job, for example because it was recovered as stalled.
+var ErrJobNotClaimed = errors.New("job is not claimed by this worker")
+
+// ErrJobCancelled is returned by HeartbeatJob when the job was cancelled
+// while the worker held the claim. The worker should stop the run.
+var ErrJobCancelled = errors.New("job was cancelled")
+
+// ErrJobNotFound is returned by CancelJob when a run has no job in the queue.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobQueue is an optional interface for distributed run execution.
+// Controllers enqueue submitted runs and worker loops on every controller
+// claim them. Use type assertion to detect if a backend supports this capability:
+//
+//	if queue, ok := store.(JobQueue); ok {
+//	    job, err := queue.DequeueJob(ctx, workerID)
+//	}
+type JobQueue interface {
+	// EnqueueJob adds a job to the queue. Enqueueing a run twice is a no-op.
+	EnqueueJob(ctx context.Context, job *Job) error
+
+	// DequeueJob claims the next pending job for a worker: highest priority
+	// first, then the fair-share group with the fewest running jobs, then
+	// the oldest. Returns nil if no job is available.
+	DequeueJob(ctx context.Context, workerID string) (*Job, error)
+
+	// HeartbeatJob refreshes a worker's claim on a job.
+	// Returns ErrJobNotClaimed if the worker no longer holds the claim, or
+	// ErrJobCancelled if the job has been cancelled.
+	HeartbeatJob(ctx context.Context, runID, workerID string) error
+
+	// CancelJob cancels a run's job. A pending job is removed from the queue
+	// and true is returned. A claimed job is marked so that the worker
+	// holding it gets ErrJobCancelled from its next heartbeat, and false is
+	// returned. Returns ErrJobNotFound if the run has no job.
+	CancelJob(ctx context.Context, runID string) (bool, error)
+
+	// CompleteJob removes a finished job from the queue.
+	CompleteJob(ctx context.Context, runID string) error
+
+	// FailJob releases a claimed job back to the queue.
+	FailJob(ctx context.Context, runID string) error
+
+	// RecoverStalledJobs returns jobs whose claim has not been refreshed
+	// within the timeout to the queue.
+	RecoverStalledJobs(ctx context.Context, timeout time.Duration) (int64, error)
+
+	// CountPendingJobs returns the number of jobs waiting to be claimed.
+	CountPendingJobs(ctx context.Context) (int, error)
+
+	// JobPosition returns a pending job's 1-based position in the order
+	// DequeueJob claims jobs, or 0 if the job is not pending.
+	JobPosition(ctx context.Context, runID string) (int, error)
+}
+
+// RunLogStore is an optional interface for sharing run logs between
+// controllers, so a run's logs can be read from any instance.
+type RunLogStore interface {
+	// AppendRunLog appends a JSON-encoded log entry to a run.
+	AppendRunLog(ctx context.Context, runID string, entry json.RawMessage) error
+
+	// ListRunLogs returns a run's log entries in order, skipping the first offset entries.
+	ListRunLogs(ctx context.Context, runID string, offset int) ([]json.RawMessage, error)
+}
+
+// ErrRoleNotFound is returned by RoleStore when a role or role binding does
+// not exist.
+var ErrRoleNotFound = errors.New("role not found")
+
+// RoleStore is an optional interface for storing RBAC roles and the role
+// bindings that assign them to users.
+// Use type assertion to detect if a backend supports this capability:
+//
+//	if roles, ok := store.(RoleStore); ok {
+//	    binding, err := roles.GetRoleBinding(ctx, userID)
+//	}
+type RoleStore interface {
+	// SaveRole creates or replaces a role.
+	SaveRole(ctx context.Context, role *Role) error
+
+	// GetRole retrieves a role by name. Returns ErrRoleNotFound if it does not exist.
+	GetRole(ctx context.Context, name string) (*Role, error)
+
+	// ListRoles returns all roles ordered by name.
+	ListRoles(ctx context.Context) ([]*Role, error)
+
+	// DeleteRole deletes a role. Returns ErrRoleNotFound if it does not exist.
+	DeleteRole(ctx context.Context, name string) error
+
+	// SaveRoleBinding creates or replaces the roles bound to a subject.
+	SaveRoleBinding(ctx context.Context, binding *RoleBinding) error
+
+	// GetRoleBinding retrieves a subject's binding. Returns ErrRoleNotFound if it does not exist.
+	GetRoleBinding(ctx context.Context, subject string) (*RoleBinding, error)
+
+	// ListRoleBindings returns all bindings ordered by subject.
+	ListRoleBindings(ctx context.Context) ([]*RoleBinding, error)
+
+	// DeleteRoleBinding deletes a subject's binding. Returns ErrRoleNotFound if it does not exist.
+	DeleteRoleBinding(ctx context.Context, subject string) error
+}
+
+// NotificationStore is an optional interface for recording run
+// notifications that could not be delivered after all retries.
+// Use type assertion to detect if a backend supports this capability:
+//
+//	if store, ok := be.(NotificationStore); ok {
+//	    err := store.SaveNotificationDeadLetter(ctx, letter)
+//	}
+type NotificationStore interface {
+	// SaveNotificationDeadLetter records an undelivered notification.
+	SaveNotificationDeadLetter(ctx context.Context, letter *NotificationDeadLetter) error
+
+	// ListNotificationDeadLetters returns undelivered notifications, newest
+	// first. A limit of zero returns all of them.
+	ListNotificationDeadLetters(ctx context.Context, limit int) ([]*NotificationDeadLetter, error)
+}
+
+// ErrTriggerEventNotFound is returned by TriggerEventStore when an event
+// does not exist.
+var ErrTriggerEventNotFound = errors.New("trigger event not found")
+
+// ErrDuplicateTriggerEvent is returned by CreateTriggerEvent when the
+// trigger already has an event with the same idempotency key.
+var ErrDuplicateTriggerEvent = errors.New("duplicate trigger event")
+
+// Trigger event statuses.
+const (
+	TriggerEventReceived  = "received"  // Recorded, delivery in progress
+	TriggerEventDelivered = "delivered" // A run was started
+	TriggerEventIgnored   = "ignored"   // Did not match the trigger's filters
+	TriggerEventRejected  = "rejected"  // Failed signature verification or could not be parsed
+	TriggerEventFailed    = "failed"    // Delivery failed; can be redelivered
+)
+
+// TriggerEventStore is an optional interface for recording inbound trigger
+// events, so failed deliveries can be inspected and redelivered.
+// Use type assertion to detect if a backend supports this capability:
+//
+//	if events, ok := store.(TriggerEventStore); ok {
+//	    err := events.CreateTriggerEvent(ctx, event)
+//	}
+type TriggerEventStore interface {
+	// CreateTriggerEvent records a new event. Returns ErrDuplicateTriggerEvent
+	// if the event has an idempotency key already recorded for its trigger.
+	CreateTriggerEvent(ctx context.Context, event *TriggerEvent) error
+
+	// UpdateTriggerEvent updates an event's outcome.
+	UpdateTriggerEvent(ctx context.Context, event *TriggerEvent) error
+
+	// GetTriggerEvent retrieves an event by ID. Returns ErrTriggerEventNotFound if it does not exist.
+	GetTriggerEvent(ctx context.Context, id string) (*TriggerEvent, error)
+
+	// FindTriggerEvent retrieves the event with an idempotency key for a
+	// trigger. Returns ErrTriggerEventNotFound if there is none.
+	FindTriggerEvent(ctx context.Context, trigger, idempotencyKey string) (*TriggerEvent, error)
+
+	// ListTriggerEvents lists events newest first.
+	ListTriggerEvents(ctx context.Context, filter TriggerEventFilter) ([]*TriggerEvent, error)
+
+	// DeleteTriggerEvents deletes events received before a time and returns
+	// how many were deleted.
+	DeleteTriggerEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
 // Backend defines the full interface for controller storage.
 // This is a composite interface that embeds all segregated interfaces
 // plus io.Closer for lifecycle management.
@@ -123,12 +326,29 @@ type Run struct {
 	CurrentStep   string         `json:"current_step,omitempty"`
 	Completed     int            `json:"completed"`
 	Total         int            `json:"total"`
-	ParentRunID   string         `json:"parent_run_id,omitempty"` // ID of the parent run for replay runs
+	ParentRunID   string         `json:"parent_run_id,omitempty"` // ID of the parent run for replay and chained runs
+	Workspace     string         `json:"workspace,omitempty"`     // Workspace the run was submitted to
 	ReplayConfig  *ReplayConfig  `json:"replay_config,omitempty"` // Configuration for replay execution
 	StartedAt     *time.Time     `json:"started_at,omitempty"`
 	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
+
+	// IdempotencyKey is the Idempotency-Key the run was created with, and
+	// RequestHash identifies the request that created it. Both are stored
+	// only when the run is created.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	RequestHash    string `json:"request_hash,omitempty"`
+}
+
+// Job is a queued run waiting to be claimed by a worker.
+type Job struct {
+	RunID    string          `json:"run_id"`
+	Priority int             `json:"priority"`
+	FairKey  string          `json:"fair_key,omitempty"`  // Fair-share group, such as a workspace or workflow
+	Payload  json.RawMessage `json:"payload,omitempty"`   // What a worker needs to execute the run
+	Attempts int             `json:"attempts"`            // Number of times the job has been claimed
+	LockedBy string          `json:"locked_by,omitempty"` // Worker holding the claim
 }
 
 // RunFilter contains filtering options for listing runs.
@@ -172,6 +392,71 @@ type ReplayConfig struct {
 	ValidateSchema bool           `json:"validate_schema"`           // Validate cached outputs
 }
 
+// Role is a named set of RBAC permissions.
+type Role struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Permissions []RolePermission `json:"permissions"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// RolePermission grants actions on workflows and workspaces matching patterns.
+type RolePermission struct {
+	Actions    []string `json:"actions"`
+	Workflows  []string `json:"workflows,omitempty"`
+	Workspaces []string `json:"workspaces,omitempty"`
+}
+
+// RoleBinding assigns roles to a subject (an authenticated user ID).
+type RoleBinding struct {
+	Subject   string    `json:"subject"`
+	Roles     []string  `json:"roles"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationDeadLetter is a run notification that failed on every
+// delivery attempt.
+type NotificationDeadLetter struct {
+	ID        string          `json:"id"`
+	RunID     string          `json:"run_id"`
+	Workflow  string          `json:"workflow"`
+	Event     string          `json:"event"`
+	Channel   string          `json:"channel"` // Channel type and target, e.g. "slack:#deploys"
+	Payload   json.RawMessage `json:"payload"`
+	Error     string          `json:"error"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// TriggerEvent is an inbound webhook or poll event and the outcome of
+// delivering it to its workflow.
+type TriggerEvent struct {
+	ID             string            `json:"id"`
+	Source         string            `json:"source"`                    // "webhook", "poll", "run_completed", "queue" or "imap"
+	Trigger        string            `json:"trigger"`                   // Webhook path or poll trigger ID
+	Workflow       string            `json:"workflow"`                  // Workflow name
+	Event          string            `json:"event,omitempty"`           // Source event type, e.g. "push"
+	IdempotencyKey string            `json:"idempotency_key,omitempty"` // Sender delivery ID; retries with the same key are not run twice
+	Headers        map[string]string `json:"headers,omitempty"`         // Request headers, with credentials redacted
+	Payload        json.RawMessage   `json:"payload,omitempty"`
+	Inputs         map[string]any    `json:"inputs,omitempty"` // Workflow inputs the event maps to
+	Matched        bool              `json:"matched"`          // Whether the event passed the trigger's filters
+	Status         string            `json:"status"`
+	Error          string            `json:"error,omitempty"`
+	RunID          string            `json:"run_id,omitempty"`
+	Attempts       int               `json:"attempts"` // Number of delivery attempts
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// TriggerEventFilter contains filtering options for listing trigger events.
+type TriggerEventFilter struct {
+	Status   string
+	Workflow string
+	Source   string
+	Limit    int
+}
+
 // ScheduleState represents the persistent state of a schedule.
 type ScheduleState struct {
 	Name       string     `json:"name"`
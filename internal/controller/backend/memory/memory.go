@@ -17,7 +17,11 @@ package memory
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,12 +31,18 @@ import (
 // Compile-time interface assertions.
 // Ensures Backend implements all segregated interfaces.
 var (
-	_ backend.RunStore        = (*Backend)(nil)
-	_ backend.RunLister       = (*Backend)(nil)
-	_ backend.CheckpointStore = (*Backend)(nil)
-	_ backend.StepResultStore = (*Backend)(nil)
-	_ backend.Backend         = (*Backend)(nil)
-	_ backend.ScheduleBackend = (*Backend)(nil)
+	_ backend.RunStore            = (*Backend)(nil)
+	_ backend.RunLister           = (*Backend)(nil)
+	_ backend.RunIdempotencyStore = (*Backend)(nil)
+	_ backend.CheckpointStore     = (*Backend)(nil)
+	_ backend.StepResultStore     = (*Backend)(nil)
+	_ backend.Backend             = (*Backend)(nil)
+	_ backend.ScheduleBackend     = (*Backend)(nil)
+	_ backend.JobQueue            = (*Backend)(nil)
+	_ backend.RunLogStore         = (*Backend)(nil)
+	_ backend.RoleStore           = (*Backend)(nil)
+	_ backend.NotificationStore   = (*Backend)(nil)
+	_ backend.TriggerEventStore   = (*Backend)(nil)
 )
 
 // Backend is an in-memory storage backend.
@@ -42,6 +52,21 @@ type Backend struct {
 	checkpoints map[string]*backend.Checkpoint
 	stepResults map[string]map[string]*backend.StepResult // runID -> stepID -> result
 	schedules   map[string]*backend.ScheduleState
+	jobs        []*queuedJob
+	runLogs     map[string][]json.RawMessage
+	roles       map[string]*backend.Role
+	bindings    map[string]*backend.RoleBinding
+	deadLetters []*backend.NotificationDeadLetter
+	events      []*backend.TriggerEvent // In the order received
+}
+
+// queuedJob is a job in the in-memory queue.
+type queuedJob struct {
+	job       backend.Job
+	running   bool
+	cancelled bool
+	lockedAt  time.Time
+	queuedAt  time.Time
 }
 
 // New creates a new in-memory backend.
@@ -51,6 +76,9 @@ func New() *Backend {
 		checkpoints: make(map[string]*backend.Checkpoint),
 		stepResults: make(map[string]map[string]*backend.StepResult),
 		schedules:   make(map[string]*backend.ScheduleState),
+		runLogs:     make(map[string][]json.RawMessage),
+		roles:       make(map[string]*backend.Role),
+		bindings:    make(map[string]*backend.RoleBinding),
 	}
 }
 
@@ -119,6 +147,39 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 	return result, nil
 }
 
+// FindRunByIdempotencyKey returns the workflow's newest run created with key since a time.
+func (b *Backend) FindRunByIdempotencyKey(ctx context.Context, workflow, key string, since time.Time) (*backend.Run, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var found *backend.Run
+	for _, run := range b.runs {
+		if run.Workflow != workflow || run.IdempotencyKey != key || run.CreatedAt.Before(since) {
+			continue
+		}
+		if found == nil || run.CreatedAt.After(found.CreatedAt) {
+			found = run
+		}
+	}
+	if found == nil {
+		return nil, backend.ErrRunNotFound
+	}
+	return found, nil
+}
+
+// ReleaseIdempotencyKey clears key from the workflow's runs created before a time.
+func (b *Backend) ReleaseIdempotencyKey(ctx context.Context, workflow, key string, before time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, run := range b.runs {
+		if run.Workflow == workflow && run.IdempotencyKey == key && run.CreatedAt.Before(before) {
+			run.IdempotencyKey = ""
+		}
+	}
+	return nil
+}
+
 // DeleteRun deletes a run.
 func (b *Backend) DeleteRun(ctx context.Context, id string) error {
 	b.mu.Lock()
@@ -254,3 +315,465 @@ func (b *Backend) ListStepResults(ctx context.Context, runID string) ([]*backend
 
 	return results, nil
 }
+
+// EnqueueJob adds a job to the queue.
+func (b *Backend) EnqueueJob(ctx context.Context, job *backend.Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, queued := range b.jobs {
+		if queued.job.RunID == job.RunID {
+			return nil
+		}
+	}
+	b.jobs = append(b.jobs, &queuedJob{
+		job:      backend.Job{RunID: job.RunID, Priority: job.Priority, FairKey: job.FairKey, Payload: job.Payload},
+		queuedAt: time.Now(),
+	})
+	return nil
+}
+
+// DequeueJob claims the next pending job: highest priority first, then the
+// fair-share group with the fewest running jobs, then the oldest.
+func (b *Backend) DequeueJob(ctx context.Context, workerID string) (*backend.Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	running := b.runningJobs()
+	var next *queuedJob
+	for _, queued := range b.jobs {
+		if !queued.running && (next == nil || jobBefore(queued, next, running)) {
+			next = queued
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.running = true
+	next.lockedAt = time.Now()
+	next.job.Attempts++
+	next.job.LockedBy = workerID
+	job := next.job
+	return &job, nil
+}
+
+// HeartbeatJob refreshes a worker's claim on a job.
+func (b *Backend) HeartbeatJob(ctx context.Context, runID, workerID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, queued := range b.jobs {
+		if queued.job.RunID == runID && queued.running && queued.job.LockedBy == workerID {
+			queued.lockedAt = time.Now()
+			if queued.cancelled {
+				return backend.ErrJobCancelled
+			}
+			return nil
+		}
+	}
+	return backend.ErrJobNotClaimed
+}
+
+// CancelJob removes a pending job, or marks a claimed job as cancelled.
+func (b *Backend) CancelJob(ctx context.Context, runID string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, queued := range b.jobs {
+		if queued.job.RunID != runID {
+			continue
+		}
+		if queued.running {
+			queued.cancelled = true
+			return false, nil
+		}
+		b.jobs = append(b.jobs[:i], b.jobs[i+1:]...)
+		return true, nil
+	}
+	return false, backend.ErrJobNotFound
+}
+
+// CompleteJob removes a job from the queue.
+func (b *Backend) CompleteJob(ctx context.Context, runID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, queued := range b.jobs {
+		if queued.job.RunID == runID {
+			b.jobs = append(b.jobs[:i], b.jobs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// FailJob returns a claimed job to the queue.
+func (b *Backend) FailJob(ctx context.Context, runID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, queued := range b.jobs {
+		if queued.job.RunID == runID {
+			queued.running = false
+			queued.job.LockedBy = ""
+		}
+	}
+	return nil
+}
+
+// RecoverStalledJobs returns jobs claimed longer than the timeout ago to the queue.
+func (b *Backend) RecoverStalledJobs(ctx context.Context, timeout time.Duration) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cutoff := time.Now().Add(-timeout)
+	var recovered int64
+	for _, queued := range b.jobs {
+		if queued.running && queued.lockedAt.Before(cutoff) {
+			queued.running = false
+			queued.job.LockedBy = ""
+			recovered++
+		}
+	}
+	return recovered, nil
+}
+
+// CountPendingJobs returns the number of jobs waiting to be claimed.
+func (b *Backend) CountPendingJobs(ctx context.Context) (int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	count := 0
+	for _, queued := range b.jobs {
+		if !queued.running {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// JobPosition returns a pending job's 1-based position by priority and age,
+// or 0 if the job is not pending.
+func (b *Backend) JobPosition(ctx context.Context, runID string) (int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var target *queuedJob
+	for _, queued := range b.jobs {
+		if queued.job.RunID == runID && !queued.running {
+			target = queued
+		}
+	}
+	if target == nil {
+		return 0, nil
+	}
+
+	running := b.runningJobs()
+	position := 1
+	for _, queued := range b.jobs {
+		if queued != target && !queued.running && jobBefore(queued, target, running) {
+			position++
+		}
+	}
+	return position, nil
+}
+
+// runningJobs counts claimed jobs per fair-share group. Callers must hold b.mu.
+func (b *Backend) runningJobs() map[string]int {
+	running := make(map[string]int)
+	for _, queued := range b.jobs {
+		if queued.running {
+			running[queued.job.FairKey]++
+		}
+	}
+	return running
+}
+
+// jobBefore reports whether a is claimed before b: by priority, then by the
+// fair-share group with fewer running jobs, then by age.
+func jobBefore(a, b *queuedJob, running map[string]int) bool {
+	if a.job.Priority != b.job.Priority {
+		return a.job.Priority > b.job.Priority
+	}
+	if running[a.job.FairKey] != running[b.job.FairKey] {
+		return running[a.job.FairKey] < running[b.job.FairKey]
+	}
+	return a.queuedAt.Before(b.queuedAt)
+}
+
+// AppendRunLog appends a log entry to a run.
+func (b *Backend) AppendRunLog(ctx context.Context, runID string, entry json.RawMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.runLogs[runID] = append(b.runLogs[runID], entry)
+	return nil
+}
+
+// ListRunLogs returns a run's log entries, skipping the first offset entries.
+func (b *Backend) ListRunLogs(ctx context.Context, runID string, offset int) ([]json.RawMessage, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	logs := b.runLogs[runID]
+	if offset >= len(logs) {
+		return nil, nil
+	}
+	result := make([]json.RawMessage, len(logs)-offset)
+	copy(result, logs[offset:])
+	return result, nil
+}
+
+// SaveRole creates or replaces a role.
+func (b *Backend) SaveRole(ctx context.Context, role *backend.Role) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	role.UpdatedAt = time.Now()
+	stored := *role
+	stored.Permissions = slices.Clone(role.Permissions)
+	b.roles[role.Name] = &stored
+	return nil
+}
+
+// GetRole retrieves a role by name.
+func (b *Backend) GetRole(ctx context.Context, name string) (*backend.Role, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	role, exists := b.roles[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", backend.ErrRoleNotFound, name)
+	}
+	result := *role
+	result.Permissions = slices.Clone(role.Permissions)
+	return &result, nil
+}
+
+// ListRoles returns all roles ordered by name.
+func (b *Backend) ListRoles(ctx context.Context) ([]*backend.Role, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make([]*backend.Role, 0, len(b.roles))
+	for _, role := range b.roles {
+		copied := *role
+		copied.Permissions = slices.Clone(role.Permissions)
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// DeleteRole deletes a role.
+func (b *Backend) DeleteRole(ctx context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.roles[name]; !exists {
+		return fmt.Errorf("%w: %s", backend.ErrRoleNotFound, name)
+	}
+	delete(b.roles, name)
+	return nil
+}
+
+// SaveRoleBinding creates or replaces the roles bound to a subject.
+func (b *Backend) SaveRoleBinding(ctx context.Context, binding *backend.RoleBinding) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	binding.UpdatedAt = time.Now()
+	stored := *binding
+	stored.Roles = slices.Clone(binding.Roles)
+	b.bindings[binding.Subject] = &stored
+	return nil
+}
+
+// GetRoleBinding retrieves a subject's role binding.
+func (b *Backend) GetRoleBinding(ctx context.Context, subject string) (*backend.RoleBinding, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	binding, exists := b.bindings[subject]
+	if !exists {
+		return nil, fmt.Errorf("%w: no binding for %s", backend.ErrRoleNotFound, subject)
+	}
+	result := *binding
+	result.Roles = slices.Clone(binding.Roles)
+	return &result, nil
+}
+
+// ListRoleBindings returns all role bindings ordered by subject.
+func (b *Backend) ListRoleBindings(ctx context.Context) ([]*backend.RoleBinding, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make([]*backend.RoleBinding, 0, len(b.bindings))
+	for _, binding := range b.bindings {
+		copied := *binding
+		copied.Roles = slices.Clone(binding.Roles)
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Subject < result[j].Subject })
+	return result, nil
+}
+
+// DeleteRoleBinding deletes a subject's role binding.
+func (b *Backend) DeleteRoleBinding(ctx context.Context, subject string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.bindings[subject]; !exists {
+		return fmt.Errorf("%w: no binding for %s", backend.ErrRoleNotFound, subject)
+	}
+	delete(b.bindings, subject)
+	return nil
+}
+
+// SaveNotificationDeadLetter records an undelivered notification.
+func (b *Backend) SaveNotificationDeadLetter(ctx context.Context, letter *backend.NotificationDeadLetter) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if letter.CreatedAt.IsZero() {
+		letter.CreatedAt = time.Now()
+	}
+	stored := *letter
+	stored.Payload = slices.Clone(letter.Payload)
+	b.deadLetters = append(b.deadLetters, &stored)
+	return nil
+}
+
+// ListNotificationDeadLetters returns undelivered notifications, newest first.
+func (b *Backend) ListNotificationDeadLetters(ctx context.Context, limit int) ([]*backend.NotificationDeadLetter, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make([]*backend.NotificationDeadLetter, 0, len(b.deadLetters))
+	for i := len(b.deadLetters) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		copied := *b.deadLetters[i]
+		copied.Payload = slices.Clone(b.deadLetters[i].Payload)
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// CreateTriggerEvent records a new trigger event.
+func (b *Backend) CreateTriggerEvent(ctx context.Context, event *backend.TriggerEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, existing := range b.events {
+		if existing.ID == event.ID {
+			return fmt.Errorf("trigger event %s already exists", event.ID)
+		}
+		if event.IdempotencyKey != "" && existing.Trigger == event.Trigger && existing.IdempotencyKey == event.IdempotencyKey {
+			return fmt.Errorf("%w: %s", backend.ErrDuplicateTriggerEvent, event.IdempotencyKey)
+		}
+	}
+
+	now := time.Now()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = now
+	}
+	event.UpdatedAt = now
+	b.events = append(b.events, copyTriggerEvent(event))
+	return nil
+}
+
+// UpdateTriggerEvent updates a trigger event.
+func (b *Backend) UpdateTriggerEvent(ctx context.Context, event *backend.TriggerEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, existing := range b.events {
+		if existing.ID == event.ID {
+			event.UpdatedAt = time.Now()
+			b.events[i] = copyTriggerEvent(event)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, event.ID)
+}
+
+// GetTriggerEvent retrieves a trigger event by ID.
+func (b *Backend) GetTriggerEvent(ctx context.Context, id string) (*backend.TriggerEvent, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, event := range b.events {
+		if event.ID == id {
+			return copyTriggerEvent(event), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, id)
+}
+
+// FindTriggerEvent retrieves the event with an idempotency key for a trigger.
+func (b *Backend) FindTriggerEvent(ctx context.Context, trigger, idempotencyKey string) (*backend.TriggerEvent, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, event := range b.events {
+		if idempotencyKey != "" && event.Trigger == trigger && event.IdempotencyKey == idempotencyKey {
+			return copyTriggerEvent(event), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, idempotencyKey)
+}
+
+// ListTriggerEvents lists trigger events newest first.
+func (b *Backend) ListTriggerEvents(ctx context.Context, filter backend.TriggerEventFilter) ([]*backend.TriggerEvent, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var result []*backend.TriggerEvent
+	for i := len(b.events) - 1; i >= 0; i-- {
+		event := b.events[i]
+		if filter.Status != "" && event.Status != filter.Status {
+			continue
+		}
+		if filter.Workflow != "" && event.Workflow != filter.Workflow {
+			continue
+		}
+		if filter.Source != "" && event.Source != filter.Source {
+			continue
+		}
+		result = append(result, copyTriggerEvent(event))
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// DeleteTriggerEvents deletes trigger events received before a time.
+func (b *Backend) DeleteTriggerEvents(ctx context.Context, before time.Time) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	kept := b.events[:0]
+	for _, event := range b.events {
+		if event.CreatedAt.Before(before) {
+			continue
+		}
+		kept = append(kept, event)
+	}
+	deleted := int64(len(b.events) - len(kept))
+	clear(b.events[len(kept):])
+	b.events = kept
+	return deleted, nil
+}
+
+// copyTriggerEvent copies an event so callers cannot modify stored state.
+func copyTriggerEvent(event *backend.TriggerEvent) *backend.TriggerEvent {
+	copied := *event
+	copied.Headers = maps.Clone(event.Headers)
+	copied.Payload = slices.Clone(event.Payload)
+	copied.Inputs = maps.Clone(event.Inputs)
+	return &copied
+}
@@ -3,7 +3,9 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -432,6 +434,343 @@ func TestBackend_ScheduleState(t *testing.T) {
 	})
 }
 
+func TestBackend_JobQueue(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	for _, job := range []*backend.Job{
+		{RunID: "low", Priority: 0},
+		{RunID: "high", Priority: 5, Payload: []byte(`{"workflow":"x"}`)},
+		{RunID: "low", Priority: 9}, // duplicate is ignored
+	} {
+		if err := b.EnqueueJob(ctx, job); err != nil {
+			t.Fatalf("EnqueueJob() error = %v", err)
+		}
+	}
+
+	job, err := b.DequeueJob(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("DequeueJob() error = %v", err)
+	}
+	if job == nil || job.RunID != "high" || job.Attempts != 1 || string(job.Payload) != `{"workflow":"x"}` {
+		t.Fatalf("DequeueJob() = %+v, want high priority job", job)
+	}
+
+	if err := b.HeartbeatJob(ctx, "high", "worker-1"); err != nil {
+		t.Errorf("HeartbeatJob() error = %v", err)
+	}
+	if err := b.HeartbeatJob(ctx, "high", "worker-2"); err != backend.ErrJobNotClaimed {
+		t.Errorf("HeartbeatJob() by other worker = %v, want ErrJobNotClaimed", err)
+	}
+
+	// Stalled jobs return to the queue and can be claimed again
+	time.Sleep(5 * time.Millisecond)
+	recovered, err := b.RecoverStalledJobs(ctx, time.Millisecond)
+	if err != nil || recovered != 1 {
+		t.Fatalf("RecoverStalledJobs() = %d, %v; want 1", recovered, err)
+	}
+	if err := b.HeartbeatJob(ctx, "high", "worker-1"); err != backend.ErrJobNotClaimed {
+		t.Errorf("HeartbeatJob() after recovery = %v, want ErrJobNotClaimed", err)
+	}
+	job, _ = b.DequeueJob(ctx, "worker-2")
+	if job == nil || job.RunID != "high" || job.Attempts != 2 {
+		t.Fatalf("DequeueJob() after recovery = %+v", job)
+	}
+
+	if err := b.CompleteJob(ctx, "high"); err != nil {
+		t.Fatalf("CompleteJob() error = %v", err)
+	}
+	job, _ = b.DequeueJob(ctx, "worker-1")
+	if job == nil || job.RunID != "low" {
+		t.Fatalf("DequeueJob() = %+v, want low priority job", job)
+	}
+	if err := b.FailJob(ctx, "low"); err != nil {
+		t.Fatalf("FailJob() error = %v", err)
+	}
+	if job, _ := b.DequeueJob(ctx, "worker-2"); job == nil || job.RunID != "low" {
+		t.Errorf("expected failed job to be claimable again, got %+v", job)
+	}
+	if job, _ := b.DequeueJob(ctx, "worker-2"); job != nil {
+		t.Errorf("expected empty queue, got %+v", job)
+	}
+}
+
+func TestBackend_CancelJob(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	for _, id := range []string{"queued", "claimed"} {
+		if err := b.EnqueueJob(ctx, &backend.Job{RunID: id}); err != nil {
+			t.Fatalf("EnqueueJob() error = %v", err)
+		}
+	}
+	if job, _ := b.DequeueJob(ctx, "worker-1"); job == nil || job.RunID != "queued" {
+		t.Fatalf("DequeueJob() = %+v", job)
+	}
+	if removed, err := b.CancelJob(ctx, "claimed"); err != nil || !removed {
+		t.Errorf("CancelJob() pending = %v, %v; want true", removed, err)
+	}
+	if job, _ := b.DequeueJob(ctx, "worker-2"); job != nil {
+		t.Errorf("expected cancelled job to leave the queue, got %+v", job)
+	}
+
+	// A claimed job is cancelled by its worker at the next heartbeat
+	if removed, err := b.CancelJob(ctx, "queued"); err != nil || removed {
+		t.Errorf("CancelJob() claimed = %v, %v; want false", removed, err)
+	}
+	if err := b.HeartbeatJob(ctx, "queued", "worker-1"); err != backend.ErrJobCancelled {
+		t.Errorf("HeartbeatJob() = %v, want ErrJobCancelled", err)
+	}
+
+	if _, err := b.CancelJob(ctx, "unknown"); err != backend.ErrJobNotFound {
+		t.Errorf("CancelJob() unknown = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestBackend_JobQueueFairShare(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	for _, job := range []*backend.Job{
+		{RunID: "a-1", Priority: 0, FairKey: "team-a"},
+		{RunID: "a-2", Priority: 0, FairKey: "team-a"},
+		{RunID: "b-1", Priority: 0, FairKey: "team-b"},
+		{RunID: "urgent", Priority: 10, FairKey: "team-a"},
+	} {
+		if err := b.EnqueueJob(ctx, job); err != nil {
+			t.Fatalf("EnqueueJob() error = %v", err)
+		}
+	}
+
+	if count, _ := b.CountPendingJobs(ctx); count != 4 {
+		t.Errorf("CountPendingJobs() = %d, want 4", count)
+	}
+	if position, _ := b.JobPosition(ctx, "b-1"); position != 4 {
+		t.Errorf("JobPosition(b-1) = %d, want 4", position)
+	}
+
+	// Priority wins, then the group with fewer running jobs
+	var order []string
+	for {
+		job, err := b.DequeueJob(ctx, "worker")
+		if err != nil {
+			t.Fatalf("DequeueJob() error = %v", err)
+		}
+		if job == nil {
+			break
+		}
+		order = append(order, job.RunID)
+	}
+	want := []string{"urgent", "b-1", "a-1", "a-2"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("dequeue order = %v, want %v", order, want)
+	}
+
+	if position, _ := b.JobPosition(ctx, "a-2"); position != 0 {
+		t.Errorf("JobPosition() of a claimed job = %d, want 0", position)
+	}
+}
+
+func TestBackend_JobPositionFairShare(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	for _, job := range []*backend.Job{
+		{RunID: "a-1", Priority: 0, FairKey: "team-a"},
+		{RunID: "a-2", Priority: 0, FairKey: "team-a"},
+		{RunID: "b-1", Priority: 0, FairKey: "team-b"},
+	} {
+		if err := b.EnqueueJob(ctx, job); err != nil {
+			t.Fatalf("EnqueueJob() error = %v", err)
+		}
+	}
+	if job, _ := b.DequeueJob(ctx, "worker"); job == nil || job.RunID != "a-1" {
+		t.Fatalf("DequeueJob() = %+v, want a-1", job)
+	}
+
+	// team-a has a running job, so team-b's job is claimed next
+	if position, _ := b.JobPosition(ctx, "b-1"); position != 1 {
+		t.Errorf("JobPosition(b-1) = %d, want 1", position)
+	}
+	if position, _ := b.JobPosition(ctx, "a-2"); position != 2 {
+		t.Errorf("JobPosition(a-2) = %d, want 2", position)
+	}
+	if job, _ := b.DequeueJob(ctx, "worker"); job == nil || job.RunID != "b-1" {
+		t.Errorf("DequeueJob() = %+v, want b-1", job)
+	}
+}
+
+func TestBackend_RunLogs(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	for _, entry := range []string{`{"message":"one"}`, `{"message":"two"}`, `{"message":"three"}`} {
+		if err := b.AppendRunLog(ctx, "run-1", []byte(entry)); err != nil {
+			t.Fatalf("AppendRunLog() error = %v", err)
+		}
+	}
+
+	logs, err := b.ListRunLogs(ctx, "run-1", 1)
+	if err != nil {
+		t.Fatalf("ListRunLogs() error = %v", err)
+	}
+	if len(logs) != 2 || string(logs[0]) != `{"message":"two"}` {
+		t.Errorf("ListRunLogs() = %s", logs)
+	}
+	if logs, _ := b.ListRunLogs(ctx, "run-1", 3); len(logs) != 0 {
+		t.Errorf("expected no logs past the end, got %s", logs)
+	}
+}
+
+func TestBackend_Roles(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	role := &backend.Role{
+		Name: "deployer",
+		Permissions: []backend.RolePermission{
+			{Actions: []string{"run", "cancel"}, Workflows: []string{"deploy-*"}},
+		},
+	}
+	if err := b.SaveRole(ctx, role); err != nil {
+		t.Fatalf("SaveRole() error = %v", err)
+	}
+	got, err := b.GetRole(ctx, "deployer")
+	if err != nil {
+		t.Fatalf("GetRole() error = %v", err)
+	}
+	if len(got.Permissions) != 1 || got.Permissions[0].Workflows[0] != "deploy-*" || got.UpdatedAt.IsZero() {
+		t.Errorf("GetRole() = %+v", got)
+	}
+	if roles, _ := b.ListRoles(ctx); len(roles) != 1 {
+		t.Errorf("ListRoles() returned %d roles, want 1", len(roles))
+	}
+
+	if err := b.SaveRoleBinding(ctx, &backend.RoleBinding{Subject: "alice", Roles: []string{"deployer", "viewer"}}); err != nil {
+		t.Fatalf("SaveRoleBinding() error = %v", err)
+	}
+	binding, err := b.GetRoleBinding(ctx, "alice")
+	if err != nil || len(binding.Roles) != 2 {
+		t.Errorf("GetRoleBinding() = %+v, %v", binding, err)
+	}
+	if bindings, _ := b.ListRoleBindings(ctx); len(bindings) != 1 {
+		t.Errorf("ListRoleBindings() returned %d bindings, want 1", len(bindings))
+	}
+
+	if err := b.DeleteRole(ctx, "deployer"); err != nil {
+		t.Fatalf("DeleteRole() error = %v", err)
+	}
+	if _, err := b.GetRole(ctx, "deployer"); !errors.Is(err, backend.ErrRoleNotFound) {
+		t.Errorf("GetRole() after delete error = %v, want ErrRoleNotFound", err)
+	}
+	if err := b.DeleteRoleBinding(ctx, "alice"); err != nil {
+		t.Fatalf("DeleteRoleBinding() error = %v", err)
+	}
+	if err := b.DeleteRoleBinding(ctx, "alice"); !errors.Is(err, backend.ErrRoleNotFound) {
+		t.Errorf("DeleteRoleBinding() twice error = %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestBackend_NotificationDeadLetters(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	letters := []*backend.NotificationDeadLetter{
+		{ID: "dl-1", RunID: "run-1", Workflow: "deploy", Event: "failed", Channel: "webhook:https://hooks.example.com",
+			Payload: []byte(`{"event":"failed"}`), Error: "status 500", Attempts: 5, CreatedAt: time.Now().Add(-time.Minute)},
+		{ID: "dl-2", RunID: "run-2", Workflow: "deploy", Event: "started", Channel: "slack:#ops",
+			Payload: []byte(`{"event":"started"}`), Error: "channel_not_found", Attempts: 1},
+	}
+	for _, letter := range letters {
+		if err := b.SaveNotificationDeadLetter(ctx, letter); err != nil {
+			t.Fatalf("SaveNotificationDeadLetter() error = %v", err)
+		}
+	}
+
+	got, err := b.ListNotificationDeadLetters(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListNotificationDeadLetters() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "dl-2" || got[1].ID != "dl-1" {
+		t.Fatalf("ListNotificationDeadLetters() = %+v, want newest first", got)
+	}
+	if string(got[1].Payload) != `{"event":"failed"}` || got[1].Attempts != 5 || got[1].Error != "status 500" {
+		t.Errorf("dead letter = %+v", got[1])
+	}
+
+	if got, _ := b.ListNotificationDeadLetters(ctx, 1); len(got) != 1 {
+		t.Errorf("ListNotificationDeadLetters(1) returned %d, want 1", len(got))
+	}
+}
+
+func TestBackend_TriggerEvents(t *testing.T) {
+	b := New()
+	ctx := context.Background()
+
+	old := &backend.TriggerEvent{ID: "ev-1", Source: "webhook", Trigger: "/webhooks/deploy", Workflow: "deploy",
+		Event: "push", IdempotencyKey: "delivery-1", Headers: map[string]string{"X-GitHub-Event": "push"},
+		Payload: []byte(`{"ref":"main"}`), Inputs: map[string]any{"ref": "main"}, Matched: true,
+		Status: backend.TriggerEventReceived, CreatedAt: time.Now().Add(-2 * time.Hour)}
+	if err := b.CreateTriggerEvent(ctx, old); err != nil {
+		t.Fatalf("CreateTriggerEvent() error = %v", err)
+	}
+	recent := &backend.TriggerEvent{ID: "ev-2", Source: "poll", Trigger: "deploy.yaml:jira", Workflow: "deploy",
+		Status: backend.TriggerEventIgnored}
+	if err := b.CreateTriggerEvent(ctx, recent); err != nil {
+		t.Fatalf("CreateTriggerEvent() error = %v", err)
+	}
+
+	// A retry with the same idempotency key is rejected
+	retry := &backend.TriggerEvent{ID: "ev-3", Source: "webhook", Trigger: "/webhooks/deploy", Workflow: "deploy",
+		IdempotencyKey: "delivery-1", Status: backend.TriggerEventReceived}
+	if err := b.CreateTriggerEvent(ctx, retry); !errors.Is(err, backend.ErrDuplicateTriggerEvent) {
+		t.Fatalf("CreateTriggerEvent(duplicate) error = %v, want ErrDuplicateTriggerEvent", err)
+	}
+
+	old.Status = backend.TriggerEventFailed
+	old.Error = "workflow not found"
+	old.Attempts = 1
+	if err := b.UpdateTriggerEvent(ctx, old); err != nil {
+		t.Fatalf("UpdateTriggerEvent() error = %v", err)
+	}
+
+	got, err := b.FindTriggerEvent(ctx, "/webhooks/deploy", "delivery-1")
+	if err != nil {
+		t.Fatalf("FindTriggerEvent() error = %v", err)
+	}
+	if got.ID != "ev-1" || got.Status != backend.TriggerEventFailed || got.Attempts != 1 || !got.Matched ||
+		got.Headers["X-GitHub-Event"] != "push" || got.Inputs["ref"] != "main" || string(got.Payload) != `{"ref":"main"}` {
+		t.Errorf("FindTriggerEvent() = %+v", got)
+	}
+	if _, err := b.GetTriggerEvent(ctx, "missing"); !errors.Is(err, backend.ErrTriggerEventNotFound) {
+		t.Errorf("GetTriggerEvent(missing) error = %v, want ErrTriggerEventNotFound", err)
+	}
+
+	events, err := b.ListTriggerEvents(ctx, backend.TriggerEventFilter{})
+	if err != nil {
+		t.Fatalf("ListTriggerEvents() error = %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "ev-2" || events[1].ID != "ev-1" {
+		t.Fatalf("ListTriggerEvents() = %+v, want newest first", events)
+	}
+	events, _ = b.ListTriggerEvents(ctx, backend.TriggerEventFilter{Status: backend.TriggerEventFailed})
+	if len(events) != 1 || events[0].ID != "ev-1" {
+		t.Errorf("ListTriggerEvents(failed) = %+v", events)
+	}
+	events, _ = b.ListTriggerEvents(ctx, backend.TriggerEventFilter{Source: "poll", Limit: 5})
+	if len(events) != 1 || events[0].ID != "ev-2" {
+		t.Errorf("ListTriggerEvents(poll) = %+v", events)
+	}
+
+	deleted, err := b.DeleteTriggerEvents(ctx, time.Now().Add(-time.Hour))
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteTriggerEvents() = %d, %v; want 1", deleted, err)
+	}
+	if _, err := b.GetTriggerEvent(ctx, "ev-1"); !errors.Is(err, backend.ErrTriggerEventNotFound) {
+		t.Errorf("GetTriggerEvent(ev-1) error = %v after delete", err)
+	}
+}
+
 func TestBackend_Close(t *testing.T) {
 	b := New()
 	err := b.Close()
@@ -19,7 +19,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/tombee/conductor/internal/controller/backend"
@@ -28,12 +30,18 @@ import (
 // Compile-time interface assertions.
 // Ensures Backend implements all segregated interfaces.
 var (
-	_ backend.RunStore        = (*Backend)(nil)
-	_ backend.RunLister       = (*Backend)(nil)
-	_ backend.CheckpointStore = (*Backend)(nil)
-	_ backend.StepResultStore = (*Backend)(nil)
-	_ backend.Backend         = (*Backend)(nil)
-	_ backend.ScheduleBackend = (*Backend)(nil)
+	_ backend.RunStore            = (*Backend)(nil)
+	_ backend.RunLister           = (*Backend)(nil)
+	_ backend.RunIdempotencyStore = (*Backend)(nil)
+	_ backend.CheckpointStore     = (*Backend)(nil)
+	_ backend.StepResultStore     = (*Backend)(nil)
+	_ backend.Backend             = (*Backend)(nil)
+	_ backend.ScheduleBackend     = (*Backend)(nil)
+	_ backend.JobQueue            = (*Backend)(nil)
+	_ backend.RunLogStore         = (*Backend)(nil)
+	_ backend.RoleStore           = (*Backend)(nil)
+	_ backend.NotificationStore   = (*Backend)(nil)
+	_ backend.TriggerEventStore   = (*Backend)(nil)
 )
 
 // Backend is a PostgreSQL storage backend.
@@ -163,6 +171,74 @@ func (b *Backend) migrate(ctx context.Context) error {
 		`CREATE INDEX IF NOT EXISTS idx_runs_parent_run_id ON runs(parent_run_id)`,
 		// Add cost_usd column to step_results table
 		`ALTER TABLE step_results ADD COLUMN IF NOT EXISTS cost_usd DOUBLE PRECISION DEFAULT 0`,
+		// Add job payload and claim count for distributed workers
+		`ALTER TABLE job_queue ADD COLUMN IF NOT EXISTS payload JSONB`,
+		`ALTER TABLE job_queue ADD COLUMN IF NOT EXISTS attempts INTEGER DEFAULT 0`,
+		`CREATE TABLE IF NOT EXISTS run_logs (
+			id BIGSERIAL PRIMARY KEY,
+			run_id VARCHAR(36) NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+			entry JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_run_logs_run_id ON run_logs(run_id, id)`,
+		// Add fair-share group for queue scheduling
+		`ALTER TABLE job_queue ADD COLUMN IF NOT EXISTS fair_key VARCHAR(255) NOT NULL DEFAULT ''`,
+		// Cancellation requested for a claimed job
+		`ALTER TABLE job_queue ADD COLUMN IF NOT EXISTS cancel_requested BOOLEAN NOT NULL DEFAULT false`,
+		// Idempotency keys sent with run creation requests
+		`ALTER TABLE runs ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255) NOT NULL DEFAULT ''`,
+		`ALTER TABLE runs ADD COLUMN IF NOT EXISTS request_hash VARCHAR(64) NOT NULL DEFAULT ''`,
+		`CREATE INDEX IF NOT EXISTS idx_runs_idempotency_key ON runs(idempotency_key, created_at) WHERE idempotency_key <> ''`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_runs_workflow_idempotency_key ON runs(workflow, idempotency_key) WHERE idempotency_key <> ''`,
+		// Workspace the run was submitted to, used for authorization
+		`ALTER TABLE runs ADD COLUMN IF NOT EXISTS workspace VARCHAR(255) NOT NULL DEFAULT ''`,
+		// RBAC roles and role bindings
+		`CREATE TABLE IF NOT EXISTS roles (
+			name VARCHAR(255) PRIMARY KEY,
+			description TEXT,
+			permissions JSONB NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS role_bindings (
+			subject VARCHAR(255) PRIMARY KEY,
+			roles JSONB NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		// Run notifications that failed every delivery attempt
+		`CREATE TABLE IF NOT EXISTS notification_dead_letters (
+			id VARCHAR(36) PRIMARY KEY,
+			run_id VARCHAR(36) NOT NULL,
+			workflow VARCHAR(255) NOT NULL,
+			event VARCHAR(64) NOT NULL,
+			channel VARCHAR(255) NOT NULL,
+			payload JSONB NOT NULL,
+			error TEXT,
+			attempts INTEGER DEFAULT 0,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notification_dead_letters_created_at ON notification_dead_letters(created_at)`,
+		// Inbound webhook and poll trigger events
+		`CREATE TABLE IF NOT EXISTS trigger_events (
+			id VARCHAR(36) PRIMARY KEY,
+			source VARCHAR(32) NOT NULL,
+			trigger_id VARCHAR(512) NOT NULL,
+			workflow VARCHAR(255) NOT NULL,
+			event VARCHAR(255),
+			idempotency_key VARCHAR(255),
+			headers JSONB,
+			payload JSONB,
+			inputs JSONB,
+			matched BOOLEAN DEFAULT FALSE,
+			status VARCHAR(32) NOT NULL,
+			error TEXT,
+			run_id VARCHAR(36),
+			attempts INTEGER DEFAULT 0,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_trigger_events_idempotency ON trigger_events(trigger_id, idempotency_key) WHERE idempotency_key <> ''`,
+		`CREATE INDEX IF NOT EXISTS idx_trigger_events_created_at ON trigger_events(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_trigger_events_status ON trigger_events(status)`,
 	}
 
 	for _, migration := range migrations {
@@ -196,8 +272,9 @@ func (b *Backend) CreateRun(ctx context.Context, run *backend.Run) error {
 
 	query := `
 		INSERT INTO runs (id, workflow_id, workflow, status, correlation_id, inputs, output, error,
-			current_step, completed, total, parent_run_id, replay_config, started_at, completed_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			current_step, completed, total, parent_run_id, replay_config, started_at, completed_at, created_at, updated_at,
+			idempotency_key, request_hash, workspace)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 	`
 
 	now := time.Now()
@@ -207,7 +284,11 @@ func (b *Backend) CreateRun(ctx context.Context, run *backend.Run) error {
 		run.CurrentStep, run.Completed, run.Total,
 		run.ParentRunID, replayConfigJSON,
 		run.StartedAt, run.CompletedAt, now, now,
+		run.IdempotencyKey, run.RequestHash, run.Workspace,
 	)
+	if err != nil && run.IdempotencyKey != "" && isUniqueViolation(err) {
+		return backend.ErrIdempotencyKeyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create run: %w", err)
 	}
@@ -217,12 +298,59 @@ func (b *Backend) CreateRun(ctx context.Context, run *backend.Run) error {
 	return nil
 }
 
+// FindRunByIdempotencyKey returns the workflow's newest run created with key since a time.
+func (b *Backend) FindRunByIdempotencyKey(ctx context.Context, workflow, key string, since time.Time) (*backend.Run, error) {
+	var id, requestHash string
+	err := b.db.QueryRowContext(ctx, `
+		SELECT id, request_hash FROM runs
+		WHERE workflow = $1 AND idempotency_key = $2 AND created_at >= $3
+		ORDER BY created_at DESC LIMIT 1
+	`, workflow, key, since).Scan(&id, &requestHash)
+	if err == sql.ErrNoRows {
+		return nil, backend.ErrRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find run: %w", err)
+	}
+
+	run, err := b.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	run.IdempotencyKey = key
+	run.RequestHash = requestHash
+	return run, nil
+}
+
+// ReleaseIdempotencyKey clears key from the workflow's runs created before a time.
+func (b *Backend) ReleaseIdempotencyKey(ctx context.Context, workflow, key string, before time.Time) error {
+	_, err := b.db.ExecContext(ctx, `
+		UPDATE runs SET idempotency_key = ''
+		WHERE workflow = $1 AND idempotency_key = $2 AND created_at < $3
+	`, workflow, key, before)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation
+// (SQLSTATE 23505), from any driver.
+func isUniqueViolation(err error) bool {
+	var state interface{ SQLState() string }
+	if errors.As(err, &state) {
+		return state.SQLState() == "23505"
+	}
+	return strings.Contains(err.Error(), "SQLSTATE 23505") ||
+		strings.Contains(err.Error(), "duplicate key value violates unique constraint")
+}
+
 // GetRun retrieves a run by ID.
 func (b *Backend) GetRun(ctx context.Context, id string) (*backend.Run, error) {
 	query := `
 		SELECT id, workflow_id, workflow, status, correlation_id, inputs, output, error,
 			current_step, completed, total, parent_run_id, replay_config,
-			started_at, completed_at, created_at, updated_at
+			started_at, completed_at, created_at, updated_at, workspace
 		FROM runs WHERE id = $1
 	`
 
@@ -235,7 +363,7 @@ func (b *Backend) GetRun(ctx context.Context, id string) (*backend.Run, error) {
 		&inputsJSON, &outputJSON, &run.Error,
 		&run.CurrentStep, &run.Completed, &run.Total,
 		&parentRunID, &replayConfigJSON,
-		&run.StartedAt, &run.CompletedAt, &run.CreatedAt, &run.UpdatedAt,
+		&run.StartedAt, &run.CompletedAt, &run.CreatedAt, &run.UpdatedAt, &run.Workspace,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("run not found: %s", id)
@@ -320,7 +448,7 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 	query := `
 		SELECT id, workflow_id, workflow, status, correlation_id, inputs, output, error,
 			current_step, completed, total, parent_run_id, replay_config,
-			started_at, completed_at, created_at, updated_at
+			started_at, completed_at, created_at, updated_at, workspace
 		FROM runs WHERE 1=1
 	`
 	args := []any{}
@@ -366,7 +494,7 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 			&inputsJSON, &outputJSON, &run.Error,
 			&run.CurrentStep, &run.Completed, &run.Total,
 			&parentRunID, &replayConfigJSON,
-			&run.StartedAt, &run.CompletedAt, &run.CreatedAt, &run.UpdatedAt,
+			&run.StartedAt, &run.CompletedAt, &run.CreatedAt, &run.UpdatedAt, &run.Workspace,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan run: %w", err)
@@ -472,13 +600,17 @@ func (b *Backend) DB() *sql.DB {
 // --- Distributed Job Queue Operations ---
 
 // EnqueueJob adds a job to the queue.
-func (b *Backend) EnqueueJob(ctx context.Context, runID string, priority int) error {
+func (b *Backend) EnqueueJob(ctx context.Context, job *backend.Job) error {
 	query := `
-		INSERT INTO job_queue (run_id, priority, status, created_at)
-		VALUES ($1, $2, 'pending', NOW())
+		INSERT INTO job_queue (run_id, priority, fair_key, payload, status, created_at)
+		VALUES ($1, $2, $3, $4, 'pending', NOW())
 		ON CONFLICT (run_id) DO NOTHING
 	`
-	_, err := b.db.ExecContext(ctx, query, runID, priority)
+	var payload []byte
+	if len(job.Payload) > 0 {
+		payload = job.Payload
+	}
+	_, err := b.db.ExecContext(ctx, query, job.RunID, job.Priority, job.FairKey, payload)
 	if err != nil {
 		return fmt.Errorf("failed to enqueue job: %w", err)
 	}
@@ -487,46 +619,98 @@ func (b *Backend) EnqueueJob(ctx context.Context, runID string, priority int) er
 
 // DequeueJob claims and returns the next available job using row locking.
 // This implements "SELECT FOR UPDATE SKIP LOCKED" for distributed job claiming.
-func (b *Backend) DequeueJob(ctx context.Context, workerID string) (string, error) {
+func (b *Backend) DequeueJob(ctx context.Context, workerID string) (*backend.Job, error) {
 	tx, err := b.db.BeginTx(ctx, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Select and lock the next available job
+	// Select and lock the next available job, favouring fair-share groups
+	// with the fewest running jobs within a priority
 	query := `
-		SELECT run_id FROM job_queue
-		WHERE status = 'pending'
-		ORDER BY priority DESC, created_at ASC
+		SELECT q.run_id, q.priority, q.fair_key, q.payload, q.attempts FROM job_queue q
+		WHERE q.status = 'pending'
+		ORDER BY q.priority DESC,
+			(SELECT COUNT(*) FROM job_queue r WHERE r.status = 'running' AND r.fair_key = q.fair_key) ASC,
+			q.created_at ASC
 		LIMIT 1
 		FOR UPDATE SKIP LOCKED
 	`
 
-	var runID string
-	err = tx.QueryRowContext(ctx, query).Scan(&runID)
+	var job backend.Job
+	var payload []byte
+	err = tx.QueryRowContext(ctx, query).Scan(&job.RunID, &job.Priority, &job.FairKey, &payload, &job.Attempts)
 	if err == sql.ErrNoRows {
-		return "", nil // No jobs available
+		return nil, nil // No jobs available
 	}
 	if err != nil {
-		return "", fmt.Errorf("failed to dequeue job: %w", err)
+		return nil, fmt.Errorf("failed to dequeue job: %w", err)
 	}
 
 	// Mark the job as running
 	updateQuery := `
-		UPDATE job_queue SET status = 'running', locked_by = $1, locked_at = NOW()
+		UPDATE job_queue SET status = 'running', locked_by = $1, locked_at = NOW(), attempts = attempts + 1
 		WHERE run_id = $2
 	`
-	_, err = tx.ExecContext(ctx, updateQuery, workerID, runID)
+	_, err = tx.ExecContext(ctx, updateQuery, workerID, job.RunID)
 	if err != nil {
-		return "", fmt.Errorf("failed to lock job: %w", err)
+		return nil, fmt.Errorf("failed to lock job: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	job.Payload = payload
+	job.Attempts++
+	job.LockedBy = workerID
+	return &job, nil
+}
+
+// HeartbeatJob refreshes a worker's claim on a job.
+func (b *Backend) HeartbeatJob(ctx context.Context, runID, workerID string) error {
+	query := `
+		UPDATE job_queue SET locked_at = NOW()
+		WHERE run_id = $1 AND locked_by = $2 AND status = 'running'
+		RETURNING cancel_requested
+	`
+	var cancelled bool
+	err := b.db.QueryRowContext(ctx, query, runID, workerID).Scan(&cancelled)
+	if err == sql.ErrNoRows {
+		return backend.ErrJobNotClaimed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to heartbeat job: %w", err)
+	}
+	if cancelled {
+		return backend.ErrJobCancelled
+	}
+	return nil
+}
+
+// CancelJob removes a pending job, or marks a claimed job as cancelled.
+func (b *Backend) CancelJob(ctx context.Context, runID string) (bool, error) {
+	result, err := b.db.ExecContext(ctx, "DELETE FROM job_queue WHERE run_id = $1 AND status = 'pending'", runID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		return true, nil
 	}
 
-	return runID, nil
+	result, err = b.db.ExecContext(ctx, "UPDATE job_queue SET cancel_requested = true WHERE run_id = $1", runID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	if rows == 0 {
+		return false, backend.ErrJobNotFound
+	}
+	return false, nil
 }
 
 // CompleteJob marks a job as completed.
@@ -564,6 +748,73 @@ func (b *Backend) RecoverStalledJobs(ctx context.Context, timeout time.Duration)
 	return result.RowsAffected()
 }
 
+// CountPendingJobs returns the number of jobs waiting to be claimed.
+func (b *Backend) CountPendingJobs(ctx context.Context) (int, error) {
+	var count int
+	err := b.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM job_queue WHERE status = 'pending'").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending jobs: %w", err)
+	}
+	return count, nil
+}
+
+// JobPosition returns a pending job's 1-based position in the order
+// DequeueJob claims jobs, or 0 if the job is not pending.
+func (b *Backend) JobPosition(ctx context.Context, runID string) (int, error) {
+	query := `
+		SELECT position FROM (
+			SELECT q.run_id, ROW_NUMBER() OVER (
+				ORDER BY q.priority DESC,
+					(SELECT COUNT(*) FROM job_queue r WHERE r.status = 'running' AND r.fair_key = q.fair_key) ASC,
+					q.created_at ASC
+			) AS position
+			FROM job_queue q
+			WHERE q.status = 'pending'
+		) pending
+		WHERE run_id = $1
+	`
+	var position int
+	err := b.db.QueryRowContext(ctx, query, runID).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get job position: %w", err)
+	}
+	return position, nil
+}
+
+// --- Run Log Operations ---
+
+// AppendRunLog appends a log entry to a run.
+func (b *Backend) AppendRunLog(ctx context.Context, runID string, entry json.RawMessage) error {
+	_, err := b.db.ExecContext(ctx, "INSERT INTO run_logs (run_id, entry) VALUES ($1, $2)", runID, []byte(entry))
+	if err != nil {
+		return fmt.Errorf("failed to append run log: %w", err)
+	}
+	return nil
+}
+
+// ListRunLogs returns a run's log entries in order, skipping the first offset entries.
+func (b *Backend) ListRunLogs(ctx context.Context, runID string, offset int) ([]json.RawMessage, error) {
+	query := `SELECT entry FROM run_logs WHERE run_id = $1 ORDER BY id OFFSET $2`
+	rows, err := b.db.QueryContext(ctx, query, runID, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run logs: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []json.RawMessage
+	for rows.Next() {
+		var entry []byte
+		if err := rows.Scan(&entry); err != nil {
+			return nil, fmt.Errorf("failed to scan run log: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
 // --- Schedule State Operations ---
 
 // SaveScheduleState saves or updates a schedule state.
@@ -783,3 +1034,397 @@ func (b *Backend) ListStepResults(ctx context.Context, runID string) ([]*backend
 
 	return results, nil
 }
+
+// SaveRole creates or replaces a role.
+func (b *Backend) SaveRole(ctx context.Context, role *backend.Role) error {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal permissions: %w", err)
+	}
+
+	now := time.Now()
+	_, err = b.db.ExecContext(ctx, `
+		INSERT INTO roles (name, description, permissions, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			permissions = EXCLUDED.permissions,
+			updated_at = EXCLUDED.updated_at
+	`, role.Name, role.Description, permissions, now)
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+
+	role.UpdatedAt = now
+	return nil
+}
+
+// GetRole retrieves a role by name.
+func (b *Backend) GetRole(ctx context.Context, name string) (*backend.Role, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT name, description, permissions, updated_at FROM roles WHERE name = $1`, name)
+	role, err := scanRole(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", backend.ErrRoleNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return role, nil
+}
+
+// ListRoles returns all roles ordered by name.
+func (b *Backend) ListRoles(ctx context.Context) ([]*backend.Role, error) {
+	rows, err := b.db.QueryContext(ctx, `SELECT name, description, permissions, updated_at FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*backend.Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// DeleteRole deletes a role.
+func (b *Backend) DeleteRole(ctx context.Context, name string) error {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM roles WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", backend.ErrRoleNotFound, name)
+	}
+	return nil
+}
+
+// SaveRoleBinding creates or replaces the roles bound to a subject.
+func (b *Backend) SaveRoleBinding(ctx context.Context, binding *backend.RoleBinding) error {
+	roles, err := json.Marshal(binding.Roles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal roles: %w", err)
+	}
+
+	now := time.Now()
+	_, err = b.db.ExecContext(ctx, `
+		INSERT INTO role_bindings (subject, roles, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subject) DO UPDATE SET
+			roles = EXCLUDED.roles,
+			updated_at = EXCLUDED.updated_at
+	`, binding.Subject, roles, now)
+	if err != nil {
+		return fmt.Errorf("failed to save role binding: %w", err)
+	}
+
+	binding.UpdatedAt = now
+	return nil
+}
+
+// GetRoleBinding retrieves a subject's role binding.
+func (b *Backend) GetRoleBinding(ctx context.Context, subject string) (*backend.RoleBinding, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT subject, roles, updated_at FROM role_bindings WHERE subject = $1`, subject)
+	binding, err := scanRoleBinding(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: no binding for %s", backend.ErrRoleNotFound, subject)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role binding: %w", err)
+	}
+	return binding, nil
+}
+
+// ListRoleBindings returns all role bindings ordered by subject.
+func (b *Backend) ListRoleBindings(ctx context.Context) ([]*backend.RoleBinding, error) {
+	rows, err := b.db.QueryContext(ctx, `SELECT subject, roles, updated_at FROM role_bindings ORDER BY subject`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	defer rows.Close()
+
+	var bindings []*backend.RoleBinding
+	for rows.Next() {
+		binding, err := scanRoleBinding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role binding: %w", err)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, rows.Err()
+}
+
+// DeleteRoleBinding deletes a subject's role binding.
+func (b *Backend) DeleteRoleBinding(ctx context.Context, subject string) error {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM role_bindings WHERE subject = $1`, subject)
+	if err != nil {
+		return fmt.Errorf("failed to delete role binding: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: no binding for %s", backend.ErrRoleNotFound, subject)
+	}
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRole(row rowScanner) (*backend.Role, error) {
+	var role backend.Role
+	var description sql.NullString
+	var permissions []byte
+	if err := row.Scan(&role.Name, &description, &permissions, &role.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(permissions, &role.Permissions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal permissions: %w", err)
+	}
+	role.Description = description.String
+	return &role, nil
+}
+
+func scanRoleBinding(row rowScanner) (*backend.RoleBinding, error) {
+	var binding backend.RoleBinding
+	var roles []byte
+	if err := row.Scan(&binding.Subject, &roles, &binding.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(roles, &binding.Roles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal roles: %w", err)
+	}
+	return &binding, nil
+}
+
+// SaveNotificationDeadLetter records an undelivered notification.
+func (b *Backend) SaveNotificationDeadLetter(ctx context.Context, letter *backend.NotificationDeadLetter) error {
+	if letter.CreatedAt.IsZero() {
+		letter.CreatedAt = time.Now()
+	}
+	_, err := b.db.ExecContext(ctx, `
+		INSERT INTO notification_dead_letters (id, run_id, workflow, event, channel, payload, error, attempts, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, letter.ID, letter.RunID, letter.Workflow, letter.Event, letter.Channel, []byte(letter.Payload),
+		letter.Error, letter.Attempts, letter.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListNotificationDeadLetters returns undelivered notifications, newest first.
+func (b *Backend) ListNotificationDeadLetters(ctx context.Context, limit int) ([]*backend.NotificationDeadLetter, error) {
+	query := `SELECT id, run_id, workflow, event, channel, payload, error, attempts, created_at
+		FROM notification_dead_letters ORDER BY created_at DESC`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT $1`
+		args = append(args, limit)
+	}
+
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*backend.NotificationDeadLetter
+	for rows.Next() {
+		var letter backend.NotificationDeadLetter
+		var payload []byte
+		var errMsg sql.NullString
+		if err := rows.Scan(&letter.ID, &letter.RunID, &letter.Workflow, &letter.Event, &letter.Channel,
+			&payload, &errMsg, &letter.Attempts, &letter.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification dead letter: %w", err)
+		}
+		letter.Payload = json.RawMessage(payload)
+		letter.Error = errMsg.String
+		letters = append(letters, &letter)
+	}
+	return letters, rows.Err()
+}
+
+const triggerEventColumns = `id, source, trigger_id, workflow, event, idempotency_key, headers, payload,
+	inputs, matched, status, error, run_id, attempts, created_at, updated_at`
+
+// CreateTriggerEvent records a new trigger event.
+func (b *Backend) CreateTriggerEvent(ctx context.Context, event *backend.TriggerEvent) error {
+	headers, inputs, err := marshalTriggerEvent(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = now
+	}
+	event.UpdatedAt = now
+
+	// The unique index on (trigger_id, idempotency_key) turns a duplicate into a no-op
+	result, err := b.db.ExecContext(ctx, `
+		INSERT INTO trigger_events (`+triggerEventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT DO NOTHING
+	`, event.ID, event.Source, event.Trigger, event.Workflow, event.Event, event.IdempotencyKey,
+		headers, nullJSON(event.Payload), inputs, event.Matched, event.Status, event.Error, event.RunID,
+		event.Attempts, event.CreatedAt, event.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create trigger event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", backend.ErrDuplicateTriggerEvent, event.IdempotencyKey)
+	}
+	return nil
+}
+
+// UpdateTriggerEvent updates a trigger event.
+func (b *Backend) UpdateTriggerEvent(ctx context.Context, event *backend.TriggerEvent) error {
+	headers, inputs, err := marshalTriggerEvent(event)
+	if err != nil {
+		return err
+	}
+
+	event.UpdatedAt = time.Now()
+	result, err := b.db.ExecContext(ctx, `
+		UPDATE trigger_events SET
+			event = $1, headers = $2, payload = $3, inputs = $4, matched = $5, status = $6,
+			error = $7, run_id = $8, attempts = $9, updated_at = $10
+		WHERE id = $11
+	`, event.Event, headers, nullJSON(event.Payload), inputs, event.Matched, event.Status,
+		event.Error, event.RunID, event.Attempts, event.UpdatedAt, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update trigger event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, event.ID)
+	}
+	return nil
+}
+
+// GetTriggerEvent retrieves a trigger event by ID.
+func (b *Backend) GetTriggerEvent(ctx context.Context, id string) (*backend.TriggerEvent, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT `+triggerEventColumns+` FROM trigger_events WHERE id = $1`, id)
+	event, err := scanTriggerEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trigger event: %w", err)
+	}
+	return event, nil
+}
+
+// FindTriggerEvent retrieves the event with an idempotency key for a trigger.
+func (b *Backend) FindTriggerEvent(ctx context.Context, trigger, idempotencyKey string) (*backend.TriggerEvent, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT `+triggerEventColumns+` FROM trigger_events
+		WHERE trigger_id = $1 AND idempotency_key = $2 AND idempotency_key <> ''`, trigger, idempotencyKey)
+	event, err := scanTriggerEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, idempotencyKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trigger event: %w", err)
+	}
+	return event, nil
+}
+
+// ListTriggerEvents lists trigger events newest first.
+func (b *Backend) ListTriggerEvents(ctx context.Context, filter backend.TriggerEventFilter) ([]*backend.TriggerEvent, error) {
+	query := `SELECT ` + triggerEventColumns + ` FROM trigger_events WHERE 1=1`
+	var args []any
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.Workflow != "" {
+		args = append(args, filter.Workflow)
+		query += fmt.Sprintf(" AND workflow = $%d", len(args))
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		query += fmt.Sprintf(" AND source = $%d", len(args))
+	}
+	query += " ORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trigger events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*backend.TriggerEvent
+	for rows.Next() {
+		event, err := scanTriggerEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trigger event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// DeleteTriggerEvents deletes trigger events received before a time.
+func (b *Backend) DeleteTriggerEvents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM trigger_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete trigger events: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// marshalTriggerEvent encodes an event's headers and inputs.
+func marshalTriggerEvent(event *backend.TriggerEvent) (headers, inputs []byte, err error) {
+	if headers, err = json.Marshal(event.Headers); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal headers: %w", err)
+	}
+	if inputs, err = json.Marshal(event.Inputs); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal inputs: %w", err)
+	}
+	return headers, inputs, nil
+}
+
+// nullJSON returns nil for an empty JSON value, so it is stored as NULL.
+func nullJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
+
+func scanTriggerEvent(row rowScanner) (*backend.TriggerEvent, error) {
+	var event backend.TriggerEvent
+	var eventType, key, errMsg, runID sql.NullString
+	var headers, payload, inputs []byte
+	if err := row.Scan(&event.ID, &event.Source, &event.Trigger, &event.Workflow, &eventType, &key,
+		&headers, &payload, &inputs, &event.Matched, &event.Status, &errMsg, &runID, &event.Attempts,
+		&event.CreatedAt, &event.UpdatedAt); err != nil {
+		return nil, err
+	}
+	event.Event = eventType.String
+	event.IdempotencyKey = key.String
+	event.Error = errMsg.String
+	event.RunID = runID.String
+	if len(payload) > 0 {
+		event.Payload = json.RawMessage(payload)
+	}
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &event.Headers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal headers: %w", err)
+		}
+	}
+	if len(inputs) > 0 {
+		if err := json.Unmarshal(inputs, &event.Inputs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal inputs: %w", err)
+		}
+	}
+	return &event, nil
+}
@@ -20,6 +20,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/tombee/conductor/internal/controller/backend"
@@ -28,12 +29,16 @@ import (
 
 // Compile-time interface assertions.
 var (
-	_ backend.RunStore        = (*Backend)(nil)
-	_ backend.RunLister       = (*Backend)(nil)
-	_ backend.CheckpointStore = (*Backend)(nil)
-	_ backend.StepResultStore = (*Backend)(nil)
-	_ backend.Backend         = (*Backend)(nil)
-	_ backend.ScheduleBackend = (*Backend)(nil)
+	_ backend.RunStore            = (*Backend)(nil)
+	_ backend.RunLister           = (*Backend)(nil)
+	_ backend.RunIdempotencyStore = (*Backend)(nil)
+	_ backend.CheckpointStore     = (*Backend)(nil)
+	_ backend.StepResultStore     = (*Backend)(nil)
+	_ backend.Backend             = (*Backend)(nil)
+	_ backend.ScheduleBackend     = (*Backend)(nil)
+	_ backend.RoleStore           = (*Backend)(nil)
+	_ backend.NotificationStore   = (*Backend)(nil)
+	_ backend.TriggerEventStore   = (*Backend)(nil)
 )
 
 // Backend is a SQLite storage backend.
@@ -167,6 +172,50 @@ func (b *Backend) migrate(ctx context.Context) error {
 			enabled INTEGER DEFAULT 1,
 			updated_at TEXT NOT NULL
 		)`,
+		`CREATE TABLE IF NOT EXISTS roles (
+			name TEXT PRIMARY KEY,
+			description TEXT,
+			permissions TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS role_bindings (
+			subject TEXT PRIMARY KEY,
+			roles TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS notification_dead_letters (
+			id TEXT PRIMARY KEY,
+			run_id TEXT NOT NULL,
+			workflow TEXT NOT NULL,
+			event TEXT NOT NULL,
+			channel TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			error TEXT,
+			attempts INTEGER DEFAULT 0,
+			created_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notification_dead_letters_created_at ON notification_dead_letters(created_at)`,
+		`CREATE TABLE IF NOT EXISTS trigger_events (
+			id TEXT PRIMARY KEY,
+			source TEXT NOT NULL,
+			trigger_id TEXT NOT NULL,
+			workflow TEXT NOT NULL,
+			event TEXT,
+			idempotency_key TEXT,
+			headers TEXT,
+			payload TEXT,
+			inputs TEXT,
+			matched INTEGER DEFAULT 0,
+			status TEXT NOT NULL,
+			error TEXT,
+			run_id TEXT,
+			attempts INTEGER DEFAULT 0,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_trigger_events_idempotency ON trigger_events(trigger_id, idempotency_key) WHERE idempotency_key <> ''`,
+		`CREATE INDEX IF NOT EXISTS idx_trigger_events_created_at ON trigger_events(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_trigger_events_status ON trigger_events(status)`,
 	}
 
 	for _, migration := range migrations {
@@ -175,9 +224,47 @@ func (b *Backend) migrate(ctx context.Context) error {
 		}
 	}
 
+	// Columns added to existing tables
+	columns := []struct{ table, column, definition string }{
+		{"runs", "idempotency_key", "TEXT"},
+		{"runs", "request_hash", "TEXT"},
+		{"runs", "workspace", "TEXT"},
+	}
+	for _, c := range columns {
+		if err := b.addColumn(ctx, c.table, c.column, c.definition); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+	}
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_runs_idempotency_key ON runs(idempotency_key) WHERE idempotency_key IS NOT NULL`,
+		// A workflow has one run per idempotency key
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_runs_workflow_idempotency_key ON runs(workflow, idempotency_key) WHERE idempotency_key IS NOT NULL`,
+	}
+	for _, index := range indexes {
+		if _, err := b.db.ExecContext(ctx, index); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// addColumn adds a column to a table unless it already exists. SQLite has
+// no ADD COLUMN IF NOT EXISTS.
+func (b *Backend) addColumn(ctx context.Context, table, column, definition string) error {
+	var count int
+	err := b.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err = b.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
 // CreateRun creates a new run.
 func (b *Backend) CreateRun(ctx context.Context, run *backend.Run) error {
 	inputsJSON, err := json.Marshal(run.Inputs)
@@ -200,8 +287,9 @@ func (b *Backend) CreateRun(ctx context.Context, run *backend.Run) error {
 
 	query := `
 		INSERT INTO runs (id, workflow_id, workflow, status, correlation_id, inputs, output, error,
-			current_step, completed, total, parent_run_id, replay_config, started_at, completed_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			current_step, completed, total, parent_run_id, replay_config, started_at, completed_at, created_at, updated_at,
+			idempotency_key, request_hash, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	now := time.Now()
@@ -214,7 +302,11 @@ func (b *Backend) CreateRun(ctx context.Context, run *backend.Run) error {
 		nullString(run.CurrentStep), run.Completed, run.Total,
 		nullString(run.ParentRunID), nullBytes(replayConfigJSON),
 		startedAt, completedAt, now.Format(time.RFC3339), now.Format(time.RFC3339),
+		nullString(run.IdempotencyKey), nullString(run.RequestHash), nullString(run.Workspace),
 	)
+	if err != nil && run.IdempotencyKey != "" && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return backend.ErrIdempotencyKeyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create run: %w", err)
 	}
@@ -229,13 +321,13 @@ func (b *Backend) GetRun(ctx context.Context, id string) (*backend.Run, error) {
 	query := `
 		SELECT id, workflow_id, workflow, status, correlation_id, inputs, output, error,
 			current_step, completed, total, parent_run_id, replay_config,
-			started_at, completed_at, created_at, updated_at
+			started_at, completed_at, created_at, updated_at, workspace
 		FROM runs WHERE id = ?
 	`
 
 	var run backend.Run
 	var inputsJSON, outputJSON, replayConfigJSON sql.NullString
-	var correlationID, currentStep, parentRunID, errorStr sql.NullString
+	var correlationID, currentStep, parentRunID, errorStr, workspace sql.NullString
 	var startedAt, completedAt, createdAt, updatedAt sql.NullString
 
 	err := b.db.QueryRowContext(ctx, query, id).Scan(
@@ -243,7 +335,7 @@ func (b *Backend) GetRun(ctx context.Context, id string) (*backend.Run, error) {
 		&inputsJSON, &outputJSON, &errorStr,
 		&currentStep, &run.Completed, &run.Total,
 		&parentRunID, &replayConfigJSON,
-		&startedAt, &completedAt, &createdAt, &updatedAt,
+		&startedAt, &completedAt, &createdAt, &updatedAt, &workspace,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("run not found: %s", id)
@@ -262,6 +354,9 @@ func (b *Backend) GetRun(ctx context.Context, id string) (*backend.Run, error) {
 	if parentRunID.Valid {
 		run.ParentRunID = parentRunID.String
 	}
+	if workspace.Valid {
+		run.Workspace = workspace.String
+	}
 	if errorStr.Valid {
 		run.Error = errorStr.String
 	}
@@ -303,6 +398,43 @@ func (b *Backend) GetRun(ctx context.Context, id string) (*backend.Run, error) {
 	return &run, nil
 }
 
+// FindRunByIdempotencyKey returns the workflow's newest run created with key since a time.
+func (b *Backend) FindRunByIdempotencyKey(ctx context.Context, workflow, key string, since time.Time) (*backend.Run, error) {
+	var id string
+	var requestHash sql.NullString
+	err := b.db.QueryRowContext(ctx, `
+		SELECT id, request_hash FROM runs
+		WHERE workflow = ? AND idempotency_key = ? AND created_at >= ?
+		ORDER BY created_at DESC LIMIT 1
+	`, workflow, key, since.Format(time.RFC3339)).Scan(&id, &requestHash)
+	if err == sql.ErrNoRows {
+		return nil, backend.ErrRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find run: %w", err)
+	}
+
+	run, err := b.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	run.IdempotencyKey = key
+	run.RequestHash = requestHash.String
+	return run, nil
+}
+
+// ReleaseIdempotencyKey clears key from the workflow's runs created before a time.
+func (b *Backend) ReleaseIdempotencyKey(ctx context.Context, workflow, key string, before time.Time) error {
+	_, err := b.db.ExecContext(ctx, `
+		UPDATE runs SET idempotency_key = NULL
+		WHERE workflow = ? AND idempotency_key = ? AND created_at < ?
+	`, workflow, key, before.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
 // UpdateRun updates an existing run.
 func (b *Backend) UpdateRun(ctx context.Context, run *backend.Run) error {
 	inputsJSON, err := json.Marshal(run.Inputs)
@@ -361,7 +493,7 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 	query := `
 		SELECT id, workflow_id, workflow, status, correlation_id, inputs, output, error,
 			current_step, completed, total, parent_run_id, replay_config,
-			started_at, completed_at, created_at, updated_at
+			started_at, completed_at, created_at, updated_at, workspace
 		FROM runs WHERE 1=1
 	`
 	args := []any{}
@@ -396,7 +528,7 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 	for rows.Next() {
 		var run backend.Run
 		var inputsJSON, outputJSON, replayConfigJSON sql.NullString
-		var correlationID, currentStep, parentRunID, errorStr sql.NullString
+		var correlationID, currentStep, parentRunID, errorStr, workspace sql.NullString
 		var startedAt, completedAt, createdAt, updatedAt sql.NullString
 
 		err := rows.Scan(
@@ -404,7 +536,7 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 			&inputsJSON, &outputJSON, &errorStr,
 			&currentStep, &run.Completed, &run.Total,
 			&parentRunID, &replayConfigJSON,
-			&startedAt, &completedAt, &createdAt, &updatedAt,
+			&startedAt, &completedAt, &createdAt, &updatedAt, &workspace,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan run: %w", err)
@@ -420,6 +552,9 @@ func (b *Backend) ListRuns(ctx context.Context, filter backend.RunFilter) ([]*ba
 		if parentRunID.Valid {
 			run.ParentRunID = parentRunID.String
 		}
+		if workspace.Valid {
+			run.Workspace = workspace.String
+		}
 		if errorStr.Valid {
 			run.Error = errorStr.String
 		}
@@ -835,3 +970,401 @@ func nullBytes(b []byte) any {
 	}
 	return string(b)
 }
+
+// SaveRole creates or replaces a role.
+func (b *Backend) SaveRole(ctx context.Context, role *backend.Role) error {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal permissions: %w", err)
+	}
+
+	now := time.Now()
+	_, err = b.db.ExecContext(ctx, `
+		INSERT INTO roles (name, description, permissions, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			description = excluded.description,
+			permissions = excluded.permissions,
+			updated_at = excluded.updated_at
+	`, role.Name, role.Description, string(permissions), now.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+
+	role.UpdatedAt = now
+	return nil
+}
+
+// GetRole retrieves a role by name.
+func (b *Backend) GetRole(ctx context.Context, name string) (*backend.Role, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT name, description, permissions, updated_at FROM roles WHERE name = ?`, name)
+	role, err := scanRole(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", backend.ErrRoleNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return role, nil
+}
+
+// ListRoles returns all roles ordered by name.
+func (b *Backend) ListRoles(ctx context.Context) ([]*backend.Role, error) {
+	rows, err := b.db.QueryContext(ctx, `SELECT name, description, permissions, updated_at FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*backend.Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// DeleteRole deletes a role.
+func (b *Backend) DeleteRole(ctx context.Context, name string) error {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM roles WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", backend.ErrRoleNotFound, name)
+	}
+	return nil
+}
+
+// SaveRoleBinding creates or replaces the roles bound to a subject.
+func (b *Backend) SaveRoleBinding(ctx context.Context, binding *backend.RoleBinding) error {
+	roles, err := json.Marshal(binding.Roles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal roles: %w", err)
+	}
+
+	now := time.Now()
+	_, err = b.db.ExecContext(ctx, `
+		INSERT INTO role_bindings (subject, roles, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET
+			roles = excluded.roles,
+			updated_at = excluded.updated_at
+	`, binding.Subject, string(roles), now.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save role binding: %w", err)
+	}
+
+	binding.UpdatedAt = now
+	return nil
+}
+
+// GetRoleBinding retrieves a subject's role binding.
+func (b *Backend) GetRoleBinding(ctx context.Context, subject string) (*backend.RoleBinding, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT subject, roles, updated_at FROM role_bindings WHERE subject = ?`, subject)
+	binding, err := scanRoleBinding(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: no binding for %s", backend.ErrRoleNotFound, subject)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role binding: %w", err)
+	}
+	return binding, nil
+}
+
+// ListRoleBindings returns all role bindings ordered by subject.
+func (b *Backend) ListRoleBindings(ctx context.Context) ([]*backend.RoleBinding, error) {
+	rows, err := b.db.QueryContext(ctx, `SELECT subject, roles, updated_at FROM role_bindings ORDER BY subject`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	defer rows.Close()
+
+	var bindings []*backend.RoleBinding
+	for rows.Next() {
+		binding, err := scanRoleBinding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role binding: %w", err)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, rows.Err()
+}
+
+// DeleteRoleBinding deletes a subject's role binding.
+func (b *Backend) DeleteRoleBinding(ctx context.Context, subject string) error {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM role_bindings WHERE subject = ?`, subject)
+	if err != nil {
+		return fmt.Errorf("failed to delete role binding: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: no binding for %s", backend.ErrRoleNotFound, subject)
+	}
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRole(row rowScanner) (*backend.Role, error) {
+	var role backend.Role
+	var description sql.NullString
+	var permissions, updatedAt string
+	if err := row.Scan(&role.Name, &description, &permissions, &updatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(permissions), &role.Permissions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal permissions: %w", err)
+	}
+	role.Description = description.String
+	role.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &role, nil
+}
+
+func scanRoleBinding(row rowScanner) (*backend.RoleBinding, error) {
+	var binding backend.RoleBinding
+	var roles, updatedAt string
+	if err := row.Scan(&binding.Subject, &roles, &updatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(roles), &binding.Roles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal roles: %w", err)
+	}
+	binding.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &binding, nil
+}
+
+// SaveNotificationDeadLetter records an undelivered notification.
+func (b *Backend) SaveNotificationDeadLetter(ctx context.Context, letter *backend.NotificationDeadLetter) error {
+	if letter.CreatedAt.IsZero() {
+		letter.CreatedAt = time.Now()
+	}
+	_, err := b.db.ExecContext(ctx, `
+		INSERT INTO notification_dead_letters (id, run_id, workflow, event, channel, payload, error, attempts, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, letter.ID, letter.RunID, letter.Workflow, letter.Event, letter.Channel, string(letter.Payload),
+		letter.Error, letter.Attempts, letter.CreatedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("failed to save notification dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListNotificationDeadLetters returns undelivered notifications, newest first.
+func (b *Backend) ListNotificationDeadLetters(ctx context.Context, limit int) ([]*backend.NotificationDeadLetter, error) {
+	query := `SELECT id, run_id, workflow, event, channel, payload, error, attempts, created_at
+		FROM notification_dead_letters ORDER BY created_at DESC`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*backend.NotificationDeadLetter
+	for rows.Next() {
+		var letter backend.NotificationDeadLetter
+		var payload, createdAt string
+		var errMsg sql.NullString
+		if err := rows.Scan(&letter.ID, &letter.RunID, &letter.Workflow, &letter.Event, &letter.Channel,
+			&payload, &errMsg, &letter.Attempts, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification dead letter: %w", err)
+		}
+		letter.Payload = json.RawMessage(payload)
+		letter.Error = errMsg.String
+		letter.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		letters = append(letters, &letter)
+	}
+	return letters, rows.Err()
+}
+
+// eventTimeFormat is a fixed-width timestamp layout, so trigger event times
+// sort and compare correctly as text.
+const eventTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+const triggerEventColumns = `id, source, trigger_id, workflow, event, idempotency_key, headers, payload,
+	inputs, matched, status, error, run_id, attempts, created_at, updated_at`
+
+// CreateTriggerEvent records a new trigger event.
+func (b *Backend) CreateTriggerEvent(ctx context.Context, event *backend.TriggerEvent) error {
+	headers, inputs, err := marshalTriggerEvent(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = now
+	}
+	event.UpdatedAt = now
+
+	// The unique index on (trigger, idempotency_key) turns a duplicate into a no-op
+	result, err := b.db.ExecContext(ctx, `
+		INSERT INTO trigger_events (`+triggerEventColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, event.ID, event.Source, event.Trigger, event.Workflow, event.Event, event.IdempotencyKey,
+		headers, string(event.Payload), inputs, event.Matched, event.Status, event.Error, event.RunID,
+		event.Attempts, event.CreatedAt.UTC().Format(eventTimeFormat), event.UpdatedAt.UTC().Format(eventTimeFormat))
+	if err != nil {
+		return fmt.Errorf("failed to create trigger event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", backend.ErrDuplicateTriggerEvent, event.IdempotencyKey)
+	}
+	return nil
+}
+
+// UpdateTriggerEvent updates a trigger event.
+func (b *Backend) UpdateTriggerEvent(ctx context.Context, event *backend.TriggerEvent) error {
+	headers, inputs, err := marshalTriggerEvent(event)
+	if err != nil {
+		return err
+	}
+
+	event.UpdatedAt = time.Now()
+	result, err := b.db.ExecContext(ctx, `
+		UPDATE trigger_events SET
+			event = ?, headers = ?, payload = ?, inputs = ?, matched = ?, status = ?,
+			error = ?, run_id = ?, attempts = ?, updated_at = ?
+		WHERE id = ?
+	`, event.Event, headers, string(event.Payload), inputs, event.Matched, event.Status,
+		event.Error, event.RunID, event.Attempts, event.UpdatedAt.UTC().Format(eventTimeFormat), event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update trigger event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, event.ID)
+	}
+	return nil
+}
+
+// GetTriggerEvent retrieves a trigger event by ID.
+func (b *Backend) GetTriggerEvent(ctx context.Context, id string) (*backend.TriggerEvent, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT `+triggerEventColumns+` FROM trigger_events WHERE id = ?`, id)
+	event, err := scanTriggerEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trigger event: %w", err)
+	}
+	return event, nil
+}
+
+// FindTriggerEvent retrieves the event with an idempotency key for a trigger.
+func (b *Backend) FindTriggerEvent(ctx context.Context, trigger, idempotencyKey string) (*backend.TriggerEvent, error) {
+	row := b.db.QueryRowContext(ctx, `SELECT `+triggerEventColumns+` FROM trigger_events
+		WHERE trigger_id = ? AND idempotency_key = ? AND idempotency_key <> ''`, trigger, idempotencyKey)
+	event, err := scanTriggerEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", backend.ErrTriggerEventNotFound, idempotencyKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trigger event: %w", err)
+	}
+	return event, nil
+}
+
+// ListTriggerEvents lists trigger events newest first.
+func (b *Backend) ListTriggerEvents(ctx context.Context, filter backend.TriggerEventFilter) ([]*backend.TriggerEvent, error) {
+	query := `SELECT ` + triggerEventColumns + ` FROM trigger_events WHERE 1=1`
+	var args []any
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.Workflow != "" {
+		query += ` AND workflow = ?`
+		args = append(args, filter.Workflow)
+	}
+	if filter.Source != "" {
+		query += ` AND source = ?`
+		args = append(args, filter.Source)
+	}
+	query += ` ORDER BY created_at DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trigger events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*backend.TriggerEvent
+	for rows.Next() {
+		event, err := scanTriggerEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trigger event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// DeleteTriggerEvents deletes trigger events received before a time.
+func (b *Backend) DeleteTriggerEvents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM trigger_events WHERE created_at < ?`,
+		before.UTC().Format(eventTimeFormat))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete trigger events: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// marshalTriggerEvent encodes an event's headers and inputs.
+func marshalTriggerEvent(event *backend.TriggerEvent) (headers, inputs string, err error) {
+	headersJSON, err := json.Marshal(event.Headers)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal headers: %w", err)
+	}
+	inputsJSON, err := json.Marshal(event.Inputs)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal inputs: %w", err)
+	}
+	return string(headersJSON), string(inputsJSON), nil
+}
+
+func scanTriggerEvent(row rowScanner) (*backend.TriggerEvent, error) {
+	var event backend.TriggerEvent
+	var eventType, key, headers, payload, inputs, errMsg, runID sql.NullString
+	var createdAt, updatedAt string
+	if err := row.Scan(&event.ID, &event.Source, &event.Trigger, &event.Workflow, &eventType, &key,
+		&headers, &payload, &inputs, &event.Matched, &event.Status, &errMsg, &runID, &event.Attempts,
+		&createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	event.Event = eventType.String
+	event.IdempotencyKey = key.String
+	event.Error = errMsg.String
+	event.RunID = runID.String
+	if payload.String != "" {
+		event.Payload = json.RawMessage(payload.String)
+	}
+	if headers.Valid && headers.String != "" {
+		if err := json.Unmarshal([]byte(headers.String), &event.Headers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal headers: %w", err)
+		}
+	}
+	if inputs.Valid && inputs.String != "" {
+		if err := json.Unmarshal([]byte(inputs.String), &event.Inputs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal inputs: %w", err)
+		}
+	}
+	event.CreatedAt, _ = time.Parse(eventTimeFormat, createdAt)
+	event.UpdatedAt, _ = time.Parse(eventTimeFormat, updatedAt)
+	return &event, nil
+}
@@ -16,6 +16,7 @@ package sqlite
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -57,6 +58,7 @@ func TestSQLiteBackend_CreateRun(t *testing.T) {
 		Inputs:     map[string]any{"key": "value"},
 		Completed:  0,
 		Total:      5,
+		Workspace:  "production",
 	}
 
 	err := be.CreateRun(ctx, run)
@@ -79,6 +81,9 @@ func TestSQLiteBackend_CreateRun(t *testing.T) {
 	if retrieved.Inputs["key"] != "value" {
 		t.Errorf("expected inputs to contain key=value, got %v", retrieved.Inputs)
 	}
+	if retrieved.Workspace != "production" {
+		t.Errorf("expected workspace production, got %q", retrieved.Workspace)
+	}
 }
 
 func TestSQLiteBackend_UpdateRun(t *testing.T) {
@@ -133,7 +138,7 @@ func TestSQLiteBackend_ListRuns(t *testing.T) {
 
 	// Create multiple runs
 	runs := []*backend.Run{
-		{ID: "run-1", WorkflowID: "wf1", Workflow: "test1.yaml", Status: "running"},
+		{ID: "run-1", WorkflowID: "wf1", Workflow: "test1.yaml", Status: "running", Workspace: "staging"},
 		{ID: "run-2", WorkflowID: "wf2", Workflow: "test2.yaml", Status: "completed"},
 		{ID: "run-3", WorkflowID: "wf1", Workflow: "test1.yaml", Status: "completed"},
 	}
@@ -170,6 +175,11 @@ func TestSQLiteBackend_ListRuns(t *testing.T) {
 	if len(wf1) != 2 {
 		t.Errorf("expected 2 runs for test1.yaml, got %d", len(wf1))
 	}
+	for _, run := range wf1 {
+		if run.ID == "run-1" && run.Workspace != "staging" {
+			t.Errorf("expected workspace staging for run-1, got %q", run.Workspace)
+		}
+	}
 
 	// Test limit
 	limited, err := be.ListRuns(ctx, backend.RunFilter{Limit: 2})
@@ -209,6 +219,91 @@ func TestSQLiteBackend_DeleteRun(t *testing.T) {
 	}
 }
 
+func TestSQLiteBackend_FindRunByIdempotencyKey(t *testing.T) {
+	b, _ := createTestBackend(t)
+	defer b.Close()
+
+	ctx := context.Background()
+
+	run := &backend.Run{ID: "run-1", WorkflowID: "deploy", Workflow: "deploy", Status: "pending",
+		IdempotencyKey: "key-1", RequestHash: "abc"}
+	if err := b.CreateRun(ctx, run); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+
+	// The same key on another workflow is a different run
+	other := &backend.Run{ID: "run-2", WorkflowID: "build", Workflow: "build", Status: "pending",
+		IdempotencyKey: "key-1", RequestHash: "def"}
+	if err := b.CreateRun(ctx, other); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+
+	found, err := b.FindRunByIdempotencyKey(ctx, "deploy", "key-1", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("FindRunByIdempotencyKey() error = %v", err)
+	}
+	if found.ID != "run-1" || found.RequestHash != "abc" {
+		t.Errorf("found = %+v", found)
+	}
+
+	// Expired and unknown keys are not found
+	if _, err := b.FindRunByIdempotencyKey(ctx, "deploy", "key-1", time.Now().Add(time.Hour)); !errors.Is(err, backend.ErrRunNotFound) {
+		t.Errorf("FindRunByIdempotencyKey() after retention error = %v, want ErrRunNotFound", err)
+	}
+	if _, err := b.FindRunByIdempotencyKey(ctx, "deploy", "key-2", time.Time{}); !errors.Is(err, backend.ErrRunNotFound) {
+		t.Errorf("FindRunByIdempotencyKey() error = %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestSQLiteBackend_IdempotencyKeyUnique(t *testing.T) {
+	b, _ := createTestBackend(t)
+	defer b.Close()
+
+	ctx := context.Background()
+
+	run := &backend.Run{ID: "run-1", WorkflowID: "deploy", Workflow: "deploy", Status: "pending",
+		IdempotencyKey: "key-1", RequestHash: "abc"}
+	if err := b.CreateRun(ctx, run); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+
+	dup := &backend.Run{ID: "run-2", WorkflowID: "deploy", Workflow: "deploy", Status: "pending",
+		IdempotencyKey: "key-1", RequestHash: "abc"}
+	if err := b.CreateRun(ctx, dup); !errors.Is(err, backend.ErrIdempotencyKeyExists) {
+		t.Fatalf("CreateRun() duplicate error = %v, want ErrIdempotencyKeyExists", err)
+	}
+
+	// Keys are unique per workflow
+	other := &backend.Run{ID: "run-3", WorkflowID: "build", Workflow: "build", Status: "pending",
+		IdempotencyKey: "key-1", RequestHash: "def"}
+	if err := b.CreateRun(ctx, other); err != nil {
+		t.Fatalf("CreateRun() other workflow error = %v", err)
+	}
+
+	// Runs created after the cutoff keep the key
+	if err := b.ReleaseIdempotencyKey(ctx, "deploy", "key-1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey() error = %v", err)
+	}
+	if err := b.CreateRun(ctx, dup); !errors.Is(err, backend.ErrIdempotencyKeyExists) {
+		t.Fatalf("CreateRun() before release error = %v, want ErrIdempotencyKeyExists", err)
+	}
+
+	// A released key can be used again
+	if err := b.ReleaseIdempotencyKey(ctx, "deploy", "key-1", time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey() error = %v", err)
+	}
+	if err := b.CreateRun(ctx, dup); err != nil {
+		t.Fatalf("CreateRun() after release error = %v", err)
+	}
+	got, err := b.GetRun(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("GetRun() error = %v", err)
+	}
+	if got.IdempotencyKey != "" {
+		t.Errorf("released run IdempotencyKey = %q, want empty", got.IdempotencyKey)
+	}
+}
+
 func TestSQLiteBackend_Checkpoint(t *testing.T) {
 	be, _ := createTestBackend(t)
 	defer be.Close()
@@ -417,6 +512,161 @@ func TestSQLiteBackend_ScheduleState(t *testing.T) {
 	}
 }
 
+func TestSQLiteBackend_Roles(t *testing.T) {
+	b, _ := createTestBackend(t)
+	defer b.Close()
+
+	ctx := context.Background()
+
+	role := &backend.Role{
+		Name: "deployer",
+		Permissions: []backend.RolePermission{
+			{Actions: []string{"run", "cancel"}, Workflows: []string{"deploy-*"}},
+		},
+	}
+	if err := b.SaveRole(ctx, role); err != nil {
+		t.Fatalf("SaveRole() error = %v", err)
+	}
+	got, err := b.GetRole(ctx, "deployer")
+	if err != nil {
+		t.Fatalf("GetRole() error = %v", err)
+	}
+	if len(got.Permissions) != 1 || got.Permissions[0].Workflows[0] != "deploy-*" || got.UpdatedAt.IsZero() {
+		t.Errorf("GetRole() = %+v", got)
+	}
+	if roles, _ := b.ListRoles(ctx); len(roles) != 1 {
+		t.Errorf("ListRoles() returned %d roles, want 1", len(roles))
+	}
+
+	if err := b.SaveRoleBinding(ctx, &backend.RoleBinding{Subject: "alice", Roles: []string{"deployer", "viewer"}}); err != nil {
+		t.Fatalf("SaveRoleBinding() error = %v", err)
+	}
+	binding, err := b.GetRoleBinding(ctx, "alice")
+	if err != nil || len(binding.Roles) != 2 {
+		t.Errorf("GetRoleBinding() = %+v, %v", binding, err)
+	}
+	if bindings, _ := b.ListRoleBindings(ctx); len(bindings) != 1 {
+		t.Errorf("ListRoleBindings() returned %d bindings, want 1", len(bindings))
+	}
+
+	if err := b.DeleteRole(ctx, "deployer"); err != nil {
+		t.Fatalf("DeleteRole() error = %v", err)
+	}
+	if _, err := b.GetRole(ctx, "deployer"); !errors.Is(err, backend.ErrRoleNotFound) {
+		t.Errorf("GetRole() after delete error = %v, want ErrRoleNotFound", err)
+	}
+	if err := b.DeleteRoleBinding(ctx, "alice"); err != nil {
+		t.Fatalf("DeleteRoleBinding() error = %v", err)
+	}
+	if err := b.DeleteRoleBinding(ctx, "alice"); !errors.Is(err, backend.ErrRoleNotFound) {
+		t.Errorf("DeleteRoleBinding() twice error = %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestSQLiteBackend_NotificationDeadLetters(t *testing.T) {
+	b, _ := createTestBackend(t)
+	defer b.Close()
+
+	ctx := context.Background()
+
+	letters := []*backend.NotificationDeadLetter{
+		{ID: "dl-1", RunID: "run-1", Workflow: "deploy", Event: "failed", Channel: "webhook:https://hooks.example.com",
+			Payload: []byte(`{"event":"failed"}`), Error: "status 500", Attempts: 5, CreatedAt: time.Now().Add(-time.Minute)},
+		{ID: "dl-2", RunID: "run-2", Workflow: "deploy", Event: "started", Channel: "slack:#ops",
+			Payload: []byte(`{"event":"started"}`), Error: "channel_not_found", Attempts: 1},
+	}
+	for _, letter := range letters {
+		if err := b.SaveNotificationDeadLetter(ctx, letter); err != nil {
+			t.Fatalf("SaveNotificationDeadLetter() error = %v", err)
+		}
+	}
+
+	got, err := b.ListNotificationDeadLetters(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListNotificationDeadLetters() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "dl-2" || got[1].ID != "dl-1" {
+		t.Fatalf("ListNotificationDeadLetters() = %+v, want newest first", got)
+	}
+	if string(got[1].Payload) != `{"event":"failed"}` || got[1].Attempts != 5 || got[1].Error != "status 500" {
+		t.Errorf("dead letter = %+v", got[1])
+	}
+
+	if got, _ := b.ListNotificationDeadLetters(ctx, 1); len(got) != 1 {
+		t.Errorf("ListNotificationDeadLetters(1) returned %d, want 1", len(got))
+	}
+}
+
+func TestSQLiteBackend_TriggerEvents(t *testing.T) {
+	b, _ := createTestBackend(t)
+	defer b.Close()
+
+	ctx := context.Background()
+
+	old := &backend.TriggerEvent{ID: "ev-1", Source: "webhook", Trigger: "/webhooks/deploy", Workflow: "deploy",
+		Event: "push", IdempotencyKey: "delivery-1", Headers: map[string]string{"X-GitHub-Event": "push"},
+		Payload: []byte(`{"ref":"main"}`), Inputs: map[string]any{"ref": "main"}, Matched: true,
+		Status: backend.TriggerEventReceived, CreatedAt: time.Now().Add(-2 * time.Hour)}
+	if err := b.CreateTriggerEvent(ctx, old); err != nil {
+		t.Fatalf("CreateTriggerEvent() error = %v", err)
+	}
+	recent := &backend.TriggerEvent{ID: "ev-2", Source: "poll", Trigger: "deploy.yaml:jira", Workflow: "deploy",
+		Status: backend.TriggerEventIgnored}
+	if err := b.CreateTriggerEvent(ctx, recent); err != nil {
+		t.Fatalf("CreateTriggerEvent() error = %v", err)
+	}
+
+	// A retry with the same idempotency key is rejected
+	retry := &backend.TriggerEvent{ID: "ev-3", Source: "webhook", Trigger: "/webhooks/deploy", Workflow: "deploy",
+		IdempotencyKey: "delivery-1", Status: backend.TriggerEventReceived}
+	if err := b.CreateTriggerEvent(ctx, retry); !errors.Is(err, backend.ErrDuplicateTriggerEvent) {
+		t.Fatalf("CreateTriggerEvent(duplicate) error = %v, want ErrDuplicateTriggerEvent", err)
+	}
+
+	old.Status = backend.TriggerEventFailed
+	old.Error = "workflow not found"
+	old.Attempts = 1
+	if err := b.UpdateTriggerEvent(ctx, old); err != nil {
+		t.Fatalf("UpdateTriggerEvent() error = %v", err)
+	}
+
+	got, err := b.FindTriggerEvent(ctx, "/webhooks/deploy", "delivery-1")
+	if err != nil {
+		t.Fatalf("FindTriggerEvent() error = %v", err)
+	}
+	if got.ID != "ev-1" || got.Status != backend.TriggerEventFailed || got.Attempts != 1 || !got.Matched ||
+		got.Headers["X-GitHub-Event"] != "push" || got.Inputs["ref"] != "main" || string(got.Payload) != `{"ref":"main"}` {
+		t.Errorf("FindTriggerEvent() = %+v", got)
+	}
+	if _, err := b.GetTriggerEvent(ctx, "missing"); !errors.Is(err, backend.ErrTriggerEventNotFound) {
+		t.Errorf("GetTriggerEvent(missing) error = %v, want ErrTriggerEventNotFound", err)
+	}
+
+	events, err := b.ListTriggerEvents(ctx, backend.TriggerEventFilter{})
+	if err != nil {
+		t.Fatalf("ListTriggerEvents() error = %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "ev-2" || events[1].ID != "ev-1" {
+		t.Fatalf("ListTriggerEvents() = %+v, want newest first", events)
+	}
+	events, _ = b.ListTriggerEvents(ctx, backend.TriggerEventFilter{Status: backend.TriggerEventFailed})
+	if len(events) != 1 || events[0].ID != "ev-1" {
+		t.Errorf("ListTriggerEvents(failed) = %+v", events)
+	}
+	events, _ = b.ListTriggerEvents(ctx, backend.TriggerEventFilter{Source: "poll", Limit: 5})
+	if len(events) != 1 || events[0].ID != "ev-2" {
+		t.Errorf("ListTriggerEvents(poll) = %+v", events)
+	}
+
+	deleted, err := b.DeleteTriggerEvents(ctx, time.Now().Add(-time.Hour))
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteTriggerEvents() = %d, %v; want 1", deleted, err)
+	}
+	if _, err := b.GetTriggerEvent(ctx, "ev-1"); !errors.Is(err, backend.ErrTriggerEventNotFound) {
+		t.Errorf("GetTriggerEvent(ev-1) error = %v after delete", err)
+	}
+}
+
 func TestSQLiteBackend_Persistence(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "persist.db")
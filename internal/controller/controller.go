@@ -17,6 +17,8 @@ package controller
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -28,6 +30,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tombee/conductor/internal/action/email"
 	"github.com/tombee/conductor/internal/config"
 
 	// Import operation package to trigger init() which registers action factories
@@ -48,10 +51,13 @@ import (
 	"github.com/tombee/conductor/internal/controller/endpoint"
 	"github.com/tombee/conductor/internal/controller/filewatcher"
 	"github.com/tombee/conductor/internal/controller/github"
+	"github.com/tombee/conductor/internal/controller/imaptrigger"
 	"github.com/tombee/conductor/internal/controller/leader"
 	"github.com/tombee/conductor/internal/controller/listener"
+	"github.com/tombee/conductor/internal/controller/notify"
 	"github.com/tombee/conductor/internal/controller/polltrigger"
 	"github.com/tombee/conductor/internal/controller/publicapi"
+	"github.com/tombee/conductor/internal/controller/queuetrigger"
 	controllerremote "github.com/tombee/conductor/internal/controller/remote"
 	"github.com/tombee/conductor/internal/controller/runner"
 	"github.com/tombee/conductor/internal/controller/scheduler"
@@ -60,15 +66,22 @@ import (
 	internalllm "github.com/tombee/conductor/internal/llm"
 	internallog "github.com/tombee/conductor/internal/log"
 	"github.com/tombee/conductor/internal/mcp"
+	mcpserver "github.com/tombee/conductor/internal/mcp/server"
 	"github.com/tombee/conductor/internal/tracing"
 	"github.com/tombee/conductor/internal/tracing/audit"
+	"github.com/tombee/conductor/internal/tracing/storage"
 	"github.com/tombee/conductor/internal/triggers"
 	"github.com/tombee/conductor/internal/workspace"
 	"github.com/tombee/conductor/pkg/security"
 	securityaudit "github.com/tombee/conductor/pkg/security/audit"
+	"github.com/tombee/conductor/pkg/tools/approval"
 	"github.com/tombee/conductor/pkg/workflow"
 )
 
+// approvalTimeout is how long a request waits for a decision through the
+// approvals API before it is denied.
+const approvalTimeout = 10 * time.Minute
+
 // Options contains controller options set at build time.
 type Options struct {
 	Version   string
@@ -92,14 +105,23 @@ type Controller struct {
 	fileWatcher        *filewatcher.Service
 	endpointHandler    *endpoint.Handler
 	authMw             *auth.Middleware
+	authorizer         *auth.Authorizer
 	leader             *leader.Elector
 	mcpRegistry        *mcp.Registry
 	mcpLogCapture      *mcp.LogCapture
+	mcpServer          *mcpserver.Server // Optional MCP server exposing workflows as tools
 	otelProvider       *tracing.OTelProvider
 	retentionMgr       *tracing.RetentionManager
 	auditLogger        *audit.Logger
 	pollTriggerService *polltrigger.Service
 	debugSessionMgr    *debug.SessionManager
+	approvals          *approval.Queue
+	notifier           *notify.Service
+	triggerEvents      *trigger.EventLog
+	webhookSources     *webhook.Registry
+	chainer            *trigger.Chainer
+	queueTriggers      *queuetrigger.Service
+	imapTriggers       *imaptrigger.Service
 
 	// Security components
 	dnsMonitor          *security.DNSQueryMonitor
@@ -201,11 +223,55 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 		return nil, fmt.Errorf("failed to create checkpoint manager: %w", err)
 	}
 
+	// In distributed mode, runs are executed by workers claiming them from
+	// the backend's job queue
+	runnerOpts := []runner.Option{runner.WithConfig(cfg)}
+	instanceID := cfg.Controller.Distributed.InstanceID
+	if instanceID == "" {
+		instanceID = uuid.New().String()
+	}
+	if cfg.Controller.Distributed.Enabled {
+		queue, ok := be.(backend.JobQueue)
+		if !ok {
+			return nil, fmt.Errorf("distributed mode requires the postgres backend")
+		}
+		logStore, _ := be.(backend.RunLogStore)
+		runnerOpts = append(runnerOpts, runner.WithDistributed(runner.DistributedConfig{
+			WorkerID:          instanceID,
+			Queue:             queue,
+			Logs:              logStore,
+			StalledJobTimeout: time.Duration(cfg.Controller.Distributed.StalledJobTimeoutSeconds) * time.Second,
+		}))
+	}
+
 	// Create runner with configured concurrency
 	r := runner.New(runner.Config{
-		MaxParallel:    cfg.Controller.MaxConcurrentRuns,
-		DefaultTimeout: cfg.Controller.DefaultTimeout,
-	}, be, cm)
+		MaxParallel:     cfg.Controller.MaxConcurrentRuns,
+		DefaultTimeout:  cfg.Controller.DefaultTimeout,
+		MaxQueueDepth:   cfg.Controller.Queue.MaxDepth,
+		FairShare:       cfg.Controller.Queue.FairShare,
+		QueueRetryAfter: cfg.Controller.Queue.RetryAfter,
+
+		IdempotencyKeyRetention: cfg.Controller.IdempotencyKeyRetention,
+	}, be, cm, runnerOpts...)
+
+	// Requests that need a human decision wait in a queue that is answered
+	// through the approvals API
+	approvals := approval.NewQueue(approvalTimeout)
+	r.SetApprover(approvals)
+
+	// Run lifecycle events are sent to the channels in each workflow's
+	// notify block, falling back to the controller defaults
+	notifications := cfg.Controller.Notifications
+	notifier := notify.New(notify.Config{
+		Backend:      be,
+		SMTP:         notifications.SMTP,
+		MaxAttempts:  notifications.MaxAttempts,
+		RetryBackoff: notifications.RetryBackoff,
+		Logger:       logger,
+	})
+	notifyDefaults := notify.ExpandDefaults(notifications.Defaults)
+	r.SetNotifier(notifier, &notifyDefaults)
 
 	// Create remote workflow fetcher
 	// This enables remote workflow support (github:user/repo)
@@ -221,7 +287,15 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 		r.SetFetcher(fetcher)
 	}
 
+	// Builtin actions share a $temp directory, where imap triggers also
+	// save the attachments of received emails
+	tempDir := filepath.Join(os.TempDir(), "conductor")
+	if cfg.Controller.DataDir != "" {
+		tempDir = filepath.Join(cfg.Controller.DataDir, "temp")
+	}
+
 	// Create LLM provider for workflow execution
+	var workflowExecutor *workflow.Executor
 	// Use the "balanced" tier to determine the default provider
 	var providerName string
 	if balancedTier, ok := cfg.Tiers["balanced"]; ok {
@@ -254,9 +328,10 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 			providerAdapter := internalllm.NewProviderAdapterWithTiers(llmProvider, cfg.Tiers)
 			// Tool registry is nil; tools are resolved dynamically per-workflow.
 			executor := workflow.NewExecutor(nil, providerAdapter)
+			workflowExecutor = executor
 
 			// Create operation registry with builtin actions and workspace integrations
-			opRegistry, integrationCount := createOperationRegistry(cfg.Controller.WorkflowsDir, logger)
+			opRegistry, integrationCount := createOperationRegistry(cfg.Controller.WorkflowsDir, tempDir, notifications.SMTP, logger)
 			if opRegistry != nil {
 				executor = executor.WithOperationRegistry(opRegistry.AsWorkflowRegistry())
 				logger.Info("operation registry initialized",
@@ -265,6 +340,7 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 
 			executionAdapter := runner.NewExecutorAdapter(executor)
 			r.SetAdapter(executionAdapter)
+			r.SetSamplingProvider(llmProvider, cfg.Tiers)
 
 			logger.Info("workflow execution adapter initialized",
 				slog.String("provider", providerName))
@@ -274,23 +350,45 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 			slog.String("hint", "run 'conductor provider add' to configure a provider"))
 	}
 
-	// Create scheduler if enabled
+	// Create scheduler if enabled, or if workflows have schedule triggers
 	var sched *scheduler.Scheduler
-	if cfg.Controller.Schedules.Enabled && len(cfg.Controller.Schedules.Schedules) > 0 {
-		schedules := make([]scheduler.Schedule, len(cfg.Controller.Schedules.Schedules))
-		for i, s := range cfg.Controller.Schedules.Schedules {
-			schedules[i] = scheduler.Schedule{
-				Name:     s.Name,
-				Cron:     s.Cron,
-				Workflow: s.Workflow,
-				Inputs:   s.Inputs,
-				Enabled:  s.Enabled,
-				Timezone: s.Timezone,
+	var schedules []scheduler.Schedule
+	if cfg.Controller.Schedules.Enabled {
+		for _, s := range cfg.Controller.Schedules.Schedules {
+			schedules = append(schedules, scheduler.Schedule{
+				Name:          s.Name,
+				Cron:          s.Cron,
+				At:            s.At,
+				Workflow:      s.Workflow,
+				Inputs:        s.Inputs,
+				Enabled:       s.Enabled,
+				Timezone:      s.Timezone,
+				Catchup:       s.Catchup,
+				CatchupWindow: s.CatchupWindow,
+				Overlap:       s.Overlap,
+				Jitter:        s.Jitter,
+				Exclude:       s.Exclude,
+			})
+		}
+	}
+	schedules = append(schedules, workflowSchedules(cfg.Controller.WorkflowsDir, logger)...)
+	if len(schedules) > 0 {
+		calendars := make(map[string]scheduler.Calendar, len(cfg.Controller.Schedules.Calendars))
+		for name, entry := range cfg.Controller.Schedules.Calendars {
+			cal := scheduler.Calendar{Dates: entry.Dates}
+			for _, period := range entry.Ranges {
+				cal.Ranges = append(cal.Ranges, scheduler.CalendarRange{From: period.From, To: period.To})
 			}
+			calendars[name] = cal
 		}
+		// Schedule history is kept in the backend so missed runs can be
+		// caught up after a restart
+		scheduleState, _ := be.(backend.ScheduleBackend)
 		sched, err = scheduler.New(scheduler.Config{
 			Schedules:    schedules,
 			WorkflowsDir: cfg.Controller.WorkflowsDir,
+			Calendars:    calendars,
+			State:        scheduleState,
 		}, r)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create scheduler: %w", err)
@@ -338,10 +436,6 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 	// Create leader elector if distributed mode is enabled
 	var elector *leader.Elector
 	if cfg.Controller.Distributed.Enabled && db != nil {
-		instanceID := cfg.Controller.Distributed.InstanceID
-		if instanceID == "" {
-			instanceID = uuid.New().String()
-		}
 		elector = leader.NewElector(leader.Config{
 			DB:            db,
 			InstanceID:    instanceID,
@@ -366,6 +460,16 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 		logger.Warn("MCP server management will not be available")
 	}
 
+	// Let workflow steps read MCP resources and prompts from workflow-scoped
+	// servers first, then from globally registered ones
+	if workflowExecutor != nil {
+		sources := []mcp.ClientSource{r.MCPManager()}
+		if mcpRegistry != nil {
+			sources = append(sources, mcpRegistry)
+		}
+		workflowExecutor.WithMCPResolver(mcp.NewResolver(sources...))
+	}
+
 	// Initialize OpenTelemetry provider for metrics and tracing
 	var otelProvider *tracing.OTelProvider
 	var retentionMgr *tracing.RetentionManager
@@ -490,12 +594,91 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 	}
 
 	// Create auth middleware (after security components are initialized)
-	authMw := auth.NewMiddleware(auth.Config{
+	authCfg := auth.Config{
 		Enabled:         cfg.Controller.ControllerAuth.Enabled,
 		APIKeys:         apiKeys,
 		AllowUnixSocket: cfg.Controller.ControllerAuth.AllowUnixSocket,
 		OverrideManager: overrideManager,
 		Logger:          logger,
+	}
+	if cfg.Controller.Dashboard.Enabled {
+		// The dashboard's static files hold no data; the API it calls is
+		// still authenticated
+		authCfg.PublicPathPrefixes = []string{api.DashboardPath}
+	}
+	if oidcCfg := cfg.Controller.ControllerAuth.OIDC; oidcCfg.Issuer != "" {
+		authCfg.OIDC = auth.NewOIDCVerifier(auth.OIDCConfig{
+			Issuer:        oidcCfg.Issuer,
+			ClientID:      oidcCfg.ClientID,
+			Audiences:     oidcCfg.Audiences,
+			UsernameClaim: oidcCfg.UsernameClaim,
+			GroupsClaim:   oidcCfg.GroupsClaim,
+			GroupRoles:    oidcCfg.GroupRoles,
+			GroupScopes:   oidcCfg.GroupScopes,
+			CacheTTL:      oidcCfg.CacheTTL,
+			ClockSkew:     time.Minute,
+		})
+		logger.Info("OIDC authentication enabled", slog.String("issuer", oidcCfg.Issuer))
+	}
+	// Roles can be managed before RBAC is enforced
+	authorizer := newAuthorizer(cfg.Controller.ControllerAuth.RBAC, be, auditLogger, logger)
+	if cfg.Controller.ControllerAuth.RBAC.Enabled {
+		authCfg.Authorizer = authorizer
+		logger.Info("role-based access control enabled")
+	}
+	authMw := auth.NewMiddleware(authCfg)
+
+	// Record webhook and poll events so failed deliveries can be redelivered
+	triggerEventStore, _ := be.(backend.TriggerEventStore)
+	triggerEvents := trigger.NewEventLog(trigger.EventLogConfig{
+		Store:        triggerEventStore,
+		Submitter:    r,
+		WorkflowsDir: cfg.Controller.WorkflowsDir,
+		Retention:    cfg.Controller.TriggerEvents.Retention,
+		Logger:       logger,
+	})
+
+	// Webhook sources, with the HMAC sources defined in config
+	webhookSources := webhook.NewRegistry()
+	for name, source := range cfg.Controller.Webhooks.Sources {
+		handler, err := webhook.NewHMACHandler(webhook.HMACConfig{
+			SignatureHeader: source.SignatureHeader,
+			Algorithm:       source.Algorithm,
+			Encoding:        source.Encoding,
+			Prefix:          source.Prefix,
+			SignedContent:   source.SignedContent,
+			TimestampHeader: source.TimestampHeader,
+			TimestampFormat: source.TimestampFormat,
+			Tolerance:       source.Tolerance,
+			EventHeader:     source.EventHeader,
+			EventField:      source.EventField,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("webhook source %s: %w", name, err)
+		}
+		webhookSources.Register(name, handler)
+	}
+
+	// Finished runs start the workflows chained after them
+	chainer := trigger.NewChainer(triggerEvents, r, logger)
+	r.SetRunObserver(chainer)
+
+	// Messages consumed by queue triggers start their workflows
+	queueTriggers := queuetrigger.NewService(queuetrigger.Config{
+		Events:       triggerEvents,
+		Runs:         r,
+		WorkflowsDir: cfg.Controller.WorkflowsDir,
+		ConsumerName: instanceID,
+		Logger:       logger,
+	})
+
+	// Emails received by imap triggers start their workflows
+	imapTriggers := imaptrigger.NewService(imaptrigger.Config{
+		Events:       triggerEvents,
+		WorkflowsDir: cfg.Controller.WorkflowsDir,
+		TempDir:      tempDir,
+		Retention:    cfg.Controller.TriggerEvents.Retention,
+		Logger:       logger,
 	})
 
 	// Create poll trigger service
@@ -503,21 +686,25 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 	pollTriggerSvc, err = polltrigger.NewService(polltrigger.ServiceConfig{
 		Logger: logger,
 		WorkflowFirer: func(ctx context.Context, workflowPath string, triggerContext *polltrigger.PollTriggerContext) error {
-			// Fire workflow via runner
-			// Read workflow YAML
-			workflowYAML, err := os.ReadFile(workflowPath)
-			if err != nil {
-				return fmt.Errorf("failed to read workflow: %w", err)
+			eventID, _ := triggerContext.Event["id"].(string)
+			payload, _ := json.Marshal(triggerContext.Event)
+
+			// The trigger context is passed by its JSON field names, as it
+			// is when the event is redelivered, so steps can use trigger.type
+			var triggerInput map[string]any
+			if data, err := json.Marshal(triggerContext); err == nil {
+				_ = json.Unmarshal(data, &triggerInput)
 			}
 
-			// Convert trigger context to workflow inputs
-			inputs := make(map[string]interface{})
-			inputs["trigger"] = triggerContext
-
-			// Execute workflow
-			_, err = r.Submit(ctx, runner.SubmitRequest{
-				WorkflowYAML: workflowYAML,
-				Inputs:       inputs,
+			// Fire workflow via the event log, with the trigger context as input
+			_, _, err := triggerEvents.Deliver(ctx, &backend.TriggerEvent{
+				Source:         trigger.SourcePoll,
+				Trigger:        triggerContext.TriggerID,
+				Workflow:       strings.TrimSuffix(filepath.Base(workflowPath), filepath.Ext(workflowPath)),
+				Event:          triggerContext.Integration,
+				IdempotencyKey: eventID,
+				Payload:        payload,
+				Inputs:         map[string]any{"trigger": triggerInput},
 			})
 			return err
 		},
@@ -527,46 +714,20 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 			internallog.Error(err))
 		logger.Warn("poll triggers will not be available")
 	} else {
-		// Register PagerDuty poller if token is configured
-		pagerdutyToken := os.Getenv("PAGERDUTY_TOKEN")
-		if pagerdutyToken != "" {
-			pdPoller := polltrigger.NewPagerDutyPoller(pagerdutyToken)
-			if err := pollTriggerSvc.RegisterPoller(pdPoller); err != nil {
-				logger.Warn("failed to register PagerDuty poller",
-					internallog.Error(err))
-			}
-		}
-
-		// Register Datadog poller if API key and app key are configured
-		datadogAPIKey := os.Getenv("DATADOG_API_KEY")
-		datadogAppKey := os.Getenv("DATADOG_APP_KEY")
-		if datadogAPIKey != "" && datadogAppKey != "" {
-			datadogSite := os.Getenv("DATADOG_SITE") // Optional, defaults to datadoghq.com
-			ddPoller := polltrigger.NewDatadogPoller(datadogAPIKey, datadogAppKey, datadogSite)
-			if err := pollTriggerSvc.RegisterPoller(ddPoller); err != nil {
-				logger.Warn("failed to register Datadog poller",
-					internallog.Error(err))
-			}
-		}
-
-		// Register Jira poller if credentials are configured
-		jiraEmail := os.Getenv("JIRA_EMAIL")
-		jiraAPIToken := os.Getenv("JIRA_API_TOKEN")
-		jiraBaseURL := os.Getenv("JIRA_BASE_URL")
-		if jiraEmail != "" && jiraAPIToken != "" && jiraBaseURL != "" {
-			jiraPoller := polltrigger.NewJiraPoller(jiraEmail, jiraAPIToken, jiraBaseURL)
-			if err := pollTriggerSvc.RegisterPoller(jiraPoller); err != nil {
-				logger.Warn("failed to register Jira poller",
-					internallog.Error(err))
+		// Register a poller for each integration whose credentials are configured
+		for _, integration := range polltrigger.PollerIntegrations {
+			poller, err := polltrigger.NewPollerFromEnv(integration)
+			if err != nil {
+				if !errors.Is(err, polltrigger.ErrPollerNotConfigured) {
+					logger.Warn("failed to create poller",
+						slog.String("integration", integration),
+						internallog.Error(err))
+				}
+				continue
 			}
-		}
-
-		// Register Slack poller if bot token is configured
-		slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
-		if slackBotToken != "" {
-			slackPoller := polltrigger.NewSlackPoller(slackBotToken)
-			if err := pollTriggerSvc.RegisterPoller(slackPoller); err != nil {
-				logger.Warn("failed to register Slack poller",
+			if err := pollTriggerSvc.RegisterPoller(poller); err != nil {
+				logger.Warn("failed to register poller",
+					slog.String("integration", integration),
 					internallog.Error(err))
 			}
 		}
@@ -583,6 +744,7 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 		fileWatcher:        fileWatcherSvc,
 		endpointHandler:    endpointHandler,
 		authMw:             authMw,
+		authorizer:         authorizer,
 		leader:             elector,
 		mcpRegistry:        mcpRegistry,
 		mcpLogCapture:      mcpLogCapture,
@@ -591,6 +753,13 @@ func New(cfg *config.Config, opts Options) (*Controller, error) {
 		auditLogger:        auditLogger,
 		pollTriggerService: pollTriggerSvc,
 		debugSessionMgr:    debugSessionMgr,
+		approvals:          approvals,
+		notifier:           notifier,
+		triggerEvents:      triggerEvents,
+		webhookSources:     webhookSources,
+		chainer:            chainer,
+		queueTriggers:      queueTriggers,
+		imapTriggers:       imapTriggers,
 		lastActivity:       time.Now(),
 		autoStarted:        autoStarted,
 
@@ -677,13 +846,16 @@ func (c *Controller) Start(ctx context.Context) error {
 				Workflow:     r.Workflow,
 				Events:       r.Events,
 				Secret:       r.Secret,
+				Filter:       r.Filter,
 				InputMapping: r.InputMapping,
 			}
 		}
 		webhookRouter := webhook.NewRouter(webhook.Config{
 			Routes:       webhookRoutes,
 			WorkflowsDir: c.cfg.Controller.WorkflowsDir,
+			Sources:      c.webhookSources,
 		}, c.runner)
+		webhookRouter.SetEventLog(c.triggerEvents)
 		webhookRouter.RegisterRoutes(router.Mux())
 	}
 
@@ -702,17 +874,72 @@ func (c *Controller) Start(ctx context.Context) error {
 		mcpHandler.RegisterRoutes(router.Mux())
 	}
 
-	// Register traces, events, and debug API if observability storage is available
-	if c.otelProvider != nil && c.otelProvider.GetStore() != nil {
-		store := c.otelProvider.GetStore()
+	// Serve workflows or endpoints as MCP tools if enabled
+	if c.cfg.Controller.MCPServer.Enabled {
+		if err := c.registerMCPServer(router.Mux()); err != nil {
+			return fmt.Errorf("failed to start MCP server: %w", err)
+		}
+	}
+
+	// Register OIDC login settings if OIDC is configured
+	if oidcCfg := c.cfg.Controller.ControllerAuth.OIDC; oidcCfg.Issuer != "" {
+		scopes := oidcCfg.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "profile", "email", "offline_access"}
+		}
+		oidcHandler := api.NewOIDCHandler(api.OIDCInfo{
+			Issuer:   oidcCfg.Issuer,
+			ClientID: oidcCfg.ClientID,
+			Scopes:   scopes,
+		})
+		oidcHandler.RegisterRoutes(router.Mux())
+	}
+
+	// Register roles and role bindings API
+	rbacHandler := api.NewRBACHandler(c.authorizer, c.cfg.Controller.ControllerAuth.RBAC.Enabled)
+	rbacHandler.RegisterRoutes(router.Mux())
+
+	// Register approvals API
+	approvalsHandler := api.NewApprovalsHandler(c.approvals)
+	approvalsHandler.SetRuns(c.runner)
+	approvalsHandler.RegisterRoutes(router.Mux())
+
+	// Register notification dead letters API
+	notificationStore, _ := c.backend.(backend.NotificationStore)
+	notificationsHandler := api.NewNotificationsHandler(notificationStore)
+	notificationsHandler.RegisterRoutes(router.Mux())
+
+	// Register trigger events API
+	triggerEventStore, _ := c.backend.(backend.TriggerEventStore)
+	triggerEventsHandler := api.NewTriggerEventsHandler(triggerEventStore, c.triggerEvents)
+	triggerEventsHandler.RegisterRoutes(router.Mux())
+
+	// Register events API. Run events are always streamed; stored trace
+	// events need observability storage.
+	var store *storage.SQLiteStore
+	if c.otelProvider != nil {
+		store = c.otelProvider.GetStore()
+	}
+	eventsHandler := api.NewEventsHandler(store)
+	eventsHandler.SetRunEvents(c.runner)
+	eventsHandler.RegisterRoutes(router.Mux())
+
+	// Register the web dashboard if enabled
+	if c.cfg.Controller.Dashboard.Enabled {
+		dashboardHandler := api.NewDashboardHandler()
+		dashboardHandler.RegisterRoutes(router.Mux())
+	}
+
+	// Register traces and debug API if observability storage is available
+	if store != nil {
 		tracesHandler := api.NewTracesHandler(store)
+		tracesHandler.SetRuns(c.runner)
 		tracesHandler.RegisterRoutes(router.Mux())
-		eventsHandler := api.NewEventsHandler(store)
-		eventsHandler.RegisterRoutes(router.Mux())
 
 		// Register debug API if session manager is available
 		if c.debugSessionMgr != nil {
 			debugHandler := api.NewDebugHandler(c.debugSessionMgr)
+			debugHandler.SetRuns(c.runner)
 			debugHandler.RegisterRoutes(router.Mux())
 		}
 	}
@@ -812,20 +1039,42 @@ func (c *Controller) Start(ctx context.Context) error {
 				if isLeader {
 					c.scheduler.Start(ctx)
 					c.logger.Info("became leader - scheduler started",
-						slog.Int("schedule_count", len(c.cfg.Controller.Schedules.Schedules)))
+						slog.Int("schedule_count", c.scheduler.GetScheduleCount()))
 				} else {
 					c.scheduler.Stop()
 					c.logger.Info("lost leadership - scheduler stopped")
 				}
 			})
 		}
+
+		// Register callback to start/stop queue trigger consumers and imap
+		// trigger watchers based on leadership, so each message and email is
+		// handled by the leader alone
+		if c.cfg.Controller.Distributed.LeaderElection {
+			c.leader.OnLeadershipChange(func(isLeader bool) {
+				if isLeader {
+					c.queueTriggers.Start(ctx)
+					c.imapTriggers.Start(ctx)
+					c.logger.Info("became leader - queue and imap triggers started",
+						slog.Int("queue_trigger_count", c.queueTriggers.Count()),
+						slog.Int("imap_trigger_count", c.imapTriggers.Count()))
+				} else {
+					c.queueTriggers.Stop()
+					c.imapTriggers.Stop()
+					c.logger.Info("lost leadership - queue and imap triggers stopped")
+				}
+			})
+		}
 	}
 
+	// Start workers claiming queued runs if in distributed mode
+	c.runner.StartWorkers(ctx)
+
 	// Start scheduler if configured (and not using leader election)
 	if c.scheduler != nil && (c.leader == nil || !c.cfg.Controller.Distributed.LeaderElection) {
 		c.scheduler.Start(ctx)
 		c.logger.Info("scheduler started",
-			slog.Int("schedule_count", len(c.cfg.Controller.Schedules.Schedules)))
+			slog.Int("schedule_count", c.scheduler.GetScheduleCount()))
 	}
 
 	// Start file watcher service if configured
@@ -879,8 +1128,16 @@ func (c *Controller) Start(ctx context.Context) error {
 					}
 				}
 
+				// Watchers of a workflow with several file triggers are
+				// named apart by trigger ID
+				name := fmt.Sprintf("workflow:%s", t.WorkflowName)
+				if t.ID != string(workflow.TriggerTypeFile) {
+					name += ":" + t.ID
+				}
+
 				config := filewatcher.WatchConfig{
-					Name:                 fmt.Sprintf("workflow:%s", t.WorkflowName),
+					Name:                 name,
+					TriggerID:            t.ID,
 					Workflow:             t.WorkflowPath,
 					Paths:                t.File.Paths,
 					Events:               t.File.Events,
@@ -925,6 +1182,25 @@ func (c *Controller) Start(ctx context.Context) error {
 		}
 	}
 
+	// Prune old trigger events
+	c.triggerEvents.Start(ctx)
+
+	// Register run_completed, queue and imap triggers from workflow definitions
+	c.registerWorkflowTriggers()
+
+	// Start queue trigger consumers and imap trigger watchers (and not
+	// using leader election)
+	if c.leader == nil || !c.cfg.Controller.Distributed.LeaderElection {
+		c.queueTriggers.Start(ctx)
+		if n := c.queueTriggers.Count(); n > 0 {
+			c.logger.Info("queue triggers started", slog.Int("trigger_count", n))
+		}
+		c.imapTriggers.Start(ctx)
+		if n := c.imapTriggers.Count(); n > 0 {
+			c.logger.Info("imap triggers started", slog.Int("trigger_count", n))
+		}
+	}
+
 	// Start poll trigger service and scan workflows
 	if c.pollTriggerService != nil {
 		if err := c.pollTriggerService.Start(ctx); err != nil {
@@ -963,8 +1239,10 @@ func (c *Controller) Start(ctx context.Context) error {
 	var publicErrCh chan error
 	if c.cfg.Controller.Listen.PublicAPI.Enabled {
 		publicRouter := api.NewPublicRouter(api.PublicRouterConfig{
-			Runner:       c.runner,
-			WorkflowsDir: c.cfg.Controller.WorkflowsDir,
+			Runner:         c.runner,
+			WorkflowsDir:   c.cfg.Controller.WorkflowsDir,
+			Events:         c.triggerEvents,
+			WebhookSources: c.webhookSources,
 		})
 		c.publicServer = publicapi.New(
 			c.cfg.Controller.Listen.PublicAPI,
@@ -1046,6 +1324,13 @@ func (c *Controller) Shutdown(ctx context.Context) error {
 		c.logger.Info("runner stopped cleanly")
 	}
 
+	// Deliver notifications sent as the last runs finished
+	if c.notifier != nil {
+		notifyCtx, notifyCancel := context.WithTimeout(ctx, 5*time.Second)
+		c.notifier.Stop(notifyCtx)
+		notifyCancel()
+	}
+
 	// Stop leader election
 	if c.leader != nil {
 		c.leader.Stop()
@@ -1056,6 +1341,12 @@ func (c *Controller) Shutdown(ctx context.Context) error {
 		c.scheduler.Stop()
 	}
 
+	// Stop queue trigger consumers; unacknowledged messages are redelivered
+	c.queueTriggers.Stop()
+
+	// Stop imap trigger watchers; emails not yet delivered stay unread
+	c.imapTriggers.Stop()
+
 	// Stop file watcher service
 	if c.fileWatcher != nil {
 		if err := c.fileWatcher.Stop(); err != nil {
@@ -1071,6 +1362,14 @@ func (c *Controller) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Stop watching MCP server runs
+	if c.mcpServer != nil {
+		if err := c.mcpServer.Shutdown(ctx); err != nil {
+			c.logger.Error("MCP server shutdown error",
+				internallog.Error(err))
+		}
+	}
+
 	// Stop poll trigger service
 	if c.pollTriggerService != nil {
 		shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 5*time.Second)
@@ -1473,6 +1772,89 @@ func convertRedactionPatterns(patterns []config.RedactionPattern) []tracing.Reda
 	return result
 }
 
+// registerWorkflowTriggers scans the workflows directory for run_completed,
+// queue and imap triggers and registers them with the chainer and the queue
+// and imap trigger services.
+func (c *Controller) registerWorkflowTriggers() {
+	if c.cfg.Controller.WorkflowsDir == "" {
+		return
+	}
+
+	scanResult, err := trigger.NewScanner(c.cfg.Controller.WorkflowsDir).Scan()
+	if err != nil {
+		c.logger.Warn("failed to scan workflows for run_completed, queue and imap triggers",
+			internallog.Error(err))
+		return
+	}
+
+	c.chainer.Register(scanResult.RunCompletedTriggers)
+	for _, t := range scanResult.RunCompletedTriggers {
+		c.logger.Info("registered run_completed trigger from workflow",
+			slog.String("workflow", t.WorkflowName),
+			slog.String("upstream", t.RunCompleted.Workflow))
+	}
+
+	c.queueTriggers.Register(scanResult.QueueTriggers)
+	for _, t := range scanResult.QueueTriggers {
+		c.logger.Info("registered queue trigger from workflow",
+			slog.String("workflow", t.WorkflowName),
+			slog.String("broker", t.Queue.Broker))
+	}
+
+	c.imapTriggers.Register(scanResult.IMAPTriggers)
+	for _, t := range scanResult.IMAPTriggers {
+		c.logger.Info("registered imap trigger from workflow",
+			slog.String("workflow", t.WorkflowName),
+			slog.String("folder", t.IMAP.FolderName()))
+	}
+}
+
+// workflowSchedules returns the schedule triggers of the workflows in dir.
+// Triggers with an invalid cron expression are skipped.
+func workflowSchedules(dir string, logger *slog.Logger) []scheduler.Schedule {
+	if dir == "" {
+		return nil
+	}
+
+	scanResult, err := trigger.NewScanner(dir).Scan()
+	if err != nil {
+		logger.Warn("failed to scan workflows for schedule triggers",
+			internallog.Error(err))
+		return nil
+	}
+
+	var schedules []scheduler.Schedule
+	for _, t := range scanResult.ScheduleTriggers {
+		if _, err := scheduler.ParseCron(t.Schedule.Cron); err != nil {
+			logger.Warn("invalid schedule trigger in workflow",
+				slog.String("workflow", t.WorkflowName),
+				slog.String("trigger_id", t.ID),
+				internallog.Error(err))
+			continue
+		}
+
+		name := "workflow:" + t.WorkflowName
+		if t.ID != string(workflow.TriggerTypeSchedule) {
+			name += ":" + t.ID
+		}
+		workflowName := t.WorkflowPath
+		if rel, err := filepath.Rel(dir, t.WorkflowPath); err == nil {
+			workflowName = rel
+		}
+
+		schedules = append(schedules, scheduler.Schedule{
+			Name:      name,
+			Cron:      t.Schedule.Cron,
+			Timezone:  t.Schedule.Timezone,
+			Workflow:  strings.TrimSuffix(workflowName, filepath.Ext(workflowName)),
+			Inputs:    t.Schedule.Inputs,
+			Enabled:   t.Schedule.Enabled == nil || *t.Schedule.Enabled,
+			TriggerID: t.ID,
+		})
+	}
+	return schedules
+}
+
 // scanAndRegisterPollTriggers scans the workflows directory and registers poll triggers.
 func (c *Controller) scanAndRegisterPollTriggers(ctx context.Context) {
 	workflowsDir := c.cfg.Controller.WorkflowsDir
@@ -1519,11 +1901,14 @@ func (c *Controller) scanAndRegisterPollTriggers(ctx context.Context) {
 			c.logger.Warn("failed to register poll trigger",
 				internallog.Error(err),
 				slog.String("workflow", workflowPath))
-		} else if wf.Trigger != nil && wf.Trigger.Poll != nil {
-			registeredCount++
-			c.logger.Info("registered poll trigger from workflow",
-				slog.String("workflow", entry.Name()),
-				slog.String("integration", wf.Trigger.Poll.Integration))
+		} else {
+			for _, t := range wf.TriggersOfType(workflow.TriggerTypePoll) {
+				registeredCount++
+				c.logger.Info("registered poll trigger from workflow",
+					slog.String("workflow", entry.Name()),
+					slog.String("trigger_id", t.TriggerID()),
+					slog.String("integration", t.Poll.Integration))
+			}
 		}
 	}
 
@@ -1534,11 +1919,20 @@ func (c *Controller) scanAndRegisterPollTriggers(ctx context.Context) {
 }
 
 // createOperationRegistry creates an operation registry with builtin actions and workspace integrations.
+// The email action sends through the notifications SMTP server by default.
 // Returns the registry and the count of integrations loaded.
-func createOperationRegistry(workflowsDir string, logger *slog.Logger) (*operation.Registry, int) {
+func createOperationRegistry(workflowsDir, tempDir string, smtp config.SMTPConfig, logger *slog.Logger) (*operation.Registry, int) {
 	// Create registry with builtin actions
 	opConfig := &operation.BuiltinConfig{
 		WorkflowDir: workflowsDir,
+		TempDir:     tempDir,
+		SMTP: email.SMTPConfig{
+			Host:     smtp.Host,
+			Port:     smtp.Port,
+			Username: smtp.Username,
+			Password: smtp.Password,
+			From:     smtp.From,
+		},
 	}
 	registry, err := operation.NewBuiltinRegistry(opConfig)
 	if err != nil {
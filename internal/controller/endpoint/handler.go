@@ -17,6 +17,7 @@ package endpoint
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -60,6 +61,11 @@ func NewHandler(registry *Registry, r *runner.Runner, workflowsDir string) *Hand
 	}
 }
 
+// Registry returns the endpoint registry served by this handler.
+func (h *Handler) Registry() *Registry {
+	return h.registry
+}
+
 // SetRateLimiter sets the rate limiter for this handler.
 // This allows external configuration of rate limits.
 func (h *Handler) SetRateLimiter(rl *auth.NamedRateLimiter) {
@@ -384,7 +390,16 @@ func (h *Handler) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 		Workspace:    req.Workspace,
 		Profile:      req.Profile,
 		Timeout:      timeout,
+		Authorize: func(workflow, workspace string) error {
+			return auth.Authorize(r.Context(), auth.ActionRun, auth.Resource{Workflow: workflow, Workspace: workspace})
+		},
 	})
+	var forbidden *auth.ForbiddenError
+	if errors.As(err, &forbidden) {
+		statusCode = http.StatusForbidden
+		writeError(w, statusCode, err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to submit run",
 			slog.String("endpoint", name),
@@ -470,7 +485,11 @@ func (h *Handler) handleListRuns(w http.ResponseWriter, r *http.Request) {
 	endpointRuns := make([]*runner.RunSnapshot, 0)
 	for _, run := range runs {
 		// Match runs that use the same workflow as this endpoint
-		if run.WorkflowID == ep.Name || containsPath(run.SourceURL, ep.Workflow) {
+		if run.WorkflowID != ep.Name && !containsPath(run.SourceURL, ep.Workflow) {
+			continue
+		}
+		// The list includes run outputs
+		if auth.Allowed(r.Context(), auth.ActionReadOutputs, auth.Resource{Workflow: run.Workflow, Workspace: run.Workspace}) {
 			endpointRuns = append(endpointRuns, run)
 		}
 	}
@@ -16,6 +16,7 @@ package endpoint
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -627,6 +628,88 @@ steps:
 	}
 }
 
+// endpointRoleStore holds one role limited to deploy workflows.
+type endpointRoleStore struct {
+	auth.RoleStore
+}
+
+func (endpointRoleStore) GetRole(ctx context.Context, name string) (*auth.Role, error) {
+	if name != "deployer" {
+		return nil, auth.ErrRoleNotFound
+	}
+	return &auth.Role{
+		Name: "deployer",
+		Permissions: []auth.Permission{{
+			Actions:   []auth.Action{auth.ActionRead, auth.ActionReadOutputs, auth.ActionRun},
+			Workflows: []string{"deploy-*"},
+		}},
+	}, nil
+}
+
+func (endpointRoleStore) GetRoleBinding(ctx context.Context, subject string) (*auth.RoleBinding, error) {
+	return nil, auth.ErrRoleNotFound
+}
+
+func TestHandlerRBAC(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowContent := `
+name: cleanup
+steps:
+  - id: echo
+    type: llm
+    prompt: "test"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "cleanup.yaml"), []byte(workflowContent), 0600); err != nil {
+		t.Fatalf("failed to write workflow file: %v", err)
+	}
+
+	registry := NewRegistry()
+	registry.Add(&Endpoint{Name: "cleanup", Workflow: "cleanup.yaml"})
+
+	mux := http.NewServeMux()
+	NewHandler(registry, createTestRunner(t), tmpDir).RegisterRoutes(mux)
+	handler := auth.NewMiddleware(auth.Config{
+		Enabled: true,
+		APIKeys: []auth.APIKey{
+			{Key: "deployer-key", Name: "deployer", Roles: []string{"deployer"}},
+			{Key: "admin-key", Name: "admin", Roles: []string{auth.RoleAdmin}},
+		},
+		Authorizer: auth.NewAuthorizer(auth.AuthorizerConfig{Store: endpointRoleStore{}}),
+	}).Wrap(mux)
+
+	request := func(key, method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/endpoints/cleanup/runs", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	listRuns := func(key string) int {
+		var response struct {
+			Runs []*runner.RunSnapshot `json:"runs"`
+		}
+		if err := json.NewDecoder(request(key, "GET").Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return len(response.Runs)
+	}
+
+	// The deployer may not run the cleanup workflow behind the endpoint
+	if rec := request("deployer-key", "POST"); rec.Code != http.StatusForbidden {
+		t.Errorf("deployer run: expected status 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := request("admin-key", "POST"); rec.Code != http.StatusAccepted {
+		t.Fatalf("admin run: expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if got := listRuns("admin-key"); got != 1 {
+		t.Errorf("admin list: expected 1 run, got %d", got)
+	}
+	if got := listRuns("deployer-key"); got != 0 {
+		t.Errorf("deployer list: expected 0 runs, got %d", got)
+	}
+}
+
 // TestHandlerSyncModeAsync tests that without ?wait=true, requests remain async.
 func TestHandlerSyncModeAsync(t *testing.T) {
 	// Create workflow file
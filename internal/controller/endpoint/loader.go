@@ -24,6 +24,7 @@ import (
 
 	"github.com/tombee/conductor/internal/config"
 	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
 // LoadConfig loads endpoints from configuration into a registry.
@@ -76,6 +77,27 @@ func LoadConfig(cfg config.EndpointsConfig, workflowsDir string, rateLimiter *au
 	return registry, nil
 }
 
+// LoadWorkflow locates and parses the workflow executed by an endpoint.
+// Returns the workflow file path and its definition.
+func LoadWorkflow(ep *Endpoint, workflowsDir string) (string, *workflow.Definition, error) {
+	workflowPath, err := findWorkflow(ep.Workflow, workflowsDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read workflow %q: %w", ep.Workflow, err)
+	}
+
+	def, err := workflow.ParseDefinition(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse workflow %q: %w", ep.Workflow, err)
+	}
+
+	return workflowPath, def, nil
+}
+
 // findWorkflow locates a workflow file by name.
 // It searches in the workflows directory with various extensions (.yaml, .yml, or no extension).
 // Returns the full path if found, error otherwise.
@@ -305,6 +305,35 @@ func TestFindWorkflow(t *testing.T) {
 	}
 }
 
+func TestLoadWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "name: review\ninputs:\n  - name: branch\n    type: string\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "review.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "broken.yaml"), []byte("steps: ["), 0644); err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+
+	path, def, err := LoadWorkflow(&Endpoint{Name: "review", Workflow: "review"}, tmpDir)
+	if err != nil {
+		t.Fatalf("LoadWorkflow() error = %v", err)
+	}
+	if path != filepath.Join(tmpDir, "review.yaml") {
+		t.Errorf("LoadWorkflow() path = %v", path)
+	}
+	if def.Name != "review" || len(def.Inputs) != 1 {
+		t.Errorf("LoadWorkflow() definition = %+v", def)
+	}
+
+	if _, _, err := LoadWorkflow(&Endpoint{Name: "broken", Workflow: "broken.yaml"}, tmpDir); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("LoadWorkflow() error = %v, want parse error", err)
+	}
+	if _, _, err := LoadWorkflow(&Endpoint{Name: "missing", Workflow: "missing"}, tmpDir); err == nil {
+		t.Error("LoadWorkflow() with missing workflow succeeded, want error")
+	}
+}
+
 func TestParseRateLimit(t *testing.T) {
 	tests := []struct {
 		name         string
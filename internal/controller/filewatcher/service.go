@@ -71,6 +71,10 @@ type WatchConfig struct {
 
 	// Inputs are passed to the workflow along with the file context
 	Inputs map[string]any
+
+	// TriggerID identifies the trigger within its workflow and is passed
+	// to runs as trigger.id. Defaults to Name
+	TriggerID string
 }
 
 // Service manages all file watchers for the controller.
@@ -419,6 +423,11 @@ func (s *Service) triggerWorkflows(entry *watcherEntry, events []*Context) {
 	}
 
 	// Add trigger context
+	triggerID := config.TriggerID
+	if triggerID == "" {
+		triggerID = config.Name
+	}
+
 	// In batch mode with multiple events, provide both single file context and array
 	if config.BatchMode && len(events) > 1 {
 		// Provide array of all file events
@@ -427,6 +436,8 @@ func (s *Service) triggerWorkflows(entry *watcherEntry, events []*Context) {
 			fileEvents[i] = evt
 		}
 		inputs["trigger"] = map[string]any{
+			"type":  "file",
+			"id":    triggerID,
 			"file":  events[0], // First event for backward compatibility
 			"files": fileEvents,
 			"count": len(events),
@@ -434,6 +445,8 @@ func (s *Service) triggerWorkflows(entry *watcherEntry, events []*Context) {
 	} else {
 		// Single event (either non-batch or batch with 1 event)
 		inputs["trigger"] = map[string]any{
+			"type": "file",
+			"id":   triggerID,
 			"file": events[0],
 		}
 	}
@@ -453,6 +466,7 @@ func (s *Service) triggerWorkflows(entry *watcherEntry, events []*Context) {
 	_, err = s.runner.Submit(s.ctx, runner.SubmitRequest{
 		WorkflowYAML: workflowYAML,
 		Inputs:       inputs,
+		Priority:     runner.PriorityTrigger,
 	})
 	if err != nil {
 		recordError(config.Name, "workflow_submit")
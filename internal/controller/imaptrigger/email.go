@@ -0,0 +1,224 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imaptrigger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message"
+	_ "github.com/emersion/go-message/charset" // Decodes non-UTF-8 charsets
+	"github.com/emersion/go-message/mail"
+)
+
+// Email is a parsed email.
+type Email struct {
+	// MessageID is the Message-ID header, without angle brackets. It is the
+	// idempotency key of the email's trigger event
+	MessageID string
+
+	Subject string
+	From    string
+	To      []string
+	Cc      []string
+	Date    time.Time
+
+	// Text and HTML are the plain text and HTML bodies, if present
+	Text string
+	HTML string
+
+	// Headers are the top-level headers, by canonical name
+	Headers map[string]string
+
+	Attachments []*Attachment
+}
+
+// Attachment is a file attached to an email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+
+	// Path is where the attachment was saved, as a $temp/ path that file
+	// actions can read. Set once saved
+	Path string
+}
+
+// ParseEmail parses a raw RFC 5322 message. Parts in unknown charsets are
+// kept undecoded rather than failing the whole email.
+func ParseEmail(raw []byte) (*Email, error) {
+	r, err := mail.CreateReader(bytes.NewReader(raw))
+	if err != nil && !message.IsUnknownCharset(err) {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+	defer r.Close()
+
+	email := &Email{Headers: make(map[string]string)}
+	fields := r.Header.Fields()
+	for fields.Next() {
+		if _, ok := email.Headers[fields.Key()]; !ok {
+			email.Headers[fields.Key()], _ = fields.Text()
+		}
+	}
+
+	email.MessageID, _ = r.Header.MessageID()
+	if email.MessageID == "" {
+		// Without a Message-ID the content identifies the email
+		sum := sha256.Sum256(raw)
+		email.MessageID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	email.Subject, _ = r.Header.Subject()
+	email.Date, _ = r.Header.Date()
+	if from, _ := r.Header.AddressList("From"); len(from) > 0 {
+		email.From = from[0].Address
+	}
+	email.To = addresses(r.Header, "To")
+	email.Cc = addresses(r.Header, "Cc")
+
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !message.IsUnknownCharset(err) {
+			return nil, fmt.Errorf("failed to read email part: %w", err)
+		}
+
+		body, err := io.ReadAll(part.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email part: %w", err)
+		}
+
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, _, _ := h.ContentType()
+			switch {
+			case contentType == "text/html" && email.HTML == "":
+				email.HTML = string(body)
+			case strings.HasPrefix(contentType, "text/") && contentType != "text/html" && email.Text == "":
+				email.Text = string(body)
+			case !strings.HasPrefix(contentType, "text/"):
+				// Inline images and the like are kept as attachments
+				email.Attachments = append(email.Attachments, newAttachment(&h.Header, "", contentType, body, len(email.Attachments)))
+			}
+		case *mail.AttachmentHeader:
+			contentType, _, _ := h.ContentType()
+			filename, _ := h.Filename()
+			email.Attachments = append(email.Attachments, newAttachment(&h.Header, filename, contentType, body, len(email.Attachments)))
+		}
+	}
+
+	return email, nil
+}
+
+// Fields returns the email as workflow inputs.
+func (e *Email) Fields() map[string]any {
+	headers := make(map[string]any, len(e.Headers))
+	for k, v := range e.Headers {
+		headers[k] = v
+	}
+	attachments := make([]any, len(e.Attachments))
+	for i, a := range e.Attachments {
+		attachments[i] = map[string]any{
+			"filename":     a.Filename,
+			"content_type": a.ContentType,
+			"size":         len(a.Data),
+			"path":         a.Path,
+		}
+	}
+
+	date := ""
+	if !e.Date.IsZero() {
+		date = e.Date.UTC().Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"message_id":  e.MessageID,
+		"subject":     e.Subject,
+		"from":        e.From,
+		"to":          stringsToAny(e.To),
+		"cc":          stringsToAny(e.Cc),
+		"date":        date,
+		"text":        e.Text,
+		"html":        e.HTML,
+		"headers":     headers,
+		"attachments": attachments,
+	}
+}
+
+// newAttachment creates an attachment, naming it after its part if the
+// part gives no filename.
+func newAttachment(h *message.Header, filename, contentType string, data []byte, index int) *Attachment {
+	if filename == "" {
+		if _, params, err := h.ContentType(); err == nil {
+			filename = params["name"]
+		}
+	}
+	filename = safeFilename(filename)
+	if filename == "" {
+		filename = fmt.Sprintf("attachment-%d", index+1)
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			filename += exts[0]
+		}
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	}
+}
+
+// safeFilename strips the directories and unprintable characters from a
+// filename given by the sender.
+func safeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '\\' {
+			return '/'
+		}
+		return r
+	}, name)
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." || name == ".." {
+		return ""
+	}
+	return name
+}
+
+func addresses(h mail.Header, key string) []string {
+	list, _ := h.AddressList(key)
+	addrs := make([]string, 0, len(list))
+	for _, a := range list {
+		addrs = append(addrs, a.Address)
+	}
+	return addrs
+}
+
+func stringsToAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
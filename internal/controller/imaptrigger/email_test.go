@@ -0,0 +1,161 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imaptrigger
+
+import (
+	"strings"
+	"testing"
+)
+
+// crlf converts a fixture to the line endings of raw email.
+func crlf(s string) []byte {
+	return []byte(strings.ReplaceAll(strings.TrimPrefix(s, "\n"), "\n", "\r\n"))
+}
+
+const multipartEmail = `
+From: Vendor Alerts <alerts@vendor.example>
+To: ops@example.com, Team <team@example.com>
+Cc: audit@example.com
+Subject: =?UTF-8?B?V2Vla2x5IHJlcG9ydCDinJM=?=
+Date: Mon, 05 Oct 2026 09:30:00 +0000
+Message-ID: <report-42@vendor.example>
+X-Vendor-Id: 42
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Caf=E9 sales are up.
+--inner
+Content-Type: text/html; charset=utf-8
+
+<p>Caf&eacute; sales are up.</p>
+--inner--
+--outer
+Content-Type: text/csv; name="ignored.csv"
+Content-Disposition: attachment; filename="../../report.csv"
+Content-Transfer-Encoding: base64
+
+c2t1LHF0eQphLDEK
+--outer
+Content-Type: image/png
+Content-Disposition: inline
+Content-Transfer-Encoding: base64
+
+iVBORw0KGgo=
+--outer--
+`
+
+func TestParseEmail_Multipart(t *testing.T) {
+	email, err := ParseEmail(crlf(multipartEmail))
+	if err != nil {
+		t.Fatalf("ParseEmail() error = %v", err)
+	}
+
+	if email.MessageID != "report-42@vendor.example" {
+		t.Errorf("MessageID = %q", email.MessageID)
+	}
+	if email.Subject != "Weekly report ✓" {
+		t.Errorf("Subject = %q", email.Subject)
+	}
+	if email.From != "alerts@vendor.example" {
+		t.Errorf("From = %q", email.From)
+	}
+	if strings.Join(email.To, ",") != "ops@example.com,team@example.com" {
+		t.Errorf("To = %v", email.To)
+	}
+	if strings.Join(email.Cc, ",") != "audit@example.com" {
+		t.Errorf("Cc = %v", email.Cc)
+	}
+	if email.Date.IsZero() || email.Date.Day() != 5 {
+		t.Errorf("Date = %v", email.Date)
+	}
+	if email.Text != "Café sales are up." {
+		t.Errorf("Text = %q", email.Text)
+	}
+	if email.HTML != "<p>Caf&eacute; sales are up.</p>" {
+		t.Errorf("HTML = %q", email.HTML)
+	}
+	if email.Headers["X-Vendor-Id"] != "42" {
+		t.Errorf("Headers = %v", email.Headers)
+	}
+
+	if len(email.Attachments) != 2 {
+		t.Fatalf("got %d attachments, want 2", len(email.Attachments))
+	}
+	report := email.Attachments[0]
+	if report.Filename != "report.csv" || report.ContentType != "text/csv" || string(report.Data) != "sku,qty\na,1\n" {
+		t.Errorf("attachment 0 = %+v", report)
+	}
+	if image := email.Attachments[1]; image.Filename != "attachment-2.png" || image.ContentType != "image/png" {
+		t.Errorf("attachment 1 = %+v", image)
+	}
+
+	fields := email.Fields()
+	if fields["date"] != "2026-10-05T09:30:00Z" {
+		t.Errorf("date field = %v", fields["date"])
+	}
+	attachments := fields["attachments"].([]any)
+	if first := attachments[0].(map[string]any); first["filename"] != "report.csv" || first["size"] != 12 {
+		t.Errorf("attachments field = %v", attachments)
+	}
+}
+
+func TestParseEmail_WithoutMessageID(t *testing.T) {
+	raw := crlf(`
+From: someone@example.com
+Subject: No ID
+
+Just text.
+`)
+	email, err := ParseEmail(raw)
+	if err != nil {
+		t.Fatalf("ParseEmail() error = %v", err)
+	}
+	if email.Text != "Just text.\r\n" {
+		t.Errorf("Text = %q", email.Text)
+	}
+	if !strings.HasPrefix(email.MessageID, "sha256:") {
+		t.Errorf("MessageID = %q, want a content hash", email.MessageID)
+	}
+
+	again, _ := ParseEmail(raw)
+	if again.MessageID != email.MessageID {
+		t.Error("MessageID is not stable for the same content")
+	}
+}
+
+func TestSafeFilename(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":           "report.pdf",
+		"../../etc/passwd":     "passwd",
+		`C:\Users\me\a.txt`:    "a.txt",
+		"evil\x00name.txt":     "name.txt",
+		"..":                   "",
+		"":                     "",
+		"dir/":                 "dir",
+		"quarterly report.xls": "quarterly report.xls",
+	}
+	for in, want := range tests {
+		if got := safeFilename(in); got != want {
+			t.Errorf("safeFilename(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imaptrigger starts workflows for emails received in IMAP mailbox
+// folders.
+package imaptrigger
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+const (
+	// dialTimeout bounds connecting to the IMAP server.
+	dialTimeout = 30 * time.Second
+
+	// commandTimeout bounds each IMAP command other than IDLE.
+	commandTimeout = 2 * time.Minute
+)
+
+// Mailbox is a connection to the folder an imap trigger watches.
+type Mailbox interface {
+	// Unread returns the UIDs of the unread emails in the folder.
+	Unread() ([]uint32, error)
+
+	// Fetch returns the raw message with the given UID, without marking it
+	// as read.
+	Fetch(uid uint32) ([]byte, error)
+
+	// MarkProcessed marks an email as read or moves it, as its trigger is
+	// configured.
+	MarkProcessed(uid uint32) error
+
+	// Wait blocks until the folder may have new mail, the poll interval
+	// passes or ctx is done. It returns an error if the connection is lost.
+	Wait(ctx context.Context) error
+
+	// Close logs out and closes the connection.
+	Close() error
+}
+
+// Dialer connects to the mailbox of an imap trigger.
+type Dialer func(cfg *workflow.IMAPTriggerConfig) (Mailbox, error)
+
+// IMAPMailbox is a Mailbox on an IMAP server.
+type IMAPMailbox struct {
+	cfg     *workflow.IMAPTriggerConfig
+	client  *client.Client
+	updates chan client.Update
+	changed chan struct{} // Signalled when the folder has new mail
+}
+
+// Dial connects and logs in to the IMAP server of an imap trigger and
+// selects its folder.
+func Dial(cfg *workflow.IMAPTriggerConfig) (Mailbox, error) {
+	username := trigger.ExpandSecret(cfg.Username)
+	password := trigger.ExpandSecret(cfg.Password)
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("imap credentials are not set: %s", cfg.Username)
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+
+	var c *client.Client
+	var err error
+	if cfg.SecurityMode() == workflow.IMAPSecurityTLS {
+		c, err = client.DialWithDialerTLS(dialer, cfg.Address(), tlsConfig)
+	} else {
+		c, err = client.DialWithDialer(dialer, cfg.Address())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.Address(), err)
+	}
+	c.Timeout = commandTimeout
+
+	m := &IMAPMailbox{
+		cfg:     cfg,
+		client:  c,
+		updates: make(chan client.Update, 16),
+		changed: make(chan struct{}, 1),
+	}
+	// The client blocks until its updates are received
+	c.Updates = m.updates
+	go m.watchUpdates()
+
+	if cfg.SecurityMode() == workflow.IMAPSecuritySTARTTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			m.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if err := c.Login(username, password); err != nil {
+		m.Close()
+		return nil, fmt.Errorf("failed to log in: %w", err)
+	}
+	if _, err := c.Select(cfg.FolderName(), false); err != nil {
+		m.Close()
+		return nil, fmt.Errorf("failed to select folder %s: %w", cfg.FolderName(), err)
+	}
+
+	return m, nil
+}
+
+// Unread returns the UIDs of the unread emails in the folder.
+func (m *IMAPMailbox) Unread() ([]uint32, error) {
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag, imap.DeletedFlag}
+	uids, err := m.client.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for unread emails: %w", err)
+	}
+	return uids, nil
+}
+
+// Fetch returns the raw message with the given UID, without marking it as
+// read.
+func (m *IMAPMailbox) Fetch(uid uint32) ([]byte, error) {
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+	section := &imap.BodySectionName{Peek: true}
+
+	messages := make(chan *imap.Message, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- m.client.UidFetch(seqSet, []imap.FetchItem{section.FetchItem()}, messages)
+	}()
+
+	var raw []byte
+	var readErr error
+	for msg := range messages {
+		if body := msg.GetBody(section); body != nil && raw == nil {
+			raw, readErr = io.ReadAll(body)
+		}
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("failed to fetch email %d: %w", uid, err)
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read email %d: %w", uid, readErr)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("email %d not found", uid)
+	}
+	return raw, nil
+}
+
+// MarkProcessed marks an email as read or moves it to the move_to folder.
+func (m *IMAPMailbox) MarkProcessed(uid uint32) error {
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+
+	if m.cfg.ProcessedAction() == workflow.IMAPProcessedMove {
+		if err := m.client.UidMove(seqSet, m.cfg.MoveTo); err != nil {
+			return fmt.Errorf("failed to move email %d to %s: %w", uid, m.cfg.MoveTo, err)
+		}
+		return nil
+	}
+
+	flags := []interface{}{imap.SeenFlag}
+	if err := m.client.UidStore(seqSet, imap.FormatFlagsOp(imap.AddFlags, true), flags, nil); err != nil {
+		return fmt.Errorf("failed to mark email %d as read: %w", uid, err)
+	}
+	return nil
+}
+
+// Wait blocks until the folder may have new mail. In idle mode the server
+// announces new mail with IDLE; either way the folder is checked again once
+// the poll interval passes.
+func (m *IMAPMailbox) Wait(ctx context.Context) error {
+	interval := m.cfg.PollInterval()
+	if m.cfg.WatchMode() == workflow.IMAPModePoll {
+		select {
+		case <-time.After(interval):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// IDLE outlasts the command timeout
+	m.client.Timeout = 0
+	defer func() { m.client.Timeout = commandTimeout }()
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- m.client.Idle(stop, &client.IdleOptions{PollInterval: interval})
+	}()
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			err = fmt.Errorf("idle ended unexpectedly")
+		}
+		return fmt.Errorf("failed to idle: %w", err)
+	case <-m.changed:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	close(stop)
+	if err := <-done; err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to idle: %w", err)
+	}
+	return ctx.Err()
+}
+
+// Close logs out and closes the connection.
+func (m *IMAPMailbox) Close() error {
+	err := m.client.Logout()
+	if err == client.ErrAlreadyLoggedOut {
+		err = nil
+	}
+	return err
+}
+
+// watchUpdates signals changed when the server announces new mail. It
+// returns once the client has logged out.
+func (m *IMAPMailbox) watchUpdates() {
+	for {
+		select {
+		case update := <-m.updates:
+			if _, ok := update.(*client.MailboxUpdate); ok {
+				select {
+				case m.changed <- struct{}{}:
+				default:
+				}
+			}
+		case <-m.client.LoggedOut():
+			return
+		}
+	}
+}
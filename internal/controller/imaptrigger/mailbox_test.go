@@ -0,0 +1,247 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imaptrigger
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-imap/server"
+
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// moveBackend adds MOVE to the memory backend, as most IMAP servers
+// support it.
+type moveBackend struct{ backend.Backend }
+
+type moveUser struct{ backend.User }
+
+type moveMailbox struct{ backend.Mailbox }
+
+func (b moveBackend) Login(info *imap.ConnInfo, username, password string) (backend.User, error) {
+	user, err := b.Backend.Login(info, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return moveUser{user}, nil
+}
+
+func (u moveUser) GetMailbox(name string) (backend.Mailbox, error) {
+	mbox, err := u.User.GetMailbox(name)
+	if err != nil {
+		return nil, err
+	}
+	return moveMailbox{mbox}, nil
+}
+
+func (m moveMailbox) MoveMessages(uid bool, seqSet *imap.SeqSet, dest string) error {
+	if err := m.CopyMessages(uid, seqSet, dest); err != nil {
+		return err
+	}
+	if err := m.UpdateMessagesFlags(uid, seqSet, imap.AddFlags, []string{imap.DeletedFlag}); err != nil {
+		return err
+	}
+	return m.Expunge()
+}
+
+// startServer starts an in-memory IMAP server with a Processed folder and
+// returns the trigger config to reach it. The memory backend's INBOX
+// already holds one read email.
+func startServer(t *testing.T) *workflow.IMAPTriggerConfig {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := server.New(moveBackend{memory.New()})
+	s.AllowInsecureAuth = true
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+
+	addr := l.Addr().(*net.TCPAddr)
+	cfg := &workflow.IMAPTriggerConfig{
+		Host:     "127.0.0.1",
+		Port:     addr.Port,
+		Security: workflow.IMAPSecurityNone,
+		Username: "username",
+		Password: "password",
+		Interval: "100ms",
+	}
+
+	c := adminClient(t, cfg)
+	if err := c.Create("Processed"); err != nil {
+		t.Fatalf("failed to create folder: %v", err)
+	}
+	return cfg
+}
+
+func adminClient(t *testing.T, cfg *workflow.IMAPTriggerConfig) *client.Client {
+	t.Helper()
+	c, err := client.Dial(net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { c.Logout() })
+	if err := c.Login(cfg.Username, cfg.Password); err != nil {
+		t.Fatalf("failed to log in: %v", err)
+	}
+	return c
+}
+
+func appendEmail(t *testing.T, cfg *workflow.IMAPTriggerConfig, id string) {
+	t.Helper()
+	raw := crlf("\nFrom: alerts@vendor.example\nSubject: Alert " + id + "\nMessage-ID: <" + id + "@vendor.example>\n\nDisk full.\n")
+	if err := adminClient(t, cfg).Append("INBOX", nil, time.Now(), bytes.NewReader(raw)); err != nil {
+		t.Fatalf("failed to append email: %v", err)
+	}
+}
+
+func folderSize(t *testing.T, cfg *workflow.IMAPTriggerConfig, folder string) uint32 {
+	t.Helper()
+	status, err := adminClient(t, cfg).Status(folder, []imap.StatusItem{imap.StatusMessages})
+	if err != nil {
+		t.Fatalf("failed to get folder status: %v", err)
+	}
+	return status.Messages
+}
+
+func TestIMAPMailbox_MarkRead(t *testing.T) {
+	cfg := startServer(t)
+	appendEmail(t, cfg, "a1")
+
+	mailbox, err := Dial(cfg)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer mailbox.Close()
+
+	uids, err := mailbox.Unread()
+	if err != nil {
+		t.Fatalf("Unread() error = %v", err)
+	}
+	if len(uids) != 1 {
+		t.Fatalf("Unread() = %v, want the one unread email", uids)
+	}
+
+	raw, err := mailbox.Fetch(uids[0])
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	email, err := ParseEmail(raw)
+	if err != nil {
+		t.Fatalf("ParseEmail() error = %v", err)
+	}
+	if email.MessageID != "a1@vendor.example" {
+		t.Errorf("MessageID = %q", email.MessageID)
+	}
+
+	// Fetching does not mark the email as read
+	if uids, _ := mailbox.Unread(); len(uids) != 1 {
+		t.Fatalf("email marked as read by Fetch")
+	}
+
+	if err := mailbox.MarkProcessed(uids[0]); err != nil {
+		t.Fatalf("MarkProcessed() error = %v", err)
+	}
+	if uids, _ := mailbox.Unread(); len(uids) != 0 {
+		t.Errorf("Unread() = %v after MarkProcessed, want none", uids)
+	}
+}
+
+func TestIMAPMailbox_Move(t *testing.T) {
+	cfg := startServer(t)
+	cfg.Processed = workflow.IMAPProcessedMove
+	cfg.MoveTo = "Processed"
+	appendEmail(t, cfg, "b1")
+
+	mailbox, err := Dial(cfg)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer mailbox.Close()
+
+	uids, err := mailbox.Unread()
+	if err != nil || len(uids) != 1 {
+		t.Fatalf("Unread() = %v, %v", uids, err)
+	}
+	if err := mailbox.MarkProcessed(uids[0]); err != nil {
+		t.Fatalf("MarkProcessed() error = %v", err)
+	}
+
+	if n := folderSize(t, cfg, "Processed"); n != 1 {
+		t.Errorf("Processed holds %d emails, want 1", n)
+	}
+	if n := folderSize(t, cfg, "INBOX"); n != 1 {
+		t.Errorf("INBOX holds %d emails, want only the read one", n)
+	}
+}
+
+func TestIMAPMailbox_Wait(t *testing.T) {
+	for _, mode := range []string{workflow.IMAPModeIdle, workflow.IMAPModePoll} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := startServer(t)
+			cfg.Mode = mode
+
+			mailbox, err := Dial(cfg)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer mailbox.Close()
+
+			// Returns once the interval passes
+			start := time.Now()
+			if err := mailbox.Wait(context.Background()); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Wait() took %v", elapsed)
+			}
+
+			// And the connection is still usable
+			if _, err := mailbox.Unread(); err != nil {
+				t.Errorf("Unread() after Wait error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := mailbox.Wait(ctx); err != context.Canceled {
+				t.Errorf("Wait() with cancelled context = %v", err)
+			}
+		})
+	}
+}
+
+func TestDial_BadCredentials(t *testing.T) {
+	cfg := startServer(t)
+	cfg.Password = "wrong"
+	if _, err := Dial(cfg); err == nil {
+		t.Fatal("Dial() succeeded with a wrong password")
+	}
+
+	cfg.Password = "${CONDUCTOR_TEST_UNSET_IMAP_PASSWORD}"
+	if _, err := Dial(cfg); err == nil {
+		t.Fatal("Dial() succeeded without a password")
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imaptrigger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+const (
+	// reconnectDelay is the wait before reconnecting to a mailbox whose
+	// connection failed or was lost.
+	reconnectDelay = 30 * time.Second
+
+	// attachmentsDir is the directory under the temp directory that
+	// attachments are saved in, one directory per email.
+	attachmentsDir = "email"
+
+	// pruneInterval is how often old attachments are removed.
+	pruneInterval = time.Hour
+
+	// defaultRetention is how long saved attachments are kept by default.
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// Events records trigger events and starts their runs. It is implemented
+// by *trigger.EventLog.
+type Events interface {
+	Deliver(ctx context.Context, event *backend.TriggerEvent) (*backend.TriggerEvent, bool, error)
+}
+
+// Config configures a Service.
+type Config struct {
+	// Events delivers emails to their workflows
+	Events Events
+
+	// WorkflowsDir is where workflow files are looked up by name
+	WorkflowsDir string
+
+	// TempDir is the $temp directory of file actions. Attachments are saved
+	// under it. If empty, attachments are not saved
+	TempDir string
+
+	// Retention is how long saved attachments are kept. Default: 7 days
+	Retention time.Duration
+
+	// Dial connects to mailboxes. Default: Dial
+	Dial Dialer
+
+	Logger *slog.Logger
+}
+
+// Service watches the mailbox folders of imap triggers and starts a
+// workflow run for each unread email. Each email is delivered through the
+// event log, so it is recorded and deduplicated by Message-ID. An email is
+// marked as read, or moved, once its run is started; emails that could not
+// be delivered are left unread and retried on the next check.
+type Service struct {
+	events       Events
+	workflowsDir string
+	tempDir      string
+	retention    time.Duration
+	dial         Dialer
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	triggers  []trigger.WorkflowTrigger
+	ctx       context.Context // Set while running
+	cancel    context.CancelFunc
+	done      sync.WaitGroup
+	lastPrune time.Time
+}
+
+// NewService creates an imap trigger service.
+func NewService(cfg Config) *Service {
+	if cfg.Dial == nil {
+		cfg.Dial = Dial
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultRetention
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Service{
+		events:       cfg.Events,
+		workflowsDir: cfg.WorkflowsDir,
+		tempDir:      cfg.TempDir,
+		retention:    cfg.Retention,
+		dial:         cfg.Dial,
+		logger:       cfg.Logger,
+	}
+}
+
+// Register replaces the registered imap triggers. If the service is
+// running, its watchers are restarted.
+func (s *Service) Register(triggers []trigger.WorkflowTrigger) {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if ctx != nil {
+		s.Stop()
+	}
+
+	s.mu.Lock()
+	s.triggers = nil
+	for _, t := range triggers {
+		if t.IMAP != nil {
+			s.triggers = append(s.triggers, t)
+		}
+	}
+	s.mu.Unlock()
+
+	if ctx != nil {
+		s.Start(ctx)
+	}
+}
+
+// Count returns the number of registered imap triggers.
+func (s *Service) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.triggers)
+}
+
+// Start starts watching the folder of each registered trigger. Watchers
+// reconnect until the service is stopped or ctx is done.
+func (s *Service) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+
+	s.ctx = ctx
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, t := range s.triggers {
+		s.done.Add(1)
+		go func() {
+			defer s.done.Done()
+			s.watch(ctx, t)
+		}()
+	}
+}
+
+// Stop stops the watchers and waits for them to return.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if s.ctx == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.ctx = nil
+	s.cancel()
+	s.mu.Unlock()
+
+	s.done.Wait()
+}
+
+// watch processes a trigger's folder until ctx is done, reconnecting when
+// the connection fails.
+func (s *Service) watch(ctx context.Context, t trigger.WorkflowTrigger) {
+	logger := s.logger.With(
+		slog.String("workflow", t.WorkflowName),
+		slog.String("trigger", t.TriggerID()),
+		slog.String("folder", t.IMAP.FolderName()))
+
+	for {
+		mailbox, err := s.dial(t.IMAP)
+		if err != nil {
+			logger.Warn("failed to connect to imap mailbox", slog.String("error", err.Error()))
+		} else {
+			logger.Info("imap trigger watching", slog.String("mode", t.IMAP.WatchMode()))
+			err = s.run(ctx, t, mailbox)
+			mailbox.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("imap mailbox disconnected", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// run processes the unread emails in a mailbox each time it may have new
+// mail, until ctx is done or the connection is lost.
+func (s *Service) run(ctx context.Context, t trigger.WorkflowTrigger, mailbox Mailbox) error {
+	for {
+		if err := s.process(ctx, t, mailbox); err != nil {
+			return err
+		}
+		s.pruneAttachments()
+
+		if err := mailbox.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// process delivers the unread emails in a mailbox. It returns an error if
+// the mailbox cannot be read.
+func (s *Service) process(ctx context.Context, t trigger.WorkflowTrigger, mailbox Mailbox) error {
+	uids, err := mailbox.Unread()
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range uids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		raw, err := mailbox.Fetch(uid)
+		if err != nil {
+			return err
+		}
+		if !s.handle(ctx, t, uid, raw) {
+			continue
+		}
+		if err := mailbox.MarkProcessed(uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle delivers an email to its workflow. It reports whether the email
+// is done with and can be marked as processed.
+func (s *Service) handle(ctx context.Context, t trigger.WorkflowTrigger, uid uint32, raw []byte) bool {
+	logger := s.logger.With(
+		slog.String("workflow", t.WorkflowName),
+		slog.String("trigger", t.TriggerID()),
+		slog.Uint64("uid", uint64(uid)))
+
+	email, err := ParseEmail(raw)
+	if err != nil {
+		// Retrying cannot fix a malformed email, so it is left for a person
+		logger.Warn("skipping email that could not be parsed", slog.String("error", err.Error()))
+		return true
+	}
+	logger = logger.With(slog.String("message_id", email.MessageID))
+
+	if err := s.saveAttachments(email); err != nil {
+		logger.Warn("failed to save email attachments", slog.String("error", err.Error()))
+		return false
+	}
+
+	payload, _ := json.Marshal(email.Fields())
+	event := &backend.TriggerEvent{
+		Source:         trigger.SourceIMAP,
+		Trigger:        t.EventName(),
+		Workflow:       trigger.WorkflowName(s.workflowsDir, t.WorkflowPath),
+		Event:          "email",
+		IdempotencyKey: email.MessageID,
+		Headers:        trigger.RecordedMessageHeaders(email.Headers),
+		Payload:        payload,
+		Inputs:         emailInputs(t, email),
+	}
+
+	_, duplicate, err := s.events.Deliver(ctx, event)
+	if err != nil {
+		logger.Warn("failed to deliver email, retrying on the next check", slog.String("error", err.Error()))
+		return false
+	}
+	if duplicate {
+		logger.Debug("email already delivered")
+	}
+	return true
+}
+
+// saveAttachments saves an email's attachments under the temp directory
+// and sets their $temp/ paths.
+func (s *Service) saveAttachments(email *Email) error {
+	if s.tempDir == "" || len(email.Attachments) == 0 {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(email.MessageID))
+	rel := filepath.Join(attachmentsDir, hex.EncodeToString(sum[:8]))
+	dir := filepath.Join(s.tempDir, rel)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create attachments directory: %w", err)
+	}
+
+	used := make(map[string]bool, len(email.Attachments))
+	for i, a := range email.Attachments {
+		name := a.Filename
+		if used[name] {
+			name = fmt.Sprintf("%d-%s", i+1, name)
+		}
+		used[name] = true
+
+		if err := os.WriteFile(filepath.Join(dir, name), a.Data, 0o600); err != nil {
+			return fmt.Errorf("failed to save attachment %s: %w", name, err)
+		}
+		a.Path = "$temp/" + filepath.ToSlash(filepath.Join(rel, name))
+	}
+	return nil
+}
+
+// pruneAttachments removes the attachments of emails older than the
+// retention period, at most once per pruneInterval.
+func (s *Service) pruneAttachments() {
+	if s.tempDir == "" {
+		return
+	}
+
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	s.mu.Unlock()
+
+	dir := filepath.Join(s.tempDir, attachmentsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-s.retention)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			s.logger.Warn("failed to remove old email attachments",
+				slog.String("path", entry.Name()),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// emailInputs maps an email to the inputs of its run. Mapping paths start
+// at the email: $.subject, $.from, $.text, $.attachments.0.path and so on.
+func emailInputs(t trigger.WorkflowTrigger, email *Email) map[string]any {
+	inputs := trigger.MapInputs(email.Fields(), t.IMAP.InputMapping, t.IMAP.Inputs)
+	inputs["trigger"] = map[string]any{
+		"type":       string(workflow.TriggerTypeIMAP),
+		"id":         t.TriggerID(),
+		"folder":     t.IMAP.FolderName(),
+		"message_id": email.MessageID,
+	}
+	return inputs
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imaptrigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// fakeEvents delivers events like the event log, deduplicating by
+// idempotency key.
+type fakeEvents struct {
+	mu         sync.Mutex
+	deliverErr error
+	events     map[string]*backend.TriggerEvent // By idempotency key
+	delivered  []*backend.TriggerEvent
+}
+
+func newFakeEvents() *fakeEvents {
+	return &fakeEvents{events: make(map[string]*backend.TriggerEvent)}
+}
+
+func (f *fakeEvents) Deliver(_ context.Context, event *backend.TriggerEvent) (*backend.TriggerEvent, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if prev, ok := f.events[event.IdempotencyKey]; ok && prev.Status != backend.TriggerEventFailed {
+		return prev, true, nil
+	}
+	f.events[event.IdempotencyKey] = event
+	if f.deliverErr != nil {
+		event.Status = backend.TriggerEventFailed
+		return event, false, f.deliverErr
+	}
+	event.Status = backend.TriggerEventDelivered
+	f.delivered = append(f.delivered, event)
+	return event, false, nil
+}
+
+func (f *fakeEvents) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.delivered)
+}
+
+// fakeMailbox holds raw emails by UID.
+type fakeMailbox struct {
+	mu        sync.Mutex
+	emails    map[uint32][]byte
+	processed []uint32
+}
+
+func newFakeMailbox(emails ...string) *fakeMailbox {
+	m := &fakeMailbox{emails: make(map[uint32][]byte)}
+	for i, e := range emails {
+		m.emails[uint32(i+1)] = crlf(e)
+	}
+	return m
+}
+
+func (m *fakeMailbox) Unread() ([]uint32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var uids []uint32
+	for uid := uint32(1); uid <= uint32(len(m.emails)); uid++ {
+		if !m.isProcessed(uid) {
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
+
+func (m *fakeMailbox) Fetch(uid uint32) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.emails[uid], nil
+}
+
+func (m *fakeMailbox) MarkProcessed(uid uint32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.processed = append(m.processed, uid)
+	return nil
+}
+
+func (m *fakeMailbox) Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (m *fakeMailbox) Close() error { return nil }
+
+func (m *fakeMailbox) isProcessed(uid uint32) bool {
+	for _, p := range m.processed {
+		if p == uid {
+			return true
+		}
+	}
+	return false
+}
+
+const reportEmail = `
+From: reports@vendor.example
+To: ops@example.com
+Subject: Daily report
+Message-ID: <daily-1@vendor.example>
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="b"
+
+--b
+Content-Type: text/plain
+
+See attached.
+--b
+Content-Type: text/csv
+Content-Disposition: attachment; filename="report.csv"
+
+sku,qty
+--b--
+`
+
+func testTrigger(cfg *workflow.IMAPTriggerConfig) trigger.WorkflowTrigger {
+	return trigger.WorkflowTrigger{
+		WorkflowPath: filepath.Join("/workflows", "reports.yaml"),
+		WorkflowName: "reports",
+		Type:         workflow.TriggerTypeIMAP,
+		IMAP:         cfg,
+	}
+}
+
+func TestService_Process(t *testing.T) {
+	events := newFakeEvents()
+	tempDir := t.TempDir()
+	s := NewService(Config{Events: events, WorkflowsDir: "/workflows", TempDir: tempDir})
+	mailbox := newFakeMailbox(reportEmail)
+	tr := testTrigger(&workflow.IMAPTriggerConfig{
+		Host: "imap.example.com",
+		InputMapping: map[string]string{
+			"report": "$.attachments.0.path",
+			"sender": "$.from",
+		},
+		Inputs: map[string]any{"team": "ops"},
+	})
+
+	if err := s.process(context.Background(), tr, mailbox); err != nil {
+		t.Fatalf("process() error = %v", err)
+	}
+
+	if len(events.delivered) != 1 {
+		t.Fatalf("delivered %d events, want 1", len(events.delivered))
+	}
+	event := events.delivered[0]
+	if event.Source != trigger.SourceIMAP || event.Trigger != "imap:reports" || event.Workflow != "reports" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.IdempotencyKey != "daily-1@vendor.example" {
+		t.Errorf("IdempotencyKey = %q", event.IdempotencyKey)
+	}
+
+	path, _ := event.Inputs["report"].(string)
+	if !strings.HasPrefix(path, "$temp/email/") || filepath.Base(path) != "report.csv" {
+		t.Fatalf("report input = %q, want a $temp/ path", path)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, strings.TrimPrefix(path, "$temp/")))
+	if err != nil || string(data) != "sku,qty" {
+		t.Errorf("saved attachment = %q, %v", data, err)
+	}
+	if event.Inputs["sender"] != "reports@vendor.example" || event.Inputs["team"] != "ops" {
+		t.Errorf("inputs = %v", event.Inputs)
+	}
+	meta := event.Inputs["trigger"].(map[string]any)
+	if meta["type"] != "imap" || meta["folder"] != "INBOX" || meta["message_id"] != "daily-1@vendor.example" {
+		t.Errorf("trigger input = %v", meta)
+	}
+
+	if len(mailbox.processed) != 1 {
+		t.Errorf("processed = %v, want the email marked", mailbox.processed)
+	}
+}
+
+func TestService_UnmappedFields(t *testing.T) {
+	events := newFakeEvents()
+	s := NewService(Config{Events: events})
+	tr := testTrigger(&workflow.IMAPTriggerConfig{Host: "imap.example.com"})
+	tr.ID = "vendor"
+
+	if err := s.process(context.Background(), tr, newFakeMailbox(reportEmail)); err != nil {
+		t.Fatalf("process() error = %v", err)
+	}
+
+	inputs := events.delivered[0].Inputs
+	if inputs["subject"] != "Daily report" || inputs["text"] != "See attached." {
+		t.Errorf("inputs = %v", inputs)
+	}
+	// Without a temp directory attachments are not saved
+	attachment := inputs["attachments"].([]any)[0].(map[string]any)
+	if attachment["filename"] != "report.csv" || attachment["path"] != "" {
+		t.Errorf("attachment = %v", attachment)
+	}
+	if events.delivered[0].Trigger != "imap:reports:vendor" {
+		t.Errorf("Trigger = %q", events.delivered[0].Trigger)
+	}
+}
+
+func TestService_DeliveryFailure(t *testing.T) {
+	events := newFakeEvents()
+	events.deliverErr = errors.New("workflow not found")
+	s := NewService(Config{Events: events})
+	mailbox := newFakeMailbox(reportEmail)
+	tr := testTrigger(&workflow.IMAPTriggerConfig{Host: "imap.example.com"})
+
+	if err := s.process(context.Background(), tr, mailbox); err != nil {
+		t.Fatalf("process() error = %v", err)
+	}
+	if len(mailbox.processed) != 0 {
+		t.Fatalf("email marked processed after failed delivery")
+	}
+
+	// The next check retries the email, and a duplicate is only marked
+	events.deliverErr = nil
+	for range 2 {
+		if err := s.process(context.Background(), tr, mailbox); err != nil {
+			t.Fatalf("process() error = %v", err)
+		}
+		mailbox.processed = nil
+	}
+	if len(events.delivered) != 1 {
+		t.Errorf("delivered %d times, want 1", len(events.delivered))
+	}
+}
+
+func TestService_Malformed(t *testing.T) {
+	events := newFakeEvents()
+	s := NewService(Config{Events: events})
+	mailbox := newFakeMailbox("\nContent-Type: multipart/mixed\n\nno boundary\n")
+	tr := testTrigger(&workflow.IMAPTriggerConfig{Host: "imap.example.com"})
+
+	if err := s.process(context.Background(), tr, mailbox); err != nil {
+		t.Fatalf("process() error = %v", err)
+	}
+	if len(events.delivered) != 0 || len(mailbox.processed) != 1 {
+		t.Errorf("delivered %d, processed %v; want the email skipped", len(events.delivered), mailbox.processed)
+	}
+}
+
+func TestService_StartStop(t *testing.T) {
+	events := newFakeEvents()
+	mailbox := newFakeMailbox(reportEmail)
+	var dials int
+	s := NewService(Config{
+		Events: events,
+		Dial: func(cfg *workflow.IMAPTriggerConfig) (Mailbox, error) {
+			dials++
+			if cfg.Host != "imap.example.com" {
+				return nil, fmt.Errorf("unexpected host %s", cfg.Host)
+			}
+			return mailbox, nil
+		},
+	})
+	s.Register([]trigger.WorkflowTrigger{
+		testTrigger(&workflow.IMAPTriggerConfig{Host: "imap.example.com"}),
+		{WorkflowName: "other", Type: workflow.TriggerTypeQueue},
+	})
+	if s.Count() != 1 {
+		t.Fatalf("Count() = %d, want 1", s.Count())
+	}
+
+	s.Start(context.Background())
+	s.Start(context.Background())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for events.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Stop()
+	s.Stop()
+
+	if events.count() != 1 || dials != 1 {
+		t.Errorf("delivered %d, dials %d; want one of each", events.count(), dials)
+	}
+}
+
+func TestService_PruneAttachments(t *testing.T) {
+	tempDir := t.TempDir()
+	s := NewService(Config{TempDir: tempDir, Retention: time.Hour})
+
+	old := filepath.Join(tempDir, attachmentsDir, "old")
+	recent := filepath.Join(tempDir, attachmentsDir, "recent")
+	for _, dir := range []string{old, recent} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	s.pruneAttachments()
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("old attachments not removed: %v", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent attachments removed: %v", err)
+	}
+}
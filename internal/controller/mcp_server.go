@@ -0,0 +1,185 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/endpoint"
+	"github.com/tombee/conductor/internal/controller/runner"
+	mcpserver "github.com/tombee/conductor/internal/mcp/server"
+)
+
+// defaultMCPServerPath is the HTTP path of the controller MCP server.
+const defaultMCPServerPath = "/mcp"
+
+// registerMCPServer mounts an MCP server exposing workflows or endpoints as tools.
+func (c *Controller) registerMCPServer(mux *http.ServeMux) error {
+	cfg := c.cfg.Controller.MCPServer
+
+	var registry *endpoint.Registry
+	var tools []mcpserver.WorkflowTool
+	var err error
+	if cfg.Expose == "endpoints" {
+		if c.endpointHandler == nil {
+			return fmt.Errorf("endpoints are not enabled")
+		}
+		registry = c.endpointHandler.Registry()
+		tools, err = mcpserver.EndpointWorkflowTools(registry, c.cfg.Controller.WorkflowsDir)
+	} else {
+		tools, err = mcpserver.LoadWorkflowTools(c.cfg.Controller.WorkflowsDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	srv, err := mcpserver.NewServer(mcpserver.ServerConfig{
+		Name:              "conductor",
+		Version:           c.opts.Version,
+		LogLevel:          "warn",
+		Workflows:         tools,
+		Runs:              &runnerRunBackend{runner: c.runner, endpoints: registry},
+		WorkflowToolsOnly: true,
+	})
+	if err != nil {
+		return err
+	}
+	c.mcpServer = srv
+
+	path := cfg.Path
+	if path == "" {
+		path = defaultMCPServerPath
+	}
+	mux.Handle(path, srv.HTTPHandler(path))
+
+	c.logger.Info("MCP server enabled",
+		slog.String("path", path),
+		slog.Int("tools", len(tools)))
+	return nil
+}
+
+// runnerRunBackend runs the workflow tools of the controller MCP server on the runner.
+type runnerRunBackend struct {
+	runner    *runner.Runner
+	endpoints *endpoint.Registry
+}
+
+// StartRun submits a workflow run if the caller may run the workflow.
+// Endpoint tools apply the endpoint's scopes and timeout.
+func (b *runnerRunBackend) StartRun(ctx context.Context, tool mcpserver.WorkflowTool, inputs map[string]any) (*mcpserver.RunInfo, error) {
+	if b.runner.IsDraining() {
+		return nil, fmt.Errorf("controller is shutting down gracefully")
+	}
+
+	var timeout time.Duration
+	if tool.Endpoint != "" {
+		ep := b.endpoints.Get(tool.Endpoint)
+		if ep == nil {
+			return nil, fmt.Errorf("endpoint %q not found", tool.Endpoint)
+		}
+
+		user, _ := auth.UserFromContext(ctx)
+		var userScopes []string
+		if user != nil {
+			userScopes = user.Scopes
+		}
+		if !ep.Public && !auth.MatchesScope(userScopes, ep.Name) {
+			// Same response as the endpoint API to avoid information disclosure
+			return nil, fmt.Errorf("endpoint %q not found", tool.Endpoint)
+		}
+		timeout = ep.Timeout
+	}
+
+	workflowYAML, err := os.ReadFile(tool.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file: %w", err)
+	}
+
+	run, err := b.runner.Submit(ctx, runner.SubmitRequest{
+		WorkflowYAML: workflowYAML,
+		Inputs:       inputs,
+		WorkflowDir:  filepath.Dir(tool.Path),
+		Timeout:      timeout,
+		Authorize: func(workflow, workspace string) error {
+			return auth.Authorize(ctx, auth.ActionRun, auth.Resource{Workflow: workflow, Workspace: workspace})
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toMCPRunInfo(run), nil
+}
+
+// ToolAllowed reports whether the caller may run a tool's workflow, so
+// tools/list only shows the tools the caller can call.
+func (b *runnerRunBackend) ToolAllowed(ctx context.Context, tool mcpserver.WorkflowTool) bool {
+	return auth.Allowed(ctx, auth.ActionRun, auth.Resource{Workflow: tool.Definition.Name, Precheck: true})
+}
+
+// GetRun returns the current state of a run.
+func (b *runnerRunBackend) GetRun(ctx context.Context, id string) (*mcpserver.RunInfo, error) {
+	run, err := b.runner.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return toMCPRunInfo(run), nil
+}
+
+// AuthorizeRun checks the caller may read a run resource: its status needs
+// read access to the run's workflow, and its logs and outputs need access
+// to outputs.
+func (b *runnerRunBackend) AuthorizeRun(ctx context.Context, info *mcpserver.RunInfo, outputs bool) error {
+	run, err := b.runner.Get(info.ID)
+	if err != nil {
+		return err
+	}
+	action := auth.ActionRead
+	if outputs {
+		action = auth.ActionReadOutputs
+	}
+	return auth.Authorize(ctx, action, auth.Resource{Workflow: run.Workflow, Workspace: run.Workspace})
+}
+
+// toMCPRunInfo converts a run snapshot for the MCP server.
+func toMCPRunInfo(run *runner.RunSnapshot) *mcpserver.RunInfo {
+	info := &mcpserver.RunInfo{
+		ID:          run.ID,
+		Workflow:    run.Workflow,
+		Status:      string(run.Status),
+		Error:       run.Error,
+		Output:      run.Output,
+		StartedAt:   run.StartedAt,
+		CompletedAt: run.CompletedAt,
+	}
+	for _, entry := range run.Logs {
+		info.Logs = append(info.Logs, mcpserver.RunLog{
+			Timestamp: entry.Timestamp,
+			Type:      entry.Type,
+			Level:     entry.Level,
+			Message:   entry.Message,
+			StepID:    entry.StepID,
+			Status:    entry.Status,
+			Error:     entry.Error,
+		})
+	}
+	return info
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/internal/controller/checkpoint"
+	"github.com/tombee/conductor/internal/controller/runner"
+	mcpserver "github.com/tombee/conductor/internal/mcp/server"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+func TestRunnerRunBackend_Authorization(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cleanup.yaml")
+	workflowYAML := "name: cleanup\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n"
+	if err := os.WriteFile(path, []byte(workflowYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	def, err := workflow.ParseDefinition([]byte(workflowYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool := mcpserver.WorkflowTool{Name: "cleanup", Definition: def, Path: path}
+
+	cm, err := checkpoint.NewManager(checkpoint.ManagerConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &runnerRunBackend{runner: runner.New(runner.Config{MaxParallel: 1, DefaultTimeout: 30 * time.Second}, memory.New(), cm)}
+
+	// The backend is called from the MCP handler, behind the auth middleware
+	var allowed bool
+	var startErr error
+	handler := auth.NewMiddleware(auth.Config{
+		Enabled: true,
+		APIKeys: []auth.APIKey{
+			{Key: "viewer-key", Name: "viewer", Roles: []string{auth.RoleViewer}},
+			{Key: "operator-key", Name: "operator", Roles: []string{auth.RoleOperator}},
+		},
+		Authorizer: auth.NewAuthorizer(auth.AuthorizerConfig{}),
+	}).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed = b.ToolAllowed(r.Context(), tool)
+		_, startErr = b.StartRun(r.Context(), tool, nil)
+	}))
+	call := func(key string) {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.Header.Set("X-API-Key", key)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	call("viewer-key")
+	var forbidden *auth.ForbiddenError
+	if allowed || !errors.As(startErr, &forbidden) {
+		t.Errorf("viewer: ToolAllowed = %v, StartRun error = %v; want hidden and forbidden", allowed, startErr)
+	}
+
+	call("operator-key")
+	if !allowed || startErr != nil {
+		t.Errorf("operator: ToolAllowed = %v, StartRun error = %v; want visible and started", allowed, startErr)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tombee/conductor/internal/config"
+	"github.com/tombee/conductor/internal/integration"
+	"github.com/tombee/conductor/internal/operation"
+	"github.com/tombee/conductor/internal/operation/api"
+	"github.com/tombee/conductor/internal/operation/transport"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// Headers set on webhook deliveries.
+const (
+	// SignatureHeader carries "sha256=<hex>", the HMAC-SHA256 of the body
+	// keyed with the channel secret. It matches what generic webhook
+	// triggers verify, so one controller can notify another.
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Conductor-Event"
+	DeliveryHeader  = "X-Conductor-Delivery"
+)
+
+// Channel delivers messages to one destination.
+type Channel interface {
+	// Name identifies the destination in logs and dead letters, e.g. "slack:#ops"
+	Name() string
+
+	// Send delivers a message. Errors are retried.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// sendTimeout bounds a single delivery attempt.
+const sendTimeout = 30 * time.Second
+
+// newChannel creates the channel for a notify block entry. Its settings
+// come from the workflow, so $secret:key references are resolved through
+// the secrets provider; environment references are not expanded.
+func (s *Service) newChannel(ctx context.Context, cfg workflow.NotifyChannel) (Channel, error) {
+	settings := []*string{&cfg.URL, &cfg.Secret, &cfg.Token, &cfg.Channel}
+	for _, setting := range settings {
+		value, err := s.resolveSecret(ctx, *setting)
+		if err != nil {
+			return nil, err
+		}
+		*setting = value
+	}
+
+	switch cfg.Type {
+	case workflow.NotifyChannelWebhook:
+		return &webhookChannel{client: s.httpClient, url: cfg.URL, secret: cfg.Secret}, nil
+	case workflow.NotifyChannelSlack:
+		return newIntegrationChannel("slack", cfg.URL, cfg.Token, cfg.Channel)
+	case workflow.NotifyChannelDiscord:
+		return newIntegrationChannel("discord", cfg.URL, cfg.Token, cfg.Channel)
+	case workflow.NotifyChannelEmail:
+		if s.cfg.SMTP.Host == "" || s.cfg.SMTP.From == "" {
+			return nil, fmt.Errorf("email channels need controller.notifications.smtp host and from")
+		}
+		return &emailChannel{smtp: s.cfg.SMTP, to: cfg.To}, nil
+	default:
+		return nil, fmt.Errorf("unsupported channel type: %q", cfg.Type)
+	}
+}
+
+// webhookChannel POSTs the message as JSON, signed when a secret is set.
+type webhookChannel struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func (c *webhookChannel) Name() string {
+	return "webhook:" + c.url
+}
+
+func (c *webhookChannel) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, msg.Event)
+	req.Header.Set(DeliveryHeader, msg.ID)
+	if c.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(c.secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+// Sign returns the signature header value for a webhook body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// integrationBaseURLs are the API base URLs of the chat integrations.
+var integrationBaseURLs = map[string]string{
+	"slack":   "https://slack.com/api",
+	"discord": "https://discord.com/api/v10",
+}
+
+// integrationChannel posts the message text through the built-in Slack or
+// Discord integration.
+type integrationChannel struct {
+	name     string
+	channel  string
+	provider operation.Provider
+}
+
+func newIntegrationChannel(name, baseURL, token, channel string) (Channel, error) {
+	factory, ok := integration.BuiltinRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("integration %s is not available", name)
+	}
+	if baseURL == "" {
+		baseURL = integrationBaseURLs[name]
+	}
+
+	// Deliveries are retried by the service, not the transport
+	httpTransport, err := transport.NewHTTPTransport(&transport.HTTPTransportConfig{
+		BaseURL:     baseURL,
+		Timeout:     sendTimeout,
+		RetryConfig: &transport.RetryConfig{MaxAttempts: 1, BackoffFactor: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	provider, err := factory(&api.ProviderConfig{
+		Transport: httpTransport,
+		BaseURL:   baseURL,
+		Token:     token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &integrationChannel{name: name, channel: channel, provider: provider}, nil
+}
+
+func (c *integrationChannel) Name() string {
+	return c.name + ":" + c.channel
+}
+
+func (c *integrationChannel) Send(ctx context.Context, msg *Message) error {
+	var err error
+	switch c.name {
+	case "slack":
+		_, err = c.provider.Execute(ctx, "post_message", map[string]interface{}{
+			"channel": c.channel,
+			"text":    msg.Text,
+		})
+	case "discord":
+		_, err = c.provider.Execute(ctx, "send_message", map[string]interface{}{
+			"channel_id": c.channel,
+			"content":    msg.Text,
+		})
+	}
+	return err
+}
+
+// emailChannel sends the message text by SMTP.
+type emailChannel struct {
+	smtp config.SMTPConfig
+	to   []string
+}
+
+func (c *emailChannel) Name() string {
+	return "email:" + strings.Join(c.to, ",")
+}
+
+func (c *emailChannel) Send(ctx context.Context, msg *Message) error {
+	port := c.smtp.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(c.smtp.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if c.smtp.Username != "" {
+		auth = smtp.PlainAuth("", c.smtp.Username, c.smtp.Password, c.smtp.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", c.smtp.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(c.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerSafe(msg.subject()))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text, "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	// net/smtp has no context support; a send outliving ctx finishes in the background
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, c.smtp.From, c.to, []byte(b.String()))
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// headerSafe strips line breaks so values cannot add mail headers.
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
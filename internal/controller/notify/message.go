@@ -0,0 +1,123 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// DefaultTemplate renders messages for channels without a template.
+const DefaultTemplate = `{{.Workflow}} run {{.RunID}} {{.Summary}}` +
+	`{{if .FailedStep}} at step {{.FailedStep}}{{end}}` +
+	`{{if .Error}}: {{.Error}}{{end}}` +
+	`{{if .Detail}}: {{.Detail}}{{end}}` +
+	` (cost ${{printf "%.4f" .CostUSD}}{{if .Duration}}, {{.Duration}}{{end}})`
+
+// Message is a run notification. It is the JSON body sent to webhooks and
+// the data available to message templates.
+type Message struct {
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	RunID      string    `json:"run_id"`
+	Workflow   string    `json:"workflow"`
+	Workspace  string    `json:"workspace,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	FailedStep string    `json:"failed_step,omitempty"`
+	Completed  int       `json:"completed_steps"`
+	Total      int       `json:"total_steps"`
+	CostUSD    float64   `json:"cost_usd"`
+	BudgetUSD  float64   `json:"budget_usd,omitempty"`
+	Duration   string    `json:"duration,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	Time       time.Time `json:"time"`
+
+	// Summary describes the event in a few words, e.g. "is awaiting approval"
+	Summary string `json:"summary"`
+
+	// Text is the rendered message
+	Text string `json:"text"`
+}
+
+// eventSummaries describe each event for the default template.
+var eventSummaries = map[string]string{
+	workflow.NotifyEventStarted:          "started",
+	workflow.NotifyEventSucceeded:        "succeeded",
+	workflow.NotifyEventFailed:           "failed",
+	workflow.NotifyEventAwaitingApproval: "is awaiting approval",
+	workflow.NotifyEventBudgetExceeded:   "exceeded its budget",
+}
+
+// newMessage builds the message for a run event, without its text.
+func newMessage(id string, event runner.RunEvent) *Message {
+	run := event.Run
+	msg := &Message{
+		ID:         id,
+		Event:      event.Type,
+		RunID:      run.ID,
+		Workflow:   run.Workflow,
+		Workspace:  run.Workspace,
+		Status:     string(run.Status),
+		Error:      run.Error,
+		FailedStep: run.FailedStep,
+		CostUSD:    run.CostUSD,
+		Detail:     event.Message,
+		Time:       event.Time,
+		Summary:    eventSummaries[event.Type],
+	}
+	if event.Notify != nil {
+		msg.BudgetUSD = event.Notify.BudgetUSD
+	}
+	if run.Progress != nil {
+		msg.Completed = run.Progress.Completed
+		msg.Total = run.Progress.Total
+	}
+	if run.StartedAt != nil {
+		end := event.Time
+		if run.CompletedAt != nil {
+			end = *run.CompletedAt
+		}
+		msg.Duration = end.Sub(*run.StartedAt).Round(time.Second).String()
+	}
+	return msg
+}
+
+// render returns the message text for a template, falling back to the
+// default template.
+func render(text string, msg *Message) (string, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("notify").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, msg); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// subject returns a short title for email subjects.
+func (m *Message) subject() string {
+	return fmt.Sprintf("[conductor] %s %s", m.Workflow, m.Summary)
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify delivers run lifecycle notifications to webhooks, Slack,
+// Discord and email. Failed deliveries are retried with exponential backoff
+// and recorded in the backend as dead letters once every attempt has failed.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tombee/conductor/internal/config"
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/httpclient"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+const (
+	// DefaultMaxAttempts is the number of delivery attempts per notification.
+	DefaultMaxAttempts = 5
+
+	// DefaultRetryBackoff is the delay before the first retry.
+	DefaultRetryBackoff = 5 * time.Second
+
+	// maxConcurrentSends limits deliveries in flight across all channels.
+	maxConcurrentSends = 16
+)
+
+// Config configures the notification service.
+type Config struct {
+	// Backend records dead letters if it implements backend.NotificationStore.
+	Backend backend.Backend
+
+	// SMTP configures the mail server for email channels. ${VAR}
+	// references in its credentials are expanded from the environment.
+	SMTP config.SMTPConfig
+
+	// ResolveSecret resolves $secret:key references in workflow channel
+	// settings. Default: config.ResolveSecretReference
+	ResolveSecret func(ctx context.Context, value string) (string, error)
+
+	// MaxAttempts is the number of delivery attempts. Default: 5
+	MaxAttempts int
+
+	// RetryBackoff is the delay before the first retry, doubled after each
+	// failed attempt. Default: 5s
+	RetryBackoff time.Duration
+
+	// HTTPClient sends webhook deliveries. Default: a client with a 30s timeout.
+	HTTPClient *http.Client
+
+	Logger *slog.Logger
+}
+
+// Service delivers run events to the channels in their notify block.
+// It implements runner.RunNotifier.
+type Service struct {
+	cfg         Config
+	logger      *slog.Logger
+	httpClient  *http.Client
+	deadLetters backend.NotificationStore
+
+	sends  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ runner.RunNotifier = (*Service)(nil)
+
+// New creates a notification service.
+func New(cfg Config) *Service {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.ResolveSecret == nil {
+		cfg.ResolveSecret = config.ResolveSecretReference
+	}
+	cfg.SMTP.Username = expandEnv(cfg.SMTP.Username)
+	cfg.SMTP.Password = expandEnv(cfg.SMTP.Password)
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		clientCfg := httpclient.DefaultConfig()
+		clientCfg.Timeout = sendTimeout
+		clientCfg.RetryAttempts = 0
+		clientCfg.UserAgent = "conductor-notify/1.0"
+		var err error
+		if httpClient, err = httpclient.New(clientCfg); err != nil {
+			httpClient = &http.Client{Timeout: sendTimeout}
+		}
+	}
+
+	s := &Service{
+		cfg:        cfg,
+		logger:     cfg.Logger,
+		httpClient: httpClient,
+		sends:      make(chan struct{}, maxConcurrentSends),
+	}
+	if store, ok := cfg.Backend.(backend.NotificationStore); ok {
+		s.deadLetters = store
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// NotifyRun renders the event for each channel that wants it and delivers
+// it in the background.
+func (s *Service) NotifyRun(event runner.RunEvent) {
+	if event.Run == nil || event.Notify == nil {
+		return
+	}
+
+	for _, chCfg := range event.Notify.Channels {
+		if !chCfg.Wants(event.Type) {
+			continue
+		}
+
+		msg := newMessage(uuid.NewString(), event)
+		tmpl := chCfg.Template
+		if tmpl == "" {
+			tmpl = event.Notify.Template
+		}
+		text, err := render(tmpl, msg)
+		if err != nil {
+			s.logger.Warn("notification template failed, using the default",
+				slog.String("workflow", msg.Workflow),
+				slog.String("error", err.Error()))
+			text, _ = render("", msg)
+		}
+		msg.Text = text
+
+		ch, err := s.newChannel(s.ctx, chCfg)
+		if err != nil {
+			s.deadLetter(msg, chCfg.Type, err, 0)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.deliver(ch, msg)
+	}
+}
+
+// deliver sends a message, retrying with exponential backoff, and records
+// a dead letter if every attempt fails.
+func (s *Service) deliver(ch Channel, msg *Message) {
+	defer s.wg.Done()
+
+	backoff := s.cfg.RetryBackoff
+	var err error
+	attempts := 0
+	for attempts < s.cfg.MaxAttempts {
+		attempts++
+		if err = s.send(ch, msg); err == nil {
+			s.logger.Debug("notification delivered",
+				slog.String("run_id", msg.RunID),
+				slog.String("event", msg.Event),
+				slog.String("channel", ch.Name()),
+				slog.Int("attempts", attempts))
+			return
+		}
+		if attempts == s.cfg.MaxAttempts {
+			break
+		}
+
+		s.logger.Debug("notification delivery failed, retrying",
+			slog.String("run_id", msg.RunID),
+			slog.String("channel", ch.Name()),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()))
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			s.deadLetter(msg, ch.Name(), err, attempts)
+			return
+		}
+	}
+	s.deadLetter(msg, ch.Name(), err, attempts)
+}
+
+// send makes one delivery attempt.
+func (s *Service) send(ch Channel, msg *Message) error {
+	select {
+	case s.sends <- struct{}{}:
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+	defer func() { <-s.sends }()
+
+	ctx, cancel := context.WithTimeout(s.ctx, sendTimeout)
+	defer cancel()
+	return ch.Send(ctx, msg)
+}
+
+// deadLetter logs an undelivered notification and records it in the backend.
+func (s *Service) deadLetter(msg *Message, channel string, err error, attempts int) {
+	s.logger.Warn("notification not delivered",
+		slog.String("run_id", msg.RunID),
+		slog.String("workflow", msg.Workflow),
+		slog.String("event", msg.Event),
+		slog.String("channel", channel),
+		slog.Int("attempts", attempts),
+		slog.String("error", err.Error()))
+
+	if s.deadLetters == nil {
+		return
+	}
+	payload, _ := json.Marshal(msg)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if saveErr := s.deadLetters.SaveNotificationDeadLetter(ctx, &backend.NotificationDeadLetter{
+		ID:       msg.ID,
+		RunID:    msg.RunID,
+		Workflow: msg.Workflow,
+		Event:    msg.Event,
+		Channel:  channel,
+		Payload:  payload,
+		Error:    err.Error(),
+		Attempts: attempts,
+	}); saveErr != nil {
+		s.logger.Error("failed to record notification dead letter",
+			slog.String("run_id", msg.RunID),
+			slog.String("error", saveErr.Error()))
+	}
+}
+
+// Stop waits for deliveries in flight until ctx is done. Deliveries still
+// pending after that give up and are recorded as dead letters.
+func (s *Service) Stop(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+	}
+	s.cancel()
+}
+
+// ExpandDefaults returns the controller's default notify block with ${VAR}
+// references in its channel settings expanded from the environment. Only
+// the controller's own configuration is expanded; workflow notify blocks
+// use $secret:key references instead.
+func ExpandDefaults(defaults workflow.NotifyDefinition) workflow.NotifyDefinition {
+	channels := make([]workflow.NotifyChannel, len(defaults.Channels))
+	for i, ch := range defaults.Channels {
+		ch.URL = expandEnv(ch.URL)
+		ch.Secret = expandEnv(ch.Secret)
+		ch.Token = expandEnv(ch.Token)
+		ch.Channel = expandEnv(ch.Channel)
+		channels[i] = ch
+	}
+	defaults.Channels = channels
+	return defaults
+}
+
+// resolveSecret resolves a $secret:key reference in a workflow channel
+// setting. Other values are returned unchanged.
+func (s *Service) resolveSecret(ctx context.Context, value string) (string, error) {
+	if !strings.HasPrefix(value, "$secret:") {
+		return value, nil
+	}
+	return s.cfg.ResolveSecret(ctx, value)
+}
+
+// expandEnv expands ${VAR} references in a controller setting.
+func expandEnv(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return os.ExpandEnv(s)
+}
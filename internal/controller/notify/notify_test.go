@@ -0,0 +1,260 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+func testEvent(eventType string, notify *workflow.NotifyDefinition) runner.RunEvent {
+	started := time.Now().Add(-90 * time.Second)
+	return runner.RunEvent{
+		Type: eventType,
+		Run: &runner.RunSnapshot{
+			ID:         "run-1",
+			Workflow:   "deploy",
+			Status:     runner.RunStatusFailed,
+			Error:      "exit status 1",
+			FailedStep: "build",
+			CostUSD:    0.25,
+			StartedAt:  &started,
+		},
+		Notify: notify,
+		Time:   time.Now(),
+	}
+}
+
+func testSecrets(ctx context.Context, value string) (string, error) {
+	if value == "$secret:notify_secret" {
+		return "s3cret", nil
+	}
+	return "", fmt.Errorf("unknown secret %q", value)
+}
+
+func TestService_Webhook(t *testing.T) {
+	received := make(chan *Message, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get(SignatureHeader); got != Sign("s3cret", body) {
+			t.Errorf("signature = %q, want %q", got, Sign("s3cret", body))
+		}
+		if r.Header.Get(EventHeader) != workflow.NotifyEventFailed {
+			t.Errorf("%s = %q", EventHeader, r.Header.Get(EventHeader))
+		}
+		var msg Message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- &msg
+	}))
+	defer server.Close()
+
+	s := New(Config{Backend: memory.New(), ResolveSecret: testSecrets})
+	s.NotifyRun(testEvent(workflow.NotifyEventFailed, &workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{
+			{Type: workflow.NotifyChannelWebhook, URL: server.URL, Secret: "$secret:notify_secret"},
+			// Not subscribed to failed
+			{Type: workflow.NotifyChannelWebhook, URL: server.URL, On: []string{workflow.NotifyEventSucceeded}},
+		},
+	}))
+	s.Stop(context.Background())
+
+	msg := <-received
+	if msg.RunID != "run-1" || msg.FailedStep != "build" || msg.CostUSD != 0.25 || msg.Duration != "1m30s" {
+		t.Errorf("message = %+v", msg)
+	}
+	want := "deploy run run-1 failed at step build: exit status 1 (cost $0.2500, 1m30s)"
+	if msg.Text != want {
+		t.Errorf("Text = %q, want %q", msg.Text, want)
+	}
+	select {
+	case extra := <-received:
+		t.Errorf("unexpected delivery %+v", extra)
+	default:
+	}
+}
+
+func TestService_WorkflowSettingsNotExpanded(t *testing.T) {
+	t.Setenv("NOTIFY_TEST_SECRET", "s3cret")
+
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r.Header.Get(SignatureHeader)
+		if r.Header.Get(SignatureHeader) != Sign("${NOTIFY_TEST_SECRET}", body) {
+			t.Error("workflow secret was expanded from the environment")
+		}
+	}))
+	defer server.Close()
+
+	s := New(Config{Backend: memory.New(), ResolveSecret: testSecrets})
+	s.NotifyRun(testEvent(workflow.NotifyEventFailed, &workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{
+			{Type: workflow.NotifyChannelWebhook, URL: server.URL, Secret: "${NOTIFY_TEST_SECRET}"},
+		},
+	}))
+	s.Stop(context.Background())
+	<-received
+
+	defaults := ExpandDefaults(workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{{Type: workflow.NotifyChannelWebhook, Secret: "${NOTIFY_TEST_SECRET}"}},
+	})
+	if defaults.Channels[0].Secret != "s3cret" {
+		t.Errorf("default secret = %q, want it expanded", defaults.Channels[0].Secret)
+	}
+}
+
+func TestService_UnresolvedSecret(t *testing.T) {
+	be := memory.New()
+	s := New(Config{Backend: be, ResolveSecret: testSecrets})
+	s.NotifyRun(testEvent(workflow.NotifyEventFailed, &workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{
+			{Type: workflow.NotifyChannelWebhook, URL: "http://127.0.0.1:1", Secret: "$secret:missing"},
+		},
+	}))
+	s.Stop(context.Background())
+
+	letters, err := be.ListNotificationDeadLetters(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("ListNotificationDeadLetters() error = %v", err)
+	}
+	if len(letters) != 1 || !strings.Contains(letters[0].Error, "missing") {
+		t.Errorf("dead letters = %+v, want one for the unresolved secret", letters)
+	}
+}
+
+func TestService_RetryAndDeadLetter(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first channel recovers on its second attempt
+		if r.URL.Path == "/flaky" && attempts.Add(1) > 1 {
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	be := memory.New()
+	s := New(Config{Backend: be, MaxAttempts: 3, RetryBackoff: time.Millisecond})
+	s.NotifyRun(testEvent(workflow.NotifyEventFailed, &workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{
+			{Type: workflow.NotifyChannelWebhook, URL: server.URL + "/flaky"},
+			{Type: workflow.NotifyChannelWebhook, URL: server.URL + "/down"},
+		},
+	}))
+	s.Stop(context.Background())
+
+	letters, err := be.ListNotificationDeadLetters(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("ListNotificationDeadLetters() error = %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(letters))
+	}
+	letter := letters[0]
+	if letter.Channel != "webhook:"+server.URL+"/down" || letter.Attempts != 3 || letter.RunID != "run-1" {
+		t.Errorf("dead letter = %+v", letter)
+	}
+	if !strings.Contains(letter.Error, "503") {
+		t.Errorf("Error = %q, want the webhook status", letter.Error)
+	}
+	var msg Message
+	if err := json.Unmarshal(letter.Payload, &msg); err != nil || msg.Event != workflow.NotifyEventFailed {
+		t.Errorf("Payload = %s", letter.Payload)
+	}
+}
+
+func TestService_Slack(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" || r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("request = %s %s (auth %q)", r.Method, r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		received <- body
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "channel": "C123", "ts": "1.2"}`))
+	}))
+	defer server.Close()
+
+	s := New(Config{})
+	s.NotifyRun(testEvent(workflow.NotifyEventBudgetExceeded, &workflow.NotifyDefinition{
+		Template:  "{{.Workflow}} {{.Event}} at ${{.CostUSD}} of ${{.BudgetUSD}}",
+		BudgetUSD: 0.2,
+		Channels: []workflow.NotifyChannel{
+			{Type: workflow.NotifyChannelSlack, URL: server.URL, Token: "xoxb-test", Channel: "#ops"},
+		},
+	}))
+	s.Stop(context.Background())
+
+	body := <-received
+	if body["channel"] != "#ops" || body["text"] != "deploy budget_exceeded at $0.25 of $0.2" {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestService_StopRecordsPendingRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	be := memory.New()
+	s := New(Config{Backend: be, RetryBackoff: time.Hour})
+	s.NotifyRun(testEvent(workflow.NotifyEventFailed, &workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{{Type: workflow.NotifyChannelWebhook, URL: server.URL}},
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s.Stop(ctx)
+
+	letters, _ := be.ListNotificationDeadLetters(context.Background(), 0)
+	if len(letters) != 1 || letters[0].Attempts != 1 {
+		t.Errorf("dead letters = %+v, want one after the first attempt", letters)
+	}
+}
+
+func TestRender(t *testing.T) {
+	msg := newMessage("id", testEvent(workflow.NotifyEventAwaitingApproval, nil))
+	msg.Error, msg.FailedStep = "", ""
+	msg.Detail = "MCP server docs requests an LLM completion"
+
+	got, err := render("", msg)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "deploy run run-1 is awaiting approval: MCP server docs") {
+		t.Errorf("render() = %q", got)
+	}
+
+	if _, err := render("{{.Missing", msg); err == nil {
+		t.Error("render() accepted an invalid template")
+	}
+}
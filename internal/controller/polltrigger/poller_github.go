@@ -0,0 +1,443 @@
+package polltrigger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// githubMaxPages caps the pages fetched in one poll.
+const githubMaxPages = 10
+
+// errNotModified reports a 304 response to a conditional request.
+var errNotModified = errors.New("not modified")
+
+// githubFilters lists the query parameters passed through to the GitHub API
+// for each polled resource.
+var githubFilters = map[string][]string{
+	"issues":          {"state", "labels", "assignee", "creator", "mentioned", "milestone"},
+	"pulls":           {"state", "base", "head"},
+	"review_comments": {},
+	"workflow_runs":   {"status", "branch", "event", "actor"},
+	"releases":        {},
+}
+
+// githubPaths maps each polled resource to its repository API path.
+var githubPaths = map[string]string{
+	"issues":          "/issues",
+	"pulls":           "/pulls",
+	"review_comments": "/pulls/comments",
+	"workflow_runs":   "/actions/runs",
+	"releases":        "/releases",
+}
+
+// githubEventTypes names the events produced for each polled resource.
+var githubEventTypes = map[string]string{
+	"issues":          "issue",
+	"pulls":           "pull_request",
+	"review_comments": "review_comment",
+	"workflow_runs":   "workflow_run",
+	"releases":        "release",
+}
+
+// GitHubPoller implements polling for GitHub issues, pull requests, pull
+// request review comments, workflow runs and releases.
+type GitHubPoller struct {
+	apiToken string
+	baseURL  string
+	client   *http.Client
+}
+
+// NewGitHubPoller creates a new GitHub poller.
+// baseURL is the GitHub Enterprise Server URL (e.g., "https://github.example.com");
+// empty means github.com.
+func NewGitHubPoller(apiToken, baseURL string) *GitHubPoller {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	switch {
+	case baseURL == "":
+		baseURL = "https://api.github.com"
+	case baseURL != "https://api.github.com" && !strings.HasSuffix(baseURL, "/api/v3"):
+		// GitHub Enterprise Server serves the REST API under /api/v3
+		baseURL += "/api/v3"
+	}
+
+	return &GitHubPoller{
+		apiToken: apiToken,
+		baseURL:  baseURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name returns the integration name.
+func (p *GitHubPoller) Name() string {
+	return "github"
+}
+
+// Poll queries the GitHub API for items in a repository changed since the
+// last poll time.
+// Supports query parameters: repo (owner/name, required), resource (issues,
+// pulls, review_comments, workflow_runs, releases), and the filters in
+// githubFilters.
+//
+// The cursor holds the ETag of the previous response. It is sent back as
+// If-None-Match, so an unchanged repository answers 304 Not Modified, which
+// does not count against the GitHub rate limit. Later pages are followed
+// through the Link header until one reaches items from before the last
+// poll, up to githubMaxPages.
+func (p *GitHubPoller) Poll(ctx context.Context, state *PollState, query map[string]interface{}) ([]map[string]interface{}, string, error) {
+	resource := "issues"
+	if r, ok := query["resource"].(string); ok && r != "" {
+		resource = r
+	}
+	filters, ok := githubFilters[resource]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported github resource %q (use issues, pulls, review_comments, workflow_runs or releases)", resource)
+	}
+
+	repo := queryParamValue(query["repo"])
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" {
+		return nil, "", fmt.Errorf("github poll requires repo in owner/name form")
+	}
+
+	// Build query parameters
+	params := url.Values{}
+	for _, key := range filters {
+		if value := queryParamValue(query[key]); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	switch resource {
+	case "issues", "review_comments":
+		// Add timestamp filter (primary deduplication)
+		if !state.LastPollTime.IsZero() {
+			params.Set("since", state.LastPollTime.UTC().Format(time.RFC3339))
+		}
+		params.Set("sort", "updated")
+		params.Set("direction", "desc")
+	case "pulls":
+		// Pulls has no since filter, so sort by update time and filter locally
+		params.Set("sort", "updated")
+		params.Set("direction", "desc")
+	}
+	params.Set("per_page", "100")
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s%s?%s", p.baseURL, url.PathEscape(owner), url.PathEscape(name), githubPaths[resource], params.Encode())
+
+	eventType := githubEventTypes[resource]
+	events := []map[string]interface{}{}
+	var etag string
+	for page := 0; ; page++ {
+		// Only the first page is conditional; its ETag is the cursor
+		var ifNoneMatch string
+		if page == 0 {
+			ifNoneMatch = state.Cursor
+		}
+		items, next, pageETag, err := p.fetchPage(ctx, apiURL, resource, repo, ifNoneMatch)
+		if errors.Is(err, errNotModified) {
+			// Nothing changed since the previous response; keep its ETag
+			return nil, state.Cursor, nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if page == 0 {
+			etag = pageETag
+		}
+
+		caughtUp := false
+		for _, item := range items {
+			if !item.changedSince(state.LastPollTime) {
+				caughtUp = true
+				continue
+			}
+			// The issues endpoint also returns pull requests
+			if resource == "issues" && item.PullRequest != nil {
+				continue
+			}
+			events = append(events, p.itemToEvent(eventType, item))
+		}
+
+		// Items are newest first, so an item from before the last poll means
+		// later pages hold nothing new. The first poll reads one page.
+		if caughtUp || state.LastPollTime.IsZero() || next == "" || page+1 >= githubMaxPages {
+			break
+		}
+		apiURL = next
+	}
+
+	return events, etag, nil
+}
+
+// fetchPage requests one page of a GitHub list endpoint. It returns the
+// page's items, the URL of the next page from the Link header, and the
+// page's ETag. errNotModified is returned when ifNoneMatch still matches.
+func (p *GitHubPoller) fetchPage(ctx context.Context, apiURL, resource, repo, ifNoneMatch string) ([]githubItem, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	// Execute request
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", "", wrapAPIError(err, "github")
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, "", "", errNotModified
+	}
+	if resp.StatusCode == 401 {
+		return nil, "", "", fmt.Errorf("GitHub auth failed (401). Token may be expired or revoked")
+	}
+	if resp.StatusCode == 403 || resp.StatusCode == 429 {
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.StatusCode == 429 {
+			return nil, "", "", fmt.Errorf("GitHub rate limit exceeded (%d)", resp.StatusCode)
+		}
+		return nil, "", "", fmt.Errorf("GitHub access denied (403). Token may lack access to %s", repo)
+	}
+	if resp.StatusCode == 404 {
+		return nil, "", "", fmt.Errorf("GitHub repository not found (404)")
+	}
+	if resp.StatusCode >= 500 {
+		return nil, "", "", fmt.Errorf("GitHub API error (%d)", resp.StatusCode)
+	}
+	if resp.StatusCode != 200 {
+		return nil, "", "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	// Parse response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var items []githubItem
+	if resource == "workflow_runs" {
+		var runs githubWorkflowRuns
+		if err := json.Unmarshal(body, &runs); err != nil {
+			return nil, "", "", fmt.Errorf("failed to parse response: %w", err)
+		}
+		items = runs.WorkflowRuns
+	} else if err := json.Unmarshal(body, &items); err != nil {
+		return nil, "", "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return items, p.nextPageURL(resp.Header.Get("Link")), resp.Header.Get("ETag"), nil
+}
+
+// nextPageURL returns the rel="next" URL of a Link header, or "" if there
+// is none. Links to another host are ignored so the token is not sent there.
+func (p *GitHubPoller) nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) != `rel="next"` {
+				continue
+			}
+			next := strings.Trim(strings.TrimSpace(target), "<>")
+			nextURL, err := url.Parse(next)
+			if err != nil {
+				return ""
+			}
+			base, err := url.Parse(p.baseURL)
+			if err != nil || nextURL.Scheme != base.Scheme || nextURL.Host != base.Host {
+				return ""
+			}
+			return next
+		}
+	}
+	return ""
+}
+
+// itemToEvent converts a GitHub issue, pull request, review comment,
+// workflow run or release to a generic event map. The event ID includes the
+// update time, so each change to an item is a new event.
+func (p *GitHubPoller) itemToEvent(eventType string, item githubItem) map[string]interface{} {
+	event := map[string]interface{}{
+		"id":         fmt.Sprintf("%s-%d-%s", eventType, item.ID, item.changedAt()),
+		"type":       eventType,
+		"object_id":  item.ID,
+		"html_url":   item.HTMLURL,
+		"created_at": item.CreatedAt,
+		"updated_at": item.UpdatedAt,
+	}
+
+	if item.Number != 0 {
+		event["number"] = item.Number
+	}
+	if item.Title != "" {
+		event["title"] = item.Title
+	}
+	if item.Name != "" {
+		event["name"] = item.Name
+	}
+	if item.Body != "" {
+		event["body"] = item.Body
+	}
+	if item.State != "" {
+		event["state"] = item.State
+	}
+	if item.Draft {
+		event["draft"] = true
+	}
+	if item.MergedAt != "" {
+		event["merged_at"] = item.MergedAt
+	}
+	if item.Head != nil {
+		event["head_ref"] = item.Head.Ref
+		event["head_sha"] = item.Head.SHA
+	}
+	if item.Base != nil {
+		event["base_ref"] = item.Base.Ref
+	}
+	if item.Path != "" {
+		event["path"] = item.Path
+		event["commit_id"] = item.CommitID
+		event["pull_request_url"] = item.PullRequestURL
+	}
+	if item.RunNumber != 0 {
+		event["run_number"] = item.RunNumber
+		event["status"] = item.Status
+		event["conclusion"] = item.Conclusion
+		event["event"] = item.Event
+		event["head_branch"] = item.HeadBranch
+		event["head_sha"] = item.HeadSHA
+	}
+	if item.TagName != "" {
+		event["tag_name"] = item.TagName
+		event["prerelease"] = item.Prerelease
+		event["published_at"] = item.PublishedAt
+	}
+	if user := item.author(); user != nil {
+		event["user"] = map[string]interface{}{
+			"id":    user.ID,
+			"login": user.Login,
+		}
+	}
+	if len(item.Labels) > 0 {
+		labels := make([]string, 0, len(item.Labels))
+		for _, label := range item.Labels {
+			labels = append(labels, label.Name)
+		}
+		event["labels"] = labels
+	}
+
+	return event
+}
+
+// GitHub API response types
+
+// githubItem holds the fields of issues, pull requests, review comments,
+// workflow runs and releases.
+type githubItem struct {
+	ID             int64         `json:"id"`
+	Number         int64         `json:"number"`
+	Title          string        `json:"title"`
+	Name           string        `json:"name"`
+	Body           string        `json:"body"`
+	State          string        `json:"state"`
+	Draft          bool          `json:"draft"`
+	HTMLURL        string        `json:"html_url"`
+	User           *githubUser   `json:"user"`
+	Author         *githubUser   `json:"author"`
+	Actor          *githubUser   `json:"actor"`
+	Labels         []githubLabel `json:"labels"`
+	PullRequest    *githubLink   `json:"pull_request"`
+	MergedAt       string        `json:"merged_at"`
+	Head           *githubRef    `json:"head"`
+	Base           *githubRef    `json:"base"`
+	Path           string        `json:"path"`
+	CommitID       string        `json:"commit_id"`
+	PullRequestURL string        `json:"pull_request_url"`
+	RunNumber      int64         `json:"run_number"`
+	Status         string        `json:"status"`
+	Conclusion     string        `json:"conclusion"`
+	Event          string        `json:"event"`
+	HeadBranch     string        `json:"head_branch"`
+	HeadSHA        string        `json:"head_sha"`
+	TagName        string        `json:"tag_name"`
+	Prerelease     bool          `json:"prerelease"`
+	PublishedAt    string        `json:"published_at"`
+	CreatedAt      string        `json:"created_at"`
+	UpdatedAt      string        `json:"updated_at"`
+}
+
+// changedAt returns the time the item last changed. Releases have no
+// update time, so their publish time is used.
+func (i githubItem) changedAt() string {
+	if i.UpdatedAt != "" {
+		return i.UpdatedAt
+	}
+	return i.PublishedAt
+}
+
+// changedSince reports whether the item changed at or after since. Items
+// with no parseable change time, such as draft releases, are skipped once a
+// poll has run.
+func (i githubItem) changedSince(since time.Time) bool {
+	if since.IsZero() {
+		return true
+	}
+	changed, err := time.Parse(time.RFC3339, i.changedAt())
+	if err != nil {
+		return false
+	}
+	return !changed.Before(since)
+}
+
+// author returns the user who created the item.
+func (i githubItem) author() *githubUser {
+	switch {
+	case i.User != nil:
+		return i.User
+	case i.Author != nil:
+		return i.Author
+	default:
+		return i.Actor
+	}
+}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubLink struct {
+	URL string `json:"url"`
+}
+
+type githubRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type githubWorkflowRuns struct {
+	WorkflowRuns []githubItem `json:"workflow_runs"`
+}
@@ -0,0 +1,219 @@
+package polltrigger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGitHubPoller_Poll(t *testing.T) {
+	lastPoll := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/acme/api/issues" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer ghp_test" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		query := r.URL.Query()
+		if query.Get("labels") != "bug,urgent" || query.Get("since") != "2025-06-01T12:00:00Z" {
+			t.Errorf("query = %v", query)
+		}
+
+		w.Header().Set("ETag", `"abc123"`)
+		json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"id":         101,
+				"number":     7,
+				"title":      "Login broken",
+				"state":      "open",
+				"updated_at": "2025-06-01T12:05:00Z",
+				"user":       map[string]any{"id": 1, "login": "bob"},
+				"labels":     []map[string]any{{"name": "bug"}},
+			},
+			{
+				"id":           102,
+				"number":       8,
+				"title":        "Fix login",
+				"updated_at":   "2025-06-01T12:06:00Z",
+				"pull_request": map[string]any{"url": "https://example.com/pulls/8"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	poller := NewGitHubPoller("ghp_test", server.URL)
+	events, cursor, err := poller.Poll(context.Background(), &PollState{LastPollTime: lastPoll}, map[string]interface{}{
+		"repo":   "acme/api",
+		"labels": []interface{}{"bug", "urgent"},
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if cursor != `"abc123"` {
+		t.Errorf("cursor = %q, want ETag", cursor)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1 (pull requests skipped)", len(events))
+	}
+	event := events[0]
+	if event["id"] != "issue-101-2025-06-01T12:05:00Z" || event["number"] != int64(7) {
+		t.Errorf("event = %v", event)
+	}
+	if labels, _ := event["labels"].([]string); len(labels) != 1 || labels[0] != "bug" {
+		t.Errorf("labels = %v", event["labels"])
+	}
+}
+
+func TestGitHubPoller_PollPages(t *testing.T) {
+	lastPoll := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var server *httptest.Server
+	var requests int
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := r.URL.Query().Get("page")
+		if page != "" && r.Header.Get("If-None-Match") != "" {
+			t.Errorf("page %s sent If-None-Match", page)
+		}
+		switch page {
+		case "":
+			w.Header().Set("ETag", `"page1"`)
+			w.Header().Set("Link", "<"+server.URL+`/api/v3/repos/acme/api/pulls?page=2>; rel="next", <`+server.URL+`/api/v3/repos/acme/api/pulls?page=4>; rel="last"`)
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"id": 1, "number": 1, "updated_at": "2025-06-01T12:09:00Z"},
+				{"id": 2, "number": 2, "updated_at": "2025-06-01T12:08:00Z"},
+			})
+		case "2":
+			w.Header().Set("ETag", `"page2"`)
+			w.Header().Set("Link", "<"+server.URL+`/api/v3/repos/acme/api/pulls?page=3>; rel="next"`)
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"id": 3, "number": 3, "updated_at": "2025-06-01T12:07:00Z"},
+				{"id": 4, "number": 4, "updated_at": "2025-06-01T11:00:00Z"},
+			})
+		default:
+			t.Errorf("requested page %s after reaching the last poll", page)
+		}
+	}))
+	defer server.Close()
+
+	poller := NewGitHubPoller("ghp_test", server.URL)
+	events, cursor, err := poller.Poll(context.Background(), &PollState{LastPollTime: lastPoll, Cursor: `"old"`}, map[string]interface{}{
+		"repo":     "acme/api",
+		"resource": "pulls",
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+	if len(events) != 3 {
+		t.Errorf("got %d events, want 3 changed since the last poll", len(events))
+	}
+	if cursor != `"page1"` {
+		t.Errorf("cursor = %q, want the first page's ETag", cursor)
+	}
+}
+
+func TestGitHubPoller_NextPageURL(t *testing.T) {
+	poller := NewGitHubPoller("ghp_test", "")
+	tests := []struct {
+		link string
+		want string
+	}{
+		{`<https://api.github.com/repos/a/b/issues?page=2>; rel="next", <https://api.github.com/repos/a/b/issues?page=5>; rel="last"`, "https://api.github.com/repos/a/b/issues?page=2"},
+		{`<https://api.github.com/repos/a/b/issues?page=1>; rel="prev"`, ""},
+		{`<https://evil.example.com/steal?page=2>; rel="next"`, ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := poller.nextPageURL(tt.link); got != tt.want {
+			t.Errorf("nextPageURL(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubPoller_PollNotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != `"abc123"` {
+			t.Errorf("If-None-Match = %q", r.Header.Get("If-None-Match"))
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	poller := NewGitHubPoller("ghp_test", server.URL+"/api/v3")
+	events, cursor, err := poller.Poll(context.Background(), &PollState{Cursor: `"abc123"`}, map[string]interface{}{
+		"repo":     "acme/api",
+		"resource": "pulls",
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(events) != 0 || cursor != `"abc123"` {
+		t.Errorf("got %d events, cursor %q; want none and the same ETag", len(events), cursor)
+	}
+}
+
+func TestGitHubPoller_PollWorkflowRuns(t *testing.T) {
+	lastPoll := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/acme/api/actions/runs" || r.URL.Query().Get("status") != "failure" {
+			t.Errorf("url = %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"total_count": 2,
+			"workflow_runs": []map[string]any{
+				{"id": 9, "run_number": 3, "status": "completed", "conclusion": "failure", "head_branch": "main", "updated_at": "2025-06-01T12:10:00Z"},
+				{"id": 8, "run_number": 2, "status": "completed", "conclusion": "failure", "head_branch": "main", "updated_at": "2025-06-01T11:00:00Z"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	poller := NewGitHubPoller("ghp_test", server.URL)
+	events, _, err := poller.Poll(context.Background(), &PollState{LastPollTime: lastPoll}, map[string]interface{}{
+		"repo":     "acme/api",
+		"resource": "workflow_runs",
+		"status":   "failure",
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1 (older runs filtered)", len(events))
+	}
+	if events[0]["id"] != "workflow_run-9-2025-06-01T12:10:00Z" || events[0]["conclusion"] != "failure" {
+		t.Errorf("event = %v", events[0])
+	}
+}
+
+func TestGitHubPoller_PollErrors(t *testing.T) {
+	poller := NewGitHubPoller("ghp_test", "")
+	if poller.baseURL != "https://api.github.com" {
+		t.Errorf("baseURL = %q", poller.baseURL)
+	}
+
+	tests := []struct {
+		name  string
+		query map[string]interface{}
+	}{
+		{"unknown resource", map[string]interface{}{"repo": "acme/api", "resource": "wikis"}},
+		{"missing repo", map[string]interface{}{"resource": "issues"}},
+		{"repo without owner", map[string]interface{}{"repo": "api"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := poller.Poll(context.Background(), &PollState{}, tt.query); err == nil {
+				t.Error("Poll() error = nil, want error")
+			}
+		})
+	}
+}
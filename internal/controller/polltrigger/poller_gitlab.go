@@ -0,0 +1,263 @@
+package polltrigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// gitlabFilters lists the query parameters passed through to the GitLab API
+// for each polled resource.
+var gitlabFilters = map[string][]string{
+	"merge_requests": {"state", "scope", "labels", "author_username", "assignee_username", "reviewer_username", "source_branch", "target_branch", "milestone"},
+	"issues":         {"state", "scope", "labels", "author_username", "assignee_username", "milestone", "confidential"},
+	"pipelines":      {"status", "ref", "source", "username"},
+}
+
+// gitlabEventTypes names the events produced for each polled resource.
+var gitlabEventTypes = map[string]string{
+	"merge_requests": "merge_request",
+	"issues":         "issue",
+	"pipelines":      "pipeline",
+}
+
+// GitLabPoller implements polling for GitLab merge requests, issues and
+// pipelines.
+type GitLabPoller struct {
+	apiToken string
+	baseURL  string
+	client   *http.Client
+}
+
+// NewGitLabPoller creates a new GitLab poller.
+// baseURL is the GitLab instance URL (e.g., "https://gitlab.example.com");
+// empty means gitlab.com.
+func NewGitLabPoller(apiToken, baseURL string) *GitLabPoller {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/api/v4") {
+		baseURL += "/api/v4"
+	}
+
+	return &GitLabPoller{
+		apiToken: apiToken,
+		baseURL:  baseURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name returns the integration name.
+func (p *GitLabPoller) Name() string {
+	return "gitlab"
+}
+
+// Poll queries the GitLab API for merge requests, issues or pipelines
+// updated since the last poll time.
+// Supports query parameters: resource (merge_requests, issues, pipelines),
+// project, and the filters in gitlabFilters. Without a project, merge
+// requests and issues are searched across the instance within scope.
+func (p *GitLabPoller) Poll(ctx context.Context, state *PollState, query map[string]interface{}) ([]map[string]interface{}, string, error) {
+	resource := "merge_requests"
+	if r, ok := query["resource"].(string); ok && r != "" {
+		resource = r
+	}
+	filters, ok := gitlabFilters[resource]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported gitlab resource %q (use merge_requests, issues or pipelines)", resource)
+	}
+
+	// Build the resource path, scoped to a project if one is given
+	path := "/" + resource
+	if project := queryParamValue(query["project"]); project != "" {
+		path = "/projects/" + url.PathEscape(project) + path
+	} else if resource == "pipelines" {
+		return nil, "", fmt.Errorf("gitlab pipelines poll requires a project")
+	}
+
+	// Build query parameters
+	params := url.Values{}
+	for _, key := range filters {
+		if value := queryParamValue(query[key]); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	// Default to open merge requests and issues
+	if resource != "pipelines" && params.Get("state") == "" {
+		params.Set("state", "opened")
+	}
+
+	// Add timestamp filter (primary deduplication)
+	if !state.LastPollTime.IsZero() {
+		params.Set("updated_after", state.LastPollTime.UTC().Format(time.RFC3339))
+	}
+
+	// Sort by updated_at to get the most recently changed first
+	params.Set("order_by", "updated_at")
+	params.Set("sort", "desc")
+	params.Set("per_page", "100")
+
+	apiURL := fmt.Sprintf("%s%s?%s", p.baseURL, path, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", p.apiToken)
+
+	// Execute request
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", wrapAPIError(err, "gitlab")
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, "", fmt.Errorf("GitLab auth failed (%d). Token may be expired or revoked", resp.StatusCode)
+	}
+	if resp.StatusCode == 404 {
+		return nil, "", fmt.Errorf("GitLab project not found (404)")
+	}
+	if resp.StatusCode == 429 {
+		return nil, "", fmt.Errorf("GitLab rate limit exceeded (429)")
+	}
+	if resp.StatusCode >= 500 {
+		return nil, "", fmt.Errorf("GitLab API error (%d)", resp.StatusCode)
+	}
+	if resp.StatusCode != 200 {
+		return nil, "", fmt.Errorf("GitLab API returned status %d", resp.StatusCode)
+	}
+
+	// Parse response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var items []gitlabItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Convert items to events
+	events := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		events = append(events, p.itemToEvent(gitlabEventTypes[resource], item))
+	}
+
+	// GitLab uses page or keyset pagination, not a poll cursor
+	return events, "", nil
+}
+
+// queryParamValue converts a query parameter to its API form. Lists are
+// joined with commas, as GitHub and GitLab expect for labels.
+func queryParamValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, ",")
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// itemToEvent converts a GitLab merge request, issue or pipeline to a
+// generic event map. The event ID includes the update time, so each
+// change to an item is a new event.
+func (p *GitLabPoller) itemToEvent(eventType string, item gitlabItem) map[string]interface{} {
+	event := map[string]interface{}{
+		"id":         fmt.Sprintf("%s-%d-%s", eventType, item.ID, item.UpdatedAt),
+		"type":       eventType,
+		"object_id":  item.ID,
+		"project_id": item.ProjectID,
+		"web_url":    item.WebURL,
+		"created_at": item.CreatedAt,
+		"updated_at": item.UpdatedAt,
+	}
+
+	if item.IID != 0 {
+		event["iid"] = item.IID
+	}
+	if item.Title != "" {
+		event["title"] = item.Title
+	}
+	if item.Description != "" {
+		event["description"] = item.Description
+	}
+	if item.State != "" {
+		event["state"] = item.State
+	}
+	if item.Status != "" {
+		event["status"] = item.Status
+	}
+	if item.Ref != "" {
+		event["ref"] = item.Ref
+		event["sha"] = item.SHA
+	}
+	if item.SourceBranch != "" {
+		event["source_branch"] = item.SourceBranch
+		event["target_branch"] = item.TargetBranch
+	}
+	if item.Author != nil {
+		event["author"] = map[string]interface{}{
+			"id":       item.Author.ID,
+			"username": item.Author.Username,
+			"name":     item.Author.Name,
+		}
+	}
+	if len(item.Labels) > 0 {
+		event["labels"] = item.Labels
+	}
+
+	return event
+}
+
+// GitLab API response types
+
+// gitlabItem holds the fields of merge requests, issues and pipelines.
+type gitlabItem struct {
+	ID           int64       `json:"id"`
+	IID          int64       `json:"iid"`
+	ProjectID    int64       `json:"project_id"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	State        string      `json:"state"`
+	Status       string      `json:"status"`
+	Ref          string      `json:"ref"`
+	SHA          string      `json:"sha"`
+	SourceBranch string      `json:"source_branch"`
+	TargetBranch string      `json:"target_branch"`
+	WebURL       string      `json:"web_url"`
+	Author       *gitlabUser `json:"author"`
+	Labels       []string    `json:"labels"`
+	CreatedAt    string      `json:"created_at"`
+	UpdatedAt    string      `json:"updated_at"`
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
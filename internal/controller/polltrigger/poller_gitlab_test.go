@@ -0,0 +1,83 @@
+package polltrigger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGitLabPoller_Poll(t *testing.T) {
+	lastPoll := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/platform%2Fapi/merge_requests" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+			t.Errorf("PRIVATE-TOKEN = %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		query := r.URL.Query()
+		if query.Get("reviewer_username") != "alice" || query.Get("labels") != "bug,urgent" || query.Get("state") != "opened" {
+			t.Errorf("query = %v", query)
+		}
+		if query.Get("updated_after") != "2025-06-01T12:00:00Z" {
+			t.Errorf("updated_after = %q", query.Get("updated_after"))
+		}
+
+		json.NewEncoder(w).Encode([]map[string]any{{
+			"id":            101,
+			"iid":           7,
+			"project_id":    42,
+			"title":         "Fix login",
+			"state":         "opened",
+			"source_branch": "fix-login",
+			"target_branch": "main",
+			"updated_at":    "2025-06-01T12:05:00Z",
+			"author":        map[string]any{"id": 1, "username": "bob"},
+		}})
+	}))
+	defer server.Close()
+
+	poller := NewGitLabPoller("glpat-test", server.URL)
+	events, _, err := poller.Poll(context.Background(), &PollState{LastPollTime: lastPoll}, map[string]interface{}{
+		"project":           "platform/api",
+		"reviewer_username": "alice",
+		"labels":            []interface{}{"bug", "urgent"},
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event["id"] != "merge_request-101-2025-06-01T12:05:00Z" || event["type"] != "merge_request" || event["iid"] != int64(7) {
+		t.Errorf("event = %v", event)
+	}
+}
+
+func TestGitLabPoller_PollErrors(t *testing.T) {
+	poller := NewGitLabPoller("glpat-test", "https://gitlab.example.com/")
+	if poller.baseURL != "https://gitlab.example.com/api/v4" {
+		t.Errorf("baseURL = %q", poller.baseURL)
+	}
+
+	tests := []struct {
+		name  string
+		query map[string]interface{}
+	}{
+		{"unknown resource", map[string]interface{}{"resource": "wikis"}},
+		{"pipelines without project", map[string]interface{}{"resource": "pipelines"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := poller.Poll(context.Background(), &PollState{}, tt.query); err == nil {
+				t.Error("Poll() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package polltrigger
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/tombee/conductor/internal/jq"
+	"github.com/tombee/conductor/internal/operation"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// httpMaxPages caps the pages fetched in one poll. A poll that reaches the
+// cap resumes from its cursor on the next poll.
+const httpMaxPages = 10
+
+// HTTPPoller polls an operation of an integration declared in a workflow.
+// The trigger configuration says how to find events in the operation
+// response, so new sources need no Go code.
+type HTTPPoller struct {
+	name           string
+	provider       operation.Provider
+	operation      string
+	items          string
+	idField        string
+	timestampField string
+	cursor         string
+	cursorParam    string
+	sinceParam     string
+	jq             *jq.Executor
+}
+
+// NewHTTPPoller creates a poller for a poll trigger on a workflow-declared
+// operation. def is the integration the trigger references.
+func NewHTTPPoller(def *workflow.IntegrationDefinition, cfg *workflow.PollTriggerConfig) (*HTTPPoller, error) {
+	_, operationName := cfg.DeclaredOperation()
+
+	// The poll may only reach the host the workflow declared, which can be
+	// an internal API the default SSRF rules would block
+	opConfig := operation.DefaultConfig()
+	if def.BaseURL != "" {
+		baseURL, err := url.Parse(def.BaseURL)
+		if err != nil || baseURL.Hostname() == "" {
+			return nil, fmt.Errorf("invalid base_url %q", def.BaseURL)
+		}
+		opConfig.AllowedHosts = []string{baseURL.Hostname()}
+	}
+
+	provider, err := operation.New(withPollInputs(def, operationName, cfg), opConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create integration %s: %w", def.Name, err)
+	}
+
+	p := &HTTPPoller{
+		name:           cfg.Integration,
+		provider:       provider,
+		operation:      operationName,
+		items:          cfg.Items,
+		idField:        cfg.IDField,
+		timestampField: cfg.TimestampField,
+		cursor:         cfg.Cursor,
+		cursorParam:    cfg.CursorParam,
+		sinceParam:     cfg.SinceParam,
+		jq:             jq.NewExecutor(operation.MaxTransformTimeout, operation.MaxTransformInputSize),
+	}
+	if p.items == "" {
+		p.items = ".[]"
+	}
+	if p.idField == "" {
+		p.idField = "id"
+	}
+
+	// Catch jq syntax errors at registration rather than on every poll
+	if err := p.jq.Validate(p.items); err != nil {
+		return nil, fmt.Errorf("invalid items expression: %w", err)
+	}
+	if err := p.jq.Validate(p.cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor expression: %w", err)
+	}
+
+	return p, nil
+}
+
+// withPollInputs returns a copy of def whose polled operation declares the
+// trigger's query, since and cursor inputs, so they are sent as query
+// parameters. Poll must be called with cfg.Query.
+func withPollInputs(def *workflow.IntegrationDefinition, operationName string, cfg *workflow.PollTriggerConfig) *workflow.IntegrationDefinition {
+	op, ok := def.Operations[operationName]
+	if !ok {
+		return def
+	}
+
+	properties := make(map[string]interface{})
+	if declared, ok := op.RequestSchema["properties"].(map[string]interface{}); ok {
+		for name, schema := range declared {
+			properties[name] = schema
+		}
+	}
+	names := []string{cfg.SinceParam, cfg.CursorParam}
+	for name := range cfg.Query {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if _, ok := properties[name]; name != "" && !ok {
+			properties[name] = map[string]interface{}{}
+		}
+	}
+
+	schema := make(map[string]interface{}, len(op.RequestSchema)+1)
+	for key, value := range op.RequestSchema {
+		schema[key] = value
+	}
+	schema["properties"] = properties
+	op.RequestSchema = schema
+
+	copied := *def
+	copied.Operations = make(map[string]workflow.OperationDefinition, len(def.Operations))
+	for name, declared := range def.Operations {
+		copied.Operations[name] = declared
+	}
+	copied.Operations[operationName] = op
+	return &copied
+}
+
+// Name returns the operation reference, e.g. "tickets.list_tickets".
+func (p *HTTPPoller) Name() string {
+	return p.name
+}
+
+// Poll runs the operation with the query as its inputs and returns the
+// selected items as events.
+// Items are skipped if their timestamp is before the high-water mark, or
+// the last poll time before any events have been seen. With a cursor, the
+// poll follows pages until the cursor runs out or the response has no
+// items, and returns the last cursor so the next poll resumes from it.
+func (p *HTTPPoller) Poll(ctx context.Context, state *PollState, query map[string]interface{}) ([]map[string]interface{}, string, error) {
+	since := state.HighWaterMark
+	if since.IsZero() {
+		since = state.LastPollTime
+	}
+
+	var events []map[string]interface{}
+	cursor := state.Cursor
+	for page := 0; page < httpMaxPages; page++ {
+		// Build operation inputs
+		inputs := make(map[string]interface{}, len(query)+2)
+		for k, v := range query {
+			inputs[k] = v
+		}
+		if p.sinceParam != "" && !since.IsZero() {
+			inputs[p.sinceParam] = since.UTC().Format(time.RFC3339)
+		}
+		if p.cursorParam != "" && cursor != "" {
+			inputs[p.cursorParam] = cursor
+		}
+
+		result, err := p.provider.Execute(ctx, p.operation, inputs)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s failed: %w", p.name, err)
+		}
+
+		items, err := p.extractItems(ctx, result.Response)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, item := range items {
+			if event, ok := p.itemToEvent(item, since); ok {
+				events = append(events, event)
+			}
+		}
+
+		if p.cursor == "" {
+			return events, "", nil
+		}
+		next, err := p.extractCursor(ctx, result.Response)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// No cursor means the last page; start from the first page next poll.
+		// An empty page means we have caught up; resume from here next poll.
+		if next == "" {
+			return events, "", nil
+		}
+		if len(items) == 0 || next == cursor {
+			return events, next, nil
+		}
+		cursor = next
+	}
+
+	return events, cursor, nil
+}
+
+// extractItems applies the items expression to an operation response.
+func (p *HTTPPoller) extractItems(ctx context.Context, response interface{}) ([]map[string]interface{}, error) {
+	selected, err := p.jq.Execute(ctx, p.items, response)
+	if err != nil {
+		return nil, fmt.Errorf("items expression failed: %w", err)
+	}
+
+	// The executor returns a single result as itself and several as a list
+	var values []interface{}
+	switch v := selected.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values = v
+	default:
+		values = []interface{}{v}
+	}
+
+	items := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		item, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("items expression %q must select objects, got %T", p.items, value)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// extractCursor applies the cursor expression to an operation response.
+func (p *HTTPPoller) extractCursor(ctx context.Context, response interface{}) (string, error) {
+	value, err := p.jq.Execute(ctx, p.cursor, response)
+	if err != nil {
+		return "", fmt.Errorf("cursor expression failed: %w", err)
+	}
+	if value == nil {
+		return "", nil
+	}
+	return queryParamValue(value), nil
+}
+
+// itemToEvent converts an item to an event. The event keeps the item's
+// fields, with id set to the item's ID, and to the ID and timestamp when a
+// timestamp field is configured so each change to an item is a new event.
+// Items without an ID, or older than since, are skipped.
+func (p *HTTPPoller) itemToEvent(item map[string]interface{}, since time.Time) (map[string]interface{}, bool) {
+	id := queryParamValue(item[p.idField])
+	if id == "" {
+		return nil, false
+	}
+
+	event := make(map[string]interface{}, len(item)+2)
+	for k, v := range item {
+		event[k] = v
+	}
+	event["id"] = id
+
+	if p.timestampField != "" {
+		raw := item[p.timestampField]
+		if changed, ok := parseItemTime(raw); ok {
+			if changed.Before(since) {
+				return nil, false
+			}
+			event["timestamp"] = changed.UTC().Format(time.RFC3339)
+		}
+		if raw != nil {
+			event["id"] = id + "-" + queryParamValue(raw)
+		}
+	}
+
+	return event, true
+}
+
+// parseItemTime parses an RFC 3339 timestamp or Unix seconds.
+func parseItemTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(secs, 0), true
+		}
+	case float64:
+		return time.Unix(int64(v), 0), true
+	}
+	return time.Time{}, false
+}
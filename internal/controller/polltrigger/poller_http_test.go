@@ -0,0 +1,134 @@
+package polltrigger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+func newTestHTTPPoller(t *testing.T, baseURL string, cfg *workflow.PollTriggerConfig) *HTTPPoller {
+	t.Helper()
+	def := &workflow.IntegrationDefinition{
+		Name:    "tickets",
+		BaseURL: baseURL,
+		Auth:    &workflow.AuthDefinition{Type: "bearer", Token: "${TICKETS_TOKEN}"},
+		Operations: map[string]workflow.OperationDefinition{
+			"list_tickets": {Method: "GET", Path: "/api/tickets"},
+		},
+	}
+	poller, err := NewHTTPPoller(def, cfg)
+	if err != nil {
+		t.Fatalf("NewHTTPPoller() error = %v", err)
+	}
+	return poller
+}
+
+func TestHTTPPoller_Poll(t *testing.T) {
+	t.Setenv("TICKETS_TOKEN", "secret")
+	highWaterMark := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		query := r.URL.Query()
+		if query.Get("status") != "open" || query.Get("updated_since") != "2025-06-01T12:00:00Z" {
+			t.Errorf("query = %v", query)
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"tickets": []map[string]any{
+				{"key": "T-1", "title": "Login broken", "modified": "2025-06-01T12:05:00Z"},
+				{"key": "T-2", "title": "Old", "modified": "2025-06-01T11:00:00Z"},
+				{"title": "No key"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	poller := newTestHTTPPoller(t, server.URL, &workflow.PollTriggerConfig{
+		Integration:    "tickets.list_tickets",
+		Query:          map[string]interface{}{"status": "open"},
+		Items:          ".tickets[]",
+		IDField:        "key",
+		TimestampField: "modified",
+		SinceParam:     "updated_since",
+	})
+	if poller.Name() != "tickets.list_tickets" {
+		t.Errorf("Name() = %q", poller.Name())
+	}
+
+	events, cursor, err := poller.Poll(context.Background(), &PollState{HighWaterMark: highWaterMark}, map[string]interface{}{
+		"status": "open",
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if cursor != "" {
+		t.Errorf("cursor = %q, want none", cursor)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1 (old and keyless items skipped)", len(events))
+	}
+	event := events[0]
+	if event["id"] != "T-1-2025-06-01T12:05:00Z" || event["timestamp"] != "2025-06-01T12:05:00Z" || event["title"] != "Login broken" {
+		t.Errorf("event = %v", event)
+	}
+}
+
+func TestHTTPPoller_PollCursor(t *testing.T) {
+	t.Setenv("TICKETS_TOKEN", "secret")
+
+	pages := map[string]map[string]any{
+		"":   {"items": []map[string]any{{"id": 1}}, "next": "p2"},
+		"p2": {"items": []map[string]any{{"id": 2}, {"id": 3}}, "next": "p3"},
+		"p3": {"items": []map[string]any{}, "next": "p3-tail"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Query().Get("after")]
+		if !ok {
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("after"))
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	poller := newTestHTTPPoller(t, server.URL, &workflow.PollTriggerConfig{
+		Integration: "tickets.list_tickets",
+		Items:       ".items",
+		Cursor:      ".next",
+		CursorParam: "after",
+	})
+
+	events, cursor, err := poller.Poll(context.Background(), &PollState{}, nil)
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(events) != 3 || events[2]["id"] != "3" {
+		t.Errorf("events = %v, want ids 1-3", events)
+	}
+	if cursor != "p3-tail" {
+		t.Errorf("cursor = %q, want the cursor of the empty page", cursor)
+	}
+}
+
+func TestNewHTTPPoller_InvalidExpression(t *testing.T) {
+	def := &workflow.IntegrationDefinition{
+		Name:       "tickets",
+		BaseURL:    "https://tickets.example.com",
+		Operations: map[string]workflow.OperationDefinition{"list_tickets": {Method: "GET", Path: "/tickets"}},
+	}
+	_, err := NewHTTPPoller(def, &workflow.PollTriggerConfig{
+		Integration: "tickets.list_tickets",
+		Items:       ".tickets[",
+	})
+	if err == nil {
+		t.Error("NewHTTPPoller() error = nil, want invalid items expression")
+	}
+}
@@ -0,0 +1,72 @@
+package polltrigger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrPollerNotConfigured is returned by NewPollerFromEnv when the credentials
+// for an integration are not set.
+var ErrPollerNotConfigured = errors.New("poller not configured")
+
+// PollerIntegrations lists the integrations that have a poller.
+var PollerIntegrations = []string{"pagerduty", "datadog", "jira", "github", "gitlab", "slack"}
+
+// NewPollerFromEnv creates the poller for an integration from its
+// environment variables. The controller and the CLI share it, so a trigger
+// tested with `conductor triggers test` polls exactly as it would at runtime.
+func NewPollerFromEnv(integration string) (IntegrationPoller, error) {
+	switch integration {
+	case "pagerduty":
+		token := os.Getenv("PAGERDUTY_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("%w: set PAGERDUTY_TOKEN", ErrPollerNotConfigured)
+		}
+		return NewPagerDutyPoller(token), nil
+
+	case "datadog":
+		apiKey := os.Getenv("DATADOG_API_KEY")
+		appKey := os.Getenv("DATADOG_APP_KEY")
+		if apiKey == "" || appKey == "" {
+			return nil, fmt.Errorf("%w: set DATADOG_API_KEY and DATADOG_APP_KEY", ErrPollerNotConfigured)
+		}
+		// DATADOG_SITE is optional, defaults to datadoghq.com
+		return NewDatadogPoller(apiKey, appKey, os.Getenv("DATADOG_SITE")), nil
+
+	case "jira":
+		email := os.Getenv("JIRA_EMAIL")
+		apiToken := os.Getenv("JIRA_API_TOKEN")
+		baseURL := os.Getenv("JIRA_BASE_URL")
+		if email == "" || apiToken == "" || baseURL == "" {
+			return nil, fmt.Errorf("%w: set JIRA_EMAIL, JIRA_API_TOKEN and JIRA_BASE_URL", ErrPollerNotConfigured)
+		}
+		return NewJiraPoller(email, apiToken, baseURL), nil
+
+	case "github":
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("%w: set GITHUB_TOKEN", ErrPollerNotConfigured)
+		}
+		// GITHUB_BASE_URL is optional, defaults to github.com
+		return NewGitHubPoller(token, os.Getenv("GITHUB_BASE_URL")), nil
+
+	case "gitlab":
+		token := os.Getenv("GITLAB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("%w: set GITLAB_TOKEN", ErrPollerNotConfigured)
+		}
+		// GITLAB_BASE_URL is optional, defaults to gitlab.com
+		return NewGitLabPoller(token, os.Getenv("GITLAB_BASE_URL")), nil
+
+	case "slack":
+		token := os.Getenv("SLACK_BOT_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("%w: set SLACK_BOT_TOKEN", ErrPollerNotConfigured)
+		}
+		return NewSlackPoller(token), nil
+
+	default:
+		return nil, fmt.Errorf("no poller for integration %q", integration)
+	}
+}
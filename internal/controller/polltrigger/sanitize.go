@@ -35,6 +35,12 @@ var integrationSensitiveFields = map[string][]string{
 	"datadog": {
 		// No additional fields beyond common patterns
 	},
+	"github": {
+		// No additional fields beyond common patterns
+	},
+	"gitlab": {
+		// No additional fields beyond common patterns
+	},
 }
 
 // Input validation pattern: alphanumeric, underscore, hyphen only.
@@ -44,6 +50,10 @@ var safeIdentifierPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 // Extended pattern allowing spaces and periods for Jira/Slack usernames.
 var safeExtendedIdentifierPattern = regexp.MustCompile(`^[a-zA-Z0-9_\-. ]+$`)
 
+// Path pattern for GitHub repositories and GitLab project paths
+// (e.g., "acme/api", "group/subgroup/project").
+var safePathPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+(/[a-zA-Z0-9_.-]+)*$`)
+
 // ValidateIdentifier validates that a string matches the safe identifier pattern.
 // This prevents injection attacks in integration query parameters.
 func ValidateIdentifier(value string) error {
@@ -72,6 +82,27 @@ func ValidateExtendedIdentifier(value string) error {
 	return nil
 }
 
+// ValidatePath validates that a string is a slash-separated repository or
+// project path.
+func ValidatePath(value string) error {
+	if value == "" {
+		return fmt.Errorf("value cannot be empty")
+	}
+
+	if !safePathPattern.MatchString(value) {
+		return fmt.Errorf("value %q is not a valid path (only alphanumeric, underscore, hyphen and period segments separated by slashes allowed)", value)
+	}
+
+	// "." and ".." segments would resolve to other API endpoints
+	for _, segment := range strings.Split(value, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("value %q is not a valid path (relative segments are not allowed)", value)
+		}
+	}
+
+	return nil
+}
+
 // ValidateQueryParameters validates all string values in a query map.
 // Returns an error if any value contains potentially dangerous characters.
 func ValidateQueryParameters(query map[string]interface{}) error {
@@ -79,7 +110,11 @@ func ValidateQueryParameters(query map[string]interface{}) error {
 		switch v := value.(type) {
 		case string:
 			// Use extended validation for username-like fields
-			if isUsernameField(key) {
+			if isPathField(key) {
+				if err := ValidatePath(v); err != nil {
+					return fmt.Errorf("invalid value for %q: %w", key, err)
+				}
+			} else if isUsernameField(key) {
 				if err := ValidateExtendedIdentifier(v); err != nil {
 					return fmt.Errorf("invalid value for %q: %w", key, err)
 				}
@@ -118,6 +153,11 @@ func isUsernameField(fieldName string) bool {
 		strings.Contains(lower, "name")
 }
 
+// isPathField returns true if the field holds a repository or project path.
+func isPathField(fieldName string) bool {
+	return fieldName == "repo" || fieldName == "project"
+}
+
 // StripSensitiveFields removes sensitive fields from an event map.
 // This prevents credentials and secrets from being passed to workflows.
 func StripSensitiveFields(event map[string]interface{}, integration string) map[string]interface{} {
@@ -235,6 +275,9 @@ func SanitizeErrorMessage(err error) string {
 		{regexp.MustCompile(`DD-APPLICATION-KEY: [a-zA-Z0-9]+`), "DD-APPLICATION-KEY: [REDACTED]"},
 		{regexp.MustCompile(`xoxb-[a-zA-Z0-9-]+`), "[REDACTED-SLACK-TOKEN]"},
 		{regexp.MustCompile(`xoxp-[a-zA-Z0-9-]+`), "[REDACTED-SLACK-TOKEN]"},
+		{regexp.MustCompile(`gh[pousr]_[a-zA-Z0-9]+`), "[REDACTED-GITHUB-TOKEN]"},
+		{regexp.MustCompile(`github_pat_[a-zA-Z0-9_]+`), "[REDACTED-GITHUB-TOKEN]"},
+		{regexp.MustCompile(`glpat-[a-zA-Z0-9_-]+`), "[REDACTED-GITLAB-TOKEN]"},
 	}
 
 	sanitized := msg
@@ -279,6 +322,16 @@ func ValidateIntegrationConfig(integration string, config map[string]string) err
 			return fmt.Errorf("datadog integration requires app_key")
 		}
 		// site is optional, defaults to datadoghq.com
+	case "github":
+		if config["api_token"] == "" {
+			return fmt.Errorf("github integration requires api_token")
+		}
+		// base_url is optional, defaults to github.com
+	case "gitlab":
+		if config["api_token"] == "" {
+			return fmt.Errorf("gitlab integration requires api_token")
+		}
+		// base_url is optional, defaults to gitlab.com
 	default:
 		return fmt.Errorf("unknown integration: %s", integration)
 	}
@@ -2,6 +2,7 @@ package polltrigger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -203,7 +204,7 @@ func (s *Service) RegisterTrigger(reg *PollTriggerRegistration) error {
 	}
 
 	// Check if integration poller is registered
-	if _, exists := s.pollers[reg.Integration]; !exists {
+	if s.pollerFor(reg) == nil {
 		return fmt.Errorf("integration %s not registered", reg.Integration)
 	}
 
@@ -228,6 +229,15 @@ func (s *Service) RegisterTrigger(reg *PollTriggerRegistration) error {
 	return nil
 }
 
+// pollerFor returns the poller for a registration, or nil if its
+// integration has none. Callers must hold s.mu.
+func (s *Service) pollerFor(reg *PollTriggerRegistration) IntegrationPoller {
+	if reg.Poller != nil {
+		return reg.Poller
+	}
+	return s.pollers[reg.Integration]
+}
+
 // UnregisterTrigger removes a poll trigger.
 func (s *Service) UnregisterTrigger(triggerID string) error {
 	s.mu.Lock()
@@ -255,14 +265,47 @@ func (s *Service) UnregisterTrigger(triggerID string) error {
 
 // RegisterWorkflowTriggers scans a workflow and registers any poll triggers.
 func (s *Service) RegisterWorkflowTriggers(workflowPath string, wf *workflow.Definition) error {
-	if wf.Trigger == nil || wf.Trigger.Poll == nil {
-		return nil
+	regs, err := WorkflowRegistrations(workflowPath, wf)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, reg := range regs {
+		if err := s.RegisterTrigger(reg); err != nil {
+			errs = append(errs, fmt.Errorf("poll trigger %s: %w", reg.WorkflowTriggerID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WorkflowRegistrations builds the registrations for the poll triggers of a
+// workflow without registering them. Triggers with invalid settings are
+// reported in the returned error and left out.
+func WorkflowRegistrations(workflowPath string, wf *workflow.Definition) ([]*PollTriggerRegistration, error) {
+	var regs []*PollTriggerRegistration
+	var errs []error
+	for _, t := range wf.TriggersOfType(workflow.TriggerTypePoll) {
+		reg, err := newWorkflowRegistration(workflowPath, wf, t)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("poll trigger %s: %w", t.TriggerID(), err))
+			continue
+		}
+		regs = append(regs, reg)
 	}
+	return regs, errors.Join(errs...)
+}
 
-	pollCfg := wf.Trigger.Poll
+// newWorkflowRegistration builds the registration for one poll trigger of a
+// workflow.
+func newWorkflowRegistration(workflowPath string, wf *workflow.Definition, t *workflow.TriggerConfig) (*PollTriggerRegistration, error) {
+	pollCfg := t.Poll
 
-	// Generate trigger ID from workflow path and integration
+	// Generate trigger ID from workflow path and integration, and the
+	// trigger's own ID if it has one
 	triggerID := fmt.Sprintf("%s:%s", workflowPath, pollCfg.Integration)
+	if t.ID != "" {
+		triggerID += ":" + t.ID
+	}
 
 	// Convert query map
 	query := make(map[string]interface{})
@@ -281,7 +324,7 @@ func (s *Service) RegisterWorkflowTriggers(workflowPath string, wf *workflow.Def
 	if pollCfg.Interval != "" {
 		duration, err := time.ParseDuration(pollCfg.Interval)
 		if err != nil {
-			return fmt.Errorf("invalid interval %s: %w", pollCfg.Interval, err)
+			return nil, fmt.Errorf("invalid interval %s: %w", pollCfg.Interval, err)
 		}
 		interval = int(duration.Seconds())
 	}
@@ -297,23 +340,39 @@ func (s *Service) RegisterWorkflowTriggers(workflowPath string, wf *workflow.Def
 	if pollCfg.Backfill != "" {
 		duration, err := time.ParseDuration(pollCfg.Backfill)
 		if err != nil {
-			return fmt.Errorf("invalid backfill duration %s: %w", pollCfg.Backfill, err)
+			return nil, fmt.Errorf("invalid backfill duration %s: %w", pollCfg.Backfill, err)
 		}
 		backfill = int(duration.Seconds())
 	}
 
 	reg := &PollTriggerRegistration{
-		TriggerID:    triggerID,
-		WorkflowPath: workflowPath,
-		Integration:  pollCfg.Integration,
-		Query:        query,
-		Interval:     interval,
-		Startup:      startup,
-		Backfill:     backfill,
-		InputMapping: inputMapping,
+		TriggerID:         triggerID,
+		WorkflowPath:      workflowPath,
+		WorkflowTriggerID: t.TriggerID(),
+		Integration:       pollCfg.Integration,
+		Query:             query,
+		Interval:          interval,
+		Startup:           startup,
+		Backfill:          backfill,
+		InputMapping:      inputMapping,
+	}
+
+	// Operations of workflow-declared integrations get their own poller
+	if pollCfg.IsDeclaredOperation() {
+		integrationName, _ := pollCfg.DeclaredOperation()
+		def, ok := wf.Integrations[integrationName]
+		if !ok {
+			return nil, fmt.Errorf("integration %s is not declared in the workflow", integrationName)
+		}
+		def.Name = integrationName
+		poller, err := NewHTTPPoller(&def, pollCfg)
+		if err != nil {
+			return nil, err
+		}
+		reg.Poller = poller
 	}
 
-	return s.RegisterTrigger(reg)
+	return reg, nil
 }
 
 // handlePoll is called by the scheduler when a poll timer fires.
@@ -332,8 +391,8 @@ func (s *Service) handlePoll(ctx context.Context, triggerID string) error {
 		return fmt.Errorf("trigger %s not registered", triggerID)
 	}
 
-	poller, exists := s.pollers[reg.Integration]
-	if !exists {
+	poller := s.pollerFor(reg)
+	if poller == nil {
 		s.mu.RUnlock()
 		return fmt.Errorf("integration %s not registered", reg.Integration)
 	}
@@ -475,11 +534,14 @@ func (s *Service) handlePoll(ctx context.Context, triggerID string) error {
 
 		// Fire the workflow
 		triggerContext := &PollTriggerContext{
+			Type:        string(workflow.TriggerTypePoll),
+			ID:          reg.WorkflowTriggerID,
 			Integration: reg.Integration,
 			TriggerTime: time.Now(),
 			PollTime:    pollTime,
 			Event:       cleanedEvent,
 			Query:       reg.Query,
+			TriggerID:   triggerID,
 		}
 
 		if err := s.workflowFirer(pollCtx, reg.WorkflowPath, triggerContext); err != nil {
@@ -526,8 +588,8 @@ func (s *Service) handlePoll(ctx context.Context, triggerID string) error {
 // extractEventTimestamp attempts to extract a timestamp from an event.
 // Returns zero time if not found or invalid.
 func extractEventTimestamp(event map[string]interface{}) (time.Time, bool) {
-	// Try common timestamp field names
-	for _, field := range []string{"created_at", "timestamp", "updated_at", "time"} {
+	// Try common timestamp field names, preferring the normalized timestamp
+	for _, field := range []string{"timestamp", "created_at", "updated_at", "time"} {
 		if val, ok := event[field]; ok {
 			switch v := val.(type) {
 			case string:
@@ -6,8 +6,59 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
+func TestService_RegisterDeclaredOperation(t *testing.T) {
+	wf, err := workflow.ParseDefinition([]byte(`
+name: ticket-triage
+integrations:
+  tickets:
+    base_url: https://tickets.example.com
+    operations:
+      list_tickets:
+        method: GET
+        path: /api/tickets
+trigger:
+  poll:
+    integration: tickets.list_tickets
+    items: .tickets[]
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`))
+	if err != nil {
+		t.Fatalf("ParseDefinition() error = %v", err)
+	}
+
+	regs, err := WorkflowRegistrations("/workflows/triage.yaml", wf)
+	if err != nil {
+		t.Fatalf("WorkflowRegistrations() error = %v", err)
+	}
+	if len(regs) != 1 || regs[0].Poller == nil || regs[0].Poller.Name() != "tickets.list_tickets" {
+		t.Fatalf("registrations = %+v, want one with its own poller", regs)
+	}
+
+	svc, err := NewService(ServiceConfig{
+		Logger: slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		WorkflowFirer: func(ctx context.Context, workflowPath string, triggerContext *PollTriggerContext) error {
+			return nil
+		},
+		StateDBPath: ":memory:",
+	})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	defer svc.Stop(context.Background())
+
+	// No poller is registered for the integration; the trigger brings its own
+	if err := svc.RegisterTrigger(regs[0]); err != nil {
+		t.Errorf("RegisterTrigger() error = %v", err)
+	}
+}
+
 func TestService_Lifecycle(t *testing.T) {
 	// This test takes 12+ seconds due to minimum poll interval enforcement
 	if testing.Short() {
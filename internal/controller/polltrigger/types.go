@@ -1,6 +1,6 @@
 // Package polltrigger implements poll-based triggers for external service events.
 //
-// Poll triggers periodically query external service APIs (PagerDuty, Slack, Jira, Datadog)
+// Poll triggers periodically query external service APIs (PagerDuty, Slack, Jira, Datadog, GitHub, GitLab)
 // for events relevant to the user and fire workflows for new events. This enables personal
 // automation use cases without requiring webhooks or public endpoints.
 package polltrigger
@@ -19,7 +19,7 @@ type PollState struct {
 	// WorkflowPath is the path to the workflow file
 	WorkflowPath string `json:"workflow_path"`
 
-	// Integration is the integration being polled (slack, pagerduty, jira, datadog)
+	// Integration is the integration being polled (slack, pagerduty, jira, datadog, github, gitlab)
 	Integration string `json:"integration"`
 
 	// LastPollTime is the PRIMARY deduplication mechanism - always passed to API as "since" parameter
@@ -33,7 +33,8 @@ type PollState struct {
 	// Maps event ID to first seen timestamp
 	SeenEvents map[string]int64 `json:"seen_events"`
 
-	// Cursor is the API pagination cursor, if applicable
+	// Cursor is an opaque value the poller carries between polls, such as an
+	// API pagination cursor or the ETag of the previous response
 	Cursor string `json:"cursor,omitempty"`
 
 	// LastError is the last error message encountered
@@ -51,7 +52,13 @@ type PollState struct {
 
 // PollTriggerContext contains the data passed to workflows when a poll trigger fires.
 type PollTriggerContext struct {
-	// Integration is the integration that sourced this event (slack, pagerduty, jira, datadog)
+	// Type is the trigger type, always poll
+	Type string `json:"type"`
+
+	// ID identifies the trigger within its workflow
+	ID string `json:"id"`
+
+	// Integration is the integration that sourced this event (slack, pagerduty, jira, datadog, github, gitlab)
 	Integration string `json:"integration"`
 
 	// TriggerTime is when the trigger fired (when we decided to invoke the workflow)
@@ -66,10 +73,13 @@ type PollTriggerContext struct {
 
 	// Query contains the query parameters that matched this event (for debugging)
 	Query map[string]interface{} `json:"query"`
+
+	// TriggerID identifies the poll trigger. It is not passed to workflows.
+	TriggerID string `json:"-"`
 }
 
 // IntegrationPoller defines the interface for integration-specific polling implementations.
-// Each integration (PagerDuty, Slack, Jira, Datadog) implements this interface to provide
+// Each integration (PagerDuty, Slack, Jira, Datadog, GitHub, GitLab) implements this interface to provide
 // polling logic for its API.
 type IntegrationPoller interface {
 	// Poll queries the integration API for new events since the given timestamp.
@@ -89,6 +99,9 @@ type PollTriggerRegistration struct {
 	// WorkflowPath is the path to the workflow file
 	WorkflowPath string
 
+	// WorkflowTriggerID identifies the trigger within its workflow
+	WorkflowTriggerID string
+
 	// Integration is the integration to poll
 	Integration string
 
@@ -106,4 +119,8 @@ type PollTriggerRegistration struct {
 
 	// InputMapping maps trigger event fields to workflow inputs
 	InputMapping map[string]string
+
+	// Poller polls a workflow-declared operation. When nil, the poller
+	// registered for Integration is used
+	Poller IntegrationPoller
 }
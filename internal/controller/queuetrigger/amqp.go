@@ -0,0 +1,186 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuetrigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// amqpPrefetch is how many unacknowledged messages a consumer holds,
+// including messages waiting out their backoff.
+const amqpPrefetch = 16
+
+// AMQPConsumer consumes an AMQP 0.9.1 queue, such as a RabbitMQ queue. The
+// queue must already exist. Consumers of the same queue share its messages.
+//
+// AMQP cannot delay a redelivery, so a failed message is published to the
+// queue again once its backoff has passed, and the original is
+// acknowledged. Messages that are given up on are rejected, which routes
+// them to the queue's dead-letter exchange if it has one.
+type AMQPConsumer struct {
+	URL      string
+	Queue    string
+	Consumer string
+}
+
+// Consume implements Consumer.
+func (c *AMQPConsumer) Consume(ctx context.Context, handle Handler) error {
+	conn, err := amqp.Dial(c.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to amqp broker: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open amqp channel: %w", err)
+	}
+	if err := ch.Qos(amqpPrefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	deliveries, err := ch.ConsumeWithContext(ctx, c.Queue, c.Consumer, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume queue %s: %w", c.Queue, err)
+	}
+
+	var retries sync.WaitGroup
+	defer retries.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return errors.New("amqp channel closed")
+			}
+			handle(ctx, c.message(ch, &retries, d))
+		}
+	}
+}
+
+// message converts a delivery. Its ID is the message ID, or that of the
+// message it redelivers.
+func (c *AMQPConsumer) message(ch *amqp.Channel, retries *sync.WaitGroup, d amqp.Delivery) *Message {
+	m := &Message{
+		ID:      d.MessageId,
+		Subject: d.RoutingKey,
+		Headers: make(map[string]string, len(d.Headers)),
+		Body:    d.Body,
+		Attempt: 1,
+	}
+	if d.ContentType != "" {
+		m.Headers["Content-Type"] = d.ContentType
+	}
+
+	for k, v := range d.Headers {
+		value := fmt.Sprint(v)
+		switch k {
+		case headerMessageID:
+			m.ID = value
+		case headerAttempt:
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				m.Attempt = n
+			}
+		default:
+			m.Headers[k] = value
+		}
+	}
+	// Quorum queues count deliveries of requeued messages
+	if count, ok := d.Headers["x-delivery-count"].(int64); ok && m.Attempt == 1 {
+		m.Attempt = int(count) + 1
+	}
+
+	m.Acknowledger = &amqpAcknowledger{
+		consumer: c,
+		ch:       ch,
+		retries:  retries,
+		delivery: d,
+		id:       m.ID,
+		attempt:  m.Attempt,
+	}
+	return m
+}
+
+type amqpAcknowledger struct {
+	consumer *AMQPConsumer
+	ch       *amqp.Channel
+	retries  *sync.WaitGroup
+	delivery amqp.Delivery
+	id       string
+	attempt  int
+}
+
+func (a *amqpAcknowledger) Ack(_ context.Context) error {
+	return a.delivery.Ack(false)
+}
+
+// Nak publishes the message again after delay. Until then it is
+// unacknowledged, so if the consumer stops first the broker requeues it.
+func (a *amqpAcknowledger) Nak(ctx context.Context, delay time.Duration) error {
+	a.retries.Add(1)
+	go func() {
+		defer a.retries.Done()
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		headers := make(amqp.Table, len(a.delivery.Headers)+2)
+		for k, v := range a.delivery.Headers {
+			headers[k] = v
+		}
+		headers[headerMessageID] = a.id
+		headers[headerAttempt] = strconv.Itoa(a.attempt + 1)
+
+		err := a.ch.PublishWithContext(ctx, "", a.consumer.Queue, false, false, amqp.Publishing{
+			Headers:         headers,
+			ContentType:     a.delivery.ContentType,
+			ContentEncoding: a.delivery.ContentEncoding,
+			DeliveryMode:    amqp.Persistent,
+			MessageId:       a.delivery.MessageId,
+			Body:            a.delivery.Body,
+		})
+		if err != nil {
+			// Leave it to the broker to requeue the original
+			_ = a.delivery.Nack(false, true)
+			return
+		}
+		_ = a.delivery.Ack(false)
+	}()
+	return nil
+}
+
+func (a *amqpAcknowledger) Term(_ context.Context) error {
+	return a.delivery.Nack(false, false)
+}
+
+// InProgress does nothing: AMQP brokers wait for acknowledgements as long
+// as the consumer is connected.
+func (a *amqpAcknowledger) InProgress(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package queuetrigger starts workflows for messages consumed from message
+// buses: NATS JetStream, Redis Streams and AMQP 0.9.1.
+package queuetrigger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// Message is a message consumed from a broker.
+type Message struct {
+	// ID identifies the message across redeliveries. It is the idempotency
+	// key of the message's trigger event. Empty if the broker gives none
+	ID string
+
+	// Subject is the NATS subject, Redis stream or AMQP routing key the
+	// message was published to
+	Subject string
+
+	// Headers are the message headers
+	Headers map[string]string
+
+	// Body is the message payload
+	Body []byte
+
+	// Attempt is the delivery attempt, starting at 1
+	Attempt int
+
+	// Acknowledger settles the message with the broker
+	Acknowledger
+}
+
+// Acknowledger settles a message with its broker.
+type Acknowledger interface {
+	// Ack removes the message from the queue.
+	Ack(ctx context.Context) error
+
+	// Nak redelivers the message after delay, as attempt Attempt+1.
+	Nak(ctx context.Context, delay time.Duration) error
+
+	// Term gives up on the message. Brokers with a dead-letter queue
+	// route it there.
+	Term(ctx context.Context) error
+
+	// InProgress tells the broker the message is still being worked on,
+	// so it is not handed to another consumer after the ack wait.
+	InProgress(ctx context.Context) error
+}
+
+// Handler processes a consumed message. It settles the message itself.
+type Handler func(ctx context.Context, msg *Message)
+
+// Consumer receives the messages of one queue trigger.
+type Consumer interface {
+	// Consume connects to the broker and passes messages to handle, one at
+	// a time, until ctx is done. It returns an error if the connection
+	// fails or is lost.
+	Consume(ctx context.Context, handle Handler) error
+}
+
+// NewConsumer creates the consumer for a queue trigger. group is the
+// consumer group, and name identifies this consumer within it.
+func NewConsumer(cfg *workflow.QueueTriggerConfig, group, name string) (Consumer, error) {
+	url := trigger.ExpandSecret(cfg.URL)
+	if url == "" {
+		return nil, fmt.Errorf("%s url is not set: %s", cfg.Broker, cfg.URL)
+	}
+
+	switch cfg.Broker {
+	case workflow.QueueBrokerNATS:
+		return &NATSConsumer{
+			URL:     url,
+			Stream:  cfg.Stream,
+			Subject: cfg.Subject,
+			Durable: group,
+			AckWait: cfg.AckWaitDuration(),
+		}, nil
+	case workflow.QueueBrokerRedis:
+		return &RedisConsumer{
+			URL:      url,
+			Stream:   cfg.Stream,
+			Group:    group,
+			Consumer: name,
+			AckWait:  cfg.AckWaitDuration(),
+		}, nil
+	case workflow.QueueBrokerAMQP:
+		return &AMQPConsumer{
+			URL:      url,
+			Queue:    cfg.Queue,
+			Consumer: name,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported broker: %s", cfg.Broker)
+	}
+}
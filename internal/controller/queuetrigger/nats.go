@@ -0,0 +1,135 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuetrigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSConsumer consumes a JetStream stream through a durable pull consumer.
+// Controllers with the same durable name share its messages.
+type NATSConsumer struct {
+	URL     string
+	Stream  string // Defaults to the stream that holds Subject
+	Subject string // Filters the stream's subjects
+	Durable string
+	AckWait time.Duration
+}
+
+// Consume implements Consumer.
+func (c *NATSConsumer) Consume(ctx context.Context, handle Handler) error {
+	nc, err := nats.Connect(c.URL, nats.Name("conductor"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return fmt.Errorf("failed to open jetstream: %w", err)
+	}
+
+	stream := c.Stream
+	if stream == "" {
+		stream, err = js.StreamNameBySubject(ctx, c.Subject)
+		if err != nil {
+			return fmt.Errorf("failed to find stream for subject %s: %w", c.Subject, err)
+		}
+	}
+
+	// Redelivery is left to the trigger's policy, which terminates
+	// messages after their last attempt
+	consumer, err := js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:       c.Durable,
+		FilterSubject: c.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       c.AckWait,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s on stream %s: %w", c.Durable, stream, err)
+	}
+
+	// Fetch one message at a time so others are free for the group while
+	// this one is handled
+	iter, err := consumer.Messages(jetstream.PullMaxMessages(1))
+	if err != nil {
+		return fmt.Errorf("failed to consume stream %s: %w", stream, err)
+	}
+	stop := context.AfterFunc(ctx, iter.Stop)
+	defer stop()
+
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to receive message: %w", err)
+		}
+		handle(ctx, natsMessage(msg))
+	}
+}
+
+// natsMessage converts a JetStream message. Its ID is the publisher's
+// Nats-Msg-Id, or its position in the stream.
+func natsMessage(msg jetstream.Msg) *Message {
+	m := &Message{
+		Subject:      msg.Subject(),
+		Headers:      make(map[string]string, len(msg.Headers())),
+		Body:         msg.Data(),
+		Attempt:      1,
+		Acknowledger: natsAcknowledger{msg},
+	}
+	for k, v := range msg.Headers() {
+		if len(v) > 0 {
+			m.Headers[k] = v[0]
+		}
+	}
+
+	if meta, err := msg.Metadata(); err == nil {
+		m.ID = fmt.Sprintf("%s:%d", meta.Stream, meta.Sequence.Stream)
+		m.Attempt = int(meta.NumDelivered)
+	}
+	if id := msg.Headers().Get(jetstream.MsgIDHeader); id != "" {
+		m.ID = id
+	}
+	return m
+}
+
+type natsAcknowledger struct {
+	msg jetstream.Msg
+}
+
+func (a natsAcknowledger) Ack(ctx context.Context) error {
+	return a.msg.DoubleAck(ctx)
+}
+
+func (a natsAcknowledger) Nak(_ context.Context, delay time.Duration) error {
+	return a.msg.NakWithDelay(delay)
+}
+
+func (a natsAcknowledger) Term(_ context.Context) error {
+	return a.msg.Term()
+}
+
+func (a natsAcknowledger) InProgress(_ context.Context) error {
+	return a.msg.InProgress()
+}
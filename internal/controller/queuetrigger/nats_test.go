@@ -0,0 +1,142 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuetrigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// runJetStream starts an embedded NATS server with JetStream and a stream
+// named ORDERS for orders.>, and returns its URL.
+func runJetStream(t *testing.T) string {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:     "ORDERS",
+		Subjects: []string{"orders.>"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return srv.ClientURL()
+}
+
+func publish(t *testing.T, url string, msg *nats.Msg) {
+	t.Helper()
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := js.PublishMsg(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNATSConsumer_Consume(t *testing.T) {
+	url := runJetStream(t)
+	consumer := &NATSConsumer{URL: url, Subject: "orders.created", Durable: "orders", AckWait: 5 * time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	received := make(chan *Message, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Consume(ctx, func(ctx context.Context, msg *Message) {
+			// The first delivery fails and is redelivered
+			if msg.Attempt == 1 {
+				_ = msg.Nak(ctx, 10*time.Millisecond)
+			} else {
+				_ = msg.Ack(ctx)
+			}
+			received <- msg
+		})
+	}()
+
+	msg := nats.NewMsg("orders.created")
+	msg.Header.Set(jetstream.MsgIDHeader, "order-1")
+	msg.Header.Set("X-Tenant", "acme")
+	msg.Data = []byte(`{"id": "order-1"}`)
+	publish(t, url, msg)
+
+	var got []*Message
+	for len(got) < 2 {
+		select {
+		case m := <-received:
+			got = append(got, m)
+		case <-ctx.Done():
+			t.Fatalf("received %d messages, want 2", len(got))
+		}
+	}
+
+	first, second := got[0], got[1]
+	if first.ID != "order-1" || first.Subject != "orders.created" || first.Headers["X-Tenant"] != "acme" || string(first.Body) != `{"id": "order-1"}` {
+		t.Errorf("message = %+v", first)
+	}
+	if first.Attempt != 1 || second.Attempt != 2 || second.ID != first.ID {
+		t.Errorf("attempts = %d, %d with IDs %q, %q, want 1 and 2 with one ID", first.Attempt, second.Attempt, first.ID, second.ID)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Consume() error = %v", err)
+	}
+}
+
+func TestNATSConsumer_UnknownSubject(t *testing.T) {
+	url := runJetStream(t)
+	consumer := &NATSConsumer{URL: url, Subject: "invoices.created", Durable: "invoices", AckWait: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := consumer.Consume(ctx, func(context.Context, *Message) {}); err == nil {
+		t.Error("Consume() error = nil, want no stream for subject")
+	}
+}
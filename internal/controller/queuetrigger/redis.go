@@ -0,0 +1,234 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuetrigger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Fields and headers that carry a message's identity and attempt across
+// redeliveries on brokers that redeliver by publishing the message again.
+const (
+	headerMessageID = "X-Conductor-Message-Id"
+	headerAttempt   = "X-Conductor-Attempt"
+)
+
+// redisBlock is how long a read waits for new entries.
+const redisBlock = 5 * time.Second
+
+// RedisConsumer consumes a Redis stream through a consumer group.
+//
+// An entry's body field is the message body and its other fields are the
+// headers. Entries without a body field are passed as a JSON object of
+// their fields. Entries left pending longer than AckWait by a consumer that
+// went away are claimed by another consumer of the group.
+//
+// Redis cannot delay a redelivery, so a failed entry is added to the stream
+// again once its backoff has passed, and the original is acknowledged.
+type RedisConsumer struct {
+	URL      string
+	Stream   string
+	Group    string
+	Consumer string
+	AckWait  time.Duration
+}
+
+// Consume implements Consumer.
+func (c *RedisConsumer) Consume(ctx context.Context, handle Handler) error {
+	opts, err := redis.ParseURL(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	// The group starts at new entries, like a new JetStream consumer
+	err = client.XGroupCreateMkStream(ctx, c.Stream, c.Group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on stream %s: %w", c.Group, c.Stream, err)
+	}
+
+	var retries sync.WaitGroup
+	defer retries.Wait()
+
+	for {
+		entries, err := c.next(ctx, client)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			handle(ctx, c.message(client, &retries, entry))
+		}
+	}
+}
+
+// next returns an entry abandoned by another consumer, or waits for a new one.
+func (c *RedisConsumer) next(ctx context.Context, client *redis.Client) ([]redis.XMessage, error) {
+	claimed, _, err := client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   c.Stream,
+		Group:    c.Group,
+		Consumer: c.Consumer,
+		MinIdle:  c.AckWait,
+		Start:    "0-0",
+		Count:    1,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending entries: %w", err)
+	}
+	if len(claimed) > 0 {
+		return claimed, nil
+	}
+
+	streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.Group,
+		Consumer: c.Consumer,
+		Streams:  []string{c.Stream, ">"},
+		Count:    1,
+		Block:    redisBlock,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream %s: %w", c.Stream, err)
+	}
+
+	var entries []redis.XMessage
+	for _, stream := range streams {
+		entries = append(entries, stream.Messages...)
+	}
+	return entries, nil
+}
+
+// message converts a stream entry. Its ID is the entry ID, or that of the
+// entry it redelivers.
+func (c *RedisConsumer) message(client *redis.Client, retries *sync.WaitGroup, entry redis.XMessage) *Message {
+	m := &Message{
+		ID:      entry.ID,
+		Subject: c.Stream,
+		Headers: make(map[string]string),
+		Attempt: 1,
+	}
+
+	body, hasBody := entry.Values["body"]
+	for k, v := range entry.Values {
+		value := fmt.Sprint(v)
+		switch k {
+		case "body":
+		case headerMessageID:
+			m.ID = value
+		case headerAttempt:
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				m.Attempt = n
+			}
+		default:
+			if hasBody {
+				m.Headers[k] = value
+			}
+		}
+	}
+
+	if hasBody {
+		m.Body = []byte(fmt.Sprint(body))
+	} else {
+		fields := make(map[string]any, len(entry.Values))
+		for k, v := range entry.Values {
+			if k != headerMessageID && k != headerAttempt {
+				fields[k] = v
+			}
+		}
+		m.Body, _ = json.Marshal(fields)
+	}
+
+	m.Acknowledger = &redisAcknowledger{
+		consumer: c,
+		client:   client,
+		retries:  retries,
+		entry:    entry,
+		id:       m.ID,
+		attempt:  m.Attempt,
+	}
+	return m
+}
+
+type redisAcknowledger struct {
+	consumer *RedisConsumer
+	client   *redis.Client
+	retries  *sync.WaitGroup
+	entry    redis.XMessage
+	id       string
+	attempt  int
+}
+
+func (a *redisAcknowledger) Ack(ctx context.Context) error {
+	return a.client.XAck(ctx, a.consumer.Stream, a.consumer.Group, a.entry.ID).Err()
+}
+
+// Nak adds the entry again after delay. Until then it stays pending, so if
+// the consumer stops first it is claimed by another consumer after the ack
+// wait.
+func (a *redisAcknowledger) Nak(ctx context.Context, delay time.Duration) error {
+	a.retries.Add(1)
+	go func() {
+		defer a.retries.Done()
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		values := make(map[string]any, len(a.entry.Values)+2)
+		for k, v := range a.entry.Values {
+			values[k] = v
+		}
+		values[headerMessageID] = a.id
+		values[headerAttempt] = a.attempt + 1
+
+		_, _ = a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: a.consumer.Stream, Values: values})
+			pipe.XAck(ctx, a.consumer.Stream, a.consumer.Group, a.entry.ID)
+			return nil
+		})
+	}()
+	return nil
+}
+
+// Term acknowledges the entry. Redis has no dead-letter queue; the failed
+// delivery stays in the trigger event log.
+func (a *redisAcknowledger) Term(ctx context.Context) error {
+	return a.Ack(ctx)
+}
+
+// InProgress claims the entry again, which resets its idle time.
+func (a *redisAcknowledger) InProgress(ctx context.Context) error {
+	return a.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   a.consumer.Stream,
+		Group:    a.consumer.Group,
+		Consumer: a.consumer.Consumer,
+		Messages: []string{a.entry.ID},
+	}).Err()
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package queuetrigger
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tombee/conductor/internal/testing/integration"
+)
+
+// Run against a local broker container:
+//
+//	docker run -d -p 6379:6379 redis:7
+//	REDIS_URL=redis://localhost:6379/0 go test -tags integration ./internal/controller/queuetrigger/
+func TestRedisConsumer_Consume(t *testing.T) {
+	integration.SkipWithoutEnv(t, "REDIS_URL")
+	url := integration.LoadConfig().RedisURL
+
+	stream := fmt.Sprintf("conductor-test-%d", time.Now().UnixNano())
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(opts)
+	defer client.Close()
+	t.Cleanup(func() { client.Del(context.Background(), stream) })
+
+	consumer := &RedisConsumer{URL: url, Stream: stream, Group: "orders", Consumer: "test", AckWait: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	received := make(chan *Message, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Consume(ctx, func(ctx context.Context, msg *Message) {
+			// The first delivery fails and is redelivered
+			if msg.Attempt == 1 {
+				_ = msg.Nak(ctx, 10*time.Millisecond)
+			} else {
+				_ = msg.Ack(ctx)
+			}
+			received <- msg
+		})
+	}()
+
+	// The group starts at new entries, so wait for it before adding one
+	for {
+		groups, _ := client.XInfoGroups(ctx, stream).Result()
+		if len(groups) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]any{"body": `{"id": "order-1"}`, "X-Tenant": "acme"},
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*Message
+	for len(got) < 2 {
+		select {
+		case m := <-received:
+			got = append(got, m)
+		case <-ctx.Done():
+			t.Fatalf("received %d messages, want 2", len(got))
+		}
+	}
+
+	first, second := got[0], got[1]
+	if first.Headers["X-Tenant"] != "acme" || string(first.Body) != `{"id": "order-1"}` {
+		t.Errorf("message = %+v", first)
+	}
+	if second.Attempt != 2 || second.ID != first.ID || second.Headers["X-Tenant"] != "acme" {
+		t.Errorf("redelivery = %+v, want attempt 2 of %s", second, first.ID)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Consume() error = %v", err)
+	}
+
+	pending, err := client.XPending(context.Background(), stream, "orders").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Count != 0 {
+		t.Errorf("pending = %d, want 0", pending.Count)
+	}
+}
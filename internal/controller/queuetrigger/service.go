@@ -0,0 +1,385 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuetrigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+const (
+	// reconnectDelay is the wait before reconnecting a consumer whose
+	// connection failed or was lost.
+	reconnectDelay = 5 * time.Second
+
+	// runPollInterval is how often a run is checked while its message
+	// waits for the run to complete.
+	runPollInterval = time.Second
+)
+
+// Events records trigger events and starts their runs. It is implemented
+// by *trigger.EventLog.
+type Events interface {
+	Deliver(ctx context.Context, event *backend.TriggerEvent) (*backend.TriggerEvent, bool, error)
+	Redeliver(ctx context.Context, id string) (*backend.TriggerEvent, error)
+}
+
+// RunLookup finds runs by ID. It is implemented by *runner.Runner.
+type RunLookup interface {
+	Get(id string) (*runner.RunSnapshot, error)
+}
+
+// ConsumerFactory creates the consumer for a queue trigger.
+type ConsumerFactory func(cfg *workflow.QueueTriggerConfig, group, name string) (Consumer, error)
+
+// Config configures a Service.
+type Config struct {
+	// Events delivers messages to their workflows
+	Events Events
+
+	// Runs looks up the runs of triggers that acknowledge on completion
+	Runs RunLookup
+
+	// WorkflowsDir is where workflow files are looked up by name
+	WorkflowsDir string
+
+	// ConsumerName identifies this controller within consumer groups
+	ConsumerName string
+
+	// NewConsumer creates consumers. Default: NewConsumer
+	NewConsumer ConsumerFactory
+
+	Logger *slog.Logger
+}
+
+// Service consumes the messages of queue triggers and starts a workflow run
+// for each. Each message is delivered through the event log, so it is
+// recorded and deduplicated by message ID. A message is acknowledged once
+// its run is started, or once it completes for triggers with ack:
+// completed. Messages that fail are redelivered with backoff until their
+// trigger's attempts run out.
+type Service struct {
+	events       Events
+	runs         RunLookup
+	workflowsDir string
+	consumerName string
+	newConsumer  ConsumerFactory
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	triggers []trigger.WorkflowTrigger
+	ctx      context.Context // Set while running
+	cancel   context.CancelFunc
+	done     sync.WaitGroup
+}
+
+// NewService creates a queue trigger service.
+func NewService(cfg Config) *Service {
+	if cfg.NewConsumer == nil {
+		cfg.NewConsumer = NewConsumer
+	}
+	if cfg.ConsumerName == "" {
+		cfg.ConsumerName = "conductor"
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Service{
+		events:       cfg.Events,
+		runs:         cfg.Runs,
+		workflowsDir: cfg.WorkflowsDir,
+		consumerName: cfg.ConsumerName,
+		newConsumer:  cfg.NewConsumer,
+		logger:       cfg.Logger,
+	}
+}
+
+// Register replaces the registered queue triggers. If the service is
+// running, its consumers are restarted.
+func (s *Service) Register(triggers []trigger.WorkflowTrigger) {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if ctx != nil {
+		s.Stop()
+	}
+
+	s.mu.Lock()
+	s.triggers = nil
+	for _, t := range triggers {
+		if t.Queue != nil {
+			s.triggers = append(s.triggers, t)
+		}
+	}
+	s.mu.Unlock()
+
+	if ctx != nil {
+		s.Start(ctx)
+	}
+}
+
+// Count returns the number of registered queue triggers.
+func (s *Service) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.triggers)
+}
+
+// Start starts a consumer for each registered trigger. Consumers reconnect
+// until the service is stopped or ctx is done.
+func (s *Service) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+
+	s.ctx = ctx
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, t := range s.triggers {
+		s.done.Add(1)
+		go func() {
+			defer s.done.Done()
+			s.consume(ctx, t)
+		}()
+	}
+}
+
+// Stop stops the consumers and waits for them to return. Messages still
+// being handled are left unacknowledged, so the broker redelivers them.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if s.ctx == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.ctx = nil
+	s.cancel()
+	s.mu.Unlock()
+
+	s.done.Wait()
+}
+
+// consume runs a trigger's consumer until ctx is done.
+func (s *Service) consume(ctx context.Context, t trigger.WorkflowTrigger) {
+	group := t.Queue.Group
+	if group == "" {
+		group = t.WorkflowName
+	}
+	logger := s.logger.With(
+		slog.String("workflow", t.WorkflowName),
+		slog.String("trigger", t.TriggerID()),
+		slog.String("broker", t.Queue.Broker))
+
+	consumer, err := s.newConsumer(t.Queue, group, s.consumerName)
+	if err != nil {
+		logger.Error("failed to create queue consumer", slog.String("error", err.Error()))
+		return
+	}
+
+	for {
+		logger.Info("queue trigger consuming", slog.String("group", group))
+		err := consumer.Consume(ctx, func(ctx context.Context, msg *Message) {
+			s.handle(ctx, t, msg)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Warn("queue consumer disconnected", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handle delivers a message to its workflow and settles it.
+func (s *Service) handle(ctx context.Context, t trigger.WorkflowTrigger, msg *Message) {
+	q := t.Queue
+	event := &backend.TriggerEvent{
+		Source:         trigger.SourceQueue,
+		Trigger:        t.EventName(),
+		Workflow:       trigger.WorkflowName(s.workflowsDir, t.WorkflowPath),
+		Event:          msg.Subject,
+		IdempotencyKey: msg.ID,
+		Headers:        trigger.RecordedMessageHeaders(msg.Headers),
+		Payload:        trigger.PayloadJSON(msg.Body),
+		Inputs:         messageInputs(t, msg),
+	}
+
+	stored, duplicate, err := s.events.Deliver(ctx, event)
+	if err != nil {
+		s.retry(ctx, t, msg, err)
+		return
+	}
+	if q.AckMode() == workflow.QueueAckSubmitted || stored.RunID == "" {
+		s.ack(ctx, t, msg)
+		return
+	}
+
+	// A redelivered message whose run failed starts a new run
+	if duplicate {
+		if run, err := s.runs.Get(stored.RunID); err == nil && run.Status != runner.RunStatusCompleted && finished(run.Status) {
+			stored, err = s.events.Redeliver(ctx, stored.ID)
+			if err != nil {
+				s.retry(ctx, t, msg, err)
+				return
+			}
+		}
+	}
+
+	status, err := s.wait(ctx, t, msg, stored.RunID)
+	if ctx.Err() != nil {
+		// Left unacknowledged for the broker to redeliver
+		return
+	}
+	if err == nil && status != runner.RunStatusCompleted {
+		err = fmt.Errorf("run %s %s", stored.RunID, status)
+	}
+	if err != nil {
+		s.retry(ctx, t, msg, err)
+		return
+	}
+	s.ack(ctx, t, msg)
+}
+
+// wait waits for a run to finish and returns its status. The message is
+// kept in progress meanwhile, so the broker does not redeliver it.
+func (s *Service) wait(ctx context.Context, t trigger.WorkflowTrigger, msg *Message, runID string) (runner.RunStatus, error) {
+	ticker := time.NewTicker(runPollInterval)
+	defer ticker.Stop()
+
+	ackWait := t.Queue.AckWaitDuration()
+	lastProgress := time.Now()
+	for {
+		run, err := s.runs.Get(runID)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up run %s: %w", runID, err)
+		}
+		if finished(run.Status) {
+			return run.Status, nil
+		}
+
+		if time.Since(lastProgress) >= ackWait/2 {
+			if err := msg.InProgress(ctx); err != nil {
+				s.logger.Warn("failed to extend queue message ack wait",
+					slog.String("workflow", t.WorkflowName),
+					slog.String("message_id", msg.ID),
+					slog.String("error", err.Error()))
+			}
+			lastProgress = time.Now()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// retry redelivers a failed message after its backoff, or gives up on it
+// after its last attempt.
+func (s *Service) retry(ctx context.Context, t trigger.WorkflowTrigger, msg *Message, cause error) {
+	q := t.Queue
+	logger := s.logger.With(
+		slog.String("workflow", t.WorkflowName),
+		slog.String("trigger", t.TriggerID()),
+		slog.String("message_id", msg.ID),
+		slog.Int("attempt", msg.Attempt),
+		slog.String("error", cause.Error()))
+
+	if msg.Attempt >= q.MaxAttempts() {
+		logger.Warn("queue message failed on its last attempt")
+		if err := msg.Term(ctx); err != nil {
+			logger.Warn("failed to terminate queue message", slog.String("term_error", err.Error()))
+		}
+		return
+	}
+
+	delay := q.RetryDelay(msg.Attempt)
+	logger.Info("queue message failed, redelivering", slog.Duration("delay", delay))
+	if err := msg.Nak(ctx, delay); err != nil {
+		logger.Warn("failed to redeliver queue message", slog.String("nak_error", err.Error()))
+	}
+}
+
+func (s *Service) ack(ctx context.Context, t trigger.WorkflowTrigger, msg *Message) {
+	if err := msg.Ack(ctx); err != nil {
+		s.logger.Warn("failed to acknowledge queue message",
+			slog.String("workflow", t.WorkflowName),
+			slog.String("message_id", msg.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// messageInputs maps a message to the inputs of its run. Mapping paths
+// start at the message: $.body, $.headers, $.subject and $.id.
+func messageInputs(t trigger.WorkflowTrigger, msg *Message) map[string]any {
+	var body any
+	if err := json.Unmarshal(msg.Body, &body); err != nil {
+		body = string(msg.Body)
+	}
+
+	var inputs map[string]any
+	if t.Queue.InputMapping == nil {
+		fields, _ := body.(map[string]any)
+		inputs = trigger.MapInputs(fields, nil, t.Queue.Inputs)
+	} else {
+		headers := make(map[string]any, len(msg.Headers))
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		inputs = trigger.MapInputs(map[string]any{
+			"body":    body,
+			"headers": headers,
+			"subject": msg.Subject,
+			"id":      msg.ID,
+		}, t.Queue.InputMapping, t.Queue.Inputs)
+	}
+
+	inputs["trigger"] = map[string]any{
+		"type":       string(workflow.TriggerTypeQueue),
+		"id":         t.TriggerID(),
+		"broker":     t.Queue.Broker,
+		"subject":    msg.Subject,
+		"message_id": msg.ID,
+		"attempt":    msg.Attempt,
+	}
+	return inputs
+}
+
+// finished reports whether a run has reached a final status.
+func finished(status runner.RunStatus) bool {
+	switch status {
+	case runner.RunStatusCompleted, runner.RunStatusFailed, runner.RunStatusCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuetrigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// fakeEvents delivers events like the event log, starting a run per
+// delivery with the status in runStatus.
+type fakeEvents struct {
+	mu         sync.Mutex
+	deliverErr error
+	runStatus  runner.RunStatus
+	events     map[string]*backend.TriggerEvent // By idempotency key
+	runs       map[string]*runner.RunSnapshot
+	delivered  []*backend.TriggerEvent
+}
+
+func newFakeEvents() *fakeEvents {
+	return &fakeEvents{
+		runStatus: runner.RunStatusCompleted,
+		events:    make(map[string]*backend.TriggerEvent),
+		runs:      make(map[string]*runner.RunSnapshot),
+	}
+}
+
+func (f *fakeEvents) Deliver(_ context.Context, event *backend.TriggerEvent) (*backend.TriggerEvent, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if prev, ok := f.events[event.IdempotencyKey]; ok && prev.Status != backend.TriggerEventFailed {
+		return prev, true, nil
+	}
+	event.ID = fmt.Sprintf("event-%d", len(f.events)+1)
+	f.events[event.IdempotencyKey] = event
+	if f.deliverErr != nil {
+		event.Status = backend.TriggerEventFailed
+		return event, false, f.deliverErr
+	}
+	f.start(event)
+	return event, false, nil
+}
+
+func (f *fakeEvents) Redeliver(_ context.Context, id string) (*backend.TriggerEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, event := range f.events {
+		if event.ID == id {
+			f.start(event)
+			return event, nil
+		}
+	}
+	return nil, errors.New("event not found")
+}
+
+func (f *fakeEvents) start(event *backend.TriggerEvent) {
+	event.Status = backend.TriggerEventDelivered
+	event.RunID = fmt.Sprintf("run-%d", len(f.runs)+1)
+	f.runs[event.RunID] = &runner.RunSnapshot{ID: event.RunID, Status: f.runStatus}
+	f.delivered = append(f.delivered, event)
+}
+
+func (f *fakeEvents) Get(id string) (*runner.RunSnapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if run, ok := f.runs[id]; ok {
+		return run, nil
+	}
+	return nil, fmt.Errorf("run not found: %s", id)
+}
+
+// fakeAcknowledger records how a message was settled.
+type fakeAcknowledger struct {
+	acked  bool
+	termed bool
+	naks   []time.Duration
+}
+
+func (a *fakeAcknowledger) Ack(context.Context) error { a.acked = true; return nil }
+func (a *fakeAcknowledger) Nak(_ context.Context, delay time.Duration) error {
+	a.naks = append(a.naks, delay)
+	return nil
+}
+func (a *fakeAcknowledger) Term(context.Context) error       { a.termed = true; return nil }
+func (a *fakeAcknowledger) InProgress(context.Context) error { return nil }
+
+func newTestService(events *fakeEvents) *Service {
+	return NewService(Config{Events: events, Runs: events, WorkflowsDir: "/workflows"})
+}
+
+func testTrigger(q *workflow.QueueTriggerConfig) trigger.WorkflowTrigger {
+	return trigger.WorkflowTrigger{
+		WorkflowPath: filepath.Join("/workflows", "orders.yaml"),
+		WorkflowName: "orders",
+		Type:         workflow.TriggerTypeQueue,
+		Queue:        q,
+	}
+}
+
+func testMessage(id string, attempt int) (*Message, *fakeAcknowledger) {
+	ack := &fakeAcknowledger{}
+	return &Message{
+		ID:           id,
+		Subject:      "orders.created",
+		Headers:      map[string]string{"X-Tenant": "acme", "Authorization": "Bearer secret"},
+		Body:         []byte(`{"id": "order-1", "total": 42}`),
+		Attempt:      attempt,
+		Acknowledger: ack,
+	}, ack
+}
+
+func TestService_HandleSubmitted(t *testing.T) {
+	events := newFakeEvents()
+	svc := newTestService(events)
+	trig := testTrigger(&workflow.QueueTriggerConfig{
+		Broker:       workflow.QueueBrokerNATS,
+		InputMapping: map[string]string{"order_id": "$.body.id", "tenant": "$.headers.X-Tenant"},
+		Inputs:       map[string]any{"priority": "normal"},
+	})
+
+	msg, ack := testMessage("msg-1", 1)
+	svc.handle(context.Background(), trig, msg)
+
+	if !ack.acked {
+		t.Fatal("message not acknowledged")
+	}
+	if len(events.delivered) != 1 {
+		t.Fatalf("delivered %d events, want 1", len(events.delivered))
+	}
+	event := events.delivered[0]
+	if event.Source != trigger.SourceQueue || event.Trigger != "queue:orders" || event.Workflow != "orders" || event.IdempotencyKey != "msg-1" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Headers["Authorization"] != "[REDACTED]" {
+		t.Errorf("Authorization header = %q, want redacted", event.Headers["Authorization"])
+	}
+	if event.Inputs["order_id"] != "order-1" || event.Inputs["tenant"] != "acme" || event.Inputs["priority"] != "normal" {
+		t.Errorf("Inputs = %v", event.Inputs)
+	}
+	if tc, _ := event.Inputs["trigger"].(map[string]any); tc["type"] != "queue" || tc["message_id"] != "msg-1" {
+		t.Errorf("trigger input = %v", event.Inputs["trigger"])
+	}
+
+	// A redelivered message does not start a second run
+	msg, ack = testMessage("msg-1", 2)
+	svc.handle(context.Background(), trig, msg)
+	if !ack.acked || len(events.delivered) != 1 {
+		t.Errorf("duplicate: acked = %v, delivered = %d, want acked and 1", ack.acked, len(events.delivered))
+	}
+}
+
+func TestService_HandleUnmappedBody(t *testing.T) {
+	events := newFakeEvents()
+	svc := newTestService(events)
+
+	msg, _ := testMessage("msg-1", 1)
+	svc.handle(context.Background(), testTrigger(&workflow.QueueTriggerConfig{Broker: workflow.QueueBrokerNATS}), msg)
+
+	inputs := events.delivered[0].Inputs
+	if inputs["id"] != "order-1" || inputs["total"] != float64(42) {
+		t.Errorf("Inputs = %v, want the body fields", inputs)
+	}
+}
+
+func TestService_HandleRetry(t *testing.T) {
+	events := newFakeEvents()
+	events.deliverErr = trigger.ErrDraining
+	svc := newTestService(events)
+	trig := testTrigger(&workflow.QueueTriggerConfig{
+		Broker:     workflow.QueueBrokerNATS,
+		Redelivery: &workflow.QueueRedelivery{MaxAttempts: 3, Backoff: "2s"},
+	})
+
+	msg, ack := testMessage("msg-1", 2)
+	svc.handle(context.Background(), trig, msg)
+	if ack.acked || ack.termed || len(ack.naks) != 1 || ack.naks[0] != 4*time.Second {
+		t.Errorf("attempt 2: acked = %v, termed = %v, naks = %v, want one 4s nak", ack.acked, ack.termed, ack.naks)
+	}
+
+	msg, ack = testMessage("msg-1", 3)
+	svc.handle(context.Background(), trig, msg)
+	if !ack.termed || len(ack.naks) != 0 {
+		t.Errorf("last attempt: termed = %v, naks = %v, want terminated", ack.termed, ack.naks)
+	}
+}
+
+func TestService_HandleCompleted(t *testing.T) {
+	events := newFakeEvents()
+	events.runStatus = runner.RunStatusFailed
+	svc := newTestService(events)
+	trig := testTrigger(&workflow.QueueTriggerConfig{
+		Broker: workflow.QueueBrokerNATS,
+		Ack:    workflow.QueueAckCompleted,
+	})
+
+	msg, ack := testMessage("msg-1", 1)
+	svc.handle(context.Background(), trig, msg)
+	if ack.acked || len(ack.naks) != 1 {
+		t.Fatalf("failed run: acked = %v, naks = %v, want a nak", ack.acked, ack.naks)
+	}
+
+	// The redelivered message starts a new run, which completes
+	events.runStatus = runner.RunStatusCompleted
+	msg, ack = testMessage("msg-1", 2)
+	svc.handle(context.Background(), trig, msg)
+	if !ack.acked {
+		t.Errorf("completed run: message not acknowledged")
+	}
+	if len(events.delivered) != 2 {
+		t.Errorf("started %d runs, want 2", len(events.delivered))
+	}
+}
+
+// fakeConsumer passes its messages to the handler, then waits for ctx.
+type fakeConsumer struct {
+	messages []*Message
+}
+
+func (c *fakeConsumer) Consume(ctx context.Context, handle Handler) error {
+	for _, msg := range c.messages {
+		handle(ctx, msg)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestService_StartStop(t *testing.T) {
+	events := newFakeEvents()
+	msg, ack := testMessage("msg-1", 1)
+
+	var group, name string
+	svc := NewService(Config{
+		Events:       events,
+		Runs:         events,
+		ConsumerName: "controller-1",
+		NewConsumer: func(_ *workflow.QueueTriggerConfig, g, n string) (Consumer, error) {
+			group, name = g, n
+			return &fakeConsumer{messages: []*Message{msg}}, nil
+		},
+	})
+	svc.Register([]trigger.WorkflowTrigger{
+		testTrigger(&workflow.QueueTriggerConfig{Broker: workflow.QueueBrokerNATS}),
+		{WorkflowName: "webhook-only", Type: workflow.TriggerTypeWebhook},
+	})
+	if svc.Count() != 1 {
+		t.Fatalf("Count() = %d, want 1", svc.Count())
+	}
+
+	svc.Start(context.Background())
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		events.mu.Lock()
+		delivered := len(events.delivered)
+		events.mu.Unlock()
+		if delivered > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	svc.Stop()
+
+	if !ack.acked {
+		t.Error("message not handled")
+	}
+	if group != "orders" || name != "controller-1" {
+		t.Errorf("consumer group = %q, name = %q, want the workflow name and controller-1", group, name)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/tombee/conductor/internal/config"
+	"github.com/tombee/conductor/internal/controller/auth"
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/tracing/audit"
+)
+
+// newAuthorizer creates the RBAC authorizer. Roles are stored in the
+// backend when it supports them, and denials are logged and, when audit
+// logging is enabled, written to the audit log.
+func newAuthorizer(cfg config.RBACConfig, be backend.Backend, auditLogger *audit.Logger, logger *slog.Logger) *auth.Authorizer {
+	authCfg := auth.AuthorizerConfig{
+		Bindings: cfg.Bindings,
+		Logger:   logger,
+		OnDeny: func(d auth.Denial) {
+			logger.Warn("access denied",
+				slog.String("user", d.UserID),
+				slog.String("action", string(d.Action)),
+				slog.String("resource", d.Resource.String()),
+				slog.String("path", d.Path))
+			if auditLogger != nil {
+				_ = auditLogger.Log(audit.Entry{
+					UserID:    d.UserID,
+					Action:    audit.Action("rbac:" + string(d.Action)),
+					Resource:  d.Resource.String(),
+					Result:    audit.ResultForbidden,
+					IPAddress: d.IPAddress,
+					Error:     fmt.Sprintf("%s denied on %s", d.Action, d.Path),
+				})
+			}
+		},
+	}
+	if cfg.DefaultRole != "" {
+		authCfg.DefaultRoles = []string{cfg.DefaultRole}
+	}
+	if store, ok := be.(backend.RoleStore); ok {
+		authCfg.Store = &roleStoreAdapter{store: store}
+	}
+	return auth.NewAuthorizer(authCfg)
+}
+
+// roleStoreAdapter adapts a backend.RoleStore to auth.RoleStore.
+type roleStoreAdapter struct {
+	store backend.RoleStore
+}
+
+func (a *roleStoreAdapter) GetRole(ctx context.Context, name string) (*auth.Role, error) {
+	role, err := a.store.GetRole(ctx, name)
+	if err != nil {
+		return nil, roleStoreError(err)
+	}
+	return toAuthRole(role), nil
+}
+
+func (a *roleStoreAdapter) ListRoles(ctx context.Context) ([]*auth.Role, error) {
+	roles, err := a.store.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*auth.Role, len(roles))
+	for i, role := range roles {
+		result[i] = toAuthRole(role)
+	}
+	return result, nil
+}
+
+func (a *roleStoreAdapter) SaveRole(ctx context.Context, role *auth.Role) error {
+	stored := &backend.Role{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: make([]backend.RolePermission, len(role.Permissions)),
+	}
+	for i, perm := range role.Permissions {
+		actions := make([]string, len(perm.Actions))
+		for j, action := range perm.Actions {
+			actions[j] = string(action)
+		}
+		stored.Permissions[i] = backend.RolePermission{
+			Actions:    actions,
+			Workflows:  perm.Workflows,
+			Workspaces: perm.Workspaces,
+		}
+	}
+	if err := a.store.SaveRole(ctx, stored); err != nil {
+		return err
+	}
+	role.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (a *roleStoreAdapter) DeleteRole(ctx context.Context, name string) error {
+	return roleStoreError(a.store.DeleteRole(ctx, name))
+}
+
+func (a *roleStoreAdapter) GetRoleBinding(ctx context.Context, subject string) (*auth.RoleBinding, error) {
+	binding, err := a.store.GetRoleBinding(ctx, subject)
+	if err != nil {
+		return nil, roleStoreError(err)
+	}
+	return toAuthRoleBinding(binding), nil
+}
+
+func (a *roleStoreAdapter) ListRoleBindings(ctx context.Context) ([]*auth.RoleBinding, error) {
+	bindings, err := a.store.ListRoleBindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*auth.RoleBinding, len(bindings))
+	for i, binding := range bindings {
+		result[i] = toAuthRoleBinding(binding)
+	}
+	return result, nil
+}
+
+func (a *roleStoreAdapter) SaveRoleBinding(ctx context.Context, binding *auth.RoleBinding) error {
+	stored := &backend.RoleBinding{Subject: binding.Subject, Roles: binding.Roles}
+	if err := a.store.SaveRoleBinding(ctx, stored); err != nil {
+		return err
+	}
+	binding.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (a *roleStoreAdapter) DeleteRoleBinding(ctx context.Context, subject string) error {
+	return roleStoreError(a.store.DeleteRoleBinding(ctx, subject))
+}
+
+// roleStoreError maps backend.ErrRoleNotFound to auth.ErrRoleNotFound.
+func roleStoreError(err error) error {
+	if errors.Is(err, backend.ErrRoleNotFound) {
+		return fmt.Errorf("%w: %v", auth.ErrRoleNotFound, err)
+	}
+	return err
+}
+
+func toAuthRole(role *backend.Role) *auth.Role {
+	result := &auth.Role{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: make([]auth.Permission, len(role.Permissions)),
+		UpdatedAt:   role.UpdatedAt,
+	}
+	for i, perm := range role.Permissions {
+		actions := make([]auth.Action, len(perm.Actions))
+		for j, action := range perm.Actions {
+			actions[j] = auth.Action(action)
+		}
+		result.Permissions[i] = auth.Permission{
+			Actions:    actions,
+			Workflows:  perm.Workflows,
+			Workspaces: perm.Workspaces,
+		}
+	}
+	return result
+}
+
+func toAuthRoleBinding(binding *backend.RoleBinding) *auth.RoleBinding {
+	return &auth.RoleBinding{
+		Subject:   binding.Subject,
+		Roles:     binding.Roles,
+		UpdatedAt: binding.UpdatedAt,
+	}
+}
@@ -16,6 +16,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -23,6 +24,10 @@ import (
 	"github.com/tombee/conductor/pkg/workflow"
 )
 
+// ErrPaused is returned by ExecuteWorkflow when execution stops at a step
+// boundary because ExecutionOptions.ShouldPause returned true.
+var ErrPaused = errors.New("run paused")
+
 // ExecutionAdapter bridges the controller Runner with workflow execution.
 // It provides a clean interface for executing workflows, allowing the Runner
 // to focus on orchestration while delegating step execution to the workflow package.
@@ -54,6 +59,19 @@ type ExecutionOptions struct {
 	// level is one of "debug", "info", "warn", "error".
 	OnLog func(level, message, stepID string)
 
+	// CompletedSteps holds the outputs of steps completed by an earlier
+	// attempt of the run, keyed by step ID. These steps are not executed again.
+	CompletedSteps map[string]map[string]any
+
+	// StepInputs holds edited inputs for steps, keyed by step ID. Entries
+	// replace values in the step's inputs; "prompt" and "system" replace
+	// the prompts of LLM steps.
+	StepInputs map[string]map[string]any
+
+	// ShouldPause is called before each step. If it returns true, execution
+	// stops and ExecuteWorkflow returns ErrPaused.
+	ShouldPause func() bool
+
 	// Runtime overrides
 	Provider   string        // Override provider for all LLM steps
 	Model      string        // Override model tier for all LLM steps
@@ -143,6 +161,25 @@ func (a *ExecutorAdapter) ExecuteWorkflow(ctx context.Context, def *workflow.Def
 		default:
 		}
 
+		// Restore steps completed by an earlier attempt
+		if output, ok := opts.CompletedSteps[step.ID]; ok {
+			workflowContext["steps"].(map[string]interface{})[step.ID] = output
+			templateCtx.SetStepOutput(step.ID, output)
+			result.StepOutputs[step.ID] = output
+			lastStepOutput = stepResultToOutput(&workflow.StepResult{StepID: step.ID, Status: workflow.StepStatusSuccess, Output: output})
+			if opts.OnLog != nil {
+				opts.OnLog("info", fmt.Sprintf("Step restored from checkpoint: %s", step.ID), step.ID)
+			}
+			continue
+		}
+
+		// Stop at the step boundary if a pause was requested
+		if opts.ShouldPause != nil && opts.ShouldPause() {
+			result.FinalError = ErrPaused
+			result.Duration = time.Since(startTime)
+			return result, ErrPaused
+		}
+
 		// Notify step start
 		if opts.OnStepStart != nil {
 			opts.OnStepStart(step.ID, i, totalSteps)
@@ -162,6 +199,14 @@ func (a *ExecutorAdapter) ExecuteWorkflow(ctx context.Context, def *workflow.Def
 			}
 		}
 
+		// Apply edited inputs from a step retry
+		if inputs, ok := opts.StepInputs[step.ID]; ok {
+			applyStepInputs(&stepToExecute, inputs)
+			if opts.OnLog != nil {
+				opts.OnLog("info", fmt.Sprintf("Applied edited inputs to step: %s", step.ID), step.ID)
+			}
+		}
+
 		// Apply timeout override by wrapping context with deadline
 		stepCtx := ctx
 		if opts.Timeout > 0 {
@@ -231,6 +276,33 @@ func (a *ExecutorAdapter) ExecuteWorkflow(ctx context.Context, def *workflow.Def
 	return result, nil
 }
 
+// applyStepInputs replaces a step's inputs with edited values. The step's
+// inputs map is copied so the workflow definition is not modified.
+func applyStepInputs(step *workflow.StepDefinition, inputs map[string]any) {
+	merged := make(map[string]interface{}, len(step.Inputs)+len(inputs))
+	for k, v := range step.Inputs {
+		merged[k] = v
+	}
+	for k, v := range inputs {
+		if step.Type == workflow.StepTypeLLM {
+			switch k {
+			case "prompt":
+				if prompt, ok := v.(string); ok {
+					step.Prompt = prompt
+					continue
+				}
+			case "system":
+				if system, ok := v.(string); ok {
+					step.System = system
+					continue
+				}
+			}
+		}
+		merged[k] = v
+	}
+	step.Inputs = merged
+}
+
 // stepResultToOutput converts a workflow.StepResult to a typed workflow.StepOutput.
 // This helper bridges the old map-based result format with the new typed format.
 func stepResultToOutput(result *workflow.StepResult) workflow.StepOutput {
@@ -522,3 +522,45 @@ func TestOutputConversionHelpers(t *testing.T) {
 	})
 
 }
+
+func TestExecutorAdapter_PauseAndStepInputs(t *testing.T) {
+	var prompts []string
+	provider := &MockLLMProvider{
+		CompleteFunc: func(ctx context.Context, prompt string, options map[string]interface{}) (*workflow.CompletionResult, error) {
+			prompts = append(prompts, prompt)
+			return &workflow.CompletionResult{Content: "ok", Model: "mock"}, nil
+		},
+	}
+	adapter := NewExecutorAdapter(workflow.NewExecutor(nil, provider))
+
+	def := &workflow.Definition{
+		Name: "test-workflow",
+		Steps: []workflow.StepDefinition{
+			{ID: "step1", Type: workflow.StepTypeLLM, Prompt: "original"},
+			{ID: "step2", Type: workflow.StepTypeLLM, Prompt: "second"},
+		},
+	}
+
+	paused := false
+	opts := ExecutionOptions{
+		StepInputs: map[string]map[string]any{"step1": {"prompt": "edited"}},
+		OnStepEnd: func(stepID string, result *workflow.StepResult, err error) {
+			paused = true
+		},
+		ShouldPause: func() bool { return paused },
+	}
+
+	result, err := adapter.ExecuteWorkflow(context.Background(), def, nil, opts)
+	if !errors.Is(err, ErrPaused) {
+		t.Fatalf("expected ErrPaused, got %v", err)
+	}
+	if len(result.Steps) != 1 {
+		t.Errorf("expected execution to stop after one step, got %d", len(result.Steps))
+	}
+	if len(prompts) != 1 || prompts[0] != "edited" {
+		t.Errorf("expected edited prompt, got %v", prompts)
+	}
+	if def.Steps[0].Prompt != "original" {
+		t.Error("expected workflow definition to be unchanged")
+	}
+}
@@ -14,13 +14,283 @@
 
 // Checkpoint save and resume logic.
 // Handles persisting workflow execution state to enable recovery from interruptions,
-// and resuming interrupted runs from saved checkpoints.
+// resuming interrupted runs from saved checkpoints, and pausing, resuming and
+// retrying runs on request.
 package runner
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
-// ResumeInterrupted attempts to resume any interrupted runs from checkpoints.
-// Delegates to LifecycleManager.
+	"github.com/tombee/conductor/internal/controller/backend"
+)
+
+// ErrRunNotLocal is returned when a run exists but is tracked by another
+// controller, so it cannot be paused, resumed or retried from this one.
+var ErrRunNotLocal = errors.New("run is not local to this controller")
+
+// ResumeInterrupted attempts to resume any interrupted runs from checkpoints
+// and restores paused runs saved before a restart.
 func (r *Runner) ResumeInterrupted(ctx context.Context) error {
-	return r.lifecycle.ResumeInterrupted(ctx)
+	if err := r.lifecycle.ResumeInterrupted(ctx); err != nil {
+		return err
+	}
+	return r.restorePaused(ctx)
+}
+
+// restorePaused adds runs that were paused when the controller stopped back
+// to local state, so they can be resumed. In distributed mode runs are
+// rebuilt from the job queue instead.
+func (r *Runner) restorePaused(ctx context.Context) error {
+	if r.dist != nil {
+		return nil
+	}
+	lister, ok := r.getBackend().(backend.RunLister)
+	if !ok {
+		return nil
+	}
+
+	runs, err := lister.ListRuns(ctx, backend.RunFilter{Status: string(RunStatusPaused)})
+	if err != nil {
+		return fmt.Errorf("failed to list paused runs: %w", err)
+	}
+
+	for _, beRun := range runs {
+		if _, exists := r.state.GetRunInternal(beRun.ID); exists {
+			continue
+		}
+		run, err := r.restorePausedRun(ctx, beRun)
+		if err != nil {
+			// The run stays paused in the backend; it just can't be resumed here
+			continue
+		}
+		r.state.AddRun(run)
+		r.addLog(run, "info", "Paused run restored after restart", "")
+	}
+	return nil
+}
+
+// restorePausedRun rebuilds a paused run from the payload saved with its
+// checkpoint.
+func (r *Runner) restorePausedRun(ctx context.Context, beRun *backend.Run) (*Run, error) {
+	cp, err := r.getBackend().GetCheckpoint(ctx, beRun.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if cp == nil {
+		return nil, fmt.Errorf("run %s has no checkpoint", beRun.ID)
+	}
+	data, ok := cp.Context["run"].(string)
+	if !ok {
+		return nil, fmt.Errorf("checkpoint of run %s does not describe the run", beRun.ID)
+	}
+	var payload jobPayload
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode run: %w", err)
+	}
+
+	run, err := r.runFromPayload(ctx, beRun.ID, payload)
+	if err != nil {
+		return nil, err
+	}
+	run.Status = RunStatusPaused
+	run.StartedAt = beRun.StartedAt
+	run.ParentRunID = beRun.ParentRunID
+	run.Progress.CurrentStep = beRun.CurrentStep
+	run.Progress.Completed = beRun.Completed
+	run.completedSteps = checkpointSteps(cp.Context["steps"])
+	run.stepInputs = checkpointSteps(cp.Context["step_inputs"])
+	return run, nil
+}
+
+// localRun returns a run tracked by this controller. A run tracked by
+// another controller is reported with ErrRunNotLocal.
+func (r *Runner) localRun(id string) (*Run, error) {
+	if run, exists := r.state.GetRunInternal(id); exists {
+		return run, nil
+	}
+	if r.dist != nil {
+		if _, err := r.remoteRun(id); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrRunNotLocal, id)
+		}
+	}
+	return nil, fmt.Errorf("run not found: %s", id)
+}
+
+// Pause stops a run at its next step boundary. The paused run releases its
+// execution slot and keeps its completed steps in the backend checkpoint until
+// it is resumed. A run still waiting in the queue is paused immediately.
+func (r *Runner) Pause(id string) (*RunSnapshot, error) {
+	run, err := r.localRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	run.mu.RLock()
+	status := run.Status
+	run.mu.RUnlock()
+
+	switch status {
+	case RunStatusPending, RunStatusRunning:
+	default:
+		return nil, fmt.Errorf("cannot pause run %s: run is %s", id, status)
+	}
+
+	if r.queue.remove(id) {
+		r.mu.RLock()
+		metrics := r.metrics
+		r.mu.RUnlock()
+		if metrics != nil {
+			metrics.DecrementQueueDepth()
+		}
+		r.pauseRun(run)
+		return r.state.Snapshot(run), nil
+	}
+
+	if !run.pauseRequested.Swap(true) {
+		r.addLog(run, "info", "Pause requested, run will pause before its next step", "")
+	}
+	return r.state.Snapshot(run), nil
+}
+
+// Resume queues a paused run again. Steps completed before the pause are
+// restored from the checkpoint rather than executed again.
+func (r *Runner) Resume(ctx context.Context, id string) (*RunSnapshot, error) {
+	run, err := r.localRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	run.mu.RLock()
+	status := run.Status
+	run.mu.RUnlock()
+	if status != RunStatusPaused {
+		return nil, fmt.Errorf("cannot resume run %s: run is %s", id, status)
+	}
+
+	r.requeue(ctx, run, "Run resumed")
+	return r.snapshotQueued(run), nil
+}
+
+// RetryStep executes the failed step of a run again and continues the run.
+// inputs, if set, replace values in the step's inputs for the retry; for LLM
+// steps "prompt" and "system" replace the step's prompts.
+func (r *Runner) RetryStep(ctx context.Context, id, stepID string, inputs map[string]any) (*RunSnapshot, error) {
+	run, err := r.localRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	run.mu.Lock()
+	if run.Status != RunStatusFailed {
+		status := run.Status
+		run.mu.Unlock()
+		return nil, fmt.Errorf("cannot retry step of run %s: run is %s", id, status)
+	}
+	if run.FailedStep != stepID {
+		failed := run.FailedStep
+		run.mu.Unlock()
+		if failed == "" {
+			return nil, fmt.Errorf("cannot retry step %s: run %s did not fail in a step", stepID, id)
+		}
+		return nil, fmt.Errorf("cannot retry step %s: run %s failed in step %s", stepID, id, failed)
+	}
+	if len(inputs) > 0 {
+		if run.stepInputs == nil {
+			run.stepInputs = make(map[string]map[string]any)
+		}
+		run.stepInputs[stepID] = inputs
+	}
+	run.FailedStep = ""
+	run.Error = ""
+	run.Output = nil
+	run.OutputFormats = nil
+	run.mu.Unlock()
+
+	if len(inputs) > 0 {
+		r.persistCheckpoint(run, stepID)
+	}
+
+	r.requeue(ctx, run, fmt.Sprintf("Retrying step %s", stepID))
+	return r.snapshotQueued(run), nil
+}
+
+// pauseRun records that a run stopped at a step boundary.
+func (r *Runner) pauseRun(run *Run) {
+	run.mu.Lock()
+	run.Status = RunStatusPaused
+	stepID := run.Progress.CurrentStep
+	run.mu.Unlock()
+
+	r.persistCheckpoint(run, stepID)
+
+	if be := r.getBackend(); be != nil {
+		_ = be.UpdateRun(context.Background(), r.toBackendRun(run))
+	}
+
+	r.addLog(run, "info", "Run paused", "")
+	r.addStatus(run, string(RunStatusPaused), "")
+}
+
+// cancelPausedRun marks a paused run as cancelled.
+func (r *Runner) cancelPausedRun(run *Run) {
+	run.mu.Lock()
+	run.Status = RunStatusCancelled
+	now := time.Now()
+	run.CompletedAt = &now
+	run.mu.Unlock()
+
+	r.addLog(run, "info", "Paused run cancelled", "")
+	r.addStatus(run, string(RunStatusCancelled), "")
+
+	if be := r.getBackend(); be != nil {
+		_ = be.UpdateRun(context.Background(), r.toBackendRun(run))
+	}
+}
+
+// requeue returns a paused or failed run to the queue, restoring its
+// completed steps and edited step inputs from the checkpoint.
+func (r *Runner) requeue(ctx context.Context, run *Run, message string) {
+	completed, stepInputs := r.loadCheckpoint(ctx, run.ID)
+
+	run.mu.Lock()
+	if completed != nil {
+		run.completedSteps = completed
+	}
+	if stepInputs != nil {
+		run.stepInputs = stepInputs
+	}
+	run.pauseRequested.Store(false)
+	run.Status = RunStatusPending
+	run.CompletedAt = nil
+	run.mu.Unlock()
+
+	r.mu.RLock()
+	metrics := r.metrics
+	r.mu.RUnlock()
+	if metrics != nil {
+		metrics.IncrementQueueDepth()
+	}
+
+	if be := r.getBackend(); be != nil {
+		_ = be.UpdateRun(ctx, r.toBackendRun(run))
+	}
+
+	r.addLog(run, "info", message, "")
+	r.addStatus(run, string(RunStatusPending), "")
+
+	r.queue.push(run, run.Priority.Level(), r.fairKey(run))
+	r.dispatch()
+}
+
+// snapshotQueued returns a snapshot of a run with its queue position.
+func (r *Runner) snapshotQueued(run *Run) *RunSnapshot {
+	snapshot := r.state.Snapshot(run)
+	if snapshot.Status == RunStatusPending {
+		snapshot.QueuePosition = r.queue.position(run.ID)
+	}
+	return snapshot
 }
@@ -0,0 +1,240 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// twoStepAdapter runs the steps of distributedTestWorkflow, skipping steps
+// restored from a checkpoint. fail, if set, decides whether a step fails.
+func twoStepAdapter(beforeSecond func(), fail func(stepID string, opts ExecutionOptions) bool) *MockExecutionAdapter {
+	return &MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			result := &ExecutionResult{StepOutputs: map[string]any{}}
+			for i, step := range def.Steps {
+				if output, ok := opts.CompletedSteps[step.ID]; ok {
+					result.StepOutputs[step.ID] = output
+					continue
+				}
+				if i == 1 && beforeSecond != nil {
+					beforeSecond()
+				}
+				if opts.ShouldPause() {
+					return result, ErrPaused
+				}
+				opts.OnStepStart(step.ID, i, len(def.Steps))
+				if fail != nil && fail(step.ID, opts) {
+					err := errors.New("step failed")
+					opts.OnStepEnd(step.ID, nil, err)
+					return result, err
+				}
+				output := map[string]any{"response": step.Prompt}
+				opts.OnStepEnd(step.ID, &workflow.StepResult{StepID: step.ID, Status: workflow.StepStatusSuccess, Output: output}, nil)
+				result.StepOutputs[step.ID] = output
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestRunner_PauseAndResume(t *testing.T) {
+	be := memory.New()
+	started, release := make(chan struct{}), make(chan struct{})
+	var once bool
+	adapter := twoStepAdapter(func() {
+		if !once {
+			once = true
+			close(started)
+			<-release
+		}
+	}, nil)
+	r := New(Config{MaxParallel: 1}, be, nil)
+	r.SetAdapter(adapter)
+	defer r.Stop(context.Background())
+
+	snapshot, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	if _, err := r.Pause(snapshot.ID); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	close(release)
+	waitForRunStatus(t, r, snapshot.ID, RunStatusPaused)
+
+	// The paused run no longer holds a slot
+	if r.ActiveRunCount() != 0 {
+		t.Error("expected paused run not to count as active")
+	}
+	cp, err := be.GetCheckpoint(context.Background(), snapshot.ID)
+	if err != nil {
+		t.Fatalf("expected checkpoint for paused run: %v", err)
+	}
+	if steps, _ := cp.Context["steps"].(map[string]any); len(steps) != 1 {
+		t.Errorf("expected one completed step in checkpoint, got %v", cp.Context["steps"])
+	}
+	if _, err := r.Pause(snapshot.ID); err == nil {
+		t.Error("expected pausing a paused run to fail")
+	}
+
+	if _, err := r.Resume(context.Background(), snapshot.ID); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	waitForRunStatus(t, r, snapshot.ID, RunStatusCompleted)
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.Calls) != 2 {
+		t.Fatalf("expected two executions, got %d", len(adapter.Calls))
+	}
+	if _, ok := adapter.Calls[1].Opts.CompletedSteps["first"]; !ok {
+		t.Error("expected resumed run to restore the first step")
+	}
+	if _, err := r.Resume(context.Background(), snapshot.ID); err == nil {
+		t.Error("expected resuming a completed run to fail")
+	}
+}
+
+func TestRunner_ResumeAfterRestart(t *testing.T) {
+	be := memory.New()
+	started, release := make(chan struct{}), make(chan struct{})
+	first := New(Config{MaxParallel: 1}, be, nil)
+	first.SetAdapter(twoStepAdapter(func() {
+		close(started)
+		<-release
+	}, nil))
+
+	snapshot, err := first.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+	if _, err := first.Pause(snapshot.ID); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	close(release)
+	waitForRunStatus(t, first, snapshot.ID, RunStatusPaused)
+	first.Stop(context.Background())
+
+	// A new controller on the same backend picks the paused run up
+	adapter := twoStepAdapter(nil, nil)
+	second := New(Config{MaxParallel: 1}, be, nil)
+	second.SetAdapter(adapter)
+	defer second.Stop(context.Background())
+	if err := second.ResumeInterrupted(context.Background()); err != nil {
+		t.Fatalf("ResumeInterrupted() error = %v", err)
+	}
+
+	restored, err := second.Get(snapshot.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if restored.Status != RunStatusPaused {
+		t.Fatalf("expected restored run to be paused, got %s", restored.Status)
+	}
+
+	if _, err := second.Resume(context.Background(), snapshot.ID); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	waitForRunStatus(t, second, snapshot.ID, RunStatusCompleted)
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.Calls) != 1 {
+		t.Fatalf("expected one execution after restart, got %d", len(adapter.Calls))
+	}
+	if _, ok := adapter.Calls[0].Opts.CompletedSteps["first"]; !ok {
+		t.Error("expected resumed run to restore the first step")
+	}
+}
+
+func TestRunner_PauseQueuedRun(t *testing.T) {
+	release := make(chan struct{})
+	r := New(Config{MaxParallel: 1}, memory.New(), nil)
+	r.SetAdapter(&MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			<-release
+			return &ExecutionResult{StepOutputs: map[string]any{}}, nil
+		},
+	})
+	defer r.Stop(context.Background())
+
+	running, _ := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: testWorkflow})
+	waitForRunStatus(t, r, running.ID, RunStatusRunning)
+	queued, _ := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: testWorkflow})
+
+	snapshot, err := r.Pause(queued.ID)
+	if err != nil || snapshot.Status != RunStatusPaused {
+		t.Fatalf("Pause() = %v, %v; want paused run", snapshot, err)
+	}
+	close(release)
+	waitForRunStatus(t, r, running.ID, RunStatusCompleted)
+
+	if err := r.Cancel(queued.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	waitForRunStatus(t, r, queued.ID, RunStatusCancelled)
+}
+
+func TestRunner_RetryStep(t *testing.T) {
+	be := memory.New()
+	adapter := twoStepAdapter(nil, func(stepID string, opts ExecutionOptions) bool {
+		return stepID == "second" && opts.StepInputs["second"]["prompt"] != "fixed"
+	})
+	r := New(Config{}, be, nil)
+	r.SetAdapter(adapter)
+	defer r.Stop(context.Background())
+
+	snapshot, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	failed := waitForRunStatus(t, r, snapshot.ID, RunStatusFailed)
+	if failed.FailedStep != "second" {
+		t.Fatalf("FailedStep = %q, want second", failed.FailedStep)
+	}
+
+	if _, err := r.RetryStep(context.Background(), snapshot.ID, "first", nil); err == nil {
+		t.Error("expected retry of a step that did not fail to be rejected")
+	}
+	if _, err := r.RetryStep(context.Background(), snapshot.ID, "second", map[string]any{"prompt": "fixed"}); err != nil {
+		t.Fatalf("RetryStep() error = %v", err)
+	}
+	completed := waitForRunStatus(t, r, snapshot.ID, RunStatusCompleted)
+	if completed.FailedStep != "" || completed.Error != "" {
+		t.Errorf("expected retried run to clear its failure, got %q: %q", completed.FailedStep, completed.Error)
+	}
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.Calls) != 2 {
+		t.Fatalf("expected two executions, got %d", len(adapter.Calls))
+	}
+	retry := adapter.Calls[1].Opts
+	if _, ok := retry.CompletedSteps["first"]; !ok {
+		t.Error("expected retry to keep the completed first step")
+	}
+	if _, ok := retry.CompletedSteps["second"]; ok {
+		t.Error("expected the failed step to run again")
+	}
+}
@@ -0,0 +1,608 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// DistributedConfig configures distributed execution.
+type DistributedConfig struct {
+	// WorkerID identifies this controller's claims on the queue.
+	WorkerID string
+
+	// Queue is the shared job queue.
+	Queue backend.JobQueue
+
+	// Logs shares run logs between controllers (optional).
+	Logs backend.RunLogStore
+
+	// StalledJobTimeout is how long a claim lasts without a heartbeat
+	// before the job is returned to the queue. Defaults to 5 minutes.
+	StalledJobTimeout time.Duration
+
+	// PollInterval is how often workers check the queue for jobs.
+	// Defaults to 1 second.
+	PollInterval time.Duration
+}
+
+// WithDistributed enables distributed execution on a shared job queue.
+func WithDistributed(cfg DistributedConfig) Option {
+	return func(r *Runner) {
+		if cfg.StalledJobTimeout <= 0 {
+			cfg.StalledJobTimeout = 5 * time.Minute
+		}
+		if cfg.PollInterval <= 0 {
+			cfg.PollInterval = time.Second
+		}
+		r.dist = &cfg
+		r.logs.store = cfg.Logs
+	}
+}
+
+// jobPayload is what a worker needs to execute a queued run.
+type jobPayload struct {
+	WorkflowYAML     []byte         `json:"workflow_yaml"`
+	Inputs           map[string]any `json:"inputs,omitempty"`
+	WorkflowDir      string         `json:"workflow_dir,omitempty"`
+	SourceURL        string         `json:"source_url,omitempty"`
+	Workspace        string         `json:"workspace,omitempty"`
+	Profile          string         `json:"profile,omitempty"`
+	CorrelationID    string         `json:"correlation_id,omitempty"`
+	Priority         PriorityClass  `json:"priority,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	Provider         string         `json:"provider,omitempty"`
+	Model            string         `json:"model,omitempty"`
+	Timeout          time.Duration  `json:"timeout,omitempty"`
+	Security         string         `json:"security,omitempty"`
+	AllowHosts       []string       `json:"allow_hosts,omitempty"`
+	AllowPaths       []string       `json:"allow_paths,omitempty"`
+	MCPDev           bool           `json:"mcp_dev,omitempty"`
+	LogLevel         string         `json:"log_level,omitempty"`
+	DebugBreakpoints []string       `json:"debug_breakpoints,omitempty"`
+	ParentRunID      string         `json:"parent_run_id,omitempty"`
+	MCPLock          []byte         `json:"mcp_lock,omitempty"`
+}
+
+// newJobPayload captures everything needed to rebuild a run on another
+// controller or after a restart.
+func newJobPayload(run *Run, workflowYAML []byte) jobPayload {
+	run.mu.RLock()
+	defer run.mu.RUnlock()
+	return jobPayload{
+		WorkflowYAML:     workflowYAML,
+		Inputs:           run.Inputs,
+		WorkflowDir:      run.WorkflowDir,
+		SourceURL:        run.SourceURL,
+		Workspace:        run.Workspace,
+		Profile:          run.Profile,
+		CorrelationID:    run.CorrelationID,
+		Priority:         run.Priority,
+		CreatedAt:        run.CreatedAt,
+		Provider:         run.Provider,
+		Model:            run.Model,
+		Timeout:          run.Timeout,
+		Security:         run.Security,
+		AllowHosts:       run.AllowHosts,
+		AllowPaths:       run.AllowPaths,
+		MCPDev:           run.MCPDev,
+		LogLevel:         run.LogLevel,
+		DebugBreakpoints: run.DebugBreakpoints,
+		ParentRunID:      run.ParentRunID,
+		MCPLock:          run.mcpLock,
+	}
+}
+
+// enqueue adds a submitted run to the job queue. The run is removed from
+// local state; whichever controller claims it tracks it from then on.
+func (r *Runner) enqueue(ctx context.Context, run *Run, workflowYAML []byte) error {
+	payload := newJobPayload(run, workflowYAML)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	job := &backend.Job{
+		RunID:    run.ID,
+		Priority: run.Priority.Level(),
+		FairKey:  r.fairKey(run),
+		Payload:  data,
+	}
+	if err := r.dist.Queue.EnqueueJob(ctx, job); err != nil {
+		return err
+	}
+
+	r.state.DeleteRun(run.ID)
+	run.cancel()
+	return nil
+}
+
+// StartWorkers starts the worker loops that claim queued runs and recover
+// stalled jobs. It does nothing unless distributed mode is enabled.
+// Workers stop when ctx is cancelled or the runner is stopped.
+func (r *Runner) StartWorkers(ctx context.Context) {
+	if r.dist == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	r.stopWorkers = cancel
+	r.mu.Unlock()
+
+	go r.claimLoop(ctx)
+	go r.recoverLoop(ctx)
+}
+
+// claimLoop claims jobs while the runner has free execution slots.
+func (r *Runner) claimLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.dist.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for !r.IsDraining() {
+			// Only claim a job when a slot is free
+			acquired := false
+			select {
+			case r.semaphore <- struct{}{}:
+				acquired = true
+			default:
+			}
+			if !acquired {
+				break
+			}
+
+			job, err := r.dist.Queue.DequeueJob(ctx, r.dist.WorkerID)
+			if err != nil || job == nil {
+				<-r.semaphore
+				if err != nil && ctx.Err() == nil {
+					slog.Warn("failed to claim job", "worker_id", r.dist.WorkerID, "error", err)
+				}
+				break
+			}
+
+			r.wg.Add(1)
+			go r.runJob(ctx, job)
+		}
+	}
+}
+
+// recoverLoop periodically returns stalled jobs to the queue.
+func (r *Runner) recoverLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.dist.StalledJobTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			recovered, err := r.dist.Queue.RecoverStalledJobs(ctx, r.dist.StalledJobTimeout)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Warn("failed to recover stalled jobs", "error", err)
+				}
+				continue
+			}
+			if recovered > 0 {
+				slog.Info("re-queued stalled jobs", "count", recovered)
+			}
+		}
+	}
+}
+
+// runJob executes a claimed job. The caller holds an execution slot and a
+// WaitGroup count for it.
+func (r *Runner) runJob(ctx context.Context, job *backend.Job) {
+	defer r.wg.Done()
+	defer func() { <-r.semaphore }()
+
+	run, err := r.restoreJobRun(ctx, job)
+	if err != nil {
+		slog.Error("failed to load claimed job", "run_id", job.RunID, "error", err)
+		r.failJobRun(job.RunID, err)
+		_ = r.dist.Queue.CompleteJob(context.Background(), job.RunID)
+		return
+	}
+
+	// Keep the queue depth gauge balanced; executeRun decrements it
+	r.mu.RLock()
+	metrics := r.metrics
+	r.mu.RUnlock()
+	if metrics != nil {
+		metrics.IncrementQueueDepth()
+	}
+
+	// Runs interrupted by this controller stopping go back to the queue
+	// so another controller resumes them
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			run.handoff.Store(true)
+		case <-done:
+		}
+	}()
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	claimLost := make(chan struct{})
+	go r.heartbeat(heartbeatCtx, run, claimLost)
+
+	r.executeRun(run)
+	stopHeartbeat()
+	close(done)
+
+	select {
+	case <-claimLost:
+		// Another worker owns the job now
+		return
+	default:
+	}
+
+	run.mu.RLock()
+	status := run.Status
+	run.mu.RUnlock()
+	if status == RunStatusCancelled && run.handoff.Load() {
+		_ = r.dist.Queue.FailJob(context.Background(), job.RunID)
+		return
+	}
+
+	if err := r.dist.Queue.CompleteJob(context.Background(), job.RunID); err != nil {
+		slog.Warn("failed to complete job", "run_id", job.RunID, "error", err)
+	}
+}
+
+// heartbeat refreshes the claim on a run's job until ctx is cancelled.
+// If the claim is lost, the run is cancelled locally and claimLost is closed.
+func (r *Runner) heartbeat(ctx context.Context, run *Run, claimLost chan struct{}) {
+	ticker := time.NewTicker(r.dist.StalledJobTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := r.dist.Queue.HeartbeatJob(ctx, run.ID, r.dist.WorkerID)
+			if errors.Is(err, backend.ErrJobNotClaimed) {
+				r.addLog(run, "warn", "Lost claim on run, stopping execution on this controller", "")
+				close(claimLost)
+				run.handoff.Store(true)
+				run.cancel()
+				return
+			}
+			if errors.Is(err, backend.ErrJobCancelled) {
+				r.addLog(run, "info", "Run cancelled from another controller", "")
+				_ = r.Cancel(run.ID)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				r.addLog(run, "warn", fmt.Sprintf("Failed to refresh job claim: %v", err), "")
+			}
+		}
+	}
+}
+
+// cancelJob cancels a run that is queued or executing on another
+// controller. A queued run is removed from the job queue and marked
+// cancelled; the controller executing a claimed run stops it at its next
+// heartbeat.
+func (r *Runner) cancelJob(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	removed, err := r.dist.Queue.CancelJob(ctx, id)
+	if errors.Is(err, backend.ErrJobNotFound) {
+		return fmt.Errorf("run not found: %s", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel run %s: %w", id, err)
+	}
+	if !removed {
+		return nil
+	}
+
+	be := r.getBackend()
+	if be == nil {
+		return nil
+	}
+	beRun, err := be.GetRun(ctx, id)
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	beRun.Status = string(RunStatusCancelled)
+	beRun.CompletedAt = &now
+	if err := be.UpdateRun(ctx, beRun); err != nil {
+		return fmt.Errorf("failed to cancel run %s: %w", id, err)
+	}
+
+	// Followers of the run's shared logs see it end
+	run := &Run{ID: id, CorrelationID: beRun.CorrelationID}
+	r.addLog(run, "info", "Run cancelled before execution started", "")
+	r.addStatus(run, string(RunStatusCancelled), "")
+	return nil
+}
+
+// restoreJobRun rebuilds a run from a claimed job and adds it to local
+// state. Runs claimed again after a failure resume from their checkpoint.
+func (r *Runner) restoreJobRun(ctx context.Context, job *backend.Job) (*Run, error) {
+	var payload jobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+
+	run, err := r.runFromPayload(ctx, job.RunID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Attempts > 1 {
+		run.completedSteps, run.stepInputs = r.loadCheckpoint(ctx, run.ID)
+	}
+
+	r.state.AddRun(run)
+	if job.Attempts > 1 {
+		r.addLog(run, "info", fmt.Sprintf("Resuming run on %s (attempt %d, %d step(s) restored)", r.dist.WorkerID, job.Attempts, len(run.completedSteps)), "")
+	} else {
+		r.addLog(run, "info", fmt.Sprintf("Run claimed by %s", r.dist.WorkerID), "")
+	}
+	return run, nil
+}
+
+// runFromPayload rebuilds a run from its job payload without adding it
+// to local state.
+func (r *Runner) runFromPayload(ctx context.Context, id string, payload jobPayload) (*Run, error) {
+	def, err := workflow.ParseDefinition(payload.WorkflowYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+
+	_, _, bindings, err := r.resolveProfile(ctx, payload.Workspace, payload.Profile, def)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve profile bindings: %w", err)
+	}
+
+	run := newRun(id, payload.CorrelationID, def, payload.Inputs, payload.SourceURL, payload.Workspace, payload.Profile, bindings, &RunOverrides{
+		Provider:         payload.Provider,
+		Model:            payload.Model,
+		Timeout:          payload.Timeout,
+		Security:         payload.Security,
+		AllowHosts:       payload.AllowHosts,
+		AllowPaths:       payload.AllowPaths,
+		MCPDev:           payload.MCPDev,
+		LogLevel:         payload.LogLevel,
+		DebugBreakpoints: payload.DebugBreakpoints,
+		ParentRunID:      payload.ParentRunID,
+		MCPLock:          payload.MCPLock,
+	})
+	run.CreatedAt = payload.CreatedAt
+	run.WorkflowDir = payload.WorkflowDir
+	run.Priority = payload.Priority
+	run.workflowYAML = payload.WorkflowYAML
+	return run, nil
+}
+
+// failJobRun marks a run that cannot be executed as failed in the backend.
+func (r *Runner) failJobRun(runID string, cause error) {
+	be := r.getBackend()
+	if be == nil {
+		return
+	}
+	ctx := context.Background()
+	beRun, err := be.GetRun(ctx, runID)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	beRun.Status = string(RunStatusFailed)
+	beRun.Error = cause.Error()
+	beRun.CompletedAt = &now
+	_ = be.UpdateRun(ctx, beRun)
+}
+
+// saveStepCheckpoint records a completed step's output. In distributed mode
+// it is also saved to the backend so a run claimed by another controller can
+// resume after it.
+func (r *Runner) saveStepCheckpoint(run *Run, stepID string, stepIndex int, output map[string]any) {
+	run.mu.Lock()
+	if run.completedSteps == nil {
+		run.completedSteps = make(map[string]map[string]any)
+	}
+	if output == nil {
+		output = map[string]any{}
+	}
+	run.completedSteps[stepID] = output
+	run.mu.Unlock()
+
+	if r.dist != nil {
+		r.persistCheckpoint(run, stepID)
+	}
+}
+
+// persistCheckpoint saves a run's completed steps and edited step inputs to
+// the backend. stepID is the step the run stopped at or after.
+func (r *Runner) persistCheckpoint(run *Run, stepID string) {
+	be := r.getBackend()
+	if be == nil {
+		return
+	}
+
+	run.mu.RLock()
+	steps := make(map[string]any, len(run.completedSteps))
+	for id, out := range run.completedSteps {
+		steps[id] = out
+	}
+	stepInputs := make(map[string]any, len(run.stepInputs))
+	for id, inputs := range run.stepInputs {
+		stepInputs[id] = inputs
+	}
+	paused := run.Status == RunStatusPaused
+	run.mu.RUnlock()
+
+	stepIndex := -1
+	for i, step := range run.definition.Steps {
+		if step.ID == stepID {
+			stepIndex = i
+			break
+		}
+	}
+
+	cp := &backend.Checkpoint{
+		StepID:    stepID,
+		StepIndex: stepIndex,
+		Context:   map[string]any{"steps": steps, "step_inputs": stepInputs},
+	}
+	if paused {
+		// A paused run has no job to rebuild it from, so the checkpoint
+		// carries its definition and overrides across a restart.
+		if data, err := json.Marshal(newJobPayload(run, run.workflowYAML)); err == nil {
+			cp.Context["run"] = string(data)
+		}
+	}
+	if err := be.SaveCheckpoint(context.Background(), run.ID, cp); err != nil {
+		r.addLog(run, "warn", fmt.Sprintf("Failed to save checkpoint: %v", err), stepID)
+	}
+}
+
+// loadCheckpoint reads the outputs of completed steps and any edited step
+// inputs from a run's checkpoint.
+func (r *Runner) loadCheckpoint(ctx context.Context, runID string) (completed, stepInputs map[string]map[string]any) {
+	be := r.getBackend()
+	if be == nil {
+		return nil, nil
+	}
+	cp, err := be.GetCheckpoint(ctx, runID)
+	if err != nil || cp == nil {
+		return nil, nil
+	}
+	return checkpointSteps(cp.Context["steps"]), checkpointSteps(cp.Context["step_inputs"])
+}
+
+// checkpointSteps decodes a map of step ID to values saved in a checkpoint.
+func checkpointSteps(value any) map[string]map[string]any {
+	entries, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	steps := make(map[string]map[string]any, len(entries))
+	for id, entry := range entries {
+		if v, ok := entry.(map[string]any); ok {
+			steps[id] = v
+		}
+	}
+	return steps
+}
+
+// remoteRun returns a snapshot of a run tracked by another controller.
+func (r *Runner) remoteRun(id string) (*RunSnapshot, error) {
+	be := r.getBackend()
+	if be == nil {
+		return nil, fmt.Errorf("run not found: %s", id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	beRun, err := be.GetRun(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("run not found: %s", id)
+	}
+	snapshot := r.state.backendRunToSnapshot(beRun)
+	if snapshot.Status == RunStatusPending {
+		snapshot.QueuePosition = r.queuePosition(id)
+	}
+	if r.dist.Logs != nil {
+		entries, err := r.dist.Logs.ListRunLogs(ctx, id, 0)
+		if err == nil {
+			snapshot.Logs = decodeLogEntries(entries)
+		}
+	}
+	return snapshot, nil
+}
+
+// subscribeRemote follows the shared logs of a run executing on another
+// controller. The channel closes once the run reaches a final status.
+func (r *Runner) subscribeRemote(runID string) (<-chan LogEntry, func()) {
+	ch := make(chan LogEntry, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Start after the entries that exist now; callers read those from the snapshot
+	offset := 0
+	if entries, err := r.dist.Logs.ListRunLogs(ctx, runID, 0); err == nil {
+		offset = len(entries)
+	}
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			entries, err := r.dist.Logs.ListRunLogs(ctx, runID, offset)
+			if err != nil {
+				continue
+			}
+			offset += len(entries)
+			for _, entry := range decodeLogEntries(entries) {
+				select {
+				case ch <- entry:
+				case <-ctx.Done():
+					return
+				}
+				if entry.Type == "status" && isFinalStatus(RunStatus(entry.Status)) {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, cancel
+}
+
+func decodeLogEntries(entries []json.RawMessage) []LogEntry {
+	logs := make([]LogEntry, 0, len(entries))
+	for _, data := range entries {
+		var entry LogEntry
+		if err := json.Unmarshal(data, &entry); err == nil {
+			logs = append(logs, entry)
+		}
+	}
+	return logs
+}
+
+func isFinalStatus(status RunStatus) bool {
+	return status == RunStatusCompleted || status == RunStatusFailed || status == RunStatusCancelled
+}
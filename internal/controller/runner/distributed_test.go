@@ -0,0 +1,307 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+const distributedTestWorkflow = `
+name: distributed-test
+steps:
+  - id: first
+    type: llm
+    prompt: "one"
+  - id: second
+    type: llm
+    prompt: "two"
+`
+
+func newDistributedTestRunner(be *memory.Backend, workerID string, adapter ExecutionAdapter) *Runner {
+	r := New(Config{MaxParallel: 2}, be, nil, WithDistributed(DistributedConfig{
+		WorkerID:          workerID,
+		Queue:             be,
+		Logs:              be,
+		StalledJobTimeout: time.Minute,
+		PollInterval:      10 * time.Millisecond,
+	}))
+	r.SetAdapter(adapter)
+	return r
+}
+
+func waitForRunStatus(t *testing.T, r *Runner, id string, want RunStatus) *RunSnapshot {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if snapshot, err := r.Get(id); err == nil && snapshot.Status == want {
+			return snapshot
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("run %s did not reach status %s", id, want)
+	return nil
+}
+
+func TestRunner_Distributed_SubmitAndClaim(t *testing.T) {
+	be := memory.New()
+	submitter := newDistributedTestRunner(be, "controller-a", &MockExecutionAdapter{})
+	worker := newDistributedTestRunner(be, "controller-b", &MockExecutionAdapter{})
+
+	snapshot, err := submitter.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if snapshot.Status != RunStatusPending {
+		t.Errorf("expected pending run, got %s", snapshot.Status)
+	}
+	if submitter.ActiveRunCount() != 0 {
+		t.Error("expected submitting controller not to execute the run")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.StartWorkers(ctx)
+
+	waitForRunStatus(t, worker, snapshot.ID, RunStatusCompleted)
+
+	// The submitting controller reads status and logs from the backend
+	remote := waitForRunStatus(t, submitter, snapshot.ID, RunStatusCompleted)
+	if len(remote.Logs) == 0 {
+		t.Error("expected shared logs for the remote run")
+	}
+	if err := submitter.Cancel(snapshot.ID); err == nil {
+		t.Error("expected cancel of a remote run to fail")
+	}
+	if _, err := submitter.Resume(context.Background(), snapshot.ID); !errors.Is(err, ErrRunNotLocal) {
+		t.Errorf("expected resume of a remote run to fail with ErrRunNotLocal, got %v", err)
+	}
+
+	if job, _ := be.DequeueJob(context.Background(), "check"); job != nil {
+		t.Errorf("expected completed job to leave the queue, got %+v", job)
+	}
+}
+
+func TestRunner_Distributed_SubscribeRemote(t *testing.T) {
+	be := memory.New()
+	submitter := newDistributedTestRunner(be, "controller-a", &MockExecutionAdapter{})
+
+	release := make(chan struct{})
+	worker := newDistributedTestRunner(be, "controller-b", &MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			<-release
+			return &ExecutionResult{StepOutputs: map[string]any{}}, nil
+		},
+	})
+
+	snapshot, err := submitter.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.StartWorkers(ctx)
+	waitForRunStatus(t, submitter, snapshot.ID, RunStatusRunning)
+
+	logCh, unsub := submitter.Subscribe(snapshot.ID)
+	defer unsub()
+	close(release)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case entry, ok := <-logCh:
+			if !ok {
+				t.Fatal("log stream closed before the final status")
+			}
+			if entry.Type == "status" && entry.Status == string(RunStatusCompleted) {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for the final status")
+		}
+	}
+}
+
+func TestRunner_Distributed_ResumeStalledJob(t *testing.T) {
+	be := memory.New()
+	ctx := context.Background()
+
+	// A run claimed by a controller that died after completing the first step
+	payload, _ := json.Marshal(jobPayload{WorkflowYAML: []byte(distributedTestWorkflow), CreatedAt: time.Now()})
+	if err := be.CreateRun(ctx, &backend.Run{ID: "run-1", WorkflowID: "distributed-test", Workflow: "distributed-test", Status: "running"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := be.EnqueueJob(ctx, &backend.Job{RunID: "run-1", Payload: payload}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := be.DequeueJob(ctx, "dead-controller"); err != nil {
+		t.Fatal(err)
+	}
+	if err := be.SaveCheckpoint(ctx, "run-1", &backend.Checkpoint{
+		StepID:  "first",
+		Context: map[string]any{"steps": map[string]any{"first": map[string]any{"response": "one"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if recovered, err := be.RecoverStalledJobs(ctx, time.Millisecond); err != nil || recovered != 1 {
+		t.Fatalf("RecoverStalledJobs() = %d, %v", recovered, err)
+	}
+
+	adapter := &MockExecutionAdapter{}
+	worker := newDistributedTestRunner(be, "controller-b", adapter)
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	worker.StartWorkers(workerCtx)
+
+	waitForRunStatus(t, worker, "run-1", RunStatusCompleted)
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.Calls) != 1 {
+		t.Fatalf("expected one execution, got %d", len(adapter.Calls))
+	}
+	completed := adapter.Calls[0].Opts.CompletedSteps
+	if completed["first"]["response"] != "one" {
+		t.Errorf("expected first step restored from checkpoint, got %v", completed)
+	}
+	if _, ok := completed["second"]; ok {
+		t.Error("expected second step to run again")
+	}
+}
+
+func TestRunner_Distributed_LostClaim(t *testing.T) {
+	be := memory.New()
+	started := make(chan struct{})
+	worker := New(Config{MaxParallel: 1}, be, nil, WithDistributed(DistributedConfig{
+		WorkerID:          "controller-b",
+		Queue:             be,
+		Logs:              be,
+		StalledJobTimeout: 30 * time.Millisecond,
+		PollInterval:      10 * time.Millisecond,
+	}))
+	worker.SetAdapter(&MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	snapshot, err := worker.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.StartWorkers(ctx)
+	<-started
+
+	// Hand the job to another controller; only the heartbeat notices
+	if err := be.FailJob(context.Background(), snapshot.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := be.DequeueJob(context.Background(), "controller-c"); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if worker.ActiveRunCount() == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if worker.ActiveRunCount() != 0 {
+		t.Fatal("expected run to stop after losing its claim")
+	}
+
+	// The run's shared state is left to the new owner
+	beRun, err := be.GetRun(context.Background(), snapshot.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beRun.Status == string(RunStatusCancelled) {
+		t.Error("expected backend status not to be overwritten after losing the claim")
+	}
+	if err := be.HeartbeatJob(context.Background(), snapshot.ID, "controller-c"); err != nil {
+		t.Errorf("expected job to stay claimed by the new owner, got %v", err)
+	}
+}
+
+func TestRunner_Distributed_CancelQueued(t *testing.T) {
+	be := memory.New()
+	submitter := newDistributedTestRunner(be, "controller-a", &MockExecutionAdapter{})
+
+	snapshot, err := submitter.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := submitter.Cancel(snapshot.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	if job, _ := be.DequeueJob(context.Background(), "check"); job != nil {
+		t.Errorf("expected cancelled job to leave the queue, got %+v", job)
+	}
+	waitForRunStatus(t, submitter, snapshot.ID, RunStatusCancelled)
+}
+
+func TestRunner_Distributed_CancelRemote(t *testing.T) {
+	be := memory.New()
+	submitter := newDistributedTestRunner(be, "controller-a", &MockExecutionAdapter{})
+	started := make(chan struct{})
+	worker := New(Config{MaxParallel: 1}, be, nil, WithDistributed(DistributedConfig{
+		WorkerID:          "controller-b",
+		Queue:             be,
+		Logs:              be,
+		StalledJobTimeout: 30 * time.Millisecond,
+		PollInterval:      10 * time.Millisecond,
+	}))
+	worker.SetAdapter(&MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	snapshot, err := submitter.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.StartWorkers(ctx)
+	<-started
+
+	// The executing controller stops the run at its next heartbeat
+	if err := submitter.Cancel(snapshot.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	waitForRunStatus(t, worker, snapshot.ID, RunStatusCancelled)
+	waitForRunStatus(t, submitter, snapshot.ID, RunStatusCancelled)
+}
@@ -62,6 +62,13 @@ The runner uses a semaphore to limit concurrent executions:
   - Pending runs queue until a slot is available
   - Cancellation is safe even for queued runs
 
+Queued runs start in priority class order (interactive, trigger, batch).
+Within a class, Config.FairShare picks the workspace or workflow with the
+fewest running runs, so a flood of runs from one group cannot starve the
+rest. Config.MaxQueueDepth bounds the queue; Submit returns a
+*QueueFullError once it is reached. RunSnapshot.QueuePosition reports where
+a pending run stands.
+
 # Checkpointing
 
 Before each step, the runner saves a checkpoint containing:
@@ -72,6 +79,31 @@ Before each step, the runner saves a checkpoint containing:
 
 On controller restart, interrupted runs can be resumed from checkpoints.
 
+Runner.Pause stops a run before its next step and releases its execution
+slot; the outputs of completed steps are saved to the backend checkpoint.
+Runner.Resume queues the run again from that checkpoint. Runner.RetryStep
+does the same for a failed run, executing the failed step again with
+optionally edited inputs.
+
+# Distributed Execution
+
+With a backend that implements backend.JobQueue, controllers share runs:
+
+	r := runner.New(cfg, pgBackend, cm, runner.WithDistributed(runner.DistributedConfig{
+	    WorkerID: instanceID,
+	    Queue:    pgBackend,
+	    Logs:     pgBackend,
+	}))
+	r.StartWorkers(ctx)
+
+In this mode:
+
+  - Submit enqueues runs instead of executing them
+  - Workers on every controller claim jobs while execution slots are free
+  - Claims are refreshed with heartbeats; stalled jobs return to the queue
+  - Reclaimed runs skip steps completed before the failure
+  - Logs and status of runs on other controllers are read from the backend
+
 # Remote Workflows
 
 The runner supports remote workflow references (e.g., github:user/repo):
@@ -30,13 +30,18 @@ import (
 	"github.com/tombee/conductor/pkg/workflow"
 )
 
-// execute runs the workflow.
-func (r *Runner) execute(run *Run) {
-	// Track this goroutine for clean shutdown
-	r.wg.Add(1)
+// execute runs a queued workflow once dispatch has given it an execution slot.
+func (r *Runner) execute(next *queuedRun) {
+	// The caller holds the slot and a WaitGroup count for this goroutine;
+	// releasing the slot lets the next queued run start
 	defer r.wg.Done()
+	defer r.dispatch()
+	defer func() { <-r.semaphore }()
+	defer r.queue.done(next.key)
 
-	// Check if cancelled before even starting
+	run := next.run
+
+	// Check if cancelled before starting
 	select {
 	case <-run.stopped:
 		run.mu.Lock()
@@ -49,23 +54,15 @@ func (r *Runner) execute(run *Run) {
 	default:
 	}
 
-	// Acquire semaphore
-	select {
-	case r.semaphore <- struct{}{}:
-		defer func() { <-r.semaphore }()
-	case <-run.stopped:
-		run.mu.Lock()
-		run.Status = RunStatusCancelled
-		now := time.Now()
-		run.CompletedAt = &now
-		run.mu.Unlock()
-		r.addLog(run, "info", "Run cancelled while waiting for semaphore", "")
-		return
-	}
+	r.executeRun(run)
+}
 
+// executeRun runs the workflow once the caller holds an execution slot.
+func (r *Runner) executeRun(run *Run) {
 	// Update status to running
 	run.mu.Lock()
 	run.Status = RunStatusRunning
+	firstStart := run.StartedAt == nil
 	now := time.Now()
 	run.StartedAt = &now
 	run.mu.Unlock()
@@ -102,13 +99,30 @@ func (r *Runner) execute(run *Run) {
 	}
 	r.addLog(run, "info", startMsg, "")
 
+	// Runs resumed after a pause or step retry have already been announced
+	if firstStart {
+		r.notify(run, workflow.NotifyEventStarted, "")
+	}
+
 	// Create log function for lifecycle manager
 	logFn := func(level, message, stepID string) {
 		r.addLog(run, level, message, stepID)
 	}
 
 	// Start MCP servers using LifecycleManager
-	mcpServerNames, err := r.lifecycle.StartMCPServers(run.ctx, run.definition, logFn)
+	var mcpServerNames []string
+	mcpOpts := MCPServerOptions{}
+	if run.bindings != nil {
+		mcpOpts.Bindings = run.bindings.MCPServerBindings
+	}
+	if run.sampler = r.newSampler(run, logFn); run.sampler != nil {
+		mcpOpts.Sampling = run.sampler
+	}
+	var err error
+	mcpOpts.ToolPolicy, err = r.mcpToolPolicy(run)
+	if err == nil {
+		mcpServerNames, err = r.lifecycle.StartMCPServers(run.ctx, run.definition, mcpOpts, logFn)
+	}
 	if err != nil {
 		run.mu.Lock()
 		run.Status = RunStatusFailed
@@ -117,6 +131,8 @@ func (r *Runner) execute(run *Run) {
 		run.CompletedAt = &completedAt
 		run.mu.Unlock()
 		r.addLog(run, "error", fmt.Sprintf("Failed to start MCP servers: %v", err), "")
+		r.notifyOutcome(run)
+		r.observeFinished(run)
 		return
 	}
 
@@ -146,6 +162,8 @@ func (r *Runner) execute(run *Run) {
 			beRun := r.toBackendRun(run)
 			_ = be.UpdateRun(run.ctx, beRun)
 		}
+		r.notifyOutcome(run)
+		r.observeFinished(run)
 		return
 	}
 
@@ -210,6 +228,16 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 		r.addLog(run, "info", fmt.Sprintf("Debug mode enabled with %d breakpoint(s)", len(run.DebugBreakpoints)), "")
 	}
 
+	// Steps restored from a checkpoint; saveStepCheckpoint adds to the run's
+	// map as steps complete
+	run.mu.RLock()
+	completedSteps := make(map[string]map[string]any, len(run.completedSteps))
+	for id, output := range run.completedSteps {
+		completedSteps[id] = output
+	}
+	stepInputs := run.stepInputs
+	run.mu.RUnlock()
+
 	opts := ExecutionOptions{
 		RunID:       run.ID,
 		WorkflowDir: run.WorkflowDir,
@@ -232,6 +260,11 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 			// Send step_start event for CLI progress display
 			r.addStepStart(run, stepID, stepName, stepIndex, total)
 
+			// Share progress with other controllers
+			if r.dist != nil {
+				_ = r.state.UpdateRun(run.ctx, run)
+			}
+
 			// Notify debug adapter if active
 			if debugAdapter != nil {
 				// Create inputs map from workflow context
@@ -305,6 +338,20 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 				}
 			}
 
+			// Include completions requested by MCP servers during the step
+			sampled := run.sampler.stepUsage(stepID)
+			costUSD += sampled.cost
+			tokensIn += sampled.tokens.InputTokens
+			tokensOut += sampled.tokens.OutputTokens
+			cacheCreation += sampled.tokens.CacheCreationTokens
+			cacheRead += sampled.tokens.CacheReadTokens
+			r.addCost(run, costUSD)
+
+			// Checkpoint the step so the run can resume after it
+			if err == nil && result != nil && result.Status != workflow.StepStatusFailed {
+				r.saveStepCheckpoint(run, stepID, stepIndex, result.Output)
+			}
+
 			// Send step_complete event for CLI progress display
 			r.addStepComplete(run, stepID, stepName, status, output, durationMs, costUSD, tokensIn, tokensOut, cacheCreation, cacheRead, errMsg)
 
@@ -317,12 +364,12 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 							RunID:     run.ID,
 							StepID:    stepID,
 							StepIndex: stepIndex,
-							Inputs:    nil, // Step inputs not available at this level
+							Inputs:    stepDefinitionInputs(run, stepIndex),
 							Outputs:   result.Output,
 							Duration:  result.Duration,
 							Status:    string(result.Status),
 							Error:     errorToString(err),
-							CostUSD:   result.CostUSD,
+							CostUSD:   costUSD,
 							CreatedAt: time.Now(),
 						}
 						// Use context.Background() to ensure step result persists
@@ -345,6 +392,9 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 		OnLog: func(level, message, stepID string) {
 			r.addLog(run, level, message, stepID)
 		},
+		CompletedSteps: completedSteps,
+		StepInputs:     stepInputs,
+		ShouldPause:    run.pauseRequested.Load,
 		// Apply runtime overrides from run
 		Provider:   run.Provider,
 		Model:      run.Model,
@@ -365,6 +415,12 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 		debugShell.Close()
 	}
 
+	// Runs paused at a step boundary give up their slot until resumed
+	if err == ErrPaused {
+		r.pauseRun(run)
+		return
+	}
+
 	// Update final status
 	run.mu.Lock()
 	completedAt := time.Now()
@@ -393,6 +449,9 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 			run.Status = RunStatusFailed
 			run.Error = err.Error()
 		}
+		if run.Status == RunStatusFailed {
+			run.FailedStep = run.Progress.CurrentStep
+		}
 	} else {
 		run.Status = RunStatusCompleted
 		if result != nil {
@@ -425,6 +484,12 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 	status := string(run.Status)
 	run.mu.Unlock()
 
+	// A run handed off to another controller keeps its shared state
+	if status == string(RunStatusCancelled) && run.handoff.Load() {
+		r.addLog(run, "info", "Run interrupted, execution continues on another controller", "")
+		return
+	}
+
 	// Record run completion for metrics
 	r.mu.RLock()
 	metrics := r.metrics
@@ -446,10 +511,55 @@ func (r *Runner) executeWithAdapter(run *Run, adapter ExecutionAdapter) {
 		_ = r.lifecycle.CleanupCheckpoint(context.Background(), run.ID)
 	}
 
+	// Keep completed steps so the failed step can be retried
+	if run.FailedStep != "" {
+		r.persistCheckpoint(run, run.FailedStep)
+	}
+
+	// Notify channels of the outcome and start chained runs
+	r.notifyOutcome(run)
+	r.observeFinished(run)
+
 	// Send status event for CLI to display final state
 	r.addStatus(run, status, run.Error)
 }
 
+// stepDefinitionInputs returns a step's inputs as written in the workflow,
+// with edits from a step retry applied. Templates are not resolved.
+func stepDefinitionInputs(run *Run, stepIndex int) map[string]any {
+	if stepIndex < 0 || stepIndex >= len(run.definition.Steps) {
+		return nil
+	}
+	step := run.definition.Steps[stepIndex]
+
+	run.mu.RLock()
+	edited := run.stepInputs[step.ID]
+	run.mu.RUnlock()
+	if edited != nil {
+		applyStepInputs(&step, edited)
+	}
+
+	inputs := make(map[string]any, len(step.Inputs)+3)
+	for k, v := range step.Inputs {
+		inputs[k] = v
+	}
+	if step.Type == workflow.StepTypeLLM {
+		if step.Prompt != "" {
+			inputs["prompt"] = step.Prompt
+		}
+		if step.System != "" {
+			inputs["system"] = step.System
+		}
+		if step.Model != "" {
+			inputs["model"] = step.Model
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	return inputs
+}
+
 // errorToString converts an error to a string, returning empty string if nil.
 func errorToString(err error) string {
 	if err == nil {
@@ -0,0 +1,123 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultIdempotencyKeyRetention is how long an Idempotency-Key returns its
+// run by default.
+const DefaultIdempotencyKeyRetention = 24 * time.Hour
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyConflictError is returned by Submit when an Idempotency-Key
+// was already used for a run submitted with a different request.
+type IdempotencyConflictError struct {
+	Key   string
+	RunID string
+}
+
+func (e *IdempotencyConflictError) Error() string {
+	return fmt.Sprintf("idempotency key %q was already used for run %s with a different request", e.Key, e.RunID)
+}
+
+// requestHash identifies what a submission asks for, so a retry can be told
+// apart from a different request sent with the same key.
+func requestHash(req SubmitRequest) string {
+	data, _ := json.Marshal(struct {
+		WorkflowYAML string         `json:"workflow_yaml,omitempty"`
+		RemoteRef    string         `json:"remote_ref,omitempty"`
+		Inputs       map[string]any `json:"inputs,omitempty"`
+		Workspace    string         `json:"workspace,omitempty"`
+		Profile      string         `json:"profile,omitempty"`
+	}{
+		WorkflowYAML: string(req.WorkflowYAML),
+		RemoteRef:    req.RemoteRef,
+		Inputs:       req.Inputs,
+		Workspace:    req.Workspace,
+		Profile:      req.Profile,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// keyLocks serializes submissions that share an idempotency key, so that
+// concurrent retries start one run without holding up other submissions.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int // Submissions holding or waiting for the lock
+}
+
+// lock locks a key and returns the function that unlocks it.
+func (k *keyLocks) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(k.locks, key)
+		}
+	}
+}
+
+// findIdempotentRun returns the workflow's run submitted earlier with req's
+// Idempotency-Key, or nil if the key is new or has expired.
+func (r *Runner) findIdempotentRun(ctx context.Context, workflow string, req SubmitRequest, hash string) (*RunSnapshot, error) {
+	since := time.Now().Add(-r.idempotencyRetention)
+	run, prevHash, err := r.state.FindRunByIdempotencyKey(ctx, workflow, req.IdempotencyKey, since)
+	if err != nil || run == nil {
+		return nil, err
+	}
+
+	// Keys are not scoped to a caller, so only reveal runs they may start
+	if req.Authorize != nil {
+		if err := req.Authorize(run.Workflow, run.Workspace); err != nil {
+			return nil, err
+		}
+	}
+	if prevHash != hash {
+		return nil, &IdempotencyConflictError{Key: req.IdempotencyKey, RunID: run.ID}
+	}
+
+	run.Replayed = true
+	return run, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+)
+
+func TestRunner_SubmitIdempotencyKey(t *testing.T) {
+	be := memory.New()
+	r := New(Config{MaxParallel: 1}, be, nil)
+	r.SetAdapter(&MockExecutionAdapter{})
+	defer r.Stop(context.Background())
+	ctx := context.Background()
+
+	req := SubmitRequest{
+		WorkflowYAML:   testWorkflow,
+		Inputs:         map[string]any{"name": "a"},
+		IdempotencyKey: "key-1",
+	}
+	first, err := r.Submit(ctx, req)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if first.Replayed || first.IdempotencyKey != "key-1" {
+		t.Errorf("first = %+v", first)
+	}
+
+	again, err := r.Submit(ctx, req)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if again.ID != first.ID || !again.Replayed {
+		t.Errorf("repeat returned run %s (replayed %v), want %s", again.ID, again.Replayed, first.ID)
+	}
+
+	changed := req
+	changed.Inputs = map[string]any{"name": "b"}
+	_, err = r.Submit(ctx, changed)
+	var conflict *IdempotencyConflictError
+	if !errors.As(err, &conflict) || conflict.RunID != first.ID {
+		t.Errorf("Submit() error = %v, want IdempotencyConflictError", err)
+	}
+
+	// A restarted controller finds the key in the backend
+	restarted := New(Config{MaxParallel: 1}, be, nil)
+	restarted.SetAdapter(&MockExecutionAdapter{})
+	defer restarted.Stop(context.Background())
+	again, err = restarted.Submit(ctx, req)
+	if err != nil || again.ID != first.ID {
+		t.Errorf("Submit() after restart = %v, %v, want run %s", again, err, first.ID)
+	}
+
+	other, err := r.Submit(ctx, SubmitRequest{WorkflowYAML: testWorkflow, IdempotencyKey: "key-2"})
+	if err != nil || other.ID == first.ID {
+		t.Errorf("Submit() with a new key = %v, %v, want a new run", other, err)
+	}
+}
+
+func TestRunner_SubmitIdempotencyKeyPerWorkflow(t *testing.T) {
+	be := memory.New()
+	r := New(Config{MaxParallel: 1}, be, nil)
+	r.SetAdapter(&MockExecutionAdapter{})
+	defer r.Stop(context.Background())
+	ctx := context.Background()
+
+	first, err := r.Submit(ctx, SubmitRequest{WorkflowYAML: testWorkflow, IdempotencyKey: "key-1"})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// Keys are scoped to a workflow, in memory and in the backend
+	otherWorkflow := bytes.Replace(testWorkflow, []byte("name: race-test"), []byte("name: other"), 1)
+	other, err := r.Submit(ctx, SubmitRequest{WorkflowYAML: otherWorkflow, IdempotencyKey: "key-1"})
+	if err != nil || other.ID == first.ID || other.Replayed {
+		t.Fatalf("Submit() for another workflow = %+v, %v, want a new run", other, err)
+	}
+
+	restarted := New(Config{MaxParallel: 1}, be, nil)
+	restarted.SetAdapter(&MockExecutionAdapter{})
+	defer restarted.Stop(context.Background())
+	again, err := restarted.Submit(ctx, SubmitRequest{WorkflowYAML: otherWorkflow, IdempotencyKey: "key-1"})
+	if err != nil || again.ID != other.ID {
+		t.Errorf("Submit() after restart = %+v, %v, want run %s", again, err, other.ID)
+	}
+}
+
+func TestKeyLocks(t *testing.T) {
+	var locks keyLocks
+	unlockA := locks.lock("a")
+
+	// Other keys are not held up
+	done := make(chan struct{})
+	go func() {
+		locks.lock("b")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock on another key waited")
+	}
+
+	// The same key waits until it is unlocked
+	acquired := make(chan func())
+	go func() { acquired <- locks.lock("a") }()
+	select {
+	case <-acquired:
+		t.Fatal("lock on a held key did not wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlockA()
+	(<-acquired)()
+
+	if len(locks.locks) != 0 {
+		t.Errorf("%d locks left after unlocking, want 0", len(locks.locks))
+	}
+}
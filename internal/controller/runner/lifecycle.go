@@ -17,8 +17,10 @@ package runner
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/tombee/conductor/internal/binding"
 	"github.com/tombee/conductor/internal/controller/checkpoint"
 	"github.com/tombee/conductor/internal/mcp"
 	"github.com/tombee/conductor/pkg/tools"
@@ -60,9 +62,25 @@ func (l *LifecycleManager) MCPManager() mcp.MCPManagerProvider {
 // LogFunc is a callback for logging during lifecycle operations.
 type LogFunc func(level, message, stepID string)
 
+// MCPServerOptions configures how workflow MCP servers are started.
+type MCPServerOptions struct {
+	// ToolPolicy checks each server's tools before they are registered.
+	// A nil policy skips the checks.
+	ToolPolicy *mcp.ToolPolicy
+
+	// Sampling completes sampling requests from servers with allow_sampling.
+	// If nil, sampling is not offered to any server.
+	Sampling mcp.SamplingHandler
+
+	// Bindings are the run's resolved MCP server bindings, keyed by server
+	// name. Secret references in a server's env, headers and auth are only
+	// ever resolved here, never from the controller environment.
+	Bindings map[string]binding.ResolvedMCPServerBinding
+}
+
 // StartMCPServers starts all MCP servers defined in the workflow.
 // Returns the list of started server names for later cleanup.
-func (l *LifecycleManager) StartMCPServers(ctx context.Context, def *workflow.Definition, logFn LogFunc) ([]string, error) {
+func (l *LifecycleManager) StartMCPServers(ctx context.Context, def *workflow.Definition, opts MCPServerOptions, logFn LogFunc) ([]string, error) {
 	if len(def.MCPServers) == 0 {
 		return nil, nil
 	}
@@ -77,13 +95,12 @@ func (l *LifecycleManager) StartMCPServers(ctx context.Context, def *workflow.De
 			logFn("info", fmt.Sprintf("Starting MCP server: %s", mcpServerDef.Name), "")
 		}
 
-		// Convert workflow MCPServerConfig to mcp.ServerConfig
-		serverConfig := mcp.ServerConfig{
-			Name:    mcpServerDef.Name,
-			Command: mcpServerDef.Command,
-			Args:    mcpServerDef.Args,
-			Env:     mcpServerDef.Env,
-			Timeout: time.Duration(mcpServerDef.Timeout) * time.Second,
+		serverConfig := mcp.WorkflowServerConfig(mcpServerDef)
+		if b, ok := opts.Bindings[mcpServerDef.Name]; ok {
+			applyMCPBinding(&serverConfig, b)
+		}
+		if mcpServerDef.AllowSampling && opts.Sampling != nil {
+			serverConfig.Sampling = opts.Sampling
 		}
 
 		// Start the server
@@ -94,7 +111,7 @@ func (l *LifecycleManager) StartMCPServers(ctx context.Context, def *workflow.De
 		serverNames = append(serverNames, mcpServerDef.Name)
 
 		// Wait for the server to be ready and register its tools
-		if err := l.registerMCPTools(ctx, mcpServerDef.Name, logFn); err != nil {
+		if err := l.registerMCPTools(ctx, mcpServerDef.Name, mcp.ServerSource(serverConfig), opts.ToolPolicy, logFn); err != nil {
 			return serverNames, fmt.Errorf("failed to register tools for MCP server %s: %w", mcpServerDef.Name, err)
 		}
 
@@ -106,6 +123,49 @@ func (l *LifecycleManager) StartMCPServers(ctx context.Context, def *workflow.De
 	return serverNames, nil
 }
 
+// applyMCPBinding overlays a resolved binding onto a workflow server config.
+// Fields the binding leaves empty keep the workflow's value.
+func applyMCPBinding(config *mcp.ServerConfig, b binding.ResolvedMCPServerBinding) {
+	if b.Transport != "" {
+		config.Transport = mcp.TransportType(b.Transport)
+	}
+	if b.Command != "" {
+		config.Command = b.Command
+	}
+	if len(b.Args) > 0 {
+		config.Args = b.Args
+	}
+	if len(b.Env) > 0 {
+		env := make([]string, 0, len(b.Env))
+		for k, v := range b.Env {
+			env = append(env, k+"="+v)
+		}
+		sort.Strings(env)
+		config.Env = env
+	}
+	if b.URL != "" {
+		config.URL = b.URL
+	}
+	if len(b.Headers) > 0 {
+		config.Headers = b.Headers
+	}
+	if b.Timeout > 0 {
+		config.Timeout = time.Duration(b.Timeout) * time.Second
+	}
+	if b.Auth != nil {
+		config.Auth = &mcp.AuthConfig{
+			Type:         mcp.AuthType(b.Auth.Type),
+			Token:        b.Auth.Token,
+			Flow:         b.Auth.Flow,
+			ClientID:     b.Auth.ClientID,
+			ClientSecret: b.Auth.ClientSecret,
+			TokenURL:     b.Auth.TokenURL,
+			RefreshToken: b.Auth.RefreshToken,
+			Scopes:       b.Auth.Scopes,
+		}
+	}
+}
+
 // StopMCPServers stops the specified MCP servers.
 func (l *LifecycleManager) StopMCPServers(serverNames []string, logFn LogFunc) {
 	for _, serverName := range serverNames {
@@ -117,8 +177,8 @@ func (l *LifecycleManager) StopMCPServers(serverNames []string, logFn LogFunc) {
 	}
 }
 
-// registerMCPTools discovers and registers tools from an MCP server.
-func (l *LifecycleManager) registerMCPTools(ctx context.Context, serverName string, logFn LogFunc) error {
+// registerMCPTools discovers, verifies and registers tools from an MCP server.
+func (l *LifecycleManager) registerMCPTools(ctx context.Context, serverName, source string, policy *mcp.ToolPolicy, logFn LogFunc) error {
 	// Get the MCP client for this server
 	// We need to wait a bit for the server to initialize
 	var client mcp.ClientProvider
@@ -149,13 +209,25 @@ func (l *LifecycleManager) registerMCPTools(ctx context.Context, serverName stri
 		return fmt.Errorf("failed to list tools from server %s: %w", serverName, err)
 	}
 
+	warnings, err := policy.Check(serverName, source, toolDefs)
+	if err != nil {
+		return err
+	}
+	if logFn != nil {
+		for _, warning := range warnings {
+			logFn("warn", warning, "")
+		}
+	}
+
 	if logFn != nil {
 		logFn("info", fmt.Sprintf("Registering %d tool(s) from MCP server %s", len(toolDefs), serverName), "")
 	}
 
-	// Register each tool in the registry
+	// Register each tool in the registry. Tools route through the manager so
+	// calls keep working after a remote server is reconnected.
+	managed := mcp.NewManagedClient(l.mcpManager, serverName)
 	for _, toolDef := range toolDefs {
-		mcpTool := mcp.NewMCPTool(serverName, toolDef, client)
+		mcpTool := mcp.NewMCPTool(serverName, toolDef, managed)
 		l.toolRegistry.Register(mcpTool)
 		if logFn != nil {
 			logFn("debug", fmt.Sprintf("Registered MCP tool: %s", mcpTool.Name()), "")
@@ -19,9 +19,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/tombee/conductor/internal/binding"
 	"github.com/tombee/conductor/internal/controller/checkpoint"
 	"github.com/tombee/conductor/internal/mcp"
 	mcptesting "github.com/tombee/conductor/internal/mcp/testing"
+	"github.com/tombee/conductor/pkg/security"
 	"github.com/tombee/conductor/pkg/tools"
 	"github.com/tombee/conductor/pkg/workflow"
 )
@@ -75,7 +77,7 @@ func TestLifecycleManager_StartMCPServers_NoServers(t *testing.T) {
 	lm := NewLifecycleManager(nil, nil, nil)
 
 	def := &workflow.Definition{Name: "test"}
-	names, err := lm.StartMCPServers(context.Background(), def, nil)
+	names, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{}, nil)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -109,7 +111,7 @@ func TestLifecycleManager_StartMCPServers_Success(t *testing.T) {
 		logs = append(logs, message)
 	}
 
-	names, err := lm.StartMCPServers(context.Background(), def, logFn)
+	names, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{}, logFn)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -124,6 +126,47 @@ func TestLifecycleManager_StartMCPServers_Success(t *testing.T) {
 	}
 }
 
+func TestLifecycleManager_StartMCPServers_Bindings(t *testing.T) {
+	var started mcp.ServerConfig
+	mockMCP := mcptesting.NewMockManager().WithOnStart(func(config mcp.ServerConfig) error {
+		started = config
+		return nil
+	})
+	lm := NewLifecycleManager(mockMCP, nil, nil)
+
+	def := &workflow.Definition{
+		Name: "test",
+		MCPServers: []workflow.MCPServerConfig{{
+			Name:      "remote",
+			Transport: "http",
+			URL:       "https://mcp.example.com",
+			Headers:   map[string]string{"X-Api-Key": "${API_KEY}"},
+			Auth:      &workflow.MCPAuthConfig{Type: "bearer", Token: "${TOKEN}"},
+		}},
+	}
+	opts := MCPServerOptions{Bindings: map[string]binding.ResolvedMCPServerBinding{
+		"remote": {
+			Transport: "http",
+			URL:       "https://mcp.example.com",
+			Headers:   map[string]string{"X-Api-Key": "resolved-key"},
+			Auth:      &binding.ResolvedMCPAuthBinding{Type: "bearer", Token: "resolved-token"},
+		},
+	}}
+
+	if _, err := lm.StartMCPServers(context.Background(), def, opts, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if started.Headers["X-Api-Key"] != "resolved-key" {
+		t.Errorf("header = %q, want resolved-key", started.Headers["X-Api-Key"])
+	}
+	if started.Auth == nil || started.Auth.Token != "resolved-token" {
+		t.Errorf("auth = %+v, want resolved-token", started.Auth)
+	}
+	if started.URL != "https://mcp.example.com" || started.Transport != mcp.TransportStreamableHTTP {
+		t.Errorf("url/transport = %q/%q, want workflow values", started.URL, started.Transport)
+	}
+}
+
 func TestLifecycleManager_StartMCPServers_StartError(t *testing.T) {
 	mockMCP := mcptesting.NewMockManager()
 	mockMCP.AddServer(mcptesting.MockServerConfig{
@@ -140,7 +183,7 @@ func TestLifecycleManager_StartMCPServers_StartError(t *testing.T) {
 		},
 	}
 
-	_, err := lm.StartMCPServers(context.Background(), def, nil)
+	_, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{}, nil)
 
 	if err == nil {
 		t.Error("expected error")
@@ -256,7 +299,7 @@ func TestLifecycleManager_StartMCPServers_WithLogFunc(t *testing.T) {
 		logs = append(logs, struct{ level, message, stepID string }{level, message, stepID})
 	}
 
-	_, err := lm.StartMCPServers(context.Background(), def, logFn)
+	_, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{}, logFn)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -291,7 +334,7 @@ func TestLifecycleManager_StartMCPServers_NilLogFunc(t *testing.T) {
 	}
 
 	// Should not panic with nil logFn
-	names, err := lm.StartMCPServers(context.Background(), def, nil)
+	names, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{}, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -300,6 +343,61 @@ func TestLifecycleManager_StartMCPServers_NilLogFunc(t *testing.T) {
 	}
 }
 
+func TestLifecycleManager_StartMCPServers_ToolPolicy(t *testing.T) {
+	mockMCP := mcptesting.NewMockManager()
+	mockMCP.AddServer(mcptesting.MockServerConfig{
+		Name:  "test-server",
+		Tools: []mcp.ToolDefinition{{Name: "test-tool", Description: "Changed description"}},
+	})
+
+	def := &workflow.Definition{
+		Name: "test",
+		MCPServers: []workflow.MCPServerConfig{
+			{Name: "test-server", Command: "echo"},
+		},
+	}
+
+	locked, err := mcp.NewServerLock("echo", []mcp.ToolDefinition{{Name: "test-tool", Description: "Original description"}})
+	if err != nil {
+		t.Fatalf("NewServerLock() error = %v", err)
+	}
+	lockfile := mcp.NewLockfile()
+	lockfile.Servers["test-server"] = locked
+
+	t.Run("warn", func(t *testing.T) {
+		lm := NewLifecycleManager(mockMCP, nil, nil)
+		var warnings []string
+		logFn := func(level, message, stepID string) {
+			if level == "warn" {
+				warnings = append(warnings, message)
+			}
+		}
+
+		policy := &mcp.ToolPolicy{Lockfile: lockfile, Actions: security.MCPConfig{ToolDrift: security.MCPActionWarn}}
+		if _, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{ToolPolicy: policy}, logFn); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("expected a drift warning, got %v", warnings)
+		}
+		if !lm.ToolRegistry().Has("test-server.test-tool") {
+			t.Error("expected tool to be registered")
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		lm := NewLifecycleManager(mockMCP, nil, nil)
+		policy := &mcp.ToolPolicy{Lockfile: lockfile, Actions: security.MCPConfig{ToolDrift: security.MCPActionBlock}}
+		_, err := lm.StartMCPServers(context.Background(), def, MCPServerOptions{ToolPolicy: policy}, nil)
+		if err == nil {
+			t.Fatal("expected drift to block the run")
+		}
+		if len(lm.ToolRegistry().List()) != 0 {
+			t.Error("expected no tools to be registered")
+		}
+	})
+}
+
 func TestLifecycleManager_SaveCheckpoint_WithManager(t *testing.T) {
 	// Create a checkpoint manager with a temp directory
 	tmpDir := t.TempDir()
@@ -15,8 +15,12 @@
 package runner
 
 import (
+	"context"
+	"encoding/json"
 	"sync"
 	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
 )
 
 // subscriberChan wraps a channel with synchronization to prevent
@@ -52,10 +56,17 @@ func (s *subscriberChan) close() {
 	}
 }
 
+// allRunsKey is the subscriber key for SubscribeAll. Run IDs are UUIDs, so
+// it cannot collide with a run.
+const allRunsKey = "*"
+
 // LogAggregator handles log collection and subscription routing.
 type LogAggregator struct {
 	mu          sync.RWMutex
 	subscribers map[string][]*subscriberChan
+
+	// store shares entries with other controllers in distributed mode (optional)
+	store backend.RunLogStore
 }
 
 // NewLogAggregator creates a new LogAggregator.
@@ -162,16 +173,39 @@ func (l *LogAggregator) AddStatus(run *Run, status, errMsg string) {
 // notifySubscribers sends a log entry to all subscribers for a run.
 // Makes a copy of the subscriber slice to avoid race with unsubscribe.
 func (l *LogAggregator) notifySubscribers(runID string, entry LogEntry) {
+	l.persistLog(runID, entry)
+
 	l.mu.RLock()
 	origSubs := l.subscribers[runID]
 	// Make a copy to avoid race with unsubscribe modifying the slice
 	subs := make([]*subscriberChan, len(origSubs))
 	copy(subs, origSubs)
+	allSubs := make([]*subscriberChan, len(l.subscribers[allRunsKey]))
+	copy(allSubs, l.subscribers[allRunsKey])
 	l.mu.RUnlock()
 
 	for _, sub := range subs {
 		sub.send(entry)
 	}
+
+	entry.RunID = runID
+	for _, sub := range allSubs {
+		sub.send(entry)
+	}
+}
+
+// persistLog writes a log entry to the shared log store (best-effort).
+func (l *LogAggregator) persistLog(runID string, entry LogEntry) {
+	if l.store == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = l.store.AppendRunLog(ctx, runID, data)
 }
 
 // Subscribe returns a channel that receives log entries for a run.
@@ -212,6 +246,12 @@ func (l *LogAggregator) Subscribe(runID string) (<-chan LogEntry, func()) {
 	return sub.ch, unsub
 }
 
+// SubscribeAll returns a channel that receives log entries for every run,
+// with RunID set. Returns the channel and an unsubscribe function.
+func (l *LogAggregator) SubscribeAll() (<-chan LogEntry, func()) {
+	return l.Subscribe(allRunsKey)
+}
+
 // SubscriberCount returns the number of subscribers for a run.
 func (l *LogAggregator) SubscriberCount(runID string) int {
 	l.mu.RLock()
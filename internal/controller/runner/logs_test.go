@@ -459,3 +459,30 @@ func TestLogAggregator_ChannelClosure_AfterReceivingMessages(t *testing.T) {
 		t.Error("expected channel to be closed after unsubscribe")
 	}
 }
+
+func TestLogAggregator_SubscribeAll(t *testing.T) {
+	la := NewLogAggregator()
+	ch, unsub := la.SubscribeAll()
+	defer unsub()
+
+	la.AddStepStart(&Run{ID: "run-1"}, "step1", "Step 1", 0, 2)
+	la.AddStatus(&Run{ID: "run-2"}, "completed", "")
+
+	for _, want := range []string{"run-1", "run-2"} {
+		select {
+		case entry := <-ch:
+			if entry.RunID != want {
+				t.Errorf("expected entry for %s, got %s", want, entry.RunID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for entry from %s", want)
+		}
+	}
+
+	// Entries stored on the run do not carry the run ID
+	run := &Run{ID: "run-3"}
+	la.AddLog(run, "info", "message", "")
+	if run.Logs[0].RunID != "" {
+		t.Errorf("expected stored entry without run ID, got %q", run.Logs[0].RunID)
+	}
+}
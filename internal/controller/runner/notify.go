@@ -0,0 +1,143 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// RunEvent is a run lifecycle event sent to the RunNotifier.
+type RunEvent struct {
+	// Type is one of the workflow.NotifyEvent* constants
+	Type string
+
+	// Run is a snapshot of the run when the event happened
+	Run *RunSnapshot
+
+	// Notify is the workflow's notify block merged with the controller defaults
+	Notify *workflow.NotifyDefinition
+
+	// Message adds detail, such as what is awaiting approval
+	Message string
+
+	Time time.Time
+}
+
+// RunNotifier delivers run lifecycle events to notification channels.
+// NotifyRun is called on the execution path and must not block.
+type RunNotifier interface {
+	NotifyRun(event RunEvent)
+}
+
+// RunObserver is told when a run reaches a final status, whether or not
+// its workflow notifies any channels. RunFinished is called on the
+// execution path and must not block.
+type RunObserver interface {
+	RunFinished(run *RunSnapshot)
+}
+
+// notifyConfig returns the notify block that applies to a run, or nil if
+// no notifier is set or the run has no channels to notify.
+func (r *Runner) notifyConfig(run *Run) *workflow.NotifyDefinition {
+	r.mu.RLock()
+	notifier := r.notifier
+	defaults := r.notifyDefaults
+	r.mu.RUnlock()
+
+	if notifier == nil || run.definition == nil {
+		return nil
+	}
+	cfg := run.definition.Notify.Merge(defaults)
+	if cfg == nil || len(cfg.Channels) == 0 {
+		return nil
+	}
+	return cfg
+}
+
+// notify sends a lifecycle event for a run if its notify block wants it.
+func (r *Runner) notify(run *Run, event, message string) {
+	cfg := r.notifyConfig(run)
+	if cfg == nil || !slices.Contains(cfg.Events(), event) {
+		return
+	}
+
+	r.mu.RLock()
+	notifier := r.notifier
+	r.mu.RUnlock()
+
+	notifier.NotifyRun(RunEvent{
+		Type:    event,
+		Run:     r.state.Snapshot(run),
+		Notify:  cfg,
+		Message: message,
+		Time:    time.Now(),
+	})
+}
+
+// notifyOutcome sends succeeded or failed for a finished run. Cancelled
+// runs are not notified.
+func (r *Runner) notifyOutcome(run *Run) {
+	run.mu.RLock()
+	status := run.Status
+	run.mu.RUnlock()
+
+	switch status {
+	case RunStatusCompleted:
+		r.notify(run, workflow.NotifyEventSucceeded, "")
+	case RunStatusFailed:
+		r.notify(run, workflow.NotifyEventFailed, "")
+	}
+}
+
+// addCost adds a step's cost to the run and sends budget_exceeded the first
+// time the total passes the notify block's budget.
+func (r *Runner) addCost(run *Run, costUSD float64) {
+	if costUSD <= 0 {
+		return
+	}
+
+	run.mu.Lock()
+	run.CostUSD += costUSD
+	total := run.CostUSD
+	run.mu.Unlock()
+
+	cfg := r.notifyConfig(run)
+	if cfg == nil || cfg.BudgetUSD <= 0 || total <= cfg.BudgetUSD {
+		return
+	}
+	if run.budgetExceeded.Swap(true) {
+		return
+	}
+
+	message := fmt.Sprintf("Run cost $%.4f exceeds the budget of $%.2f", total, cfg.BudgetUSD)
+	r.addLog(run, "warn", message, "")
+	r.notify(run, workflow.NotifyEventBudgetExceeded, message)
+}
+
+// observeFinished tells the run observer that a run finished. Runs handed
+// off to another controller are reported there.
+func (r *Runner) observeFinished(run *Run) {
+	r.mu.RLock()
+	observer := r.observer
+	r.mu.RUnlock()
+
+	if observer != nil {
+		observer.RunFinished(r.state.Snapshot(run))
+	}
+}
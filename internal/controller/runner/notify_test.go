@@ -0,0 +1,135 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// recordingNotifier collects run events.
+type recordingNotifier struct {
+	events chan RunEvent
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{events: make(chan RunEvent, 16)}
+}
+
+func (n *recordingNotifier) NotifyRun(event RunEvent) {
+	n.events <- event
+}
+
+// next waits for the next event.
+func (n *recordingNotifier) next(t *testing.T) RunEvent {
+	t.Helper()
+	select {
+	case event := <-n.events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for run event")
+		return RunEvent{}
+	}
+}
+
+// costAdapter runs each step of a workflow at the given cost, failing the
+// step named fail.
+func costAdapter(costUSD float64, fail string) *MockExecutionAdapter {
+	return &MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			for i, step := range def.Steps {
+				opts.OnStepStart(step.ID, i, len(def.Steps))
+				if step.ID == fail {
+					err := errors.New("step failed")
+					opts.OnStepEnd(step.ID, nil, err)
+					return &ExecutionResult{}, err
+				}
+				opts.OnStepEnd(step.ID, &workflow.StepResult{StepID: step.ID, Status: workflow.StepStatusSuccess, CostUSD: costUSD}, nil)
+			}
+			return &ExecutionResult{StepOutputs: map[string]any{}}, nil
+		},
+	}
+}
+
+func TestRunner_Notify(t *testing.T) {
+	notifier := newRecordingNotifier()
+	r := New(Config{}, memory.New(), nil)
+	r.SetAdapter(costAdapter(0.6, ""))
+	r.SetNotifier(notifier, &workflow.NotifyDefinition{
+		On:        []string{workflow.NotifyEventStarted, workflow.NotifyEventSucceeded, workflow.NotifyEventBudgetExceeded},
+		BudgetUSD: 1,
+		Channels:  []workflow.NotifyChannel{{Type: workflow.NotifyChannelWebhook, URL: "https://hooks.example.com"}},
+	})
+	defer r.Stop(context.Background())
+
+	snapshot, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(distributedTestWorkflow)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	for _, want := range []string{workflow.NotifyEventStarted, workflow.NotifyEventBudgetExceeded, workflow.NotifyEventSucceeded} {
+		event := notifier.next(t)
+		if event.Type != want {
+			t.Fatalf("event = %s, want %s", event.Type, want)
+		}
+		if event.Run.ID != snapshot.ID || event.Notify == nil || len(event.Notify.Channels) != 1 {
+			t.Errorf("%s event = %+v", want, event)
+		}
+	}
+
+	final := waitForRunStatus(t, r, snapshot.ID, RunStatusCompleted)
+	if final.CostUSD < 1.19 || final.CostUSD > 1.21 {
+		t.Errorf("CostUSD = %v, want 1.2", final.CostUSD)
+	}
+}
+
+func TestRunner_NotifyWorkflowBlock(t *testing.T) {
+	notifier := newRecordingNotifier()
+	r := New(Config{}, memory.New(), nil)
+	r.SetAdapter(costAdapter(0, "second"))
+	// Workflows without channels of their own use the defaults
+	r.SetNotifier(notifier, &workflow.NotifyDefinition{
+		Channels: []workflow.NotifyChannel{{Type: workflow.NotifyChannelWebhook, URL: "https://hooks.example.com"}},
+	})
+	defer r.Stop(context.Background())
+
+	wf := distributedTestWorkflow + `notify:
+  on: [failed]
+`
+	snapshot, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(wf)})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	event := notifier.next(t)
+	if event.Type != workflow.NotifyEventFailed || event.Run.FailedStep != "second" {
+		t.Fatalf("event = %s (failed step %q), want failed in second", event.Type, event.Run.FailedStep)
+	}
+	if event.Notify.Channels[0].URL != "https://hooks.example.com" {
+		t.Errorf("channels = %+v, want the defaults", event.Notify.Channels)
+	}
+	waitForRunStatus(t, r, snapshot.ID, RunStatusFailed)
+
+	select {
+	case extra := <-notifier.events:
+		t.Errorf("unexpected %s event", extra.Type)
+	default:
+	}
+}
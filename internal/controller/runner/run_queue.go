@@ -0,0 +1,311 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// PriorityClass orders queued runs. Runs in a higher class start first.
+type PriorityClass string
+
+const (
+	// PriorityInteractive is for runs started through the API, CLI or MCP.
+	PriorityInteractive PriorityClass = "interactive"
+	// PriorityTrigger is for runs started by webhooks, file watchers and pollers.
+	PriorityTrigger PriorityClass = "trigger"
+	// PriorityBatch is for scheduled runs.
+	PriorityBatch PriorityClass = "batch"
+)
+
+// Fair-share modes group queued runs so that, within a priority class, the
+// group with the fewest running runs starts next.
+const (
+	FairShareWorkspace = "workspace"
+	FairShareWorkflow  = "workflow"
+)
+
+// defaultQueueRetryAfter is suggested to clients when the queue is full.
+const defaultQueueRetryAfter = 30 * time.Second
+
+// ParsePriorityClass parses a priority class name. An empty name is interactive.
+func ParsePriorityClass(name string) (PriorityClass, error) {
+	switch PriorityClass(name) {
+	case "":
+		return PriorityInteractive, nil
+	case PriorityInteractive, PriorityTrigger, PriorityBatch:
+		return PriorityClass(name), nil
+	default:
+		return "", fmt.Errorf("invalid priority %q: must be interactive, trigger or batch", name)
+	}
+}
+
+// Level returns the queue priority of the class. Higher levels start first.
+func (p PriorityClass) Level() int {
+	switch p {
+	case PriorityBatch:
+		return 0
+	case PriorityTrigger:
+		return 10
+	default:
+		return 20
+	}
+}
+
+// QueueFullError is returned by Submit when the queue is at its maximum depth.
+type QueueFullError struct {
+	Depth      int
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("run queue is full (%d runs waiting)", e.Depth)
+}
+
+// queuedRun is a run waiting for an execution slot.
+type queuedRun struct {
+	run   *Run
+	level int
+	key   string // Fair-share group
+}
+
+// runQueue orders runs waiting for an execution slot by priority class, then
+// by fair-share group, then by arrival.
+type runQueue struct {
+	mu      sync.Mutex
+	entries []*queuedRun   // In arrival order
+	running map[string]int // Running runs per fair-share group
+}
+
+func newRunQueue() *runQueue {
+	return &runQueue{running: make(map[string]int)}
+}
+
+// push adds a run to the queue.
+func (q *runQueue) push(run *Run, level int, key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entries = append(q.entries, &queuedRun{run: run, level: level, key: key})
+}
+
+// pop removes the next run and counts it as running in its group.
+// Returns nil if the queue is empty.
+func (q *runQueue) pop() *queuedRun {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := nextQueued(q.entries, q.running)
+	if i < 0 {
+		return nil
+	}
+	next := q.entries[i]
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	q.running[next.key]++
+	return next
+}
+
+// done records that a run popped from the queue has finished.
+func (q *runQueue) done(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.running[key]--; q.running[key] <= 0 {
+		delete(q.running, key)
+	}
+}
+
+// remove takes a run out of the queue. Returns false if it was not queued.
+func (q *runQueue) remove(runID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, entry := range q.entries {
+		if entry.run.ID == runID {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// drain empties the queue and returns the runs that were waiting.
+func (q *runQueue) drain() []*Run {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	runs := make([]*Run, len(q.entries))
+	for i, entry := range q.entries {
+		runs[i] = entry.run
+	}
+	q.entries = nil
+	return runs
+}
+
+// len returns the number of queued runs.
+func (q *runQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+// position returns the 1-based position of a queued run, or 0 if it is not
+// queued. It assumes no running run finishes before the run starts.
+func (q *runQueue) position(runID string) int {
+	return q.positions()[runID]
+}
+
+// positions returns the 1-based position of every queued run by run ID,
+// in the order the runs would start.
+func (q *runQueue) positions() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entries := make([]*queuedRun, len(q.entries))
+	copy(entries, q.entries)
+	running := make(map[string]int, len(q.running))
+	for key, count := range q.running {
+		running[key] = count
+	}
+
+	positions := make(map[string]int, len(entries))
+	for position := 1; len(entries) > 0; position++ {
+		i := nextQueued(entries, running)
+		positions[entries[i].run.ID] = position
+		running[entries[i].key]++
+		entries = append(entries[:i], entries[i+1:]...)
+	}
+	return positions
+}
+
+// nextQueued returns the index of the run to start next, or -1 if there is none.
+func nextQueued(entries []*queuedRun, running map[string]int) int {
+	best := -1
+	for i, entry := range entries {
+		if best < 0 {
+			best = i
+			continue
+		}
+		current := entries[best]
+		if entry.level != current.level {
+			if entry.level > current.level {
+				best = i
+			}
+			continue
+		}
+		if running[entry.key] < running[current.key] {
+			best = i
+		}
+	}
+	return best
+}
+
+// fairKey returns the fair-share group of a run.
+func (r *Runner) fairKey(run *Run) string {
+	switch r.fairShare {
+	case FairShareWorkspace:
+		return run.Workspace
+	case FairShareWorkflow:
+		return run.Workflow
+	default:
+		return ""
+	}
+}
+
+// checkQueueDepth returns a QueueFullError if no more runs can be queued.
+func (r *Runner) checkQueueDepth(ctx context.Context) error {
+	if r.maxQueueDepth <= 0 {
+		return nil
+	}
+
+	depth := r.queue.len()
+	if r.dist != nil {
+		count, err := r.dist.Queue.CountPendingJobs(ctx)
+		if err != nil {
+			// Accept the run rather than reject it on a transient error
+			slog.Warn("failed to count pending jobs", "error", err)
+			return nil
+		}
+		depth = count
+	}
+
+	if depth >= r.maxQueueDepth {
+		return &QueueFullError{Depth: depth, RetryAfter: r.queueRetryAfter}
+	}
+	return nil
+}
+
+// dispatch starts queued runs while execution slots are free.
+func (r *Runner) dispatch() {
+	for {
+		select {
+		case r.semaphore <- struct{}{}:
+		default:
+			return
+		}
+
+		next := r.queue.pop()
+		if next == nil {
+			<-r.semaphore
+			// A run queued while the slot was held would otherwise wait
+			// for the next run to finish
+			if r.queue.len() > 0 {
+				continue
+			}
+			return
+		}
+
+		r.wg.Add(1)
+		go r.execute(next)
+	}
+}
+
+// cancelQueuedRun marks a run that never left the queue as cancelled.
+func (r *Runner) cancelQueuedRun(run *Run) {
+	run.mu.Lock()
+	run.Status = RunStatusCancelled
+	now := time.Now()
+	run.CompletedAt = &now
+	run.mu.Unlock()
+
+	r.mu.RLock()
+	metrics := r.metrics
+	r.mu.RUnlock()
+	if metrics != nil {
+		metrics.DecrementQueueDepth()
+	}
+
+	r.addLog(run, "info", "Run cancelled before execution started", "")
+	r.addStatus(run, string(RunStatusCancelled), "")
+
+	if be := r.getBackend(); be != nil {
+		_ = be.UpdateRun(context.Background(), r.toBackendRun(run))
+	}
+}
+
+// queuePosition returns the position of a pending run in the local queue or,
+// in distributed mode, the shared job queue.
+func (r *Runner) queuePosition(runID string) int {
+	if r.dist == nil {
+		return r.queue.position(runID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	position, err := r.dist.Queue.JobPosition(ctx, runID)
+	if err != nil {
+		return 0
+	}
+	return position
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+func TestRunQueue_Order(t *testing.T) {
+	q := newRunQueue()
+	push := func(id string, class PriorityClass, key string) {
+		q.push(&Run{ID: id}, class.Level(), key)
+	}
+
+	push("batch-a1", PriorityBatch, "a")
+	push("batch-a2", PriorityBatch, "a")
+	push("batch-b1", PriorityBatch, "b")
+	push("trigger-a", PriorityTrigger, "a")
+	push("interactive-a", PriorityInteractive, "a")
+
+	if got := q.position("batch-b1"); got != 3 {
+		t.Errorf("position(batch-b1) = %d, want 3", got)
+	}
+
+	want := []string{"interactive-a", "trigger-a", "batch-b1", "batch-a1", "batch-a2"}
+	positions := q.positions()
+	for i, id := range want {
+		if positions[id] != i+1 {
+			t.Errorf("positions()[%s] = %d, want %d", id, positions[id], i+1)
+		}
+	}
+	for i, id := range want {
+		next := q.pop()
+		if next == nil || next.run.ID != id {
+			t.Fatalf("pop %d = %v, want %s", i, next, id)
+		}
+	}
+	if q.pop() != nil {
+		t.Error("expected empty queue")
+	}
+
+	// Finished runs no longer count against their group
+	for _, key := range []string{"a", "a", "a", "a", "b"} {
+		q.done(key)
+	}
+	if len(q.running) != 0 {
+		t.Errorf("expected no running groups, got %v", q.running)
+	}
+}
+
+func TestRunQueue_RemoveAndDrain(t *testing.T) {
+	q := newRunQueue()
+	q.push(&Run{ID: "one"}, 0, "")
+	q.push(&Run{ID: "two"}, 0, "")
+
+	if !q.remove("one") || q.remove("one") {
+		t.Error("expected remove to succeed once")
+	}
+	if got := q.position("two"); got != 1 {
+		t.Errorf("position(two) = %d, want 1", got)
+	}
+	if runs := q.drain(); len(runs) != 1 || runs[0].ID != "two" || q.len() != 0 {
+		t.Errorf("unexpected drain result %v", runs)
+	}
+}
+
+func TestParsePriorityClass(t *testing.T) {
+	if p, err := ParsePriorityClass(""); err != nil || p != PriorityInteractive {
+		t.Errorf("ParsePriorityClass(\"\") = %q, %v", p, err)
+	}
+	if p, err := ParsePriorityClass("batch"); err != nil || p != PriorityBatch {
+		t.Errorf("ParsePriorityClass(batch) = %q, %v", p, err)
+	}
+	if _, err := ParsePriorityClass("urgent"); err == nil {
+		t.Error("expected error for unknown class")
+	}
+}
+
+func TestRunner_QueuePriority(t *testing.T) {
+	release := make(chan struct{})
+	adapter := &MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return &ExecutionResult{StepOutputs: map[string]any{}}, nil
+		},
+	}
+	r := New(Config{MaxParallel: 1, MaxQueueDepth: 3}, memory.New(), nil)
+	r.SetAdapter(adapter)
+	defer r.Stop(context.Background())
+
+	submit := func(priority PriorityClass) *RunSnapshot {
+		t.Helper()
+		snapshot, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: testWorkflow, Priority: priority})
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+		return snapshot
+	}
+
+	first := submit(PriorityBatch)
+	waitForRunStatus(t, r, first.ID, RunStatusRunning)
+
+	batch := submit(PriorityBatch)
+	cancelled := submit(PriorityBatch)
+	interactive := submit(PriorityInteractive)
+	if interactive.QueuePosition != 1 || interactive.Priority != PriorityInteractive {
+		t.Errorf("expected interactive run first in queue, got position %d", interactive.QueuePosition)
+	}
+	if snapshot, _ := r.Get(batch.ID); snapshot.QueuePosition != 2 {
+		t.Errorf("expected batch run second in queue, got %d", snapshot.QueuePosition)
+	}
+
+	_, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: testWorkflow})
+	var queueFull *QueueFullError
+	if !errors.As(err, &queueFull) || queueFull.RetryAfter != defaultQueueRetryAfter {
+		t.Fatalf("expected QueueFullError, got %v", err)
+	}
+
+	// Cancelling a queued run removes it without executing it
+	if err := r.Cancel(cancelled.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	waitForRunStatus(t, r, cancelled.ID, RunStatusCancelled)
+
+	close(release)
+	waitForRunStatus(t, r, batch.ID, RunStatusCompleted)
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	var order []string
+	for _, call := range adapter.Calls {
+		order = append(order, call.Opts.RunID)
+	}
+	want := []string{first.ID, interactive.ID, batch.ID}
+	if len(order) != len(want) {
+		t.Fatalf("executed runs = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("executed runs = %v, want %v", order, want)
+			break
+		}
+	}
+}
+
+func TestRunner_StopCancelsQueuedRuns(t *testing.T) {
+	r := New(Config{MaxParallel: 1}, memory.New(), nil)
+	r.SetAdapter(&MockExecutionAdapter{
+		ExecuteWorkflowFunc: func(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts ExecutionOptions) (*ExecutionResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	running, _ := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: testWorkflow})
+	waitForRunStatus(t, r, running.ID, RunStatusRunning)
+	queued, _ := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: testWorkflow})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if snapshot, _ := r.Get(queued.ID); snapshot.Status != RunStatusCancelled {
+		t.Errorf("expected queued run to be cancelled, got %s", snapshot.Status)
+	}
+}
@@ -16,6 +16,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -28,8 +29,11 @@ import (
 	"github.com/tombee/conductor/internal/controller/backend"
 	"github.com/tombee/conductor/internal/controller/checkpoint"
 	controllerremote "github.com/tombee/conductor/internal/controller/remote"
+	"github.com/tombee/conductor/internal/mcp"
 	"github.com/tombee/conductor/internal/remote"
+	"github.com/tombee/conductor/pkg/llm"
 	"github.com/tombee/conductor/pkg/tools"
+	"github.com/tombee/conductor/pkg/tools/approval"
 	"github.com/tombee/conductor/pkg/workflow"
 )
 
@@ -39,6 +43,7 @@ type RunStatus string
 const (
 	RunStatusPending   RunStatus = "pending"
 	RunStatusRunning   RunStatus = "running"
+	RunStatusPaused    RunStatus = "paused"
 	RunStatusCompleted RunStatus = "completed"
 	RunStatusFailed    RunStatus = "failed"
 	RunStatusCancelled RunStatus = "cancelled"
@@ -72,6 +77,9 @@ type Run struct {
 	SourceURL     string         `json:"source_url,omitempty"` // Remote workflow source (for provenance)
 	Workspace     string         `json:"workspace,omitempty"`  // Workspace used for profile resolution
 	Profile       string         `json:"profile,omitempty"`    // Profile used for binding resolution
+	Priority      PriorityClass  `json:"priority,omitempty"`   // Queue priority class
+	FailedStep    string         `json:"failed_step,omitempty"` // Step that failed the run, if any
+	CostUSD       float64        `json:"cost_usd,omitempty"`    // Cost of the run's steps so far
 
 	// Runtime overrides
 	Provider   string        `json:"provider,omitempty"`    // Provider override
@@ -89,12 +97,32 @@ type Run struct {
 	// Workflow source
 	WorkflowDir string `json:"workflow_dir,omitempty"` // Directory containing the workflow file (for relative path resolution)
 
+	// IdempotencyKey is the Idempotency-Key the run was created with
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// ParentRunID is the run that started this one, such as the upstream
+	// run of a run_completed trigger
+	ParentRunID string `json:"parent_run_id,omitempty"`
+
 	// Internal
 	mu         sync.RWMutex // Protects mutable fields (Status, Progress, Output, Error, etc.)
 	ctx        context.Context
 	cancel     context.CancelFunc
 	definition *workflow.Definition
 	bindings   *binding.ResolvedBinding // Resolved bindings from profile
+	reqHash    string                   // Identifies the request that created the run
+	mcpLock    []byte                   // Pinned MCP tools sent with the submission
+	sampler    *sampler                 // Completes MCP sampling requests
+	handoff    atomic.Bool              // Set when execution continues on another controller
+
+	// Outputs of completed steps by step ID, restored when a queued run resumes
+	completedSteps map[string]map[string]any
+	// Edited inputs by step ID, applied when a failed step is retried
+	stepInputs     map[string]map[string]any
+	workflowYAML   []byte      // Definition source, kept to restore a paused run after a restart
+	pauseRequested atomic.Bool // Set to stop the run at the next step boundary
+	budgetExceeded atomic.Bool // Set once budget_exceeded has been sent
+
 	cancelOnce sync.Once
 	stopped    chan struct{}
 }
@@ -117,8 +145,12 @@ type RunSnapshot struct {
 	CreatedAt     time.Time         `json:"created_at"`
 	Logs          []LogEntry        `json:"logs,omitempty"`
 	SourceURL     string            `json:"source_url,omitempty"`
-	Workspace     string            `json:"workspace,omitempty"` // Workspace used for profile resolution
-	Profile       string            `json:"profile,omitempty"`   // Profile used for binding resolution
+	Workspace     string            `json:"workspace,omitempty"`      // Workspace used for profile resolution
+	Profile       string            `json:"profile,omitempty"`        // Profile used for binding resolution
+	Priority      PriorityClass     `json:"priority,omitempty"`       // Queue priority class
+	QueuePosition int               `json:"queue_position,omitempty"` // 1-based position while waiting to start
+	FailedStep    string            `json:"failed_step,omitempty"`    // Step that failed the run, if any
+	CostUSD       float64           `json:"cost_usd,omitempty"`       // Cost of the run's steps so far
 
 	// Runtime overrides
 	Provider   string        `json:"provider,omitempty"`    // Provider override
@@ -128,6 +160,14 @@ type RunSnapshot struct {
 	AllowHosts []string      `json:"allow_hosts,omitempty"` // Extended allowed hosts
 	AllowPaths []string      `json:"allow_paths,omitempty"` // Extended allowed paths
 	MCPDev     bool          `json:"mcp_dev,omitempty"`     // MCP development mode
+
+	// IdempotencyKey is the Idempotency-Key the run was created with
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// ParentRunID is the run that started this one, if any
+	ParentRunID string `json:"parent_run_id,omitempty"`
+	// Replayed is set when Submit returned an earlier run for its
+	// Idempotency-Key instead of starting a new one
+	Replayed bool `json:"-"`
 }
 
 // Progress tracks workflow execution progress.
@@ -140,6 +180,7 @@ type Progress struct {
 // LogEntry represents a log message from a run.
 type LogEntry struct {
 	Timestamp     time.Time      `json:"timestamp"`
+	RunID         string         `json:"run_id,omitempty"`           // Run the entry belongs to (set by SubscribeAll)
 	Type          string         `json:"type,omitempty"`             // Event type: log, step_start, step_complete, status
 	Level         string         `json:"level,omitempty"`            // Log level for type=log entries
 	Message       string         `json:"message,omitempty"`          // Log message or status message
@@ -163,6 +204,21 @@ type LogEntry struct {
 type Config struct {
 	MaxParallel    int
 	DefaultTimeout time.Duration
+
+	// MaxQueueDepth limits runs waiting for an execution slot. Zero means unlimited.
+	MaxQueueDepth int
+
+	// FairShare groups queued runs by "workspace" or "workflow" so that busy
+	// groups do not starve others. Empty means first come, first served.
+	FairShare string
+
+	// QueueRetryAfter is suggested to clients rejected by a full queue.
+	// Defaults to 30 seconds.
+	QueueRetryAfter time.Duration
+
+	// IdempotencyKeyRetention is how long a submission's Idempotency-Key
+	// returns its run instead of starting a new one. Defaults to 24 hours.
+	IdempotencyKeyRetention time.Duration
 }
 
 // ListFilter contains filtering options for listing runs.
@@ -183,6 +239,14 @@ type RunOverrides struct {
 	MCPDev           bool
 	LogLevel         string
 	DebugBreakpoints []string
+	// IdempotencyKey is the Idempotency-Key the run was submitted with, and
+	// RequestHash identifies the request sent with it.
+	IdempotencyKey string
+	RequestHash    string
+	// ParentRunID is the run that started this one, if any
+	ParentRunID string
+	// MCPLock is the mcp.lock sent with the submission
+	MCPLock []byte
 }
 
 // SubmitRequest contains the parameters for submitting a workflow run.
@@ -223,6 +287,21 @@ type SubmitRequest struct {
 	LogLevel string
 	// DebugBreakpoints lists step IDs where execution should pause
 	DebugBreakpoints []string
+	// Priority is the queue priority class. If empty, the run is interactive
+	Priority PriorityClass
+	// IdempotencyKey, if set, makes the submission idempotent: a later
+	// submission with the same key returns this run instead of starting
+	// another
+	IdempotencyKey string
+	// ParentRunID links the run to the run that started it
+	ParentRunID string
+	// MCPLock is the contents of an mcp.lock pinning the workflow's MCP
+	// server tools. It takes precedence over the lockfile in WorkflowDir,
+	// so workflows submitted inline can be pinned too
+	MCPLock []byte
+	// Authorize, if set, is called with the workflow name and workspace once
+	// the definition is parsed. A non-nil error rejects the submission
+	Authorize func(workflow, workspace string) error
 }
 
 // Runner manages workflow executions by composing focused components.
@@ -236,6 +315,16 @@ type Runner struct {
 	semaphore  chan struct{}
 	defTimeout time.Duration
 
+	// Runs waiting for an execution slot
+	queue           *runQueue
+	maxQueueDepth   int
+	fairShare       string
+	queueRetryAfter time.Duration
+
+	// Serializes idempotent submissions per key so concurrent retries start one run
+	idempotencyLocks     keyLocks
+	idempotencyRetention time.Duration
+
 	// Execution adapter for step execution (required for workflow execution)
 	mu      sync.RWMutex
 	adapter ExecutionAdapter
@@ -252,9 +341,27 @@ type Runner struct {
 	// Configuration for profile resolution
 	config *config.Config
 
+	// LLM provider and tiers for MCP sampling requests (optional)
+	samplingProvider llm.Provider
+	samplingTiers    map[string]string
+
+	// Approver for MCP sampling requests that require approval (optional)
+	approver approval.Approver
+
+	// Notifier for run lifecycle events, with the controller's notify defaults (optional)
+	notifier       RunNotifier
+	notifyDefaults *workflow.NotifyDefinition
+
+	// Observer told when runs finish, for run_completed triggers (optional)
+	observer RunObserver
+
 	// Binding resolver for profile-based configuration
 	resolver *binding.Resolver
 
+	// Distributed execution on a shared job queue (optional)
+	dist        *DistributedConfig
+	stopWorkers context.CancelFunc
+
 	// draining indicates the runner is in graceful shutdown mode
 	draining atomic.Bool
 
@@ -270,6 +377,12 @@ func New(cfg Config, be backend.Backend, cm *checkpoint.Manager, opts ...Option)
 	if cfg.DefaultTimeout <= 0 {
 		cfg.DefaultTimeout = 30 * time.Minute
 	}
+	if cfg.QueueRetryAfter <= 0 {
+		cfg.QueueRetryAfter = defaultQueueRetryAfter
+	}
+	if cfg.IdempotencyKeyRetention <= 0 {
+		cfg.IdempotencyKeyRetention = DefaultIdempotencyKeyRetention
+	}
 
 	// Create default components
 	state := NewStateManager(be)
@@ -282,6 +395,13 @@ func New(cfg Config, be backend.Backend, cm *checkpoint.Manager, opts ...Option)
 		logs:       logs,
 		semaphore:  make(chan struct{}, cfg.MaxParallel),
 		defTimeout: cfg.DefaultTimeout,
+
+		queue:           newRunQueue(),
+		maxQueueDepth:   cfg.MaxQueueDepth,
+		fairShare:       cfg.FairShare,
+		queueRetryAfter: cfg.QueueRetryAfter,
+
+		idempotencyRetention: cfg.IdempotencyKeyRetention,
 	}
 
 	// Apply options
@@ -311,6 +431,11 @@ func (r *Runner) ToolRegistry() *tools.Registry {
 	return r.lifecycle.ToolRegistry()
 }
 
+// MCPManager returns the manager for workflow-scoped MCP servers.
+func (r *Runner) MCPManager() mcp.MCPManagerProvider {
+	return r.lifecycle.MCPManager()
+}
+
 // SetMetrics sets the metrics collector for observability.
 func (r *Runner) SetMetrics(metrics MetricsCollector) {
 	r.mu.Lock()
@@ -318,6 +443,39 @@ func (r *Runner) SetMetrics(metrics MetricsCollector) {
 	r.metrics = metrics
 }
 
+// SetSamplingProvider sets the LLM provider and model tiers used to
+// complete sampling requests from MCP servers.
+func (r *Runner) SetSamplingProvider(provider llm.Provider, tiers map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samplingProvider = provider
+	r.samplingTiers = tiers
+}
+
+// SetApprover sets the approver for MCP sampling requests.
+func (r *Runner) SetApprover(approver approval.Approver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.approver = approver
+}
+
+// SetNotifier sets the notifier for run lifecycle events. defaults fill in
+// the fields of each workflow's notify block, and apply to workflows
+// without one.
+func (r *Runner) SetNotifier(notifier RunNotifier, defaults *workflow.NotifyDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifier = notifier
+	r.notifyDefaults = defaults
+}
+
+// SetRunObserver sets the observer told when runs finish.
+func (r *Runner) SetRunObserver(observer RunObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
+}
+
 // SetWorkflowTracer sets the OpenTelemetry tracer for workflow tracing.
 func (r *Runner) SetWorkflowTracer(tracer trace.Tracer) {
 	r.mu.Lock()
@@ -342,6 +500,19 @@ func (r *Runner) Submit(ctx context.Context, req SubmitRequest) (*RunSnapshot, e
 		return r.DryRun(ctx, req)
 	}
 
+	priority, err := ParsePriorityClass(string(req.Priority))
+	if err != nil {
+		return nil, err
+	}
+
+	var hash string
+	if req.IdempotencyKey != "" {
+		if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+			return nil, fmt.Errorf("idempotency key is longer than %d characters", maxIdempotencyKeyLength)
+		}
+		hash = requestHash(req)
+	}
+
 	var workflowYAML []byte
 	var sourceURL string
 
@@ -375,6 +546,9 @@ func (r *Runner) Submit(ctx context.Context, req SubmitRequest) (*RunSnapshot, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse workflow: %w", err)
 	}
+	if err := r.checkSamplingApproval(def); err != nil {
+		return nil, err
+	}
 
 	// Resolve profile and bindings
 	workspace, profile, resolvedBindings, err := r.resolveProfile(ctx, req.Workspace, req.Profile, def)
@@ -382,11 +556,26 @@ func (r *Runner) Submit(ctx context.Context, req SubmitRequest) (*RunSnapshot, e
 		return nil, fmt.Errorf("failed to resolve profile bindings: %w", err)
 	}
 
+	// Check the caller may run this workflow, now that its name is known
+	if req.Authorize != nil {
+		if err := req.Authorize(def.Name, workspace); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reject a malformed lockfile now rather than when the run starts
+	if len(req.MCPLock) > 0 {
+		if _, err := mcp.ParseLockfile(req.MCPLock); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build runtime overrides from request
 	var overrides *RunOverrides
 	if req.Provider != "" || req.Model != "" || req.Timeout != 0 || req.Security != "" ||
 		len(req.AllowHosts) > 0 || len(req.AllowPaths) > 0 || req.MCPDev ||
-		req.LogLevel != "" || len(req.DebugBreakpoints) > 0 {
+		req.LogLevel != "" || len(req.DebugBreakpoints) > 0 || req.IdempotencyKey != "" ||
+		req.ParentRunID != "" || len(req.MCPLock) > 0 {
 		overrides = &RunOverrides{
 			Provider:         req.Provider,
 			Model:            req.Model,
@@ -397,19 +586,24 @@ func (r *Runner) Submit(ctx context.Context, req SubmitRequest) (*RunSnapshot, e
 			MCPDev:           req.MCPDev,
 			LogLevel:         req.LogLevel,
 			DebugBreakpoints: req.DebugBreakpoints,
+			IdempotencyKey:   req.IdempotencyKey,
+			RequestHash:      hash,
+			ParentRunID:      req.ParentRunID,
+			MCPLock:          req.MCPLock,
 		}
 	}
 
-	// Create run via StateManager
-	run, err := r.state.CreateRun(ctx, def, req.Inputs, sourceURL, workspace, profile, resolvedBindings, overrides)
-	if err != nil {
-		return nil, err
+	run, prev, err := r.createRun(ctx, req, hash, def, sourceURL, workspace, profile, resolvedBindings, overrides)
+	if err != nil || prev != nil {
+		return prev, err
 	}
 
 	// Set workflow directory for action path resolution (file.read, shell.run, etc.)
 	if req.WorkflowDir != "" {
 		run.WorkflowDir = req.WorkflowDir
 	}
+	run.Priority = priority
+	run.workflowYAML = workflowYAML
 
 	// Increment queue depth for metrics
 	r.mu.RLock()
@@ -419,17 +613,61 @@ func (r *Runner) Submit(ctx context.Context, req SubmitRequest) (*RunSnapshot, e
 		metrics.IncrementQueueDepth()
 	}
 
+	// In distributed mode, any controller's workers may claim the run
+	if r.dist != nil {
+		snapshot := r.state.Snapshot(run)
+		if err := r.enqueue(ctx, run, workflowYAML); err != nil {
+			return nil, fmt.Errorf("failed to enqueue run: %w", err)
+		}
+		snapshot.QueuePosition = r.queuePosition(run.ID)
+		return snapshot, nil
+	}
+
 	// Create initial snapshot before background execution starts
+	r.queue.push(run, priority.Level(), r.fairKey(run))
 	snapshot := r.state.Snapshot(run)
+	snapshot.QueuePosition = r.queue.position(run.ID)
 
-	// Track goroutine BEFORE spawning to avoid race condition
-	r.wg.Add(1)
-	// Start execution in background
-	go r.execute(run)
+	r.dispatch()
 
 	return snapshot, nil
 }
 
+// createRun creates a submitted run. A retried idempotent submission
+// returns the run it started the first time as prev instead. Submissions
+// with the same key wait for each other only from the lookup until the run
+// is created.
+func (r *Runner) createRun(ctx context.Context, req SubmitRequest, hash string, def *workflow.Definition, sourceURL, workspace, profile string, bindings *binding.ResolvedBinding, overrides *RunOverrides) (run *Run, prev *RunSnapshot, err error) {
+	if req.IdempotencyKey != "" {
+		unlock := r.idempotencyLocks.lock(def.Name + "\x00" + req.IdempotencyKey)
+		defer unlock()
+
+		if prev, err := r.findIdempotentRun(ctx, def.Name, req, hash); err != nil || prev != nil {
+			return nil, prev, err
+		}
+	}
+
+	// A retry is answered even when the queue is full
+	if err := r.checkQueueDepth(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	run, err = r.state.CreateRun(ctx, def, req.Inputs, sourceURL, workspace, profile, bindings, overrides)
+	if errors.Is(err, backend.ErrIdempotencyKeyExists) {
+		// Another controller created the run first, or the key belongs to
+		// an expired run that can be released
+		if prev, err := r.findIdempotentRun(ctx, def.Name, req, hash); err != nil || prev != nil {
+			return nil, prev, err
+		}
+		since := time.Now().Add(-r.idempotencyRetention)
+		if err := r.state.ReleaseIdempotencyKey(ctx, def.Name, req.IdempotencyKey, since); err != nil {
+			return nil, nil, err
+		}
+		run, err = r.state.CreateRun(ctx, def, req.Inputs, sourceURL, workspace, profile, bindings, overrides)
+	}
+	return run, nil, err
+}
+
 // resolveProfile determines the workspace and profile to use, then resolves bindings.
 // Returns: workspace, profile, resolvedBindings, error
 func (r *Runner) resolveProfile(ctx context.Context, requestedWorkspace, requestedProfile string, def *workflow.Definition) (string, string, *binding.ResolvedBinding, error) {
@@ -491,19 +729,37 @@ func (r *Runner) resolveProfile(ctx context.Context, requestedWorkspace, request
 }
 
 // Get returns an immutable snapshot of a run by ID.
+// In distributed mode, runs tracked by other controllers are read from the backend.
 func (r *Runner) Get(id string) (*RunSnapshot, error) {
-	return r.state.GetRun(id)
+	snapshot, err := r.state.GetRun(id)
+	if err != nil && r.dist != nil {
+		return r.remoteRun(id)
+	}
+	if err == nil && snapshot.Status == RunStatusPending {
+		snapshot.QueuePosition = r.queue.position(id)
+	}
+	return snapshot, err
 }
 
 // List returns immutable snapshots of all runs, optionally filtered.
 func (r *Runner) List(filter ListFilter) []*RunSnapshot {
-	return r.state.ListRuns(filter)
+	runs := r.state.ListRuns(filter)
+	positions := r.queue.positions()
+	for _, snapshot := range runs {
+		if snapshot.Status == RunStatusPending {
+			snapshot.QueuePosition = positions[snapshot.ID]
+		}
+	}
+	return runs
 }
 
 // Cancel cancels a running workflow.
 func (r *Runner) Cancel(id string) error {
 	run, exists := r.state.GetRunInternal(id)
 	if !exists {
+		if r.dist != nil {
+			return r.cancelJob(id)
+		}
 		return fmt.Errorf("run not found: %s", id)
 	}
 
@@ -515,14 +771,37 @@ func (r *Runner) Cancel(id string) error {
 	// Also cancel the context for immediate effect
 	run.cancel()
 
+	// Runs still waiting for a slot or paused never reach execute
+	run.mu.RLock()
+	paused := run.Status == RunStatusPaused
+	run.mu.RUnlock()
+	if r.queue.remove(id) {
+		r.cancelQueuedRun(run)
+	} else if paused {
+		r.cancelPausedRun(run)
+	}
+
 	return nil
 }
 
 // Subscribe returns a channel that receives log entries for a run.
+// In distributed mode, runs executing on other controllers are followed
+// through the shared log store.
 func (r *Runner) Subscribe(runID string) (<-chan LogEntry, func()) {
+	if r.dist != nil && r.dist.Logs != nil {
+		if _, local := r.state.GetRunInternal(runID); !local {
+			return r.subscribeRemote(runID)
+		}
+	}
 	return r.logs.Subscribe(runID)
 }
 
+// SubscribeAll returns a channel that receives log entries for every run
+// executing on this controller. Entries carry the ID of their run.
+func (r *Runner) SubscribeAll() (<-chan LogEntry, func()) {
+	return r.logs.SubscribeAll()
+}
+
 // Backend returns the backend storage instance for direct access.
 // This is used by API handlers to access optional backend capabilities
 // like step result storage.
@@ -574,7 +853,19 @@ func (r *Runner) WaitForDrain(ctx context.Context, timeout time.Duration) error
 // Cancels all active run contexts and waits for goroutines to exit.
 // Returns an error if runs don't complete within the context deadline.
 func (r *Runner) Stop(ctx context.Context) error {
-	// Cancel all active runs
+	// Stop claiming jobs; runs interrupted below return to the queue
+	r.mu.Lock()
+	if r.stopWorkers != nil {
+		r.stopWorkers()
+	}
+	r.mu.Unlock()
+
+	// Cancel queued and active runs
+	for _, run := range r.queue.drain() {
+		run.cancelOnce.Do(func() { close(run.stopped) })
+		run.cancel()
+		r.cancelQueuedRun(run)
+	}
 	r.state.CancelAll()
 
 	// Wait for all execute() goroutines to complete
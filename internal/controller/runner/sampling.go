@@ -0,0 +1,285 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tombee/conductor/internal/mcp"
+	"github.com/tombee/conductor/pkg/llm"
+	"github.com/tombee/conductor/pkg/tools/approval"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// sampler completes sampling requests from a run's MCP servers with the
+// runner's LLM provider. Usage is tracked against the run and attributed to
+// the step that was running when the request arrived.
+type sampler struct {
+	run      *Run
+	provider llm.Provider
+	tiers    map[string]string
+	approver approval.Approver
+	logFn    LogFunc
+	notifyFn func(event, message string)
+
+	mu    sync.Mutex
+	usage map[string]*sampledUsage
+}
+
+// sampledUsage is the sampling usage accumulated by one step.
+type sampledUsage struct {
+	tokens llm.TokenUsage
+	cost   float64
+}
+
+// newSampler returns a sampler for the run, or nil if no provider is set.
+func (r *Runner) newSampler(run *Run, logFn LogFunc) *sampler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.samplingProvider == nil {
+		return nil
+	}
+	return &sampler{
+		run:      run,
+		provider: r.samplingProvider,
+		tiers:    r.samplingTiers,
+		approver: r.approver,
+		logFn:    logFn,
+		notifyFn: func(event, message string) { r.notify(run, event, message) },
+		usage:    make(map[string]*sampledUsage),
+	}
+}
+
+// CreateMessage implements mcp.SamplingHandler.
+func (s *sampler) CreateMessage(ctx context.Context, req mcp.SamplingRequest) (*mcp.SamplingResult, error) {
+	s.run.mu.RLock()
+	stepID := s.run.Progress.CurrentStep
+	modelOverride := s.run.Model
+	s.run.mu.RUnlock()
+
+	if err := s.approve(ctx, req); err != nil {
+		s.logFn("warn", fmt.Sprintf("Sampling request from MCP server %s refused: %v", req.ServerName, err), stepID)
+		return nil, err
+	}
+
+	maxTokens, err := s.budget(stepID, req.MaxTokens)
+	if err != nil {
+		s.logFn("warn", fmt.Sprintf("Sampling request from MCP server %s refused: %v", req.ServerName, err), stepID)
+		return nil, err
+	}
+
+	tier := req.Tier()
+	if modelOverride != "" {
+		tier = modelOverride
+	}
+
+	completion := llm.CompletionRequest{
+		Model:         s.resolveModel(tier),
+		MaxTokens:     &maxTokens,
+		StopSequences: req.StopSequences,
+	}
+	if req.Temperature > 0 {
+		temperature := req.Temperature
+		completion.Temperature = &temperature
+	}
+	if req.SystemPrompt != "" {
+		completion.Messages = append(completion.Messages, llm.Message{Role: llm.MessageRoleSystem, Content: req.SystemPrompt})
+	}
+	for _, msg := range req.Messages {
+		role := llm.MessageRoleUser
+		if msg.Role == string(llm.MessageRoleAssistant) {
+			role = llm.MessageRoleAssistant
+		}
+		completion.Messages = append(completion.Messages, llm.Message{Role: role, Content: msg.Text})
+	}
+
+	s.logFn("info", fmt.Sprintf("MCP server %s requested a completion (tier %s)", req.ServerName, tier), stepID)
+
+	start := time.Now()
+	resp, err := s.provider.Complete(ctx, completion)
+	if err != nil {
+		return nil, fmt.Errorf("sampling completion failed: %w", err)
+	}
+
+	llm.TrackUsage(llm.UsageRecord{
+		RequestID:  resp.RequestID,
+		RunID:      s.run.ID,
+		StepName:   stepID,
+		WorkflowID: s.run.WorkflowID,
+		Provider:   s.provider.Name(),
+		Model:      resp.Model,
+		Timestamp:  start,
+		Duration:   time.Since(start),
+		Usage:      resp.Usage,
+	})
+	s.record(stepID, resp.Usage, resp.Cost)
+
+	return &mcp.SamplingResult{
+		Text:       resp.Content,
+		Model:      resp.Model,
+		StopReason: samplingStopReason(resp.FinishReason),
+	}, nil
+}
+
+// checkSamplingApproval refuses workflows with MCP servers that require
+// sampling approval when no approver is configured, since every sampling
+// request they make would be refused.
+func (r *Runner) checkSamplingApproval(def *workflow.Definition) error {
+	r.mu.RLock()
+	approver := r.approver
+	r.mu.RUnlock()
+	if approver != nil {
+		return nil
+	}
+	for _, server := range def.MCPServers {
+		if server.SamplingApproval {
+			return fmt.Errorf("MCP server %s requires sampling approval but no approver is configured", server.Name)
+		}
+	}
+	return nil
+}
+
+// approve runs the approval flow for servers that require it. Requests are
+// refused when approval is required and no approver is configured.
+func (s *sampler) approve(ctx context.Context, req mcp.SamplingRequest) error {
+	requireApproval := false
+	for _, server := range s.run.definition.MCPServers {
+		if server.Name == req.ServerName {
+			requireApproval = server.SamplingApproval
+			break
+		}
+	}
+	if !requireApproval {
+		return nil
+	}
+	if s.approver == nil {
+		return fmt.Errorf("sampling requires approval but no approver is configured")
+	}
+
+	inputs := map[string]interface{}{
+		"run_id":     s.run.ID,
+		"max_tokens": req.MaxTokens,
+		"messages":   len(req.Messages),
+	}
+	if len(req.Messages) > 0 {
+		inputs["prompt"] = req.Messages[len(req.Messages)-1].Text
+	}
+	if s.notifyFn != nil {
+		s.notifyFn(workflow.NotifyEventAwaitingApproval, fmt.Sprintf("MCP server %s requests an LLM completion", req.ServerName))
+	}
+	approved, err := s.approver.Approve(ctx, req.ServerName+":sampling", "MCP server requests an LLM completion", inputs)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return fmt.Errorf("sampling request denied")
+	}
+	return nil
+}
+
+// budget returns the max tokens for a request from the current step. The
+// step's max_tokens caps each request and an agent's token_limit caps the
+// total sampled by the step.
+func (s *sampler) budget(stepID string, requested int) (int, error) {
+	maxTokens := requested
+	for _, step := range s.run.definition.Steps {
+		if step.ID != stepID {
+			continue
+		}
+		if step.MaxTokens != nil && (maxTokens <= 0 || *step.MaxTokens < maxTokens) {
+			maxTokens = *step.MaxTokens
+		}
+		if step.AgentConfig != nil && step.AgentConfig.TokenLimit > 0 {
+			s.mu.Lock()
+			var used int
+			if u := s.usage[stepID]; u != nil {
+				used = u.tokens.TotalTokens
+			}
+			s.mu.Unlock()
+
+			remaining := step.AgentConfig.TokenLimit - used
+			if remaining <= 0 {
+				return 0, fmt.Errorf("step %s token limit of %d reached", stepID, step.AgentConfig.TokenLimit)
+			}
+			if maxTokens <= 0 || remaining < maxTokens {
+				maxTokens = remaining
+			}
+		}
+		break
+	}
+	if maxTokens <= 0 {
+		return 0, fmt.Errorf("max_tokens must be positive")
+	}
+	return maxTokens, nil
+}
+
+// resolveModel maps a tier to the provider's model name.
+func (s *sampler) resolveModel(tier string) string {
+	if tierRef, ok := s.tiers[tier]; ok {
+		if idx := strings.Index(tierRef, "/"); idx >= 0 {
+			return tierRef[idx+1:]
+		}
+		return tierRef
+	}
+	return tier
+}
+
+func (s *sampler) record(stepID string, tokens llm.TokenUsage, cost float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.usage[stepID]
+	if u == nil {
+		u = &sampledUsage{}
+		s.usage[stepID] = u
+	}
+	u.tokens.InputTokens += tokens.InputTokens
+	u.tokens.OutputTokens += tokens.OutputTokens
+	u.tokens.TotalTokens += tokens.TotalTokens
+	u.tokens.CacheCreationTokens += tokens.CacheCreationTokens
+	u.tokens.CacheReadTokens += tokens.CacheReadTokens
+	u.cost += cost
+}
+
+// stepUsage returns and clears the sampling usage recorded for a step.
+// It is safe to call on a nil sampler.
+func (s *sampler) stepUsage(stepID string) sampledUsage {
+	if s == nil {
+		return sampledUsage{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.usage[stepID]
+	if u == nil {
+		return sampledUsage{}
+	}
+	delete(s.usage, stepID)
+	return *u
+}
+
+func samplingStopReason(reason llm.FinishReason) string {
+	switch reason {
+	case llm.FinishReasonLength:
+		return "maxTokens"
+	default:
+		return "endTurn"
+	}
+}
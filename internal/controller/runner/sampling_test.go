@@ -0,0 +1,255 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/mcp"
+	"github.com/tombee/conductor/pkg/llm"
+	"github.com/tombee/conductor/pkg/tools/approval"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// samplingProvider records completion requests and returns fixed usage.
+type samplingProvider struct {
+	requests []llm.CompletionRequest
+}
+
+func (p *samplingProvider) Name() string { return "mock" }
+
+func (p *samplingProvider) Capabilities() llm.Capabilities { return llm.Capabilities{} }
+
+func (p *samplingProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	p.requests = append(p.requests, req)
+	return &llm.CompletionResponse{
+		Content:      "sampled",
+		Model:        req.Model,
+		FinishReason: llm.FinishReasonStop,
+		Usage:        llm.TokenUsage{InputTokens: 30, OutputTokens: 20, TotalTokens: 50},
+		Cost:         0.01,
+	}, nil
+}
+
+func (p *samplingProvider) Stream(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+	return nil, nil
+}
+
+func newSamplingTestRun(servers []workflow.MCPServerConfig, steps []workflow.StepDefinition) *Run {
+	return &Run{
+		ID:         "run-1",
+		WorkflowID: "wf-1",
+		Progress:   &Progress{CurrentStep: "summarize"},
+		definition: &workflow.Definition{Name: "test", MCPServers: servers, Steps: steps},
+	}
+}
+
+func TestSampler_CreateMessage(t *testing.T) {
+	provider := &samplingProvider{}
+	r := New(Config{}, nil, nil)
+	r.SetSamplingProvider(provider, map[string]string{"fast": "mock/small-model", "balanced": "mock/mid-model"})
+
+	maxTokens := 100
+	run := newSamplingTestRun(
+		[]workflow.MCPServerConfig{{Name: "docs", AllowSampling: true}},
+		[]workflow.StepDefinition{{ID: "summarize", MaxTokens: &maxTokens}},
+	)
+	s := r.newSampler(run, func(level, message, stepID string) {})
+
+	result, err := s.CreateMessage(context.Background(), mcp.SamplingRequest{
+		ServerName:    "docs",
+		SystemPrompt:  "Be brief",
+		Messages:      []mcp.SamplingMessage{{Role: "user", Text: "Summarize"}},
+		MaxTokens:     500,
+		SpeedPriority: 0.9,
+	})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if result.Text != "sampled" || result.StopReason != "endTurn" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	req := provider.requests[0]
+	if req.Model != "small-model" {
+		t.Errorf("expected fast tier model, got %q", req.Model)
+	}
+	if req.MaxTokens == nil || *req.MaxTokens != 100 {
+		t.Errorf("expected max tokens capped by step, got %v", req.MaxTokens)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != llm.MessageRoleSystem {
+		t.Errorf("expected system and user messages, got %+v", req.Messages)
+	}
+
+	usage := s.stepUsage("summarize")
+	if usage.tokens.TotalTokens != 50 || usage.cost != 0.01 {
+		t.Errorf("unexpected step usage %+v", usage)
+	}
+	if usage := s.stepUsage("summarize"); usage.tokens.TotalTokens != 0 {
+		t.Error("expected step usage to be cleared after it is read")
+	}
+}
+
+func TestSampler_ModelOverride(t *testing.T) {
+	provider := &samplingProvider{}
+	r := New(Config{}, nil, nil)
+	r.SetSamplingProvider(provider, map[string]string{"strategic": "mock/large-model"})
+
+	run := newSamplingTestRun([]workflow.MCPServerConfig{{Name: "docs", AllowSampling: true}}, nil)
+	run.Model = "strategic"
+	s := r.newSampler(run, func(level, message, stepID string) {})
+
+	if _, err := s.CreateMessage(context.Background(), mcp.SamplingRequest{ServerName: "docs", MaxTokens: 10}); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if provider.requests[0].Model != "large-model" {
+		t.Errorf("expected run model override, got %q", provider.requests[0].Model)
+	}
+}
+
+func TestSampler_TokenLimit(t *testing.T) {
+	provider := &samplingProvider{}
+	r := New(Config{}, nil, nil)
+	r.SetSamplingProvider(provider, nil)
+
+	run := newSamplingTestRun(
+		[]workflow.MCPServerConfig{{Name: "docs", AllowSampling: true}},
+		[]workflow.StepDefinition{{ID: "summarize", AgentConfig: &workflow.AgentConfigDefinition{TokenLimit: 60}}},
+	)
+	s := r.newSampler(run, func(level, message, stepID string) {})
+	req := mcp.SamplingRequest{ServerName: "docs", MaxTokens: 100}
+
+	if _, err := s.CreateMessage(context.Background(), req); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if *provider.requests[0].MaxTokens != 60 {
+		t.Errorf("expected max tokens capped by token limit, got %d", *provider.requests[0].MaxTokens)
+	}
+
+	if _, err := s.CreateMessage(context.Background(), req); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if *provider.requests[1].MaxTokens != 10 {
+		t.Errorf("expected remaining budget, got %d", *provider.requests[1].MaxTokens)
+	}
+
+	_, err := s.CreateMessage(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "token limit") {
+		t.Errorf("expected token limit error, got %v", err)
+	}
+}
+
+func TestSampler_Approval(t *testing.T) {
+	servers := []workflow.MCPServerConfig{{Name: "docs", AllowSampling: true, SamplingApproval: true}}
+	req := mcp.SamplingRequest{ServerName: "docs", MaxTokens: 10}
+
+	t.Run("no approver", func(t *testing.T) {
+		provider := &samplingProvider{}
+		r := New(Config{}, nil, nil)
+		r.SetSamplingProvider(provider, nil)
+		s := r.newSampler(newSamplingTestRun(servers, nil), func(level, message, stepID string) {})
+
+		if _, err := s.CreateMessage(context.Background(), req); err == nil {
+			t.Error("expected request to be refused without an approver")
+		}
+		if len(provider.requests) != 0 {
+			t.Error("expected no completion")
+		}
+	})
+
+	t.Run("approved", func(t *testing.T) {
+		provider := &samplingProvider{}
+		r := New(Config{}, nil, nil)
+		r.SetSamplingProvider(provider, nil)
+		r.SetApprover(approval.NewUnattendedApprover(map[string]bool{"docs:sampling": true}))
+		s := r.newSampler(newSamplingTestRun(servers, nil), func(level, message, stepID string) {})
+
+		if _, err := s.CreateMessage(context.Background(), req); err != nil {
+			t.Fatalf("CreateMessage() error = %v", err)
+		}
+	})
+
+	t.Run("approvals queue", func(t *testing.T) {
+		provider := &samplingProvider{}
+		queue := approval.NewQueue(time.Minute)
+		r := New(Config{}, nil, nil)
+		r.SetSamplingProvider(provider, nil)
+		r.SetApprover(queue)
+		s := r.newSampler(newSamplingTestRun(servers, nil), func(level, message, stepID string) {})
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := s.CreateMessage(context.Background(), req)
+			done <- err
+		}()
+
+		var pending []approval.Request
+		deadline := time.Now().Add(2 * time.Second)
+		for len(pending) == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+			pending = queue.Pending()
+		}
+		if len(pending) != 1 || pending[0].ToolName != "docs:sampling" {
+			t.Fatalf("Pending() = %+v, want one sampling request", pending)
+		}
+		if err := queue.Decide(pending[0].ID, true); err != nil {
+			t.Fatalf("Decide() error = %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("CreateMessage() error = %v", err)
+		}
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		r := New(Config{}, nil, nil)
+		r.SetSamplingProvider(&samplingProvider{}, nil)
+		r.SetApprover(approval.NewUnattendedApprover(nil))
+		s := r.newSampler(newSamplingTestRun(servers, nil), func(level, message, stepID string) {})
+
+		if _, err := s.CreateMessage(context.Background(), req); err == nil {
+			t.Error("expected request to be denied")
+		}
+	})
+}
+
+func TestRunner_NewSampler_NoProvider(t *testing.T) {
+	r := New(Config{}, nil, nil)
+	if s := r.newSampler(newSamplingTestRun(nil, nil), nil); s != nil {
+		t.Error("expected no sampler without a provider")
+	}
+}
+
+func TestRunner_SubmitSamplingApprovalWithoutApprover(t *testing.T) {
+	r := New(Config{}, nil, nil)
+	yaml := `
+name: sampling
+mcp_servers:
+  - name: docs
+    command: docs-server
+    allow_sampling: true
+    sampling_approval: true
+steps:
+  - id: first
+    type: llm
+    prompt: "one"
+`
+	_, err := r.Submit(context.Background(), SubmitRequest{WorkflowYAML: []byte(yaml)})
+	if err == nil || !strings.Contains(err.Error(), "no approver") {
+		t.Errorf("Submit() error = %v, want missing approver error", err)
+	}
+}
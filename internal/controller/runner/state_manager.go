@@ -16,6 +16,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -45,16 +46,100 @@ func NewStateManager(be backend.Backend) *StateManager {
 
 // CreateRun creates a new run and persists to backend (best-effort).
 // Returns the created Run (internal) for further processing.
+// Returns backend.ErrIdempotencyKeyExists, without creating the run, if the
+// backend already has a run of the workflow with the run's idempotency key.
 func (s *StateManager) CreateRun(ctx context.Context, def *workflow.Definition, inputs map[string]any, sourceURL, workspace, profile string, bindings *binding.ResolvedBinding, overrides *RunOverrides) (*Run, error) {
-	runID := uuid.New().String()[:8]
+	// Extract correlation ID from context (set by HTTP middleware)
+	correlationID := string(tracing.FromContextOrEmpty(ctx))
+
+	run := newRun(uuid.New().String()[:8], correlationID, def, inputs, sourceURL, workspace, profile, bindings, overrides)
+
+	s.mu.Lock()
+	s.runs[run.ID] = run
+	s.mu.Unlock()
+
+	// Persist to backend (best-effort)
+	if s.backend != nil {
+		beRun := s.toBackendRun(run)
+		if err := s.backend.CreateRun(ctx, beRun); err != nil {
+			if errors.Is(err, backend.ErrIdempotencyKeyExists) {
+				s.mu.Lock()
+				delete(s.runs, run.ID)
+				s.mu.Unlock()
+				run.cancel()
+				return nil, err
+			}
+			// Log error but continue - in-memory state is the source of truth
+			// Caller can add log via LogAggregator if needed
+			_ = err
+		}
+	}
+
+	return run, nil
+}
+
+// ReleaseIdempotencyKey clears an expired idempotency key from the
+// workflow's stored runs created before a time, so that it can be used for
+// a new run.
+func (s *StateManager) ReleaseIdempotencyKey(ctx context.Context, workflow, key string, before time.Time) error {
+	store, ok := s.backend.(backend.RunIdempotencyStore)
+	if !ok {
+		return nil
+	}
+	return store.ReleaseIdempotencyKey(ctx, workflow, key, before)
+}
+
+// FindRunByIdempotencyKey returns the newest run of a workflow created with
+// an Idempotency-Key since a time, and the hash of the request that created
+// it. Returns a nil snapshot if there is none.
+func (s *StateManager) FindRunByIdempotencyKey(ctx context.Context, workflow, key string, since time.Time) (*RunSnapshot, string, error) {
+	s.mu.RLock()
+	var found *Run
+	for _, run := range s.runs {
+		if run.Workflow != workflow || run.IdempotencyKey != key || run.CreatedAt.Before(since) {
+			continue
+		}
+		if found == nil || run.CreatedAt.After(found.CreatedAt) {
+			found = run
+		}
+	}
+	if found != nil {
+		snapshot := s.snapshotRun(found)
+		s.mu.RUnlock()
+		return snapshot, found.reqHash, nil
+	}
+	s.mu.RUnlock()
+
+	// Runs created before a restart or by another controller
+	store, ok := s.backend.(backend.RunIdempotencyStore)
+	if !ok {
+		return nil, "", nil
+	}
+	beRun, err := store.FindRunByIdempotencyKey(ctx, workflow, key, since)
+	if errors.Is(err, backend.ErrRunNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	return s.backendRunToSnapshot(beRun), beRun.RequestHash, nil
+}
+
+// AddRun adds a run that already exists in the backend to the in-memory state.
+// Used for runs claimed from the distributed job queue.
+func (s *StateManager) AddRun(run *Run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.ID] = run
+}
+
+// newRun builds a pending run with its own execution context.
+func newRun(runID, correlationID string, def *workflow.Definition, inputs map[string]any, sourceURL, workspace, profile string, bindings *binding.ResolvedBinding, overrides *RunOverrides) *Run {
 	// Use context.Background() as the parent for workflow execution.
-	// The HTTP request context (ctx) would get cancelled when the request ends,
+	// The HTTP request context would get cancelled when the request ends,
 	// which would incorrectly cancel long-running workflows.
 	runCtx, cancel := context.WithCancel(context.Background())
 
-	// Extract correlation ID from context (set by HTTP middleware)
-	correlationID := string(tracing.FromContextOrEmpty(ctx))
-
 	run := &Run{
 		ID:            runID,
 		WorkflowID:    def.Name,
@@ -87,23 +172,13 @@ func (s *StateManager) CreateRun(ctx context.Context, def *workflow.Definition,
 		run.MCPDev = overrides.MCPDev
 		run.LogLevel = overrides.LogLevel
 		run.DebugBreakpoints = overrides.DebugBreakpoints
+		run.IdempotencyKey = overrides.IdempotencyKey
+		run.reqHash = overrides.RequestHash
+		run.ParentRunID = overrides.ParentRunID
+		run.mcpLock = overrides.MCPLock
 	}
 
-	s.mu.Lock()
-	s.runs[runID] = run
-	s.mu.Unlock()
-
-	// Persist to backend (best-effort)
-	if s.backend != nil {
-		beRun := s.toBackendRun(run)
-		if err := s.backend.CreateRun(ctx, beRun); err != nil {
-			// Log error but continue - in-memory state is the source of truth
-			// Caller can add log via LogAggregator if needed
-			_ = err
-		}
-	}
-
-	return run, nil
+	return run
 }
 
 // GetRun returns an immutable snapshot of a run by ID.
@@ -193,9 +268,11 @@ func (s *StateManager) ActiveRunCount() int {
 
 	count := 0
 	for _, run := range s.runs {
+		run.mu.RLock()
 		if run.Status == RunStatusRunning || run.Status == RunStatusPending {
 			count++
 		}
+		run.mu.RUnlock()
 	}
 	return count
 }
@@ -206,7 +283,10 @@ func (s *StateManager) CancelAll() {
 	defer s.mu.RUnlock()
 
 	for _, run := range s.runs {
-		if run.Status == RunStatusRunning || run.Status == RunStatusPending {
+		run.mu.RLock()
+		active := run.Status == RunStatusRunning || run.Status == RunStatusPending
+		run.mu.RUnlock()
+		if active {
 			run.cancel()
 		}
 	}
@@ -293,6 +373,9 @@ func (s *StateManager) snapshotRun(run *Run) *RunSnapshot {
 		SourceURL:     run.SourceURL,
 		Workspace:     run.Workspace,
 		Profile:       run.Profile,
+		Priority:      run.Priority,
+		FailedStep:    run.FailedStep,
+		CostUSD:       run.CostUSD,
 		Provider:      run.Provider,
 		Model:         run.Model,
 		Timeout:       run.Timeout,
@@ -300,6 +383,9 @@ func (s *StateManager) snapshotRun(run *Run) *RunSnapshot {
 		AllowHosts:    allowHosts,
 		AllowPaths:    allowPaths,
 		MCPDev:        run.MCPDev,
+
+		IdempotencyKey: run.IdempotencyKey,
+		ParentRunID:    run.ParentRunID,
 	}
 }
 
@@ -332,7 +418,11 @@ func (s *StateManager) backendRunToSnapshot(beRun *backend.Run) *RunSnapshot {
 		StartedAt:     beRun.StartedAt,
 		CompletedAt:   beRun.CompletedAt,
 		CreatedAt:     beRun.CreatedAt,
-		// Note: Fields like SourceURL, Workspace, Profile, Provider, Model,
+		Workspace:     beRun.Workspace,
+
+		IdempotencyKey: beRun.IdempotencyKey,
+		ParentRunID:    beRun.ParentRunID,
+		// Note: Fields like SourceURL, Profile, Provider, Model,
 		// Timeout, Security, AllowHosts, AllowPaths, MCPDev are not persisted
 		// in the backend, so they will be empty for historical runs.
 	}
@@ -354,6 +444,11 @@ func (s *StateManager) toBackendRun(run *Run) *backend.Run {
 		Output:        run.Output,
 		Error:         run.Error,
 		CreatedAt:     run.CreatedAt,
+		ParentRunID:   run.ParentRunID,
+		Workspace:     run.Workspace,
+
+		IdempotencyKey: run.IdempotencyKey,
+		RequestHash:    run.reqHash,
 	}
 	if run.Progress != nil {
 		beRun.CurrentStep = run.Progress.CurrentStep
@@ -0,0 +1,72 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/tombee/conductor/internal/mcp"
+	"github.com/tombee/conductor/pkg/security"
+)
+
+// mcpToolPolicy builds the policy that verifies a run's MCP server tools.
+// The actions come from the run's security profile, falling back to the
+// configured default profile. The lockfile is the one sent with the run, or
+// else the one in the workflow directory.
+func (r *Runner) mcpToolPolicy(run *Run) (*mcp.ToolPolicy, error) {
+	if len(run.definition.MCPServers) == 0 {
+		return nil, nil
+	}
+
+	r.mu.RLock()
+	cfg := r.config
+	r.mu.RUnlock()
+
+	profileName := run.Security
+	var customProfiles map[string]*security.SecurityProfile
+	if cfg != nil {
+		if profileName == "" {
+			profileName = cfg.Security.DefaultProfile
+		}
+		customProfiles = cfg.Security.Profiles
+	}
+	if profileName == "" {
+		profileName = security.ProfileStandard
+	}
+
+	profile, err := security.LoadProfile(profileName, customProfiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load security profile: %w", err)
+	}
+
+	policy := &mcp.ToolPolicy{Actions: profile.MCP}
+	switch {
+	case len(run.mcpLock) > 0:
+		lock, err := mcp.ParseLockfile(run.mcpLock)
+		if err != nil {
+			return nil, err
+		}
+		policy.Lockfile = lock
+	case run.WorkflowDir != "":
+		lock, err := mcp.LoadLockfile(mcp.LockfilePath(run.WorkflowDir))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		policy.Lockfile = lock
+	}
+	return policy, nil
+}
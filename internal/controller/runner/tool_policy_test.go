@@ -0,0 +1,93 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tombee/conductor/internal/mcp"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+func TestRunner_MCPToolPolicy_Lockfile(t *testing.T) {
+	tools := []mcp.ToolDefinition{{Name: "search", Description: "Search issues"}}
+	lockFor := func(source string) []byte {
+		t.Helper()
+		locked, err := mcp.NewServerLock(source, tools)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lock := mcp.NewLockfile()
+		lock.Servers["github"] = locked
+		path := filepath.Join(t.TempDir(), mcp.LockfileName)
+		if err := lock.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(mcp.LockfilePath(dir), lockFor("from-dir"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	def := &workflow.Definition{
+		Name:       "pinned",
+		MCPServers: []workflow.MCPServerConfig{{Name: "github", Command: "npx"}},
+	}
+	r := New(Config{MaxParallel: 1}, nil, nil)
+
+	// A lockfile sent with the run is used over the workflow directory's
+	run := newRun("run-1", "", def, nil, "", "", "", nil, &RunOverrides{MCPLock: lockFor("submitted")})
+	run.WorkflowDir = dir
+	policy, err := r.mcpToolPolicy(run)
+	if err != nil {
+		t.Fatalf("mcpToolPolicy() error = %v", err)
+	}
+	if policy.Lockfile == nil || policy.Lockfile.Servers["github"].Source != "submitted" {
+		t.Errorf("Lockfile = %+v, want the submitted pins", policy.Lockfile)
+	}
+
+	run = newRun("run-2", "", def, nil, "", "", "", nil, nil)
+	run.WorkflowDir = dir
+	if policy, err = r.mcpToolPolicy(run); err != nil || policy.Lockfile.Servers["github"].Source != "from-dir" {
+		t.Errorf("mcpToolPolicy() = %+v, %v; want the workflow directory's pins", policy, err)
+	}
+
+	// Without any lockfile the tools are trusted with a warning
+	run = newRun("run-3", "", def, nil, "", "", "", nil, nil)
+	if policy, err = r.mcpToolPolicy(run); err != nil {
+		t.Fatalf("mcpToolPolicy() error = %v", err)
+	}
+	warnings, err := policy.Check("github", "npx", tools)
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("Check() = %v, %v; want a not pinned warning", warnings, err)
+	}
+
+	// A malformed lockfile is rejected when the run is submitted
+	_, err = r.Submit(context.Background(), SubmitRequest{
+		WorkflowYAML: []byte("name: pinned\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n"),
+		MCPLock:      []byte("version: 99\n"),
+	})
+	if err == nil {
+		t.Error("expected an unsupported lockfile to be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar is a named set of days and periods, such as holidays or a change
+// freeze, when the schedules that exclude it do not run.
+type Calendar struct {
+	// Dates are whole days (2006-01-02) in each schedule's timezone
+	Dates []string `yaml:"dates,omitempty" json:"dates,omitempty"`
+
+	// Ranges are periods from From up to (not including) To
+	Ranges []CalendarRange `yaml:"ranges,omitempty" json:"ranges,omitempty"`
+}
+
+// CalendarRange is a period of a calendar. Times without an offset are in
+// each schedule's timezone.
+type CalendarRange struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// exclusions are the days and periods a schedule's calendars exclude, in
+// the schedule's timezone.
+type exclusions struct {
+	dates  map[string]bool
+	ranges [][2]time.Time
+}
+
+// compileExclusions resolves the named calendars for a schedule in loc.
+func compileExclusions(names []string, calendars map[string]Calendar, loc *time.Location) (*exclusions, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	ex := &exclusions{dates: make(map[string]bool)}
+	for _, name := range names {
+		cal, ok := calendars[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown calendar %q", name)
+		}
+		for _, date := range cal.Dates {
+			day, err := time.ParseInLocation("2006-01-02", date, loc)
+			if err != nil {
+				return nil, fmt.Errorf("calendar %s: invalid date %q (use 2006-01-02)", name, date)
+			}
+			ex.dates[day.Format("2006-01-02")] = true
+		}
+		for _, r := range cal.Ranges {
+			from, err := parseTime(r.From, loc)
+			if err != nil {
+				return nil, fmt.Errorf("calendar %s: %w", name, err)
+			}
+			to, err := parseTime(r.To, loc)
+			if err != nil {
+				return nil, fmt.Errorf("calendar %s: %w", name, err)
+			}
+			if !to.After(from) {
+				return nil, fmt.Errorf("calendar %s: range ends before it starts: %s to %s", name, r.From, r.To)
+			}
+			ex.ranges = append(ex.ranges, [2]time.Time{from, to})
+		}
+	}
+	return ex, nil
+}
+
+// until returns when the exclusion covering t ends, or the zero time if t
+// is not excluded.
+func (ex *exclusions) until(t time.Time) time.Time {
+	if ex == nil {
+		return time.Time{}
+	}
+	if ex.dates[t.Format("2006-01-02")] {
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	}
+	for _, r := range ex.ranges {
+		if !t.Before(r[0]) && t.Before(r[1]) {
+			return r[1]
+		}
+	}
+	return time.Time{}
+}
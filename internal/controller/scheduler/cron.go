@@ -21,22 +21,53 @@ import (
 	"time"
 )
 
-// CronExpr represents a parsed cron expression.
+// CronExpr represents a parsed schedule expression: a cron expression, an
+// @every interval or a one-off time.
 type CronExpr struct {
+	second     []int // 0-59
 	minute     []int // 0-59
 	hour       []int // 0-23
 	dayOfMonth []int // 1-31
 	month      []int // 1-12
 	dayOfWeek  []int // 0-6 (0 = Sunday)
+
+	// Day modifiers
+	lastDay        bool     // L: last day of the month
+	lastWeekday    bool     // LW: last weekday (Monday-Friday) of the month
+	nearestWeekday []int    // nW: weekday nearest day n of the month
+	lastDayOfWeek  []int    // nL: last weekday n of the month
+	nthDayOfWeek   [][2]int // n#k: kth weekday n of the month
+
+	every time.Duration // @every interval
+	at    time.Time     // One-off time
 }
 
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
 // ParseCron parses a cron expression.
-// Format: minute hour day-of-month month day-of-week
+// Format: [second] minute hour day-of-month month day-of-week
 // Examples:
 //   - "0 * * * *" - every hour at minute 0
 //   - "*/15 * * * *" - every 15 minutes
 //   - "0 9 * * 1-5" - 9 AM on weekdays
 //   - "0 0 1 * *" - midnight on the first of each month
+//   - "*/30 * * * * *" - every 30 seconds
+//   - "0 17 L * *" - 5 PM on the last day of each month
+//   - "0 9 * * MON#1" - 9 AM on the first Monday of each month
+//   - "@every 90m" - every 90 minutes
+//
+// Months and days of the week can be given by name (JAN, MON). Day 7 is also
+// Sunday. "?" is the same as "*" in the day fields. The day-of-month field
+// accepts L (last day), LW (last weekday) and nW (weekday nearest day n);
+// the day-of-week field accepts nL (last weekday n) and n#k (kth weekday n).
 func ParseCron(expr string) (*CronExpr, error) {
 	// Handle special expressions
 	switch strings.ToLower(expr) {
@@ -52,14 +83,33 @@ func ParseCron(expr string) (*CronExpr, error) {
 		expr = "0 0 1 1 *"
 	}
 
-	fields := strings.Fields(expr)
-	if len(fields) != 5 {
-		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	if rest, ok := strings.CutPrefix(strings.ToLower(expr), "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every interval: %w", err)
+		}
+		if every < time.Second {
+			return nil, fmt.Errorf("@every interval must be at least 1s, got %s", every)
+		}
+		return &CronExpr{every: every}, nil
 	}
 
-	c := &CronExpr{}
+	fields := strings.Fields(strings.ToUpper(expr))
+	c := &CronExpr{second: []int{0}}
 	var err error
 
+	switch len(fields) {
+	case 5:
+	case 6:
+		c.second, err = parseField(fields[0], 0, 59)
+		if err != nil {
+			return nil, fmt.Errorf("invalid second field: %w", err)
+		}
+		fields = fields[1:]
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, got %d", len(fields))
+	}
+
 	c.minute, err = parseField(fields[0], 0, 59)
 	if err != nil {
 		return nil, fmt.Errorf("invalid minute field: %w", err)
@@ -70,24 +120,130 @@ func ParseCron(expr string) (*CronExpr, error) {
 		return nil, fmt.Errorf("invalid hour field: %w", err)
 	}
 
-	c.dayOfMonth, err = parseField(fields[2], 1, 31)
-	if err != nil {
+	if err := c.parseDayOfMonth(fields[2]); err != nil {
 		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
 	}
 
-	c.month, err = parseField(fields[3], 1, 12)
+	c.month, err = parseField(replaceNames(fields[3], monthNames), 1, 12)
 	if err != nil {
 		return nil, fmt.Errorf("invalid month field: %w", err)
 	}
 
-	c.dayOfWeek, err = parseField(fields[4], 0, 6)
-	if err != nil {
+	if err := c.parseDayOfWeek(replaceNames(fields[4], dayNames)); err != nil {
 		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
 	}
 
 	return c, nil
 }
 
+// ParseAt parses the time of a one-off schedule, in loc unless it has an
+// offset. The expression's Next returns that time once.
+func ParseAt(value string, loc *time.Location) (*CronExpr, error) {
+	at, err := parseTime(value, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &CronExpr{at: at}, nil
+}
+
+// parseTime parses a date and time (2006-01-02T15:04, with optional
+// seconds or offset) or a date, in loc unless it has an offset.
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use 2006-01-02T15:04)", value)
+}
+
+// replaceNames replaces month or day names in a field with their numbers.
+func replaceNames(field string, names map[string]int) string {
+	for name, n := range names {
+		field = strings.ReplaceAll(field, name, strconv.Itoa(n))
+	}
+	return field
+}
+
+// parseDayOfMonth parses the day-of-month field and its L and W modifiers.
+func (c *CronExpr) parseDayOfMonth(field string) error {
+	if field == "?" {
+		field = "*"
+	}
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case part == "L":
+			c.lastDay = true
+		case part == "LW":
+			c.lastWeekday = true
+		case strings.HasSuffix(part, "W"):
+			day, err := strconv.Atoi(strings.TrimSuffix(part, "W"))
+			if err != nil || day < 1 || day > 31 {
+				return fmt.Errorf("invalid nearest weekday: %s", part)
+			}
+			c.nearestWeekday = append(c.nearestWeekday, day)
+		default:
+			values, err := parseField(part, 1, 31)
+			if err != nil {
+				return err
+			}
+			c.dayOfMonth = unique(append(c.dayOfMonth, values...))
+		}
+	}
+	return nil
+}
+
+// parseDayOfWeek parses the day-of-week field and its L and # modifiers.
+func (c *CronExpr) parseDayOfWeek(field string) error {
+	if field == "?" {
+		field = "*"
+	}
+	for _, part := range strings.Split(field, ",") {
+		if day, n, ok := strings.Cut(part, "#"); ok {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return err
+			}
+			nth, err := strconv.Atoi(n)
+			if err != nil || nth < 1 || nth > 5 {
+				return fmt.Errorf("invalid week of the month: %s", part)
+			}
+			c.nthDayOfWeek = append(c.nthDayOfWeek, [2]int{weekday, nth})
+			continue
+		}
+		if day, ok := strings.CutSuffix(part, "L"); ok {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return err
+			}
+			c.lastDayOfWeek = append(c.lastDayOfWeek, weekday)
+			continue
+		}
+
+		values, err := parseField(part, 0, 7)
+		if err != nil {
+			return err
+		}
+		for i, v := range values {
+			values[i] = v % 7 // 7 is Sunday
+		}
+		c.dayOfWeek = unique(append(c.dayOfWeek, values...))
+	}
+	return nil
+}
+
+// parseWeekday parses a single day of the week, 0-7.
+func parseWeekday(s string) (int, error) {
+	day, err := strconv.Atoi(s)
+	if err != nil || day < 0 || day > 7 {
+		return 0, fmt.Errorf("invalid day of week: %s", s)
+	}
+	return day % 7, nil
+}
+
 // parseField parses a single cron field.
 func parseField(field string, min, max int) ([]int, error) {
 	// Handle wildcard
@@ -118,8 +274,9 @@ func parseField(field string, min, max int) ([]int, error) {
 
 // parseFieldPart parses a single part of a cron field (handles ranges and steps).
 func parseFieldPart(part string, min, max int) ([]int, error) {
-	// Handle step values (*/5 or 1-10/2)
+	// Handle step values (*/5, 1-10/2 or 5/15)
 	var step int = 1
+	hasStep := false
 	if idx := strings.Index(part, "/"); idx != -1 {
 		stepStr := part[idx+1:]
 		var err error
@@ -128,6 +285,7 @@ func parseFieldPart(part string, min, max int) ([]int, error) {
 			return nil, fmt.Errorf("invalid step: %s", stepStr)
 		}
 		part = part[:idx]
+		hasStep = true
 	}
 
 	var start, end int
@@ -147,13 +305,16 @@ func parseFieldPart(part string, min, max int) ([]int, error) {
 			return nil, fmt.Errorf("invalid range end: %s", part[idx+1:])
 		}
 	} else {
-		// Single value
+		// Single value, or the start of a step through the rest of the range
 		var err error
 		start, err = strconv.Atoi(part)
 		if err != nil {
 			return nil, fmt.Errorf("invalid value: %s", part)
 		}
 		end = start
+		if hasStep {
+			end = max
+		}
 	}
 
 	// Validate range
@@ -177,9 +338,24 @@ func parseFieldPart(part string, min, max int) ([]int, error) {
 }
 
 // Next returns the next time that matches the cron expression after the given time.
+// It returns the zero time if there is none, such as after a one-off time.
 func (c *CronExpr) Next(from time.Time) time.Time {
-	// Start from the next minute
-	t := from.Truncate(time.Minute).Add(time.Minute)
+	if !c.at.IsZero() {
+		if c.at.After(from) {
+			return c.at
+		}
+		return time.Time{}
+	}
+
+	if c.every > 0 {
+		// Intervals count from the Unix epoch, so they line up across restarts
+		epoch := time.Unix(0, 0)
+		n := from.Sub(epoch) / c.every
+		return epoch.Add((n + 1) * c.every).In(from.Location())
+	}
+
+	// Start from the next second
+	t := from.Truncate(time.Second).Add(time.Second)
 
 	// Search for up to 4 years
 	maxTime := from.Add(4 * 365 * 24 * time.Hour)
@@ -191,15 +367,9 @@ func (c *CronExpr) Next(from time.Time) time.Time {
 			continue
 		}
 
-		// Check day of month and day of week
-		dayOfMonthMatch := contains(c.dayOfMonth, t.Day())
-		dayOfWeekMatch := contains(c.dayOfWeek, int(t.Weekday()))
-
 		// Both day constraints must be satisfied if they're both restricted
 		// (If one is *, only the other matters)
-		isDayMatch := dayOfMonthMatch && dayOfWeekMatch
-
-		if !isDayMatch {
+		if !c.matchDayOfMonth(t) || !c.matchDayOfWeek(t) {
 			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
 			continue
 		}
@@ -212,7 +382,13 @@ func (c *CronExpr) Next(from time.Time) time.Time {
 
 		// Check minute
 		if !contains(c.minute, t.Minute()) {
-			t = t.Add(time.Minute)
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location())
+			continue
+		}
+
+		// Check second
+		if !contains(c.second, t.Second()) {
+			t = t.Add(time.Second)
 			continue
 		}
 
@@ -224,6 +400,69 @@ func (c *CronExpr) Next(from time.Time) time.Time {
 	return time.Time{}
 }
 
+// matchDayOfMonth reports whether t's day matches the day-of-month field.
+func (c *CronExpr) matchDayOfMonth(t time.Time) bool {
+	day := t.Day()
+	if contains(c.dayOfMonth, day) {
+		return true
+	}
+
+	last := daysIn(t)
+	if c.lastDay && day == last {
+		return true
+	}
+	if c.lastWeekday && day == nearestWeekday(t, last) {
+		return true
+	}
+	for _, n := range c.nearestWeekday {
+		if n <= last && day == nearestWeekday(t, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDayOfWeek reports whether t's day matches the day-of-week field.
+func (c *CronExpr) matchDayOfWeek(t time.Time) bool {
+	weekday := int(t.Weekday())
+	if contains(c.dayOfWeek, weekday) {
+		return true
+	}
+
+	if contains(c.lastDayOfWeek, weekday) && t.Day()+7 > daysIn(t) {
+		return true
+	}
+	for _, nth := range c.nthDayOfWeek {
+		if nth[0] == weekday && (t.Day()-1)/7+1 == nth[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// daysIn returns the number of days in t's month.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// nearestWeekday returns the Monday-Friday day of t's month nearest to day,
+// without leaving the month.
+func nearestWeekday(t time.Time, day int) int {
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, t.Location()).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == daysIn(t) {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
 // contains checks if a slice contains a value.
 func contains(slice []int, val int) bool {
 	for _, v := range slice {
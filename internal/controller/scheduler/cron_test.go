@@ -36,10 +36,19 @@ func TestParseCron(t *testing.T) {
 		{"@weekly", "@weekly", false},
 		{"@monthly", "@monthly", false},
 		{"@yearly", "@yearly", false},
+		{"with seconds", "*/30 * * * * *", false},
+		{"names", "0 9 * JAN-MAR MON-FRI", false},
+		{"last day", "0 17 L * *", false},
+		{"nearest weekday", "0 9 15W * *", false},
+		{"nth weekday", "0 9 ? * 1#2", false},
+		{"@every", "@every 90m", false},
 		{"invalid - too few fields", "* * *", true},
-		{"invalid - too many fields", "* * * * * *", true},
+		{"invalid - too many fields", "* * * * * * *", true},
 		{"invalid - bad minute", "60 * * * *", true},
 		{"invalid - bad hour", "0 25 * * *", true},
+		{"invalid - bad second", "60 * * * * *", true},
+		{"invalid - bad nth weekday", "0 9 * * 1#6", true},
+		{"invalid - @every too short", "@every 500ms", true},
 	}
 
 	for _, tt := range tests {
@@ -92,6 +101,60 @@ func TestCronExpr_Next(t *testing.T) {
 			from:     ref,
 			expected: time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC),
 		},
+		{
+			name:     "every 30 seconds",
+			expr:     "*/30 * * * * *",
+			from:     ref.Add(10 * time.Second),
+			expected: time.Date(2025, 1, 15, 10, 30, 30, 0, time.UTC),
+		},
+		{
+			name:     "step from a start value",
+			expr:     "50/5 * * * *",
+			from:     ref,
+			expected: time.Date(2025, 1, 15, 10, 50, 0, 0, time.UTC),
+		},
+		{
+			name:     "last day of the month",
+			expr:     "0 17 L * *",
+			from:     ref,
+			expected: time.Date(2025, 1, 31, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last weekday of the month (May 31 2025 is a Saturday)",
+			expr:     "0 17 LW 5 *",
+			from:     ref,
+			expected: time.Date(2025, 5, 30, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekday nearest the 1st (Feb 1 2025 is a Saturday)",
+			expr:     "0 9 1W * *",
+			from:     ref,
+			expected: time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "second Monday",
+			expr:     "0 9 * * MON#2",
+			from:     ref,
+			expected: time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last Friday",
+			expr:     "0 9 * * 5L",
+			from:     ref,
+			expected: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Sunday as 7",
+			expr:     "0 9 * * 7",
+			from:     ref,
+			expected: time.Date(2025, 1, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "@every counts from the epoch",
+			expr:     "@every 90m",
+			from:     ref,
+			expected: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC).Add(90 * time.Minute),
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,6 +172,29 @@ func TestCronExpr_Next(t *testing.T) {
 	}
 }
 
+func TestParseAt(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	expr, err := ParseAt("2026-11-01T09:00", loc)
+	if err != nil {
+		t.Fatalf("ParseAt() error = %v", err)
+	}
+	at := time.Date(2026, 11, 1, 9, 0, 0, 0, loc)
+	if got := expr.Next(at.Add(-time.Hour)); !got.Equal(at) {
+		t.Errorf("Next() before = %v, want %v", got, at)
+	}
+	if got := expr.Next(at); !got.IsZero() {
+		t.Errorf("Next() after = %v, want zero", got)
+	}
+
+	if _, err := ParseAt("tomorrow", loc); err == nil {
+		t.Error("ParseAt(tomorrow) error = nil, want error")
+	}
+}
+
 func TestParseField(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -123,6 +209,7 @@ func TestParseField(t *testing.T) {
 		{"step", "*/2", 0, 5, []int{0, 2, 4}, false},
 		{"comma list", "1,3,5", 0, 5, []int{1, 3, 5}, false},
 		{"range with step", "0-4/2", 0, 5, []int{0, 2, 4}, false},
+		{"start with step", "1/2", 0, 5, []int{1, 3, 5}, false},
 		{"out of range", "10", 0, 5, nil, true},
 	}
 
@@ -17,26 +17,81 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/tombee/conductor/internal/controller/backend"
 	"github.com/tombee/conductor/internal/controller/runner"
 	"github.com/tombee/conductor/internal/log"
 )
 
+// Catch-up policies for runs that were due while the scheduler was not running.
+const (
+	CatchupNone = "none" // Skip missed runs (default)
+	CatchupLast = "last" // Start the most recent missed run
+	CatchupAll  = "all"  // Start every missed run, oldest first
+)
+
+// Overlap policies for a schedule that is due while its previous run is
+// still going.
+const (
+	OverlapAllow = "allow" // Start another run (default)
+	OverlapSkip  = "skip"  // Skip this run
+	OverlapQueue = "queue" // Start this run once the previous one finishes
+)
+
+// Schedule triggers, passed to runs as the _schedule_trigger input.
+const (
+	TriggerCron    = "cron"
+	TriggerCatchup = "catchup"
+	TriggerManual  = "manual"
+)
+
+// DefaultCatchupWindow is how far back missed runs are caught up by default.
+const DefaultCatchupWindow = 24 * time.Hour
+
+// maxCatchupRuns caps the runs a schedule catches up with catchup: all.
+const maxCatchupRuns = 100
+
+// maxQueuedRuns caps the runs waiting for the previous run to finish with
+// overlap: queue. Later due runs are skipped.
+const maxQueuedRuns = 1
+
+var (
+	// ErrScheduleNotFound is returned for an unknown schedule name.
+	ErrScheduleNotFound = errors.New("schedule not found")
+
+	// ErrDraining is returned by RunNow while the controller is shutting down.
+	ErrDraining = errors.New("controller is shutting down gracefully")
+)
+
+// Runner starts and looks up workflow runs. It is implemented by *runner.Runner.
+type Runner interface {
+	Submit(ctx context.Context, req runner.SubmitRequest) (*runner.RunSnapshot, error)
+	Get(id string) (*runner.RunSnapshot, error)
+	IsDraining() bool
+}
+
 // Schedule defines a scheduled workflow execution.
 type Schedule struct {
 	// Name is the unique identifier for this schedule
 	Name string `yaml:"name" json:"name"`
 
-	// Cron is the cron expression (standard 5-field format)
-	// Format: minute hour day-of-month month day-of-week
+	// Cron is the cron expression (5 fields, or 6 with seconds first) or
+	// an @every interval. See ParseCron.
+	// Format: [second] minute hour day-of-month month day-of-week
 	Cron string `yaml:"cron" json:"cron"`
 
+	// At is the time of a one-off schedule (2026-11-01T09:00), used
+	// instead of Cron
+	At string `yaml:"at,omitempty" json:"at,omitempty"`
+
 	// Workflow is the workflow file to run
 	Workflow string `yaml:"workflow" json:"workflow"`
 
@@ -49,12 +104,40 @@ type Schedule struct {
 	// Timezone for cron evaluation (defaults to UTC)
 	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
 
+	// Catchup is what happens to runs missed while the scheduler was not
+	// running: none (default), last or all
+	Catchup string `yaml:"catchup,omitempty" json:"catchup,omitempty"`
+
+	// CatchupWindow bounds how far back missed runs are caught up (default 24h)
+	CatchupWindow time.Duration `yaml:"catchup_window,omitempty" json:"catchup_window,omitempty"`
+
+	// Overlap is what happens when the schedule is due while its previous
+	// run is still going: allow (default), skip or queue
+	Overlap string `yaml:"overlap,omitempty" json:"overlap,omitempty"`
+
+	// Jitter delays each run by a random duration up to this long
+	Jitter time.Duration `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+
+	// Exclude names the calendars whose days and periods the schedule
+	// does not run in
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+
+	// TriggerID is the ID of the workflow trigger the schedule comes from,
+	// passed to runs as trigger.id. Defaults to Name
+	TriggerID string `yaml:"-" json:"trigger_id,omitempty"`
+
 	// computed fields
 	cronExpr   *CronExpr
+	location   *time.Location
+	exclusions *exclusions
 	nextRun    time.Time
 	lastRun    *time.Time
 	runCount   int64
 	errorCount int64
+	skipCount  int64
+	lastRunID  string // Most recent run started by the schedule
+	starting   int    // Runs waiting out their jitter
+	queued     int    // Runs waiting for the previous run to finish
 }
 
 // Config contains scheduler configuration.
@@ -64,14 +147,23 @@ type Config struct {
 
 	// WorkflowsDir is where to find workflow files
 	WorkflowsDir string `yaml:"workflows_dir" json:"workflows_dir"`
+
+	// Calendars are the named exclusion calendars schedules can exclude
+	Calendars map[string]Calendar `yaml:"calendars,omitempty" json:"calendars,omitempty"`
+
+	// State persists each schedule's run history, used to catch up runs
+	// missed while the scheduler was not running (optional)
+	State backend.ScheduleBackend `yaml:"-" json:"-"`
 }
 
 // Scheduler manages scheduled workflow execution.
 type Scheduler struct {
 	mu           sync.RWMutex
 	schedules    map[string]*Schedule
-	runner       *runner.Runner
+	runner       Runner
+	state        backend.ScheduleBackend
 	workflowsDir string
+	calendars    map[string]Calendar
 	stopCh       chan struct{}
 	doneCh       chan struct{}
 	running      bool
@@ -79,13 +171,15 @@ type Scheduler struct {
 }
 
 // New creates a new scheduler.
-func New(cfg Config, r *runner.Runner) (*Scheduler, error) {
+func New(cfg Config, r Runner) (*Scheduler, error) {
 	logger := slog.Default().With(slog.String("component", "scheduler"))
 
 	s := &Scheduler{
 		schedules:    make(map[string]*Schedule),
 		runner:       r,
+		state:        cfg.State,
 		workflowsDir: cfg.WorkflowsDir,
+		calendars:    cfg.Calendars,
 		stopCh:       make(chan struct{}),
 		doneCh:       make(chan struct{}),
 		logger:       logger,
@@ -106,27 +200,92 @@ func (s *Scheduler) AddSchedule(sched Schedule) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Parse cron expression
-	expr, err := ParseCron(sched.Cron)
-	if err != nil {
-		return fmt.Errorf("invalid cron expression: %w", err)
+	if err := sched.compile(s.calendars); err != nil {
+		return err
 	}
-	sched.cronExpr = expr
+	sched.nextRun = sched.next(time.Now())
+
+	s.schedules[sched.Name] = &sched
+	return nil
+}
 
-	// Calculate next run time
-	loc := time.UTC
+// compile validates the schedule and parses its expression, timezone and
+// exclusion calendars.
+func (sched *Schedule) compile(calendars map[string]Calendar) error {
+	var err error
+	sched.location = time.UTC
 	if sched.Timezone != "" {
-		loc, err = time.LoadLocation(sched.Timezone)
+		sched.location, err = time.LoadLocation(sched.Timezone)
 		if err != nil {
 			return fmt.Errorf("invalid timezone: %w", err)
 		}
 	}
-	sched.nextRun = expr.Next(time.Now().In(loc))
 
-	s.schedules[sched.Name] = &sched
+	switch {
+	case sched.At != "" && sched.Cron != "":
+		return fmt.Errorf("cron and at cannot both be set")
+	case sched.At != "":
+		sched.cronExpr, err = ParseAt(sched.At, sched.location)
+		if err != nil {
+			return fmt.Errorf("invalid at: %w", err)
+		}
+	default:
+		sched.cronExpr, err = ParseCron(sched.Cron)
+		if err != nil {
+			return fmt.Errorf("invalid cron expression: %w", err)
+		}
+	}
+
+	sched.exclusions, err = compileExclusions(sched.Exclude, calendars, sched.location)
+	if err != nil {
+		return err
+	}
+
+	switch sched.Catchup {
+	case "", CatchupNone, CatchupLast, CatchupAll:
+	default:
+		return fmt.Errorf("invalid catchup policy %q (must be none, last or all)", sched.Catchup)
+	}
+	switch sched.Overlap {
+	case "", OverlapAllow, OverlapSkip, OverlapQueue:
+	default:
+		return fmt.Errorf("invalid overlap policy %q (must be allow, skip or queue)", sched.Overlap)
+	}
+	if sched.CatchupWindow < 0 || sched.Jitter < 0 {
+		return fmt.Errorf("catchup_window and jitter must be non-negative")
+	}
 	return nil
 }
 
+// next returns when the schedule is next due after from, skipping times
+// its calendars exclude. It returns the zero time if it is never due again.
+func (sched *Schedule) next(from time.Time) time.Time {
+	t := sched.cronExpr.Next(from.In(sched.location))
+	for !t.IsZero() {
+		end := sched.exclusions.until(t)
+		if end.IsZero() {
+			return t
+		}
+		// Exclusions end before their end time, so a tick at it can run
+		t = sched.cronExpr.Next(end.Add(-time.Nanosecond))
+	}
+	return t
+}
+
+// Preview returns up to n times the schedule is due after from, skipping
+// times excluded by its calendars.
+func Preview(sched Schedule, calendars map[string]Calendar, from time.Time, n int) ([]time.Time, error) {
+	if err := sched.compile(calendars); err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	for t := sched.next(from); !t.IsZero() && len(times) < n; t = sched.next(t) {
+		times = append(times, t)
+	}
+	return times, nil
+}
+
 // RemoveSchedule removes a schedule.
 func (s *Scheduler) RemoveSchedule(name string) {
 	s.mu.Lock()
@@ -161,13 +320,14 @@ func (s *Scheduler) SetEnabled(name string, enabled bool) error {
 
 	sched, ok := s.schedules[name]
 	if !ok {
-		return fmt.Errorf("schedule not found: %s", name)
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
 	}
 	sched.Enabled = enabled
 	return nil
 }
 
-// Start starts the scheduler loop.
+// Start starts the scheduler loop. Runs missed since each schedule last ran
+// are caught up according to its catchup policy.
 func (s *Scheduler) Start(ctx context.Context) {
 	s.mu.Lock()
 	if s.running {
@@ -179,9 +339,88 @@ func (s *Scheduler) Start(ctx context.Context) {
 	s.doneCh = make(chan struct{})
 	s.mu.Unlock()
 
+	s.restore(ctx, time.Now())
 	go s.run(ctx)
 }
 
+// restore loads each schedule's history from the state store, catches up
+// missed runs and schedules the next run from now.
+func (s *Scheduler) restore(ctx context.Context, now time.Time) {
+	s.mu.RLock()
+	schedules := make([]*Schedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		schedules = append(schedules, sched)
+	}
+	s.mu.RUnlock()
+
+	runs := s.activeRuns()
+	for _, sched := range schedules {
+		var state *backend.ScheduleState
+		if s.state != nil {
+			// A schedule that never ran has no state
+			state, _ = s.state.GetScheduleState(ctx, sched.Name)
+		}
+
+		s.mu.Lock()
+		if state != nil {
+			if sched.lastRun == nil || (state.LastRun != nil && state.LastRun.After(*sched.lastRun)) {
+				sched.lastRun = state.LastRun
+			}
+			sched.runCount = max(sched.runCount, state.RunCount)
+			sched.errorCount = max(sched.errorCount, state.ErrorCount)
+		}
+		sched.nextRun = sched.next(now)
+
+		var missed []time.Time
+		if sched.Enabled && sched.lastRun != nil {
+			missed = sched.missedRuns(*sched.lastRun, now)
+		}
+		if len(missed) > 0 {
+			s.logger.Info("Catching up missed scheduled runs",
+				slog.String("schedule", sched.Name),
+				slog.String("catchup", sched.Catchup),
+				slog.Int("missed", len(missed)))
+		}
+		for _, at := range missed {
+			s.due(ctx, sched, at, TriggerCatchup, runs)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// missedRuns returns the times the schedule was due after lastRun and up
+// to now that its catchup policy starts, within its catch-up window.
+func (sched *Schedule) missedRuns(lastRun, now time.Time) []time.Time {
+	if sched.Catchup == "" || sched.Catchup == CatchupNone {
+		return nil
+	}
+
+	window := sched.CatchupWindow
+	if window == 0 {
+		window = DefaultCatchupWindow
+	}
+	// A run due exactly at the start of the window is included
+	from := now.Add(-window - time.Nanosecond)
+	if lastRun.After(from) {
+		from = lastRun
+	}
+
+	var missed []time.Time
+	for t := sched.next(from); !t.IsZero() && !t.After(now); t = sched.next(t) {
+		missed = append(missed, t)
+	}
+
+	switch {
+	case len(missed) == 0:
+		return nil
+	case sched.Catchup == CatchupLast:
+		return missed[len(missed)-1:]
+	case len(missed) > maxCatchupRuns:
+		return missed[len(missed)-maxCatchupRuns:]
+	}
+	return missed
+}
+
 // Stop stops the scheduler loop.
 func (s *Scheduler) Stop() {
 	s.mu.Lock()
@@ -217,6 +456,8 @@ func (s *Scheduler) run(ctx context.Context) {
 
 // tick checks for due schedules and triggers them.
 func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	runs := s.activeRuns()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -225,54 +466,208 @@ func (s *Scheduler) tick(ctx context.Context, now time.Time) {
 			continue
 		}
 
+		// A queued run starts once the previous run finishes
+		if sched.queued > 0 && !sched.active(runs) {
+			sched.queued--
+			s.start(ctx, sched, now, TriggerCron)
+		}
+
+		// A one-off schedule that has run is never due again
+		if sched.nextRun.IsZero() {
+			continue
+		}
+
 		if now.After(sched.nextRun) || now.Equal(sched.nextRun) {
 			// Time to run this schedule
-			go s.triggerSchedule(ctx, sched)
-
-			// Calculate next run time
-			loc := time.UTC
-			if sched.Timezone != "" {
-				if l, err := time.LoadLocation(sched.Timezone); err == nil {
-					loc = l
-				}
+			sched.nextRun = sched.next(now)
+			s.due(ctx, sched, now, TriggerCron, runs)
+		}
+	}
+}
+
+// due handles a schedule being due at the given time, applying its
+// overlap policy. runs is the result of activeRuns. Must be called with
+// s.mu held.
+func (s *Scheduler) due(ctx context.Context, sched *Schedule, at time.Time, trigger string, runs map[string]bool) {
+	if sched.Overlap == OverlapSkip || sched.Overlap == OverlapQueue {
+		if sched.active(runs) {
+			if sched.Overlap == OverlapQueue && sched.queued < maxQueuedRuns {
+				sched.queued++
+				return
 			}
-			sched.nextRun = sched.cronExpr.Next(now.In(loc))
-			sched.lastRun = &now
-			sched.runCount++
+			sched.skipCount++
+			s.logger.Info("Skipping scheduled run, previous run still active",
+				slog.String("schedule", sched.Name),
+				slog.String(log.RunIDKey, sched.lastRunID))
+			return
 		}
 	}
+	s.start(ctx, sched, at, trigger)
 }
 
-// triggerSchedule triggers a scheduled workflow.
-func (s *Scheduler) triggerSchedule(ctx context.Context, sched *Schedule) {
+// activeRuns reports, by run ID, whether the previous run of each schedule
+// with an overlap policy has yet to finish. The runner may have to ask the
+// backend, so it must be called without s.mu held.
+func (s *Scheduler) activeRuns() map[string]bool {
+	s.mu.RLock()
+	var ids []string
+	for _, sched := range s.schedules {
+		if sched.lastRunID == "" {
+			continue
+		}
+		if sched.queued > 0 || sched.Overlap == OverlapSkip || sched.Overlap == OverlapQueue {
+			ids = append(ids, sched.lastRunID)
+		}
+	}
+	s.mu.RUnlock()
+
+	runs := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		run, err := s.runner.Get(id)
+		if err != nil {
+			runs[id] = false
+			continue
+		}
+		switch run.Status {
+		case runner.RunStatusPending, runner.RunStatusRunning, runner.RunStatusPaused:
+			runs[id] = true
+		default:
+			runs[id] = false
+		}
+	}
+	return runs
+}
+
+// active reports whether the schedule's previous run has yet to finish,
+// given the result of activeRuns. Must be called with s.mu held.
+func (sched *Schedule) active(runs map[string]bool) bool {
+	if sched.starting > 0 {
+		return true
+	}
+	if sched.lastRunID == "" {
+		return false
+	}
+	active, ok := runs[sched.lastRunID]
+	// A run started since the statuses were read has not finished
+	return active || !ok
+}
+
+// start starts a run of the schedule after the schedule's jitter. The run
+// is recorded once it has been submitted. Must be called with s.mu held.
+func (s *Scheduler) start(ctx context.Context, sched *Schedule, at time.Time, trigger string) {
+	sched.starting++
+
+	var delay time.Duration
+	if sched.Jitter > 0 {
+		delay = rand.N(sched.Jitter)
+	}
+	go s.triggerSchedule(ctx, sched, at, trigger, delay)
+}
+
+// triggerSchedule triggers a scheduled workflow due at the given time
+// after delay.
+func (s *Scheduler) triggerSchedule(ctx context.Context, sched *Schedule, at time.Time, trigger string, delay time.Duration) {
+	s.mu.RLock()
+	stopCh := s.stopCh
+	s.mu.RUnlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			s.abandonStart(sched)
+			return
+		case <-stopCh:
+			s.abandonStart(sched)
+			return
+		}
+	}
+
+	run, _ := s.submit(ctx, sched, trigger)
+
+	s.mu.Lock()
+	sched.starting--
+	if run != nil {
+		// Catch-up runs started together may be submitted out of order
+		if sched.lastRun == nil || at.After(*sched.lastRun) {
+			sched.lastRun = &at
+		}
+		sched.runCount++
+		sched.lastRunID = run.ID
+	}
+	s.mu.Unlock()
+
+	s.saveState(ctx, sched)
+}
+
+// abandonStart records that a run waiting out its jitter was not started
+// because the scheduler stopped.
+func (s *Scheduler) abandonStart(sched *Schedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sched.starting--
+}
+
+// RunNow starts a run of the schedule immediately, whether or not it is
+// enabled. The run is recorded in the schedule's history but does not
+// change when it is next due.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*runner.RunSnapshot, error) {
+	s.mu.Lock()
+	sched, ok := s.schedules[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	s.mu.Unlock()
+
+	if s.runner.IsDraining() {
+		return nil, ErrDraining
+	}
+
+	run, err := s.submit(ctx, sched, TriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	sched.lastRun = &now
+	sched.runCount++
+	sched.lastRunID = run.ID
+	s.mu.Unlock()
+
+	s.saveState(ctx, sched)
+	return run, nil
+}
+
+// submit starts a run of the schedule's workflow.
+func (s *Scheduler) submit(ctx context.Context, sched *Schedule, trigger string) (*runner.RunSnapshot, error) {
 	schedLogger := s.logger.With(slog.String("schedule", sched.Name), slog.String(log.WorkflowKey, sched.Workflow))
 
 	// Check if runner is draining (graceful shutdown in progress)
 	if s.runner.IsDraining() {
 		schedLogger.Info("Skipping scheduled execution during graceful shutdown")
-		return
+		return nil, ErrDraining
 	}
 
-	schedLogger.Info("Triggering scheduled workflow")
+	schedLogger.Info("Triggering scheduled workflow", slog.String("trigger", trigger))
 
 	// Find workflow file
 	workflowPath, err := s.findWorkflow(sched.Workflow)
 	if err != nil {
 		schedLogger.Error("Workflow not found", slog.Any("error", err))
-		s.mu.Lock()
-		sched.errorCount++
-		s.mu.Unlock()
-		return
+		s.recordError(sched)
+		return nil, err
 	}
 
 	// Read workflow file
 	workflowYAML, err := os.ReadFile(workflowPath)
 	if err != nil {
 		schedLogger.Error("Failed to read workflow", slog.Any("error", err))
-		s.mu.Lock()
-		sched.errorCount++
-		s.mu.Unlock()
-		return
+		s.recordError(sched)
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
 	}
 
 	// Add schedule metadata to inputs
@@ -282,21 +677,73 @@ func (s *Scheduler) triggerSchedule(ctx context.Context, sched *Schedule) {
 	}
 	inputs["_scheduled"] = true
 	inputs["_schedule_name"] = sched.Name
+	inputs["_schedule_trigger"] = trigger
+	if _, ok := inputs["trigger"]; !ok {
+		triggerID := sched.TriggerID
+		if triggerID == "" {
+			triggerID = sched.Name
+		}
+		inputs["trigger"] = map[string]any{
+			"type":     "schedule",
+			"id":       triggerID,
+			"schedule": sched.Name,
+		}
+	}
+
+	// Manual runs were asked for by someone, so they are not batch priority
+	priority := runner.PriorityBatch
+	if trigger == TriggerManual {
+		priority = runner.PriorityInteractive
+	}
 
 	// Submit workflow
 	run, err := s.runner.Submit(ctx, runner.SubmitRequest{
 		WorkflowYAML: workflowYAML,
 		Inputs:       inputs,
+		Priority:     priority,
 	})
 	if err != nil {
 		schedLogger.Error("Failed to submit workflow", slog.Any("error", err))
-		s.mu.Lock()
-		sched.errorCount++
-		s.mu.Unlock()
-		return
+		s.recordError(sched)
+		return nil, err
 	}
 
 	schedLogger.Info("Started workflow run", slog.String(log.RunIDKey, run.ID))
+	return run, nil
+}
+
+func (s *Scheduler) recordError(sched *Schedule) {
+	s.mu.Lock()
+	sched.errorCount++
+	s.mu.Unlock()
+}
+
+// saveState persists the schedule's history to the state store.
+func (s *Scheduler) saveState(ctx context.Context, sched *Schedule) {
+	if s.state == nil {
+		return
+	}
+
+	s.mu.RLock()
+	nextRun := sched.nextRun
+	state := &backend.ScheduleState{
+		Name:       sched.Name,
+		LastRun:    sched.lastRun,
+		NextRun:    &nextRun,
+		RunCount:   sched.runCount,
+		ErrorCount: sched.errorCount,
+		Enabled:    sched.Enabled,
+	}
+	s.mu.RUnlock()
+
+	// The history is kept even if the run's request was cancelled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.state.SaveScheduleState(ctx, state); err != nil {
+		s.logger.Warn("Failed to save schedule state",
+			slog.String("schedule", sched.Name),
+			slog.Any("error", err))
+	}
 }
 
 // findWorkflow finds a workflow file.
@@ -323,12 +770,21 @@ func (s *Scheduler) findWorkflow(name string) (string, error) {
 type ScheduleStatus struct {
 	Name       string     `json:"name"`
 	Cron       string     `json:"cron"`
+	At         string     `json:"at,omitempty"`
 	Workflow   string     `json:"workflow"`
 	Enabled    bool       `json:"enabled"`
 	NextRun    time.Time  `json:"next_run"`
 	LastRun    *time.Time `json:"last_run,omitempty"`
 	RunCount   int64      `json:"run_count"`
 	ErrorCount int64      `json:"error_count"`
+	SkipCount  int64      `json:"skip_count"`
+	Queued     int        `json:"queued,omitempty"`
+	LastRunID  string     `json:"last_run_id,omitempty"`
+
+	Catchup string        `json:"catchup,omitempty"`
+	Overlap string        `json:"overlap,omitempty"`
+	Jitter  time.Duration `json:"jitter,omitempty"`
+	Exclude []string      `json:"exclude,omitempty"`
 }
 
 // GetStatus returns the status of all schedules.
@@ -341,12 +797,20 @@ func (s *Scheduler) GetStatus() []ScheduleStatus {
 		result = append(result, ScheduleStatus{
 			Name:       sched.Name,
 			Cron:       sched.Cron,
+			At:         sched.At,
 			Workflow:   sched.Workflow,
 			Enabled:    sched.Enabled,
 			NextRun:    sched.nextRun,
 			LastRun:    sched.lastRun,
 			RunCount:   sched.runCount,
 			ErrorCount: sched.errorCount,
+			SkipCount:  sched.skipCount,
+			Queued:     sched.queued,
+			LastRunID:  sched.lastRunID,
+			Catchup:    sched.Catchup,
+			Overlap:    sched.Overlap,
+			Jitter:     sched.Jitter,
+			Exclude:    sched.Exclude,
 		})
 	}
 	return result
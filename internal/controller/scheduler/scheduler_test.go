@@ -0,0 +1,384 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/internal/controller/runner"
+)
+
+type fakeRunner struct {
+	mu        sync.Mutex
+	runs      []*runner.RunSnapshot
+	reqs      []runner.SubmitRequest
+	submitErr error
+	onGet     func()
+}
+
+func (f *fakeRunner) Submit(ctx context.Context, req runner.SubmitRequest) (*runner.RunSnapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.submitErr != nil {
+		return nil, f.submitErr
+	}
+	run := &runner.RunSnapshot{ID: fmt.Sprintf("run-%d", len(f.runs)+1), Workflow: "report", Status: runner.RunStatusRunning}
+	f.runs = append(f.runs, run)
+	f.reqs = append(f.reqs, req)
+	return run, nil
+}
+
+func (f *fakeRunner) Get(id string) (*runner.RunSnapshot, error) {
+	if f.onGet != nil {
+		f.onGet()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, run := range f.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, errors.New("run not found")
+}
+
+func (f *fakeRunner) IsDraining() bool { return false }
+
+func (f *fakeRunner) submitted() []runner.SubmitRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]runner.SubmitRequest(nil), f.reqs...)
+}
+
+func (f *fakeRunner) finish() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, run := range f.runs {
+		run.Status = runner.RunStatusCompleted
+	}
+}
+
+func newTestScheduler(t *testing.T, state backend.ScheduleBackend, sched Schedule) (*Scheduler, *fakeRunner) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.yaml"), []byte("name: report\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sched.Workflow = "report"
+	sched.Enabled = true
+	r := &fakeRunner{}
+	s, err := New(Config{Schedules: []Schedule{sched}, WorkflowsDir: dir, State: state}, r)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s, r
+}
+
+// waitForRuns waits for the scheduler's goroutines to submit n runs.
+func waitForRuns(t *testing.T, r *fakeRunner, n int) []runner.SubmitRequest {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if reqs := r.submitted(); len(reqs) >= n {
+			return reqs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("submitted %d runs, want %d", len(r.submitted()), n)
+	return nil
+}
+
+// waitForStarts waits for the scheduler's goroutines to finish starting
+// runs of the named schedule.
+func waitForStarts(t *testing.T, s *Scheduler, name string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.RLock()
+		starting := s.schedules[name].starting
+		s.mu.RUnlock()
+		if starting == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("schedule %s still starting runs", name)
+}
+
+func TestSchedule_MissedRuns(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 30, 0, 0, time.UTC)
+	lastRun := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		catchup string
+		window  time.Duration
+		want    []string
+	}{
+		{"none", CatchupNone, 0, nil},
+		{"last", CatchupLast, 0, []string{"12:00"}},
+		{"all", CatchupAll, 0, []string{"10:00", "11:00", "12:00"}},
+		{"all within window", CatchupAll, 90 * time.Minute, []string{"11:00", "12:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, _ := ParseCron("0 * * * *")
+			sched := &Schedule{Catchup: tt.catchup, CatchupWindow: tt.window, cronExpr: expr, location: time.UTC}
+
+			var got []string
+			for _, at := range sched.missedRuns(lastRun, now) {
+				got = append(got, at.Format("15:04"))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("missedRuns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_CatchupFromState(t *testing.T) {
+	state := memory.New()
+	lastRun := time.Now().Add(-3 * time.Hour)
+	state.SaveScheduleState(context.Background(), &backend.ScheduleState{Name: "hourly", LastRun: &lastRun, RunCount: 7})
+
+	s, r := newTestScheduler(t, state, Schedule{Name: "hourly", Cron: "0 * * * *", Catchup: CatchupAll})
+	s.restore(context.Background(), time.Now())
+
+	reqs := waitForRuns(t, r, 3)
+	waitForStarts(t, s, "hourly")
+	if reqs[0].Inputs["_schedule_trigger"] != TriggerCatchup {
+		t.Errorf("Inputs = %v, want a catchup run", reqs[0].Inputs)
+	}
+	status := s.GetStatus()[0]
+	if status.RunCount != 10 {
+		t.Errorf("RunCount = %d, want 10", status.RunCount)
+	}
+}
+
+func TestScheduler_Overlap(t *testing.T) {
+	for _, overlap := range []string{OverlapAllow, OverlapSkip, OverlapQueue} {
+		t.Run(overlap, func(t *testing.T) {
+			s, r := newTestScheduler(t, nil, Schedule{Name: "report", Cron: "* * * * *", Overlap: overlap})
+			ctx := context.Background()
+
+			runs := s.activeRuns()
+			s.mu.Lock()
+			s.due(ctx, s.schedules["report"], time.Now(), TriggerCron, runs)
+			s.mu.Unlock()
+			waitForRuns(t, r, 1)
+
+			// Due again while the first run is still going
+			runs = s.activeRuns()
+			s.mu.Lock()
+			s.due(ctx, s.schedules["report"], time.Now(), TriggerCron, runs)
+			s.mu.Unlock()
+
+			switch overlap {
+			case OverlapAllow:
+				waitForRuns(t, r, 2)
+			case OverlapSkip:
+				if status := s.GetStatus()[0]; status.SkipCount != 1 {
+					t.Errorf("SkipCount = %d, want 1", status.SkipCount)
+				}
+			case OverlapQueue:
+				s.tick(ctx, time.Now())
+				if got := len(r.submitted()); got != 1 {
+					t.Errorf("started %d runs before the first finished, want 1", got)
+				}
+				r.finish()
+				s.tick(ctx, time.Now())
+				waitForRuns(t, r, 2)
+			}
+		})
+	}
+}
+
+func TestScheduler_OverlapQueueLimit(t *testing.T) {
+	s, r := newTestScheduler(t, nil, Schedule{Name: "report", Cron: "* * * * *", Overlap: OverlapQueue})
+	ctx := context.Background()
+
+	s.mu.Lock()
+	s.due(ctx, s.schedules["report"], time.Now(), TriggerCron, nil)
+	s.mu.Unlock()
+	waitForRuns(t, r, 1)
+
+	// Only one run waits for the first to finish; the rest are skipped
+	runs := s.activeRuns()
+	s.mu.Lock()
+	for i := 0; i < 3; i++ {
+		s.due(ctx, s.schedules["report"], time.Now(), TriggerCron, runs)
+	}
+	s.mu.Unlock()
+
+	status := s.GetStatus()[0]
+	if status.Queued != 1 || status.SkipCount != 2 {
+		t.Errorf("Queued = %d, SkipCount = %d; want 1 and 2", status.Queued, status.SkipCount)
+	}
+}
+
+func TestScheduler_JitterStop(t *testing.T) {
+	s, r := newTestScheduler(t, nil, Schedule{Name: "report", Cron: "* * * * *", Jitter: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	s.due(ctx, s.schedules["report"], time.Now(), TriggerCron, nil)
+	s.mu.Unlock()
+	cancel()
+
+	// The run waiting out its jitter is dropped, not submitted
+	waitForStarts(t, s, "report")
+	if got := len(r.submitted()); got != 0 {
+		t.Errorf("submitted %d runs after cancellation, want 0", got)
+	}
+}
+
+func TestScheduler_FailedSubmit(t *testing.T) {
+	state := memory.New()
+	lastRun := time.Now().Add(-90 * time.Minute)
+	state.SaveScheduleState(context.Background(), &backend.ScheduleState{Name: "hourly", LastRun: &lastRun, RunCount: 7})
+
+	s, r := newTestScheduler(t, state, Schedule{Name: "hourly", Cron: "0 * * * *", Catchup: CatchupAll})
+	r.submitErr = &runner.QueueFullError{Depth: 10}
+	s.restore(context.Background(), time.Now())
+	waitForStarts(t, s, "hourly")
+
+	// A rejected run is not counted, so it is caught up again next time
+	status := s.GetStatus()[0]
+	if status.RunCount != 7 || status.LastRun == nil || !status.LastRun.Equal(lastRun) {
+		t.Errorf("RunCount = %d, LastRun = %v; want 7 and %v", status.RunCount, status.LastRun, lastRun)
+	}
+}
+
+func TestScheduler_ActiveOutsideLock(t *testing.T) {
+	s, r := newTestScheduler(t, nil, Schedule{Name: "report", Cron: "* * * * *", Overlap: OverlapSkip})
+	ctx := context.Background()
+
+	s.tick(ctx, time.Now().Add(time.Minute))
+	waitForRuns(t, r, 1)
+	waitForStarts(t, s, "report")
+
+	// Looking up the previous run may go to the backend
+	var locked bool
+	r.onGet = func() {
+		if s.mu.TryLock() {
+			s.mu.Unlock()
+		} else {
+			locked = true
+		}
+	}
+	s.tick(ctx, time.Now().Add(2*time.Minute))
+	if locked {
+		t.Error("runner.Get called with the scheduler lock held")
+	}
+	if status := s.GetStatus()[0]; status.SkipCount != 1 {
+		t.Errorf("SkipCount = %d, want 1", status.SkipCount)
+	}
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	state := memory.New()
+	s, r := newTestScheduler(t, state, Schedule{Name: "report", Cron: "0 2 * * *"})
+	nextRun := s.GetStatus()[0].NextRun
+
+	run, err := s.RunNow(context.Background(), "report")
+	if err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	if reqs := r.submitted(); len(reqs) != 1 || reqs[0].Inputs["_schedule_trigger"] != TriggerManual {
+		t.Errorf("submitted %v, want one manual run", reqs)
+	}
+
+	status := s.GetStatus()[0]
+	if status.RunCount != 1 || status.LastRunID != run.ID || !status.NextRun.Equal(nextRun) {
+		t.Errorf("status = %+v", status)
+	}
+	saved, err := state.GetScheduleState(context.Background(), "report")
+	if err != nil || saved.RunCount != 1 || saved.LastRun == nil {
+		t.Errorf("saved state = %+v, %v", saved, err)
+	}
+
+	if _, err := s.RunNow(context.Background(), "missing"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("RunNow() error = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	from := time.Date(2025, 12, 23, 12, 0, 0, 0, time.UTC)
+	calendars := map[string]Calendar{
+		"holidays": {Dates: []string{"2025-12-25", "2025-12-26"}},
+		"freeze":   {Ranges: []CalendarRange{{From: "2025-12-29T00:00", To: "2025-12-31T09:00"}}},
+	}
+
+	tests := []struct {
+		name  string
+		sched Schedule
+		want  []string
+	}{
+		{"cron", Schedule{Cron: "0 9 * * *"}, []string{"12-24 09:00", "12-25 09:00", "12-26 09:00"}},
+		{"excluded dates", Schedule{Cron: "0 9 * * *", Exclude: []string{"holidays"}}, []string{"12-24 09:00", "12-27 09:00", "12-28 09:00"}},
+		{"excluded range", Schedule{Cron: "0 9 29-31 * *", Exclude: []string{"freeze"}}, []string{"12-31 09:00", "01-29 09:00", "01-30 09:00"}},
+		{"one-off", Schedule{At: "2025-12-24T18:30"}, []string{"12-24 18:30"}},
+		{"one-off in the past", Schedule{At: "2025-12-01T09:00"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times, err := Preview(tt.sched, calendars, from, 3)
+			if err != nil {
+				t.Fatalf("Preview() error = %v", err)
+			}
+			var got []string
+			for _, at := range times {
+				got = append(got, at.Format("01-02 15:04"))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Preview() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Preview(Schedule{Cron: "0 9 * * *", Exclude: []string{"missing"}}, calendars, from, 3); err == nil {
+		t.Error("Preview() with an unknown calendar: error = nil, want error")
+	}
+	if _, err := Preview(Schedule{Cron: "0 9 * * *", At: "2025-12-24T18:30"}, calendars, from, 3); err == nil {
+		t.Error("Preview() with cron and at: error = nil, want error")
+	}
+}
+
+func TestScheduler_OneOff(t *testing.T) {
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	s, r := newTestScheduler(t, nil, Schedule{Name: "once", At: at.UTC().Format(time.RFC3339)})
+	ctx := context.Background()
+
+	s.tick(ctx, at)
+	waitForRuns(t, r, 1)
+	if next := s.GetStatus()[0].NextRun; !next.IsZero() {
+		t.Errorf("NextRun = %v, want zero after the run", next)
+	}
+
+	s.tick(ctx, at.Add(time.Minute))
+	time.Sleep(20 * time.Millisecond)
+	if got := len(r.submitted()); got != 1 {
+		t.Errorf("started %d runs, want 1", got)
+	}
+}
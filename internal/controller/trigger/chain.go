@@ -0,0 +1,266 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/workflow"
+	"github.com/tombee/conductor/pkg/workflow/expression"
+)
+
+// MaxChainDepth is how many chained runs can follow each other. It stops a
+// cycle of run_completed triggers from running forever.
+const MaxChainDepth = 10
+
+// RunLookup finds runs by ID. It is implemented by *runner.Runner.
+type RunLookup interface {
+	Get(id string) (*runner.RunSnapshot, error)
+}
+
+// Chainer starts the workflows with a run_completed trigger when a run of
+// their upstream workflow finishes. Each start is delivered through the
+// event log, so it is recorded, deduplicated by upstream run and can be
+// redelivered. Chainer implements runner.RunObserver.
+type Chainer struct {
+	events    *EventLog
+	runs      RunLookup
+	evaluator *expression.Evaluator
+	logger    *slog.Logger
+
+	mu       sync.RWMutex
+	triggers map[string][]WorkflowTrigger // By upstream workflow name
+}
+
+// NewChainer creates a chainer that delivers through events and looks up
+// parent runs in runs to limit chain depth.
+func NewChainer(events *EventLog, runs RunLookup, logger *slog.Logger) *Chainer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Chainer{
+		events:    events,
+		runs:      runs,
+		evaluator: expression.New(),
+		logger:    logger,
+		triggers:  make(map[string][]WorkflowTrigger),
+	}
+}
+
+// Register replaces the registered run_completed triggers.
+func (c *Chainer) Register(triggers []WorkflowTrigger) {
+	byUpstream := make(map[string][]WorkflowTrigger)
+	for _, t := range triggers {
+		if t.RunCompleted == nil {
+			continue
+		}
+		byUpstream[t.RunCompleted.Workflow] = append(byUpstream[t.RunCompleted.Workflow], t)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.triggers = byUpstream
+}
+
+// RunFinished starts the workflows chained after run's workflow.
+func (c *Chainer) RunFinished(run *runner.RunSnapshot) {
+	c.mu.RLock()
+	triggers := c.triggers[run.Workflow]
+	c.mu.RUnlock()
+
+	if len(triggers) == 0 {
+		return
+	}
+
+	// Delivery submits runs, so it happens off the execution path
+	go func() {
+		depth := c.depth(run)
+		for _, t := range triggers {
+			c.fire(t, run, depth)
+		}
+	}()
+}
+
+// fire delivers one trigger's event for a finished upstream run, or records
+// it as ignored if the run does not match.
+func (c *Chainer) fire(t WorkflowTrigger, run *runner.RunSnapshot, depth int) {
+	ctx := context.Background()
+	rc := t.RunCompleted
+
+	// Each trigger starts the workflow once per upstream run, so triggers
+	// with their own ID are recorded apart
+	event := &backend.TriggerEvent{
+		Source:         SourceRunCompleted,
+		Trigger:        t.EventName(),
+		Workflow:       WorkflowName(c.events.workflowsDir, t.WorkflowPath),
+		Event:          string(run.Status),
+		IdempotencyKey: run.ID,
+		Payload:        runPayload(run),
+	}
+
+	if !slices.Contains(rc.Statuses(), string(run.Status)) {
+		event.Status = backend.TriggerEventIgnored
+		c.events.Record(ctx, event)
+		return
+	}
+
+	matched, err := c.evaluator.Evaluate(rc.Condition, conditionContext(run))
+	if err != nil {
+		c.logger.Warn("run_completed trigger condition failed",
+			slog.String("workflow", t.WorkflowName),
+			slog.String("upstream_run_id", run.ID),
+			slog.String("error", err.Error()))
+		event.Error = err.Error()
+	}
+	if !matched {
+		event.Status = backend.TriggerEventIgnored
+		c.events.Record(ctx, event)
+		return
+	}
+
+	if depth >= MaxChainDepth {
+		c.logger.Warn("run_completed trigger skipped: chain too deep",
+			slog.String("workflow", t.WorkflowName),
+			slog.String("upstream_run_id", run.ID),
+			slog.Int("max_depth", MaxChainDepth))
+		event.Status = backend.TriggerEventIgnored
+		event.Error = "chain of run_completed triggers is too deep"
+		c.events.Record(ctx, event)
+		return
+	}
+
+	event.Inputs = MapInputs(run.Output, rc.InputMapping, rc.Inputs)
+	event.Inputs["trigger"] = map[string]any{
+		"type":          string(workflow.TriggerTypeRunCompleted),
+		"id":            t.TriggerID(),
+		"parent_run_id": run.ID,
+	}
+	stored, duplicate, err := c.events.Deliver(ctx, event)
+	if err != nil || duplicate {
+		// Failures are recorded by the event log for redelivery
+		return
+	}
+	c.logger.Info("started chained workflow run",
+		slog.String("workflow", t.WorkflowName),
+		slog.String("upstream_workflow", run.Workflow),
+		slog.String("upstream_run_id", run.ID),
+		slog.String("run_id", stored.RunID))
+}
+
+// depth returns how many chained runs led to run, stopping at MaxChainDepth.
+func (c *Chainer) depth(run *runner.RunSnapshot) int {
+	depth := 0
+	parent := run.ParentRunID
+	for parent != "" && depth < MaxChainDepth {
+		depth++
+		if c.runs == nil {
+			break
+		}
+		prev, err := c.runs.Get(parent)
+		if err != nil {
+			break
+		}
+		parent = prev.ParentRunID
+	}
+	return depth
+}
+
+// conditionContext returns the variables a run_completed condition can use.
+func conditionContext(run *runner.RunSnapshot) map[string]any {
+	outputs := run.Output
+	if outputs == nil {
+		outputs = map[string]any{}
+	}
+	return map[string]any{
+		"outputs":  outputs,
+		"status":   string(run.Status),
+		"error":    run.Error,
+		"workflow": run.Workflow,
+		"run_id":   run.ID,
+	}
+}
+
+// runPayload is the upstream run recorded with a chained trigger event.
+func runPayload(run *runner.RunSnapshot) json.RawMessage {
+	payload, _ := json.Marshal(map[string]any{
+		"run_id":   run.ID,
+		"workflow": run.Workflow,
+		"status":   run.Status,
+		"error":    run.Error,
+		"outputs":  run.Output,
+	})
+	return payload
+}
+
+// MapInputs maps values, such as upstream outputs, to inputs. Mapping
+// values are paths into the values ($.field.subfield) or literals. Without
+// a mapping the values are passed through by name. Mapped values override
+// static inputs.
+func MapInputs(values map[string]any, mapping map[string]string, static map[string]any) map[string]any {
+	inputs := make(map[string]any, len(static)+len(values))
+	for k, v := range static {
+		inputs[k] = v
+	}
+
+	if mapping == nil {
+		for k, v := range values {
+			inputs[k] = v
+		}
+		return inputs
+	}
+
+	for name, expr := range mapping {
+		if value := pathValue(expr, values); value != nil {
+			inputs[name] = value
+		}
+	}
+	return inputs
+}
+
+// pathValue resolves a $.field.subfield path in values. Numeric parts index
+// into lists, as in $.items.0. Other expressions are literals.
+func pathValue(expr string, values map[string]any) any {
+	if !strings.HasPrefix(expr, "$") {
+		return expr
+	}
+
+	var current any = values
+	for _, part := range strings.Split(strings.TrimPrefix(expr, "$."), ".") {
+		if part == "" || part == "$" {
+			continue
+		}
+		switch v := current.(type) {
+		case map[string]any:
+			current = v[part]
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			current = v[i]
+		default:
+			return nil
+		}
+	}
+	return current
+}
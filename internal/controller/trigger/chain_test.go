@@ -0,0 +1,204 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+type fakeRunLookup map[string]*runner.RunSnapshot
+
+func (l fakeRunLookup) Get(id string) (*runner.RunSnapshot, error) {
+	if run, ok := l[id]; ok {
+		return run, nil
+	}
+	return nil, fmt.Errorf("run not found: %s", id)
+}
+
+func TestChainer_Fire(t *testing.T) {
+	events, submitter, _ := newTestEventLog(t)
+	chainer := NewChainer(events, nil, nil)
+	trig := WorkflowTrigger{
+		WorkflowPath: filepath.Join(events.workflowsDir, "deploy.yaml"),
+		WorkflowName: "deploy",
+		Type:         workflow.TriggerTypeRunCompleted,
+		RunCompleted: &workflow.RunCompletedTrigger{
+			Workflow:     "build",
+			Condition:    "outputs.approved == true",
+			InputMapping: map[string]string{"artifact": "$.artifact.url"},
+			Inputs:       map[string]any{"env": "staging", "artifact": "none"},
+		},
+	}
+	upstream := &runner.RunSnapshot{
+		ID:       "build-1",
+		Workflow: "build",
+		Status:   runner.RunStatusCompleted,
+		Output: map[string]any{
+			"approved": true,
+			"artifact": map[string]any{"url": "s3://builds/1"},
+		},
+	}
+
+	chainer.fire(trig, upstream, 0)
+	if len(submitter.runs) != 1 {
+		t.Fatalf("started %d runs, want 1", len(submitter.runs))
+	}
+	req := submitter.runs[0]
+	if req.ParentRunID != "build-1" {
+		t.Errorf("ParentRunID = %q, want build-1", req.ParentRunID)
+	}
+	if req.Inputs["artifact"] != "s3://builds/1" || req.Inputs["env"] != "staging" {
+		t.Errorf("Inputs = %v", req.Inputs)
+	}
+
+	// The same upstream run does not start a second run
+	chainer.fire(trig, upstream, 0)
+	if len(submitter.runs) != 1 {
+		t.Errorf("started %d runs after a repeat, want 1", len(submitter.runs))
+	}
+
+	tests := []struct {
+		name  string
+		run   *runner.RunSnapshot
+		depth int
+	}{
+		{"status not matched", &runner.RunSnapshot{ID: "build-2", Workflow: "build", Status: runner.RunStatusFailed}, 0},
+		{"condition false", &runner.RunSnapshot{ID: "build-3", Workflow: "build", Status: runner.RunStatusCompleted, Output: map[string]any{"approved": false}}, 0},
+		{"chain too deep", &runner.RunSnapshot{ID: "build-4", Workflow: "build", Status: runner.RunStatusCompleted, Output: map[string]any{"approved": true}}, MaxChainDepth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chainer.fire(trig, tt.run, tt.depth)
+			if len(submitter.runs) != 1 {
+				t.Errorf("started %d runs, want 1", len(submitter.runs))
+			}
+		})
+	}
+}
+
+func TestChainer_Depth(t *testing.T) {
+	runs := fakeRunLookup{
+		"a": {ID: "a"},
+		"b": {ID: "b", ParentRunID: "a"},
+		"c": {ID: "c", ParentRunID: "b"},
+	}
+	chainer := NewChainer(nil, runs, nil)
+
+	if got := chainer.depth(runs["a"]); got != 0 {
+		t.Errorf("depth(a) = %d, want 0", got)
+	}
+	if got := chainer.depth(runs["c"]); got != 2 {
+		t.Errorf("depth(c) = %d, want 2", got)
+	}
+
+	// A cycle stops at the limit
+	runs["a"].ParentRunID = "c"
+	if got := chainer.depth(runs["c"]); got != MaxChainDepth {
+		t.Errorf("depth of a cycle = %d, want %d", got, MaxChainDepth)
+	}
+}
+
+// completingAdapter finishes every run straight away.
+type completingAdapter struct{}
+
+func (completingAdapter) ExecuteWorkflow(ctx context.Context, def *workflow.Definition, inputs map[string]any, opts runner.ExecutionOptions) (*runner.ExecutionResult, error) {
+	return &runner.ExecutionResult{}, nil
+}
+
+func TestChainer_DistributedCycle(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"ping", "pong"} {
+		yaml := fmt.Sprintf("name: %s\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n", name)
+		if err := os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Runs are claimed by a worker and looked up through the backend, so
+	// chain depth relies on the parent run being persisted with the job
+	be := memory.New()
+	r := runner.New(runner.Config{MaxParallel: 2}, be, nil, runner.WithDistributed(runner.DistributedConfig{
+		WorkerID:          "worker-1",
+		Queue:             be,
+		Logs:              be,
+		StalledJobTimeout: time.Minute,
+		PollInterval:      10 * time.Millisecond,
+	}))
+	r.SetAdapter(completingAdapter{})
+
+	events := NewEventLog(EventLogConfig{Store: be, Submitter: r, WorkflowsDir: dir})
+	chainer := NewChainer(events, r, nil)
+	chainer.Register([]WorkflowTrigger{
+		{
+			WorkflowPath: filepath.Join(dir, "pong.yaml"),
+			WorkflowName: "pong",
+			Type:         workflow.TriggerTypeRunCompleted,
+			RunCompleted: &workflow.RunCompletedTrigger{Workflow: "ping"},
+		},
+		{
+			WorkflowPath: filepath.Join(dir, "ping.yaml"),
+			WorkflowName: "ping",
+			Type:         workflow.TriggerTypeRunCompleted,
+			RunCompleted: &workflow.RunCompletedTrigger{Workflow: "pong"},
+		},
+	})
+	r.SetRunObserver(chainer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.StartWorkers(ctx)
+
+	pingYAML, err := os.ReadFile(filepath.Join(dir, "ping.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Submit(ctx, runner.SubmitRequest{WorkflowYAML: pingYAML}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// The first run and MaxChainDepth chained runs complete, then the
+	// cycle stops
+	want := MaxChainDepth + 1
+	completed := func() int {
+		runs, err := be.ListRuns(ctx, backend.RunFilter{Status: string(runner.RunStatusCompleted)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(runs)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for completed() < want && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	runs, err := be.ListRuns(ctx, backend.RunFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != want {
+		t.Errorf("cycle started %d runs, want %d", len(runs), want)
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/runner"
+)
+
+// Trigger event sources.
+const (
+	SourceWebhook      = "webhook"
+	SourcePoll         = "poll"
+	SourceRunCompleted = "run_completed"
+	SourceQueue        = "queue"
+	SourceIMAP         = "imap"
+)
+
+// DefaultEventRetention is how long trigger events are kept by default.
+const DefaultEventRetention = 7 * 24 * time.Hour
+
+// redactedHeaders are stored with trigger events without their values, as
+// are headers with "Signature" in their name.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+	"X-Gitlab-Token":      true,
+	"X-Hub-Signature":     true,
+	"X-Hub-Signature-256": true,
+	"X-Slack-Signature":   true,
+	"X-Webhook-Signature": true,
+	"X-Signature":         true,
+}
+
+var (
+	// ErrDraining is returned when an event arrives while the controller
+	// is shutting down. The event is recorded as failed.
+	ErrDraining = errors.New("controller is shutting down gracefully")
+
+	// ErrWorkflowNotFound is returned when an event's workflow file does not exist.
+	ErrWorkflowNotFound = errors.New("workflow not found")
+
+	// ErrNotRedeliverable is returned when redelivering an event that was
+	// ignored or rejected.
+	ErrNotRedeliverable = errors.New("only matched events can be redelivered")
+
+	// ErrEventsNotRecorded is returned by Redeliver when the backend does
+	// not store trigger events.
+	ErrEventsNotRecorded = errors.New("backend does not record trigger events")
+)
+
+// Submitter starts workflow runs. It is implemented by *runner.Runner.
+type Submitter interface {
+	Submit(ctx context.Context, req runner.SubmitRequest) (*runner.RunSnapshot, error)
+	IsDraining() bool
+}
+
+// EventLogConfig configures an EventLog.
+type EventLogConfig struct {
+	// Store records events. If nil, events are delivered without being
+	// recorded and idempotency keys are not checked.
+	Store backend.TriggerEventStore
+
+	// Submitter starts the runs events map to.
+	Submitter Submitter
+
+	// WorkflowsDir is where workflow files are looked up by name.
+	WorkflowsDir string
+
+	// Retention is how long events are kept. Default: 7 days
+	Retention time.Duration
+
+	Logger *slog.Logger
+}
+
+// EventLog records inbound webhook, poll, queue and imap trigger events and
+// delivers them to their workflows. Events whose delivery fails are kept as
+// failed so they can be inspected and redelivered.
+type EventLog struct {
+	store        backend.TriggerEventStore
+	submitter    Submitter
+	workflowsDir string
+	retention    time.Duration
+	logger       *slog.Logger
+}
+
+// NewEventLog creates an event log.
+func NewEventLog(cfg EventLogConfig) *EventLog {
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultEventRetention
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &EventLog{
+		store:        cfg.Store,
+		submitter:    cfg.Submitter,
+		workflowsDir: cfg.WorkflowsDir,
+		retention:    cfg.Retention,
+		logger:       cfg.Logger,
+	}
+}
+
+// Record stores an event that is not delivered, such as one that did not
+// match its trigger's filters. The caller sets its status.
+func (l *EventLog) Record(ctx context.Context, event *backend.TriggerEvent) {
+	if l == nil || l.store == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	err := l.store.CreateTriggerEvent(ctx, event)
+	if errors.Is(err, backend.ErrDuplicateTriggerEvent) {
+		return
+	}
+	if err != nil {
+		l.logger.Warn("failed to record trigger event",
+			slog.String("trigger", event.Trigger),
+			slog.String("error", err.Error()))
+	}
+}
+
+// Deliver records a matched event and starts its workflow run.
+//
+// If the trigger already has an event with the same idempotency key, that
+// event is returned with duplicate set and no run is started, unless its
+// delivery failed, in which case it is delivered again.
+//
+// The returned error is the delivery failure. The event is then stored as
+// failed and can be redelivered.
+func (l *EventLog) Deliver(ctx context.Context, event *backend.TriggerEvent) (stored *backend.TriggerEvent, duplicate bool, err error) {
+	event.ID = uuid.NewString()
+	event.Matched = true
+	event.Status = backend.TriggerEventReceived
+
+	recorded := false
+	if l.store != nil {
+		err := l.store.CreateTriggerEvent(ctx, event)
+		switch {
+		case errors.Is(err, backend.ErrDuplicateTriggerEvent):
+			prev, findErr := l.store.FindTriggerEvent(ctx, event.Trigger, event.IdempotencyKey)
+			if findErr != nil {
+				return nil, false, fmt.Errorf("failed to look up duplicate event: %w", findErr)
+			}
+			if prev.Status != backend.TriggerEventFailed {
+				l.logger.Debug("duplicate trigger event",
+					slog.String("trigger", event.Trigger),
+					slog.String("idempotency_key", event.IdempotencyKey),
+					slog.String("event_id", prev.ID))
+				return prev, true, nil
+			}
+			// The sender is retrying an event we failed to deliver
+			event.ID = prev.ID
+			event.CreatedAt = prev.CreatedAt
+			event.Attempts = prev.Attempts
+			recorded = true
+		case err != nil:
+			// Losing the record is better than losing the event
+			l.logger.Warn("failed to record trigger event",
+				slog.String("trigger", event.Trigger),
+				slog.String("error", err.Error()))
+		default:
+			recorded = true
+		}
+	}
+
+	err = l.deliver(ctx, event, recorded)
+	return event, false, err
+}
+
+// Redeliver delivers a recorded event again and starts a new run.
+func (l *EventLog) Redeliver(ctx context.Context, id string) (*backend.TriggerEvent, error) {
+	if l.store == nil {
+		return nil, ErrEventsNotRecorded
+	}
+	event, err := l.store.GetTriggerEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !event.Matched {
+		return event, ErrNotRedeliverable
+	}
+
+	l.logger.Info("redelivering trigger event",
+		slog.String("event_id", event.ID),
+		slog.String("workflow", event.Workflow),
+		slog.Int("attempts", event.Attempts))
+	return event, l.deliver(ctx, event, true)
+}
+
+// deliver starts the event's run and stores the outcome.
+func (l *EventLog) deliver(ctx context.Context, event *backend.TriggerEvent, recorded bool) error {
+	event.Attempts++
+	runID, err := l.submit(ctx, event)
+	if err != nil {
+		event.Status = backend.TriggerEventFailed
+		event.Error = err.Error()
+		l.logger.Warn("trigger event delivery failed",
+			slog.String("event_id", event.ID),
+			slog.String("source", event.Source),
+			slog.String("trigger", event.Trigger),
+			slog.String("workflow", event.Workflow),
+			slog.String("error", err.Error()))
+	} else {
+		event.Status = backend.TriggerEventDelivered
+		event.Error = ""
+		event.RunID = runID
+	}
+
+	if recorded {
+		// The outcome is stored even if the request was cancelled
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if updateErr := l.store.UpdateTriggerEvent(storeCtx, event); updateErr != nil {
+			l.logger.Error("failed to update trigger event",
+				slog.String("event_id", event.ID),
+				slog.String("error", updateErr.Error()))
+		}
+	}
+	return err
+}
+
+// submit starts a run of the event's workflow with its inputs.
+func (l *EventLog) submit(ctx context.Context, event *backend.TriggerEvent) (string, error) {
+	if l.submitter.IsDraining() {
+		return "", ErrDraining
+	}
+
+	workflowPath, err := findWorkflow(l.workflowsDir, event.Workflow)
+	if err != nil {
+		return "", err
+	}
+	workflowYAML, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read workflow: %w", err)
+	}
+
+	req := runner.SubmitRequest{
+		WorkflowYAML: workflowYAML,
+		Inputs:       event.Inputs,
+		Priority:     runner.PriorityTrigger,
+	}
+	// A chained run's idempotency key is the upstream run it follows
+	if event.Source == SourceRunCompleted {
+		req.ParentRunID = event.IdempotencyKey
+	}
+
+	run, err := l.submitter.Submit(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to trigger workflow: %w", err)
+	}
+	return run.ID, nil
+}
+
+// Start prunes events older than the retention period, now and then
+// hourly, until ctx is done.
+func (l *EventLog) Start(ctx context.Context) {
+	if l.store == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			l.prune(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (l *EventLog) prune(ctx context.Context) {
+	deleted, err := l.store.DeleteTriggerEvents(ctx, time.Now().Add(-l.retention))
+	if err != nil {
+		l.logger.Warn("failed to prune trigger events", slog.String("error", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		l.logger.Debug("pruned trigger events", slog.Int64("deleted", deleted))
+	}
+}
+
+// findWorkflow finds a workflow file by name in dir or the working directory.
+func findWorkflow(dir, name string) (string, error) {
+	name = filepath.Clean(name)
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid workflow name: %s", name)
+	}
+
+	for _, baseDir := range []string{dir, "."} {
+		if baseDir == "" {
+			continue
+		}
+		for _, ext := range []string{".yaml", ".yml", ""} {
+			path := filepath.Join(baseDir, name+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrWorkflowNotFound, name)
+}
+
+// WorkflowName returns the name the event log finds a workflow file in
+// workflowsDir by.
+func WorkflowName(workflowsDir, path string) string {
+	name := path
+	if rel, err := filepath.Rel(workflowsDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		name = rel
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// RecordedHeaders returns the request headers to store with a trigger
+// event. Credentials and signatures are redacted.
+func RecordedHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for name, values := range h {
+		if len(values) == 0 {
+			continue
+		}
+		canonical := http.CanonicalHeaderKey(name)
+		if redactedHeaders[canonical] || strings.Contains(canonical, "Signature") {
+			headers[name] = "[REDACTED]"
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// RecordedMessageHeaders returns the headers of a queue message or email to
+// store with its trigger event, redacted as request headers are.
+func RecordedMessageHeaders(headers map[string]string) map[string]string {
+	h := make(http.Header, len(headers))
+	for k, v := range headers {
+		h[k] = []string{v}
+	}
+	return RecordedHeaders(h)
+}
+
+// PayloadJSON returns a message body for storage with its trigger event.
+// Bodies that are not JSON are stored as a string.
+func PayloadJSON(body []byte) json.RawMessage {
+	if json.Valid(body) {
+		return body
+	}
+	data, _ := json.Marshal(string(body))
+	return data
+}
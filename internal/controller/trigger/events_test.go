@@ -0,0 +1,208 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/backend/memory"
+	"github.com/tombee/conductor/internal/controller/runner"
+)
+
+type fakeSubmitter struct {
+	draining bool
+	runs     []runner.SubmitRequest
+}
+
+func (s *fakeSubmitter) Submit(ctx context.Context, req runner.SubmitRequest) (*runner.RunSnapshot, error) {
+	s.runs = append(s.runs, req)
+	return &runner.RunSnapshot{ID: fmt.Sprintf("run-%d", len(s.runs))}, nil
+}
+
+func (s *fakeSubmitter) IsDraining() bool {
+	return s.draining
+}
+
+func newTestEventLog(t *testing.T) (*EventLog, *fakeSubmitter, *memory.Backend) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "deploy.yaml"), []byte("name: deploy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	be := memory.New()
+	submitter := &fakeSubmitter{}
+	return NewEventLog(EventLogConfig{Store: be, Submitter: submitter, WorkflowsDir: dir}), submitter, be
+}
+
+func testTriggerEvent(key string) *backend.TriggerEvent {
+	return &backend.TriggerEvent{
+		Source:         SourceWebhook,
+		Trigger:        "/webhooks/deploy",
+		Workflow:       "deploy",
+		IdempotencyKey: key,
+		Inputs:         map[string]any{"ref": "main"},
+	}
+}
+
+func TestEventLog_DeliverDuplicate(t *testing.T) {
+	events, submitter, _ := newTestEventLog(t)
+	ctx := context.Background()
+
+	first, duplicate, err := events.Deliver(ctx, testTriggerEvent("delivery-1"))
+	if err != nil || duplicate {
+		t.Fatalf("Deliver() duplicate = %v, error = %v", duplicate, err)
+	}
+	if first.Status != backend.TriggerEventDelivered || first.RunID != "run-1" || first.Attempts != 1 {
+		t.Errorf("event = %+v", first)
+	}
+
+	second, duplicate, err := events.Deliver(ctx, testTriggerEvent("delivery-1"))
+	if err != nil || !duplicate {
+		t.Fatalf("Deliver() duplicate = %v, error = %v, want a duplicate", duplicate, err)
+	}
+	if second.ID != first.ID || second.RunID != "run-1" {
+		t.Errorf("duplicate = %+v, want the first event", second)
+	}
+
+	// Events without a key are never duplicates
+	events.Deliver(ctx, testTriggerEvent(""))
+	events.Deliver(ctx, testTriggerEvent(""))
+	if len(submitter.runs) != 3 {
+		t.Errorf("started %d runs, want 3", len(submitter.runs))
+	}
+}
+
+func TestEventLog_RetryFailedDelivery(t *testing.T) {
+	events, submitter, be := newTestEventLog(t)
+	ctx := context.Background()
+
+	submitter.draining = true
+	failed, _, err := events.Deliver(ctx, testTriggerEvent("delivery-1"))
+	if !errors.Is(err, ErrDraining) {
+		t.Fatalf("Deliver() error = %v, want ErrDraining", err)
+	}
+
+	stored, err := be.GetTriggerEvent(ctx, failed.ID)
+	if err != nil {
+		t.Fatalf("GetTriggerEvent() error = %v", err)
+	}
+	if stored.Status != backend.TriggerEventFailed || stored.Error == "" {
+		t.Errorf("stored = %+v, want a failed event", stored)
+	}
+
+	// The sender retries with the same key
+	submitter.draining = false
+	retried, duplicate, err := events.Deliver(ctx, testTriggerEvent("delivery-1"))
+	if err != nil || duplicate {
+		t.Fatalf("Deliver() duplicate = %v, error = %v", duplicate, err)
+	}
+	if retried.ID != failed.ID || retried.Attempts != 2 || retried.Status != backend.TriggerEventDelivered {
+		t.Errorf("retried = %+v, want the failed event delivered", retried)
+	}
+}
+
+func TestEventLog_Redeliver(t *testing.T) {
+	events, submitter, _ := newTestEventLog(t)
+	ctx := context.Background()
+
+	missing := testTriggerEvent("")
+	missing.Workflow = "missing"
+	failed, _, err := events.Deliver(ctx, missing)
+	if !errors.Is(err, ErrWorkflowNotFound) {
+		t.Fatalf("Deliver() error = %v, want ErrWorkflowNotFound", err)
+	}
+
+	delivered, _, _ := events.Deliver(ctx, testTriggerEvent(""))
+	redelivered, err := events.Redeliver(ctx, delivered.ID)
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if redelivered.RunID != "run-2" || redelivered.Attempts != 2 {
+		t.Errorf("redelivered = %+v, want a new run", redelivered)
+	}
+	if submitter.runs[1].Inputs["ref"] != "main" {
+		t.Errorf("inputs = %v", submitter.runs[1].Inputs)
+	}
+
+	if _, err := events.Redeliver(ctx, failed.ID); !errors.Is(err, ErrWorkflowNotFound) {
+		t.Errorf("Redeliver() error = %v, want ErrWorkflowNotFound", err)
+	}
+
+	ignored := testTriggerEvent("")
+	ignored.Status = backend.TriggerEventIgnored
+	events.Record(ctx, ignored)
+	if _, err := events.Redeliver(ctx, ignored.ID); !errors.Is(err, ErrNotRedeliverable) {
+		t.Errorf("Redeliver() error = %v, want ErrNotRedeliverable", err)
+	}
+}
+
+func TestWorkflowName(t *testing.T) {
+	dir := "workflows"
+	if got := WorkflowName(dir, filepath.Join(dir, "ops", "deploy.yaml")); got != filepath.Join("ops", "deploy") {
+		t.Errorf("WorkflowName() = %q, want ops/deploy", got)
+	}
+	if got := WorkflowName(dir, "other/deploy.yml"); got != "other/deploy" {
+		t.Errorf("WorkflowName() outside dir = %q, want other/deploy", got)
+	}
+}
+
+func TestRecordedHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	h.Set("Authorization", "Bearer secret")
+	h.Set("X-Hub-Signature-256", "sha256=abc")
+	h.Set("X-Gitlab-Token", "secret")
+	h.Set("Stripe-Signature", "t=1,v1=abc")
+	h.Set("X-Acme-Signature", "abc")
+	h.Add("Accept", "text/plain")
+	h.Add("Accept", "application/json")
+
+	got := RecordedHeaders(h)
+	want := map[string]string{
+		"Content-Type":        "application/json",
+		"Authorization":       "[REDACTED]",
+		"X-Hub-Signature-256": "[REDACTED]",
+		"X-Gitlab-Token":      "[REDACTED]",
+		"Stripe-Signature":    "[REDACTED]",
+		"X-Acme-Signature":    "[REDACTED]",
+		"Accept":              "text/plain, application/json",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+
+	message := RecordedMessageHeaders(map[string]string{"authorization": "secret", "content-type": "text/plain"})
+	if message["authorization"] != "[REDACTED]" || message["content-type"] != "text/plain" {
+		t.Errorf("RecordedMessageHeaders() = %v", message)
+	}
+}
+
+func TestPayloadJSON(t *testing.T) {
+	if got := string(PayloadJSON([]byte(`{"a":1}`))); got != `{"a":1}` {
+		t.Errorf("PayloadJSON() = %s, want the JSON body", got)
+	}
+	if got := string(PayloadJSON([]byte("plain text"))); got != `"plain text"` {
+		t.Errorf("PayloadJSON() = %s, want a JSON string", got)
+	}
+}
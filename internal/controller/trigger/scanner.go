@@ -32,9 +32,12 @@ type WorkflowTrigger struct {
 	// WorkflowName is the name of the workflow
 	WorkflowName string
 
-	// Type is the trigger type (webhook, schedule, file)
+	// Type is the trigger type (webhook, schedule, file, run_completed, queue, imap)
 	Type workflow.TriggerType
 
+	// ID identifies the trigger within its workflow
+	ID string
+
 	// Webhook configuration (for webhook triggers)
 	Webhook *workflow.WebhookTrigger
 
@@ -43,6 +46,34 @@ type WorkflowTrigger struct {
 
 	// File configuration (for file triggers)
 	File *workflow.FileTriggerConfig
+
+	// RunCompleted configuration (for run_completed triggers)
+	RunCompleted *workflow.RunCompletedTrigger
+
+	// Queue configuration (for queue triggers)
+	Queue *workflow.QueueTriggerConfig
+
+	// IMAP configuration (for imap triggers)
+	IMAP *workflow.IMAPTriggerConfig
+}
+
+// TriggerID returns the ID of the trigger, defaulting to its type.
+func (t WorkflowTrigger) TriggerID() string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return string(t.Type)
+}
+
+// EventName is the name the trigger's events are recorded under, such as
+// "queue:orders" or "queue:orders:eu" for a trigger with its own ID.
+// Events are deduplicated per name.
+func (t WorkflowTrigger) EventName() string {
+	name := string(t.Type) + ":" + t.WorkflowName
+	if id := t.TriggerID(); id != string(t.Type) {
+		name += ":" + id
+	}
+	return name
 }
 
 // ScanResult contains the results of scanning workflows for triggers.
@@ -56,6 +87,15 @@ type ScanResult struct {
 	// FileTriggers are all file watcher triggers found
 	FileTriggers []WorkflowTrigger
 
+	// RunCompletedTriggers are all run_completed triggers found
+	RunCompletedTriggers []WorkflowTrigger
+
+	// QueueTriggers are all queue triggers found
+	QueueTriggers []WorkflowTrigger
+
+	// IMAPTriggers are all imap triggers found
+	IMAPTriggers []WorkflowTrigger
+
 	// Errors are any errors encountered while scanning
 	Errors []error
 }
@@ -75,10 +115,13 @@ func NewScanner(workflowsDir string) *Scanner {
 // Scan scans all workflow files in the configured directory.
 func (s *Scanner) Scan() (*ScanResult, error) {
 	result := &ScanResult{
-		WebhookTriggers:  make([]WorkflowTrigger, 0),
-		ScheduleTriggers: make([]WorkflowTrigger, 0),
-		FileTriggers:     make([]WorkflowTrigger, 0),
-		Errors:           make([]error, 0),
+		WebhookTriggers:      make([]WorkflowTrigger, 0),
+		ScheduleTriggers:     make([]WorkflowTrigger, 0),
+		FileTriggers:         make([]WorkflowTrigger, 0),
+		RunCompletedTriggers: make([]WorkflowTrigger, 0),
+		QueueTriggers:        make([]WorkflowTrigger, 0),
+		IMAPTriggers:         make([]WorkflowTrigger, 0),
+		Errors:               make([]error, 0),
 	}
 
 	// Walk the workflows directory
@@ -115,6 +158,12 @@ func (s *Scanner) Scan() (*ScanResult, error) {
 				result.ScheduleTriggers = append(result.ScheduleTriggers, t)
 			case workflow.TriggerTypeFile:
 				result.FileTriggers = append(result.FileTriggers, t)
+			case workflow.TriggerTypeRunCompleted:
+				result.RunCompletedTriggers = append(result.RunCompletedTriggers, t)
+			case workflow.TriggerTypeQueue:
+				result.QueueTriggers = append(result.QueueTriggers, t)
+			case workflow.TriggerTypeIMAP:
+				result.IMAPTriggers = append(result.IMAPTriggers, t)
 			}
 		}
 
@@ -140,45 +189,46 @@ func (s *Scanner) scanWorkflow(path string) ([]WorkflowTrigger, error) {
 		return nil, fmt.Errorf("failed to parse workflow: %w", err)
 	}
 
-	if def.Trigger == nil {
-		return nil, nil
-	}
-
-	triggers := make([]WorkflowTrigger, 0, 2)
-
-	// Collect webhook trigger
-	if def.Trigger.Webhook != nil {
-		triggers = append(triggers, WorkflowTrigger{
+	var triggers []WorkflowTrigger
+	for _, t := range def.AllTriggers() {
+		wt := WorkflowTrigger{
 			WorkflowPath: path,
 			WorkflowName: def.Name,
-			Type:         workflow.TriggerTypeWebhook,
-			Webhook:      def.Trigger.Webhook,
-		})
-	}
-
-	// Collect schedule trigger
-	if def.Trigger.Schedule != nil {
-		triggers = append(triggers, WorkflowTrigger{
-			WorkflowPath: path,
-			WorkflowName: def.Name,
-			Type:         workflow.TriggerTypeSchedule,
-			Schedule:     def.Trigger.Schedule,
-		})
-	}
-
-	// Collect file trigger
-	if def.Trigger.File != nil {
-		// Validate file trigger configuration
-		if err := workflow.ValidateFileTrigger(def.Trigger.File); err != nil {
-			return nil, fmt.Errorf("invalid file trigger: %w", err)
+			Type:         t.Type(),
+			ID:           t.TriggerID(),
 		}
 
-		triggers = append(triggers, WorkflowTrigger{
-			WorkflowPath: path,
-			WorkflowName: def.Name,
-			Type:         workflow.TriggerTypeFile,
-			File:         def.Trigger.File,
-		})
+		switch wt.Type {
+		case workflow.TriggerTypeWebhook:
+			wt.Webhook = t.Webhook
+		case workflow.TriggerTypeSchedule:
+			wt.Schedule = t.Schedule
+		case workflow.TriggerTypeFile:
+			// Validate file trigger configuration
+			if err := workflow.ValidateFileTrigger(t.File); err != nil {
+				return nil, fmt.Errorf("invalid file trigger %s: %w", wt.ID, err)
+			}
+			wt.File = t.File
+		case workflow.TriggerTypeRunCompleted:
+			if err := t.RunCompleted.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid run_completed trigger %s: %w", wt.ID, err)
+			}
+			wt.RunCompleted = t.RunCompleted
+		case workflow.TriggerTypeQueue:
+			if err := t.Queue.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid queue trigger %s: %w", wt.ID, err)
+			}
+			wt.Queue = t.Queue
+		case workflow.TriggerTypeIMAP:
+			if err := t.IMAP.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid imap trigger %s: %w", wt.ID, err)
+			}
+			wt.IMAP = t.IMAP
+		default:
+			// API and poll triggers are served by their own handlers
+			continue
+		}
+		triggers = append(triggers, wt)
 	}
 
 	return triggers, nil
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
 func TestNewScanner(t *testing.T) {
@@ -37,6 +39,119 @@ func TestScanner_Scan_EmptyDir(t *testing.T) {
 	}
 }
 
+func TestScanner_Scan_WorkflowWithRunCompleted(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	workflowContent := `
+name: deploy
+trigger:
+  run_completed:
+    workflow: build
+    status: [completed]
+    condition: outputs.approved == true
+    input_mapping:
+      artifact: $.artifact
+
+steps:
+  - id: deploy
+    type: llm
+    prompt: "Deploy {{.inputs.artifact}}"
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "deploy.yaml"), []byte(workflowContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	result, err := NewScanner(tmpDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Errors = %v", result.Errors)
+	}
+	if len(result.RunCompletedTriggers) != 1 {
+		t.Fatalf("RunCompletedTriggers = %d, want 1", len(result.RunCompletedTriggers))
+	}
+	if rc := result.RunCompletedTriggers[0].RunCompleted; rc.Workflow != "build" || rc.InputMapping["artifact"] != "$.artifact" {
+		t.Errorf("RunCompleted = %+v", rc)
+	}
+}
+
+func TestScanner_Scan_WorkflowWithQueue(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	workflowContent := `
+name: orders
+trigger:
+  queue:
+    broker: nats
+    url: ${NATS_URL}
+    subject: orders.created
+    ack: completed
+
+steps:
+  - id: process
+    type: llm
+    prompt: "Process {{.inputs.id}}"
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "orders.yaml"), []byte(workflowContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	result, err := NewScanner(tmpDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Errors = %v", result.Errors)
+	}
+	if len(result.QueueTriggers) != 1 {
+		t.Fatalf("QueueTriggers = %d, want 1", len(result.QueueTriggers))
+	}
+	if q := result.QueueTriggers[0].Queue; q.Subject != "orders.created" || q.AckMode() != workflow.QueueAckCompleted {
+		t.Errorf("Queue = %+v", q)
+	}
+}
+
+func TestScanner_Scan_WorkflowWithIMAP(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	workflowContent := `
+name: reports
+triggers:
+  - id: vendor
+    imap:
+      host: imap.example.com
+      username: ${IMAP_USERNAME}
+      password: ${IMAP_PASSWORD}
+      folder: Vendor
+
+steps:
+  - id: process
+    type: llm
+    prompt: "Summarize {{.inputs.subject}}"
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "reports.yaml"), []byte(workflowContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	result, err := NewScanner(tmpDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Errors = %v", result.Errors)
+	}
+	if len(result.IMAPTriggers) != 1 {
+		t.Fatalf("IMAPTriggers = %d, want 1", len(result.IMAPTriggers))
+	}
+	if tr := result.IMAPTriggers[0]; tr.ID != "vendor" || tr.IMAP.FolderName() != "Vendor" {
+		t.Errorf("IMAP trigger = %+v", tr)
+	}
+}
+
 func TestScanner_Scan_WorkflowWithWebhook(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -182,6 +297,49 @@ steps:
 	}
 }
 
+func TestScanner_Scan_TriggersList(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// One workflow with several triggers, two of them schedules
+	workflowContent := `
+name: sync
+triggers:
+  - id: nightly
+    schedule:
+      cron: "0 2 * * *"
+  - id: hourly
+    schedule:
+      cron: "0 * * * *"
+  - webhook:
+      path: /webhooks/sync
+
+steps:
+  - id: run
+    type: llm
+    prompt: "Run task"
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "sync.yaml"), []byte(workflowContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	s := NewScanner(tmpDir)
+	result, err := s.Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(result.ScheduleTriggers) != 2 {
+		t.Fatalf("ScheduleTriggers = %d, want 2", len(result.ScheduleTriggers))
+	}
+	if result.ScheduleTriggers[0].ID != "nightly" || result.ScheduleTriggers[1].ID != "hourly" {
+		t.Errorf("schedule IDs = %q, %q, want nightly, hourly", result.ScheduleTriggers[0].ID, result.ScheduleTriggers[1].ID)
+	}
+	if len(result.WebhookTriggers) != 1 || result.WebhookTriggers[0].ID != "webhook" {
+		t.Errorf("WebhookTriggers = %+v, want one with ID webhook", result.WebhookTriggers)
+	}
+}
+
 func TestScanner_Scan_NoTriggers(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -417,6 +575,26 @@ func TestWorkflowTrigger_Fields(t *testing.T) {
 	}
 }
 
+func TestWorkflowTrigger_EventName(t *testing.T) {
+	tests := []struct {
+		trigger WorkflowTrigger
+		id      string
+		name    string
+	}{
+		{WorkflowTrigger{WorkflowName: "orders", Type: workflow.TriggerTypeQueue}, "queue", "queue:orders"},
+		{WorkflowTrigger{WorkflowName: "orders", Type: workflow.TriggerTypeQueue, ID: "eu"}, "eu", "queue:orders:eu"},
+		{WorkflowTrigger{WorkflowName: "deploy", Type: workflow.TriggerTypeRunCompleted}, "run_completed", "run_completed:deploy"},
+	}
+	for _, tt := range tests {
+		if got := tt.trigger.TriggerID(); got != tt.id {
+			t.Errorf("TriggerID() = %q, want %q", got, tt.id)
+		}
+		if got := tt.trigger.EventName(); got != tt.name {
+			t.Errorf("EventName() = %q, want %q", got, tt.name)
+		}
+	}
+}
+
 func TestScanResult_Fields(t *testing.T) {
 	result := &ScanResult{
 		WebhookTriggers:  make([]WorkflowTrigger, 0),
@@ -0,0 +1,157 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/controller/trigger"
+)
+
+// idempotencyHeaders carry a sender's delivery ID, in order of preference.
+// Retries of a delivery reuse its ID.
+var idempotencyHeaders = []string{
+	"Idempotency-Key",
+	"X-Idempotency-Key",
+	"X-GitHub-Delivery",
+	"X-Gitlab-Event-UUID",
+	"Linear-Delivery",
+	"X-Atlassian-Webhook-Identifier",
+	"I-Twilio-Idempotency-Token",
+}
+
+// signatureFailureLogInterval limits how often failed signature checks are
+// logged.
+const signatureFailureLogInterval = time.Minute
+
+// SignatureFailures counts requests that fail signature verification. They
+// are not recorded as trigger events, so unauthenticated senders cannot fill
+// the event log; failures are logged at most once per interval instead.
+type SignatureFailures struct {
+	logger *slog.Logger
+	total  atomic.Int64
+
+	mu         sync.Mutex
+	lastLogged time.Time
+	suppressed int
+}
+
+// NewSignatureFailures creates a counter that logs to logger.
+func NewSignatureFailures(logger *slog.Logger) *SignatureFailures {
+	return &SignatureFailures{logger: logger}
+}
+
+// Record counts a failed signature check and logs it unless one was logged
+// within the interval.
+func (f *SignatureFailures) Record(path, source string, err error) {
+	f.total.Add(1)
+
+	f.mu.Lock()
+	if time.Since(f.lastLogged) < signatureFailureLogInterval {
+		f.suppressed++
+		f.mu.Unlock()
+		return
+	}
+	suppressed := f.suppressed
+	f.lastLogged = time.Now()
+	f.suppressed = 0
+	f.mu.Unlock()
+
+	f.logger.Warn("Webhook signature verification failed",
+		slog.String("path", path),
+		slog.String("source", source),
+		slog.Any("error", err),
+		slog.Int("suppressed", suppressed),
+		slog.Int64("total", f.total.Load()),
+	)
+}
+
+// Total returns the number of failed signature checks.
+func (f *SignatureFailures) Total() int64 {
+	return f.total.Load()
+}
+
+// IdempotencyKey returns the request's delivery ID, or "" if it has none.
+func IdempotencyKey(r *http.Request) string {
+	for _, header := range idempotencyHeaders {
+		if key := strings.TrimSpace(r.Header.Get(header)); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// PayloadJSON encodes a parsed payload for storage with a trigger event.
+func PayloadJSON(payload map[string]any) json.RawMessage {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// RawPayloadJSON stores a body that could not be parsed as a JSON string.
+func RawPayloadJSON(body []byte) json.RawMessage {
+	data, _ := json.Marshal(string(body))
+	return data
+}
+
+// WriteDelivery writes the response for an event passed to
+// trigger.EventLog.Deliver.
+func WriteDelivery(w http.ResponseWriter, event *backend.TriggerEvent, duplicate bool, err error) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, trigger.ErrDraining):
+			w.Header().Set("Retry-After", "10")
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, trigger.ErrWorkflowNotFound):
+			status = http.StatusNotFound
+		}
+		response := map[string]string{"error": err.Error()}
+		if event != nil {
+			response["event_id"] = event.ID
+		}
+		writeJSON(w, status, response)
+		return
+	}
+
+	if duplicate {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status":   "duplicate",
+			"event_id": event.ID,
+			"run_id":   event.RunID,
+			"workflow": event.Workflow,
+			"event":    event.Event,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"status":   "triggered",
+		"event_id": event.ID,
+		"run_id":   event.RunID,
+		"workflow": event.Workflow,
+		"event":    event.Event,
+	})
+}
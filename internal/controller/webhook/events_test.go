@@ -0,0 +1,67 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	req := httptest.NewRequest("POST", "/webhooks/test", nil)
+	if key := IdempotencyKey(req); key != "" {
+		t.Errorf("IdempotencyKey() = %q, want none", key)
+	}
+
+	req.Header.Set("X-Gitlab-Event-UUID", "gl-1")
+	if key := IdempotencyKey(req); key != "gl-1" {
+		t.Errorf("IdempotencyKey() = %q, want gl-1", key)
+	}
+
+	req.Header.Set("Linear-Delivery", "lin-1")
+	if key := IdempotencyKey(req); key != "gl-1" {
+		t.Errorf("IdempotencyKey() = %q, want gl-1 before Linear-Delivery", key)
+	}
+
+	req.Header.Set("X-GitHub-Delivery", "gh-1")
+	if key := IdempotencyKey(req); key != "gh-1" {
+		t.Errorf("IdempotencyKey() = %q, want gh-1", key)
+	}
+
+	req.Header.Set("Idempotency-Key", "key-1")
+	if key := IdempotencyKey(req); key != "key-1" {
+		t.Errorf("IdempotencyKey() = %q, want Idempotency-Key to win", key)
+	}
+}
+
+func TestSignatureFailures(t *testing.T) {
+	var logs bytes.Buffer
+	failures := NewSignatureFailures(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	for range 3 {
+		failures.Record("/webhooks/github", "github", errors.New("invalid signature"))
+	}
+
+	if failures.Total() != 3 {
+		t.Errorf("Total() = %d, want 3", failures.Total())
+	}
+	if n := strings.Count(logs.String(), "Webhook signature verification failed"); n != 1 {
+		t.Errorf("logged %d failures, want 1 within the interval", n)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tombee/conductor/internal/controller/backend"
+	"github.com/tombee/conductor/internal/jq"
+	"github.com/tombee/conductor/pkg/workflow"
+	"github.com/tombee/conductor/pkg/workflow/expression"
+)
+
+var (
+	// filterEvaluator caches compiled filter expressions across requests
+	filterEvaluator = expression.New()
+
+	// jqExecutor runs jq filters and input mappings
+	jqExecutor = jq.NewExecutor(jq.DefaultTimeout, jq.DefaultMaxInputSize)
+)
+
+// Match reports whether a webhook request passes a trigger's filter. A nil
+// filter matches every request. Expressions see payload, headers, event and
+// source; jq filters get them as one object and must produce a boolean.
+func Match(ctx context.Context, filter *workflow.WebhookFilter, payload map[string]any, headers http.Header, event, source string) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+
+	request := map[string]any{
+		"payload": payload,
+		"headers": filterHeaders(headers),
+		"event":   event,
+		"source":  source,
+	}
+
+	if filter.JQ != "" {
+		result, err := jqExecutor.Execute(ctx, filter.JQ, request)
+		if err != nil {
+			return false, fmt.Errorf("jq filter: %w", err)
+		}
+		if result == nil {
+			return false, nil
+		}
+		matched, ok := result.(bool)
+		if !ok {
+			return false, fmt.Errorf("jq filter must produce a boolean, got %T", result)
+		}
+		return matched, nil
+	}
+
+	return filterEvaluator.Evaluate(filter.Expr, request)
+}
+
+// MapInput evaluates an input_mapping value against a webhook payload. A
+// value starting with "jq:" is a jq query, and one starting with "$" is a
+// path such as $.pull_request.title. ok is false for any other value, which
+// the caller treats as a field name or a literal.
+func MapInput(ctx context.Context, mapping string, payload map[string]any) (value any, ok bool, err error) {
+	if query, isJQ := workflow.JQMapping(mapping); isJQ {
+		value, err = jqExecutor.Execute(ctx, query, payload)
+		if err != nil {
+			return nil, true, fmt.Errorf("input mapping %q: %w", mapping, err)
+		}
+		return value, true, nil
+	}
+	if strings.HasPrefix(mapping, "$") {
+		return evaluateExpression(mapping, payload), true, nil
+	}
+	return nil, false, nil
+}
+
+// SkippedEvent marks an event its trigger's filter did not match as ignored,
+// with the filter's error if it could not be evaluated.
+func SkippedEvent(event *backend.TriggerEvent, err error) *backend.TriggerEvent {
+	event.Status = backend.TriggerEventIgnored
+	event.Error = "request did not match the trigger's filter"
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// WriteSkipped responds to a request its trigger's filter did not match.
+// The request was accepted, so senders do not retry it.
+func WriteSkipped(w http.ResponseWriter) {
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "skipped",
+		"message": "request did not match the trigger's filter",
+	})
+}
+
+// filterHeaders flattens request headers to their first values, keyed by
+// canonical name (e.g. headers["X-Github-Event"]).
+func filterHeaders(h http.Header) map[string]any {
+	headers := make(map[string]any, len(h))
+	for name, values := range h {
+		if len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	return headers
+}
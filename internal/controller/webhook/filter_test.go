@@ -0,0 +1,138 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+func reviewPayload(base string, labels ...string) map[string]any {
+	var labelList []any
+	for _, l := range labels {
+		labelList = append(labelList, map[string]any{"name": l})
+	}
+	return map[string]any{
+		"action": "opened",
+		"pull_request": map[string]any{
+			"title":  "Add filters",
+			"base":   map[string]any{"ref": base},
+			"labels": labelList,
+		},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	headers := http.Header{"X-Github-Event": []string{"pull_request"}}
+
+	tests := []struct {
+		name    string
+		filter  *workflow.WebhookFilter
+		payload map[string]any
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "no filter",
+			payload: reviewPayload("dev"),
+			want:    true,
+		},
+		{
+			name:    "expression matches",
+			filter:  &workflow.WebhookFilter{Expr: `payload.pull_request.base.ref == "main" && "ai-review" in map(payload.pull_request.labels, .name)`},
+			payload: reviewPayload("main", "bug", "ai-review"),
+			want:    true,
+		},
+		{
+			name:    "expression without label",
+			filter:  &workflow.WebhookFilter{Expr: `payload.pull_request.base.ref == "main" && "ai-review" in map(payload.pull_request.labels, .name)`},
+			payload: reviewPayload("main", "bug"),
+			want:    false,
+		},
+		{
+			name:    "expression over headers and event",
+			filter:  &workflow.WebhookFilter{Expr: `headers["X-Github-Event"] == "pull_request" && event == "pull_request" && source == "github"`},
+			payload: reviewPayload("main"),
+			want:    true,
+		},
+		{
+			name:    "jq matches",
+			filter:  &workflow.WebhookFilter{JQ: `.payload.pull_request.base.ref == "main" and (.payload.pull_request.labels | any(.name == "ai-review"))`},
+			payload: reviewPayload("main", "ai-review"),
+			want:    true,
+		},
+		{
+			name:    "jq targets another branch",
+			filter:  &workflow.WebhookFilter{JQ: `.payload.pull_request.base.ref == "main"`},
+			payload: reviewPayload("dev", "ai-review"),
+			want:    false,
+		},
+		{
+			name:    "jq must produce a boolean",
+			filter:  &workflow.WebhookFilter{JQ: `.payload.action`},
+			payload: reviewPayload("main"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Match(context.Background(), tt.filter, tt.payload, headers, "pull_request", "github")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapInput(t *testing.T) {
+	payload := reviewPayload("main", "bug", "ai-review")
+
+	tests := []struct {
+		mapping string
+		want    any
+		wantOK  bool
+	}{
+		{"jq: .pull_request.labels | map(.name)", []any{"bug", "ai-review"}, true},
+		{"jq:{title: .pull_request.title, base: .pull_request.base.ref}", map[string]any{"title": "Add filters", "base": "main"}, true},
+		{"$.pull_request.title", "Add filters", true},
+		{"$.missing", nil, true},
+		{"action", nil, false},
+		{".action", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mapping, func(t *testing.T) {
+			got, ok, err := MapInput(context.Background(), tt.mapping, payload)
+			if err != nil {
+				t.Fatalf("MapInput() error = %v", err)
+			}
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MapInput() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, _, err := MapInput(context.Background(), "jq: .action | map(.name)", payload); err == nil {
+		t.Error("MapInput() expected an error for a failing jq query")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GitLabHandler handles GitLab webhooks.
+type GitLabHandler struct{}
+
+// Verify checks the GitLab secret token. GitLab sends the webhook's secret
+// token unchanged in the X-Gitlab-Token header rather than signing the body.
+func (h *GitLabHandler) Verify(r *http.Request, body []byte, secret string) error {
+	token := r.Header.Get("X-Gitlab-Token")
+	if token == "" {
+		return fmt.Errorf("missing X-Gitlab-Token header")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return fmt.Errorf("token mismatch")
+	}
+
+	return nil
+}
+
+// ParseEvent parses the GitLab event type from the request. The
+// X-Gitlab-Event header ("Merge Request Hook") is converted to the
+// payload's object_kind form ("merge_request").
+func (h *GitLabHandler) ParseEvent(r *http.Request) string {
+	event := strings.TrimSpace(r.Header.Get("X-Gitlab-Event"))
+	event = strings.TrimSuffix(event, " Hook")
+	return strings.ReplaceAll(strings.ToLower(event), " ", "_")
+}
+
+// ExtractPayload extracts the payload from a GitLab webhook.
+func (h *GitLabHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
+
+// GitLabEvent contains common GitLab webhook event fields.
+type GitLabEvent struct {
+	ObjectKind       string         `json:"object_kind"`
+	EventType        string         `json:"event_type"`
+	User             GitLabUser     `json:"user"`
+	Project          GitLabProject  `json:"project"`
+	ObjectAttributes map[string]any `json:"object_attributes"`
+}
+
+// GitLabUser represents a GitLab user.
+type GitLabUser struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// GitLabProject represents a GitLab project.
+type GitLabProject struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+	GitHTTPURL        string `json:"git_http_url"`
+	DefaultBranch     string `json:"default_branch"`
+}
+
+// Common GitLab event types
+const (
+	GitLabEventPush         = "push"
+	GitLabEventTagPush      = "tag_push"
+	GitLabEventMergeRequest = "merge_request"
+	GitLabEventNote         = "note"
+	GitLabEventIssue        = "issue"
+	GitLabEventPipeline     = "pipeline"
+	GitLabEventJob          = "job"
+	GitLabEventRelease      = "release"
+)
+
+// Common GitLab merge request and issue actions
+const (
+	GitLabActionOpen   = "open"
+	GitLabActionUpdate = "update"
+	GitLabActionClose  = "close"
+	GitLabActionReopen = "reopen"
+	GitLabActionMerge  = "merge"
+)
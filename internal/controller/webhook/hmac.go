@@ -0,0 +1,312 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// HMAC signature encodings.
+const (
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+)
+
+// Timestamp formats for HMAC sources.
+const (
+	TimestampUnix    = "unix"
+	TimestampUnixMS  = "unix_ms"
+	TimestampRFC3339 = "rfc3339"
+)
+
+// hmacAlgorithms are the hashes an HMAC source can use.
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HMACConfig describes the signing scheme of a webhook source that signs
+// requests with an HMAC, for sources without a built-in handler.
+type HMACConfig struct {
+	// SignatureHeader is the header holding the signature
+	SignatureHeader string
+
+	// Algorithm is the HMAC hash: sha1, sha256 or sha512. Default: sha256
+	Algorithm string
+
+	// Encoding is how the signature is written: hex or base64. Default: hex
+	Encoding string
+
+	// Prefix is removed from the header value before comparing, e.g. "sha256="
+	Prefix string
+
+	// SignedContent is a template for the signed bytes. It can use .Body,
+	// .Timestamp, .Method, .URL and .Path, and {{header "Name"}}.
+	// Default: {{.Body}}
+	SignedContent string
+
+	// TimestampHeader is the header holding the signing time. When set,
+	// SignedContent must use .Timestamp, requests outside Tolerance are
+	// rejected and accepted signatures are kept in a replay cache
+	TimestampHeader string
+
+	// TimestampFormat is unix, unix_ms or rfc3339. Default: unix
+	TimestampFormat string
+
+	// Tolerance is how old the timestamp may be. Default: 5 minutes
+	Tolerance time.Duration
+
+	// EventHeader is the header holding the event type
+	EventHeader string
+
+	// EventField is a dotted payload path holding the event type, used when
+	// EventHeader is unset or empty, e.g. "type" or "event.name"
+	EventField string
+}
+
+// HMACHandler handles webhooks from a source configured with HMACConfig.
+type HMACHandler struct {
+	cfg      HMACConfig
+	newHash  func() hash.Hash
+	template *template.Template
+	replays  ReplayCache
+}
+
+// signedContent is the data available to a SignedContent template.
+type signedContent struct {
+	Body      string
+	Timestamp string
+	Method    string
+	URL       string
+	Path      string
+}
+
+// NewHMACHandler creates a handler for an HMAC-signed webhook source.
+func NewHMACHandler(cfg HMACConfig) (*HMACHandler, error) {
+	if cfg.SignatureHeader == "" {
+		return nil, fmt.Errorf("signature_header is required")
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = "sha256"
+	}
+	newHash, ok := hmacAlgorithms[cfg.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q: use sha1, sha256 or sha512", cfg.Algorithm)
+	}
+	switch cfg.Encoding {
+	case "":
+		cfg.Encoding = EncodingHex
+	case EncodingHex, EncodingBase64:
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q: use hex or base64", cfg.Encoding)
+	}
+	switch cfg.TimestampFormat {
+	case "":
+		cfg.TimestampFormat = TimestampUnix
+	case TimestampUnix, TimestampUnixMS, TimestampRFC3339:
+	default:
+		return nil, fmt.Errorf("unsupported timestamp_format %q: use unix, unix_ms or rfc3339", cfg.TimestampFormat)
+	}
+	if cfg.SignedContent == "" {
+		cfg.SignedContent = "{{.Body}}"
+	}
+	signsTimestamp := strings.Contains(cfg.SignedContent, ".Timestamp")
+	if signsTimestamp && cfg.TimestampHeader == "" {
+		return nil, fmt.Errorf("signed_content uses .Timestamp but timestamp_header is not set")
+	}
+	if !signsTimestamp && cfg.TimestampHeader != "" {
+		// An unsigned timestamp could be replaced to replay a request
+		return nil, fmt.Errorf("timestamp_header is set but signed_content does not use .Timestamp")
+	}
+
+	// The request is bound at execution; header is replaced per request
+	tmpl, err := template.New("signed_content").
+		Funcs(template.FuncMap{"header": func(string) string { return "" }}).
+		Option("missingkey=error").
+		Parse(cfg.SignedContent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signed_content: %w", err)
+	}
+
+	return &HMACHandler{cfg: cfg, newHash: newHash, template: tmpl}, nil
+}
+
+// Verify verifies the request's signature, and its timestamp when the
+// source sends one.
+func (h *HMACHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature, expires, err := h.verify(r, body, secret)
+	if err != nil || signature == "" {
+		// Without a timestamp there is no replay window
+		return err
+	}
+	return h.replays.Reserve(signature, expires)
+}
+
+// Release forgets a verified request whose event was not accepted, so that
+// it can be sent again.
+func (h *HMACHandler) Release(r *http.Request, body []byte, secret string) {
+	if signature, _, err := h.verify(r, body, secret); err == nil && signature != "" {
+		h.replays.Release(signature)
+	}
+}
+
+// verify checks the request's signature and returns it with the time it
+// stops being accepted. The signature is "" when the source sends no
+// timestamp.
+func (h *HMACHandler) verify(r *http.Request, body []byte, secret string) (string, time.Time, error) {
+	signature := r.Header.Get(h.cfg.SignatureHeader)
+	if signature == "" {
+		return "", time.Time{}, fmt.Errorf("missing %s header", h.cfg.SignatureHeader)
+	}
+	if h.cfg.Prefix != "" {
+		if !strings.HasPrefix(signature, h.cfg.Prefix) {
+			return "", time.Time{}, fmt.Errorf("invalid signature format")
+		}
+		signature = strings.TrimPrefix(signature, h.cfg.Prefix)
+	}
+
+	var timestamp string
+	var signedAt time.Time
+	if h.cfg.TimestampHeader != "" {
+		timestamp = r.Header.Get(h.cfg.TimestampHeader)
+		if timestamp == "" {
+			return "", time.Time{}, fmt.Errorf("missing %s header", h.cfg.TimestampHeader)
+		}
+		var err error
+		signedAt, err = h.parseTimestamp(timestamp)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if err := checkTimestamp(signedAt, h.cfg.Tolerance); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+
+	content, err := h.signedContent(r, body, timestamp)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	sum := computeHMAC(h.newHash, secret, content)
+	expected := hex.EncodeToString(sum)
+	if h.cfg.Encoding == EncodingBase64 {
+		expected = base64.StdEncoding.EncodeToString(sum)
+	} else {
+		signature = strings.ToLower(signature)
+	}
+	if !matchSignature([]string{signature}, expected) {
+		return "", time.Time{}, fmt.Errorf("signature mismatch")
+	}
+
+	if h.cfg.TimestampHeader == "" {
+		return "", time.Time{}, nil
+	}
+	return expected, signedAt.Add(tolerance(h.cfg.Tolerance)), nil
+}
+
+// ParseEvent parses the event type from the configured event header.
+func (h *HMACHandler) ParseEvent(r *http.Request) string {
+	if h.cfg.EventHeader == "" {
+		return ""
+	}
+	return r.Header.Get(h.cfg.EventHeader)
+}
+
+// PayloadEvent returns the event type from the configured payload field.
+func (h *HMACHandler) PayloadEvent(payload map[string]any) string {
+	if h.cfg.EventField == "" {
+		return ""
+	}
+	var current any = payload
+	for _, part := range strings.Split(h.cfg.EventField, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return ""
+		}
+		current = m[part]
+	}
+	event, _ := current.(string)
+	return event
+}
+
+// ExtractPayload extracts the JSON payload from the webhook.
+func (h *HMACHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
+
+// signedContent renders the bytes the sender signed.
+func (h *HMACHandler) signedContent(r *http.Request, body []byte, timestamp string) ([]byte, error) {
+	tmpl, err := h.template.Clone()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Funcs(template.FuncMap{"header": r.Header.Get})
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, signedContent{
+		Body:      string(body),
+		Timestamp: timestamp,
+		Method:    r.Method,
+		URL:       requestURL(r),
+		Path:      r.URL.Path,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render signed content: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// parseTimestamp parses the signing time in the configured format.
+func (h *HMACHandler) parseTimestamp(value string) (time.Time, error) {
+	switch h.cfg.TimestampFormat {
+	case TimestampRFC3339:
+		ts, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp")
+		}
+		return ts, nil
+	case TimestampUnixMS:
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp")
+		}
+		return time.UnixMilli(ms), nil
+	default:
+		sec, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp")
+		}
+		whole, frac := math.Modf(sec)
+		return time.Unix(int64(whole), int64(frac*1e9)), nil
+	}
+}
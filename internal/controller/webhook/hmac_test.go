@@ -0,0 +1,121 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACHandler_Verify(t *testing.T) {
+	body := `{"data": {"kind": "order.created"}}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	h, err := NewHMACHandler(HMACConfig{
+		SignatureHeader: "X-Acme-Signature",
+		Prefix:          "sha256=",
+		SignedContent:   `{{.Timestamp}}:{{header "X-Acme-Delivery"}}:{{.Body}}`,
+		TimestampHeader: "X-Acme-Timestamp",
+		EventField:      "data.kind",
+	})
+	if err != nil {
+		t.Fatalf("NewHMACHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		wantErr   string
+	}{
+		{"valid", now, "sha256=" + hexSign(now+":d-1:"+body), ""},
+		{"replay with uppercase hex", now, "sha256=" + strings.ToUpper(hexSign(now+":d-1:"+body)), "replayed"},
+		{"without delivery in signed content", now, "sha256=" + hexSign(now+"::"+body), "signature mismatch"},
+		{"without prefix", now, hexSign(now + ":d-1:" + body), "invalid signature format"},
+		{"too old", old, "sha256=" + hexSign(old+":d-1:"+body), "too old"},
+		{"no timestamp", "", "sha256=" + hexSign(":d-1:"+body), "missing X-Acme-Timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(body, map[string]string{
+				"X-Acme-Signature": tt.signature,
+				"X-Acme-Timestamp": tt.timestamp,
+				"X-Acme-Delivery":  "d-1",
+			})
+			checkVerifyError(t, h.Verify(req, []byte(body), testSecret), tt.wantErr)
+		})
+	}
+
+	payload, _ := h.ExtractPayload([]byte(body))
+	if got := EventType(h, h.ParseEvent(newRequest(body, nil)), payload); got != "order.created" {
+		t.Errorf("EventType() = %q, want order.created", got)
+	}
+}
+
+func TestHMACHandler_Base64(t *testing.T) {
+	body := `{"ok": true}`
+	h, err := NewHMACHandler(HMACConfig{
+		SignatureHeader: "X-Signature-512",
+		Algorithm:       "sha512",
+		Encoding:        EncodingBase64,
+		SignedContent:   "{{.Method}} {{.URL}}\n{{.Body}}",
+		EventHeader:     "X-Event-Name",
+	})
+	if err != nil {
+		t.Fatalf("NewHMACHandler() error = %v", err)
+	}
+
+	signed := "POST https://hooks.example.com/webhooks/test\n" + body
+	signature := base64.StdEncoding.EncodeToString(sign(sha512.New, signed))
+	req := newRequest(body, map[string]string{"X-Signature-512": signature, "X-Event-Name": "ping"})
+	checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "")
+	// Without a timestamp there is no replay window, so retries verify
+	checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "")
+
+	if got := h.ParseEvent(req); got != "ping" {
+		t.Errorf("ParseEvent() = %q, want ping", got)
+	}
+}
+
+func TestNewHMACHandler_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HMACConfig
+		wantErr string
+	}{
+		{"no header", HMACConfig{}, "signature_header is required"},
+		{"algorithm", HMACConfig{SignatureHeader: "X-Sig", Algorithm: "md5"}, "unsupported algorithm"},
+		{"encoding", HMACConfig{SignatureHeader: "X-Sig", Encoding: "base32"}, "unsupported encoding"},
+		{"timestamp format", HMACConfig{SignatureHeader: "X-Sig", TimestampFormat: "iso"}, "unsupported timestamp_format"},
+		{"template", HMACConfig{SignatureHeader: "X-Sig", SignedContent: "{{.Body"}, "invalid signed_content"},
+		{"timestamp without header", HMACConfig{SignatureHeader: "X-Sig", SignedContent: "{{.Timestamp}}.{{.Body}}"}, "timestamp_header is not set"},
+		{"unsigned timestamp", HMACConfig{SignatureHeader: "X-Sig", TimestampHeader: "X-Timestamp"}, "does not use .Timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHMACHandler(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewHMACHandler() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
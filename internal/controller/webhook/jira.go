@@ -0,0 +1,76 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// JiraHandler handles Jira and other Atlassian webhooks.
+//
+// Atlassian does not sign a timestamp, and retries resend the same
+// signature, so replays are caught by the X-Atlassian-Webhook-Identifier
+// idempotency key rather than a time window.
+type JiraHandler struct{}
+
+// Verify verifies the X-Hub-Signature header that Atlassian sends for
+// webhooks with a secret: sha256=<hex HMAC-SHA256 of the body>.
+func (h *JiraHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature := r.Header.Get("X-Hub-Signature")
+	if signature == "" {
+		return fmt.Errorf("missing X-Hub-Signature header")
+	}
+
+	method, sig, ok := strings.Cut(signature, "=")
+	if !ok {
+		return fmt.Errorf("invalid signature format")
+	}
+	if method != "sha256" {
+		return fmt.Errorf("unsupported algorithm: %s", method)
+	}
+
+	expected := hex.EncodeToString(computeHMAC(sha256.New, secret, body))
+	if !matchSignature([]string{sig}, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// ParseEvent returns "" because Jira sends the event type in the payload.
+// See PayloadEvent.
+func (h *JiraHandler) ParseEvent(r *http.Request) string {
+	return ""
+}
+
+// PayloadEvent returns the Jira event type, such as "jira:issue_created".
+func (h *JiraHandler) PayloadEvent(payload map[string]any) string {
+	event, _ := payload["webhookEvent"].(string)
+	return event
+}
+
+// ExtractPayload extracts the payload from a Jira webhook.
+func (h *JiraHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
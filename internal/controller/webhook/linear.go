@@ -0,0 +1,100 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// linearTolerance is Linear's recommended window for webhookTimestamp.
+const linearTolerance = time.Minute
+
+// LinearHandler handles Linear webhooks.
+type LinearHandler struct {
+	// Tolerance is how old the payload's webhookTimestamp may be.
+	// Default: 1 minute
+	Tolerance time.Duration
+
+	replays ReplayCache
+}
+
+// Verify verifies the Linear-Signature header, the hex HMAC-SHA256 of the
+// body, and checks the signed webhookTimestamp field of the payload.
+func (h *LinearHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature, expires, err := h.verify(r, body, secret)
+	if err != nil {
+		return err
+	}
+	return h.replays.Reserve(signature, expires)
+}
+
+// Release forgets a verified request whose event was not accepted, so that
+// it can be sent again.
+func (h *LinearHandler) Release(r *http.Request, body []byte, secret string) {
+	if signature, _, err := h.verify(r, body, secret); err == nil {
+		h.replays.Release(signature)
+	}
+}
+
+// verify checks the request's signature and returns it with the time it
+// stops being accepted.
+func (h *LinearHandler) verify(r *http.Request, body []byte, secret string) (string, time.Time, error) {
+	signature := r.Header.Get("Linear-Signature")
+	if signature == "" {
+		return "", time.Time{}, fmt.Errorf("missing Linear-Signature header")
+	}
+
+	expected := hex.EncodeToString(computeHMAC(sha256.New, secret, body))
+	if !matchSignature([]string{signature}, expected) {
+		return "", time.Time{}, fmt.Errorf("signature mismatch")
+	}
+
+	var fields struct {
+		WebhookTimestamp int64 `json:"webhookTimestamp"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil || fields.WebhookTimestamp == 0 {
+		return "", time.Time{}, fmt.Errorf("missing webhookTimestamp")
+	}
+	window := h.Tolerance
+	if window <= 0 {
+		window = linearTolerance
+	}
+	signedAt := time.UnixMilli(fields.WebhookTimestamp)
+	if err := checkTimestamp(signedAt, window); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return expected, signedAt.Add(window), nil
+}
+
+// ParseEvent parses the Linear event type, such as "Issue" or "Comment",
+// from the request.
+func (h *LinearHandler) ParseEvent(r *http.Request) string {
+	return r.Header.Get("Linear-Event")
+}
+
+// ExtractPayload extracts the payload from a Linear webhook.
+func (h *LinearHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PagerDutyHandler handles PagerDuty v3 webhooks.
+//
+// PagerDuty does not sign a timestamp, and retries resend the same
+// signature, so replays are caught by the event ID idempotency key rather
+// than a time window.
+type PagerDutyHandler struct{}
+
+// Verify verifies the X-PagerDuty-Signature header, which holds one or
+// more hex HMAC-SHA256 signatures of the body: v1=<hex>,v1=<hex>. Several
+// signatures are sent while a secret is rotated.
+func (h *PagerDutyHandler) Verify(r *http.Request, body []byte, secret string) error {
+	header := r.Header.Get("X-PagerDuty-Signature")
+	if header == "" {
+		return fmt.Errorf("missing X-PagerDuty-Signature header")
+	}
+
+	signatures := signatureValues(header, "v1")
+	if len(signatures) == 0 {
+		return fmt.Errorf("invalid signature format")
+	}
+
+	expected := hex.EncodeToString(computeHMAC(sha256.New, secret, body))
+	if !matchSignature(signatures, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// ParseEvent returns "" because PagerDuty sends the event type in the
+// payload. See PayloadEvent.
+func (h *PagerDutyHandler) ParseEvent(r *http.Request) string {
+	return ""
+}
+
+// PayloadEvent returns the PagerDuty event type, such as
+// "incident.triggered".
+func (h *PagerDutyHandler) PayloadEvent(payload map[string]any) string {
+	event, _ := payload["event"].(map[string]any)
+	eventType, _ := event["event_type"].(string)
+	return eventType
+}
+
+// ExtractPayload extracts the payload from a PagerDuty webhook.
+func (h *PagerDutyHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+func sign(newHash func() hash.Hash, data string) []byte {
+	mac := hmac.New(newHash, []byte(testSecret))
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSign(data string) string {
+	return hex.EncodeToString(sign(sha256.New, data))
+}
+
+func newRequest(body string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest("POST", "https://hooks.example.com/webhooks/test", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestStripeHandler_Verify(t *testing.T) {
+	body := `{"id": "evt_1", "type": "payment_intent.succeeded"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr string
+	}{
+		{"valid", "t=" + now + ",v1=" + hexSign(now+"."+body), ""},
+		{"rolled secret", "t=" + now + ",v1=" + hexSign("other") + ",v1=" + hexSign(now+"."+body), ""},
+		{"wrong signature", "t=" + now + ",v1=" + hexSign(body), "signature mismatch"},
+		{"too old", "t=" + old + ",v1=" + hexSign(old+"."+body), "too old"},
+		{"no timestamp", "v1=" + hexSign(body), "invalid signature format"},
+		{"missing", "", "missing Stripe-Signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &StripeHandler{}
+			req := newRequest(body, map[string]string{"Stripe-Signature": tt.header})
+			err := h.Verify(req, []byte(body), testSecret)
+			checkVerifyError(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		h := &StripeHandler{}
+		header := "t=" + now + ",v1=" + hexSign(now+"."+body)
+		if err := h.Verify(newRequest(body, map[string]string{"Stripe-Signature": header}), []byte(body), testSecret); err != nil {
+			t.Fatalf("first Verify() error = %v", err)
+		}
+		err := h.Verify(newRequest(body, map[string]string{"Stripe-Signature": header}), []byte(body), testSecret)
+		checkVerifyError(t, err, "replayed")
+	})
+
+	payload, _ := (&StripeHandler{}).ExtractPayload([]byte(body))
+	if got := EventType(&StripeHandler{}, "", payload); got != "payment_intent.succeeded" {
+		t.Errorf("EventType() = %q, want payment_intent.succeeded", got)
+	}
+}
+
+func TestLinearHandler_Verify(t *testing.T) {
+	fresh := fmt.Sprintf(`{"action": "create", "type": "Issue", "webhookTimestamp": %d}`, time.Now().UnixMilli())
+	stale := fmt.Sprintf(`{"action": "create", "type": "Issue", "webhookTimestamp": %d}`, time.Now().Add(-2*time.Minute).UnixMilli())
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		wantErr   string
+	}{
+		{"valid", fresh, hexSign(fresh), ""},
+		{"wrong signature", fresh, hexSign(stale), "signature mismatch"},
+		{"too old", stale, hexSign(stale), "too old"},
+		{"no timestamp", `{"type": "Issue"}`, hexSign(`{"type": "Issue"}`), "missing webhookTimestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &LinearHandler{}
+			req := newRequest(tt.body, map[string]string{"Linear-Signature": tt.signature, "Linear-Event": "Issue"})
+			checkVerifyError(t, h.Verify(req, []byte(tt.body), testSecret), tt.wantErr)
+			if got := h.ParseEvent(req); got != "Issue" {
+				t.Errorf("ParseEvent() = %q, want Issue", got)
+			}
+		})
+	}
+}
+
+func TestPagerDutyHandler_Verify(t *testing.T) {
+	body := `{"event": {"id": "01ABC", "event_type": "incident.triggered"}}`
+	h := &PagerDutyHandler{}
+
+	req := newRequest(body, map[string]string{"X-PagerDuty-Signature": "v1=" + hexSign("old") + ",v1=" + hexSign(body)})
+	checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "")
+
+	req = newRequest(body, map[string]string{"X-PagerDuty-Signature": "v1=" + hexSign("old")})
+	checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "signature mismatch")
+
+	payload, _ := h.ExtractPayload([]byte(body))
+	if got := EventType(h, h.ParseEvent(req), payload); got != "incident.triggered" {
+		t.Errorf("EventType() = %q, want incident.triggered", got)
+	}
+}
+
+func TestJiraHandler_Verify(t *testing.T) {
+	body := `{"webhookEvent": "jira:issue_created", "issue": {"key": "OPS-1"}}`
+	h := &JiraHandler{}
+
+	req := newRequest(body, map[string]string{"X-Hub-Signature": "sha256=" + hexSign(body)})
+	checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "")
+
+	req = newRequest(body, map[string]string{"X-Hub-Signature": "sha1=" + hexSign(body)})
+	checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "unsupported algorithm")
+
+	payload, _ := h.ExtractPayload([]byte(body))
+	if got := EventType(h, h.ParseEvent(req), payload); got != "jira:issue_created" {
+		t.Errorf("EventType() = %q, want jira:issue_created", got)
+	}
+}
+
+func TestSentryHandler_Verify(t *testing.T) {
+	body := `{"action": "created", "data": {"issue": {"id": "1"}}}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		wantErr   string
+	}{
+		{"valid", now, hexSign(body), ""},
+		{"wrong signature", now, hexSign("other"), "signature mismatch"},
+		{"too old", old, hexSign(body), "too old"},
+		{"no timestamp", "", hexSign(body), "missing required headers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &SentryHandler{}
+			req := newRequest(body, map[string]string{
+				"Sentry-Hook-Signature": tt.signature,
+				"Sentry-Hook-Timestamp": tt.timestamp,
+				"Sentry-Hook-Resource":  "issue",
+			})
+			checkVerifyError(t, h.Verify(req, []byte(body), testSecret), tt.wantErr)
+			if got := h.ParseEvent(req); got != "issue" {
+				t.Errorf("ParseEvent() = %q, want issue", got)
+			}
+		})
+	}
+
+	t.Run("replay with a fresh timestamp", func(t *testing.T) {
+		// The timestamp is not signed, so the body is what is remembered
+		h := &SentryHandler{}
+		headers := map[string]string{"Sentry-Hook-Signature": hexSign(body), "Sentry-Hook-Timestamp": now}
+		checkVerifyError(t, h.Verify(newRequest(body, headers), []byte(body), testSecret), "")
+		headers["Sentry-Hook-Timestamp"] = strconv.FormatInt(time.Now().Unix()+1, 10)
+		checkVerifyError(t, h.Verify(newRequest(body, headers), []byte(body), testSecret), "replayed")
+	})
+}
+
+func TestTwilioHandler_Verify(t *testing.T) {
+	h := &TwilioHandler{}
+
+	t.Run("form parameters", func(t *testing.T) {
+		body := "To=%2B15550100&From=%2B15550199&MessageSid=SM1&Body=hello"
+		// Parameters are signed sorted by name
+		signed := "https://hooks.example.com/webhooks/test" + "BodyhelloFrom+15550199MessageSidSM1To+15550100"
+		signature := base64.StdEncoding.EncodeToString(sign(sha1.New, signed))
+
+		req := newRequest(body, map[string]string{
+			"X-Twilio-Signature": signature,
+			"Content-Type":       "application/x-www-form-urlencoded",
+		})
+		checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "")
+
+		tampered := strings.Replace(body, "hello", "goodbye", 1)
+		checkVerifyError(t, h.Verify(req, []byte(tampered), testSecret), "signature mismatch")
+
+		payload, err := h.ExtractPayload([]byte(body))
+		if err != nil {
+			t.Fatalf("ExtractPayload() error = %v", err)
+		}
+		if payload["Body"] != "hello" || EventType(h, "", payload) != "message" {
+			t.Errorf("payload = %v", payload)
+		}
+	})
+
+	t.Run("json body behind proxy", func(t *testing.T) {
+		body := `{"CallSid": "CA1"}`
+		sum := sha256.Sum256([]byte(body))
+		url := "https://public.example.com/webhooks/test?bodySHA256=" + hex.EncodeToString(sum[:])
+		signature := base64.StdEncoding.EncodeToString(sign(sha1.New, url))
+
+		req := httptest.NewRequest("POST", "http://10.0.0.5/webhooks/test?bodySHA256="+hex.EncodeToString(sum[:]), strings.NewReader(body))
+		req.Header.Set("X-Twilio-Signature", signature)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "public.example.com")
+		checkVerifyError(t, h.Verify(req, []byte(body), testSecret), "")
+		checkVerifyError(t, h.Verify(req, []byte(`{"CallSid": "CA2"}`), testSecret), "body hash mismatch")
+	})
+}
+
+func TestSlackHandler_Replay(t *testing.T) {
+	body := `{"type": "event_callback"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		"X-Slack-Request-Timestamp": now,
+		"X-Slack-Signature":         "v0=" + hexSign("v0:"+now+":"+body),
+	}
+
+	h := &SlackHandler{}
+	checkVerifyError(t, h.Verify(newRequest(body, headers), []byte(body), testSecret), "")
+	checkVerifyError(t, h.Verify(newRequest(body, headers), []byte(body), testSecret), "replayed")
+
+	// A request whose event was not accepted can be retried
+	Release(h, newRequest(body, headers), []byte(body), testSecret)
+	checkVerifyError(t, h.Verify(newRequest(body, headers), []byte(body), testSecret), "")
+}
+
+func TestReplayCache(t *testing.T) {
+	var cache ReplayCache
+
+	// An expired entry no longer counts as seen
+	if err := cache.Reserve("sig", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := cache.Reserve("sig", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Reserve() after expiry error = %v", err)
+	}
+	if err := cache.Reserve("sig", time.Now().Add(time.Minute)); err == nil {
+		t.Error("Reserve() expected a replay error")
+	}
+
+	cache.Release("sig")
+	if err := cache.Reserve("sig", time.Now().Add(time.Minute)); err != nil {
+		t.Errorf("Reserve() after release error = %v", err)
+	}
+
+	// Of concurrent copies of a request, only one is accepted
+	var wg sync.WaitGroup
+	var accepted atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if cache.Reserve("concurrent", time.Now().Add(time.Minute)) == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := accepted.Load(); got != 1 {
+		t.Errorf("%d concurrent reservations accepted, want 1", got)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	if _, ok := reg.Handler("stripe").(*StripeHandler); !ok {
+		t.Errorf("Handler(stripe) = %T", reg.Handler("stripe"))
+	}
+	if reg.Handler("atlassian") != reg.Handler("jira") {
+		t.Error("atlassian should share the jira handler")
+	}
+	if _, ok := reg.Handler("unknown").(*GenericHandler); !ok {
+		t.Errorf("Handler(unknown) = %T, want generic", reg.Handler("unknown"))
+	}
+
+	custom, err := NewHMACHandler(HMACConfig{SignatureHeader: "X-Acme-Signature"})
+	if err != nil {
+		t.Fatalf("NewHMACHandler() error = %v", err)
+	}
+	reg.Register("acme", custom)
+	if reg.Handler("acme") != custom {
+		t.Error("Handler(acme) did not return the registered handler")
+	}
+}
+
+func checkVerifyError(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Verify() error = %v, want %q", err, want)
+	}
+}
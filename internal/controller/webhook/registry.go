@@ -0,0 +1,119 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// PayloadEventParser is implemented by handlers whose source sends the
+// event type in the payload rather than a header, such as Stripe's "type".
+type PayloadEventParser interface {
+	// PayloadEvent returns the event type from the payload, or "".
+	PayloadEvent(payload map[string]any) string
+}
+
+// ReplayRecorder is implemented by handlers that reject replayed requests.
+// Their Verify reserves the request, rejecting one that was verified before;
+// Release drops the reservation when the request's event is not accepted,
+// so a request whose delivery failed can be sent again.
+type ReplayRecorder interface {
+	// Release forgets a request that passed Verify.
+	Release(r *http.Request, body []byte, secret string)
+}
+
+// Registry maps webhook source names to their handlers. Sources without a
+// handler use the generic one.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRegistry creates a registry with the built-in sources.
+func NewRegistry() *Registry {
+	jira := &JiraHandler{}
+	return &Registry{
+		handlers: map[string]Handler{
+			"github":    &GitHubHandler{},
+			"gitlab":    &GitLabHandler{},
+			"slack":     &SlackHandler{},
+			"stripe":    &StripeHandler{},
+			"linear":    &LinearHandler{},
+			"pagerduty": &PagerDutyHandler{},
+			"jira":      jira,
+			"atlassian": jira,
+			"sentry":    &SentryHandler{},
+			"twilio":    &TwilioHandler{},
+			"generic":   &GenericHandler{},
+		},
+	}
+}
+
+// Register adds or replaces the handler for a source.
+func (reg *Registry) Register(source string, handler Handler) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.handlers[source] = handler
+}
+
+// Handler returns the handler for a source, or the generic handler if the
+// source has none.
+func (reg *Registry) Handler(source string) Handler {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	if handler, ok := reg.handlers[source]; ok {
+		return handler
+	}
+	return reg.handlers["generic"]
+}
+
+// Sources returns the names of the registered sources, sorted.
+func (reg *Registry) Sources() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	sources := make([]string, 0, len(reg.handlers))
+	for source := range reg.handlers {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// EventType returns the event type of a request: the one parsed from its
+// headers or, when that is empty and the handler implements
+// PayloadEventParser, the one in its payload.
+func EventType(handler Handler, event string, payload map[string]any) string {
+	if event != "" {
+		return event
+	}
+	if parser, ok := handler.(PayloadEventParser); ok {
+		return parser.PayloadEvent(payload)
+	}
+	return event
+}
+
+// Release forgets a request verified with secret, when the handler
+// implements ReplayRecorder. Call it when the request's event could not be
+// recorded as delivered, ignored or skipped.
+func Release(handler Handler, r *http.Request, body []byte, secret string) {
+	if secret == "" {
+		return
+	}
+	if recorder, ok := handler.(ReplayRecorder); ok {
+		recorder.Release(r, body, secret)
+	}
+}
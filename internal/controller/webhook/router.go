@@ -16,16 +16,18 @@
 package webhook
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 
+	"github.com/tombee/conductor/internal/controller/backend"
 	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/internal/controller/trigger"
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
 // Route defines a webhook route mapping.
@@ -33,7 +35,8 @@ type Route struct {
 	// Path is the URL path to match (e.g., "/webhooks/github")
 	Path string `yaml:"path" json:"path"`
 
-	// Source is the webhook source type (github, slack, generic)
+	// Source is the webhook source type (github, gitlab, slack, stripe,
+	// linear, pagerduty, jira, sentry, twilio, generic, or a configured source)
 	Source string `yaml:"source" json:"source"`
 
 	// Workflow is the workflow to trigger
@@ -45,6 +48,10 @@ type Route struct {
 	// Secret is used for signature verification
 	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
 
+	// Filter is a condition over the request that must hold to trigger the
+	// workflow
+	Filter *workflow.WebhookFilter `yaml:"filter,omitempty" json:"filter,omitempty"`
+
 	// InputMapping defines how to map webhook payload to workflow inputs
 	InputMapping map[string]string `yaml:"input_mapping,omitempty" json:"input_mapping,omitempty"`
 }
@@ -53,14 +60,19 @@ type Route struct {
 type Config struct {
 	Routes       []Route `yaml:"routes" json:"routes"`
 	WorkflowsDir string  `yaml:"workflows_dir" json:"workflows_dir"`
+
+	// Sources are the webhook source handlers. If nil, the built-in
+	// sources are used.
+	Sources *Registry `yaml:"-" json:"-"`
 }
 
 // Router routes incoming webhooks to workflows.
 type Router struct {
 	routes       []Route
-	runner       *runner.Runner
 	workflowsDir string
-	handlers     map[string]Handler
+	sources      *Registry
+	events       *trigger.EventLog
+	failures     *SignatureFailures
 	logger       *slog.Logger
 }
 
@@ -78,22 +90,32 @@ type Handler interface {
 
 // NewRouter creates a new webhook router.
 func NewRouter(cfg Config, r *runner.Runner) *Router {
+	sources := cfg.Sources
+	if sources == nil {
+		sources = NewRegistry()
+	}
 	router := &Router{
 		routes:       cfg.Routes,
-		runner:       r,
 		workflowsDir: cfg.WorkflowsDir,
-		handlers:     make(map[string]Handler),
+		sources:      sources,
 		logger:       slog.Default().With(slog.String("component", "webhook")),
 	}
-
-	// Register built-in handlers
-	router.handlers["github"] = &GitHubHandler{}
-	router.handlers["slack"] = &SlackHandler{}
-	router.handlers["generic"] = &GenericHandler{}
+	router.failures = NewSignatureFailures(router.logger)
+	router.events = trigger.NewEventLog(trigger.EventLogConfig{
+		Submitter:    r,
+		WorkflowsDir: cfg.WorkflowsDir,
+		Logger:       router.logger,
+	})
 
 	return router
 }
 
+// SetEventLog sets the event log that records and delivers webhook events.
+// Without one, events are delivered but not recorded.
+func (router *Router) SetEventLog(events *trigger.EventLog) {
+	router.events = events
+}
+
 // RegisterRoutes registers webhook routes on the given mux.
 func (router *Router) RegisterRoutes(mux *http.ServeMux) {
 	for _, route := range router.routes {
@@ -109,13 +131,6 @@ func (router *Router) RegisterRoutes(mux *http.ServeMux) {
 
 // handleWebhook handles a webhook request for a specific route.
 func (router *Router) handleWebhook(w http.ResponseWriter, r *http.Request, route Route) {
-	// Check if runner is draining (graceful shutdown in progress)
-	if router.runner.IsDraining() {
-		w.Header().Set("Retry-After", "10")
-		writeError(w, http.StatusServiceUnavailable, "controller is shutting down gracefully")
-		return
-	}
-
 	// Read body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -124,85 +139,91 @@ func (router *Router) handleWebhook(w http.ResponseWriter, r *http.Request, rout
 	}
 
 	// Get handler for source
-	handler, ok := router.handlers[route.Source]
-	if !ok {
-		handler = router.handlers["generic"]
+	handler := router.sources.Handler(route.Source)
+
+	event := &backend.TriggerEvent{
+		Source:         trigger.SourceWebhook,
+		Trigger:        route.Path,
+		Workflow:       route.Workflow,
+		IdempotencyKey: IdempotencyKey(r),
+		Headers:        trigger.RecordedHeaders(r.Header),
 	}
 
-	// Verify signature if secret is configured
+	// Verify signature if secret is configured. Verify reserves the request
+	// against replays; the reservation is released if its event cannot be
+	// accepted, so the sender can retry
 	if route.Secret != "" {
 		if err := handler.Verify(r, body, route.Secret); err != nil {
-			router.logger.Warn("Webhook signature verification failed",
-				slog.String("path", route.Path),
-				slog.String("source", route.Source),
-				slog.Any("error", err),
-			)
+			router.failures.Record(route.Path, route.Source, err)
 			writeError(w, http.StatusUnauthorized, "signature verification failed")
 			return
 		}
 	}
 
 	// Parse event type
-	event := handler.ParseEvent(r)
-
-	// Check if this event should trigger the workflow
-	if len(route.Events) > 0 && !contains(route.Events, event) {
-		writeJSON(w, http.StatusOK, map[string]string{
-			"status":  "ignored",
-			"message": fmt.Sprintf("Event '%s' not in configured events", event),
-		})
-		return
-	}
+	event.Event = handler.ParseEvent(r)
 
 	// Extract payload
 	payload, err := handler.ExtractPayload(body)
 	if err != nil {
+		event.Payload = RawPayloadJSON(body)
+		router.reject(r, event, fmt.Sprintf("failed to parse payload: %v", err))
+		Release(handler, r, body, route.Secret)
 		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse payload: %v", err))
 		return
 	}
+	event.Payload = PayloadJSON(payload)
+	event.Event = EventType(handler, event.Event, payload)
 
-	// Map inputs
-	inputs := router.mapInputs(payload, route.InputMapping, event)
-
-	// Load and trigger workflow
-	workflowPath, err := router.findWorkflow(route.Workflow)
-	if err != nil {
-		writeError(w, http.StatusNotFound, fmt.Sprintf("workflow not found: %s", route.Workflow))
+	// Check if this event should trigger the workflow
+	if len(route.Events) > 0 && !contains(route.Events, event.Event) {
+		message := fmt.Sprintf("Event '%s' not in configured events", event.Event)
+		event.Status = backend.TriggerEventIgnored
+		event.Error = message
+		router.events.Record(r.Context(), event)
+		writeJSON(w, http.StatusOK, map[string]string{
+			"status":  "ignored",
+			"message": message,
+		})
 		return
 	}
 
-	workflowYAML, err := os.ReadFile(workflowPath)
+	// Check the route's filter
+	matched, err := Match(r.Context(), route.Filter, payload, r.Header, event.Event, route.Source)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read workflow: %v", err))
+		router.logger.Warn("Webhook filter failed",
+			slog.String("path", route.Path),
+			slog.Any("error", err),
+		)
+	}
+	if !matched {
+		router.logger.Info("Webhook skipped by filter",
+			slog.String("path", route.Path),
+			slog.String("workflow", route.Workflow),
+			slog.String("event", event.Event),
+		)
+		router.events.Record(r.Context(), SkippedEvent(event, err))
+		WriteSkipped(w)
 		return
 	}
 
-	run, err := router.runner.Submit(r.Context(), runner.SubmitRequest{
-		WorkflowYAML: workflowYAML,
-		Inputs:       inputs,
-	})
+	// Map inputs and trigger the workflow
+	event.Inputs, err = router.mapInputs(r.Context(), payload, route.InputMapping, event.Event)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to trigger workflow: %v", err))
+		router.reject(r, event, err.Error())
+		Release(handler, r, body, route.Secret)
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
-
-	writeJSON(w, http.StatusAccepted, map[string]any{
-		"status":   "triggered",
-		"run_id":   run.ID,
-		"workflow": run.Workflow,
-		"event":    event,
-	})
+	stored, duplicate, err := router.events.Deliver(r.Context(), event)
+	if err != nil {
+		Release(handler, r, body, route.Secret)
+	}
+	WriteDelivery(w, stored, duplicate, err)
 }
 
 // handleDynamicWebhook handles webhooks to /webhooks/{source}/{workflow}
 func (router *Router) handleDynamicWebhook(w http.ResponseWriter, r *http.Request) {
-	// Check if runner is draining (graceful shutdown in progress)
-	if router.runner.IsDraining() {
-		w.Header().Set("Retry-After", "10")
-		writeError(w, http.StatusServiceUnavailable, "controller is shutting down gracefully")
-		return
-	}
-
 	source := r.PathValue("source")
 	workflowName := r.PathValue("workflow")
 
@@ -212,10 +233,7 @@ func (router *Router) handleDynamicWebhook(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Get handler for source
-	handler, ok := router.handlers[source]
-	if !ok {
-		handler = router.handlers["generic"]
-	}
+	handler := router.sources.Handler(source)
 
 	// Read body
 	body, err := io.ReadAll(r.Body)
@@ -224,19 +242,29 @@ func (router *Router) handleDynamicWebhook(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Parse event
-	event := handler.ParseEvent(r)
+	event := &backend.TriggerEvent{
+		Source:         trigger.SourceWebhook,
+		Trigger:        r.URL.Path,
+		Workflow:       workflowName,
+		Event:          handler.ParseEvent(r),
+		IdempotencyKey: IdempotencyKey(r),
+		Headers:        trigger.RecordedHeaders(r.Header),
+	}
 
 	// Extract payload
 	payload, err := handler.ExtractPayload(body)
 	if err != nil {
+		event.Payload = RawPayloadJSON(body)
+		router.reject(r, event, fmt.Sprintf("failed to parse payload: %v", err))
 		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse payload: %v", err))
 		return
 	}
+	event.Payload = PayloadJSON(payload)
+	event.Event = EventType(handler, event.Event, payload)
 
 	// Create inputs from payload
 	inputs := map[string]any{
-		"_event":   event,
+		"_event":   event.Event,
 		"_source":  source,
 		"_payload": payload,
 	}
@@ -245,39 +273,22 @@ func (router *Router) handleDynamicWebhook(w http.ResponseWriter, r *http.Reques
 	for k, v := range payload {
 		inputs[k] = v
 	}
+	event.Inputs = inputs
 
-	// Load and trigger workflow
-	workflowPath, err := router.findWorkflow(workflowName)
-	if err != nil {
-		writeError(w, http.StatusNotFound, fmt.Sprintf("workflow not found: %s", workflowName))
-		return
-	}
-
-	workflowYAML, err := os.ReadFile(workflowPath)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read workflow: %v", err))
-		return
-	}
-
-	run, err := router.runner.Submit(r.Context(), runner.SubmitRequest{
-		WorkflowYAML: workflowYAML,
-		Inputs:       inputs,
-	})
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to trigger workflow: %v", err))
-		return
-	}
+	stored, duplicate, err := router.events.Deliver(r.Context(), event)
+	WriteDelivery(w, stored, duplicate, err)
+}
 
-	writeJSON(w, http.StatusAccepted, map[string]any{
-		"status":   "triggered",
-		"run_id":   run.ID,
-		"workflow": run.Workflow,
-		"event":    event,
-	})
+// reject records an event that failed parsing or input mapping.
+func (router *Router) reject(r *http.Request, event *backend.TriggerEvent, reason string) {
+	event.Status = backend.TriggerEventRejected
+	event.Error = reason
+	router.events.Record(r.Context(), event)
 }
 
 // mapInputs maps webhook payload to workflow inputs using the mapping.
-func (router *Router) mapInputs(payload map[string]any, mapping map[string]string, event string) map[string]any {
+// Mapping values that are not jq: queries or paths are literals.
+func (router *Router) mapInputs(ctx context.Context, payload map[string]any, mapping map[string]string, event string) (map[string]any, error) {
 	inputs := map[string]any{
 		"_event":   event,
 		"_payload": payload,
@@ -288,18 +299,24 @@ func (router *Router) mapInputs(payload map[string]any, mapping map[string]strin
 		for k, v := range payload {
 			inputs[k] = v
 		}
-		return inputs
+		return inputs, nil
 	}
 
 	// Apply mapping
 	for inputName, expr := range mapping {
-		value := evaluateExpression(expr, payload)
+		value, ok, err := MapInput(ctx, expr, payload)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			value = expr
+		}
 		if value != nil {
 			inputs[inputName] = value
 		}
 	}
 
-	return inputs
+	return inputs, nil
 }
 
 // evaluateExpression evaluates a simple JSONPath-like expression.
@@ -329,32 +346,6 @@ func evaluateExpression(expr string, payload map[string]any) any {
 	return current
 }
 
-// findWorkflow finds a workflow file by name.
-func (router *Router) findWorkflow(name string) (string, error) {
-	// Security: prevent directory traversal
-	name = filepath.Clean(name)
-	if strings.Contains(name, "..") {
-		return "", fmt.Errorf("invalid workflow name")
-	}
-
-	extensions := []string{".yaml", ".yml", ""}
-	baseDirs := []string{router.workflowsDir, "."}
-
-	for _, baseDir := range baseDirs {
-		if baseDir == "" {
-			continue
-		}
-		for _, ext := range extensions {
-			path := filepath.Join(baseDir, name+ext)
-			if info, err := os.Stat(path); err == nil && !info.IsDir() {
-				return path, nil
-			}
-		}
-	}
-
-	return "", fmt.Errorf("workflow not found: %s", name)
-}
-
 func writeJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -31,6 +31,7 @@ import (
 	"github.com/tombee/conductor/internal/controller/backend/memory"
 	"github.com/tombee/conductor/internal/controller/checkpoint"
 	"github.com/tombee/conductor/internal/controller/runner"
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
 func setupTestRouter(t *testing.T, routes []Route) (*http.ServeMux, string) {
@@ -275,6 +276,71 @@ func TestWebhookRouter_InputMapping(t *testing.T) {
 	}
 }
 
+func TestWebhookRouter_Filter(t *testing.T) {
+	routes := []Route{
+		{
+			Path:     "/webhooks/review",
+			Source:   "generic",
+			Workflow: "test-workflow",
+			Filter: &workflow.WebhookFilter{
+				Expr: `payload.pull_request.base.ref == "main" && "ai-review" in map(payload.pull_request.labels, .name)`,
+			},
+			InputMapping: map[string]string{
+				"labels": "jq: .pull_request.labels | map(.name)",
+			},
+		},
+	}
+	mux, _ := setupTestRouter(t, routes)
+
+	tests := []struct {
+		name       string
+		payload    string
+		wantStatus int
+		wantResult string
+	}{
+		{
+			name:       "matching pull request",
+			payload:    `{"pull_request": {"base": {"ref": "main"}, "labels": [{"name": "ai-review"}]}}`,
+			wantStatus: http.StatusAccepted,
+			wantResult: "triggered",
+		},
+		{
+			name:       "other branch",
+			payload:    `{"pull_request": {"base": {"ref": "dev"}, "labels": [{"name": "ai-review"}]}}`,
+			wantStatus: http.StatusAccepted,
+			wantResult: "skipped",
+		},
+		{
+			name:       "not a pull request",
+			payload:    `{"ref": "refs/heads/main"}`,
+			wantStatus: http.StatusAccepted,
+			wantResult: "skipped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhooks/review", strings.NewReader(tt.payload))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d. Body: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			var result map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result["status"] != tt.wantResult {
+				t.Errorf("got status %q, want %q", result["status"], tt.wantResult)
+			}
+		})
+	}
+}
+
 func TestWebhookRouter_GitHubSignatureVerification(t *testing.T) {
 	secret := "test-secret-key"
 	routes := []Route{
@@ -393,6 +459,120 @@ func TestWebhookRouter_SlackSignatureVerification(t *testing.T) {
 	}
 }
 
+func TestWebhookRouter_GitLabTokenVerification(t *testing.T) {
+	secret := "test-secret-token"
+	routes := []Route{
+		{
+			Path:     "/webhooks/gitlab",
+			Source:   "gitlab",
+			Workflow: "test-workflow",
+			Secret:   secret,
+			Events:   []string{"merge_request"},
+		},
+	}
+	mux, _ := setupTestRouter(t, routes)
+
+	payload := `{"object_kind": "merge_request", "object_attributes": {"iid": 1, "action": "open"}}`
+
+	tests := []struct {
+		name       string
+		token      string
+		event      string
+		wantStatus int
+	}{
+		{
+			name:       "valid token",
+			token:      secret,
+			event:      "Merge Request Hook",
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "other event",
+			token:      secret,
+			event:      "Push Hook",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid token",
+			token:      "wrong",
+			event:      "Merge Request Hook",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing token",
+			event:      "Merge Request Hook",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhooks/gitlab", strings.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Gitlab-Event", tt.event)
+			if tt.token != "" {
+				req.Header.Set("X-Gitlab-Token", tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d. Body: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookRouter_StripeEvents(t *testing.T) {
+	secret := "whsec_test"
+	routes := []Route{
+		{
+			Path:     "/webhooks/stripe",
+			Source:   "stripe",
+			Workflow: "test-workflow",
+			Secret:   secret,
+			Events:   []string{"invoice.paid"},
+		},
+	}
+	mux, _ := setupTestRouter(t, routes)
+
+	tests := []struct {
+		eventType  string
+		wantStatus int
+		wantResult string
+	}{
+		{"invoice.paid", http.StatusAccepted, "triggered"},
+		{"customer.created", http.StatusOK, "ignored"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			payload := fmt.Sprintf(`{"id": "evt_%s", "type": %q}`, tt.eventType, tt.eventType)
+			timestamp := fmt.Sprintf("%d", time.Now().Unix())
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte(timestamp + "." + payload))
+
+			req := httptest.NewRequest("POST", "/webhooks/stripe", strings.NewReader(payload))
+			req.Header.Set("Stripe-Signature", "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d. Body: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			var result map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result["status"] != tt.wantResult {
+				t.Errorf("got status %q, want %q", result["status"], tt.wantResult)
+			}
+		})
+	}
+}
+
 func TestWebhookRouter_DirectoryTraversal(t *testing.T) {
 	mux, _ := setupTestRouter(t, nil)
 
@@ -439,3 +619,42 @@ func TestEvaluateExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookRouter_ReplayAfterFailedDelivery(t *testing.T) {
+	routes := []Route{
+		{
+			Path:     "/webhooks/payments",
+			Source:   "stripe",
+			Workflow: "late-workflow",
+			Secret:   testSecret,
+		},
+	}
+	mux, tmpDir := setupTestRouter(t, routes)
+
+	body := `{"type": "invoice.paid"}`
+	now := fmt.Sprintf("%d", time.Now().Unix())
+	send := func() int {
+		req := httptest.NewRequest("POST", "/webhooks/payments", strings.NewReader(body))
+		req.Header.Set("Stripe-Signature", "t="+now+",v1="+hexSign(now+"."+body))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// The workflow does not exist yet, so delivery fails and the sender
+	// may retry the same request
+	if code := send(); code < 400 || code == http.StatusUnauthorized {
+		t.Fatalf("first delivery got status %d, want a delivery failure", code)
+	}
+
+	workflowYAML := "name: late-workflow\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "late-workflow.yaml"), []byte(workflowYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := send(); code != http.StatusAccepted {
+		t.Fatalf("retry got status %d, want %d", code, http.StatusAccepted)
+	}
+	if code := send(); code != http.StatusUnauthorized {
+		t.Errorf("replay got status %d, want %d", code, http.StatusUnauthorized)
+	}
+}
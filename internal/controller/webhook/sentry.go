@@ -0,0 +1,111 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultSentryReplayWindow is how long a Sentry request body is remembered.
+const DefaultSentryReplayWindow = 24 * time.Hour
+
+// SentryHandler handles Sentry integration webhooks.
+//
+// Sentry does not sign its timestamp header, so a captured request can be
+// resent with a fresh timestamp. Replays are instead keyed on the signed
+// body, which is remembered for ReplayWindow from when it was received. A
+// body resent after that window verifies again.
+type SentryHandler struct {
+	// Tolerance is how old the Sentry-Hook-Timestamp may be.
+	// Default: 5 minutes
+	Tolerance time.Duration
+
+	// ReplayWindow is how long a received body is rejected as a replay.
+	// Default: 24 hours
+	ReplayWindow time.Duration
+
+	replays ReplayCache
+}
+
+// Verify verifies the Sentry-Hook-Signature header, the hex HMAC-SHA256 of
+// the body, and checks the Sentry-Hook-Timestamp header.
+func (h *SentryHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature, expires, err := h.verify(r, body, secret)
+	if err != nil {
+		return err
+	}
+	return h.replays.Reserve(signature, expires)
+}
+
+// Release forgets a verified request whose event was not accepted, so that
+// it can be sent again.
+func (h *SentryHandler) Release(r *http.Request, body []byte, secret string) {
+	if signature, _, err := h.verify(r, body, secret); err == nil {
+		h.replays.Release(signature)
+	}
+}
+
+// verify checks the request's signature and returns it with the time it
+// stops being remembered.
+func (h *SentryHandler) verify(r *http.Request, body []byte, secret string) (string, time.Time, error) {
+	signature := r.Header.Get("Sentry-Hook-Signature")
+	timestamp := r.Header.Get("Sentry-Hook-Timestamp")
+	if signature == "" || timestamp == "" {
+		return "", time.Time{}, fmt.Errorf("missing required headers")
+	}
+
+	ts, err := strconv.ParseFloat(timestamp, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid timestamp")
+	}
+	sec, frac := math.Modf(ts)
+	sentAt := time.Unix(int64(sec), int64(frac*1e9))
+	if err := checkTimestamp(sentAt, h.Tolerance); err != nil {
+		return "", time.Time{}, err
+	}
+
+	expected := hex.EncodeToString(computeHMAC(sha256.New, secret, body))
+	if !matchSignature([]string{signature}, expected) {
+		return "", time.Time{}, fmt.Errorf("signature mismatch")
+	}
+
+	window := h.ReplayWindow
+	if window <= 0 {
+		window = DefaultSentryReplayWindow
+	}
+	return expected, time.Now().Add(window), nil
+}
+
+// ParseEvent parses the Sentry resource, such as "issue", "event_alert" or
+// "installation", from the request.
+func (h *SentryHandler) ParseEvent(r *http.Request) string {
+	return r.Header.Get("Sentry-Hook-Resource")
+}
+
+// ExtractPayload extracts the payload from a Sentry webhook.
+func (h *SentryHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
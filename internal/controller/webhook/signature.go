@@ -0,0 +1,145 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTolerance is how far a signed timestamp may be from the current
+// time before a request is rejected as a possible replay.
+const DefaultTolerance = 5 * time.Minute
+
+// replayPruneSize is the number of remembered signatures at which expired
+// ones are dropped.
+const replayPruneSize = 1024
+
+// ReplayCache remembers the signatures of verified requests until their
+// signed timestamp leaves the tolerance window, so a captured request cannot
+// be sent again while its timestamp is still accepted. The zero value is
+// ready to use.
+type ReplayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Reserve remembers a signature until expires. It returns an error if the
+// signature is already remembered, so of two concurrent copies of a request
+// only one is accepted.
+func (c *ReplayCache) Reserve(signature string, expires time.Time) error {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until, ok := c.seen[signature]; ok && now.Before(until) {
+		return fmt.Errorf("request replayed")
+	}
+	if c.seen == nil {
+		c.seen = make(map[string]time.Time)
+	}
+	if len(c.seen) >= replayPruneSize {
+		for key, until := range c.seen {
+			if !now.Before(until) {
+				delete(c.seen, key)
+			}
+		}
+	}
+	c.seen[signature] = expires
+	return nil
+}
+
+// Release forgets a reserved signature, so the request can be sent again.
+func (c *ReplayCache) Release(signature string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.seen, signature)
+}
+
+// tolerance returns a handler's timestamp tolerance, or DefaultTolerance.
+func tolerance(d time.Duration) time.Duration {
+	if d <= 0 {
+		return DefaultTolerance
+	}
+	return d
+}
+
+// checkTimestamp rejects a signed timestamp further than the tolerance from
+// now, in either direction.
+func checkTimestamp(ts time.Time, window time.Duration) error {
+	window = tolerance(window)
+	age := time.Since(ts)
+	if age > window {
+		return fmt.Errorf("request too old")
+	}
+	if age < -window {
+		return fmt.Errorf("request timestamp is in the future")
+	}
+	return nil
+}
+
+// computeHMAC returns the HMAC of data under secret.
+func computeHMAC(newHash func() hash.Hash, secret string, data []byte) []byte {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// matchSignature reports whether any of the signatures equals expected.
+func matchSignature(signatures []string, expected string) bool {
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(strings.TrimSpace(sig)), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestURL rebuilds the URL the sender requested, following the
+// X-Forwarded-Proto and X-Forwarded-Host headers set by a proxy.
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := r.Host
+	if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host + r.URL.RequestURI()
+}
+
+// signatureValues returns the values of a comma-separated list of key=value
+// pairs with the given key, such as the v1 signatures in
+// "t=1700000000,v1=abc,v1=def".
+func signatureValues(header, key string) []string {
+	var values []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && k == key {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -27,26 +27,49 @@ import (
 )
 
 // SlackHandler handles Slack webhooks.
-type SlackHandler struct{}
+type SlackHandler struct {
+	// Tolerance is how old the signed timestamp may be. Default: 5 minutes
+	Tolerance time.Duration
+
+	replays ReplayCache
+}
 
 // Verify verifies the Slack webhook signature.
 func (h *SlackHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature, expires, err := h.verify(r, body, secret)
+	if err != nil {
+		return err
+	}
+	return h.replays.Reserve(signature, expires)
+}
+
+// Release forgets a verified request whose event was not accepted, so that
+// it can be sent again.
+func (h *SlackHandler) Release(r *http.Request, body []byte, secret string) {
+	if signature, _, err := h.verify(r, body, secret); err == nil {
+		h.replays.Release(signature)
+	}
+}
+
+// verify checks the request's signature and returns it with the time it
+// stops being accepted.
+func (h *SlackHandler) verify(r *http.Request, body []byte, secret string) (string, time.Time, error) {
 	// Get timestamp and signature
 	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
 	signature := r.Header.Get("X-Slack-Signature")
 
 	if timestamp == "" || signature == "" {
-		return fmt.Errorf("missing required headers")
+		return "", time.Time{}, fmt.Errorf("missing required headers")
 	}
 
-	// Check timestamp to prevent replay attacks (5 minute window)
+	// Check timestamp to prevent replay attacks
 	ts, err := strconv.ParseInt(timestamp, 10, 64)
 	if err != nil {
-		return fmt.Errorf("invalid timestamp")
+		return "", time.Time{}, fmt.Errorf("invalid timestamp")
 	}
-
-	if abs(time.Now().Unix()-ts) > 300 {
-		return fmt.Errorf("request too old")
+	signedAt := time.Unix(ts, 0)
+	if err := checkTimestamp(signedAt, h.Tolerance); err != nil {
+		return "", time.Time{}, err
 	}
 
 	// Compute expected signature
@@ -58,10 +81,10 @@ func (h *SlackHandler) Verify(r *http.Request, body []byte, secret string) error
 
 	// Compare signatures
 	if !hmac.Equal([]byte(signature), []byte(expected)) {
-		return fmt.Errorf("signature mismatch")
+		return "", time.Time{}, fmt.Errorf("signature mismatch")
 	}
 
-	return nil
+	return expected, signedAt.Add(tolerance(h.Tolerance)), nil
 }
 
 // ParseEvent parses the Slack event type from the request.
@@ -160,10 +183,3 @@ const (
 	SlackEventReactionAdded   = "reaction_added"
 	SlackEventURLVerification = "url_verification"
 )
-
-func abs(x int64) int64 {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
@@ -0,0 +1,107 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StripeHandler handles Stripe webhooks.
+type StripeHandler struct {
+	// Tolerance is how old the signed timestamp may be. Default: 5 minutes
+	Tolerance time.Duration
+
+	replays ReplayCache
+}
+
+// Verify verifies the Stripe-Signature header, which holds the signing
+// time and one or more HMAC-SHA256 signatures of "<time>.<body>":
+// t=1700000000,v1=<hex>. Several v1 signatures are sent while a secret is
+// rolled.
+func (h *StripeHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature, expires, err := h.verify(r, body, secret)
+	if err != nil {
+		return err
+	}
+	return h.replays.Reserve(signature, expires)
+}
+
+// Release forgets a verified request whose event was not accepted, so that
+// it can be sent again.
+func (h *StripeHandler) Release(r *http.Request, body []byte, secret string) {
+	if signature, _, err := h.verify(r, body, secret); err == nil {
+		h.replays.Release(signature)
+	}
+}
+
+// verify checks the request's signature and returns it with the time it
+// stops being accepted.
+func (h *StripeHandler) verify(r *http.Request, body []byte, secret string) (string, time.Time, error) {
+	header := r.Header.Get("Stripe-Signature")
+	if header == "" {
+		return "", time.Time{}, fmt.Errorf("missing Stripe-Signature header")
+	}
+
+	timestamps := signatureValues(header, "t")
+	signatures := signatureValues(header, "v1")
+	if len(timestamps) != 1 || len(signatures) == 0 {
+		return "", time.Time{}, fmt.Errorf("invalid signature format")
+	}
+
+	ts, err := strconv.ParseInt(timestamps[0], 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid timestamp")
+	}
+	signedAt := time.Unix(ts, 0)
+	if err := checkTimestamp(signedAt, h.Tolerance); err != nil {
+		return "", time.Time{}, err
+	}
+
+	signed := append([]byte(timestamps[0]+"."), body...)
+	expected := hex.EncodeToString(computeHMAC(sha256.New, secret, signed))
+	if !matchSignature(signatures, expected) {
+		return "", time.Time{}, fmt.Errorf("signature mismatch")
+	}
+
+	return expected, signedAt.Add(tolerance(h.Tolerance)), nil
+}
+
+// ParseEvent returns "" because Stripe sends the event type in the
+// payload. See PayloadEvent.
+func (h *StripeHandler) ParseEvent(r *http.Request) string {
+	return ""
+}
+
+// PayloadEvent returns the Stripe event type, such as
+// "payment_intent.succeeded".
+func (h *StripeHandler) PayloadEvent(payload map[string]any) string {
+	event, _ := payload["type"].(string)
+	return event
+}
+
+// ExtractPayload extracts the payload from a Stripe webhook.
+func (h *StripeHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return payload, nil
+}
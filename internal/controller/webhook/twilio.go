@@ -0,0 +1,138 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// TwilioHandler handles Twilio webhooks.
+//
+// Twilio does not sign a timestamp, and retries resend the same signature,
+// so replays are caught by the I-Twilio-Idempotency-Token idempotency key
+// rather than a time window.
+type TwilioHandler struct{}
+
+// Verify verifies the X-Twilio-Signature header, the base64 HMAC-SHA1 of
+// the URL Twilio requested. For form posts, each parameter's name and value
+// are appended to the URL in name order. JSON bodies are covered by the
+// bodySHA256 query parameter instead.
+//
+// Behind a proxy, set X-Forwarded-Proto and X-Forwarded-Host so the URL can
+// be rebuilt.
+func (h *TwilioHandler) Verify(r *http.Request, body []byte, secret string) error {
+	signature := r.Header.Get("X-Twilio-Signature")
+	if signature == "" {
+		return fmt.Errorf("missing X-Twilio-Signature header")
+	}
+
+	requestURL := requestURL(r)
+	signed := requestURL
+	if bodyHash := r.URL.Query().Get("bodySHA256"); bodyHash != "" {
+		sum := sha256.Sum256(body)
+		if !matchSignature([]string{strings.ToLower(bodyHash)}, hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("body hash mismatch")
+		}
+	} else if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		params, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Errorf("invalid form body")
+		}
+		signed += twilioParams(params)
+	}
+
+	expected := base64.StdEncoding.EncodeToString(computeHMAC(sha1.New, secret, []byte(signed)))
+	if !matchSignature([]string{signature}, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// ParseEvent returns "" because Twilio callbacks carry no event type
+// header. See PayloadEvent.
+func (h *TwilioHandler) ParseEvent(r *http.Request) string {
+	return ""
+}
+
+// PayloadEvent returns "message" for messaging callbacks and "call" for
+// voice callbacks.
+func (h *TwilioHandler) PayloadEvent(payload map[string]any) string {
+	switch {
+	case payload["MessageSid"] != nil || payload["SmsSid"] != nil:
+		return "message"
+	case payload["CallSid"] != nil:
+		return "call"
+	}
+	return ""
+}
+
+// ExtractPayload extracts the payload from a Twilio webhook: a JSON body,
+// or form parameters with repeated parameters as lists.
+func (h *TwilioHandler) ExtractPayload(body []byte) (map[string]any, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		return payload, nil
+	}
+
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse form body: %w", err)
+	}
+	payload = make(map[string]any, len(params))
+	for name, values := range params {
+		if len(values) == 1 {
+			payload[name] = values[0]
+			continue
+		}
+		list := make([]any, len(values))
+		for i, v := range values {
+			list[i] = v
+		}
+		payload[name] = list
+	}
+	return payload, nil
+}
+
+// twilioParams concatenates form parameters as Twilio signs them: sorted by
+// name, each name followed by its value, repeated names once per value.
+func twilioParams(params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, v := range values {
+			b.WriteString(name)
+			b.WriteString(v)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tombee/conductor/internal/operation/transport"
+)
+
+// GitLabError represents a GitLab API error response.
+type GitLabError struct {
+	Message            string
+	StatusCode         int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+}
+
+// Error implements the error interface.
+func (e *GitLabError) Error() string {
+	msg := fmt.Sprintf("GitLab API error: %s (status %d)", e.Message, e.StatusCode)
+
+	if e.StatusCode == 429 && !e.RateLimitReset.IsZero() {
+		msg += fmt.Sprintf(" - rate limit exceeded, resets at %s", e.RateLimitReset.Format(time.RFC3339))
+	}
+
+	return msg
+}
+
+// ParseError parses a GitLab error response.
+func ParseError(resp *transport.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	glErr := &GitLabError{
+		StatusCode:         resp.StatusCode,
+		RateLimitRemaining: -1,
+	}
+
+	// Parse rate limit headers
+	if remaining := resp.Headers["Ratelimit-Remaining"]; len(remaining) > 0 {
+		if val, err := strconv.Atoi(remaining[0]); err == nil {
+			glErr.RateLimitRemaining = val
+		}
+	}
+	if reset := resp.Headers["Ratelimit-Reset"]; len(reset) > 0 {
+		if val, err := strconv.ParseInt(reset[0], 10, 64); err == nil {
+			glErr.RateLimitReset = time.Unix(val, 0)
+		}
+	}
+
+	// GitLab returns {"message": "..."}, {"message": {"field": ["..."]}}
+	// or {"error": "..."}
+	if len(resp.Body) > 0 {
+		var errResp struct {
+			Message json.RawMessage `json:"message"`
+			Error   string          `json:"error"`
+		}
+
+		if err := json.Unmarshal(resp.Body, &errResp); err == nil {
+			glErr.Message = errorMessage(errResp.Message)
+			if glErr.Message == "" {
+				glErr.Message = errResp.Error
+			}
+		} else {
+			// Fallback to raw body as message
+			glErr.Message = string(resp.Body)
+		}
+	}
+
+	// If no message was parsed, use a generic message based on status code
+	if glErr.Message == "" {
+		glErr.Message = getDefaultMessage(resp.StatusCode)
+	}
+
+	return glErr
+}
+
+// errorMessage flattens a GitLab error message, which is either a string or
+// a map of field names to validation errors.
+func errorMessage(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var fields map[string][]string
+	if err := json.Unmarshal(raw, &fields); err == nil {
+		parts := make([]string, 0, len(fields))
+		for field, errs := range fields {
+			parts = append(parts, fmt.Sprintf("%s %s", field, strings.Join(errs, ", ")))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, "; ")
+	}
+
+	return string(raw)
+}
+
+// getDefaultMessage returns a default error message for a status code.
+func getDefaultMessage(statusCode int) string {
+	switch statusCode {
+	case 400:
+		return "Bad request"
+	case 401:
+		return "Unauthorized - check your token"
+	case 403:
+		return "Forbidden - check your permissions"
+	case 404:
+		return "Not found"
+	case 405:
+		return "Method not allowed - the merge request may not be mergeable"
+	case 409:
+		return "Conflict"
+	case 422:
+		return "Unprocessable entity - validation failed"
+	case 429:
+		return "Rate limit exceeded"
+	case 500:
+		return "Internal server error"
+	case 502:
+		return "Bad gateway"
+	case 503:
+		return "Service unavailable"
+	default:
+		return fmt.Sprintf("Request failed with status %d", statusCode)
+	}
+}
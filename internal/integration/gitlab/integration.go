@@ -0,0 +1,156 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tombee/conductor/internal/operation"
+	"github.com/tombee/conductor/internal/operation/api"
+)
+
+// DefaultBaseURL is the GitLab.com REST API. Self-hosted instances use
+// https://<host>/api/v4.
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+// GitLabIntegration implements the Provider interface for GitLab API.
+type GitLabIntegration struct {
+	*api.BaseProvider
+	baseURL string
+}
+
+// NewGitLabIntegration creates a new GitLab integration.
+func NewGitLabIntegration(config *api.ProviderConfig) (operation.Provider, error) {
+	// Set default base URL if not provided
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	base := api.NewBaseProvider("gitlab", config)
+
+	return &GitLabIntegration{
+		BaseProvider: base,
+		baseURL:      config.BaseURL,
+	}, nil
+}
+
+// Execute runs a named operation with the given inputs.
+func (c *GitLabIntegration) Execute(ctx context.Context, operation string, inputs map[string]interface{}) (*operation.Result, error) {
+	switch operation {
+	// Merge Requests
+	case "create_merge_request":
+		return c.createMergeRequest(ctx, inputs)
+	case "get_merge_request":
+		return c.getMergeRequest(ctx, inputs)
+	case "update_merge_request":
+		return c.updateMergeRequest(ctx, inputs)
+	case "merge_merge_request":
+		return c.mergeMergeRequest(ctx, inputs)
+	case "list_merge_requests":
+		return c.list(ctx, operation, inputs)
+
+	// Notes
+	case "add_note":
+		return c.addNote(ctx, inputs)
+	case "list_notes":
+		return c.list(ctx, operation, inputs)
+
+	// Issues
+	case "create_issue":
+		return c.createIssue(ctx, inputs)
+	case "update_issue":
+		return c.updateIssue(ctx, inputs)
+	case "close_issue":
+		return c.closeIssue(ctx, inputs)
+	case "list_issues":
+		return c.list(ctx, operation, inputs)
+
+	// Pipelines
+	case "create_pipeline":
+		return c.createPipeline(ctx, inputs)
+	case "get_pipeline":
+		return c.getPipeline(ctx, inputs)
+	case "retry_pipeline":
+		return c.retryPipeline(ctx, inputs)
+	case "list_pipelines":
+		return c.list(ctx, operation, inputs)
+
+	// Repository files
+	case "get_file":
+		return c.getFile(ctx, inputs)
+
+	// Releases
+	case "create_release":
+		return c.createRelease(ctx, inputs)
+	case "list_releases":
+		return c.list(ctx, operation, inputs)
+
+	default:
+		return nil, fmt.Errorf("unknown operation: %s", operation)
+	}
+}
+
+// Operations returns the list of available operations.
+func (c *GitLabIntegration) Operations() []api.OperationInfo {
+	return []api.OperationInfo{
+		// Merge Requests
+		{Name: "create_merge_request", Description: "Create a merge request", Category: "merge_requests", Tags: []string{"write"}},
+		{Name: "get_merge_request", Description: "Get details for a specific merge request", Category: "merge_requests", Tags: []string{"read"}},
+		{Name: "update_merge_request", Description: "Update a merge request", Category: "merge_requests", Tags: []string{"write"}},
+		{Name: "merge_merge_request", Description: "Merge a merge request", Category: "merge_requests", Tags: []string{"write"}},
+		{Name: "list_merge_requests", Description: "List merge requests with filtering", Category: "merge_requests", Tags: []string{"read", "paginated"}},
+
+		// Notes
+		{Name: "add_note", Description: "Add a comment to a merge request or issue", Category: "notes", Tags: []string{"write"}},
+		{Name: "list_notes", Description: "List comments on a merge request or issue", Category: "notes", Tags: []string{"read", "paginated"}},
+
+		// Issues
+		{Name: "create_issue", Description: "Create a new issue", Category: "issues", Tags: []string{"write"}},
+		{Name: "update_issue", Description: "Update an existing issue", Category: "issues", Tags: []string{"write"}},
+		{Name: "close_issue", Description: "Close an issue", Category: "issues", Tags: []string{"write"}},
+		{Name: "list_issues", Description: "List issues with filtering", Category: "issues", Tags: []string{"read", "paginated"}},
+
+		// Pipelines
+		{Name: "create_pipeline", Description: "Run a pipeline for a branch or tag", Category: "pipelines", Tags: []string{"write"}},
+		{Name: "get_pipeline", Description: "Get details for a specific pipeline", Category: "pipelines", Tags: []string{"read"}},
+		{Name: "retry_pipeline", Description: "Retry the failed jobs of a pipeline", Category: "pipelines", Tags: []string{"write"}},
+		{Name: "list_pipelines", Description: "List pipelines with filtering", Category: "pipelines", Tags: []string{"read", "paginated"}},
+
+		// Repository files
+		{Name: "get_file", Description: "Get file contents from a repository", Category: "files", Tags: []string{"read"}},
+
+		// Releases
+		{Name: "create_release", Description: "Create a new release", Category: "releases", Tags: []string{"write"}},
+		{Name: "list_releases", Description: "List releases", Category: "releases", Tags: []string{"read", "paginated"}},
+	}
+}
+
+// OperationSchema returns the schema for an operation.
+func (c *GitLabIntegration) OperationSchema(operation string) *api.OperationSchema {
+	// This would return detailed schema information for each operation
+	// For now, returning nil (would be implemented based on requirements)
+	return nil
+}
+
+// defaultHeaders returns default headers for GitLab API requests.
+func (c *GitLabIntegration) defaultHeaders() map[string]string {
+	return map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+}
+
+// buildURL constructs a URL from a path template, escaping each path
+// parameter. Projects can be given by ID or by path ("group/project"),
+// which GitLab expects URL-encoded.
+func (c *GitLabIntegration) buildURL(pathTemplate string, inputs map[string]interface{}, pathParams []string) (string, error) {
+	escaped := make(map[string]interface{}, len(pathParams))
+	for _, param := range pathParams {
+		if value, ok := inputs[param]; ok {
+			escaped[param] = url.PathEscape(fmt.Sprint(value))
+		}
+	}
+	return c.BuildURL(pathTemplate, escaped)
+}
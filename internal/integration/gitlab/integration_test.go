@@ -0,0 +1,340 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tombee/conductor/internal/operation/api"
+	"github.com/tombee/conductor/internal/operation/transport"
+)
+
+// newTestIntegration creates a GitLab integration that talks to server.
+func newTestIntegration(t *testing.T, server *httptest.Server) *GitLabIntegration {
+	t.Helper()
+
+	httpTransport, err := transport.NewHTTPTransport(&transport.HTTPTransportConfig{
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport() error = %v", err)
+	}
+
+	conn, err := NewGitLabIntegration(&api.ProviderConfig{
+		BaseURL:   server.URL + "/api/v4",
+		Token:     "test-token",
+		Transport: httpTransport,
+	})
+	if err != nil {
+		t.Fatalf("NewGitLabIntegration() error = %v", err)
+	}
+	return conn.(*GitLabIntegration)
+}
+
+func TestNewGitLabIntegration(t *testing.T) {
+	conn, err := NewGitLabIntegration(&api.ProviderConfig{
+		Token:     "test-token",
+		Transport: &transport.HTTPTransport{},
+	})
+	if err != nil {
+		t.Fatalf("NewGitLabIntegration() error = %v", err)
+	}
+
+	if conn.Name() != "gitlab" {
+		t.Errorf("Expected integration name 'gitlab', got '%s'", conn.Name())
+	}
+	if gl := conn.(*GitLabIntegration); gl.baseURL != DefaultBaseURL {
+		t.Errorf("baseURL = %q, want %q", gl.baseURL, DefaultBaseURL)
+	}
+}
+
+func TestGitLabIntegration_Operations(t *testing.T) {
+	conn, err := NewGitLabIntegration(&api.ProviderConfig{
+		Token:     "test-token",
+		Transport: &transport.HTTPTransport{},
+	})
+	if err != nil {
+		t.Fatalf("NewGitLabIntegration() error = %v", err)
+	}
+
+	ops := conn.(*GitLabIntegration).Operations()
+	byName := make(map[string]api.OperationInfo)
+	for _, op := range ops {
+		byName[op.Name] = op
+	}
+
+	for _, name := range []string{"create_merge_request", "list_merge_requests", "add_note", "list_issues", "create_pipeline", "get_file", "create_release"} {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("missing operation %s", name)
+		}
+	}
+	for _, op := range ops {
+		if op.Category == "" || op.Description == "" {
+			t.Errorf("operation %s has incomplete metadata", op.Name)
+		}
+	}
+}
+
+func TestGitLabIntegration_CreateMergeRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The project path is sent as one encoded path segment
+		if r.Method != "POST" || r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/merge_requests" {
+			t.Errorf("request = %s %s", r.Method, r.URL.EscapedPath())
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["project"]; ok {
+			t.Error("body contains the project path parameter")
+		}
+		if body["source_branch"] != "feature" {
+			t.Errorf("body = %v", body)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(MergeRequest{
+			ID:           100,
+			IID:          7,
+			Title:        "Add feature",
+			State:        "opened",
+			SourceBranch: "feature",
+			TargetBranch: "main",
+			WebURL:       "https://gitlab.example.com/group/project/-/merge_requests/7",
+		})
+	}))
+	defer server.Close()
+
+	gl := newTestIntegration(t, server)
+	result, err := gl.Execute(context.Background(), "create_merge_request", map[string]interface{}{
+		"project":       "group/project",
+		"source_branch": "feature",
+		"target_branch": "main",
+		"title":         "Add feature",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	mr := result.Response.(map[string]interface{})
+	if mr["iid"] != 7 || mr["state"] != "opened" {
+		t.Errorf("Response = %v", mr)
+	}
+}
+
+func TestGitLabIntegration_AddNote(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Note{ID: 5, Body: "Looks good"})
+	}))
+	defer server.Close()
+
+	gl := newTestIntegration(t, server)
+	tests := []struct {
+		name     string
+		inputs   map[string]interface{}
+		wantPath string
+		wantErr  string
+	}{
+		{"merge request", map[string]interface{}{"project": 42, "merge_request_iid": 7, "body": "Looks good"}, "/api/v4/projects/42/merge_requests/7/notes", ""},
+		{"issue", map[string]interface{}{"project": 42, "issue_iid": 3, "body": "Looks good"}, "/api/v4/projects/42/issues/3/notes", ""},
+		{"no target", map[string]interface{}{"project": 42, "body": "Looks good"}, "", "merge_request_iid or issue_iid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath = ""
+			_, err := gl.Execute(context.Background(), "add_note", tt.inputs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("path = %q, want %q", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestGitLabIntegration_GetFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/42/repository/files/docs%2FREADME.md" || r.URL.Query().Get("ref") != "main" {
+			t.Errorf("request = %s", r.URL.String())
+		}
+		json.NewEncoder(w).Encode(File{FileName: "README.md", FilePath: "docs/README.md", Encoding: "base64", Content: "aGVsbG8="})
+	}))
+	defer server.Close()
+
+	gl := newTestIntegration(t, server)
+	result, err := gl.Execute(context.Background(), "get_file", map[string]interface{}{
+		"project":   42,
+		"file_path": "docs/README.md",
+		"ref":       "main",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if file := result.Response.(map[string]interface{}); file["content"] != "aGVsbG8=" || file["path"] != "docs/README.md" {
+		t.Errorf("Response = %v", file)
+	}
+}
+
+func TestGitLabIntegration_ExecutePaginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("pagination") != "keyset" || query.Get("per_page") != "2" {
+			t.Errorf("query = %v", query)
+		}
+
+		switch query.Get("id_after") {
+		case "":
+			// The next link may name another host, as behind a proxy
+			w.Header().Set("Link", `<https://gitlab.internal/api/v4/projects/42/pipelines?id_after=2&order_by=id&pagination=keyset&per_page=2&sort=asc>; rel="next"`)
+			json.NewEncoder(w).Encode([]Pipeline{{ID: 1}, {ID: 2}})
+		case "2":
+			json.NewEncoder(w).Encode([]Pipeline{{ID: 3}})
+		default:
+			t.Errorf("unexpected page %s", r.URL.String())
+		}
+	}))
+	defer server.Close()
+
+	gl := newTestIntegration(t, server)
+	resultsChan, err := gl.ExecutePaginated(context.Background(), "list_pipelines", map[string]interface{}{
+		"project":    42,
+		"paginate":   true,
+		"per_page":   2,
+		"pagination": "keyset",
+		"order_by":   "id",
+		"sort":       "asc",
+	})
+	if err != nil {
+		t.Fatalf("ExecutePaginated() error = %v", err)
+	}
+
+	var ids []interface{}
+	for result := range resultsChan {
+		if errMsg, ok := result.Metadata["error"]; ok {
+			t.Fatalf("Got error in paginated results: %v", errMsg)
+		}
+		for _, pipeline := range result.Response.([]map[string]interface{}) {
+			ids = append(ids, pipeline["id"])
+		}
+	}
+
+	if len(ids) != 3 || ids[2] != int64(3) {
+		t.Errorf("pipeline IDs = %v, want [1 2 3]", ids)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	current := "https://gitlab.example.com/api/v4/projects/42/issues?page=1&per_page=20"
+
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		want    string
+	}{
+		{"no more pages", map[string][]string{}, ""},
+		{"offset", map[string][]string{"X-Next-Page": {"2"}}, "https://gitlab.example.com/api/v4/projects/42/issues?page=2&per_page=20"},
+		{"empty next page", map[string][]string{"X-Next-Page": {""}}, ""},
+		{
+			"link",
+			map[string][]string{"Link": {`<https://gitlab.example.com/api/v4/projects/42/issues?cursor=abc&per_page=20>; rel="next", <https://gitlab.example.com/api/v4/projects/42/issues?page=1>; rel="first"`}},
+			"https://gitlab.example.com/api/v4/projects/42/issues?cursor=abc&per_page=20",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(current, tt.headers); got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLabIntegration_ErrorHandling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "401 Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	gl := newTestIntegration(t, server)
+	_, err := gl.Execute(context.Background(), "list_issues", map[string]interface{}{"project": 42})
+
+	// The error is wrapped by the transport layer
+	if err == nil {
+		t.Fatal("Expected error for auth failure, got nil")
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"success", http.StatusOK, `[]`, ""},
+		{"field errors", http.StatusBadRequest, `{"message": {"title": ["can't be blank"], "labels": ["is invalid"]}}`, "labels is invalid; title can't be blank"},
+		{"message", http.StatusUnauthorized, `{"message": "401 Unauthorized"}`, "401 Unauthorized (status 401)"},
+		{"error field", http.StatusForbidden, `{"error": "insufficient_scope"}`, "insufficient_scope"},
+		{"empty body", http.StatusNotFound, ``, "Not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseError(&transport.Response{StatusCode: tt.status, Body: []byte(tt.body)})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseError() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseError() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitLabIntegration_Validation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.String())
+	}))
+	defer server.Close()
+
+	gl := newTestIntegration(t, server)
+	tests := []struct {
+		operation string
+		wantErr   string
+	}{
+		{"create_merge_request", "project"},
+		{"get_pipeline", "project"},
+		{"list_releases", "project"},
+		{"nonexistent", "unknown operation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			_, err := gl.Execute(context.Background(), tt.operation, map[string]interface{}{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := gl.ExecutePaginated(context.Background(), "get_file", map[string]interface{}{"paginate": true}); err == nil {
+		t.Error("ExecutePaginated(get_file) error = nil, want unsupported")
+	}
+}
@@ -0,0 +1,110 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/tombee/conductor/internal/operation"
+)
+
+// createIssue creates a new GitLab issue.
+func (c *GitLabIntegration) createIssue(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "title"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project"}
+	url, err := c.buildURL("/projects/{project}/issues", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (exclude path parameters)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.issueRequest(ctx, "POST", url, body)
+}
+
+// updateIssue updates an existing GitLab issue.
+func (c *GitLabIntegration) updateIssue(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "issue_iid"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project", "issue_iid"}
+	url, err := c.buildURL("/projects/{project}/issues/{issue_iid}", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (exclude path parameters)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.issueRequest(ctx, "PUT", url, body)
+}
+
+// closeIssue closes a GitLab issue.
+func (c *GitLabIntegration) closeIssue(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "issue_iid"}); err != nil {
+		return nil, err
+	}
+
+	url, err := c.buildURL("/projects/{project}/issues/{issue_iid}", inputs, []string{"project", "issue_iid"})
+	if err != nil {
+		return nil, err
+	}
+
+	// Hardcode state event to close
+	body := []byte(`{"state_event":"close"}`)
+
+	return c.issueRequest(ctx, "PUT", url, body)
+}
+
+// issueRequest sends a request that returns a single issue.
+func (c *GitLabIntegration) issueRequest(ctx context.Context, method, url string, body []byte) (*operation.Result, error) {
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, method, url, c.defaultHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var issue Issue
+	if err := c.ParseJSONResponse(resp, &issue); err != nil {
+		return nil, err
+	}
+
+	return c.ToResult(resp, issueResult(issue)), nil
+}
+
+// issueResult converts an issue to the operation result format.
+func issueResult(issue Issue) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":         issue.ID,
+		"iid":        issue.IID,
+		"title":      issue.Title,
+		"state":      issue.State,
+		"web_url":    issue.WebURL,
+		"author":     issue.Author.Username,
+		"labels":     issue.Labels,
+		"created_at": issue.CreatedAt,
+		"updated_at": issue.UpdatedAt,
+	}
+	if issue.ClosedAt != nil {
+		result["closed_at"] = issue.ClosedAt
+	}
+	return result
+}
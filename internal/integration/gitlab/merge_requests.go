@@ -0,0 +1,140 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/tombee/conductor/internal/operation"
+)
+
+// createMergeRequest creates a new GitLab merge request.
+func (c *GitLabIntegration) createMergeRequest(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "source_branch", "target_branch", "title"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project"}
+	url, err := c.buildURL("/projects/{project}/merge_requests", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (exclude path parameters)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mergeRequestRequest(ctx, "POST", url, body)
+}
+
+// getMergeRequest gets a single GitLab merge request.
+func (c *GitLabIntegration) getMergeRequest(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "merge_request_iid"}); err != nil {
+		return nil, err
+	}
+
+	url, err := c.buildURL("/projects/{project}/merge_requests/{merge_request_iid}", inputs, []string{"project", "merge_request_iid"})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mergeRequestRequest(ctx, "GET", url, nil)
+}
+
+// updateMergeRequest updates a GitLab merge request. Set state_event to
+// close or reopen to change its state.
+func (c *GitLabIntegration) updateMergeRequest(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "merge_request_iid"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project", "merge_request_iid"}
+	url, err := c.buildURL("/projects/{project}/merge_requests/{merge_request_iid}", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (exclude path parameters)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mergeRequestRequest(ctx, "PUT", url, body)
+}
+
+// mergeMergeRequest merges a GitLab merge request.
+func (c *GitLabIntegration) mergeMergeRequest(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "merge_request_iid"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project", "merge_request_iid"}
+	url, err := c.buildURL("/projects/{project}/merge_requests/{merge_request_iid}/merge", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (e.g., squash, should_remove_source_branch, sha)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mergeRequestRequest(ctx, "PUT", url, body)
+}
+
+// mergeRequestRequest sends a request that returns a single merge request.
+func (c *GitLabIntegration) mergeRequestRequest(ctx context.Context, method, url string, body []byte) (*operation.Result, error) {
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, method, url, c.defaultHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var mr MergeRequest
+	if err := c.ParseJSONResponse(resp, &mr); err != nil {
+		return nil, err
+	}
+
+	return c.ToResult(resp, mergeRequestResult(mr)), nil
+}
+
+// mergeRequestResult converts a merge request to the operation result format.
+func mergeRequestResult(mr MergeRequest) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":            mr.ID,
+		"iid":           mr.IID,
+		"title":         mr.Title,
+		"description":   mr.Description,
+		"state":         mr.State,
+		"web_url":       mr.WebURL,
+		"source_branch": mr.SourceBranch,
+		"target_branch": mr.TargetBranch,
+		"author":        mr.Author.Username,
+		"labels":        mr.Labels,
+		"draft":         mr.Draft,
+		"merge_status":  mr.MergeStatus,
+		"sha":           mr.SHA,
+		"created_at":    mr.CreatedAt,
+		"updated_at":    mr.UpdatedAt,
+	}
+	if mr.MergedAt != nil {
+		result["merged_at"] = mr.MergedAt
+		result["merge_commit_sha"] = mr.MergeCommitSHA
+	}
+	if mr.ClosedAt != nil {
+		result["closed_at"] = mr.ClosedAt
+	}
+	return result
+}
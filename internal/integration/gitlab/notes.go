@@ -0,0 +1,73 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tombee/conductor/internal/operation"
+)
+
+// addNote adds a comment to a GitLab merge request or issue.
+func (c *GitLabIntegration) addNote(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "body"}); err != nil {
+		return nil, err
+	}
+
+	path, pathParams, err := notesPath(inputs)
+	if err != nil {
+		return nil, err
+	}
+	url, err := c.buildURL(path, inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (exclude path parameters)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, "POST", url, c.defaultHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var note Note
+	if err := c.ParseJSONResponse(resp, &note); err != nil {
+		return nil, err
+	}
+
+	return c.ToResult(resp, noteResult(note)), nil
+}
+
+// noteResult converts a note to the operation result format.
+func noteResult(note Note) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         note.ID,
+		"body":       note.Body,
+		"author":     note.Author.Username,
+		"system":     note.System,
+		"created_at": note.CreatedAt,
+	}
+}
+
+// notesPath returns the notes endpoint for the merge request or issue in
+// inputs, and its path parameters.
+func notesPath(inputs map[string]interface{}) (string, []string, error) {
+	if _, ok := inputs["merge_request_iid"]; ok {
+		return "/projects/{project}/merge_requests/{merge_request_iid}/notes", []string{"project", "merge_request_iid"}, nil
+	}
+	if _, ok := inputs["issue_iid"]; ok {
+		return "/projects/{project}/issues/{issue_iid}/notes", []string{"project", "issue_iid"}, nil
+	}
+	return "", nil, fmt.Errorf("missing required parameter: merge_request_iid or issue_iid")
+}
@@ -0,0 +1,246 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	op "github.com/tombee/conductor/internal/operation"
+	"github.com/tombee/conductor/internal/operation/transport"
+)
+
+// nextLinkRegex matches the "next" relation of an RFC 5988 Link header.
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// listEndpoint returns the path template and path parameters of a list
+// operation.
+func listEndpoint(operation string, inputs map[string]interface{}) (string, []string, error) {
+	switch operation {
+	case "list_merge_requests":
+		return "/projects/{project}/merge_requests", []string{"project"}, nil
+	case "list_issues":
+		return "/projects/{project}/issues", []string{"project"}, nil
+	case "list_notes":
+		return notesPath(inputs)
+	case "list_pipelines":
+		return "/projects/{project}/pipelines", []string{"project"}, nil
+	case "list_releases":
+		return "/projects/{project}/releases", []string{"project"}, nil
+	default:
+		return "", nil, fmt.Errorf("operation %s does not support pagination", operation)
+	}
+}
+
+// listURL builds the first page URL of a list operation. Inputs other than
+// path parameters are passed as query parameters.
+func (c *GitLabIntegration) listURL(operation string, inputs map[string]interface{}) (string, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project"}); err != nil {
+		return "", err
+	}
+
+	path, pathParams, err := listEndpoint(operation, inputs)
+	if err != nil {
+		return "", err
+	}
+	pageURL, err := c.buildURL(path, inputs, pathParams)
+	if err != nil {
+		return "", err
+	}
+	return pageURL + c.BuildQueryString(inputs, pathParams), nil
+}
+
+// list runs a list operation and returns its first page.
+func (c *GitLabIntegration) list(ctx context.Context, operation string, inputs map[string]interface{}) (*op.Result, error) {
+	pageURL, err := c.listURL(operation, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchList(ctx, operation, pageURL)
+}
+
+// fetchList gets one page of a list operation.
+func (c *GitLabIntegration) fetchList(ctx context.Context, operation string, pageURL string) (*op.Result, error) {
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, "GET", pageURL, c.defaultHeaders(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Transform to simplified format
+	var result []map[string]interface{}
+	switch operation {
+	case "list_merge_requests":
+		result, err = convertList(c, resp, mergeRequestResult)
+	case "list_issues":
+		result, err = convertList(c, resp, issueResult)
+	case "list_notes":
+		result, err = convertList(c, resp, noteResult)
+	case "list_pipelines":
+		result, err = convertList(c, resp, pipelineResult)
+	case "list_releases":
+		result, err = convertList(c, resp, releaseResult)
+	default:
+		return nil, fmt.Errorf("operation %s does not support pagination", operation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ToResult(resp, result), nil
+}
+
+// convertList parses a list response and converts each item.
+func convertList[T any](c *GitLabIntegration, resp *transport.Response, convert func(T) map[string]interface{}) ([]map[string]interface{}, error) {
+	var items []T
+	if err := c.ParseJSONResponse(resp, &items); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		result[i] = convert(item)
+	}
+	return result, nil
+}
+
+// ExecutePaginated implements paginated operations for GitLab integration.
+// Supports list_merge_requests, list_issues, list_notes, list_pipelines and
+// list_releases.
+//
+// Pages are followed through GitLab's Link header, so keyset pagination
+// (pagination: keyset, with order_by and sort) works on the endpoints that
+// support it. Offset pagination falls back to the X-Next-Page header.
+func (c *GitLabIntegration) ExecutePaginated(ctx context.Context, operation string, inputs map[string]interface{}) (<-chan *op.Result, error) {
+	// Check if pagination is enabled
+	paginate, _ := inputs["paginate"].(bool)
+	if !paginate {
+		// If pagination is not enabled, execute normally and return single result
+		result, err := c.Execute(ctx, operation, inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		ch := make(chan *op.Result, 1)
+		ch <- result
+		close(ch)
+		return ch, nil
+	}
+
+	// Validate operation supports pagination
+	if _, _, err := listEndpoint(operation, inputs); err != nil {
+		return nil, err
+	}
+
+	// Get max results limit
+	maxResults := 0
+	if max, ok := inputs["max_results"].(int); ok {
+		maxResults = max
+	}
+
+	// Set page size (default to 100, GitLab's max)
+	pageSize := 100
+	if perPage, ok := inputs["per_page"].(int); ok {
+		pageSize = perPage
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	inputs["per_page"] = pageSize
+
+	pageURL, err := c.listURL(operation, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create results channel
+	resultsChan := make(chan *op.Result)
+
+	// Start pagination in goroutine
+	go func() {
+		defer close(resultsChan)
+
+		// Track total results sent
+		totalSent := 0
+
+		for pageURL != "" {
+			// Check context cancellation
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Execute request
+			result, err := c.fetchList(ctx, operation, pageURL)
+			if err != nil {
+				// Send error in metadata
+				resultsChan <- &op.Result{
+					Metadata: map[string]interface{}{
+						"error": err.Error(),
+					},
+				}
+				return
+			}
+
+			// Send result
+			resultsChan <- result
+
+			// Count results in this page
+			resultsInPage := 0
+			if items, ok := result.Response.([]map[string]interface{}); ok {
+				resultsInPage = len(items)
+			}
+			totalSent += resultsInPage
+
+			// Check if we've reached max results
+			if maxResults > 0 && totalSent >= maxResults {
+				return
+			}
+
+			// Check if this is the last page (fewer results than page size)
+			if resultsInPage < pageSize {
+				return
+			}
+
+			pageURL = nextPageURL(pageURL, result.Headers)
+		}
+	}()
+
+	return resultsChan, nil
+}
+
+// nextPageURL returns the URL of the page after current, or "" on the last
+// page. Only the query of the Link header's next URL is used, so requests
+// stay on the configured base URL even when GitLab reports another host.
+func nextPageURL(current string, headers map[string][]string) string {
+	currentURL, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+
+	for _, link := range headers["Link"] {
+		if match := nextLinkRegex.FindStringSubmatch(link); match != nil {
+			next, err := url.Parse(match[1])
+			if err != nil {
+				return ""
+			}
+			currentURL.RawQuery = next.RawQuery
+			return currentURL.String()
+		}
+	}
+
+	// Offset pagination without a Link header
+	if page := headers["X-Next-Page"]; len(page) > 0 && page[0] != "" {
+		query := currentURL.Query()
+		query.Set("page", page[0])
+		currentURL.RawQuery = query.Encode()
+		return currentURL.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/tombee/conductor/internal/operation"
+)
+
+// createPipeline runs a new pipeline for a branch or tag.
+func (c *GitLabIntegration) createPipeline(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "ref"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project"}
+	url, err := c.buildURL("/projects/{project}/pipeline", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (ref and optional variables)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.pipelineRequest(ctx, "POST", url, body)
+}
+
+// getPipeline gets a single GitLab pipeline.
+func (c *GitLabIntegration) getPipeline(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "pipeline_id"}); err != nil {
+		return nil, err
+	}
+
+	url, err := c.buildURL("/projects/{project}/pipelines/{pipeline_id}", inputs, []string{"project", "pipeline_id"})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.pipelineRequest(ctx, "GET", url, nil)
+}
+
+// retryPipeline retries the failed or cancelled jobs of a pipeline.
+func (c *GitLabIntegration) retryPipeline(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "pipeline_id"}); err != nil {
+		return nil, err
+	}
+
+	url, err := c.buildURL("/projects/{project}/pipelines/{pipeline_id}/retry", inputs, []string{"project", "pipeline_id"})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.pipelineRequest(ctx, "POST", url, nil)
+}
+
+// pipelineRequest sends a request that returns a single pipeline.
+func (c *GitLabIntegration) pipelineRequest(ctx context.Context, method, url string, body []byte) (*operation.Result, error) {
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, method, url, c.defaultHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var pipeline Pipeline
+	if err := c.ParseJSONResponse(resp, &pipeline); err != nil {
+		return nil, err
+	}
+
+	return c.ToResult(resp, pipelineResult(pipeline)), nil
+}
+
+// pipelineResult converts a pipeline to the operation result format.
+func pipelineResult(pipeline Pipeline) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":         pipeline.ID,
+		"status":     pipeline.Status,
+		"source":     pipeline.Source,
+		"ref":        pipeline.Ref,
+		"sha":        pipeline.SHA,
+		"web_url":    pipeline.WebURL,
+		"created_at": pipeline.CreatedAt,
+		"updated_at": pipeline.UpdatedAt,
+	}
+	if pipeline.FinishedAt != nil {
+		result["finished_at"] = pipeline.FinishedAt
+	}
+	if pipeline.Duration != nil {
+		result["duration"] = *pipeline.Duration
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/tombee/conductor/internal/operation"
+)
+
+// createRelease creates a new GitLab release.
+func (c *GitLabIntegration) createRelease(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "tag_name"}); err != nil {
+		return nil, err
+	}
+
+	pathParams := []string{"project"}
+	url, err := c.buildURL("/projects/{project}/releases", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (exclude path parameters)
+	body, err := c.BuildRequestBody(inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, "POST", url, c.defaultHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var release Release
+	if err := c.ParseJSONResponse(resp, &release); err != nil {
+		return nil, err
+	}
+
+	return c.ToResult(resp, releaseResult(release)), nil
+}
+
+// releaseResult converts a release to the operation result format.
+func releaseResult(release Release) map[string]interface{} {
+	return map[string]interface{}{
+		"tag_name":    release.TagName,
+		"name":        release.Name,
+		"description": release.Description,
+		"author":      release.Author.Username,
+		"web_url":     release.Links.Self,
+		"created_at":  release.CreatedAt,
+		"released_at": release.ReleasedAt,
+	}
+}
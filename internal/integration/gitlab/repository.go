@@ -0,0 +1,52 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/tombee/conductor/internal/operation"
+)
+
+// getFile gets file contents from a GitLab repository.
+func (c *GitLabIntegration) getFile(ctx context.Context, inputs map[string]interface{}) (*operation.Result, error) {
+	// Validate required parameters
+	if err := c.ValidateRequired(inputs, []string{"project", "file_path", "ref"}); err != nil {
+		return nil, err
+	}
+
+	// The file path is one URL-encoded path segment
+	pathParams := []string{"project", "file_path"}
+	url, err := c.buildURL("/projects/{project}/repository/files/{file_path}", inputs, pathParams)
+	if err != nil {
+		return nil, err
+	}
+	url += c.BuildQueryString(inputs, pathParams)
+
+	// Execute request
+	resp, err := c.ExecuteRequest(ctx, "GET", url, c.defaultHeaders(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse error if any
+	if err := ParseError(resp); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var file File
+	if err := c.ParseJSONResponse(resp, &file); err != nil {
+		return nil, err
+	}
+
+	// Return operation result
+	return c.ToResult(resp, map[string]interface{}{
+		"name":      file.FileName,
+		"path":      file.FilePath,
+		"size":      file.Size,
+		"content":   file.Content,
+		"encoding":  file.Encoding,
+		"ref":       file.Ref,
+		"blob_id":   file.BlobID,
+		"commit_id": file.CommitID,
+	}), nil
+}
@@ -0,0 +1,105 @@
+package gitlab
+
+import "time"
+
+// MergeRequest represents a GitLab merge request.
+type MergeRequest struct {
+	ID             int64      `json:"id"`
+	IID            int        `json:"iid"`
+	ProjectID      int64      `json:"project_id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	State          string     `json:"state"`
+	WebURL         string     `json:"web_url"`
+	SourceBranch   string     `json:"source_branch"`
+	TargetBranch   string     `json:"target_branch"`
+	Author         User       `json:"author"`
+	Labels         []string   `json:"labels"`
+	Draft          bool       `json:"draft"`
+	MergeStatus    string     `json:"detailed_merge_status"`
+	SHA            string     `json:"sha"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	MergedAt       *time.Time `json:"merged_at,omitempty"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+}
+
+// Issue represents a GitLab issue.
+type Issue struct {
+	ID          int64      `json:"id"`
+	IID         int        `json:"iid"`
+	ProjectID   int64      `json:"project_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	WebURL      string     `json:"web_url"`
+	Author      User       `json:"author"`
+	Assignees   []User     `json:"assignees"`
+	Labels      []string   `json:"labels"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+}
+
+// Note represents a comment on a GitLab merge request or issue.
+type Note struct {
+	ID           int64     `json:"id"`
+	Body         string    `json:"body"`
+	Author       User      `json:"author"`
+	System       bool      `json:"system"`
+	NoteableType string    `json:"noteable_type"`
+	NoteableIID  int       `json:"noteable_iid"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Pipeline represents a GitLab CI/CD pipeline.
+type Pipeline struct {
+	ID         int64      `json:"id"`
+	IID        int        `json:"iid"`
+	ProjectID  int64      `json:"project_id"`
+	Status     string     `json:"status"`
+	Source     string     `json:"source"`
+	Ref        string     `json:"ref"`
+	SHA        string     `json:"sha"`
+	WebURL     string     `json:"web_url"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Duration   *int       `json:"duration,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// File represents a file in a GitLab repository.
+type File struct {
+	FileName     string `json:"file_name"`
+	FilePath     string `json:"file_path"`
+	Size         int    `json:"size"`
+	Encoding     string `json:"encoding"`
+	Content      string `json:"content"`
+	Ref          string `json:"ref"`
+	BlobID       string `json:"blob_id"`
+	CommitID     string `json:"commit_id"`
+	LastCommitID string `json:"last_commit_id"`
+}
+
+// Release represents a GitLab release.
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Author      User      `json:"author"`
+	CreatedAt   time.Time `json:"created_at"`
+	ReleasedAt  time.Time `json:"released_at"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// User represents a GitLab user.
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	WebURL   string `json:"web_url"`
+}
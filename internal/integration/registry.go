@@ -9,6 +9,7 @@ import (
 	"github.com/tombee/conductor/internal/integration/discord"
 	"github.com/tombee/conductor/internal/integration/elasticsearch"
 	"github.com/tombee/conductor/internal/integration/github"
+	"github.com/tombee/conductor/internal/integration/gitlab"
 	"github.com/tombee/conductor/internal/integration/jenkins"
 	"github.com/tombee/conductor/internal/integration/jira"
 	"github.com/tombee/conductor/internal/integration/loki"
@@ -25,6 +26,7 @@ import (
 // These are applied when no explicit base_url is provided.
 var defaultBaseURLs = map[string]string{
 	"github":  "https://api.github.com",
+	"gitlab":  gitlab.DefaultBaseURL,
 	"slack":   "https://slack.com/api",
 	"discord": "https://discord.com/api/v10",
 	"notion":  "https://api.notion.com/v1",
@@ -35,6 +37,7 @@ var defaultBaseURLs = map[string]string{
 // API-specific error handling and pagination support.
 var BuiltinRegistry = map[string]func(config *api.ProviderConfig) (operation.Provider, error){
 	"github":        github.NewGitHubIntegration,
+	"gitlab":        gitlab.NewGitLabIntegration,
 	"slack":         slack.NewSlackIntegration,
 	"jira":          jira.NewJiraIntegration,
 	"discord":       discord.NewDiscordIntegration,
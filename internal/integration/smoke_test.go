@@ -142,6 +142,11 @@ func TestAllIntegrationsValidateMissingRequiredParams(t *testing.T) {
 			"create_pr":    "owner",
 			"get_file":     "owner",
 		},
+		"gitlab": {
+			"create_merge_request": "project",
+			"add_note":             "project",
+			"get_file":             "project",
+		},
 		"slack": {
 			"post_message": "channel",
 			"get_channel":  "channel",
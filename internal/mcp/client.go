@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"reflect"
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
@@ -27,7 +25,8 @@ type Client struct {
 	// timeout is the default timeout for tool calls
 	timeout time.Duration
 
-	// process is the underlying OS process (for force-kill during shutdown)
+	// process is the underlying OS process (for force-kill during shutdown).
+	// Nil for remote transports.
 	process ProcessHandle
 }
 
@@ -36,25 +35,50 @@ type ClientConfig struct {
 	// ServerName is the unique identifier for this server
 	ServerName string
 
-	// Command is the executable to run
+	// Transport selects how to connect: "stdio" (default), "http" or "sse"
+	Transport TransportType
+
+	// Command is the executable to run (stdio transport)
 	Command string
 
-	// Args are the command-line arguments
+	// Args are the command-line arguments (stdio transport)
 	Args []string
 
-	// Env are environment variables to pass to the server
+	// Env are environment variables to pass to the server (stdio transport)
 	Env []string
 
+	// URL is the server endpoint (http and sse transports)
+	URL string
+
+	// Headers are extra HTTP headers sent with every request (http and sse transports)
+	Headers map[string]string
+
+	// Auth configures bearer or OAuth2 authentication (http and sse transports)
+	Auth *AuthConfig
+
 	// Timeout is the default timeout for tool calls (defaults to 30s)
 	Timeout time.Duration
+
+	// Sampling handles completion requests from the server. When nil the
+	// client does not advertise the sampling capability.
+	Sampling SamplingHandler
 }
 
-// NewClient creates a new MCP client and starts the server process.
+// NewClient creates a new MCP client and connects to the server.
+// For stdio servers this starts the server process.
 func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
 	if config.ServerName == "" {
 		return nil, fmt.Errorf("server name is required")
 	}
-	if config.Command == "" {
+	transport := ResolveTransport(config.Transport, config.URL)
+	if err := ValidateTransport(transport); err != nil {
+		return nil, err
+	}
+	if transport.IsRemote() {
+		if err := ValidateURL(config.URL); err != nil {
+			return nil, err
+		}
+	} else if config.Command == "" {
 		return nil, fmt.Errorf("command is required")
 	}
 
@@ -65,13 +89,14 @@ func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
 	}
 
 	// Create the MCP client
-	mcpClient, err := client.NewStdioMCPClient(config.Command, config.Env, config.Args...)
+	mcpClient, process, err := newMCPClient(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MCP client: %w", err)
 	}
 
-	// Start the connection
-	if err := mcpClient.Start(ctx); err != nil {
+	// Start the connection. The SSE transport keeps its event stream bound to
+	// this context, so it must outlive the caller's connect timeout.
+	if err := mcpClient.Start(context.WithoutCancel(ctx)); err != nil {
 		return nil, fmt.Errorf("failed to start MCP client: %w", err)
 	}
 
@@ -79,7 +104,7 @@ func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
 		serverName: config.ServerName,
 		client:     mcpClient,
 		timeout:    timeout,
-		process:    extractProcess(mcpClient),
+		process:    process,
 	}
 
 	// Initialize the server (sends initialize request)
@@ -91,50 +116,6 @@ func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
 	return c, nil
 }
 
-// extractProcess attempts to extract the underlying OS process from the MCP client.
-// Uses reflection to access the stdio transport's process field.
-// Returns nil if extraction fails (non-fatal - we just won't be able to force-kill).
-func extractProcess(mcpClient *client.Client) ProcessHandle {
-	if mcpClient == nil {
-		return nil
-	}
-
-	// Get transport using GetTransport() method
-	transport := mcpClient.GetTransport()
-	if transport == nil {
-		return nil
-	}
-
-	// Use reflection to access the Cmd field from StdioTransport
-	// The transport should be *transport.StdioTransport which has a Cmd *exec.Cmd field
-	transportVal := reflect.ValueOf(transport)
-	if transportVal.Kind() == reflect.Ptr {
-		transportVal = transportVal.Elem()
-	}
-
-	// Look for Cmd field
-	cmdField := transportVal.FieldByName("Cmd")
-	if !cmdField.IsValid() || cmdField.IsNil() {
-		return nil
-	}
-
-	// The Cmd is *exec.Cmd, which has a Process field
-	if cmdField.Kind() == reflect.Ptr {
-		cmdVal := cmdField.Elem()
-		processField := cmdVal.FieldByName("Process")
-		if !processField.IsValid() || processField.IsNil() {
-			return nil
-		}
-
-		// Extract *os.Process
-		if proc, ok := processField.Interface().(*os.Process); ok {
-			return proc
-		}
-	}
-
-	return nil
-}
-
 // initialize sends the initialize request to the MCP server.
 func (c *Client) initialize(ctx context.Context) error {
 	// Send initialize request with client capabilities
@@ -247,39 +228,9 @@ func (c *Client) CallTool(ctx context.Context, req ToolCallRequest) (*ToolCallRe
 	}
 
 	for i, content := range result.Content {
-		item := ContentItem{}
-
-		// Use type assertions to determine content type
-		if textContent, ok := mcp.AsTextContent(content); ok {
-			item.Type = textContent.Type
-			item.Text = textContent.Text
-		} else if imageContent, ok := mcp.AsImageContent(content); ok {
-			item.Type = imageContent.Type
-			item.Data = imageContent.Data
-			item.MimeType = imageContent.MIMEType
-		} else {
-			// Fallback: marshal to JSON to extract fields
-			contentBytes, err := json.Marshal(content)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal content: %w", err)
-			}
-			var contentMap map[string]interface{}
-			if err := json.Unmarshal(contentBytes, &contentMap); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal content: %w", err)
-			}
-
-			if contentType, ok := contentMap["type"].(string); ok {
-				item.Type = contentType
-			}
-			if text, ok := contentMap["text"].(string); ok {
-				item.Text = text
-			}
-			if data, ok := contentMap["data"].(string); ok {
-				item.Data = data
-			}
-			if mimeType, ok := contentMap["mimeType"].(string); ok {
-				item.MimeType = mimeType
-			}
+		item, err := convertContent(content)
+		if err != nil {
+			return nil, err
 		}
 
 		response.Content[i] = item
@@ -351,6 +302,71 @@ func (c *Client) ReadResource(ctx context.Context, req ResourceReadRequest) (*Re
 	return response, nil
 }
 
+// ListPrompts retrieves the list of available prompts from the MCP server.
+func (c *Client) ListPrompts(ctx context.Context) ([]PromptDefinition, error) {
+	if c.capabilities == nil || c.capabilities.Prompts == nil {
+		return nil, fmt.Errorf("server does not support prompts")
+	}
+
+	result, err := c.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	prompts := make([]PromptDefinition, len(result.Prompts))
+	for i, prompt := range result.Prompts {
+		def := PromptDefinition{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+		}
+		for _, arg := range prompt.Arguments {
+			def.Arguments = append(def.Arguments, PromptArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			})
+		}
+		prompts[i] = def
+	}
+
+	return prompts, nil
+}
+
+// GetPrompt renders an MCP prompt with the given arguments.
+func (c *Client) GetPrompt(ctx context.Context, req PromptGetRequest) (*PromptGetResponse, error) {
+	if c.capabilities == nil || c.capabilities.Prompts == nil {
+		return nil, fmt.Errorf("server does not support prompts")
+	}
+
+	result, err := c.client.GetPrompt(ctx, mcp.GetPromptRequest{
+		Params: mcp.GetPromptParams{
+			Name:      req.Name,
+			Arguments: req.Arguments,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	response := &PromptGetResponse{
+		Description: result.Description,
+		Messages:    make([]PromptMessage, len(result.Messages)),
+	}
+
+	for i, msg := range result.Messages {
+		item, err := convertContent(msg.Content)
+		if err != nil {
+			return nil, err
+		}
+		response.Messages[i] = PromptMessage{
+			Role:    string(msg.Role),
+			Content: item,
+		}
+	}
+
+	return response, nil
+}
+
 // Capabilities returns the server's capabilities.
 func (c *Client) Capabilities() *ServerCapabilities {
 	return c.capabilities
@@ -394,3 +410,53 @@ func (c *Client) Ping(ctx context.Context) error {
 
 	return nil
 }
+
+// convertContent converts an MCP content block into a ContentItem.
+func convertContent(content mcp.Content) (ContentItem, error) {
+	item := ContentItem{}
+
+	// Use type assertions to determine content type
+	if textContent, ok := mcp.AsTextContent(content); ok {
+		item.Type = textContent.Type
+		item.Text = textContent.Text
+	} else if embedded, ok := mcp.AsEmbeddedResource(content); ok {
+		// Embedded resources in prompts carry their text inline
+		item.Type = embedded.Type
+		if text, ok := mcp.AsTextResourceContents(embedded.Resource); ok {
+			item.Text = text.Text
+			item.MimeType = text.MIMEType
+		} else if blob, ok := mcp.AsBlobResourceContents(embedded.Resource); ok {
+			item.Data = blob.Blob
+			item.MimeType = blob.MIMEType
+		}
+	} else if imageContent, ok := mcp.AsImageContent(content); ok {
+		item.Type = imageContent.Type
+		item.Data = imageContent.Data
+		item.MimeType = imageContent.MIMEType
+	} else {
+		// Fallback: marshal to JSON to extract fields
+		contentBytes, err := json.Marshal(content)
+		if err != nil {
+			return item, fmt.Errorf("failed to marshal content: %w", err)
+		}
+		var contentMap map[string]interface{}
+		if err := json.Unmarshal(contentBytes, &contentMap); err != nil {
+			return item, fmt.Errorf("failed to unmarshal content: %w", err)
+		}
+
+		if contentType, ok := contentMap["type"].(string); ok {
+			item.Type = contentType
+		}
+		if text, ok := contentMap["text"].(string); ok {
+			item.Text = text
+		}
+		if data, ok := contentMap["data"].(string); ok {
+			item.Data = data
+		}
+		if mimeType, ok := contentMap["mimeType"].(string); ok {
+			item.MimeType = mimeType
+		}
+	}
+
+	return item, nil
+}
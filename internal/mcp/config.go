@@ -58,6 +58,10 @@ type MCPGlobalConfig struct {
 
 // MCPServerEntry represents a single MCP server configuration entry.
 type MCPServerEntry struct {
+	// Transport selects how to connect: "stdio", "http" or "sse".
+	// Defaults to "http" when URL is set, otherwise "stdio".
+	Transport TransportType `yaml:"transport,omitempty"`
+
 	// Command is the executable to run (e.g., "npx", "python").
 	Command string `yaml:"command,omitempty"`
 
@@ -68,10 +72,24 @@ type MCPServerEntry struct {
 	// Supports ${VAR} syntax for runtime variable substitution.
 	Env []string `yaml:"env,omitempty"`
 
+	// URL is the endpoint of a remote server (http and sse transports).
+	URL string `yaml:"url,omitempty"`
+
+	// Headers are extra HTTP headers sent to a remote server.
+	// Values support ${VAR} syntax for runtime variable substitution.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Auth configures bearer or OAuth2 authentication for a remote server.
+	Auth *AuthConfig `yaml:"auth,omitempty"`
+
 	// Timeout is the default timeout for tool calls in seconds.
 	// Defaults to 30 seconds if not specified.
 	Timeout int `yaml:"timeout,omitempty"`
 
+	// HealthCheckInterval is how often to ping the server in seconds.
+	// Defaults to 30 for remote servers; 0 disables checks for stdio servers.
+	HealthCheckInterval int `yaml:"health_check_interval,omitempty"`
+
 	// AutoStart indicates whether to start this server when the controller starts.
 	AutoStart bool `yaml:"auto_start,omitempty"`
 
@@ -230,6 +248,18 @@ func (c *MCPGlobalConfig) Validate() error {
 
 // Validate validates a single server entry.
 func (e *MCPServerEntry) Validate() error {
+	if err := ValidateTransport(e.Transport); err != nil {
+		return err
+	}
+
+	if ResolveTransport(e.Transport, e.URL).IsRemote() {
+		return e.validateRemote()
+	}
+
+	if e.URL != "" {
+		return fmt.Errorf("url is not supported with stdio transport")
+	}
+
 	if e.Command == "" && e.Source == "" {
 		return fmt.Errorf("either command or source is required")
 	}
@@ -274,18 +304,50 @@ func (e *MCPServerEntry) Validate() error {
 	return nil
 }
 
+// validateRemote validates an entry for the http and sse transports.
+func (e *MCPServerEntry) validateRemote() error {
+	if e.Command != "" || len(e.Args) > 0 {
+		return fmt.Errorf("command and args are not supported with %s transport", ResolveTransport(e.Transport, e.URL))
+	}
+	if err := ValidateURL(e.URL); err != nil {
+		return err
+	}
+	for key := range e.Headers {
+		if key == "" || strings.ContainsAny(key, " :\r\n") {
+			return fmt.Errorf("invalid header name: %q", key)
+		}
+	}
+	if e.Auth != nil {
+		if err := e.Auth.Validate(); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if e.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative")
+	}
+	if e.HealthCheckInterval < 0 {
+		return fmt.Errorf("health_check_interval must be non-negative")
+	}
+	return nil
+}
+
 // ToServerConfig converts an MCPServerEntry to a ServerConfig for the manager.
 func (e *MCPServerEntry) ToServerConfig(name string) ServerConfig {
 	return ServerConfig{
-		Name:               name,
-		Command:            e.Command,
-		Args:               e.Args,
-		Env:                e.Env,
-		Timeout:            time.Duration(e.Timeout) * time.Second,
-		RestartPolicy:      string(e.RestartPolicy),
-		MaxRestartAttempts: e.MaxRestartAttempts,
-		Source:             e.Source,
-		Version:            e.Version,
+		Name:                name,
+		Transport:           e.Transport,
+		Command:             e.Command,
+		Args:                e.Args,
+		Env:                 e.Env,
+		URL:                 e.URL,
+		Headers:             e.Headers,
+		Auth:                e.Auth,
+		Timeout:             time.Duration(e.Timeout) * time.Second,
+		HealthCheckInterval: time.Duration(e.HealthCheckInterval) * time.Second,
+		RestartPolicy:       string(e.RestartPolicy),
+		MaxRestartAttempts:  e.MaxRestartAttempts,
+		Source:              e.Source,
+		Version:             e.Version,
 	}
 }
 
@@ -422,3 +484,27 @@ func RedactEnv(envs []string) []string {
 	}
 	return result
 }
+
+// sensitiveHeaderNames are HTTP headers whose values are always redacted.
+var sensitiveHeaderNames = map[string]bool{
+	"AUTHORIZATION":       true,
+	"PROXY-AUTHORIZATION": true,
+	"COOKIE":              true,
+}
+
+// RedactHeaders redacts sensitive values from a map of HTTP headers.
+func RedactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(headers))
+	for key, value := range headers {
+		upperKey := strings.ToUpper(key)
+		if sensitiveHeaderNames[upperKey] || IsSensitiveEnvKey(strings.ReplaceAll(upperKey, "-", "_")) {
+			result[key] = "***REDACTED***"
+		} else {
+			result[key] = value
+		}
+	}
+	return result
+}
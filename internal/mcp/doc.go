@@ -24,7 +24,7 @@ This package handles server lifecycle, client communication, and tool adaptation
 The MCP implementation consists of several components:
 
   - Manager: Lifecycle management for MCP servers (start, stop, restart)
-  - Client: Communication with MCP server processes via stdio
+  - Client: Communication with MCP servers via stdio, Streamable HTTP or SSE
   - Registry: Global registration and discovery of MCP servers
   - Tool Adapter: Converts MCP tools to Conductor's tool interface
 
@@ -41,10 +41,19 @@ Start an MCP server:
 	    Env:     []string{"HOME=/home/user"},
 	})
 
+Remote servers are reached over HTTP instead of spawned:
+
+	err := mgr.Start(mcp.ServerConfig{
+	    Name:    "docs",
+	    URL:     "https://mcp.example.com/mcp",
+	    Headers: map[string]string{"X-Tenant": "acme"},
+	    Auth:    &mcp.AuthConfig{Type: mcp.AuthBearer, Token: "${DOCS_TOKEN}"},
+	})
+
 The manager handles:
 
   - Process spawning and monitoring
-  - Health checking via ping
+  - Health checking via ping (periodic for remote servers, with reconnect)
   - Automatic restart with exponential backoff
   - Graceful shutdown
 
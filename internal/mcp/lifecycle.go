@@ -98,36 +98,69 @@ func (m *Manager) monitorServer(state *serverState) {
 		// Emit started event
 		m.eventEmitter.EmitStarted(serverName)
 
-		// Monitor for restart or stop signals
-		select {
-		case <-state.restartCh:
+		// Monitor for restart or stop signals, pinging the server periodically
+		// so dropped connections to remote servers are re-established.
+		switch m.waitForServerEvent(state) {
+		case serverEventRestart:
 			m.logger.Info("restarting mcp server", "server", serverName)
 			state.mu.Lock()
 			state.state = ServerStateRestarting
 			restartAttempt := state.restartCount + 1
-			if state.client != nil {
-				if err := state.client.Close(); err != nil {
-					m.logger.Warn("failed to close MCP client during restart", "server", serverName, "error", err)
-				}
-				state.client = nil
-				state.process = nil
-			}
-			// Reset tool count on restart - will be re-queried on next successful connection
-			state.toolCount = nil
+			m.closeServerClient(state)
 			state.mu.Unlock()
 
 			// Emit restarting event
 			m.eventEmitter.EmitRestarting(serverName, restartAttempt)
 			continue
 
-		case <-state.stopCh:
+		case serverEventUnhealthy:
+			state.mu.Lock()
+			state.state = ServerStateRestarting
+			state.failureCount++
+			state.lastFailure = time.Now()
+			state.restartCount++
+			currentRestartCount := state.restartCount
+			restartPolicy := state.config.RestartPolicy
+			maxAttempts := state.config.MaxRestartAttempts
+			m.closeServerClient(state)
+			state.mu.Unlock()
+
+			if !m.shouldRestart(restartPolicy, maxAttempts, currentRestartCount) {
+				state.mu.Lock()
+				state.state = ServerStateError
+				state.mu.Unlock()
+				m.logger.Info("restart policy prevents reconnect",
+					"server", serverName,
+					"policy", restartPolicy,
+					"restart_count", currentRestartCount,
+				)
+				return
+			}
+
+			m.eventEmitter.EmitRestarting(serverName, currentRestartCount)
+
+			backoff := m.calculateBackoff(state)
+			m.logger.Info("mcp server will reconnect after backoff",
+				"server", serverName,
+				"backoff", backoff,
+			)
+			select {
+			case <-time.After(backoff):
+				continue
+			case <-state.stopCh:
+				return
+			case <-m.ctx.Done():
+				return
+			}
+
+		case serverEventStop:
 			m.logger.Info("stopping mcp server monitor", "server", serverName)
 			state.mu.Lock()
 			state.state = ServerStateStopped
 			state.mu.Unlock()
 			return
 
-		case <-m.ctx.Done():
+		case serverEventShutdown:
 			m.logger.Info("manager shutting down, stopping mcp server", "server", serverName)
 			state.mu.Lock()
 			state.state = ServerStateStopped
@@ -137,6 +170,94 @@ func (m *Manager) monitorServer(state *serverState) {
 	}
 }
 
+// serverEvent is a signal observed while a server is running.
+type serverEvent int
+
+const (
+	serverEventRestart serverEvent = iota
+	serverEventUnhealthy
+	serverEventStop
+	serverEventShutdown
+)
+
+// defaultRemoteHealthCheckInterval is the ping interval for remote servers
+// when none is configured.
+const defaultRemoteHealthCheckInterval = 30 * time.Second
+
+// healthCheckInterval returns how often a server should be pinged while running.
+func healthCheckInterval(config ServerConfig) time.Duration {
+	if config.HealthCheckInterval > 0 {
+		return config.HealthCheckInterval
+	}
+	if ResolveTransport(config.Transport, config.URL).IsRemote() {
+		return defaultRemoteHealthCheckInterval
+	}
+	return 0
+}
+
+// waitForServerEvent blocks until the running server must be restarted, stopped,
+// or has failed a health check.
+func (m *Manager) waitForServerEvent(state *serverState) serverEvent {
+	var healthC <-chan time.Time
+	if interval := healthCheckInterval(state.config); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		healthC = ticker.C
+	}
+
+	for {
+		select {
+		case <-state.restartCh:
+			return serverEventRestart
+		case <-state.stopCh:
+			return serverEventStop
+		case <-m.ctx.Done():
+			return serverEventShutdown
+		case <-healthC:
+			if err := m.pingServer(state); err != nil {
+				m.logger.Warn("mcp server health check failed",
+					"server", state.config.Name,
+					"error", err,
+				)
+				state.mu.Lock()
+				state.lastError = err.Error()
+				state.mu.Unlock()
+				m.eventEmitter.EmitFailed(state.config.Name, err)
+				return serverEventUnhealthy
+			}
+		}
+	}
+}
+
+// pingServer sends a ping to the server's current client.
+func (m *Manager) pingServer(state *serverState) error {
+	state.mu.RLock()
+	client := state.client
+	state.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("server connection closed")
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
+	defer cancel()
+	return client.Ping(ctx)
+}
+
+// closeServerClient closes the active client and clears per-connection state.
+// The caller must hold state.mu.
+func (m *Manager) closeServerClient(state *serverState) {
+	if state.client != nil {
+		if err := state.client.Close(); err != nil {
+			m.logger.Warn("failed to close MCP client", "server", state.config.Name, "error", err)
+		}
+		state.client = nil
+		state.process = nil
+	}
+	// Reset tool count - will be re-queried on next successful connection
+	state.toolCount = nil
+}
+
 // startServerClient starts the MCP client for a server.
 func (m *Manager) startServerClient(state *serverState) error {
 	state.mu.Lock()
@@ -145,10 +266,15 @@ func (m *Manager) startServerClient(state *serverState) error {
 	// Create client config
 	clientConfig := ClientConfig{
 		ServerName: state.config.Name,
+		Transport:  state.config.Transport,
 		Command:    state.config.Command,
 		Args:       state.config.Args,
 		Env:        state.config.Env,
+		URL:        state.config.URL,
+		Headers:    state.config.Headers,
+		Auth:       state.config.Auth,
 		Timeout:    state.config.Timeout,
+		Sampling:   state.config.Sampling,
 	}
 
 	// Start client with timeout
@@ -0,0 +1,257 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// LockfileName is the name of the lockfile kept next to workflow files.
+	LockfileName = "mcp.lock"
+
+	// LockfileVersion is the current version of the lockfile format.
+	LockfileVersion = 1
+)
+
+// Lockfile records the tools each MCP server offered when it was pinned.
+// Servers are keyed by name, so workflows in the same directory that use a
+// server name share its entry.
+type Lockfile struct {
+	// Version is the lockfile format version.
+	Version int `yaml:"version"`
+
+	// Servers contains the pinned tools of each server.
+	Servers map[string]*ServerLock `yaml:"servers"`
+}
+
+// ServerLock is the pinned tool list of a single server.
+type ServerLock struct {
+	// Source identifies where the server runs: its command line or URL.
+	Source string `yaml:"source"`
+
+	// Fingerprint is a hash over all of the server's tools.
+	Fingerprint string `yaml:"fingerprint"`
+
+	// Tools contains the fingerprint of each tool, sorted by name.
+	Tools []ToolFingerprint `yaml:"tools"`
+}
+
+// ToolFingerprint pins a single tool.
+type ToolFingerprint struct {
+	// Name is the tool name.
+	Name string `yaml:"name"`
+
+	// Description is the tool description as offered by the server.
+	Description string `yaml:"description"`
+
+	// Schema is a hash of the tool's input schema.
+	Schema string `yaml:"schema"`
+}
+
+// ToolDrift describes a difference between a server's tools and its lockfile entry.
+type ToolDrift struct {
+	// Server is the server name.
+	Server string
+
+	// Tool is the tool name, empty for server-level changes.
+	Tool string
+
+	// Change describes what changed.
+	Change string
+}
+
+// String returns a human-readable description of the drift.
+func (d ToolDrift) String() string {
+	if d.Tool == "" {
+		return fmt.Sprintf("%s: %s", d.Server, d.Change)
+	}
+	return fmt.Sprintf("%s.%s: %s", d.Server, d.Tool, d.Change)
+}
+
+// LockfilePath returns the lockfile path for workflows in a directory.
+func LockfilePath(workflowDir string) string {
+	return filepath.Join(workflowDir, LockfileName)
+}
+
+// NewLockfile creates an empty lockfile.
+func NewLockfile() *Lockfile {
+	return &Lockfile{
+		Version: LockfileVersion,
+		Servers: make(map[string]*ServerLock),
+	}
+}
+
+// LoadLockfile reads a lockfile. The returned error wraps os.ErrNotExist
+// if the file does not exist.
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	lock, err := ParseLockfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return lock, nil
+}
+
+// ParseLockfile parses lockfile contents, such as those sent with a run.
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	var lock Lockfile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+	if lock.Version != LockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d", lock.Version)
+	}
+	if lock.Servers == nil {
+		lock.Servers = make(map[string]*ServerLock)
+	}
+	return &lock, nil
+}
+
+// Save writes the lockfile.
+func (l *Lockfile) Save(path string) error {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by conductor mcp lock. Do not edit.\n")
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// ServerSource identifies where a server runs. Environment variables and
+// headers are left out as they commonly hold credentials.
+func ServerSource(config ServerConfig) string {
+	if ResolveTransport(config.Transport, config.URL).IsRemote() {
+		return config.URL
+	}
+	return strings.TrimSpace(config.Command + " " + strings.Join(config.Args, " "))
+}
+
+// NewServerLock fingerprints a server's tools.
+func NewServerLock(source string, tools []ToolDefinition) (*ServerLock, error) {
+	lock := &ServerLock{Source: source}
+	for _, tool := range tools {
+		schema, err := schemaHash(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		lock.Tools = append(lock.Tools, ToolFingerprint{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Schema:      schema,
+		})
+	}
+	sort.Slice(lock.Tools, func(i, j int) bool {
+		return lock.Tools[i].Name < lock.Tools[j].Name
+	})
+
+	h := sha256.New()
+	for _, tool := range lock.Tools {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", tool.Name, tool.Description, tool.Schema)
+	}
+	lock.Fingerprint = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	return lock, nil
+}
+
+// schemaHash hashes a JSON schema independently of key order and whitespace.
+func schemaHash(schema json.RawMessage) (string, error) {
+	canonical := []byte("null")
+	if len(bytes.TrimSpace(schema)) > 0 {
+		var v any
+		if err := json.Unmarshal(schema, &v); err != nil {
+			return "", fmt.Errorf("invalid input schema: %w", err)
+		}
+		// Map keys are marshaled in sorted order
+		var err error
+		canonical, err = json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("invalid input schema: %w", err)
+		}
+	}
+	sum := sha256.Sum256(canonical)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Compare lists the differences between a server's current tools and its
+// lockfile entry. A server missing from the lockfile is reported as drift.
+func (l *Lockfile) Compare(serverName string, current *ServerLock) []ToolDrift {
+	locked, ok := l.Servers[serverName]
+	if !ok {
+		return []ToolDrift{{Server: serverName, Change: "server not in lockfile"}}
+	}
+	if locked.Fingerprint == current.Fingerprint && locked.Source == current.Source {
+		return nil
+	}
+
+	var drift []ToolDrift
+	if locked.Source != current.Source {
+		drift = append(drift, ToolDrift{
+			Server: serverName,
+			Change: fmt.Sprintf("source changed from %q to %q", locked.Source, current.Source),
+		})
+	}
+
+	lockedTools := make(map[string]ToolFingerprint, len(locked.Tools))
+	for _, tool := range locked.Tools {
+		lockedTools[tool.Name] = tool
+	}
+	for _, tool := range current.Tools {
+		prev, ok := lockedTools[tool.Name]
+		delete(lockedTools, tool.Name)
+		switch {
+		case !ok:
+			drift = append(drift, ToolDrift{Server: serverName, Tool: tool.Name, Change: "tool added"})
+		case prev.Description != tool.Description:
+			drift = append(drift, ToolDrift{Server: serverName, Tool: tool.Name, Change: "description changed"})
+		case prev.Schema != tool.Schema:
+			drift = append(drift, ToolDrift{Server: serverName, Tool: tool.Name, Change: "input schema changed"})
+		}
+	}
+
+	removed := make([]string, 0, len(lockedTools))
+	for name := range lockedTools {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		drift = append(drift, ToolDrift{Server: serverName, Tool: name, Change: "tool removed"})
+	}
+
+	return drift
+}
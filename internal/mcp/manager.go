@@ -24,6 +24,8 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/tombee/conductor/pkg/workflow"
 )
 
 // ProcessHandle represents the underlying process for an MCP server.
@@ -37,18 +39,38 @@ type ServerConfig struct {
 	// Name is the unique identifier for this server
 	Name string
 
-	// Command is the executable to run
+	// Transport selects how to connect: "stdio" (default), "http" or "sse"
+	Transport TransportType
+
+	// Command is the executable to run (stdio transport)
 	Command string
 
-	// Args are the command-line arguments
+	// Args are the command-line arguments (stdio transport)
 	Args []string
 
-	// Env are environment variables to pass to the server
+	// Env are environment variables to pass to the server (stdio transport)
 	Env []string
 
+	// URL is the server endpoint (http and sse transports)
+	URL string
+
+	// Headers are extra HTTP headers sent with every request (http and sse transports)
+	Headers map[string]string
+
+	// Auth configures bearer or OAuth2 authentication (http and sse transports)
+	Auth *AuthConfig
+
 	// Timeout is the default timeout for tool calls (defaults to 30s)
 	Timeout time.Duration
 
+	// Sampling handles completion requests from the server (nil disables sampling)
+	Sampling SamplingHandler
+
+	// HealthCheckInterval is how often a running server is pinged.
+	// A failed ping closes the connection and reconnects with backoff.
+	// Defaults to 30s for remote transports; 0 disables checks for stdio servers.
+	HealthCheckInterval time.Duration
+
 	// RestartPolicy controls when the server should be restarted
 	// Options: "always", "on-failure", "never" (default: "always")
 	RestartPolicy string
@@ -65,6 +87,33 @@ type ServerConfig struct {
 	Version string
 }
 
+// WorkflowServerConfig converts an MCP server defined in a workflow.
+func WorkflowServerConfig(def workflow.MCPServerConfig) ServerConfig {
+	config := ServerConfig{
+		Name:      def.Name,
+		Transport: TransportType(def.Transport),
+		Command:   def.Command,
+		Args:      def.Args,
+		Env:       def.Env,
+		URL:       def.URL,
+		Headers:   def.Headers,
+		Timeout:   time.Duration(def.Timeout) * time.Second,
+	}
+	if def.Auth != nil {
+		config.Auth = &AuthConfig{
+			Type:         AuthType(def.Auth.Type),
+			Token:        def.Auth.Token,
+			Flow:         def.Auth.Flow,
+			ClientID:     def.Auth.ClientID,
+			ClientSecret: def.Auth.ClientSecret,
+			TokenURL:     def.Auth.TokenURL,
+			RefreshToken: def.Auth.RefreshToken,
+			Scopes:       def.Auth.Scopes,
+		}
+	}
+	return config
+}
+
 // ServerState represents the lifecycle state of an MCP server.
 type ServerState string
 
@@ -219,7 +268,15 @@ func (m *Manager) Start(config ServerConfig) error {
 	if config.Name == "" {
 		return fmt.Errorf("server name is required")
 	}
-	if config.Command == "" {
+	transport := ResolveTransport(config.Transport, config.URL)
+	if err := ValidateTransport(transport); err != nil {
+		return err
+	}
+	if transport.IsRemote() {
+		if err := ValidateURL(config.URL); err != nil {
+			return err
+		}
+	} else if config.Command == "" {
 		return fmt.Errorf("command is required")
 	}
 
@@ -244,10 +301,18 @@ func (m *Manager) Start(config ServerConfig) error {
 	m.wg.Add(1)
 	go m.monitorServer(state)
 
-	m.logger.Info("mcp server started",
-		"server", config.Name,
-		"command", config.Command,
-	)
+	if transport.IsRemote() {
+		m.logger.Info("mcp server started",
+			"server", config.Name,
+			"transport", transport,
+			"url", config.URL,
+		)
+	} else {
+		m.logger.Info("mcp server started",
+			"server", config.Name,
+			"command", config.Command,
+		)
+	}
 
 	return nil
 }
@@ -391,6 +456,107 @@ func (m *Manager) GetClient(name string) (ClientProvider, error) {
 	return client, nil
 }
 
+// managedClient is a ClientProvider that resolves the server's current client
+// on every call, so callers keep working after the manager reconnects.
+type managedClient struct {
+	manager MCPManagerProvider
+	name    string
+}
+
+// NewManagedClient returns a ClientProvider for the named server that always
+// routes through the manager's current connection. Use it for long-lived
+// references such as registered tools, which would otherwise hold on to a
+// client that was closed by a restart or reconnect.
+func NewManagedClient(manager MCPManagerProvider, name string) ClientProvider {
+	return &managedClient{manager: manager, name: name}
+}
+
+func (c *managedClient) current() (ClientProvider, error) {
+	return c.manager.GetClient(c.name)
+}
+
+// ListTools retrieves the list of available tools from the MCP server.
+func (c *managedClient) ListTools(ctx context.Context) ([]ToolDefinition, error) {
+	client, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListTools(ctx)
+}
+
+// CallTool executes an MCP tool with the given arguments.
+func (c *managedClient) CallTool(ctx context.Context, req ToolCallRequest) (*ToolCallResponse, error) {
+	client, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return client.CallTool(ctx, req)
+}
+
+// ListResources retrieves the list of available resources from the MCP server.
+func (c *managedClient) ListResources(ctx context.Context) ([]ResourceDefinition, error) {
+	client, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListResources(ctx)
+}
+
+// ReadResource reads the content of an MCP resource.
+func (c *managedClient) ReadResource(ctx context.Context, req ResourceReadRequest) (*ResourceReadResponse, error) {
+	client, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return client.ReadResource(ctx, req)
+}
+
+// ListPrompts retrieves the list of available prompts from the MCP server.
+func (c *managedClient) ListPrompts(ctx context.Context) ([]PromptDefinition, error) {
+	client, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListPrompts(ctx)
+}
+
+// GetPrompt renders an MCP prompt with the given arguments.
+func (c *managedClient) GetPrompt(ctx context.Context, req PromptGetRequest) (*PromptGetResponse, error) {
+	client, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetPrompt(ctx, req)
+}
+
+// Close is a no-op; the manager owns the underlying connection.
+func (c *managedClient) Close() error {
+	return nil
+}
+
+// Ping checks if the server is still responsive.
+func (c *managedClient) Ping(ctx context.Context) error {
+	client, err := c.current()
+	if err != nil {
+		return err
+	}
+	return client.Ping(ctx)
+}
+
+// ServerName returns the unique identifier for this server.
+func (c *managedClient) ServerName() string {
+	return c.name
+}
+
+// Capabilities returns the server's capabilities, or nil if it is not connected.
+func (c *managedClient) Capabilities() *ServerCapabilities {
+	client, err := c.current()
+	if err != nil {
+		return nil
+	}
+	return client.Capabilities()
+}
+
 // Restart triggers a restart of the specified server.
 func (m *Manager) Restart(name string) error {
 	m.mu.RLock()
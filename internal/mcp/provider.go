@@ -44,6 +44,18 @@ type ClientProvider interface {
 	// CallTool executes an MCP tool with the given arguments.
 	CallTool(ctx context.Context, req ToolCallRequest) (*ToolCallResponse, error)
 
+	// ListResources retrieves the list of available resources from the MCP server.
+	ListResources(ctx context.Context) ([]ResourceDefinition, error)
+
+	// ReadResource reads the content of an MCP resource.
+	ReadResource(ctx context.Context, req ResourceReadRequest) (*ResourceReadResponse, error)
+
+	// ListPrompts retrieves the list of available prompts from the MCP server.
+	ListPrompts(ctx context.Context) ([]PromptDefinition, error)
+
+	// GetPrompt renders an MCP prompt with the given arguments.
+	GetPrompt(ctx context.Context, req PromptGetRequest) (*PromptGetResponse, error)
+
 	// Close closes the connection to the MCP server.
 	Close() error
 
@@ -367,7 +367,7 @@ func (r *Registry) GetServerStatus(name string) (*ServerStatus, error) {
 				Name:    name,
 				State:   ServerStateStopped,
 				Running: false,
-				Config:  &ServerConfig{Name: name, Command: entry.Command, Args: entry.Args, Env: entry.Env},
+				Config:  entryServerConfig(name, entry),
 			}, nil
 		}
 		return nil, ErrServerNotFound(name)
@@ -395,7 +395,7 @@ func (r *Registry) ListAllServers() []*ServerStatus {
 				Name:    name,
 				State:   ServerStateStopped,
 				Running: false,
-				Config:  &ServerConfig{Name: name, Command: entry.Command, Args: entry.Args, Env: entry.Env},
+				Config:  entryServerConfig(name, entry),
 			})
 		}
 	}
@@ -493,3 +493,9 @@ func (r *Registry) GetSummary() ServerSummary {
 
 	return summary
 }
+
+// entryServerConfig returns the server configuration reported for a stopped global server.
+func entryServerConfig(name string, entry *MCPServerEntry) *ServerConfig {
+	cfg := entry.ToServerConfig(name)
+	return &cfg
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ClientSource looks up MCP clients by server name.
+// Both Manager and Registry implement it.
+type ClientSource interface {
+	GetClient(name string) (ClientProvider, error)
+}
+
+// Resolver reads resources and renders prompts from MCP servers on behalf of
+// workflow steps. Servers are looked up in each source in order, so
+// workflow-scoped servers shadow globally registered ones.
+type Resolver struct {
+	sources []ClientSource
+}
+
+// NewResolver creates a resolver that looks up servers in the given sources.
+// Nil sources are ignored.
+func NewResolver(sources ...ClientSource) *Resolver {
+	r := &Resolver{}
+	for _, s := range sources {
+		if s != nil {
+			r.sources = append(r.sources, s)
+		}
+	}
+	return r
+}
+
+// client returns the client for the named server from the first source that has it.
+func (r *Resolver) client(server string) (ClientProvider, error) {
+	err := fmt.Errorf("server not found: %s", server)
+	for _, source := range r.sources {
+		client, getErr := source.GetClient(server)
+		if getErr == nil {
+			return client, nil
+		}
+		err = getErr
+	}
+	return nil, err
+}
+
+// ReadResource reads a resource from the named server and returns its text content.
+// The uri may be the server's own resource URI (e.g. file:///docs/setup.md) or a
+// path or name that identifies a single listed resource (e.g. docs/setup.md).
+func (r *Resolver) ReadResource(ctx context.Context, server, uri string) (string, error) {
+	client, err := r.client(server)
+	if err != nil {
+		return "", err
+	}
+
+	resourceURI, err := lookupResourceURI(ctx, client, uri)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.ReadResource(ctx, ResourceReadRequest{URI: resourceURI})
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(resp.Contents))
+	for _, content := range resp.Contents {
+		if content.Blob == "" {
+			parts = append(parts, content.Text)
+			continue
+		}
+		if !isTextMimeType(content.MimeType) {
+			return "", fmt.Errorf("resource %s has binary content (%s)", resourceURI, content.MimeType)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(content.Blob)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode resource %s: %w", resourceURI, err)
+		}
+		parts = append(parts, string(decoded))
+	}
+
+	return strings.Join(parts, "\n"), nil
+}
+
+// GetPrompt renders a prompt from the named server and returns its text.
+func (r *Resolver) GetPrompt(ctx context.Context, server, name string, args map[string]string) (string, error) {
+	client, err := r.client(server)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.GetPrompt(ctx, PromptGetRequest{Name: name, Arguments: args})
+	if err != nil {
+		return "", err
+	}
+
+	text := resp.Text()
+	if text == "" {
+		return "", fmt.Errorf("prompt %s returned no text content", name)
+	}
+	return text, nil
+}
+
+// lookupResourceURI maps a workflow resource path to the server's resource URI.
+// Full URIs are used as-is. Otherwise the server's resource list is searched for
+// an exact URI or name match, then for a unique URI ending in the path; if nothing
+// matches, the path is passed through for servers that use resource templates.
+func lookupResourceURI(ctx context.Context, client ClientProvider, path string) (string, error) {
+	if strings.Contains(path, "://") {
+		return path, nil
+	}
+
+	resources, err := client.ListResources(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var suffixMatches []string
+	for _, res := range resources {
+		if res.URI == path || res.Name == path {
+			return res.URI, nil
+		}
+		if strings.HasSuffix(res.URI, "/"+path) {
+			suffixMatches = append(suffixMatches, res.URI)
+		}
+	}
+
+	switch len(suffixMatches) {
+	case 0:
+		return path, nil
+	case 1:
+		return suffixMatches[0], nil
+	default:
+		return "", fmt.Errorf("resource %q is ambiguous: matches %s", path, strings.Join(suffixMatches, ", "))
+	}
+}
+
+// isTextMimeType reports whether a MIME type holds text that can be inlined into a prompt.
+func isTextMimeType(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch strings.TrimSpace(strings.Split(mimeType, ";")[0]) {
+	case "application/json", "application/xml", "application/yaml", "application/x-yaml", "application/javascript":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newResourceTestServer returns an MCP server exposing two resources and a prompt.
+func newResourceTestServer() *server.MCPServer {
+	s := server.NewMCPServer("docs", "1.0.0",
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+	)
+
+	for uri, text := range map[string]string{
+		"file:///docs/guides/setup.md": "Run make.",
+		"file:///docs/guides/style.md": "Use tabs.",
+	} {
+		s.AddResource(mcp.NewResource(uri, uri[strings.LastIndex(uri, "/")+1:], mcp.WithMIMEType("text/markdown")),
+			func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return []mcp.ResourceContents{
+					mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "text/markdown", Text: text},
+				}, nil
+			})
+	}
+
+	s.AddPrompt(mcp.NewPrompt("reviewer",
+		mcp.WithPromptDescription("Code review instructions"),
+		mcp.WithArgument("language", mcp.RequiredArgument()),
+	), func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("Reviewer", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(fmt.Sprintf("You review %s code.", req.Params.Arguments["language"]))),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Be concise.")),
+		}), nil
+	})
+
+	return s
+}
+
+// staticSource is a ClientSource backed by a fixed map.
+type staticSource map[string]ClientProvider
+
+func (s staticSource) GetClient(name string) (ClientProvider, error) {
+	if c, ok := s[name]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("server not found: %s", name)
+}
+
+func newResourceTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	httpServer := server.NewTestStreamableHTTPServer(newResourceTestServer())
+	t.Cleanup(httpServer.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, ClientConfig{ServerName: "docs", URL: httpServer.URL + "/mcp"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClient_Prompts(t *testing.T) {
+	client := newResourceTestClient(t)
+	ctx := context.Background()
+
+	prompts, err := client.ListPrompts(ctx)
+	if err != nil {
+		t.Fatalf("ListPrompts() error = %v", err)
+	}
+	if len(prompts) != 1 || prompts[0].Name != "reviewer" {
+		t.Fatalf("ListPrompts() = %+v, want reviewer prompt", prompts)
+	}
+	if len(prompts[0].Arguments) != 1 || !prompts[0].Arguments[0].Required {
+		t.Errorf("Arguments = %+v, want one required argument", prompts[0].Arguments)
+	}
+
+	resp, err := client.GetPrompt(ctx, PromptGetRequest{Name: "reviewer", Arguments: map[string]string{"language": "Go"}})
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if len(resp.Messages) != 2 || resp.Messages[0].Role != "user" {
+		t.Fatalf("Messages = %+v, want two user messages", resp.Messages)
+	}
+	if got := resp.Text(); got != "You review Go code.\n\nBe concise." {
+		t.Errorf("Text() = %q", got)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	client := newResourceTestClient(t)
+	resolver := NewResolver(nil, staticSource{}, staticSource{"docs": client})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		uri  string
+		want string
+	}{
+		{"full uri", "file:///docs/guides/setup.md", "Run make."},
+		{"path suffix", "guides/style.md", "Use tabs."},
+		{"resource name", "setup.md", "Run make."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.ReadResource(ctx, "docs", tt.uri)
+			if err != nil {
+				t.Fatalf("ReadResource() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadResource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	text, err := resolver.GetPrompt(ctx, "docs", "reviewer", map[string]string{"language": "Rust"})
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if !strings.HasPrefix(text, "You review Rust code.") {
+		t.Errorf("GetPrompt() = %q", text)
+	}
+
+	if _, err := resolver.ReadResource(ctx, "missing", "a"); err == nil || !strings.Contains(err.Error(), "server not found") {
+		t.Errorf("ReadResource() on unknown server error = %v, want server not found", err)
+	}
+}
+
+func TestIsTextMimeType(t *testing.T) {
+	for mime, want := range map[string]bool{
+		"text/plain":                      true,
+		"application/json; charset=utf-8": true,
+		"image/png":                       false,
+		"":                                false,
+	} {
+		if got := isTextMimeType(mime); got != want {
+			t.Errorf("isTextMimeType(%q) = %v, want %v", mime, got, want)
+		}
+	}
+}
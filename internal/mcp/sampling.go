@@ -0,0 +1,163 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SamplingHandler completes LLM requests made by an MCP server
+// (sampling/createMessage).
+type SamplingHandler interface {
+	CreateMessage(ctx context.Context, req SamplingRequest) (*SamplingResult, error)
+}
+
+// SamplingRequest is a completion requested by an MCP server.
+type SamplingRequest struct {
+	// ServerName is the server that made the request.
+	ServerName string
+
+	// Messages is the conversation to complete.
+	Messages []SamplingMessage
+
+	// SystemPrompt is an optional system prompt.
+	SystemPrompt string
+
+	// MaxTokens is the maximum number of tokens to generate.
+	MaxTokens int
+
+	// Temperature is the requested sampling temperature (0 = provider default).
+	Temperature float64
+
+	// StopSequences halt generation when encountered.
+	StopSequences []string
+
+	// ModelHints are model name hints in order of preference.
+	ModelHints []string
+
+	// CostPriority, SpeedPriority and IntelligencePriority weight model
+	// selection, each from 0 to 1.
+	CostPriority         float64
+	SpeedPriority        float64
+	IntelligencePriority float64
+}
+
+// SamplingMessage is a single text message in a sampling request.
+type SamplingMessage struct {
+	// Role is "user" or "assistant".
+	Role string
+
+	// Text is the message content.
+	Text string
+}
+
+// SamplingResult is the completion returned to the server.
+type SamplingResult struct {
+	// Text is the generated content.
+	Text string
+
+	// Model is the model that generated the content.
+	Model string
+
+	// StopReason explains why generation stopped ("endTurn", "maxTokens" or "stopSequence").
+	StopReason string
+}
+
+// Tier maps the request's model preferences to a model tier: "fast",
+// "balanced" or "strategic". A hint naming a tier wins; otherwise the
+// highest priority decides, defaulting to balanced.
+func (r SamplingRequest) Tier() string {
+	for _, hint := range r.ModelHints {
+		switch hint := strings.ToLower(hint); hint {
+		case "fast", "balanced", "strategic":
+			return hint
+		}
+	}
+
+	switch {
+	case r.IntelligencePriority > r.SpeedPriority && r.IntelligencePriority > r.CostPriority:
+		return "strategic"
+	case r.SpeedPriority > r.IntelligencePriority || r.CostPriority > r.IntelligencePriority:
+		return "fast"
+	default:
+		return "balanced"
+	}
+}
+
+// samplingAdapter exposes a SamplingHandler to the mcp-go client.
+type samplingAdapter struct {
+	serverName string
+	handler    SamplingHandler
+}
+
+// CreateMessage converts a sampling request from the server, completes it
+// and converts the result back.
+func (a *samplingAdapter) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	params := request.CreateMessageParams
+
+	req := SamplingRequest{
+		ServerName:    a.serverName,
+		SystemPrompt:  params.SystemPrompt,
+		MaxTokens:     params.MaxTokens,
+		Temperature:   params.Temperature,
+		StopSequences: params.StopSequences,
+	}
+	for i, msg := range params.Messages {
+		text, ok := samplingText(msg.Content)
+		if !ok {
+			return nil, fmt.Errorf("message %d: only text content is supported for sampling", i)
+		}
+		req.Messages = append(req.Messages, SamplingMessage{Role: string(msg.Role), Text: text})
+	}
+	if prefs := params.ModelPreferences; prefs != nil {
+		for _, hint := range prefs.Hints {
+			req.ModelHints = append(req.ModelHints, hint.Name)
+		}
+		req.CostPriority = prefs.CostPriority
+		req.SpeedPriority = prefs.SpeedPriority
+		req.IntelligencePriority = prefs.IntelligencePriority
+	}
+
+	result, err := a.handler.CreateMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: mcp.NewTextContent(result.Text),
+		},
+		Model:      result.Model,
+		StopReason: result.StopReason,
+	}, nil
+}
+
+func samplingText(content any) (string, bool) {
+	switch c := content.(type) {
+	case mcp.TextContent:
+		return c.Text, true
+	case *mcp.TextContent:
+		return c.Text, true
+	case string:
+		return c, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSamplingRequest_Tier(t *testing.T) {
+	tests := []struct {
+		name string
+		req  SamplingRequest
+		want string
+	}{
+		{"default", SamplingRequest{}, "balanced"},
+		{"tier hint", SamplingRequest{ModelHints: []string{"claude-3", "Strategic"}, SpeedPriority: 1}, "strategic"},
+		{"intelligence", SamplingRequest{IntelligencePriority: 0.9, SpeedPriority: 0.2}, "strategic"},
+		{"speed", SamplingRequest{IntelligencePriority: 0.3, SpeedPriority: 0.8}, "fast"},
+		{"cost", SamplingRequest{CostPriority: 0.8}, "fast"},
+		{"tied", SamplingRequest{IntelligencePriority: 0.5, SpeedPriority: 0.5}, "balanced"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.Tier(); got != tt.want {
+				t.Errorf("Tier() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type recordingSampler struct {
+	req SamplingRequest
+}
+
+func (s *recordingSampler) CreateMessage(ctx context.Context, req SamplingRequest) (*SamplingResult, error) {
+	s.req = req
+	return &SamplingResult{Text: "done", Model: "small-model", StopReason: "endTurn"}, nil
+}
+
+func TestSamplingAdapter_CreateMessage(t *testing.T) {
+	handler := &recordingSampler{}
+	adapter := &samplingAdapter{serverName: "docs", handler: handler}
+
+	request := mcp.CreateMessageRequest{}
+	request.Messages = []mcp.SamplingMessage{
+		{Role: mcp.RoleUser, Content: mcp.NewTextContent("Summarize this")},
+	}
+	request.SystemPrompt = "Be brief"
+	request.MaxTokens = 200
+	request.ModelPreferences = &mcp.ModelPreferences{
+		Hints:         []mcp.ModelHint{{Name: "fast"}},
+		SpeedPriority: 0.7,
+	}
+
+	result, err := adapter.CreateMessage(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+
+	if handler.req.ServerName != "docs" || handler.req.MaxTokens != 200 || handler.req.SystemPrompt != "Be brief" {
+		t.Errorf("unexpected request %+v", handler.req)
+	}
+	if len(handler.req.Messages) != 1 || handler.req.Messages[0].Text != "Summarize this" || handler.req.Messages[0].Role != "user" {
+		t.Errorf("unexpected messages %+v", handler.req.Messages)
+	}
+	if handler.req.Tier() != "fast" {
+		t.Errorf("expected fast tier, got %q", handler.req.Tier())
+	}
+
+	if result.Model != "small-model" || result.Role != mcp.RoleAssistant {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if text, ok := samplingText(result.Content); !ok || text != "done" {
+		t.Errorf("unexpected result content %v", result.Content)
+	}
+
+	request.Messages = []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewImageContent("data", "image/png")}}
+	if _, err := adapter.CreateMessage(context.Background(), request); err == nil {
+		t.Error("expected error for image content")
+	}
+}
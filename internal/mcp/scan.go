@@ -0,0 +1,149 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tombee/conductor/pkg/security"
+)
+
+// suspiciousPatterns match wording in tool descriptions that tries to steer
+// the model rather than describe the tool.
+var suspiciousPatterns = []struct {
+	reason  string
+	pattern *regexp.Regexp
+}{
+	{"overrides prior instructions", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,30}\b(previous|prior|above|earlier|other|all)\b.{0,20}\b(instructions?|rules|prompts?|guidelines)`)},
+	{"hides actions from the user", regexp.MustCompile(`(?i)\b(do not|don't|never)\b.{0,20}\b(tell|inform|mention|reveal|notify|show)\b.{0,20}\b(the )?user`)},
+	{"contains hidden directive tags", regexp.MustCompile(`(?i)<\s*/?\s*(important|system|instructions?|secret)\s*>`)},
+	{"references the system prompt", regexp.MustCompile(`(?i)\bsystem\s+prompt\b`)},
+	{"references credential files", regexp.MustCompile(`(?i)(~/\.ssh|id_rsa|id_ed25519|\.aws/credentials|\.netrc)`)},
+	{"instructs other tool calls", regexp.MustCompile(`(?i)\b(before|after|instead of)\b.{0,20}\b(using|calling)\b.{0,40}\b(you must|always|first)\b`)},
+	{"asks to send data elsewhere", regexp.MustCompile(`(?i)\b(send|forward|upload|post|exfiltrate)\b.{0,40}\b(to|via)\b.{0,20}(https?://|\bemail\b|\bwebhook\b)`)},
+}
+
+// hiddenRunes are invisible or text-reordering characters used to conceal
+// instructions from human reviewers.
+var hiddenRunes = map[rune]bool{
+	'\u200b': true, '\u200c': true, '\u200d': true, '\u2060': true, '\ufeff': true,
+	'\u202a': true, '\u202b': true, '\u202c': true, '\u202d': true, '\u202e': true,
+	'\u2066': true, '\u2067': true, '\u2068': true, '\u2069': true,
+}
+
+// SuspiciousTool is a tool whose description looks like a prompt injection.
+type SuspiciousTool struct {
+	// Server is the server name.
+	Server string
+
+	// Tool is the tool name.
+	Tool string
+
+	// Reasons lists why the description was flagged.
+	Reasons []string
+}
+
+// String returns a human-readable description of the finding.
+func (s SuspiciousTool) String() string {
+	return fmt.Sprintf("%s.%s: %s", s.Server, s.Tool, strings.Join(s.Reasons, ", "))
+}
+
+// ScanTools flags tools whose descriptions contain instructions aimed at the
+// model, such as overriding prior instructions or reading credential files.
+func ScanTools(serverName string, tools []ToolDefinition) []SuspiciousTool {
+	var findings []SuspiciousTool
+	for _, tool := range tools {
+		if reasons := scanDescription(tool.Description); len(reasons) > 0 {
+			findings = append(findings, SuspiciousTool{Server: serverName, Tool: tool.Name, Reasons: reasons})
+		}
+	}
+	return findings
+}
+
+func scanDescription(description string) []string {
+	var reasons []string
+	for _, r := range description {
+		if hiddenRunes[r] || (r >= 0xe0000 && r <= 0xe007f) {
+			reasons = append(reasons, "contains hidden characters")
+			break
+		}
+	}
+	for _, p := range suspiciousPatterns {
+		if p.pattern.MatchString(description) {
+			reasons = append(reasons, p.reason)
+		}
+	}
+	return reasons
+}
+
+// ToolPolicy verifies the tools offered by MCP servers before a run uses them.
+type ToolPolicy struct {
+	// Lockfile holds the pinned tools. Servers it does not pin, or every
+	// server if it is nil, are trusted with a warning.
+	Lockfile *Lockfile
+
+	// Actions come from the run's security profile.
+	Actions security.MCPConfig
+}
+
+// Check verifies a server's tools against the lockfile and scans their
+// descriptions. Findings whose action is warn are returned as warnings;
+// findings whose action is block are returned as an error.
+func (p *ToolPolicy) Check(serverName, source string, tools []ToolDefinition) ([]string, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var warnings []string
+
+	// Only a mismatch with an existing pin is drift
+	pinned := false
+	if p.Lockfile != nil {
+		_, pinned = p.Lockfile.Servers[serverName]
+	}
+	if !pinned {
+		warnings = append(warnings, fmt.Sprintf("MCP server %s tools are not pinned in %s (run 'conductor mcp lock' after review)",
+			serverName, LockfileName))
+	} else {
+		current, err := NewServerLock(source, tools)
+		if err != nil {
+			return nil, err
+		}
+		if drift := p.Lockfile.Compare(serverName, current); len(drift) > 0 {
+			details := make([]string, len(drift))
+			for i, d := range drift {
+				details[i] = d.String()
+			}
+			msg := fmt.Sprintf("MCP server %s tools differ from %s (run 'conductor mcp lock' after review): %s",
+				serverName, LockfileName, strings.Join(details, "; "))
+			if p.Actions.ToolDrift == security.MCPActionBlock {
+				return nil, fmt.Errorf("%s", msg)
+			}
+			warnings = append(warnings, msg)
+		}
+	}
+
+	for _, finding := range ScanTools(serverName, tools) {
+		msg := fmt.Sprintf("Suspicious MCP tool description: %s", finding)
+		if p.Actions.SuspiciousTools == security.MCPActionBlock {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		warnings = append(warnings, msg)
+	}
+
+	return warnings, nil
+}
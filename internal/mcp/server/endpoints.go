@@ -0,0 +1,45 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tombee/conductor/internal/controller/endpoint"
+)
+
+// EndpointWorkflowTools returns a workflow tool for every endpoint in the registry.
+// Endpoint default inputs become tool defaults.
+func EndpointWorkflowTools(registry *endpoint.Registry, workflowsDir string) ([]WorkflowTool, error) {
+	var tools []WorkflowTool
+	for _, ep := range registry.List() {
+		path, def, err := endpoint.LoadWorkflow(ep, workflowsDir)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", ep.Name, err)
+		}
+		tools = append(tools, WorkflowTool{
+			Name:        ep.Name,
+			Description: ep.Description,
+			Definition:  def,
+			Path:        path,
+			Endpoint:    ep.Name,
+			Inputs:      ep.Inputs,
+		})
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
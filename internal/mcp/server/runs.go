@@ -0,0 +1,254 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// runResourcePrefix is the URI prefix of run resources.
+	runResourcePrefix = "conductor://runs/"
+
+	// maxTrackedRuns bounds the runs listed as resources.
+	maxTrackedRuns = 100
+
+	// defaultPollInterval is how often in-progress runs are polled for changes.
+	defaultPollInterval = time.Second
+)
+
+// runResourceURIs returns the status, logs and outputs resource URIs of a run.
+func runResourceURIs(id string) []string {
+	base := runResourcePrefix + id
+	return []string{base, base + "/logs", base + "/outputs"}
+}
+
+// parseRunResourceURI splits a run resource URI into the run ID and the
+// resource kind ("", "logs" or "outputs").
+func parseRunResourceURI(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, runResourcePrefix)
+	if !ok || rest == "" {
+		return "", "", fmt.Errorf("invalid run resource URI: %s", uri)
+	}
+
+	id, kind, _ := strings.Cut(rest, "/")
+	switch kind {
+	case "", "logs", "outputs":
+	default:
+		return "", "", fmt.Errorf("unknown run resource: %s", uri)
+	}
+	if id == "" {
+		return "", "", fmt.Errorf("invalid run resource URI: %s", uri)
+	}
+	return id, kind, nil
+}
+
+// registerRunResources registers the run resource templates.
+func (s *Server) registerRunResources() {
+	templates := []struct {
+		uri, name, description, mimeType string
+	}{
+		{runResourcePrefix + "{id}", "Run status", "Status, error and timing of a workflow run", "application/json"},
+		{runResourcePrefix + "{id}/logs", "Run logs", "Log output of a workflow run", "text/plain"},
+		{runResourcePrefix + "{id}/outputs", "Run outputs", "Outputs of a completed workflow run", "application/json"},
+	}
+
+	for _, t := range templates {
+		s.mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(t.uri, t.name,
+			mcp.WithTemplateDescription(t.description),
+			mcp.WithTemplateMIMEType(t.mimeType),
+		), s.handleReadRunResource)
+	}
+}
+
+// handleReadRunResource reads a run status, logs or outputs resource.
+func (s *Server) handleReadRunResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	id, kind, err := parseRunResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.runs.GetRun(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %s: %w", id, err)
+	}
+	if authorizer, ok := s.runs.(RunAuthorizer); ok {
+		if err := authorizer.AuthorizeRun(ctx, run, kind != ""); err != nil {
+			// Same response as a missing run to avoid information disclosure
+			return nil, fmt.Errorf("failed to get run %s: run not found", id)
+		}
+	}
+
+	switch kind {
+	case "logs":
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: uri, MIMEType: "text/plain", Text: formatRunLogs(run.Logs)},
+		}, nil
+	case "outputs":
+		if run.Status != "completed" {
+			return nil, fmt.Errorf("run %s not completed (status: %s)", id, run.Status)
+		}
+		return jsonResourceContents(uri, run.Output)
+	default:
+		status := *run
+		status.Logs = nil
+		status.Output = nil
+		return jsonResourceContents(uri, status)
+	}
+}
+
+func jsonResourceContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", uri, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}, nil
+}
+
+// formatRunLogs renders run log entries as plain text, one entry per line.
+func formatRunLogs(logs []RunLog) string {
+	var b strings.Builder
+	for _, entry := range logs {
+		var text string
+		switch entry.Type {
+		case "step_start":
+			text = fmt.Sprintf("step %s started", entry.StepID)
+		case "step_complete":
+			text = fmt.Sprintf("step %s %s", entry.StepID, entry.Status)
+		case "status":
+			text = fmt.Sprintf("run %s", entry.Status)
+		default:
+			text = entry.Message
+			if entry.StepID != "" {
+				text = entry.StepID + ": " + text
+			}
+		}
+		if entry.Error != "" {
+			text += ": " + entry.Error
+		}
+
+		level := entry.Level
+		if level == "" {
+			level = "info"
+		}
+		fmt.Fprintf(&b, "%s %-5s %s\n", entry.Timestamp.Format(time.RFC3339), strings.ToUpper(level), text)
+	}
+	return b.String()
+}
+
+// runTracker lists runs started by workflow tools as resources and
+// notifies clients when their state changes.
+type runTracker struct {
+	s *Server
+
+	mu    sync.Mutex
+	order []string
+	ctx   context.Context
+	stop  context.CancelFunc
+}
+
+func newRunTracker(s *Server) *runTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &runTracker{s: s, ctx: ctx, stop: cancel}
+}
+
+// track lists a run as resources and watches it until it finishes.
+func (t *runTracker) track(run *RunInfo) {
+	t.mu.Lock()
+	t.order = append(t.order, run.ID)
+	var evicted []string
+	if len(t.order) > maxTrackedRuns {
+		evicted = t.order[:len(t.order)-maxTrackedRuns]
+		t.order = append([]string(nil), t.order[len(t.order)-maxTrackedRuns:]...)
+	}
+	t.mu.Unlock()
+
+	for _, id := range evicted {
+		t.s.mcpServer.DeleteResources(runResourceURIs(id)...)
+	}
+
+	uris := runResourceURIs(run.ID)
+	names := []string{"status", "logs", "outputs"}
+	for i, uri := range uris {
+		mimeType := "application/json"
+		if names[i] == "logs" {
+			mimeType = "text/plain"
+		}
+		t.s.mcpServer.AddResource(mcp.NewResource(uri, fmt.Sprintf("%s %s (%s)", run.Workflow, names[i], run.ID),
+			mcp.WithMIMEType(mimeType),
+		), t.s.handleReadRunResource)
+	}
+
+	if !run.Done() {
+		go t.watch(run)
+	}
+}
+
+// watch polls a run and sends resource update notifications until the run finishes.
+func (t *runTracker) watch(run *RunInfo) {
+	ticker := time.NewTicker(t.s.pollInterval)
+	defer ticker.Stop()
+
+	last := run
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := t.s.runs.GetRun(t.ctx, run.ID)
+		if err != nil {
+			t.s.logger.Debug("Failed to poll run", "run_id", run.ID, "error", err)
+			continue
+		}
+
+		uris := runResourceURIs(run.ID)
+		if current.Status != last.Status || current.Error != last.Error {
+			t.notifyUpdated(uris[0])
+		}
+		if len(current.Logs) != len(last.Logs) {
+			t.notifyUpdated(uris[1])
+		}
+		if current.Done() {
+			if current.Status == "completed" {
+				t.notifyUpdated(uris[2])
+			}
+			return
+		}
+		last = current
+	}
+}
+
+func (t *runTracker) notifyUpdated(uri string) {
+	t.s.mcpServer.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{
+		"uri": uri,
+	})
+}
+
+// close stops watching runs.
+func (t *runTracker) close() {
+	t.stop()
+}
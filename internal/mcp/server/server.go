@@ -19,7 +19,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -32,6 +34,13 @@ type Server struct {
 	version     string
 	rateLimiter *RateLimiter
 	logger      *slog.Logger
+
+	// Workflow tools
+	runs          RunBackend
+	workflowTools map[string]WorkflowTool
+	tracker       *runTracker
+	runWait       time.Duration
+	pollInterval  time.Duration
 }
 
 // ServerConfig configures the MCP server
@@ -48,6 +57,21 @@ type ServerConfig struct {
 	// OperationRegistry optionally provides operation registry actions as tools.
 	// If set, builtin actions (file, shell, etc.) will be exposed as MCP tools.
 	OperationRegistry OperationRegistry
+
+	// Workflows are exposed as dedicated tools, one per workflow, with an
+	// input schema built from the workflow inputs.
+	Workflows []WorkflowTool
+
+	// Runs starts and tracks the runs of workflow tools. Required when
+	// Workflows is set. Runs are published as conductor://runs/{id} resources.
+	Runs RunBackend
+
+	// RunWait is how long a workflow tool waits for its run to finish before
+	// returning the run ID (default: 30s). A negative value returns immediately.
+	RunWait time.Duration
+
+	// WorkflowToolsOnly omits the generic conductor_* tools.
+	WorkflowToolsOnly bool
 }
 
 // createLogger creates a logger with the specified log level.
@@ -90,23 +114,45 @@ func NewServer(config ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("failed to create logger: %w", err)
 	}
 
-	// Create the underlying MCP server
-	mcpServer := server.NewMCPServer(config.Name, config.Version)
+	if config.RunWait == 0 {
+		config.RunWait = defaultRunWait
+	}
 
 	// Create rate limiter (10 runs/min, 100 calls/min)
 	rateLimiter := NewRateLimiter(10, 100)
 
 	s := &Server{
-		mcpServer:   mcpServer,
-		name:        config.Name,
-		version:     config.Version,
-		rateLimiter: rateLimiter,
-		logger:      logger,
+		name:          config.Name,
+		version:       config.Version,
+		rateLimiter:   rateLimiter,
+		logger:        logger,
+		runs:          config.Runs,
+		workflowTools: make(map[string]WorkflowTool),
+		runWait:       config.RunWait,
+		pollInterval:  defaultPollInterval,
 	}
 
+	// Create the underlying MCP server
+	s.mcpServer = server.NewMCPServer(config.Name, config.Version,
+		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, true),
+		server.WithToolFilter(s.filterWorkflowTools),
+	)
+
 	// Register Conductor workflow tools (validate, run, etc.)
-	if err := s.registerTools(); err != nil {
-		return nil, fmt.Errorf("failed to register tools: %w", err)
+	if !config.WorkflowToolsOnly {
+		if err := s.registerTools(); err != nil {
+			return nil, fmt.Errorf("failed to register tools: %w", err)
+		}
+	}
+
+	// Register one tool per workflow, with run resources
+	if len(config.Workflows) > 0 {
+		if err := s.registerWorkflowTools(config.Workflows); err != nil {
+			return nil, fmt.Errorf("failed to register workflow tools: %w", err)
+		}
+		s.tracker = newRunTracker(s)
+		s.registerRunResources()
 	}
 
 	// Register operation registry tools if provided
@@ -236,9 +282,17 @@ func (s *Server) Run(ctx context.Context) error {
 	return nil
 }
 
+// HTTPHandler returns a handler serving the MCP server over Streamable HTTP at path.
+func (s *Server) HTTPHandler(path string) http.Handler {
+	return server.NewStreamableHTTPServer(s.mcpServer, server.WithEndpointPath(path))
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down Conductor MCP server")
+	if s.tracker != nil {
+		s.tracker.close()
+	}
 	// The mcp-go server doesn't have an explicit shutdown method
 	// Returning from ServeStdio() is sufficient
 	return nil
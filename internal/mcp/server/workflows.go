@@ -0,0 +1,358 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// defaultRunWait is how long a workflow tool waits for its run to finish
+// before returning the run ID and resource URIs instead.
+const defaultRunWait = 30 * time.Second
+
+// WorkflowTool describes a workflow exposed as a dedicated MCP tool.
+type WorkflowTool struct {
+	// Name is the tool name. Derived from the workflow name when empty.
+	Name string
+
+	// Description is the tool description. Defaults to the workflow description.
+	Description string
+
+	// Definition is the parsed workflow, used to build the input schema.
+	Definition *workflow.Definition
+
+	// Path is the workflow file executed by the tool.
+	Path string
+
+	// Endpoint is the controller endpoint that runs the workflow, if any.
+	Endpoint string
+
+	// Inputs are default inputs merged beneath caller-provided inputs.
+	Inputs map[string]any
+}
+
+// RunBackend starts workflow runs for workflow tools and reports their state.
+type RunBackend interface {
+	StartRun(ctx context.Context, tool WorkflowTool, inputs map[string]any) (*RunInfo, error)
+	GetRun(ctx context.Context, id string) (*RunInfo, error)
+}
+
+// RunAuthorizer is implemented by run backends that limit which runs a
+// caller may read as conductor://runs resources. outputs is set for the logs
+// and outputs resources.
+type RunAuthorizer interface {
+	AuthorizeRun(ctx context.Context, run *RunInfo, outputs bool) error
+}
+
+// ToolAuthorizer is implemented by run backends that limit which workflow
+// tools a caller may see in tools/list. StartRun still checks each call.
+type ToolAuthorizer interface {
+	ToolAllowed(ctx context.Context, tool WorkflowTool) bool
+}
+
+// RunInfo is the state of a workflow run started through a workflow tool.
+type RunInfo struct {
+	ID          string         `json:"id"`
+	Workflow    string         `json:"workflow"`
+	Status      string         `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	Output      map[string]any `json:"output,omitempty"`
+	Logs        []RunLog       `json:"logs,omitempty"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+}
+
+// RunLog is a single log entry of a run.
+type RunLog struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type,omitempty"`
+	Level     string    `json:"level,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	StepID    string    `json:"step_id,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Done reports whether the run has reached a terminal state.
+func (r *RunInfo) Done() bool {
+	switch r.Status {
+	case "completed", "failed", "cancelled", "dry_run":
+		return true
+	}
+	return false
+}
+
+// LoadWorkflowTools returns a workflow tool for every workflow file in dir.
+// Files that do not parse as workflows are skipped.
+func LoadWorkflowTools(dir string) ([]WorkflowTool, error) {
+	var tools []WorkflowTool
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		def, err := workflow.ParseDefinition(data)
+		if err != nil {
+			return nil
+		}
+
+		tools = append(tools, WorkflowTool{Name: def.Name, Definition: def, Path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflows from %s: %w", dir, err)
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Path < tools[j].Path })
+	return tools, nil
+}
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// workflowToolName converts a workflow or endpoint name into a valid MCP tool name.
+func workflowToolName(name string) string {
+	return strings.Trim(invalidToolNameChars.ReplaceAllString(name, "_"), "_")
+}
+
+// registerWorkflowTools registers one MCP tool per workflow.
+func (s *Server) registerWorkflowTools(tools []WorkflowTool) error {
+	if s.runs == nil {
+		return fmt.Errorf("a run backend is required for workflow tools")
+	}
+
+	seen := make(map[string]string)
+	for _, tool := range tools {
+		if tool.Definition == nil {
+			return fmt.Errorf("workflow tool %q has no definition", tool.Name)
+		}
+
+		name := tool.Name
+		if name == "" {
+			name = tool.Definition.Name
+		}
+		name = workflowToolName(name)
+		if name == "" {
+			return fmt.Errorf("workflow %s has no usable tool name", tool.Path)
+		}
+		if prev, ok := seen[name]; ok {
+			return fmt.Errorf("duplicate workflow tool %q (%s and %s)", name, prev, tool.Path)
+		}
+		seen[name] = tool.Path
+		tool.Name = name
+		s.workflowTools[name] = tool
+
+		description := tool.Description
+		if description == "" {
+			description = tool.Definition.Description
+		}
+		if description == "" {
+			description = fmt.Sprintf("Run the %s workflow", tool.Definition.Name)
+		}
+
+		s.mcpServer.AddTool(mcp.Tool{
+			Name:        name,
+			Description: description,
+			InputSchema: workflowInputSchema(tool.Definition, tool.Inputs),
+		}, s.handleWorkflowTool(tool))
+
+		s.logger.Debug("Registered workflow tool", "tool", name, "path", tool.Path)
+	}
+
+	return nil
+}
+
+// filterWorkflowTools hides the workflow tools the caller may not run, when
+// the run backend is a ToolAuthorizer.
+func (s *Server) filterWorkflowTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	authorizer, ok := s.runs.(ToolAuthorizer)
+	if !ok {
+		return tools
+	}
+	visible := make([]mcp.Tool, 0, len(tools))
+	for _, t := range tools {
+		if tool, ok := s.workflowTools[t.Name]; ok && !authorizer.ToolAllowed(ctx, tool) {
+			continue
+		}
+		visible = append(visible, t)
+	}
+	return visible
+}
+
+// workflowInputSchema builds the JSON schema of a workflow tool from the workflow inputs.
+// Inputs without a default, in the workflow or the tool defaults, are required.
+func workflowInputSchema(def *workflow.Definition, defaults map[string]any) mcp.ToolInputSchema {
+	schema := mcp.ToolInputSchema{
+		Type:       "object",
+		Properties: make(map[string]interface{}),
+	}
+
+	for _, input := range def.Inputs {
+		prop := map[string]interface{}{
+			"type": jsonSchemaType(input.Type),
+		}
+		if input.Description != "" {
+			prop["description"] = input.Description
+		}
+		if len(input.Enum) > 0 {
+			prop["enum"] = input.Enum
+		}
+		if input.Pattern != "" {
+			prop["pattern"] = input.Pattern
+		}
+
+		value, hasDefault := defaults[input.Name]
+		if !hasDefault && input.Default != nil {
+			value, hasDefault = input.Default, true
+		}
+		if hasDefault {
+			prop["default"] = value
+		} else {
+			schema.Required = append(schema.Required, input.Name)
+		}
+
+		schema.Properties[input.Name] = prop
+	}
+
+	return schema
+}
+
+// jsonSchemaType maps a workflow input type to a JSON schema type.
+func jsonSchemaType(inputType string) string {
+	switch inputType {
+	case "number", "boolean", "object", "array":
+		return inputType
+	default:
+		return "string"
+	}
+}
+
+// workflowToolResult is the result of a workflow tool call.
+type workflowToolResult struct {
+	RunID     string         `json:"run_id"`
+	Status    string         `json:"status"`
+	Outputs   map[string]any `json:"outputs,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Resources []string       `json:"resources"`
+	Message   string         `json:"message,omitempty"`
+}
+
+// handleWorkflowTool returns the handler for a workflow tool.
+func (s *Server) handleWorkflowTool(tool WorkflowTool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !s.rateLimiter.AllowRun() {
+			return errorResponse("Rate limit exceeded for workflow execution. Please try again later."), nil
+		}
+
+		inputs := make(map[string]any, len(tool.Inputs))
+		for k, v := range tool.Inputs {
+			inputs[k] = v
+		}
+		for k, v := range request.GetArguments() {
+			inputs[k] = v
+		}
+
+		if err := validateInputs(tool.Definition, inputs); err != nil {
+			return errorResponse(fmt.Sprintf("Input validation failed: %v", err)), nil
+		}
+
+		run, err := s.runs.StartRun(ctx, tool, inputs)
+		if err != nil {
+			return errorResponse(fmt.Sprintf("Failed to start workflow: %v", err)), nil
+		}
+		s.tracker.track(run)
+
+		run = s.waitForRun(ctx, run)
+
+		result := workflowToolResult{
+			RunID:     run.ID,
+			Status:    run.Status,
+			Outputs:   run.Output,
+			Error:     run.Error,
+			Resources: runResourceURIs(run.ID),
+		}
+		if !run.Done() {
+			result.Message = "The run is still in progress. Read the run resources for status, logs and outputs; updates are sent as resource notifications."
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errorResponse(fmt.Sprintf("Failed to encode run result: %v", err)), nil
+		}
+
+		response := textResponse(string(resultJSON))
+		response.IsError = run.Status == "failed" || run.Status == "cancelled"
+		return response, nil
+	}
+}
+
+// waitForRun polls a run until it finishes, the wait period elapses, or ctx is done.
+// It returns the latest known state of the run.
+func (s *Server) waitForRun(ctx context.Context, run *RunInfo) *RunInfo {
+	if run.Done() || s.runWait <= 0 {
+		return run
+	}
+
+	timer := time.NewTimer(s.runWait)
+	defer timer.Stop()
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return run
+		case <-timer.C:
+			return run
+		case <-ticker.C:
+			latest, err := s.runs.GetRun(ctx, run.ID)
+			if err != nil {
+				s.logger.Debug("Failed to poll run", "run_id", run.ID, "error", err)
+				continue
+			}
+			run = latest
+			if run.Done() {
+				return run
+			}
+		}
+	}
+}
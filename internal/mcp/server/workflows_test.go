@@ -0,0 +1,442 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeRunBackend completes each run after a fixed number of polls.
+type fakeRunBackend struct {
+	mu       sync.Mutex
+	runs     map[string]*RunInfo
+	polls    map[string]int
+	finishAt int
+	started  []map[string]any
+}
+
+func newFakeRunBackend(finishAt int) *fakeRunBackend {
+	return &fakeRunBackend{runs: make(map[string]*RunInfo), polls: make(map[string]int), finishAt: finishAt}
+}
+
+func (b *fakeRunBackend) StartRun(ctx context.Context, tool WorkflowTool, inputs map[string]any) (*RunInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := fmt.Sprintf("run-%d", len(b.runs)+1)
+	b.runs[id] = &RunInfo{ID: id, Workflow: tool.Definition.Name, Status: "running"}
+	b.started = append(b.started, inputs)
+	return b.get(id), nil
+}
+
+func (b *fakeRunBackend) GetRun(ctx context.Context, id string) (*RunInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	run, ok := b.runs[id]
+	if !ok {
+		return nil, fmt.Errorf("run not found: %s", id)
+	}
+	b.polls[id]++
+	if b.polls[id] >= b.finishAt && !run.Done() {
+		run.Status = "completed"
+		run.Output = map[string]any{"summary": "done"}
+		run.Logs = append(run.Logs, RunLog{Timestamp: time.Unix(0, 0).UTC(), Type: "status", Status: "completed"})
+	}
+	return b.get(id), nil
+}
+
+func (b *fakeRunBackend) get(id string) *RunInfo {
+	run := *b.runs[id]
+	run.Logs = append([]RunLog(nil), run.Logs...)
+	return &run
+}
+
+// outputsDeniedBackend lets callers read run status but not logs or outputs.
+type outputsDeniedBackend struct {
+	*fakeRunBackend
+}
+
+func (b outputsDeniedBackend) AuthorizeRun(ctx context.Context, run *RunInfo, outputs bool) error {
+	if outputs {
+		return fmt.Errorf("forbidden: read_outputs on workflow %s", run.Workflow)
+	}
+	return nil
+}
+
+// reviewOnlyBackend lets callers see only the code-review tool.
+type reviewOnlyBackend struct {
+	*fakeRunBackend
+}
+
+func (b reviewOnlyBackend) ToolAllowed(ctx context.Context, tool WorkflowTool) bool {
+	return tool.Definition.Name == "code-review"
+}
+
+const reviewWorkflow = `
+name: code-review
+description: Review a change
+inputs:
+  - name: branch
+    type: string
+    description: Branch to review
+  - name: depth
+    type: number
+    default: 2
+  - name: mode
+    type: string
+    enum: [quick, full]
+    default: quick
+steps:
+  - id: review
+    type: llm
+    prompt: "Review {{.inputs.branch}}"
+`
+
+func writeWorkflows(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"review.yaml":         reviewWorkflow,
+		"nested/hello.yml":    "name: hello world\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n",
+		"notes.txt":           "not a workflow",
+		"broken.yaml":         "steps: [",
+		".hidden/skip.yaml":   "name: hidden\nsteps:\n  - id: s\n    type: llm\n    prompt: hi\n",
+		"config/unnamed.yaml": "steps:\n  - id: s\n    type: llm\n    prompt: hi\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadWorkflowTools(t *testing.T) {
+	dir := writeWorkflows(t)
+
+	tools, err := LoadWorkflowTools(dir)
+	if err != nil {
+		t.Fatalf("LoadWorkflowTools() error = %v", err)
+	}
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	got := strings.Join(names, ",")
+	if got != "hello world,code-review" {
+		t.Errorf("tool names = %s, want hello world,code-review", got)
+	}
+}
+
+func TestWorkflowInputSchema(t *testing.T) {
+	dir := writeWorkflows(t)
+	tools, err := LoadWorkflowTools(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := tools[1].Definition
+
+	schema := workflowInputSchema(def, nil)
+	if strings.Join(schema.Required, ",") != "branch" {
+		t.Errorf("Required = %v, want [branch]", schema.Required)
+	}
+	depth := schema.Properties["depth"].(map[string]interface{})
+	if depth["type"] != "number" || depth["default"] != 2 {
+		t.Errorf("depth = %v, want number with default 2", depth)
+	}
+	mode := schema.Properties["mode"].(map[string]interface{})
+	if mode["type"] != "string" || len(mode["enum"].([]string)) != 2 {
+		t.Errorf("mode = %v, want string enum", mode)
+	}
+
+	// Tool defaults make inputs optional
+	schema = workflowInputSchema(def, map[string]any{"branch": "main"})
+	if len(schema.Required) != 0 {
+		t.Errorf("Required = %v, want none", schema.Required)
+	}
+}
+
+func TestWorkflowToolName(t *testing.T) {
+	for in, want := range map[string]string{
+		"code-review":  "code-review",
+		"hello world":  "hello_world",
+		"deploy/app!!": "deploy_app",
+		"...":          "",
+	} {
+		if got := workflowToolName(in); got != want {
+			t.Errorf("workflowToolName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func newWorkflowTestClient(t *testing.T, backend RunBackend, runWait time.Duration, notifications chan<- mcp.JSONRPCNotification) *client.Client {
+	t.Helper()
+
+	tools, err := LoadWorkflowTools(writeWorkflows(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(ServerConfig{
+		LogLevel:          "error",
+		Workflows:         tools,
+		Runs:              backend,
+		RunWait:           runWait,
+		WorkflowToolsOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	srv.pollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	httpServer := httptest.NewServer(srv.HTTPHandler("/mcp"))
+	t.Cleanup(httpServer.Close)
+
+	c, err := client.NewStreamableHttpClient(httpServer.URL+"/mcp", transport.WithContinuousListening())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifications != nil {
+		c.OnNotification(func(n mcp.JSONRPCNotification) { notifications <- n })
+	}
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func callWorkflowTool(t *testing.T, c *client.Client, name string, args map[string]any) (workflowToolResult, *mcp.CallToolResult) {
+	t.Helper()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := c.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	var result workflowToolResult
+	text := res.Content[0].(mcp.TextContent).Text
+	if !res.IsError || strings.HasPrefix(text, "{") {
+		if err := json.Unmarshal([]byte(text), &result); err != nil {
+			t.Fatalf("failed to decode result %q: %v", text, err)
+		}
+	}
+	return result, res
+}
+
+func TestServer_WorkflowTools(t *testing.T) {
+	backend := newFakeRunBackend(2)
+	c := newWorkflowTestClient(t, backend, time.Second, nil)
+	ctx := context.Background()
+
+	tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 2 {
+		t.Fatalf("len(Tools) = %d, want 2 workflow tools only", len(tools.Tools))
+	}
+
+	result, res := callWorkflowTool(t, c, "code-review", map[string]any{"branch": "main"})
+	if res.IsError {
+		t.Fatalf("CallTool() returned error result: %+v", res.Content)
+	}
+	if result.Status != "completed" || result.Outputs["summary"] != "done" {
+		t.Errorf("result = %+v, want completed run with outputs", result)
+	}
+	if len(result.Resources) != 3 || result.Resources[0] != "conductor://runs/"+result.RunID {
+		t.Errorf("Resources = %v", result.Resources)
+	}
+	if backend.started[0]["branch"] != "main" {
+		t.Errorf("started inputs = %v", backend.started[0])
+	}
+
+	// Missing required input
+	_, res = callWorkflowTool(t, c, "code-review", map[string]any{})
+	if !res.IsError {
+		t.Error("CallTool() without required input succeeded, want error")
+	}
+
+	// Run resources are listed and readable
+	resources, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources.Resources) != 3 {
+		t.Errorf("len(Resources) = %d, want 3", len(resources.Resources))
+	}
+
+	read := func(uri string) string {
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = uri
+		res, err := c.ReadResource(ctx, req)
+		if err != nil {
+			t.Fatalf("ReadResource(%s) error = %v", uri, err)
+		}
+		return res.Contents[0].(mcp.TextResourceContents).Text
+	}
+
+	if status := read(result.Resources[0]); !strings.Contains(status, `"status": "completed"`) {
+		t.Errorf("status resource = %s", status)
+	}
+	if logs := read(result.Resources[1]); !strings.Contains(logs, "INFO  run completed") {
+		t.Errorf("logs resource = %q", logs)
+	}
+	if outputs := read(result.Resources[2]); !strings.Contains(outputs, `"summary": "done"`) {
+		t.Errorf("outputs resource = %s", outputs)
+	}
+}
+
+func TestServer_RunResourceAuthorization(t *testing.T) {
+	c := newWorkflowTestClient(t, outputsDeniedBackend{newFakeRunBackend(1)}, time.Second, nil)
+	ctx := context.Background()
+
+	result, res := callWorkflowTool(t, c, "hello_world", nil)
+	if res.IsError {
+		t.Fatalf("CallTool() returned error result: %+v", res.Content)
+	}
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = result.Resources[0]
+	if _, err := c.ReadResource(ctx, req); err != nil {
+		t.Errorf("ReadResource(status) error = %v", err)
+	}
+	for _, uri := range result.Resources[1:] {
+		req.Params.URI = uri
+		_, err := c.ReadResource(ctx, req)
+		if err == nil || !strings.Contains(err.Error(), "run not found") {
+			t.Errorf("ReadResource(%s) error = %v, want run not found", uri, err)
+		}
+	}
+}
+
+func TestServer_WorkflowToolFilter(t *testing.T) {
+	c := newWorkflowTestClient(t, reviewOnlyBackend{newFakeRunBackend(1)}, time.Second, nil)
+
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "code-review" {
+		t.Errorf("Tools = %+v, want only code-review", tools.Tools)
+	}
+}
+
+func TestServer_WorkflowToolNotifications(t *testing.T) {
+	backend := newFakeRunBackend(3)
+	notifications := make(chan mcp.JSONRPCNotification, 16)
+	c := newWorkflowTestClient(t, backend, -1, notifications)
+
+	result, res := callWorkflowTool(t, c, "hello_world", nil)
+	if res.IsError {
+		t.Fatalf("CallTool() returned error result: %+v", res.Content)
+	}
+	if result.Status != "running" || result.Message == "" {
+		t.Errorf("result = %+v, want running run", result)
+	}
+
+	want := map[string]bool{
+		mcp.MethodNotificationResourcesListChanged: false,
+		result.Resources[0]:                        false,
+		result.Resources[1]:                        false,
+		result.Resources[2]:                        false,
+	}
+	timeout := time.After(5 * time.Second)
+	for remaining := len(want); remaining > 0; {
+		select {
+		case n := <-notifications:
+			key := n.Method
+			if n.Method == mcp.MethodNotificationResourceUpdated {
+				key, _ = n.Params.AdditionalFields["uri"].(string)
+			}
+			if seen, ok := want[key]; ok && !seen {
+				want[key] = true
+				remaining--
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for notifications, got %v", want)
+		}
+	}
+}
+
+func TestNewServer_WorkflowToolsRequireBackend(t *testing.T) {
+	tools, err := LoadWorkflowTools(writeWorkflows(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServer(ServerConfig{Workflows: tools}); err == nil {
+		t.Error("NewServer() without run backend succeeded, want error")
+	}
+
+	dup := []WorkflowTool{tools[0], tools[0]}
+	if _, err := NewServer(ServerConfig{Workflows: dup, Runs: newFakeRunBackend(1)}); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("NewServer() with duplicate tools error = %v, want duplicate error", err)
+	}
+}
+
+func TestParseRunResourceURI(t *testing.T) {
+	tests := []struct {
+		uri      string
+		wantID   string
+		wantKind string
+		wantErr  bool
+	}{
+		{"conductor://runs/abc", "abc", "", false},
+		{"conductor://runs/abc/logs", "abc", "logs", false},
+		{"conductor://runs/abc/outputs", "abc", "outputs", false},
+		{"conductor://runs/abc/steps", "", "", true},
+		{"conductor://runs/", "", "", true},
+		{"file:///runs/abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		id, kind, err := parseRunResourceURI(tt.uri)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRunResourceURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			continue
+		}
+		if id != tt.wantID || kind != tt.wantKind {
+			t.Errorf("parseRunResourceURI(%q) = (%q, %q), want (%q, %q)", tt.uri, id, kind, tt.wantID, tt.wantKind)
+		}
+	}
+}
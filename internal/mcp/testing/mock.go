@@ -31,6 +31,10 @@ type MockClient struct {
 	pingFunc   func(ctx context.Context) error
 	closeFunc  func() error
 	callDelay  time.Duration
+	resources  []mcp.ResourceDefinition
+	contents   map[string]string
+	prompts    []mcp.PromptDefinition
+	promptFunc func(ctx context.Context, req mcp.PromptGetRequest) (*mcp.PromptGetResponse, error)
 	mu         sync.RWMutex
 }
 
@@ -85,6 +89,58 @@ func (c *MockClient) CallTool(ctx context.Context, req mcp.ToolCallRequest) (*mc
 	}, nil
 }
 
+// ListResources returns the configured list of resources.
+func (c *MockClient) ListResources(ctx context.Context) ([]mcp.ResourceDefinition, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.resources == nil {
+		return nil, fmt.Errorf("server does not support resources")
+	}
+	resourcesCopy := make([]mcp.ResourceDefinition, len(c.resources))
+	copy(resourcesCopy, c.resources)
+	return resourcesCopy, nil
+}
+
+// ReadResource returns the configured text content for a resource URI.
+func (c *MockClient) ReadResource(ctx context.Context, req mcp.ResourceReadRequest) (*mcp.ResourceReadResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	text, ok := c.contents[req.URI]
+	if !ok {
+		return nil, fmt.Errorf("resource not found: %s", req.URI)
+	}
+	return &mcp.ResourceReadResponse{
+		Contents: []mcp.ResourceContent{{URI: req.URI, MimeType: "text/plain", Text: text}},
+	}, nil
+}
+
+// ListPrompts returns the configured list of prompts.
+func (c *MockClient) ListPrompts(ctx context.Context) ([]mcp.PromptDefinition, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.prompts == nil {
+		return nil, fmt.Errorf("server does not support prompts")
+	}
+	promptsCopy := make([]mcp.PromptDefinition, len(c.prompts))
+	copy(promptsCopy, c.prompts)
+	return promptsCopy, nil
+}
+
+// GetPrompt renders a prompt using the configured handler.
+func (c *MockClient) GetPrompt(ctx context.Context, req mcp.PromptGetRequest) (*mcp.PromptGetResponse, error) {
+	c.mu.RLock()
+	promptFunc := c.promptFunc
+	c.mu.RUnlock()
+
+	if promptFunc == nil {
+		return nil, fmt.Errorf("prompt not found: %s", req.Name)
+	}
+	return promptFunc(ctx, req)
+}
+
 // Close is a no-op for mock clients.
 func (c *MockClient) Close() error {
 	c.mu.RLock()
@@ -116,10 +172,38 @@ func (c *MockClient) ServerName() string {
 
 // Capabilities returns the mock server capabilities.
 func (c *MockClient) Capabilities() *mcp.ServerCapabilities {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	// Return a basic capabilities structure for testing
-	return &mcp.ServerCapabilities{
+	caps := &mcp.ServerCapabilities{
 		Tools: &mcp.ToolsCapability{},
 	}
+	if c.resources != nil {
+		caps.Resources = &mcp.ResourcesCapability{}
+	}
+	if c.prompts != nil {
+		caps.Prompts = &mcp.PromptsCapability{}
+	}
+	return caps
+}
+
+// SetResources configures the resources exposed by this client.
+// contents maps resource URIs to their text content.
+func (c *MockClient) SetResources(resources []mcp.ResourceDefinition, contents map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resources = resources
+	c.contents = contents
+}
+
+// SetPrompts configures the prompts exposed by this client and the handler
+// used to render them.
+func (c *MockClient) SetPrompts(prompts []mcp.PromptDefinition, f func(ctx context.Context, req mcp.PromptGetRequest) (*mcp.PromptGetResponse, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prompts = prompts
+	c.promptFunc = f
 }
 
 // SetCallHandler sets a custom call handler for this client.
@@ -0,0 +1,282 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client"
+	mcptransport "github.com/mark3labs/mcp-go/client/transport"
+	"golang.org/x/oauth2"
+
+	optransport "github.com/tombee/conductor/internal/operation/transport"
+)
+
+// TransportType identifies how Conductor connects to an MCP server.
+type TransportType string
+
+const (
+	// TransportStdio spawns the server as a subprocess and talks over stdin/stdout.
+	TransportStdio TransportType = "stdio"
+	// TransportStreamableHTTP connects to a remote server using the Streamable HTTP transport.
+	TransportStreamableHTTP TransportType = "http"
+	// TransportSSE connects to a remote server using the legacy HTTP+SSE transport.
+	TransportSSE TransportType = "sse"
+)
+
+// ResolveTransport returns the effective transport for a server definition.
+// An explicit transport wins; otherwise a URL implies Streamable HTTP and
+// anything else is treated as stdio.
+func ResolveTransport(transport TransportType, serverURL string) TransportType {
+	if transport != "" {
+		return transport
+	}
+	if serverURL != "" {
+		return TransportStreamableHTTP
+	}
+	return TransportStdio
+}
+
+// IsRemote returns true if the transport connects to a server over the network.
+func (t TransportType) IsRemote() bool {
+	return t == TransportStreamableHTTP || t == TransportSSE
+}
+
+// ValidateTransport checks that a transport type is known.
+func ValidateTransport(t TransportType) error {
+	switch t {
+	case "", TransportStdio, TransportStreamableHTTP, TransportSSE:
+		return nil
+	default:
+		return fmt.Errorf("invalid transport: %s (must be 'stdio', 'http', or 'sse')", t)
+	}
+}
+
+// ValidateURL checks that a remote MCP server URL is well-formed.
+func ValidateURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url must start with http:// or https://")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url must include a host")
+	}
+	return nil
+}
+
+// AuthType identifies the authentication scheme for a remote MCP server.
+type AuthType string
+
+const (
+	// AuthBearer sends a static bearer token in the Authorization header.
+	AuthBearer AuthType = "bearer"
+	// AuthOAuth2 obtains and refreshes access tokens using an OAuth2 flow.
+	AuthOAuth2 AuthType = "oauth2"
+)
+
+// AuthConfig configures authentication for remote MCP servers.
+// String values support ${VAR} syntax, expanded when the connection is opened.
+type AuthConfig struct {
+	// Type is the auth scheme: "bearer" or "oauth2"
+	Type AuthType `yaml:"type" json:"type"`
+
+	// Token is the bearer token (for type="bearer")
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// Flow is the OAuth2 flow: "client_credentials" (default) or "authorization_code"
+	Flow string `yaml:"flow,omitempty" json:"flow,omitempty"`
+
+	// ClientID is the OAuth2 client ID
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// TokenURL is the OAuth2 token endpoint
+	TokenURL string `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+
+	// RefreshToken is the refresh token for the authorization_code flow
+	RefreshToken string `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+
+	// Scopes are the OAuth2 scopes to request
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+}
+
+// Validate checks that the auth configuration is complete.
+func (a *AuthConfig) Validate() error {
+	switch a.Type {
+	case AuthBearer:
+		if a.Token == "" {
+			return fmt.Errorf("token is required for bearer auth")
+		}
+	case AuthOAuth2:
+		if a.ClientID == "" {
+			return fmt.Errorf("client_id is required for oauth2 auth")
+		}
+		if a.ClientSecret == "" {
+			return fmt.Errorf("client_secret is required for oauth2 auth")
+		}
+		if a.TokenURL == "" {
+			return fmt.Errorf("token_url is required for oauth2 auth")
+		}
+		switch a.Flow {
+		case "", "client_credentials":
+		case "authorization_code":
+			if a.RefreshToken == "" {
+				return fmt.Errorf("refresh_token is required for authorization_code flow")
+			}
+		default:
+			return fmt.Errorf("invalid oauth2 flow: %s (must be 'client_credentials' or 'authorization_code')", a.Flow)
+		}
+	default:
+		return fmt.Errorf("invalid auth type: %q (must be 'bearer' or 'oauth2')", a.Type)
+	}
+	return nil
+}
+
+// newMCPClient creates the underlying protocol client for the configured transport.
+// For stdio servers it also returns a handle to the spawned process.
+func newMCPClient(ctx context.Context, config ClientConfig) (*client.Client, ProcessHandle, error) {
+	var opts []client.ClientOption
+	if config.Sampling != nil {
+		opts = append(opts, client.WithSamplingHandler(&samplingAdapter{
+			serverName: config.ServerName,
+			handler:    config.Sampling,
+		}))
+	}
+
+	switch ResolveTransport(config.Transport, config.URL) {
+	case TransportStdio:
+		proc := &stdioProcess{}
+		trans := mcptransport.NewStdioWithOptions(config.Command, config.Env, config.Args,
+			mcptransport.WithCommandFunc(proc.command))
+		return client.NewClient(trans, opts...), proc, nil
+
+	case TransportStreamableHTTP:
+		httpClient, err := newRemoteHTTPClient(ctx, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		trans, err := mcptransport.NewStreamableHTTP(config.URL,
+			mcptransport.WithHTTPBasicClient(httpClient),
+			mcptransport.WithHTTPHeaders(config.Headers),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client.NewClient(trans, opts...), nil, nil
+
+	case TransportSSE:
+		httpClient, err := newRemoteHTTPClient(ctx, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		trans, err := mcptransport.NewSSE(config.URL,
+			mcptransport.WithHTTPClient(httpClient),
+			mcptransport.WithHeaders(config.Headers),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client.NewClient(trans, opts...), nil, nil
+
+	default:
+		return nil, nil, ValidateTransport(config.Transport)
+	}
+}
+
+// newRemoteHTTPClient builds the HTTP client used by remote transports,
+// wrapping it with bearer or OAuth2 authentication when configured.
+func newRemoteHTTPClient(ctx context.Context, config ClientConfig) (*http.Client, error) {
+	if config.Auth == nil {
+		return &http.Client{}, nil
+	}
+	if err := config.Auth.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	var ts oauth2.TokenSource
+	switch config.Auth.Type {
+	case AuthBearer:
+		ts = oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: config.Auth.Token,
+			TokenType:   "Bearer",
+		})
+	case AuthOAuth2:
+		flow := config.Auth.Flow
+		if flow == "" {
+			flow = "client_credentials"
+		}
+		source, err := optransport.NewOAuth2TokenSource(context.WithoutCancel(ctx), &optransport.OAuth2TransportConfig{
+			BaseURL:      config.URL,
+			Flow:         flow,
+			ClientID:     config.Auth.ClientID,
+			ClientSecret: config.Auth.ClientSecret,
+			TokenURL:     config.Auth.TokenURL,
+			RefreshToken: config.Auth.RefreshToken,
+			Scopes:       config.Auth.Scopes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ts = oauth2.ReuseTokenSource(nil, source)
+	}
+
+	return &http.Client{
+		Transport: &oauth2.Transport{Source: ts, Base: http.DefaultTransport},
+	}, nil
+}
+
+// stdioProcess captures the subprocess spawned by the stdio transport so it
+// can be force-killed during shutdown.
+type stdioProcess struct {
+	mu  sync.Mutex
+	cmd *exec.Cmd
+}
+
+// command builds the server subprocess, mirroring the stdio transport's default.
+func (p *stdioProcess) command(ctx context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	p.mu.Lock()
+	p.cmd = cmd
+	p.mu.Unlock()
+
+	return cmd, nil
+}
+
+// Kill terminates the subprocess if it has been started.
+func (p *stdioProcess) Kill() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+	return p.cmd.Process.Kill()
+}
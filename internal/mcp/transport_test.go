@@ -0,0 +1,277 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newTestMCPServer returns an MCP server exposing a single "echo" tool.
+func newTestMCPServer() *server.MCPServer {
+	s := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+	s.AddTool(mcp.NewTool("echo",
+		mcp.WithDescription("Echo a message"),
+		mcp.WithString("message", mcp.Required()),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(req.GetString("message", "")), nil
+	})
+	return s
+}
+
+func TestResolveTransport(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport TransportType
+		url       string
+		want      TransportType
+	}{
+		{"default is stdio", "", "", TransportStdio},
+		{"url implies http", "", "https://example.com/mcp", TransportStreamableHTTP},
+		{"explicit sse", TransportSSE, "https://example.com/sse", TransportSSE},
+		{"explicit stdio", TransportStdio, "", TransportStdio},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveTransport(tt.transport, tt.url); got != tt.want {
+				t.Errorf("ResolveTransport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    AuthConfig
+		wantErr bool
+	}{
+		{"bearer ok", AuthConfig{Type: AuthBearer, Token: "${TOKEN}"}, false},
+		{"bearer missing token", AuthConfig{Type: AuthBearer}, true},
+		{"oauth2 ok", AuthConfig{Type: AuthOAuth2, ClientID: "id", ClientSecret: "${SECRET}", TokenURL: "https://auth/token"}, false},
+		{"oauth2 missing token url", AuthConfig{Type: AuthOAuth2, ClientID: "id", ClientSecret: "s"}, true},
+		{"oauth2 auth code needs refresh", AuthConfig{Type: AuthOAuth2, Flow: "authorization_code", ClientID: "id", ClientSecret: "s", TokenURL: "https://auth/token"}, true},
+		{"unknown type", AuthConfig{Type: "basic"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClient_StreamableHTTP(t *testing.T) {
+	var authHeader atomic.Value
+	authHeader.Store("")
+
+	httpServer := server.NewTestStreamableHTTPServer(newTestMCPServer())
+	defer httpServer.Close()
+
+	// Record the Authorization header seen by the server
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader.Store(r.Header.Get("Authorization"))
+		r.URL.Scheme = "http"
+		r.URL.Host = httpServer.Listener.Addr().String()
+		httpServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	// Values are sent as-is; secret references are resolved by bindings
+	t.Setenv("TEST_MCP_TOKEN", "from-environment")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, ClientConfig{
+		ServerName: "remote",
+		URL:        proxy.URL + "/mcp",
+		Auth:       &AuthConfig{Type: AuthBearer, Token: "pa$$${TEST_MCP_TOKEN}"},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.Process() != nil {
+		t.Error("remote client should not expose a process handle")
+	}
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "echo" {
+		t.Fatalf("ListTools() = %+v, want single echo tool", tools)
+	}
+
+	resp, err := client.CallTool(ctx, ToolCallRequest{Name: "echo", Arguments: map[string]interface{}{"message": "hi"}})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if len(resp.Content) != 1 || resp.Content[0].Text != "hi" {
+		t.Errorf("CallTool() = %+v, want echo of 'hi'", resp.Content)
+	}
+
+	if got := authHeader.Load().(string); got != "Bearer pa$$${TEST_MCP_TOKEN}" {
+		t.Errorf("Authorization header = %q, want %q", got, "Bearer pa$$${TEST_MCP_TOKEN}")
+	}
+}
+
+func TestNewClient_SSE(t *testing.T) {
+	sseServer := server.NewTestServer(newTestMCPServer())
+	defer sseServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, ClientConfig{
+		ServerName: "legacy",
+		Transport:  TransportSSE,
+		URL:        sseServer.URL + "/sse",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// The event stream must survive the connect context being cancelled
+	cancel()
+
+	pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer pingCancel()
+	if err := client.Ping(pingCtx); err != nil {
+		t.Fatalf("Ping() after connect error = %v", err)
+	}
+}
+
+func TestNewClient_RemoteValidation(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewClient(ctx, ClientConfig{ServerName: "remote", Transport: TransportStreamableHTTP})
+	if err == nil || err.Error() != "url is required" {
+		t.Errorf("NewClient() error = %v, want 'url is required'", err)
+	}
+
+	_, err = NewClient(ctx, ClientConfig{ServerName: "remote", URL: "ftp://example.com"})
+	if err == nil {
+		t.Error("NewClient() expected error for non-http url")
+	}
+}
+
+func TestManager_RemoteServer(t *testing.T) {
+	httpServer := server.NewTestStreamableHTTPServer(newTestMCPServer())
+	defer httpServer.Close()
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	if err := mgr.Start(ServerConfig{
+		Name:                "remote",
+		URL:                 httpServer.URL + "/mcp",
+		HealthCheckInterval: 50 * time.Millisecond,
+	}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !mgr.IsRunning("remote") {
+		if time.Now().After(deadline) {
+			t.Fatal("remote server did not become ready")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	status, err := mgr.GetStatus("remote")
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.ToolCount == nil || *status.ToolCount != 1 {
+		t.Errorf("ToolCount = %v, want 1", status.ToolCount)
+	}
+
+	// Tools registered through a managed client keep working across health checks
+	managed := NewManagedClient(mgr, "remote")
+	time.Sleep(150 * time.Millisecond)
+	tools, err := managed.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools() via managed client error = %v", err)
+	}
+	if len(tools) != 1 {
+		t.Errorf("ListTools() returned %d tools, want 1", len(tools))
+	}
+}
+
+func TestHealthCheckInterval(t *testing.T) {
+	if got := healthCheckInterval(ServerConfig{Command: "echo"}); got != 0 {
+		t.Errorf("stdio default interval = %v, want 0", got)
+	}
+	if got := healthCheckInterval(ServerConfig{URL: "https://example.com/mcp"}); got != defaultRemoteHealthCheckInterval {
+		t.Errorf("remote default interval = %v, want %v", got, defaultRemoteHealthCheckInterval)
+	}
+	if got := healthCheckInterval(ServerConfig{Command: "echo", HealthCheckInterval: time.Second}); got != time.Second {
+		t.Errorf("explicit interval = %v, want 1s", got)
+	}
+}
+
+func TestMCPServerEntryValidate_Remote(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   MCPServerEntry
+		wantErr bool
+	}{
+		{"url only", MCPServerEntry{URL: "https://example.com/mcp"}, false},
+		{"sse transport", MCPServerEntry{Transport: TransportSSE, URL: "https://example.com/sse"}, false},
+		{"http transport without url", MCPServerEntry{Transport: TransportStreamableHTTP}, true},
+		{"url with command", MCPServerEntry{URL: "https://example.com/mcp", Command: "echo"}, true},
+		{"url with stdio transport", MCPServerEntry{Transport: TransportStdio, Command: "echo", URL: "https://example.com/mcp"}, true},
+		{"invalid auth", MCPServerEntry{URL: "https://example.com/mcp", Auth: &AuthConfig{Type: AuthBearer}}, true},
+		{"unknown transport", MCPServerEntry{Transport: "websocket", URL: "https://example.com/mcp"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.entry.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	got := RedactHeaders(map[string]string{
+		"Authorization": "Bearer abc",
+		"X-Api-Key":     "abc",
+		"X-Tenant":      "acme",
+	})
+	if got["Authorization"] != "***REDACTED***" || got["X-Api-Key"] != "***REDACTED***" {
+		t.Errorf("sensitive headers not redacted: %v", got)
+	}
+	if got["X-Tenant"] != "acme" {
+		t.Errorf("X-Tenant = %q, want acme", got["X-Tenant"])
+	}
+}
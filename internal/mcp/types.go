@@ -8,6 +8,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 // ToolDefinition represents an MCP tool definition.
@@ -98,6 +99,69 @@ type ResourceContent struct {
 	Blob string `json:"blob,omitempty"`
 }
 
+// PromptDefinition represents an MCP prompt template offered by a server.
+type PromptDefinition struct {
+	// Name is the unique identifier for this prompt
+	Name string `json:"name"`
+
+	// Description explains what this prompt is for
+	Description string `json:"description,omitempty"`
+
+	// Arguments lists the values used to render the prompt
+	Arguments []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument describes an argument accepted by an MCP prompt.
+type PromptArgument struct {
+	// Name is the argument name
+	Name string `json:"name"`
+
+	// Description explains what the argument is for
+	Description string `json:"description,omitempty"`
+
+	// Required indicates the argument must be provided
+	Required bool `json:"required,omitempty"`
+}
+
+// PromptGetRequest represents a request to render an MCP prompt.
+type PromptGetRequest struct {
+	// Name is the prompt to render
+	Name string `json:"name"`
+
+	// Arguments are the values substituted into the prompt template
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// PromptGetResponse represents a rendered MCP prompt.
+type PromptGetResponse struct {
+	// Description is an optional description of the rendered prompt
+	Description string `json:"description,omitempty"`
+
+	// Messages contains the rendered prompt messages
+	Messages []PromptMessage `json:"messages"`
+}
+
+// PromptMessage is a single message in a rendered MCP prompt.
+type PromptMessage struct {
+	// Role is the message author (user or assistant)
+	Role string `json:"role"`
+
+	// Content is the message content
+	Content ContentItem `json:"content"`
+}
+
+// Text returns the text content of all messages, separated by blank lines.
+// Non-text content (images, audio) is skipped.
+func (r *PromptGetResponse) Text() string {
+	parts := make([]string, 0, len(r.Messages))
+	for _, msg := range r.Messages {
+		if msg.Content.Text != "" {
+			parts = append(parts, msg.Content.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
 // ServerCapabilities describes what features an MCP server supports.
 type ServerCapabilities struct {
 	// Tools indicates if the server provides tools
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/tombee/conductor/internal/action/email"
 	"github.com/tombee/conductor/internal/action/file"
 	"github.com/tombee/conductor/internal/action/http"
 	"github.com/tombee/conductor/internal/action/shell"
@@ -56,6 +57,9 @@ type BuiltinConfig struct {
 
 	// SecurityConfig provides HTTP security validation
 	SecurityConfig *security.HTTPSecurityConfig
+
+	// SMTP is the default mail server for the email action
+	SMTP email.SMTPConfig
 }
 
 // builtinNames lists all builtin action names.
@@ -65,6 +69,7 @@ var builtinNames = map[string]bool{
 	"transform": true,
 	"utility":   true,
 	"http":      true,
+	"email":     true,
 }
 
 // IsBuiltin returns true if the action name is a builtin.
@@ -80,6 +85,7 @@ type BuiltinProvider struct {
 	transformAction *transform.TransformAction
 	utilityAction   *utility.UtilityAction
 	httpAction      *http.HTTPAction
+	emailAction     *email.EmailAction
 }
 
 // NewBuiltin creates a builtin action by name.
@@ -167,6 +173,25 @@ func NewBuiltin(name string, config *BuiltinConfig) (Provider, error) {
 			httpAction: hc,
 		}, nil
 
+	case "email":
+		emailConfig := &email.Config{
+			SMTP:           config.SMTP,
+			WorkflowDir:    config.WorkflowDir,
+			OutputDir:      config.OutputDir,
+			TempDir:        config.TempDir,
+			DNSMonitor:     config.DNSMonitor,
+			SecurityConfig: config.SecurityConfig,
+		}
+		ec, err := email.New(emailConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email action: %w", err)
+		}
+
+		return &BuiltinProvider{
+			name:        "email",
+			emailAction: ec,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unknown builtin action: %s", name)
 	}
@@ -230,6 +255,16 @@ func (c *BuiltinProvider) Execute(ctx context.Context, operation string, inputs
 			Metadata: result.Metadata,
 		}, nil
 
+	case "email":
+		result, err := c.emailAction.Execute(ctx, operation, inputs)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{
+			Response: result.Response,
+			Metadata: result.Metadata,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unknown builtin action: %s", c.name)
 	}
@@ -261,6 +296,8 @@ func GetBuiltinOperations(name string) []string {
 		return []string{
 			"get", "post", "put", "patch", "delete", "request",
 		}
+	case "email":
+		return []string{"send"}
 	default:
 		return nil
 	}
@@ -279,6 +316,8 @@ func GetBuiltinDescription(name string) string {
 		return "Utility functions (random, ID generation, math operations)"
 	case "http":
 		return "HTTP requests (GET, POST, PUT, PATCH, DELETE with security controls)"
+	case "email":
+		return "Email sending over SMTP (STARTTLS, auth, templated bodies, attachments)"
 	default:
 		return ""
 	}
@@ -465,7 +465,7 @@ func TestNewBuiltinRegistry(t *testing.T) {
 
 	// Verify all builtin operations are registered
 	names := registry.List()
-	expected := []string{"file", "shell", "transform", "utility", "http"}
+	expected := []string{"file", "shell", "transform", "utility", "http", "email"}
 
 	if len(names) != len(expected) {
 		t.Errorf("expected %d operations, got %d", len(expected), len(names))
@@ -408,9 +408,42 @@ func (e *httpExecutor) buildURL(inputs map[string]interface{}) (string, error) {
 	baseURL = strings.TrimSuffix(baseURL, "/")
 	path = "/" + strings.TrimPrefix(path, "/")
 
+	// Methods without a body send the declared inputs as query parameters
+	if !e.requiresBody() {
+		if query := e.buildQuery(inputs); query != "" {
+			separator := "?"
+			if strings.Contains(path, "?") {
+				separator = "&"
+			}
+			path += separator + query
+		}
+	}
+
 	return baseURL + path, nil
 }
 
+// buildQuery encodes the inputs declared in the operation's request schema
+// that are not path parameters as a query string. List inputs repeat the
+// parameter once per element.
+func (e *httpExecutor) buildQuery(inputs map[string]interface{}) string {
+	pathParams := e.getPathParameters()
+	declared, _ := e.operation.RequestSchema["properties"].(map[string]interface{})
+	query := url.Values{}
+	for key, value := range inputs {
+		if _, ok := declared[key]; !ok || pathParams[key] || value == nil {
+			continue
+		}
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				query.Add(key, fmt.Sprintf("%v", item))
+			}
+			continue
+		}
+		query.Set(key, fmt.Sprintf("%v", value))
+	}
+	return query.Encode()
+}
+
 // buildBody creates the request body from inputs.
 func (e *httpExecutor) buildBody(inputs map[string]interface{}) (io.Reader, error) {
 	// Filter out path parameters from body
@@ -246,3 +246,87 @@ func TestApplyHeaders_ValidationAndProtection(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildURL_QueryParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		inputs map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "GET sends declared inputs as query",
+			method: "GET",
+			path:   "/projects/{project}/tickets",
+			inputs: map[string]interface{}{"project": "api", "status": "open", "since": "2025-06-01T12:00:00Z"},
+			want:   "https://tickets.example.com/projects/api/tickets?since=2025-06-01T12%3A00%3A00Z&status=open",
+		},
+		{
+			name:   "GET appends to an existing query",
+			method: "GET",
+			path:   "/tickets?expand=owner",
+			inputs: map[string]interface{}{"labels": []interface{}{"bug", "urgent"}},
+			want:   "https://tickets.example.com/tickets?expand=owner&labels=bug&labels=urgent",
+		},
+		{
+			name:   "GET leaves out undeclared inputs",
+			method: "GET",
+			path:   "/tickets",
+			inputs: map[string]interface{}{"status": "open", "token": "secret"},
+			want:   "https://tickets.example.com/tickets?status=open",
+		},
+		{
+			name:   "POST keeps inputs in the body",
+			method: "POST",
+			path:   "/tickets",
+			inputs: map[string]interface{}{"title": "Broken"},
+			want:   "https://tickets.example.com/tickets",
+		},
+	}
+	schema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"project": map[string]interface{}{"type": "string"},
+			"status":  map[string]interface{}{"type": "string"},
+			"since":   map[string]interface{}{"type": "string"},
+			"labels":  map[string]interface{}{"type": "array"},
+			"title":   map[string]interface{}{"type": "string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &httpExecutor{
+				integration: &httpProvider{
+					def: &workflow.IntegrationDefinition{BaseURL: "https://tickets.example.com"},
+				},
+				operation: &workflow.OperationDefinition{Method: tt.method, Path: tt.path, RequestSchema: schema},
+			}
+
+			got, err := executor.buildURL(tt.inputs)
+			if err != nil {
+				t.Fatalf("buildURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildURL_NoRequestSchema(t *testing.T) {
+	executor := &httpExecutor{
+		integration: &httpProvider{
+			def: &workflow.IntegrationDefinition{BaseURL: "https://tickets.example.com"},
+		},
+		operation: &workflow.OperationDefinition{Method: "GET", Path: "/tickets/{id}"},
+	}
+
+	got, err := executor.buildURL(map[string]interface{}{"id": "T-1", "status": "open"})
+	if err != nil {
+		t.Fatalf("buildURL() error = %v", err)
+	}
+	if want := "https://tickets.example.com/tickets/T-1"; got != want {
+		t.Errorf("buildURL() = %q, want %q", got, want)
+	}
+}
@@ -43,6 +43,7 @@ var builtinIntegrationInfo = map[string]struct {
 	description string
 }{
 	"github":     {baseURL: "https://api.github.com", description: "GitHub REST API v3"},
+	"gitlab":     {baseURL: "https://gitlab.com/api/v4", description: "GitLab REST API v4"},
 	"slack":      {baseURL: "https://slack.com/api", description: "Slack Web API"},
 	"jira":       {baseURL: "https://your-domain.atlassian.net", description: "Jira Cloud REST API v3"},
 	"discord":    {baseURL: "https://discord.com/api/v10", description: "Discord REST API v10"},
@@ -51,7 +52,7 @@ var builtinIntegrationInfo = map[string]struct {
 }
 
 // loadBundledPackage loads a bundled integration.
-// For Go-based builtin integrations (github, slack, jira, discord, jenkins),
+// For Go-based builtin integrations (github, gitlab, slack, jira, discord, jenkins),
 // this returns metadata from the builtin integration info.
 func loadBundledPackage(from string) (*PackageDefinition, error) {
 	// Extract integration name from "integrations/<name>"
@@ -92,7 +93,7 @@ func loadBundledPackage(from string) (*PackageDefinition, error) {
 	return nil, &Error{
 		Type:        ErrorTypeNotFound,
 		Message:     fmt.Sprintf("bundled integration %q not found", integrationName),
-		SuggestText: "available bundled integrations: github, slack, jira, discord, jenkins",
+		SuggestText: "available bundled integrations: github, gitlab, slack, jira, discord, jenkins",
 	}
 }
 
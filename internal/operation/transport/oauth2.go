@@ -130,9 +130,30 @@ func NewOAuth2Transport(cfg *OAuth2TransportConfig) (*OAuth2Transport, error) {
 	transport.refreshCond = sync.NewCond(&transport.tokenMutex)
 
 	// Set up token source based on flow
-	var tokenSource oauth2.TokenSource
-	ctx := context.Background()
+	tokenSource, err := NewOAuth2TokenSource(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.tokenSource = tokenSource
+
+	// Acquire initial token
+	if err := transport.refreshToken(context.Background()); err != nil {
+		return nil, &TransportError{
+			Type:      ErrorTypeAuth,
+			Message:   fmt.Sprintf("failed to acquire OAuth2 token: %v", err),
+			Retryable: false,
+			Cause:     err,
+		}
+	}
+
+	return transport, nil
+}
 
+// NewOAuth2TokenSource creates a token source for the configured OAuth2 flow.
+// Callers that manage their own HTTP client (e.g. MCP remote transports) can use
+// it to share the flow setup without going through the Transport interface.
+func NewOAuth2TokenSource(ctx context.Context, cfg *OAuth2TransportConfig) (oauth2.TokenSource, error) {
 	switch cfg.Flow {
 	case "client_credentials":
 		ccConfig := &clientcredentials.Config{
@@ -141,7 +162,7 @@ func NewOAuth2Transport(cfg *OAuth2TransportConfig) (*OAuth2Transport, error) {
 			TokenURL:     cfg.TokenURL,
 			Scopes:       cfg.Scopes,
 		}
-		tokenSource = ccConfig.TokenSource(ctx)
+		return ccConfig.TokenSource(ctx), nil
 
 	case "authorization_code":
 		oauthConfig := &oauth2.Config{
@@ -157,25 +178,11 @@ func NewOAuth2Transport(cfg *OAuth2TransportConfig) (*OAuth2Transport, error) {
 		token := &oauth2.Token{
 			RefreshToken: cfg.RefreshToken,
 		}
-		tokenSource = oauthConfig.TokenSource(ctx, token)
+		return oauthConfig.TokenSource(ctx, token), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported OAuth2 flow: %s", cfg.Flow)
 	}
-
-	transport.tokenSource = tokenSource
-
-	// Acquire initial token
-	if err := transport.refreshToken(context.Background()); err != nil {
-		return nil, &TransportError{
-			Type:      ErrorTypeAuth,
-			Message:   fmt.Sprintf("failed to acquire OAuth2 token: %v", err),
-			Retryable: false,
-			Cause:     err,
-		}
-	}
-
-	return transport, nil
 }
 
 // refreshToken acquires a new access token.
@@ -16,6 +16,13 @@ type TestConfig struct {
 	// PostgresURL is the connection string for Postgres integration tests.
 	PostgresURL string
 
+	// RedisURL is the address of a Redis server for queue trigger tests.
+	RedisURL string
+
+	// AMQPURL is the address of an AMQP broker, such as RabbitMQ, for queue
+	// trigger tests.
+	AMQPURL string
+
 	// OllamaURL is the base URL for Ollama tests (defaults to http://localhost:11434).
 	OllamaURL string
 }
@@ -27,6 +34,8 @@ func LoadConfig() *TestConfig {
 		AnthropicAPIKey: os.Getenv("ANTHROPIC_API_KEY"),
 		OpenAIAPIKey:    os.Getenv("OPENAI_API_KEY"),
 		PostgresURL:     os.Getenv("POSTGRES_URL"),
+		RedisURL:        os.Getenv("REDIS_URL"),
+		AMQPURL:         os.Getenv("AMQP_URL"),
 		OllamaURL:       os.Getenv("OLLAMA_URL"),
 	}
 
@@ -0,0 +1,46 @@
+package triggers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tombee/conductor/internal/controller/polltrigger"
+	"github.com/tombee/conductor/pkg/workflow"
+)
+
+// ListPollTriggers returns the poll triggers declared by workflows in the
+// workflows directory. It scans the directory the same way the controller
+// does, so the trigger IDs match those used for poll state.
+func (m *Manager) ListPollTriggers(ctx context.Context) ([]*polltrigger.PollTriggerRegistration, error) {
+	entries, err := os.ReadDir(m.workflowsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflows directory: %w", err)
+	}
+
+	var triggers []*polltrigger.PollTriggerRegistration
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") && !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+
+		workflowPath := filepath.Join(m.workflowsDir, entry.Name())
+		data, err := os.ReadFile(workflowPath)
+		if err != nil {
+			continue
+		}
+
+		// Skip files that are not valid workflows; the controller skips them too
+		wf, err := workflow.ParseDefinition(data)
+		if err != nil {
+			continue
+		}
+
+		regs, _ := polltrigger.WorkflowRegistrations(workflowPath, wf)
+		triggers = append(triggers, regs...)
+	}
+
+	return triggers, nil
+}
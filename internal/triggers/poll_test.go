@@ -0,0 +1,55 @@
+package triggers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListPollTriggers(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"triage.yaml": `
+name: triage
+trigger:
+  poll:
+    integration: github
+    interval: 1m
+    query:
+      repo: acme/api
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+		"manual.yaml": `
+name: manual
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+		"notes.txt": "not a workflow",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mgr := NewManager(filepath.Join(tmpDir, "config.yaml"), tmpDir)
+	triggers, err := mgr.ListPollTriggers(context.Background())
+	if err != nil {
+		t.Fatalf("ListPollTriggers() error = %v", err)
+	}
+
+	if len(triggers) != 1 {
+		t.Fatalf("got %d triggers, want 1", len(triggers))
+	}
+	want := filepath.Join(tmpDir, "triage.yaml") + ":github"
+	if triggers[0].TriggerID != want || triggers[0].Interval != 60 {
+		t.Errorf("trigger = %+v, want ID %q and 60s interval", triggers[0], want)
+	}
+}
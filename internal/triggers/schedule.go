@@ -3,8 +3,10 @@ package triggers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tombee/conductor/internal/config"
+	"github.com/tombee/conductor/internal/controller/scheduler"
 )
 
 // AddSchedule adds a new schedule trigger to the configuration.
@@ -18,34 +20,8 @@ func (m *Manager) AddSchedule(ctx context.Context, req CreateScheduleRequest) er
 		return fmt.Errorf("schedule name cannot be empty")
 	}
 
-	// Determine cron expression and timezone
-	var cronExpr, timezone string
-	var err error
-
-	if req.Cron != "" && (req.Every != "" || req.At != "") {
-		return fmt.Errorf("cannot use both --cron and --every/--at")
-	}
-
-	if req.Cron != "" {
-		cronExpr = req.Cron
-		timezone = req.Timezone
-		if timezone == "" {
-			timezone = "UTC"
-		}
-	} else {
-		cronExpr, timezone, err = ParseEverySchedule(req.Every, req.At, req.Timezone)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Validate cron expression
-	if err := ValidateCron(cronExpr); err != nil {
-		return err
-	}
-
-	// Validate timezone
-	if err := ValidateTimezone(timezone); err != nil {
+	newSchedule, err := scheduleEntry(req)
+	if err != nil {
 		return err
 	}
 
@@ -71,19 +47,131 @@ func (m *Manager) AddSchedule(ctx context.Context, req CreateScheduleRequest) er
 		}
 	}
 
+	// Check excluded calendars exist
+	for _, name := range req.Exclude {
+		if _, ok := cfg.Controller.Schedules.Calendars[name]; !ok {
+			lock.Release()
+			return fmt.Errorf("calendar not found: %s", name)
+		}
+	}
+
 	// Add new schedule
-	newSchedule := config.ScheduleEntry{
+	cfg.Controller.Schedules.Schedules = append(cfg.Controller.Schedules.Schedules, newSchedule)
+
+	// Save config
+	return m.saveConfig(cfg, lock)
+}
+
+// PreviewSchedule returns the next n times the requested schedule would
+// run, skipping the days and periods of the calendars it excludes.
+func (m *Manager) PreviewSchedule(ctx context.Context, req CreateScheduleRequest, n int) ([]time.Time, error) {
+	entry, err := scheduleEntry(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calendars are only needed, and the config only read, if excluded
+	var calendars map[string]scheduler.Calendar
+	if len(entry.Exclude) > 0 {
+		cfg, lock, err := m.loadConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		lock.Release()
+		calendars = make(map[string]scheduler.Calendar, len(cfg.Controller.Schedules.Calendars))
+		for name, calendar := range cfg.Controller.Schedules.Calendars {
+			cal := scheduler.Calendar{Dates: calendar.Dates}
+			for _, period := range calendar.Ranges {
+				cal.Ranges = append(cal.Ranges, scheduler.CalendarRange{From: period.From, To: period.To})
+			}
+			calendars[name] = cal
+		}
+	}
+
+	return scheduler.Preview(scheduler.Schedule{
+		Cron:     entry.Cron,
+		At:       entry.At,
+		Timezone: entry.Timezone,
+		Exclude:  entry.Exclude,
+	}, calendars, time.Now(), n)
+}
+
+// scheduleEntry validates a schedule request and converts it to its config
+// entry. The schedule is a cron expression (--cron), a human-friendly
+// schedule (--every and --at) or a one-off time (--at with a date).
+func scheduleEntry(req CreateScheduleRequest) (config.ScheduleEntry, error) {
+	var cronExpr, at, timezone string
+	var err error
+
+	if req.Cron != "" && (req.Every != "" || req.At != "") {
+		return config.ScheduleEntry{}, fmt.Errorf("cannot use both --cron and --every/--at")
+	}
+
+	switch {
+	case req.Cron != "":
+		cronExpr = req.Cron
+		timezone = req.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+	case req.Every == "" && IsOneOffTime(req.At):
+		at = req.At
+		timezone = req.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+	default:
+		cronExpr, timezone, err = ParseEverySchedule(req.Every, req.At, req.Timezone)
+		if err != nil {
+			return config.ScheduleEntry{}, err
+		}
+	}
+
+	// Validate timezone
+	if err := ValidateTimezone(timezone); err != nil {
+		return config.ScheduleEntry{}, err
+	}
+
+	// Validate the cron expression or one-off time
+	if at != "" {
+		loc, _ := time.LoadLocation(timezone)
+		if _, err := scheduler.ParseAt(at, loc); err != nil {
+			return config.ScheduleEntry{}, err
+		}
+	} else if err := ValidateCron(cronExpr); err != nil {
+		return config.ScheduleEntry{}, err
+	}
+
+	// Validate policies
+	switch req.Catchup {
+	case "", "none", "last", "all":
+	default:
+		return config.ScheduleEntry{}, fmt.Errorf("invalid catchup policy %q (must be none, last or all)", req.Catchup)
+	}
+	switch req.Overlap {
+	case "", "allow", "skip", "queue":
+	default:
+		return config.ScheduleEntry{}, fmt.Errorf("invalid overlap policy %q (must be allow, skip or queue)", req.Overlap)
+	}
+	if req.CatchupWindow < 0 || req.Jitter < 0 {
+		return config.ScheduleEntry{}, fmt.Errorf("catchup window and jitter must be non-negative")
+	}
+
+	return config.ScheduleEntry{
 		Name:     req.Name,
 		Cron:     cronExpr,
+		At:       at,
 		Workflow: req.Workflow,
 		Inputs:   req.Inputs,
 		Enabled:  true,
 		Timezone: timezone,
-	}
-	cfg.Controller.Schedules.Schedules = append(cfg.Controller.Schedules.Schedules, newSchedule)
 
-	// Save config
-	return m.saveConfig(cfg, lock)
+		Catchup:       req.Catchup,
+		CatchupWindow: req.CatchupWindow,
+		Overlap:       req.Overlap,
+		Jitter:        req.Jitter,
+		Exclude:       req.Exclude,
+	}, nil
 }
 
 // ListSchedules returns all configured schedule triggers.
@@ -99,10 +187,12 @@ func (m *Manager) ListSchedules(ctx context.Context) ([]ScheduleTrigger, error)
 		triggers = append(triggers, ScheduleTrigger{
 			Name:     schedule.Name,
 			Cron:     schedule.Cron,
+			At:       schedule.At,
 			Workflow: schedule.Workflow,
 			Inputs:   schedule.Inputs,
 			Enabled:  schedule.Enabled,
 			Timezone: schedule.Timezone,
+			Exclude:  schedule.Exclude,
 		})
 	}
 
@@ -5,17 +5,30 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var timeFormatRegex = regexp.MustCompile(`^([0-1]?[0-9]|2[0-3]):([0-5][0-9])$`)
 
 // ParseEverySchedule converts human-friendly schedule syntax to cron expression.
-// Supports: hour, day, week, month with optional --at time.
+// Supports: hour, day, week, month with optional --at time, or an interval
+// such as 90m, which becomes an @every expression.
 func ParseEverySchedule(every, at, timezone string) (cron, tz string, err error) {
 	if every == "" {
 		return "", "", fmt.Errorf("--every is required when not using --cron")
 	}
 
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	if _, err := time.ParseDuration(every); err == nil {
+		if at != "" {
+			return "", "", fmt.Errorf("--at not supported with an --every interval")
+		}
+		return "@every " + every, timezone, nil
+	}
+
 	var hour, minute int
 	if at != "" {
 		matches := timeFormatRegex.FindStringSubmatch(at)
@@ -39,12 +52,14 @@ func ParseEverySchedule(every, at, timezone string) (cron, tz string, err error)
 	case "month":
 		cron = fmt.Sprintf("%d %d 1 * *", minute, hour) // Monthly on the 1st
 	default:
-		return "", "", fmt.Errorf("invalid --every value, must be: hour, day, week, or month")
-	}
-
-	if timezone == "" {
-		timezone = "UTC"
+		return "", "", fmt.Errorf("invalid --every value, must be: hour, day, week, month, or an interval such as 90m")
 	}
 
 	return cron, timezone, nil
 }
+
+// IsOneOffTime reports whether at is a date and time (2026-11-01T09:00)
+// for a one-off schedule rather than a time of day.
+func IsOneOffTime(at string) bool {
+	return strings.Contains(at, "-")
+}
@@ -52,6 +52,23 @@ func TestParseEverySchedule(t *testing.T) {
 			wantTZ:   "Europe/London",
 			wantErr:  false,
 		},
+		{
+			name:     "interval",
+			every:    "90m",
+			at:       "",
+			timezone: "",
+			wantCron: "@every 90m",
+			wantTZ:   "UTC",
+			wantErr:  false,
+		},
+		{
+			name:        "interval with at not supported",
+			every:       "90m",
+			at:          "09:00",
+			timezone:    "",
+			wantErr:     true,
+			errContains: "--at not supported with an --every interval",
+		},
 		{
 			name:        "hour with at not supported",
 			every:       "hour",
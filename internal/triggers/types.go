@@ -15,11 +15,13 @@ type WebhookTrigger struct {
 // ScheduleTrigger represents a schedule trigger configuration.
 type ScheduleTrigger struct {
 	Name     string         `json:"name"`
-	Cron     string         `json:"cron"`
+	Cron     string         `json:"cron,omitempty"`
+	At       string         `json:"at,omitempty"`
 	Workflow string         `json:"workflow"`
 	Inputs   map[string]any `json:"inputs,omitempty"`
 	Enabled  bool           `json:"enabled"`
 	Timezone string         `json:"timezone,omitempty"`
+	Exclude  []string       `json:"exclude,omitempty"`
 }
 
 // EndpointTrigger represents an API endpoint trigger configuration.
@@ -53,6 +55,14 @@ type CreateScheduleRequest struct {
 	At       string         `json:"at,omitempty"`
 	Timezone string         `json:"timezone,omitempty"`
 	Inputs   map[string]any `json:"inputs,omitempty"`
+
+	Catchup       string        `json:"catchup,omitempty"`
+	CatchupWindow time.Duration `json:"catchup_window,omitempty"`
+	Overlap       string        `json:"overlap,omitempty"`
+	Jitter        time.Duration `json:"jitter,omitempty"`
+
+	// Exclude names the exclusion calendars the schedule does not run in
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 // CreateEndpointRequest is the request to create an endpoint trigger.
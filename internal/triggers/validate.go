@@ -43,7 +43,7 @@ func ValidateCron(expr string) error {
 
 	_, err := scheduler.ParseCron(expr)
 	if err != nil {
-		return fmt.Errorf("invalid cron expression: %s", expr)
+		return fmt.Errorf("invalid cron expression %q: %w", expr, err)
 	}
 
 	return nil
@@ -73,6 +73,21 @@ func TestValidateCron(t *testing.T) {
 			expr:    "@hourly",
 			wantErr: false,
 		},
+		{
+			name:    "with seconds",
+			expr:    "*/30 * * * * *",
+			wantErr: false,
+		},
+		{
+			name:    "day modifiers",
+			expr:    "0 17 LW * *",
+			wantErr: false,
+		},
+		{
+			name:    "special @every",
+			expr:    "@every 90m",
+			wantErr: false,
+		},
 		{
 			name:    "empty cron",
 			expr:    "",
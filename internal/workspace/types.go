@@ -174,6 +174,7 @@ const (
 func DefaultBaseURL(integrationType string) string {
 	defaults := map[string]string{
 		"github":    "https://api.github.com",
+		"gitlab":    "https://gitlab.com/api/v4",
 		"slack":     "https://slack.com/api",
 		"jira":      "", // Requires custom instance URL
 		"discord":   "https://discord.com/api/v10",
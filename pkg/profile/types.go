@@ -144,6 +144,50 @@ type MCPServerBinding struct {
 
 	// Timeout is the default timeout for tool calls in seconds
 	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// URL is the endpoint of a remote MCP server (used instead of Command)
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Headers are extra HTTP headers sent to a remote server
+	// Supports secret references in values
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// Transport selects how to connect: "stdio", "http" or "sse"
+	Transport string `yaml:"transport,omitempty" json:"transport,omitempty"`
+
+	// Auth configures authentication for a remote server
+	Auth *MCPAuthBinding `yaml:"auth,omitempty" json:"auth,omitempty"`
+}
+
+// MCPAuthBinding contains authentication settings for a remote MCP server.
+// Supports bearer tokens and OAuth2 client credentials or refresh tokens.
+type MCPAuthBinding struct {
+	// Type is the auth scheme: "bearer" or "oauth2"
+	Type string `yaml:"type" json:"type"`
+
+	// Token is the bearer token
+	// Supports secret references
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// Flow is the OAuth2 flow: "client_credentials" or "authorization_code"
+	Flow string `yaml:"flow,omitempty" json:"flow,omitempty"`
+
+	// ClientID is the OAuth2 client ID
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+
+	// ClientSecret is the OAuth2 client secret
+	// Supports secret references
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// TokenURL is the OAuth2 token endpoint
+	TokenURL string `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+
+	// RefreshToken is the OAuth2 refresh token
+	// Supports secret references
+	RefreshToken string `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+
+	// Scopes are the OAuth2 scopes to request
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
 }
 
 // SecretReference represents a reference to a secret value.
@@ -116,6 +116,13 @@ func (c *HTTPSecurityConfig) ValidateURL(rawURL string) error {
 		return fmt.Errorf("URL missing host")
 	}
 
+	return c.ValidateHost(host)
+}
+
+// ValidateHost validates a host name against the allowlist and, when
+// configured, the addresses it resolves to. It applies the URL checks to
+// connections that are not made over HTTP, such as SMTP.
+func (c *HTTPSecurityConfig) ValidateHost(host string) error {
 	// Validate host against allowlist
 	if len(c.AllowedHosts) > 0 {
 		if err := c.validateHost(host); err != nil {
@@ -49,6 +49,10 @@ var builtinProfiles = map[string]*SecurityProfile{
 			TotalRuntime:   0,                // No limit
 			MaxFileSize:    10 * 1024 * 1024, // 10 MB
 		},
+		MCP: MCPConfig{
+			ToolDrift:       MCPActionWarn,
+			SuspiciousTools: MCPActionWarn,
+		},
 	},
 	ProfileStandard: {
 		Name: ProfileStandard,
@@ -99,6 +103,10 @@ var builtinProfiles = map[string]*SecurityProfile{
 			TotalRuntime:   5 * time.Minute,
 			MaxFileSize:    50 * 1024 * 1024, // 50 MB
 		},
+		MCP: MCPConfig{
+			ToolDrift:       MCPActionBlock,
+			SuspiciousTools: MCPActionWarn,
+		},
 	},
 }
 
@@ -168,6 +176,19 @@ func ValidateProfile(profile *SecurityProfile) error {
 		return fmt.Errorf("max_file_size cannot be negative")
 	}
 
+	// Validate MCP tool actions
+	for field, action := range map[string]MCPAction{
+		"tool_drift":       profile.MCP.ToolDrift,
+		"suspicious_tools": profile.MCP.SuspiciousTools,
+	} {
+		switch action {
+		case "", MCPActionWarn, MCPActionBlock:
+			// Valid
+		default:
+			return fmt.Errorf("invalid mcp.%s action: %s (must be 'warn' or 'block')", field, action)
+		}
+	}
+
 	return nil
 }
 
@@ -195,6 +216,7 @@ func copyProfile(p *SecurityProfile) *SecurityProfile {
 		},
 		Isolation: p.Isolation,
 		Limits:    p.Limits,
+		MCP:       p.MCP,
 	}
 }
 
@@ -130,6 +130,20 @@ func TestValidateProfile(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "invalid mcp tool drift action",
+			profile: &SecurityProfile{
+				Name:      "test",
+				Isolation: IsolationNone,
+				Limits: ResourceLimits{
+					TimeoutPerTool: 30 * time.Second,
+				},
+				MCP: MCPConfig{
+					ToolDrift: "ignore",
+				},
+			},
+			wantError: true,
+		},
 		{
 			name: "valid profile",
 			profile: &SecurityProfile{
@@ -42,6 +42,9 @@ type SecurityProfile struct {
 
 	// Limits defines resource limits
 	Limits ResourceLimits `yaml:"limits" json:"limits"`
+
+	// MCP defines how MCP server tools are verified
+	MCP MCPConfig `yaml:"mcp,omitempty" json:"mcp,omitempty"`
 }
 
 // FilesystemConfig defines file access restrictions.
@@ -107,6 +110,28 @@ type ResourceLimits struct {
 	MaxFileSize int64 `yaml:"max_file_size,omitempty" json:"max_file_size,omitempty"`
 }
 
+// MCPAction is the action taken when an MCP tool check fails.
+type MCPAction string
+
+const (
+	// MCPActionWarn logs a warning and continues the run
+	MCPActionWarn MCPAction = "warn"
+
+	// MCPActionBlock refuses to run the workflow
+	MCPActionBlock MCPAction = "block"
+)
+
+// MCPConfig defines how MCP server tools are verified before a run uses them.
+type MCPConfig struct {
+	// ToolDrift is the action when a server's tools differ from the lockfile
+	// Empty means warn
+	ToolDrift MCPAction `yaml:"tool_drift,omitempty" json:"tool_drift,omitempty"`
+
+	// SuspiciousTools is the action when a tool description contains
+	// instructions aimed at the model. Empty means warn
+	SuspiciousTools MCPAction `yaml:"suspicious_tools,omitempty" json:"suspicious_tools,omitempty"`
+}
+
 // AccessRequest represents a request to access a protected resource.
 type AccessRequest struct {
 	// WorkflowID identifies the workflow making the request
@@ -0,0 +1,118 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRequestNotFound is returned by Queue.Decide for unknown or already
+// decided requests.
+var ErrRequestNotFound = errors.New("approval request not found")
+
+// Request is an approval waiting for a decision.
+type Request struct {
+	ID          string                 `json:"id"`
+	ToolName    string                 `json:"tool_name"`
+	Description string                 `json:"description"`
+	Inputs      map[string]interface{} `json:"inputs,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// Queue holds approval requests until they are decided with Decide, for
+// example from the controller API. A request is denied if it is not decided
+// before its context ends or the queue's timeout passes.
+type Queue struct {
+	mu      sync.Mutex
+	pending map[string]*queuedRequest
+	timeout time.Duration
+}
+
+type queuedRequest struct {
+	Request
+	decision chan bool
+}
+
+// NewQueue creates an approval queue. A timeout of zero waits until the
+// request's context ends.
+func NewQueue(timeout time.Duration) *Queue {
+	return &Queue{
+		pending: make(map[string]*queuedRequest),
+		timeout: timeout,
+	}
+}
+
+// Approve queues the request and waits for a decision.
+func (q *Queue) Approve(ctx context.Context, toolName string, toolDescription string, inputs map[string]interface{}) (bool, error) {
+	req := &queuedRequest{
+		Request: Request{
+			ID:          uuid.New().String(),
+			ToolName:    toolName,
+			Description: toolDescription,
+			Inputs:      inputs,
+			CreatedAt:   time.Now(),
+		},
+		decision: make(chan bool, 1),
+	}
+
+	q.mu.Lock()
+	q.pending[req.ID] = req
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.pending, req.ID)
+		q.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if q.timeout > 0 {
+		timer := time.NewTimer(q.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case approved := <-req.decision:
+		return approved, nil
+	case <-timeout:
+		return false, fmt.Errorf("approval for %s timed out after %s", toolName, q.timeout)
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// Pending returns the requests waiting for a decision, oldest first.
+func (q *Queue) Pending() []Request {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests := make([]Request, 0, len(q.pending))
+	for _, req := range q.pending {
+		requests = append(requests, req.Request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+	return requests
+}
+
+// Decide approves or denies a pending request.
+func (q *Queue) Decide(id string, approved bool) error {
+	q.mu.Lock()
+	req, ok := q.pending[id]
+	if ok {
+		delete(q.pending, id)
+	}
+	q.mu.Unlock()
+
+	if !ok {
+		return ErrRequestNotFound
+	}
+	req.decision <- approved
+	return nil
+}
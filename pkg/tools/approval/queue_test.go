@@ -0,0 +1,52 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueue_Decide(t *testing.T) {
+	q := NewQueue(0)
+
+	result := make(chan bool, 1)
+	go func() {
+		approved, _ := q.Approve(context.Background(), "server:sampling", "A completion", nil)
+		result <- approved
+	}()
+
+	var pending []Request
+	for deadline := time.Now().Add(time.Second); len(pending) == 0 && time.Now().Before(deadline); {
+		pending = q.Pending()
+		time.Sleep(time.Millisecond)
+	}
+	if len(pending) != 1 || pending[0].ToolName != "server:sampling" {
+		t.Fatalf("Pending() = %v, want one request", pending)
+	}
+
+	if err := q.Decide(pending[0].ID, true); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if !<-result {
+		t.Error("expected request to be approved")
+	}
+	if err := q.Decide(pending[0].ID, false); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("expected ErrRequestNotFound for decided request, got %v", err)
+	}
+	if len(q.Pending()) != 0 {
+		t.Error("expected no pending requests")
+	}
+}
+
+func TestQueue_Timeout(t *testing.T) {
+	q := NewQueue(10 * time.Millisecond)
+
+	approved, err := q.Approve(context.Background(), "tool", "", nil)
+	if approved || err == nil {
+		t.Errorf("Approve() = %v, %v; want denial after timeout", approved, err)
+	}
+	if len(q.Pending()) != 0 {
+		t.Error("expected timed out request to be removed")
+	}
+}
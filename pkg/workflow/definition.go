@@ -36,6 +36,13 @@ type Definition struct {
 	// Trigger defines how this workflow can be invoked (webhooks, API, schedules)
 	Trigger *TriggerConfig `yaml:"trigger,omitempty" json:"trigger,omitempty"`
 
+	// Triggers lists several ways the workflow can be invoked, each with
+	// its own type, input mapping and ID
+	Triggers []TriggerConfig `yaml:"triggers,omitempty" json:"triggers,omitempty"`
+
+	// Notify sends run lifecycle events to channels (webhook, Slack, Discord, email)
+	Notify *NotifyDefinition `yaml:"notify,omitempty" json:"notify,omitempty"`
+
 	// Inputs defines the expected input parameters for the workflow
 	Inputs []InputDefinition `yaml:"inputs" json:"inputs"`
 
@@ -137,6 +144,18 @@ type StepDefinition struct {
 	// Supports template variables: {{.input}}, {{.steps.stepid.response}}
 	Prompt string `yaml:"prompt,omitempty" json:"prompt,omitempty"`
 
+	// PromptFrom renders the user prompt from an MCP server prompt (llm and agent steps)
+	// Mutually exclusive with Prompt and UserPrompt
+	PromptFrom *MCPPromptRef `yaml:"prompt_from,omitempty" json:"prompt_from,omitempty"`
+
+	// SystemFrom renders the system prompt from an MCP server prompt (llm and agent steps)
+	// Mutually exclusive with System and SystemPrompt
+	SystemFrom *MCPPromptRef `yaml:"system_from,omitempty" json:"system_from,omitempty"`
+
+	// Resources lists MCP resources (mcp://server/path) read before the step runs.
+	// Unnamed resources are attached to the prompt; named ones are available as {{.resources.<as>}}
+	Resources []MCPResourceRef `yaml:"resources,omitempty" json:"resources,omitempty"`
+
 	// OutputSchema defines the expected JSON Schema for LLM step outputs
 	// Mutually exclusive with OutputType
 	OutputSchema map[string]interface{} `yaml:"output_schema,omitempty" json:"output_schema,omitempty"`
@@ -765,6 +784,25 @@ func (d *Definition) Validate() error {
 		}
 	}
 
+	// Validate poll trigger integration references. Unlike steps, poll
+	// triggers cannot use workspace integrations, since the controller polls
+	// with the integration as declared in the workflow
+	for _, t := range d.TriggersOfType(TriggerTypePoll) {
+		if !t.Poll.IsDeclaredOperation() {
+			continue
+		}
+		integrationName, operationName := t.Poll.DeclaredOperation()
+		if !integrationNames[integrationName] {
+			return fmt.Errorf("poll trigger %s references undefined integration %s", t.TriggerID(), integrationName)
+		}
+		integration := d.Integrations[integrationName]
+		if integration.From == "" {
+			if _, exists := integration.Operations[operationName]; !exists {
+				return fmt.Errorf("poll trigger %s references undefined operation %s in integration %s", t.TriggerID(), operationName, integrationName)
+			}
+		}
+	}
+
 	// Validate requirements section
 	if d.Requires != nil {
 		if err := d.Requires.Validate(); err != nil {
@@ -786,11 +824,26 @@ func (d *Definition) Validate() error {
 		}
 	}
 
+	// Validate notifications
+	if d.Notify != nil {
+		if err := d.Notify.Validate(); err != nil {
+			return fmt.Errorf("invalid notify configuration: %w", err)
+		}
+	}
+
 	// Validate trigger configuration
-	if d.Trigger != nil {
-		if err := d.Trigger.Validate(); err != nil {
+	triggerIDs := make(map[string]bool)
+	for _, t := range d.AllTriggers() {
+		if err := t.Validate(); err != nil {
 			return fmt.Errorf("invalid trigger configuration: %w", err)
 		}
+		if rc := t.RunCompleted; rc != nil && rc.Workflow == d.Name {
+			return fmt.Errorf("invalid trigger configuration: run_completed cannot chain workflow %q to itself", d.Name)
+		}
+		if triggerIDs[t.TriggerID()] {
+			return fmt.Errorf("invalid trigger configuration: duplicate trigger id %q (set a unique id on each trigger)", t.TriggerID())
+		}
+		triggerIDs[t.TriggerID()] = true
 	}
 
 	return nil
@@ -876,7 +929,7 @@ func (s *StepDefinition) Validate() error {
 	}
 
 	// Validate prompt is present for LLM steps
-	if s.Type == StepTypeLLM && s.Prompt == "" {
+	if s.Type == StepTypeLLM && s.Prompt == "" && s.PromptFrom == nil {
 		return fmt.Errorf("prompt is required for LLM step type")
 	}
 
@@ -914,9 +967,10 @@ func (s *StepDefinition) Validate() error {
 				"http":      true,
 				"transform": true,
 				"utility":   true,
+				"email":     true,
 			}
 			if !validActions[s.Action] {
-				return fmt.Errorf("invalid action: %s (must be file, shell, http, transform, or utility)", s.Action)
+				return fmt.Errorf("invalid action: %s (must be file, shell, http, transform, utility, or email)", s.Action)
 			}
 		}
 		// Format validation for integration field happens at workflow level where we can check against defined integrations
@@ -942,7 +996,7 @@ func (s *StepDefinition) Validate() error {
 	// Validate agent steps
 	if s.Type == StepTypeAgent {
 		// user_prompt is required
-		if s.UserPrompt == "" {
+		if s.UserPrompt == "" && s.PromptFrom == nil {
 			return fmt.Errorf("user_prompt is required for agent step type")
 		}
 
@@ -965,6 +1019,11 @@ func (s *StepDefinition) Validate() error {
 		}
 	}
 
+	// Validate MCP resource and prompt references
+	if err := s.validateMCPReferences(); err != nil {
+		return err
+	}
+
 	// Validate error handling
 	if s.OnError != nil {
 		if err := s.OnError.Validate(); err != nil {
@@ -1386,6 +1445,7 @@ var builtinActionNames = map[string]bool{
 	"http":      true,
 	"transform": true,
 	"utility":   true,
+	"email":     true,
 }
 
 // primaryParameters maps operation names to their primary parameter for inline form
@@ -2,7 +2,9 @@ package workflow
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestIntegrationDefinitionValidate(t *testing.T) {
@@ -607,6 +609,42 @@ steps:
   - id: process
     type: llm
     prompt: test
+`,
+			wantErr: false,
+		},
+		{
+			name: "gitlab poll trigger with project path",
+			definition: `
+name: test-workflow
+trigger:
+  poll:
+    integration: gitlab
+    query:
+      resource: merge_requests
+      project: platform/api
+      reviewer_username: alice
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantErr: false,
+		},
+		{
+			name: "github poll trigger with repo",
+			definition: `
+name: test-workflow
+trigger:
+  poll:
+    integration: github
+    query:
+      resource: workflow_runs
+      repo: acme/api
+      status: failure
+steps:
+  - id: process
+    type: llm
+    prompt: test
 `,
 			wantErr: false,
 		},
@@ -748,6 +786,124 @@ steps:
 			wantErr: true,
 			errMsg:  "invalid query parameter value",
 		},
+		{
+			name: "declared operation poll trigger",
+			definition: `
+name: test-workflow
+integrations:
+  tickets:
+    base_url: https://tickets.example.com
+    operations:
+      list_tickets:
+        method: GET
+        path: /api/tickets
+trigger:
+  poll:
+    integration: tickets.list_tickets
+    items: .tickets[]
+    id_field: key
+    timestamp_field: modified
+    cursor: .next
+    cursor_param: after
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantErr: false,
+		},
+		{
+			name: "declared operation with undefined integration",
+			definition: `
+name: test-workflow
+integrations:
+  tickets:
+    base_url: https://tickets.example.com
+    operations:
+      list_tickets:
+        method: GET
+        path: /api/tickets
+trigger:
+  poll:
+    integration: deploys.list_deploys
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantErr: true,
+			errMsg:  "undefined integration deploys",
+		},
+		{
+			name: "declared operation with undefined operation",
+			definition: `
+name: test-workflow
+integrations:
+  tickets:
+    base_url: https://tickets.example.com
+    operations:
+      list_tickets:
+        method: GET
+        path: /api/tickets
+trigger:
+  poll:
+    integration: tickets.search
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantErr: true,
+			errMsg:  "undefined operation search",
+		},
+		{
+			name: "cursor without cursor_param",
+			definition: `
+name: test-workflow
+integrations:
+  tickets:
+    base_url: https://tickets.example.com
+    operations:
+      list_tickets:
+        method: GET
+        path: /api/tickets
+trigger:
+  poll:
+    integration: tickets.list_tickets
+    cursor: .next
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantErr: true,
+			errMsg:  "cursor and cursor_param must be set together",
+		},
+		{
+			name: "items with builtin integration",
+			definition: `
+name: test-workflow
+integrations:
+  tickets:
+    base_url: https://tickets.example.com
+    operations:
+      list_tickets:
+        method: GET
+        path: /api/tickets
+trigger:
+  poll:
+    integration: pagerduty
+    items: .incidents[]
+    query:
+      user_id: PUSER123
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantErr: true,
+			errMsg:  "not supported by the pagerduty integration",
+		},
 	}
 
 	for _, tt := range tests {
@@ -766,6 +922,360 @@ steps:
 	}
 }
 
+func TestTriggersList(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		wantIDs    []string
+		errMsg     string
+	}{
+		{
+			name: "trigger and triggers",
+			definition: `
+name: test-workflow
+trigger:
+  webhook:
+    path: /hook
+triggers:
+  - id: nightly
+    schedule:
+      cron: "0 2 * * *"
+  - api:
+      secret: token
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			wantIDs: []string{"webhook", "nightly", "api"},
+		},
+		{
+			name: "duplicate id",
+			definition: `
+name: test-workflow
+triggers:
+  - schedule:
+      cron: "0 2 * * *"
+  - schedule:
+      cron: "0 3 * * *"
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			errMsg: `duplicate trigger id "schedule"`,
+		},
+		{
+			name: "two types in one entry",
+			definition: `
+name: test-workflow
+triggers:
+  - schedule:
+      cron: "0 2 * * *"
+    webhook:
+      path: /hook
+steps:
+  - id: process
+    type: llm
+    prompt: test
+`,
+			errMsg: "only one trigger type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := ParseDefinition([]byte(tt.definition))
+			if tt.errMsg != "" {
+				if err == nil || !contains(err.Error(), tt.errMsg) {
+					t.Errorf("ParseDefinition() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDefinition() error = %v", err)
+			}
+			var ids []string
+			for _, trig := range def.AllTriggers() {
+				ids = append(ids, trig.TriggerID())
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("trigger IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestQueueTriggerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger string
+		errMsg  string
+	}{
+		{
+			name: "nats",
+			trigger: `
+    broker: nats
+    url: ${NATS_URL}
+    subject: orders.created
+    ack: completed
+    input_mapping:
+      order_id: $.body.id
+      tenant: $.headers.X-Tenant
+    redelivery:
+      max_attempts: 3
+      backoff: 2s`,
+		},
+		{
+			name: "redis without stream",
+			trigger: `
+    broker: redis
+    url: redis://localhost:6379`,
+			errMsg: "stream is required",
+		},
+		{
+			name: "amqp without queue",
+			trigger: `
+    broker: amqp
+    url: amqp://localhost`,
+			errMsg: "queue is required",
+		},
+		{
+			name: "unknown broker",
+			trigger: `
+    broker: kafka
+    url: localhost:9092`,
+			errMsg: "unsupported broker: kafka",
+		},
+		{
+			name: "invalid ack mode",
+			trigger: `
+    broker: nats
+    url: nats://localhost
+    subject: orders.created
+    ack: received`,
+			errMsg: "invalid ack mode",
+		},
+		{
+			name: "invalid backoff",
+			trigger: `
+    broker: nats
+    url: nats://localhost
+    subject: orders.created
+    redelivery:
+      backoff: soon`,
+			errMsg: "invalid duration: soon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := "name: orders\ntrigger:\n  queue:" + tt.trigger + "\nsteps:\n  - id: process\n    type: llm\n    prompt: test\n"
+			_, err := ParseDefinition([]byte(definition))
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("ParseDefinition() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParseDefinition() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestQueueTriggerRetryDelay(t *testing.T) {
+	q := &QueueTriggerConfig{Redelivery: &QueueRedelivery{Backoff: "1s", MaxBackoff: "5s"}}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := q.RetryDelay(i + 1); got != w {
+			t.Errorf("RetryDelay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	if got := (&QueueTriggerConfig{}).MaxAttempts(); got != DefaultQueueMaxAttempts {
+		t.Errorf("MaxAttempts() = %d, want default %d", got, DefaultQueueMaxAttempts)
+	}
+}
+
+func TestWebhookTriggerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger string
+		errMsg  string
+	}{
+		{
+			name: "expression filter and jq mapping",
+			trigger: `
+    path: /review
+    filter: payload.pull_request.base.ref == "main"
+    input_mapping:
+      labels: "jq: .pull_request.labels | map(.name)"
+      title: $.pull_request.title`,
+		},
+		{
+			name: "jq filter",
+			trigger: `
+    path: /review
+    filter:
+      jq: '.payload.pull_request.labels | any(.name == "ai-review")'`,
+		},
+		{
+			name: "invalid expression",
+			trigger: `
+    path: /review
+    filter: payload.action ==`,
+			errMsg: "invalid filter",
+		},
+		{
+			name: "invalid jq filter",
+			trigger: `
+    path: /review
+    filter:
+      jq: .payload | any(`,
+			errMsg: "invalid jq filter",
+		},
+		{
+			name: "both expr and jq",
+			trigger: `
+    path: /review
+    filter:
+      expr: event == "push"
+      jq: .event == "push"`,
+			errMsg: "exactly one of expr or jq",
+		},
+		{
+			name: "invalid jq mapping",
+			trigger: `
+    path: /review
+    input_mapping:
+      labels: "jq: .labels | map("`,
+			errMsg: "invalid jq query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := "name: review\ntrigger:\n  webhook:" + tt.trigger + "\nsteps:\n  - id: process\n    type: llm\n    prompt: test\n"
+			def, err := ParseDefinition([]byte(definition))
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("ParseDefinition() error = %v", err)
+				} else if def.Trigger.Webhook.Filter == nil {
+					t.Error("Filter is nil")
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParseDefinition() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestIMAPTriggerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger string
+		errMsg  string
+	}{
+		{
+			name: "idle with move",
+			trigger: `
+    host: imap.example.com
+    username: ${IMAP_USERNAME}
+    password: ${IMAP_PASSWORD}
+    folder: Reports
+    processed: move
+    move_to: Reports/Done
+    input_mapping:
+      report: $.attachments.0.path`,
+		},
+		{
+			name: "without host",
+			trigger: `
+    username: ${IMAP_USERNAME}
+    password: ${IMAP_PASSWORD}`,
+			errMsg: "host is required",
+		},
+		{
+			name: "without credentials",
+			trigger: `
+    host: imap.example.com`,
+			errMsg: "username and password are required",
+		},
+		{
+			name: "move without folder",
+			trigger: `
+    host: imap.example.com
+    username: ${IMAP_USERNAME}
+    password: ${IMAP_PASSWORD}
+    processed: move`,
+			errMsg: "move_to is required",
+		},
+		{
+			name: "invalid mode",
+			trigger: `
+    host: imap.example.com
+    username: ${IMAP_USERNAME}
+    password: ${IMAP_PASSWORD}
+    mode: push`,
+			errMsg: "invalid mode: push",
+		},
+		{
+			name: "invalid interval",
+			trigger: `
+    host: imap.example.com
+    username: ${IMAP_USERNAME}
+    password: ${IMAP_PASSWORD}
+    mode: poll
+    interval: often`,
+			errMsg: "invalid duration: often",
+		},
+		{
+			name: "invalid security",
+			trigger: `
+    host: imap.example.com
+    username: ${IMAP_USERNAME}
+    password: ${IMAP_PASSWORD}
+    security: ssl`,
+			errMsg: "invalid security mode: ssl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := "name: reports\ntrigger:\n  imap:" + tt.trigger + "\nsteps:\n  - id: process\n    type: llm\n    prompt: test\n"
+			_, err := ParseDefinition([]byte(definition))
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("ParseDefinition() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParseDefinition() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestIMAPTriggerDefaults(t *testing.T) {
+	m := &IMAPTriggerConfig{Host: "imap.example.com"}
+	if m.Address() != "imap.example.com:993" || m.FolderName() != "INBOX" || m.WatchMode() != IMAPModeIdle {
+		t.Errorf("defaults = %s %s %s", m.Address(), m.FolderName(), m.WatchMode())
+	}
+	if m.PollInterval() != DefaultIMAPInterval || m.ProcessedAction() != IMAPProcessedRead {
+		t.Errorf("defaults = %v %s", m.PollInterval(), m.ProcessedAction())
+	}
+
+	m.Security = IMAPSecuritySTARTTLS
+	if m.Address() != "imap.example.com:143" {
+		t.Errorf("Address() = %s, want port 143 for starttls", m.Address())
+	}
+}
+
 func TestPollTriggerUnmarshal(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -1416,3 +1926,69 @@ steps:
 		})
 	}
 }
+
+func TestMCPServerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  MCPServerConfig
+		wantErr string
+	}{
+		{
+			name:   "stdio server",
+			server: MCPServerConfig{Name: "fs", Command: "npx"},
+		},
+		{
+			name:   "remote server with bearer auth",
+			server: MCPServerConfig{Name: "docs", URL: "https://mcp.example.com/mcp", Auth: &MCPAuthConfig{Type: "bearer", Token: "${DOCS_TOKEN}"}},
+		},
+		{
+			name:   "sse server",
+			server: MCPServerConfig{Name: "legacy", Transport: "sse", URL: "https://mcp.example.com/sse"},
+		},
+		{
+			name:    "missing command and url",
+			server:  MCPServerConfig{Name: "empty"},
+			wantErr: "mcp_server command is required",
+		},
+		{
+			name:    "http transport without url",
+			server:  MCPServerConfig{Name: "docs", Transport: "http"},
+			wantErr: "mcp_server url is required",
+		},
+		{
+			name:    "command and url",
+			server:  MCPServerConfig{Name: "docs", Command: "npx", URL: "https://mcp.example.com/mcp"},
+			wantErr: "cannot set both command and url",
+		},
+		{
+			name:    "headers on stdio server",
+			server:  MCPServerConfig{Name: "fs", Command: "npx", Headers: map[string]string{"X-Key": "v"}},
+			wantErr: "require http or sse transport",
+		},
+		{
+			name:    "invalid oauth2 auth",
+			server:  MCPServerConfig{Name: "docs", URL: "https://mcp.example.com/mcp", Auth: &MCPAuthConfig{Type: "oauth2", ClientID: "id"}},
+			wantErr: "client_id, client_secret and token_url are required",
+		},
+		{
+			name:    "unknown transport",
+			server:  MCPServerConfig{Name: "docs", Transport: "websocket", URL: "wss://mcp.example.com"},
+			wantErr: "transport must be stdio, http, or sse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -106,6 +106,9 @@ type Executor struct {
 
 	// subworkflowLoader loads sub-workflow definitions (lazily initialized)
 	subworkflowLoader SubworkflowLoader
+
+	// mcpResolver reads MCP resources and renders MCP prompts for steps
+	mcpResolver MCPResolver
 }
 
 // SubworkflowLoader defines the interface for loading sub-workflow definitions.
@@ -419,6 +422,12 @@ func (e *Executor) Execute(ctx context.Context, step *StepDefinition, workflowCo
 
 // executeStep executes a step once without retry logic.
 func (e *Executor) executeStep(ctx context.Context, step *StepDefinition, workflowContext map[string]interface{}) (map[string]interface{}, error) {
+	// Read MCP resources first so inputs and prompts can reference them
+	workflowContext, attachments, err := e.resolveMCPResources(ctx, step, workflowContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resources: %w", err)
+	}
+
 	// Resolve inputs (substitute context variables)
 	inputs, err := e.resolveInputs(step.Inputs, workflowContext)
 	if err != nil {
@@ -431,6 +440,12 @@ func (e *Executor) executeStep(ctx context.Context, step *StepDefinition, workfl
 		return nil, fmt.Errorf("failed to resolve step fields: %w", err)
 	}
 
+	// Render MCP prompts and attach resource content to the prompt
+	if err := e.resolveMCPPrompts(ctx, &resolvedStep, workflowContext); err != nil {
+		return nil, err
+	}
+	attachMCPResources(&resolvedStep, attachments)
+
 	// Execute based on step type
 	switch step.Type {
 	case StepTypeLLM:
@@ -0,0 +1,343 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tombee/conductor/pkg/errors"
+)
+
+// MCPResourceScheme is the URI scheme used to reference MCP server resources
+// from workflow steps, e.g. mcp://docs-server/guides/setup.md.
+const MCPResourceScheme = "mcp://"
+
+// MCPResolver reads resources and renders prompts from MCP servers.
+// It keeps the executor independent of the internal/mcp package.
+type MCPResolver interface {
+	// ReadResource reads a resource from the named server and returns its text content.
+	// uri is the resource path after the server name in an mcp:// reference.
+	ReadResource(ctx context.Context, server, uri string) (string, error)
+
+	// GetPrompt renders a prompt from the named server and returns its text.
+	GetPrompt(ctx context.Context, server, name string, args map[string]string) (string, error)
+}
+
+// MCPResourceRef references an MCP resource used as input to a step.
+//
+// Resources can be written as a plain URI, which attaches the content to the
+// prompt of an llm or agent step like a file:
+//
+//	resources:
+//	  - mcp://docs-server/guides/setup.md
+//
+// or as an object with "as", which exposes the content to templates as
+// {{.resources.<as>}} instead:
+//
+//	resources:
+//	  - resource: mcp://docs-server/guides/setup.md
+//	    as: setup_guide
+type MCPResourceRef struct {
+	// URI is the mcp://server/path reference (supports template variables)
+	URI string `yaml:"resource" json:"resource"`
+
+	// As names the template variable for the content ({{.resources.<as>}}).
+	// When empty, the content is attached to the step's prompt.
+	As string `yaml:"as,omitempty" json:"as,omitempty"`
+}
+
+// UnmarshalYAML supports both the plain URI and the object form.
+func (r *MCPResourceRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var uri string
+	if err := unmarshal(&uri); err == nil {
+		r.URI = uri
+		return nil
+	}
+
+	type plainRef MCPResourceRef
+	return unmarshal((*plainRef)(r))
+}
+
+// Validate checks that the resource reference is well-formed.
+func (r *MCPResourceRef) Validate() error {
+	if r.URI == "" {
+		return fmt.Errorf("resource uri is required")
+	}
+	// Templated URIs are checked once resolved at execution time
+	if containsTemplateSyntax(r.URI) {
+		return nil
+	}
+	if _, _, err := ParseMCPResourceURI(r.URI); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseMCPResourceURI splits an mcp://server/path reference into the server
+// name and the resource path on that server.
+func ParseMCPResourceURI(uri string) (server, path string, err error) {
+	if !strings.HasPrefix(uri, MCPResourceScheme) {
+		return "", "", fmt.Errorf("invalid resource uri %q: must start with %s", uri, MCPResourceScheme)
+	}
+	rest := strings.TrimPrefix(uri, MCPResourceScheme)
+	server, path, _ = strings.Cut(rest, "/")
+	if server == "" {
+		return "", "", fmt.Errorf("invalid resource uri %q: missing server name", uri)
+	}
+	if path == "" {
+		return "", "", fmt.Errorf("invalid resource uri %q: missing resource path", uri)
+	}
+	return server, path, nil
+}
+
+// MCPPromptRef references a prompt offered by an MCP server.
+// Argument values support template variables from the workflow context.
+//
+//	prompt_from:
+//	  server: prompts-server
+//	  prompt: code-review
+//	  arguments:
+//	    language: "{{.inputs.language}}"
+type MCPPromptRef struct {
+	// Server is the name of the MCP server offering the prompt
+	Server string `yaml:"server" json:"server"`
+
+	// Prompt is the name of the prompt on the server
+	Prompt string `yaml:"prompt" json:"prompt"`
+
+	// Arguments are the values passed to the prompt template
+	Arguments map[string]string `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+}
+
+// Validate checks that the prompt reference is complete.
+func (p *MCPPromptRef) Validate() error {
+	if p.Server == "" {
+		return fmt.Errorf("server is required")
+	}
+	if p.Prompt == "" {
+		return fmt.Errorf("prompt is required")
+	}
+	return nil
+}
+
+// validateMCPReferences checks the MCP resource and prompt references on a step.
+func (s *StepDefinition) validateMCPReferences() error {
+	usesPrompts := s.Type == StepTypeLLM || s.Type == StepTypeAgent
+
+	for i := range s.Resources {
+		ref := &s.Resources[i]
+		if err := ref.Validate(); err != nil {
+			return fmt.Errorf("resources[%d]: %w", i, err)
+		}
+		if ref.As == "" && !usesPrompts {
+			return fmt.Errorf("resources[%d]: 'as' is required on %s steps (resources are only attached to llm and agent prompts)", i, s.Type)
+		}
+	}
+
+	if s.PromptFrom != nil || s.SystemFrom != nil {
+		if !usesPrompts {
+			return fmt.Errorf("prompt_from and system_from are only valid for llm and agent steps")
+		}
+	}
+	if s.PromptFrom != nil {
+		if err := s.PromptFrom.Validate(); err != nil {
+			return fmt.Errorf("invalid prompt_from: %w", err)
+		}
+		if s.Prompt != "" || s.UserPrompt != "" {
+			return fmt.Errorf("prompt_from cannot be combined with an inline prompt")
+		}
+	}
+	if s.SystemFrom != nil {
+		if err := s.SystemFrom.Validate(); err != nil {
+			return fmt.Errorf("invalid system_from: %w", err)
+		}
+		if s.System != "" || s.SystemPrompt != "" {
+			return fmt.Errorf("system_from cannot be combined with an inline system prompt")
+		}
+	}
+
+	return nil
+}
+
+// WithMCPResolver sets the resolver used for MCP resources and prompts in steps.
+func (e *Executor) WithMCPResolver(resolver MCPResolver) *Executor {
+	e.mcpResolver = resolver
+	return e
+}
+
+// mcpAttachment is resource content attached to a step's prompt.
+type mcpAttachment struct {
+	uri     string
+	content string
+}
+
+// resolveMCPResources reads the step's MCP resources. Named resources are
+// exposed to templates through a step-scoped copy of the workflow context;
+// unnamed resources are returned as prompt attachments.
+func (e *Executor) resolveMCPResources(ctx context.Context, step *StepDefinition, workflowContext map[string]interface{}) (map[string]interface{}, []mcpAttachment, error) {
+	if len(step.Resources) == 0 {
+		return workflowContext, nil, nil
+	}
+	if e.mcpResolver == nil {
+		return nil, nil, &errors.ConfigError{
+			Key:    "mcp",
+			Reason: "MCP resolver not configured for workflow executor; resources require a running controller",
+		}
+	}
+
+	tmplCtx, _ := workflowContext["_templateContext"].(*TemplateContext)
+
+	named := make(map[string]interface{})
+	var attachments []mcpAttachment
+	for _, ref := range step.Resources {
+		uri := ref.URI
+		if tmplCtx != nil {
+			resolved, err := ResolveTemplate(uri, tmplCtx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to resolve resource uri: %w", err)
+			}
+			uri = resolved
+		}
+
+		server, path, err := ParseMCPResourceURI(uri)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		content, err := e.mcpResolver.ReadResource(ctx, server, path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+		}
+		e.logger.Debug("read MCP resource",
+			"uri", uri,
+			"bytes", len(content),
+		)
+
+		if ref.As != "" {
+			named[ref.As] = content
+		} else {
+			attachments = append(attachments, mcpAttachment{uri: uri, content: content})
+		}
+	}
+
+	if len(named) == 0 || tmplCtx == nil {
+		return workflowContext, attachments, nil
+	}
+
+	// Scope named resources to this step without mutating the shared context
+	stepTmplCtx := *tmplCtx
+	stepTmplCtx.Resources = make(map[string]interface{}, len(tmplCtx.Resources)+len(named))
+	for k, v := range tmplCtx.Resources {
+		stepTmplCtx.Resources[k] = v
+	}
+	for k, v := range named {
+		stepTmplCtx.Resources[k] = v
+	}
+
+	stepContext := make(map[string]interface{}, len(workflowContext))
+	for k, v := range workflowContext {
+		stepContext[k] = v
+	}
+	stepContext["_templateContext"] = &stepTmplCtx
+
+	return stepContext, attachments, nil
+}
+
+// resolveMCPPrompts renders prompt_from and system_from references into the
+// step's prompt fields. Argument values are resolved as templates first.
+func (e *Executor) resolveMCPPrompts(ctx context.Context, step *StepDefinition, workflowContext map[string]interface{}) error {
+	if step.PromptFrom == nil && step.SystemFrom == nil {
+		return nil
+	}
+	if e.mcpResolver == nil {
+		return &errors.ConfigError{
+			Key:    "mcp",
+			Reason: "MCP resolver not configured for workflow executor; prompt_from and system_from require a running controller",
+		}
+	}
+
+	if step.PromptFrom != nil {
+		prompt, err := e.renderMCPPrompt(ctx, step.PromptFrom, workflowContext)
+		if err != nil {
+			return fmt.Errorf("failed to render prompt_from: %w", err)
+		}
+		if step.Type == StepTypeAgent {
+			step.UserPrompt = prompt
+		} else {
+			step.Prompt = prompt
+		}
+	}
+
+	if step.SystemFrom != nil {
+		system, err := e.renderMCPPrompt(ctx, step.SystemFrom, workflowContext)
+		if err != nil {
+			return fmt.Errorf("failed to render system_from: %w", err)
+		}
+		if step.Type == StepTypeAgent {
+			step.SystemPrompt = system
+		} else {
+			step.System = system
+		}
+	}
+
+	return nil
+}
+
+// renderMCPPrompt resolves a prompt reference's arguments and renders it.
+func (e *Executor) renderMCPPrompt(ctx context.Context, ref *MCPPromptRef, workflowContext map[string]interface{}) (string, error) {
+	tmplCtx, _ := workflowContext["_templateContext"].(*TemplateContext)
+
+	args := make(map[string]string, len(ref.Arguments))
+	for name, value := range ref.Arguments {
+		if tmplCtx != nil {
+			resolved, err := ResolveTemplate(value, tmplCtx)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve argument %s: %w", name, err)
+			}
+			value = resolved
+		}
+		args[name] = value
+	}
+
+	text, err := e.mcpResolver.GetPrompt(ctx, ref.Server, ref.Prompt, args)
+	if err != nil {
+		return "", err
+	}
+	e.logger.Debug("rendered MCP prompt",
+		"server", ref.Server,
+		"prompt", ref.Prompt,
+	)
+	return text, nil
+}
+
+// attachMCPResources appends resource content to the prompt of an llm or agent step.
+func attachMCPResources(step *StepDefinition, attachments []mcpAttachment) {
+	if len(attachments) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	for _, a := range attachments {
+		fmt.Fprintf(&b, "\n\n<resource uri=%q>\n%s\n</resource>", a.uri, a.content)
+	}
+
+	if step.Type == StepTypeAgent {
+		step.UserPrompt += b.String()
+	} else {
+		step.Prompt += b.String()
+	}
+}
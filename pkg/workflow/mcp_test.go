@@ -0,0 +1,282 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mockMCPResolver serves resources and prompts from in-memory maps.
+type mockMCPResolver struct {
+	resources map[string]string // "server/path" -> content
+	prompts   map[string]string // "server/name" -> text with {arg} placeholders
+}
+
+func (m *mockMCPResolver) ReadResource(ctx context.Context, server, uri string) (string, error) {
+	content, ok := m.resources[server+"/"+uri]
+	if !ok {
+		return "", fmt.Errorf("resource not found: %s", uri)
+	}
+	return content, nil
+}
+
+func (m *mockMCPResolver) GetPrompt(ctx context.Context, server, name string, args map[string]string) (string, error) {
+	text, ok := m.prompts[server+"/"+name]
+	if !ok {
+		return "", fmt.Errorf("prompt not found: %s", name)
+	}
+	for k, v := range args {
+		text = strings.ReplaceAll(text, "{"+k+"}", v)
+	}
+	return text, nil
+}
+
+func TestParseMCPResourceURI(t *testing.T) {
+	tests := []struct {
+		uri        string
+		wantServer string
+		wantPath   string
+		wantErr    bool
+	}{
+		{"mcp://docs-server/guides/setup.md", "docs-server", "guides/setup.md", false},
+		{"mcp://docs/file:///etc/motd", "docs", "file:///etc/motd", false},
+		{"mcp://docs-server", "", "", true},
+		{"mcp:///path", "", "", true},
+		{"https://example.com/doc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			server, path, err := ParseMCPResourceURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMCPResourceURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if server != tt.wantServer || path != tt.wantPath {
+				t.Errorf("ParseMCPResourceURI() = (%q, %q), want (%q, %q)", server, path, tt.wantServer, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestParseDefinition_MCPResourcesAndPrompts(t *testing.T) {
+	def, err := ParseDefinition([]byte(`
+name: mcp-refs
+steps:
+  - id: review
+    type: llm
+    resources:
+      - mcp://docs-server/guides/style.md
+      - resource: mcp://docs-server/guides/setup.md
+        as: setup
+    system_from:
+      server: prompts-server
+      prompt: reviewer
+      arguments:
+        language: go
+    prompt: "Review using {{.resources.setup}}"
+`))
+	if err != nil {
+		t.Fatalf("ParseDefinition() error = %v", err)
+	}
+
+	step := def.Steps[0]
+	if len(step.Resources) != 2 {
+		t.Fatalf("len(Resources) = %d, want 2", len(step.Resources))
+	}
+	if step.Resources[0].URI != "mcp://docs-server/guides/style.md" || step.Resources[0].As != "" {
+		t.Errorf("Resources[0] = %+v, want plain attachment", step.Resources[0])
+	}
+	if step.Resources[1].As != "setup" {
+		t.Errorf("Resources[1].As = %q, want setup", step.Resources[1].As)
+	}
+	if step.SystemFrom == nil || step.SystemFrom.Prompt != "reviewer" || step.SystemFrom.Arguments["language"] != "go" {
+		t.Errorf("SystemFrom = %+v, want reviewer prompt with language argument", step.SystemFrom)
+	}
+}
+
+func TestStepDefinition_ValidateMCPReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    StepDefinition
+		wantErr string
+	}{
+		{
+			name: "llm with prompt_from only",
+			step: StepDefinition{ID: "s", Type: StepTypeLLM, PromptFrom: &MCPPromptRef{Server: "p", Prompt: "x"}},
+		},
+		{
+			name: "agent with prompt_from only",
+			step: StepDefinition{ID: "s", Type: StepTypeAgent, Tools: []string{"t"}, PromptFrom: &MCPPromptRef{Server: "p", Prompt: "x"}},
+		},
+		{
+			name:    "prompt_from with inline prompt",
+			step:    StepDefinition{ID: "s", Type: StepTypeLLM, Prompt: "hi", PromptFrom: &MCPPromptRef{Server: "p", Prompt: "x"}},
+			wantErr: "cannot be combined",
+		},
+		{
+			name:    "system_from missing server",
+			step:    StepDefinition{ID: "s", Type: StepTypeLLM, Prompt: "hi", SystemFrom: &MCPPromptRef{Prompt: "x"}},
+			wantErr: "server is required",
+		},
+		{
+			name:    "invalid resource uri",
+			step:    StepDefinition{ID: "s", Type: StepTypeLLM, Prompt: "hi", Resources: []MCPResourceRef{{URI: "file:///etc/passwd"}}},
+			wantErr: "must start with mcp://",
+		},
+		{
+			name:    "unnamed resource on integration step",
+			step:    StepDefinition{ID: "s", Type: StepTypeIntegration, Action: "file", Operation: "write", Resources: []MCPResourceRef{{URI: "mcp://docs/a"}}},
+			wantErr: "'as' is required",
+		},
+		{
+			name: "named resource on integration step",
+			step: StepDefinition{ID: "s", Type: StepTypeIntegration, Action: "file", Operation: "write", Resources: []MCPResourceRef{{URI: "mcp://docs/a", As: "doc"}}},
+		},
+		{
+			name: "templated resource uri",
+			step: StepDefinition{ID: "s", Type: StepTypeLLM, Prompt: "hi", Resources: []MCPResourceRef{{URI: "mcp://docs/{{.inputs.page}}"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.step.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecutor_MCPResourcesAndPrompts(t *testing.T) {
+	var gotPrompt string
+	var gotOptions map[string]interface{}
+	llm := &mockLLMProviderFunc{
+		completeFunc: func(ctx context.Context, prompt string, options map[string]interface{}) (*CompletionResult, error) {
+			gotPrompt = prompt
+			gotOptions = options
+			return &CompletionResult{Content: "ok"}, nil
+		},
+	}
+
+	resolver := &mockMCPResolver{
+		resources: map[string]string{
+			"docs/style.md": "Use tabs.",
+			"docs/setup.md": "Run make.",
+		},
+		prompts: map[string]string{
+			"prompts/reviewer": "You review {language} code.",
+		},
+	}
+
+	executor := NewExecutor(nil, llm).WithMCPResolver(resolver)
+
+	templateCtx := NewTemplateContext()
+	templateCtx.SetInput("language", "Go")
+	workflowContext := map[string]interface{}{
+		"_templateContext": templateCtx,
+	}
+
+	step := &StepDefinition{
+		ID:   "review",
+		Type: StepTypeLLM,
+		Resources: []MCPResourceRef{
+			{URI: "mcp://docs/style.md"},
+			{URI: "mcp://docs/setup.md", As: "setup"},
+		},
+		SystemFrom: &MCPPromptRef{
+			Server:    "prompts",
+			Prompt:    "reviewer",
+			Arguments: map[string]string{"language": "{{.inputs.language}}"},
+		},
+		Prompt: "Setup notes: {{.resources.setup}}",
+	}
+
+	result, err := executor.Execute(context.Background(), step, workflowContext)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != StepStatusSuccess {
+		t.Fatalf("Status = %v, want success", result.Status)
+	}
+
+	if !strings.HasPrefix(gotPrompt, "Setup notes: Run make.") {
+		t.Errorf("prompt = %q, want named resource resolved in template", gotPrompt)
+	}
+	if !strings.Contains(gotPrompt, "<resource uri=\"mcp://docs/style.md\">\nUse tabs.\n</resource>") {
+		t.Errorf("prompt = %q, want unnamed resource attached", gotPrompt)
+	}
+	if gotOptions["system"] != "You review Go code." {
+		t.Errorf("system = %v, want rendered MCP prompt", gotOptions["system"])
+	}
+
+	// Named resources are scoped to the step
+	if templateCtx.Resources != nil {
+		t.Errorf("shared template context was modified: %v", templateCtx.Resources)
+	}
+}
+
+func TestExecutor_MCPPromptFrom(t *testing.T) {
+	var gotPrompt string
+	llm := &mockLLMProviderFunc{
+		completeFunc: func(ctx context.Context, prompt string, options map[string]interface{}) (*CompletionResult, error) {
+			gotPrompt = prompt
+			return &CompletionResult{Content: "ok"}, nil
+		},
+	}
+
+	executor := NewExecutor(nil, llm).WithMCPResolver(&mockMCPResolver{
+		prompts: map[string]string{"prompts/summarize": "Summarize {topic}."},
+	})
+
+	step := &StepDefinition{
+		ID:         "summarize",
+		Type:       StepTypeLLM,
+		PromptFrom: &MCPPromptRef{Server: "prompts", Prompt: "summarize", Arguments: map[string]string{"topic": "MCP"}},
+	}
+
+	if _, err := executor.Execute(context.Background(), step, map[string]interface{}{}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if gotPrompt != "Summarize MCP." {
+		t.Errorf("prompt = %q, want %q", gotPrompt, "Summarize MCP.")
+	}
+}
+
+func TestExecutor_MCPResourcesWithoutResolver(t *testing.T) {
+	executor := NewExecutor(nil, &mockLLMProvider{response: "ok"})
+
+	step := &StepDefinition{
+		ID:        "review",
+		Type:      StepTypeLLM,
+		Prompt:    "Review",
+		Resources: []MCPResourceRef{{URI: "mcp://docs/style.md"}},
+		Retry:     &RetryDefinition{MaxAttempts: 1},
+	}
+
+	_, err := executor.Execute(context.Background(), step, map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "MCP resolver not configured") {
+		t.Errorf("Execute() error = %v, want resolver not configured error", err)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Tom Barlow
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"text/template"
+
+	"github.com/tombee/conductor/pkg/errors"
+)
+
+// Run lifecycle events that can be sent as notifications.
+const (
+	NotifyEventStarted          = "started"
+	NotifyEventSucceeded        = "succeeded"
+	NotifyEventFailed           = "failed"
+	NotifyEventAwaitingApproval = "awaiting_approval"
+	NotifyEventBudgetExceeded   = "budget_exceeded"
+)
+
+// NotifyEvents lists every run lifecycle event, in the order a run can
+// reach them.
+var NotifyEvents = []string{
+	NotifyEventStarted,
+	NotifyEventAwaitingApproval,
+	NotifyEventBudgetExceeded,
+	NotifyEventSucceeded,
+	NotifyEventFailed,
+}
+
+// DefaultNotifyEvents are sent when a notify block does not list any.
+var DefaultNotifyEvents = []string{
+	NotifyEventFailed,
+	NotifyEventAwaitingApproval,
+	NotifyEventBudgetExceeded,
+}
+
+// Notification channel types.
+const (
+	NotifyChannelWebhook = "webhook"
+	NotifyChannelSlack   = "slack"
+	NotifyChannelDiscord = "discord"
+	NotifyChannelEmail   = "email"
+)
+
+// NotifyDefinition configures notifications about a workflow's runs.
+//
+// Example:
+//
+//	notify:
+//	  on: [failed, budget_exceeded]
+//	  budget_usd: 5
+//	  channels:
+//	    - type: slack
+//	      token: ${SLACK_TOKEN}
+//	      channel: "#deploys"
+//	    - type: webhook
+//	      url: https://hooks.example.com/conductor
+//	      secret: ${NOTIFY_SECRET}
+type NotifyDefinition struct {
+	// On lists the events to send (started, succeeded, failed,
+	// awaiting_approval, budget_exceeded). Default: failed,
+	// awaiting_approval and budget_exceeded.
+	On []string `yaml:"on,omitempty" json:"on,omitempty"`
+
+	// BudgetUSD sends budget_exceeded once when a run's cost passes it.
+	// The run is not stopped.
+	BudgetUSD float64 `yaml:"budget_usd,omitempty" json:"budget_usd,omitempty"`
+
+	// Template is a Go text/template for the message text.
+	// Channels without their own template use it.
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+
+	// Channels receive the notifications
+	Channels []NotifyChannel `yaml:"channels,omitempty" json:"channels,omitempty"`
+}
+
+// NotifyChannel is a destination for run notifications.
+type NotifyChannel struct {
+	// Type is the channel type: webhook, slack, discord or email
+	Type string `yaml:"type" json:"type"`
+
+	// URL receives a JSON POST (webhook), or overrides the API base URL (slack, discord)
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Secret signs webhook bodies with HMAC-SHA256 (can be a secret reference like $secret:key)
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+
+	// Token is the Slack or Discord bot token
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// Channel is the Slack channel or Discord channel ID
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+
+	// To lists email recipients
+	To []string `yaml:"to,omitempty" json:"to,omitempty"`
+
+	// On limits this channel to some of the notify block's events
+	On []string `yaml:"on,omitempty" json:"on,omitempty"`
+
+	// Template overrides the notify block's template for this channel
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+}
+
+// Events returns the events to send, applying the default.
+func (n *NotifyDefinition) Events() []string {
+	if len(n.On) == 0 {
+		return DefaultNotifyEvents
+	}
+	return n.On
+}
+
+// Wants reports whether the channel receives an event sent by the block.
+func (c *NotifyChannel) Wants(event string) bool {
+	return len(c.On) == 0 || slices.Contains(c.On, event)
+}
+
+// Merge returns the block with unset fields filled in from defaults.
+// A nil block takes all of the defaults.
+func (n *NotifyDefinition) Merge(defaults *NotifyDefinition) *NotifyDefinition {
+	if n == nil {
+		return defaults
+	}
+	if defaults == nil {
+		return n
+	}
+	merged := *n
+	if len(merged.On) == 0 {
+		merged.On = defaults.On
+	}
+	if merged.BudgetUSD == 0 {
+		merged.BudgetUSD = defaults.BudgetUSD
+	}
+	if merged.Template == "" {
+		merged.Template = defaults.Template
+	}
+	if len(merged.Channels) == 0 {
+		merged.Channels = defaults.Channels
+	}
+	return &merged
+}
+
+// Validate checks the notify block for errors.
+func (n *NotifyDefinition) Validate() error {
+	if err := validateNotifyEvents("on", n.On); err != nil {
+		return err
+	}
+	if n.BudgetUSD < 0 {
+		return &errors.ValidationError{
+			Field:      "budget_usd",
+			Message:    fmt.Sprintf("budget_usd must be non-negative, got %v", n.BudgetUSD),
+			Suggestion: "set budget_usd to the run cost in USD that should trigger budget_exceeded",
+		}
+	}
+	if err := validateNotifyTemplate("template", n.Template); err != nil {
+		return err
+	}
+
+	for i, ch := range n.Channels {
+		if err := ch.Validate(); err != nil {
+			return fmt.Errorf("invalid channel %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the channel configuration for errors.
+func (c *NotifyChannel) Validate() error {
+	switch c.Type {
+	case NotifyChannelWebhook:
+		if c.URL == "" {
+			return &errors.ValidationError{
+				Field:      "url",
+				Message:    "url is required for webhook channels",
+				Suggestion: "add the URL that should receive notifications",
+			}
+		}
+	case NotifyChannelSlack, NotifyChannelDiscord:
+		if c.Token == "" || c.Channel == "" {
+			return &errors.ValidationError{
+				Field:      "channel",
+				Message:    fmt.Sprintf("token and channel are required for %s channels", c.Type),
+				Suggestion: "add the bot token and the channel to post to",
+			}
+		}
+	case NotifyChannelEmail:
+		if len(c.To) == 0 {
+			return &errors.ValidationError{
+				Field:      "to",
+				Message:    "to is required for email channels",
+				Suggestion: "list the email addresses to notify",
+			}
+		}
+	default:
+		return &errors.ValidationError{
+			Field:      "type",
+			Message:    fmt.Sprintf("unsupported channel type: %q", c.Type),
+			Suggestion: "use one of: webhook, slack, discord, email",
+		}
+	}
+
+	if err := validateNotifyEvents("on", c.On); err != nil {
+		return err
+	}
+	return validateNotifyTemplate("template", c.Template)
+}
+
+func validateNotifyEvents(field string, events []string) error {
+	for _, event := range events {
+		if !slices.Contains(NotifyEvents, event) {
+			return &errors.ValidationError{
+				Field:      field,
+				Message:    fmt.Sprintf("unknown notify event: %q", event),
+				Suggestion: "use one of: started, succeeded, failed, awaiting_approval, budget_exceeded",
+			}
+		}
+	}
+	return nil
+}
+
+func validateNotifyTemplate(field, text string) error {
+	if text == "" {
+		return nil
+	}
+	if _, err := template.New(field).Parse(text); err != nil {
+		return &errors.ValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("invalid template: %v", err),
+			Suggestion: "use Go text/template syntax, e.g. {{.Workflow}} {{.Status}}",
+		}
+	}
+	return nil
+}
@@ -305,6 +305,7 @@ func checkMissingAuth(def *Definition, result *SecurityValidationResult) {
 		"http":      true,
 		"transform": true,
 		"utility":   true,
+		"email":     true,
 	}
 
 	for integrationName, integration := range def.Integrations {
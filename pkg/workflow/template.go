@@ -23,6 +23,9 @@ type TemplateContext struct {
 
 	// Loop context accessible as {{.loop.iteration}}, {{.loop.max_iterations}}, {{.loop.history}}
 	Loop map[string]interface{}
+
+	// MCP resource contents accessible as {{.resources.name}}
+	Resources map[string]interface{}
 }
 
 // NewTemplateContext creates a new template context with empty maps.
@@ -67,6 +70,7 @@ func (tc *TemplateContext) SetLoopContext(iteration, maxIterations int, history
 // Environment variables are under "env": {{.env.VAR_NAME}}
 // Tool results are under "tools": {{.tools.tool_name}}
 // Loop context is under "loop": {{.loop.iteration}}, {{.loop.max_iterations}}, {{.loop.history}}
+// MCP resources are under "resources": {{.resources.name}}
 func (tc *TemplateContext) ToMap() map[string]interface{} {
 	data := make(map[string]interface{})
 
@@ -92,6 +96,11 @@ func (tc *TemplateContext) ToMap() map[string]interface{} {
 		data["loop"] = tc.Loop
 	}
 
+	// Add MCP resources under "resources" key if present
+	if tc.Resources != nil {
+		data["resources"] = tc.Resources
+	}
+
 	return data
 }
 
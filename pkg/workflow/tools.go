@@ -14,7 +14,10 @@
 
 package workflow
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // AgentDefinition describes an agent with provider preferences and capability requirements.
 type AgentDefinition struct {
@@ -82,12 +85,17 @@ var ValidToolTypes = map[ToolType]bool{
 
 // MCPServerConfig defines configuration for an MCP (Model Context Protocol) server.
 // MCP servers provide tools that can be used in workflow steps via the tool registry.
+// Local servers are started from Command; remote servers are reached at URL.
 type MCPServerConfig struct {
 	// Name is the unique identifier for this MCP server
 	Name string `yaml:"name" json:"name"`
 
+	// Transport selects how to connect: "stdio", "http" (Streamable HTTP) or "sse".
+	// Defaults to "http" when URL is set, otherwise "stdio".
+	Transport string `yaml:"transport,omitempty" json:"transport,omitempty"`
+
 	// Command is the executable to run (e.g., "npx", "python", "/usr/bin/mcp-server")
-	Command string `yaml:"command" json:"command"`
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
 
 	// Args are command-line arguments to pass to the server
 	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
@@ -95,8 +103,51 @@ type MCPServerConfig struct {
 	// Env are environment variables to pass to the server (e.g., ["API_KEY=xyz"])
 	Env []string `yaml:"env,omitempty" json:"env,omitempty"`
 
+	// URL is the endpoint of a remote MCP server (e.g., "https://mcp.example.com/mcp")
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Headers are extra HTTP headers sent to a remote server (values support ${VAR})
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// Auth configures authentication for a remote server
+	Auth *MCPAuthConfig `yaml:"auth,omitempty" json:"auth,omitempty"`
+
 	// Timeout is the default timeout for tool calls in seconds (defaults to 30)
 	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// AllowSampling lets the server request LLM completions through the run's provider
+	AllowSampling bool `yaml:"allow_sampling,omitempty" json:"allow_sampling,omitempty"`
+
+	// SamplingApproval requires each sampling request to be approved
+	SamplingApproval bool `yaml:"sampling_approval,omitempty" json:"sampling_approval,omitempty"`
+}
+
+// MCPAuthConfig defines authentication for a remote MCP server.
+// Secret values should use ${VAR} references rather than literals.
+type MCPAuthConfig struct {
+	// Type is the auth scheme: "bearer" or "oauth2"
+	Type string `yaml:"type" json:"type"`
+
+	// Token is the bearer token (for type "bearer")
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// Flow is the OAuth2 flow: "client_credentials" (default) or "authorization_code"
+	Flow string `yaml:"flow,omitempty" json:"flow,omitempty"`
+
+	// ClientID is the OAuth2 client ID
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// TokenURL is the OAuth2 token endpoint
+	TokenURL string `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+
+	// RefreshToken is the refresh token for the authorization_code flow
+	RefreshToken string `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+
+	// Scopes are the OAuth2 scopes to request
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
 }
 
 // MCPServerRequirement struct represents a required MCP server dependency.
@@ -128,8 +179,34 @@ func (m *MCPServerConfig) Validate() error {
 		return fmt.Errorf("mcp_server name is required")
 	}
 
-	if m.Command == "" {
-		return fmt.Errorf("mcp_server command is required")
+	switch m.Transport {
+	case "", "stdio", "http", "sse":
+	default:
+		return fmt.Errorf("mcp_server transport must be stdio, http, or sse, got %q", m.Transport)
+	}
+
+	if m.IsRemote() {
+		if m.URL == "" {
+			return fmt.Errorf("mcp_server url is required for %s transport", m.Transport)
+		}
+		if !strings.HasPrefix(m.URL, "http://") && !strings.HasPrefix(m.URL, "https://") {
+			return fmt.Errorf("mcp_server url must start with http:// or https://")
+		}
+		if m.Command != "" {
+			return fmt.Errorf("mcp_server cannot set both command and url")
+		}
+		if m.Auth != nil {
+			if err := m.Auth.Validate(); err != nil {
+				return fmt.Errorf("mcp_server auth: %w", err)
+			}
+		}
+	} else {
+		if m.Command == "" {
+			return fmt.Errorf("mcp_server command is required")
+		}
+		if m.URL != "" || len(m.Headers) > 0 || m.Auth != nil {
+			return fmt.Errorf("mcp_server url, headers and auth require http or sse transport")
+		}
 	}
 
 	// Validate timeout if specified
@@ -140,6 +217,40 @@ func (m *MCPServerConfig) Validate() error {
 	return nil
 }
 
+// IsRemote returns true if the server is reached over HTTP rather than spawned locally.
+func (m *MCPServerConfig) IsRemote() bool {
+	if m.Transport != "" {
+		return m.Transport == "http" || m.Transport == "sse"
+	}
+	return m.URL != ""
+}
+
+// Validate checks if the MCP auth config is valid.
+func (a *MCPAuthConfig) Validate() error {
+	switch a.Type {
+	case "bearer":
+		if a.Token == "" {
+			return fmt.Errorf("token is required for bearer auth")
+		}
+	case "oauth2":
+		if a.ClientID == "" || a.ClientSecret == "" || a.TokenURL == "" {
+			return fmt.Errorf("client_id, client_secret and token_url are required for oauth2 auth")
+		}
+		switch a.Flow {
+		case "", "client_credentials":
+		case "authorization_code":
+			if a.RefreshToken == "" {
+				return fmt.Errorf("refresh_token is required for authorization_code flow")
+			}
+		default:
+			return fmt.Errorf("flow must be client_credentials or authorization_code, got %q", a.Flow)
+		}
+	default:
+		return fmt.Errorf("type must be bearer or oauth2, got %q", a.Type)
+	}
+	return nil
+}
+
 // Validate checks if the function definition is valid.
 func (t *FunctionDefinition) Validate() error {
 	if t.Name == "" {
@@ -172,6 +172,20 @@ func DetectEmbeddedCredentials(def *Definition) []string {
 			// Env vars are in "KEY=value" format
 			checkValue(fmt.Sprintf("mcp_servers[%d].env", i), envVar)
 		}
+		for header, value := range server.Headers {
+			checkValue(fmt.Sprintf("mcp_servers[%d].headers.%s", i, header), value)
+		}
+		if server.Auth != nil {
+			if server.Auth.Token != "" {
+				checkValue(fmt.Sprintf("mcp_servers[%d].auth.token", i), server.Auth.Token)
+			}
+			if server.Auth.ClientSecret != "" {
+				checkValue(fmt.Sprintf("mcp_servers[%d].auth.client_secret", i), server.Auth.ClientSecret)
+			}
+			if server.Auth.RefreshToken != "" {
+				checkValue(fmt.Sprintf("mcp_servers[%d].auth.refresh_token", i), server.Auth.RefreshToken)
+			}
+		}
 	}
 
 	return warnings
@@ -294,7 +294,7 @@ func (s *SDK) LoadWorkflowWithWarnings(yamlContent []byte) (*Workflow, []string,
 
 	// Collect warnings for platform-only features
 	var warnings []string
-	if def.Trigger != nil {
+	if len(def.AllTriggers()) > 0 {
 		warnings = append(warnings, "workflow 'listen' configuration ignored - triggers not supported in SDK")
 	}
 